@@ -0,0 +1,17 @@
+package common
+
+import "context"
+
+type requestIDKey struct{}
+
+// WithRequestID keeps the id of the request in the context so the calls made for the request can carry it
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID is the id of the request of the context, it is empty when the context is not of a request
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+
+	return id
+}
@@ -1,8 +1,8 @@
 package common
 
 import (
+	"errors"
 	"fmt"
-	"regexp"
 	"strconv"
 	"strings"
 )
@@ -15,23 +15,55 @@ func IsExisted(e map[string]struct{}, key string) bool {
 	return false
 }
 
-func FormatPath(method string, path string, id int32) string {
-	if id > 0 {
-		path = strings.Replace(path, strconv.Itoa(int(id)), ":id", 1)
+// ParseIDs parses a comma separated list of positive ids, keeping the given order and dropping duplicates
+func ParseIDs(s string, max int) ([]int32, error) {
+	var result []int32
+	seen := map[int32]struct{}{}
+
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		n, err := strconv.ParseInt(part, 10, 32)
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("invalid id %q", part)
+		}
+
+		id := int32(n)
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+
+		result = append(result, id)
 	}
 
-	return fmt.Sprintf("%v %v", method, path)
-}
+	if len(result) == 0 {
+		return nil, errors.New("ids must not be empty")
+	}
 
-func FindIntFromStr(s string) []int32 {
-	re := regexp.MustCompile("[0-9]+")
-	nums := re.FindAllString(s, -1)
+	if len(result) > max {
+		return nil, fmt.Errorf("cannot request more than %d ids at once", max)
+	}
 
-	var result []int32
+	return result, nil
+}
+
+// OrderByIDs returns the indexes of the n items in the order of the ids, the upstream services do not guarantee the
+// order of a batch lookup, the ids that have no item are skipped
+func OrderByIDs(ids []int32, n int, id func(i int) uint32) []int {
+	byID := make(map[uint32]int, n)
+	for i := 0; i < n; i++ {
+		byID[id(i)] = i
+	}
 
-	for _, num := range nums {
-		n, _ := strconv.Atoi(num)
-		result = append(result, int32(n))
+	result := make([]int, 0, len(ids))
+	for _, want := range ids {
+		if i, ok := byID[uint32(want)]; ok {
+			result = append(result, i)
+		}
 	}
 
 	return result
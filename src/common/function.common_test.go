@@ -39,34 +39,47 @@ func (u *UtilTest) TestIsExistedFalse() {
 	assert.False(u.T(), ok)
 }
 
-func (u *UtilTest) TestFormatPathWithID() {
-	want := "POST /user/:id"
+func (u *UtilTest) TestParseIDsSuccess() {
+	want := []int32{3, 1, 2}
 
-	path := FormatPath("POST", "/user/1", 1)
+	ids, err := ParseIDs("3, 1,2,,1", 10)
 
-	assert.Equal(u.T(), want, path)
+	assert.Nil(u.T(), err)
+	assert.Equal(u.T(), want, ids)
 }
 
-func (u *UtilTest) TestFormatPathWithoutID() {
-	want := "POST /user"
+func (u *UtilTest) TestParseIDsInvalid() {
+	ids, err := ParseIDs("1,abc", 10)
 
-	path := FormatPath("POST", "/user", 0)
+	assert.Nil(u.T(), ids)
+	assert.Equal(u.T(), `invalid id "abc"`, err.Error())
+}
+
+func (u *UtilTest) TestParseIDsNegative() {
+	ids, err := ParseIDs("1,-2", 10)
 
-	assert.Equal(u.T(), want, path)
+	assert.Nil(u.T(), ids)
+	assert.NotNil(u.T(), err)
 }
 
-func (u *UtilTest) TestGetIntFromStrFound() {
-	want := []int32{1}
+func (u *UtilTest) TestParseIDsEmpty() {
+	ids, err := ParseIDs(" , ", 10)
+
+	assert.Nil(u.T(), ids)
+	assert.Equal(u.T(), "ids must not be empty", err.Error())
+}
 
-	id := FindIntFromStr("/user/1")
+func (u *UtilTest) TestParseIDsExceedMax() {
+	ids, err := ParseIDs("1,2,3", 2)
 
-	assert.Equal(u.T(), want, id)
+	assert.Nil(u.T(), ids)
+	assert.Equal(u.T(), "cannot request more than 2 ids at once", err.Error())
 }
 
-func (u *UtilTest) TestGetIntFromStrNotFound() {
-	var want []int32
+func (u *UtilTest) TestOrderByIDs() {
+	items := []uint32{3, 1, 2}
 
-	id := FindIntFromStr("/user")
+	order := OrderByIDs([]int32{1, 4, 2, 3}, len(items), func(i int) uint32 { return items[i] })
 
-	assert.Equal(u.T(), want, id)
+	assert.Equal(u.T(), []int{1, 2, 0}, order)
 }
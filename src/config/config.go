@@ -3,6 +3,7 @@ package config
 import (
 	"github.com/pkg/errors"
 	"github.com/spf13/viper"
+	"time"
 )
 
 type Service struct {
@@ -15,9 +16,231 @@ type App struct {
 	Debug bool `mapstructure:"debug"`
 }
 
+// Proxy is the reverse proxy in front of the gateway, the trusted proxies are addresses or CIDR ranges
+type Proxy struct {
+	Header         string   `mapstructure:"header"`
+	TrustedProxies []string `mapstructure:"trusted_proxies"`
+}
+
+type Activity struct {
+	Sink   string `mapstructure:"sink"`
+	Retain int    `mapstructure:"retain"`
+}
+
+type JwtKey struct {
+	Kid       string `mapstructure:"kid"`
+	Alg       string `mapstructure:"alg"`
+	Secret    string `mapstructure:"secret"`
+	PublicKey string `mapstructure:"public_key"`
+}
+
+// Jwt selects how the access tokens are validated, remote calls the auth service on every request,
+// local verifies them in the gateway and hybrid verifies them locally and calls the auth service when the key is unknown
+type Jwt struct {
+	Mode                string        `mapstructure:"mode"`
+	Issuer              string        `mapstructure:"issuer"`
+	Audience            string        `mapstructure:"audience"`
+	ClockSkew           time.Duration `mapstructure:"clock_skew"`
+	UserIDClaim         string        `mapstructure:"user_id_claim"`
+	JwksUrl             string        `mapstructure:"jwks_url"`
+	JwksRefreshInterval time.Duration `mapstructure:"jwks_refresh_interval"`
+	Keys                []JwtKey      `mapstructure:"keys"`
+}
+
+// TokenCache caches the results of the remote token validation, a zero size disables it
+type TokenCache struct {
+	Size int           `mapstructure:"size"`
+	TTL  time.Duration `mapstructure:"ttl"`
+}
+
+// RoleCache caches the roles of a user for the permission guard, the roles changed through another gateway instance are
+// seen after the ttl. The ttl must be positive because the upstream cannot find the roles of a user, so every miss pages
+// through all the roles
+type RoleCache struct {
+	TTL time.Duration `mapstructure:"ttl"`
+}
+
+// Session selects where the browser keeps the tokens, header leaves them to the client and cookie
+// sets them in HttpOnly cookies and protects the unsafe methods with a double submit CSRF token
+type Session struct {
+	Mode          string        `mapstructure:"mode"`
+	AccessCookie  string        `mapstructure:"access_cookie"`
+	RefreshCookie string        `mapstructure:"refresh_cookie"`
+	CsrfCookie    string        `mapstructure:"csrf_cookie"`
+	CsrfHeader    string        `mapstructure:"csrf_header"`
+	Domain        string        `mapstructure:"domain"`
+	Secure        bool          `mapstructure:"secure"`
+	SameSite      string        `mapstructure:"same_site"`
+	RefreshMaxAge time.Duration `mapstructure:"refresh_max_age"`
+}
+
+func (s Session) CookieMode() bool {
+	return s.Mode == "cookie"
+}
+
+// ApiKey is a key of a server to server client, the hash is the hex encoded sha256 of the secret
+// and a signing secret makes the client sign every request
+type ApiKey struct {
+	ID            string   `mapstructure:"id"`
+	Hash          string   `mapstructure:"hash"`
+	Scopes        []string `mapstructure:"scopes"`
+	ExpiresAt     string   `mapstructure:"expires_at"`
+	SigningSecret string   `mapstructure:"signing_secret"`
+	UserID        int32    `mapstructure:"user_id"`
+}
+
+type ApiKeys struct {
+	SignatureWindow time.Duration `mapstructure:"signature_window"`
+	Keys            []ApiKey      `mapstructure:"keys"`
+}
+
+// LoginThrottle locks out the emails and the client IPs that fail to log in too often inside the window,
+// every lockout in a row doubles the lock duration from the base lockout up to the max lockout
+type LoginThrottle struct {
+	Window                  time.Duration `mapstructure:"window"`
+	MaxFailuresPerEmail     int           `mapstructure:"max_failures_per_email"`
+	MaxFailuresPerIP        int           `mapstructure:"max_failures_per_ip"`
+	MaxRefreshFailuresPerIP int           `mapstructure:"max_refresh_failures_per_ip"`
+	MaxTwoFactorFailures    int           `mapstructure:"max_two_factor_failures"`
+	BaseLockout             time.Duration `mapstructure:"base_lockout"`
+	MaxLockout              time.Duration `mapstructure:"max_lockout"`
+}
+
+// OAuthProvider is an OpenID Connect provider, the endpoints are discovered from the issuer when they are not given
+type OAuthProvider struct {
+	Name             string   `mapstructure:"name"`
+	Issuer           string   `mapstructure:"issuer"`
+	ClientID         string   `mapstructure:"client_id"`
+	ClientSecret     string   `mapstructure:"client_secret"`
+	RedirectUrl      string   `mapstructure:"redirect_url"`
+	Scopes           []string `mapstructure:"scopes"`
+	AuthorizationUrl string   `mapstructure:"authorization_url"`
+	TokenUrl         string   `mapstructure:"token_url"`
+	JwksUrl          string   `mapstructure:"jwks_url"`
+}
+
+// OAuth is the social login, the auth service only logs in with a password so the account secret
+// derives the password of the accounts that are created by a provider
+type OAuth struct {
+	AccountSecret string          `mapstructure:"account_secret"`
+	StateTTL      time.Duration   `mapstructure:"state_ttl"`
+	Providers     []OAuthProvider `mapstructure:"providers"`
+}
+
+type Smtp struct {
+	Host     string `mapstructure:"host"`
+	Port     int    `mapstructure:"port"`
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+}
+
+// Mail selects how the mails are delivered, smtp sends them, log prints them and file appends them to the file
+type Mail struct {
+	Driver string `mapstructure:"driver"`
+	From   string `mapstructure:"from"`
+	File   string `mapstructure:"file"`
+	Smtp   Smtp   `mapstructure:"smtp"`
+}
+
+// EmailVerification mails the verification link on the registration, the url is the page of the client that takes the token in its %s,
+// the auth guard rejects the users that did not verify from the routes outside /auth when it is required, which needs
+// the redis store
+type EmailVerification struct {
+	Enabled      bool          `mapstructure:"enabled"`
+	Required     bool          `mapstructure:"required"`
+	Secret       string        `mapstructure:"secret"`
+	TTL          time.Duration `mapstructure:"ttl"`
+	Url          string        `mapstructure:"url"`
+	MaxResends   int           `mapstructure:"max_resends"`
+	ResendWindow time.Duration `mapstructure:"resend_window"`
+}
+
+// TwoFactor encrypts the secrets of the authenticators with the encryption key, a base64 encoded 32 bytes AES key,
+// the two-factor authentication is disabled when the key is empty and needs the redis store when it is not
+type TwoFactor struct {
+	EncryptionKey string        `mapstructure:"encryption_key"`
+	Issuer        string        `mapstructure:"issuer"`
+	ChallengeTTL  time.Duration `mapstructure:"challenge_ttl"`
+	RecoveryCodes int           `mapstructure:"recovery_codes"`
+}
+
+// Sessions keeps the sessions of the issued credentials, a session that is not seen for the retention is dropped
+type Sessions struct {
+	Retention time.Duration `mapstructure:"retention"`
+}
+
+// PasswordPolicy is checked on every new password, the breached list is a file with a password on each line that is
+// loaded into a bloom filter, the false positive rate trades its size for the passwords that are rejected by mistake
+type PasswordPolicy struct {
+	MinLength             int     `mapstructure:"min_length"`
+	MaxLength             int     `mapstructure:"max_length"`
+	RequireUpper          bool    `mapstructure:"require_upper"`
+	RequireLower          bool    `mapstructure:"require_lower"`
+	RequireDigit          bool    `mapstructure:"require_digit"`
+	RequireSymbol         bool    `mapstructure:"require_symbol"`
+	BreachedList          string  `mapstructure:"breached_list"`
+	BreachedFalsePositive float64 `mapstructure:"breached_false_positive"`
+}
+
+type RateLimitPolicy struct {
+	Limit  int           `mapstructure:"limit"`
+	Period time.Duration `mapstructure:"period"`
+}
+
+type Redis struct {
+	Addr     string        `mapstructure:"addr"`
+	Password string        `mapstructure:"password"`
+	DB       int           `mapstructure:"db"`
+	PoolSize int           `mapstructure:"pool_size"`
+	Timeout  time.Duration `mapstructure:"timeout"`
+	Prefix   string        `mapstructure:"prefix"`
+}
+
+// RateLimit keeps the token buckets in the memory of the gateway or in a server that speaks the Redis protocol,
+// each policy lets a caller burst its limit and refills the limit over the period, a policy without a limit does not limit
+type RateLimit struct {
+	Backend  string                     `mapstructure:"backend"`
+	Redis    Redis                      `mapstructure:"redis"`
+	Policies map[string]RateLimitPolicy `mapstructure:"policies"`
+}
+
+// Store keeps the verifications, the two-factor enrollments, the sessions and the emails of the users in the memory of
+// the gateway or in a Redis server, the memory is lost on a restart and is not shared by the gateway instances
+type Store struct {
+	Backend string `mapstructure:"backend"`
+	Redis   Redis  `mapstructure:"redis"`
+}
+
+// Policy overrides the access level that a route declares in the code, the path is the route pattern (e.g. /user/:id)
+// and the owner is the resource of the :id param when the access is owner
+type Policy struct {
+	Method     string `mapstructure:"method"`
+	Path       string `mapstructure:"path"`
+	Access     string `mapstructure:"access"`
+	Permission string `mapstructure:"permission"`
+	Owner      string `mapstructure:"owner"`
+}
+
 type Config struct {
-	Service Service `mapstructure:"service"`
-	App     App     `mapstructure:"app"`
+	Service           Service           `mapstructure:"service"`
+	App               App               `mapstructure:"app"`
+	Proxy             Proxy             `mapstructure:"proxy"`
+	Activity          Activity          `mapstructure:"activity"`
+	Policies          []Policy          `mapstructure:"policies"`
+	Jwt               Jwt               `mapstructure:"jwt"`
+	TokenCache        TokenCache        `mapstructure:"token_cache"`
+	RoleCache         RoleCache         `mapstructure:"role_cache"`
+	Session           Session           `mapstructure:"session"`
+	ApiKeys           ApiKeys           `mapstructure:"api_keys"`
+	LoginThrottle     LoginThrottle     `mapstructure:"login_throttle"`
+	OAuth             OAuth             `mapstructure:"oauth"`
+	Mail              Mail              `mapstructure:"mail"`
+	EmailVerification EmailVerification `mapstructure:"email_verification"`
+	TwoFactor         TwoFactor         `mapstructure:"two_factor"`
+	Sessions          Sessions          `mapstructure:"sessions"`
+	PasswordPolicy    PasswordPolicy    `mapstructure:"password_policy"`
+	RateLimit         RateLimit         `mapstructure:"rate_limit"`
+	Store             Store             `mapstructure:"store"`
 }
 
 func LoadConfig() (config *Config, err error) {
@@ -25,6 +248,70 @@ func LoadConfig() (config *Config, err error) {
 	viper.SetConfigName("config")
 	viper.SetConfigType("yaml")
 
+	viper.SetDefault("proxy.header", "X-Forwarded-For")
+	viper.SetDefault("activity.retain", 100)
+	viper.SetDefault("session.mode", "header")
+	viper.SetDefault("session.access_cookie", "access_token")
+	viper.SetDefault("session.refresh_cookie", "refresh_token")
+	viper.SetDefault("session.csrf_cookie", "csrf_token")
+	viper.SetDefault("session.csrf_header", "X-CSRF-Token")
+	viper.SetDefault("session.secure", true)
+	viper.SetDefault("session.same_site", "Strict")
+	viper.SetDefault("session.refresh_max_age", "720h")
+
+	viper.SetDefault("role_cache.ttl", "30s")
+
+	viper.SetDefault("api_keys.signature_window", "5m")
+
+	viper.SetDefault("login_throttle.window", "15m")
+	viper.SetDefault("login_throttle.max_failures_per_email", 5)
+	viper.SetDefault("login_throttle.max_failures_per_ip", 20)
+	viper.SetDefault("login_throttle.max_refresh_failures_per_ip", 20)
+	viper.SetDefault("login_throttle.max_two_factor_failures", 5)
+	viper.SetDefault("login_throttle.base_lockout", "1m")
+	viper.SetDefault("login_throttle.max_lockout", "1h")
+
+	viper.SetDefault("oauth.state_ttl", "10m")
+
+	viper.SetDefault("mail.driver", "log")
+	viper.SetDefault("mail.smtp.port", 587)
+
+	viper.SetDefault("email_verification.ttl", "24h")
+	viper.SetDefault("email_verification.max_resends", 3)
+	viper.SetDefault("email_verification.resend_window", "1h")
+
+	viper.SetDefault("two_factor.issuer", "Samithiwat.dev")
+	viper.SetDefault("two_factor.challenge_ttl", "5m")
+	viper.SetDefault("two_factor.recovery_codes", 10)
+
+	viper.SetDefault("sessions.retention", "720h")
+
+	viper.SetDefault("password_policy.min_length", 8)
+	viper.SetDefault("password_policy.max_length", 72)
+	viper.SetDefault("password_policy.breached_false_positive", 0.001)
+
+	viper.SetDefault("rate_limit.backend", "memory")
+	viper.SetDefault("rate_limit.redis.addr", "localhost:6379")
+	viper.SetDefault("rate_limit.redis.pool_size", 10)
+	viper.SetDefault("rate_limit.redis.timeout", "1s")
+	viper.SetDefault("rate_limit.redis.prefix", "ratelimit:")
+	viper.SetDefault("rate_limit.policies.client.limit", 1200)
+	viper.SetDefault("rate_limit.policies.client.period", "1m")
+	viper.SetDefault("rate_limit.policies.login.limit", 10)
+	viper.SetDefault("rate_limit.policies.login.period", "1m")
+	viper.SetDefault("rate_limit.policies.auth.limit", 60)
+	viper.SetDefault("rate_limit.policies.auth.period", "1m")
+	viper.SetDefault("rate_limit.policies.read.limit", 600)
+	viper.SetDefault("rate_limit.policies.read.period", "1m")
+	viper.SetDefault("rate_limit.policies.write.limit", 120)
+	viper.SetDefault("rate_limit.policies.write.period", "1m")
+
+	viper.SetDefault("store.backend", "memory")
+	viper.SetDefault("store.redis.addr", "localhost:6379")
+	viper.SetDefault("store.redis.pool_size", 10)
+	viper.SetDefault("store.redis.timeout", "1s")
+	viper.SetDefault("store.redis.prefix", "gateway:")
+
 	viper.AutomaticEnv()
 
 	err = viper.ReadInConfig()
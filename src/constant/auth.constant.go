@@ -0,0 +1,29 @@
+package constant
+
+// The scopes of the failed attempt tracking, each scope has its own lockout policy
+const (
+	LockoutLoginEmail = "login:email"
+	LockoutLoginIP    = "login:ip"
+	LockoutRefreshIP  = "refresh:ip"
+
+	LockoutTwoFactorUser = "2fa:user"
+
+	LockoutResendVerification = "resend-verification:user"
+)
+
+// The resources that a route can require the caller to own
+const (
+	ResourceUser         = "user"
+	ResourceTeam         = "team"
+	ResourceOrganization = "organization"
+)
+
+// The rate limit policies of the routes, a policy that is not configured does not limit its routes. The client policy
+// limits every request by its address before the auth guard
+const (
+	RateLimitClient = "client"
+	RateLimitLogin  = "login"
+	RateLimitAuth   = "auth"
+	RateLimitRead   = "read"
+	RateLimitWrite  = "write"
+)
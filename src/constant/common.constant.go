@@ -1,10 +1,23 @@
 package constant
 
-var AuthExcludePath = map[string]struct{}{
-	"POST /auth/register":   {},
-	"POST /auth/login":      {},
-	"GET /user/:id":         {},
-	"GET /organization":     {},
-	"GET /organization/:id": {},
-	"GET /team/:id":         {},
-}
+// FetchAllLimit is the page size used when the gateway walks through every page of an upstream resource
+const FetchAllLimit = 100
+
+// DefaultPageLimit is the page size used by the gateway side pagination when the limit is not given
+const DefaultPageLimit = 10
+
+// MaxBatchSize is the maximum number of ids accepted by a single batch lookup
+const MaxBatchSize = 50
+
+// DefaultTeamTreeDepth is the number of sub-team levels returned by the team tree when the depth is not given
+const DefaultTeamTreeDepth = 3
+
+// MaxTeamTreeDepth is the deepest team tree the gateway is willing to build in one request
+const MaxTeamTreeDepth = 10
+
+// RequestIDHeader is the header of the request id that the gateway accepts and returns, the id is sent to the
+// upstream services in the RequestIDMetadata of the gRPC calls
+const (
+	RequestIDHeader   = "X-Request-ID"
+	RequestIDMetadata = "x-request-id"
+)
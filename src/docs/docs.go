@@ -20,14 +20,14 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/auth/change-password": {
+        "/auth/2fa/confirm": {
             "post": {
                 "security": [
                     {
                         "AuthToken": []
                     }
                 ],
-                "description": "Return the true if successfully",
+                "description": "Return the recovery codes if successfully, they are shown only once",
                 "consumes": [
                     "application/json"
                 ],
@@ -37,36 +37,39 @@ const docTemplate = `{
                 "tags": [
                     "auth"
                 ],
-                "summary": "ChangePassword of user account",
+                "summary": "Confirm the two-factor authentication",
                 "parameters": [
                     {
-                        "description": "change password dto",
-                        "name": "register",
+                        "description": "totp code dto",
+                        "name": "code",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.ChangePassword"
+                            "$ref": "#/definitions/dto.TotpCode"
                         }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": ""
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.RecoveryCodes"
+                        }
                     },
                     "400": {
-                        "description": "Invalid request body",
+                        "description": "Invalid code",
                         "schema": {
                             "$ref": "#/definitions/dto.ResponseErr"
                         }
                     },
                     "401": {
-                        "description": "Invalid access token",
+                        "description": "Invalid token",
                         "schema": {
                             "$ref": "#/definitions/dto.ResponseErr"
                         }
                     },
-                    "403": {
-                        "description": "Insufficiency permission",
+                    "409": {
+                        "description": "Two-factor authentication is already enabled",
                         "schema": {
                             "$ref": "#/definitions/dto.ResponseErr"
                         }
@@ -80,9 +83,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/login": {
+        "/auth/2fa/disable": {
             "post": {
-                "description": "Return the credentials if successfully",
+                "security": [
+                    {
+                        "AuthToken": []
+                    }
+                ],
+                "description": "Remove the authenticator when the code is a code of the authenticator or a recovery code, the logins are not challenged anymore",
                 "consumes": [
                     "application/json"
                 ],
@@ -92,33 +100,36 @@ const docTemplate = `{
                 "tags": [
                     "auth"
                 ],
-                "summary": "Login user account",
+                "summary": "Disable the two-factor authentication",
                 "parameters": [
                     {
-                        "description": "login dto",
-                        "name": "register",
+                        "description": "totp code dto",
+                        "name": "code",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.Login"
+                            "$ref": "#/definitions/dto.TotpCode"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
-                        "schema": {
-                            "$ref": "#/definitions/proto.Credential"
-                        }
+                    "204": {
+                        "description": ""
                     },
                     "400": {
-                        "description": "Invalid request body",
+                        "description": "Invalid code",
                         "schema": {
                             "$ref": "#/definitions/dto.ResponseErr"
                         }
                     },
                     "401": {
-                        "description": "Invalid email or username",
+                        "description": "Invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many attempts",
                         "schema": {
                             "$ref": "#/definitions/dto.ResponseErr"
                         }
@@ -132,14 +143,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/logout": {
-            "get": {
+        "/auth/2fa/setup": {
+            "post": {
                 "security": [
                     {
                         "AuthToken": []
                     }
                 ],
-                "description": "Return the user dto if successfully",
+                "description": "Return the secret, the otpauth uri and its qr code as a base64 encoded PNG, the authenticator is used after it is confirmed",
                 "consumes": [
                     "application/json"
                 ],
@@ -149,10 +160,13 @@ const docTemplate = `{
                 "tags": [
                     "auth"
                 ],
-                "summary": "Logout user from service",
+                "summary": "Set up the two-factor authentication",
                 "responses": {
-                    "204": {
-                        "description": ""
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.TotpSetup"
+                        }
                     },
                     "401": {
                         "description": "Invalid token",
@@ -160,6 +174,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/dto.ResponseErr"
                         }
                     },
+                    "404": {
+                        "description": "Two-factor authentication is not enabled",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "409": {
+                        "description": "Two-factor authentication is already enabled",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
                     "503": {
                         "description": "Service is down",
                         "schema": {
@@ -169,14 +195,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/me": {
-            "get": {
-                "security": [
-                    {
-                        "AuthToken": []
-                    }
-                ],
-                "description": "Return the user dto if successfully",
+        "/auth/2fa/verify": {
+            "post": {
+                "description": "Return the credentials if successfully, the code is a code of the authenticator or a recovery code",
                 "consumes": [
                     "application/json"
                 ],
@@ -186,16 +207,45 @@ const docTemplate = `{
                 "tags": [
                     "auth"
                 ],
-                "summary": "Check user status and user info",
+                "summary": "Verify the two-factor authentication of the login",
+                "parameters": [
+                    {
+                        "description": "two-factor verify dto",
+                        "name": "verify",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.TwoFactorVerify"
+                        }
+                    }
+                ],
                 "responses": {
+                    "200": {
+                        "description": "When the session is kept in cookies",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Session"
+                        }
+                    },
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/proto.User"
+                            "$ref": "#/definitions/proto.Credential"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
                         }
                     },
                     "401": {
-                        "description": "Invalid token",
+                        "description": "Invalid code",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many attempts",
                         "schema": {
                             "$ref": "#/definitions/dto.ResponseErr"
                         }
@@ -209,9 +259,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/register": {
+        "/auth/change-password": {
             "post": {
-                "description": "Return the user dto if successfully",
+                "security": [
+                    {
+                        "AuthToken": []
+                    }
+                ],
+                "description": "Return the true if successfully",
                 "consumes": [
                     "application/json"
                 ],
@@ -221,24 +276,21 @@ const docTemplate = `{
                 "tags": [
                     "auth"
                 ],
-                "summary": "Register user account",
+                "summary": "ChangePassword of user account",
                 "parameters": [
                     {
-                        "description": "register dto",
+                        "description": "change password dto",
                         "name": "register",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.Register"
+                            "$ref": "#/definitions/dto.ChangePassword"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
-                        "schema": {
-                            "$ref": "#/definitions/proto.User"
-                        }
+                    "204": {
+                        "description": ""
                     },
                     "400": {
                         "description": "Invalid request body",
@@ -246,8 +298,14 @@ const docTemplate = `{
                             "$ref": "#/definitions/dto.ResponseErr"
                         }
                     },
-                    "422": {
-                        "description": "Email is already existed",
+                    "401": {
+                        "description": "Invalid access token",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "403": {
+                        "description": "Insufficiency permission",
                         "schema": {
                             "$ref": "#/definitions/dto.ResponseErr"
                         }
@@ -261,7 +319,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/token": {
+        "/auth/login": {
             "post": {
                 "description": "Return the credentials if successfully",
                 "consumes": [
@@ -273,25 +331,37 @@ const docTemplate = `{
                 "tags": [
                     "auth"
                 ],
-                "summary": "Redeem new token",
+                "summary": "Login user account",
                 "parameters": [
                     {
-                        "description": "refresh token dto",
+                        "description": "login dto",
                         "name": "register",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.RedeemNewToken"
+                            "$ref": "#/definitions/dto.Login"
                         }
                     }
                 ],
                 "responses": {
+                    "200": {
+                        "description": "When the session is kept in cookies",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Session"
+                        }
+                    },
                     "201": {
                         "description": "Created",
                         "schema": {
                             "$ref": "#/definitions/proto.Credential"
                         }
                     },
+                    "202": {
+                        "description": "When the user has enabled the two-factor authentication, the challenge is verified at /auth/2fa/verify",
+                        "schema": {
+                            "$ref": "#/definitions/dto.TwoFactorChallenge"
+                        }
+                    },
                     "400": {
                         "description": "Invalid request body",
                         "schema": {
@@ -299,7 +369,13 @@ const docTemplate = `{
                         }
                     },
                     "401": {
-                        "description": "Invalid refresh token",
+                        "description": "Invalid email or username",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many attempts",
                         "schema": {
                             "$ref": "#/definitions/dto.ResponseErr"
                         }
@@ -313,9 +389,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/organization": {
+        "/auth/logout": {
             "get": {
-                "description": "Return the arrays of organization dto if successfully",
+                "security": [
+                    {
+                        "AuthToken": []
+                    }
+                ],
+                "description": "Return the user dto if successfully",
                 "consumes": [
                     "application/json"
                 ],
@@ -323,32 +404,15 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "organization"
-                ],
-                "summary": "Get all organizations",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Limit",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Page",
-                        "name": "page",
-                        "in": "query"
-                    }
+                    "auth"
                 ],
+                "summary": "Logout user from service",
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/proto.Organization"
-                        }
+                    "204": {
+                        "description": ""
                     },
-                    "400": {
-                        "description": "Invalid query param",
+                    "401": {
+                        "description": "Invalid token",
                         "schema": {
                             "$ref": "#/definitions/dto.ResponseErr"
                         }
@@ -360,14 +424,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/auth/logout-all": {
             "post": {
                 "security": [
                     {
                         "AuthToken": []
                     }
                 ],
-                "description": "Return the organization dto if successfully",
+                "description": "Revoke every session of the user, the tokens of the sessions are rejected",
                 "consumes": [
                     "application/json"
                 ],
@@ -375,35 +441,15 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "organization"
-                ],
-                "summary": "Create the organization",
-                "parameters": [
-                    {
-                        "description": "organization dto",
-                        "name": "organization",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.OrganizationDto"
-                        }
-                    }
+                    "auth"
                 ],
+                "summary": "Logout user from every session",
                 "responses": {
-                    "201": {
-                        "description": "Created",
-                        "schema": {
-                            "$ref": "#/definitions/proto.Organization"
-                        }
-                    },
-                    "400": {
-                        "description": "Invalid ID",
-                        "schema": {
-                            "$ref": "#/definitions/dto.ResponseErr"
-                        }
+                    "204": {
+                        "description": ""
                     },
-                    "404": {
-                        "description": "Not found organization",
+                    "401": {
+                        "description": "Invalid token",
                         "schema": {
                             "$ref": "#/definitions/dto.ResponseErr"
                         }
@@ -417,9 +463,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/organization/{id}": {
+        "/auth/me": {
             "get": {
-                "description": "Return the organization dto if successfully",
+                "security": [
+                    {
+                        "AuthToken": []
+                    }
+                ],
+                "description": "Return the user dto if successfully",
                 "consumes": [
                     "application/json"
                 ],
@@ -427,32 +478,1439 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "organization"
-                ],
-                "summary": "Get specific organization with id",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "id",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "auth"
                 ],
+                "summary": "Check user status and user info",
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/proto.Organization"
+                            "$ref": "#/definitions/proto.User"
                         }
                     },
-                    "400": {
-                        "description": "Invalid ID",
+                    "401": {
+                        "description": "Invalid token",
                         "schema": {
                             "$ref": "#/definitions/dto.ResponseErr"
                         }
                     },
-                    "404": {
+                    "503": {
+                        "description": "Service is down",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/oauth/{provider}/callback": {
+            "get": {
+                "description": "Return the credentials if successfully, the account is created on the first login",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Finish the login with an OpenID Connect provider",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "provider name",
+                        "name": "provider",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "authorization code",
+                        "name": "code",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "state of the login",
+                        "name": "state",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "When the session is kept in cookies",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Session"
+                        }
+                    },
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/proto.Credential"
+                        }
+                    },
+                    "202": {
+                        "description": "When the user has enabled the two-factor authentication, the challenge is verified at /auth/2fa/verify",
+                        "schema": {
+                            "$ref": "#/definitions/dto.TwoFactorChallenge"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid callback",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid ID token",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "404": {
+                        "description": "Unknown provider",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "409": {
+                        "description": "Email is already registered with a password",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "503": {
+                        "description": "Service is down",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/oauth/{provider}/login": {
+            "get": {
+                "description": "Redirect to the provider, the provider redirects back to the callback",
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Login with an OpenID Connect provider",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "provider name",
+                        "name": "provider",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "302": {
+                        "description": ""
+                    },
+                    "404": {
+                        "description": "Unknown provider",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "503": {
+                        "description": "Cannot reach the provider",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/register": {
+            "post": {
+                "description": "Return the user dto if successfully, the verification link is mailed to the email when the email verification is enabled",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Register user account",
+                "parameters": [
+                    {
+                        "description": "register dto",
+                        "name": "register",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.Register"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/proto.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "422": {
+                        "description": "Email is already existed",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "503": {
+                        "description": "Service is down",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/resend-verification": {
+            "post": {
+                "security": [
+                    {
+                        "AuthToken": []
+                    }
+                ],
+                "description": "Mail a new verification link to the email that the caller registered with, the resends are limited in a window",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Resend the verification link",
+                "responses": {
+                    "204": {
+                        "description": ""
+                    },
+                    "401": {
+                        "description": "Invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "404": {
+                        "description": "Email verification is not enabled",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "409": {
+                        "description": "Email is already verified",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many attempts",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "503": {
+                        "description": "Service is down",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/sessions": {
+            "get": {
+                "security": [
+                    {
+                        "AuthToken": []
+                    }
+                ],
+                "description": "Return the active sessions from the last seen, the session of the request is the current one",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Get the sessions of user account",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.DeviceSession"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "503": {
+                        "description": "Service is down",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/sessions/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "AuthToken": []
+                    }
+                ],
+                "description": "The tokens of the session are rejected after it is revoked",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Revoke a session of user account",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "session id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": ""
+                    },
+                    "401": {
+                        "description": "Invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "404": {
+                        "description": "Session not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "503": {
+                        "description": "Service is down",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/token": {
+            "post": {
+                "description": "Return the credentials if successfully, the refresh token is the credential of the route so an expired access token is not needed. In the cookie mode the refresh token of the cookie needs the csrf header",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Redeem new token",
+                "parameters": [
+                    {
+                        "description": "refresh token dto",
+                        "name": "register",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.RedeemNewToken"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "When the session is kept in cookies",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Session"
+                        }
+                    },
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/proto.Credential"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid refresh token",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "403": {
+                        "description": "Invalid CSRF token",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many attempts",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "503": {
+                        "description": "Service is down",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/token-cache": {
+            "get": {
+                "security": [
+                    {
+                        "AuthToken": []
+                    }
+                ],
+                "description": "Return the hit, miss and size of the cache",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Get the token validation cache counters",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.TokenCacheStats"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "403": {
+                        "description": "Insufficient permission",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/verify-email": {
+            "get": {
+                "description": "Confirm the email with the token of the verification link",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Verify the email of user account",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "verification token",
+                        "name": "token",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": ""
+                    },
+                    "400": {
+                        "description": "Invalid or expired verification token",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "404": {
+                        "description": "Email verification is not enabled",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "503": {
+                        "description": "Service is down",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    }
+                }
+            }
+        },
+        "/organization": {
+            "get": {
+                "description": "Return the arrays of organization dto if successfully",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organization"
+                ],
+                "summary": "Get all organizations",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated ids, return only these organizations in the given order",
+                        "name": "ids",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/proto.Organization"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid query param",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "503": {
+                        "description": "Service is down",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "AuthToken": []
+                    }
+                ],
+                "description": "Return the organization dto if successfully",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organization"
+                ],
+                "summary": "Create the organization",
+                "parameters": [
+                    {
+                        "description": "organization dto",
+                        "name": "organization",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.OrganizationDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/proto.Organization"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found organization",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "503": {
+                        "description": "Service is down",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    }
+                }
+            }
+        },
+        "/organization/{id}": {
+            "get": {
+                "description": "Return the organization dto if successfully",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organization"
+                ],
+                "summary": "Get specific organization with id",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/proto.Organization"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found organization",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "503": {
+                        "description": "Service is down",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "AuthToken": []
+                    }
+                ],
+                "description": "Return the organization dto if successfully",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organization"
+                ],
+                "summary": "Delete the organization",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/proto.Organization"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found organization",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "503": {
+                        "description": "Service is down",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "AuthToken": []
+                    }
+                ],
+                "description": "Return the organization dto if successfully",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organization"
+                ],
+                "summary": "Update the existing organization",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "organization dto",
+                        "name": "organization",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.OrganizationDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/proto.Organization"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found organization",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "503": {
+                        "description": "Service is down",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    }
+                }
+            }
+        },
+        "/organization/{id}/activity": {
+            "get": {
+                "security": [
+                    {
+                        "AuthToken": []
+                    }
+                ],
+                "description": "Return the page of log dto if successfully, the newest first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organization"
+                ],
+                "summary": "Get the activity logs of the organization",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "POST",
+                            "EDIT",
+                            "LOGIN",
+                            "CHANGE_PASSWORD",
+                            "BOOKMARK"
+                        ],
+                        "type": "string",
+                        "description": "Log type",
+                        "name": "type",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.LogPagination"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID or log type",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found organization",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "503": {
+                        "description": "Service is down",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    }
+                }
+            }
+        },
+        "/organization/{id}/contact": {
+            "get": {
+                "description": "Return the contact dto if successfully",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organization"
+                ],
+                "summary": "Get the contact of the organization",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/proto.Contact"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found organization or contact",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "503": {
+                        "description": "Service is down",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "AuthToken": []
+                    }
+                ],
+                "description": "Return the contact dto if successfully",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organization"
+                ],
+                "summary": "Create or update the contact of the organization",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "contact dto",
+                        "name": "contact",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ContactDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/proto.Contact"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found organization",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "503": {
+                        "description": "Service is down",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    }
+                }
+            }
+        },
+        "/organization/{id}/location": {
+            "get": {
+                "description": "Return the location dto if successfully",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organization"
+                ],
+                "summary": "Get the location of the organization",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/proto.Location"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found organization or location",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "503": {
+                        "description": "Service is down",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "AuthToken": []
+                    }
+                ],
+                "description": "Return the location dto if successfully",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organization"
+                ],
+                "summary": "Create or update the location of the organization",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "location dto",
+                        "name": "location",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.LocationDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/proto.Location"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found organization",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "503": {
+                        "description": "Service is down",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    }
+                }
+            }
+        },
+        "/organization/{id}/member": {
+            "get": {
+                "description": "Return the pagination of user dto if successfully",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organization"
+                ],
+                "summary": "Get the members of the organization",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page",
+                        "name": "page",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/proto.UserPagination"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found organization",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "503": {
+                        "description": "Service is down",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "AuthToken": []
+                    }
+                ],
+                "description": "Return the organization dto if successfully",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organization"
+                ],
+                "summary": "Add the member to the organization",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "member dto",
+                        "name": "member",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.MemberDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/proto.Organization"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found organization or user",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "503": {
+                        "description": "Service is down",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    }
+                }
+            }
+        },
+        "/organization/{id}/member/{userId}": {
+            "delete": {
+                "security": [
+                    {
+                        "AuthToken": []
+                    }
+                ],
+                "description": "Return the organization dto if successfully",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organization"
+                ],
+                "summary": "Remove the member from the organization",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "user id",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/proto.Organization"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found organization or member",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "503": {
+                        "description": "Service is down",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    }
+                }
+            }
+        },
+        "/organization/{id}/member/{userId}/role": {
+            "put": {
+                "security": [
+                    {
+                        "AuthToken": []
+                    }
+                ],
+                "description": "Return the role dto if successfully, the member loses the other roles of the organization",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organization"
+                ],
+                "summary": "Assign the organization role to the member",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "user id",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "member role dto",
+                        "name": "role",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.MemberRoleDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/proto.Role"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found organization, member or role",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "503": {
+                        "description": "Service is down",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    }
+                }
+            }
+        },
+        "/organization/{id}/teams": {
+            "get": {
+                "description": "Return the arrays of team dto if successfully",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "organization"
+                ],
+                "summary": "Get the teams of the organization",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/proto.Team"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "404": {
                         "description": "Not found organization",
                         "schema": {
                             "$ref": "#/definitions/dto.ResponseErr"
@@ -465,14 +1923,1282 @@ const docTemplate = `{
                         }
                     }
                 }
+            }
+        },
+        "/permission": {
+            "get": {
+                "security": [
+                    {
+                        "AuthToken": []
+                    }
+                ],
+                "description": "Return the arrays of permission dto if successfully",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "permission"
+                ],
+                "summary": "Get all permissions",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page",
+                        "name": "page",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/proto.Permission"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid query param",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "503": {
+                        "description": "Service is down",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "AuthToken": []
+                    }
+                ],
+                "description": "Return the permission dto if successfully",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "permission"
+                ],
+                "summary": "Create the permission",
+                "parameters": [
+                    {
+                        "description": "permission dto",
+                        "name": "permission",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.PermissionDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/proto.Permission"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found permission",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "422": {
+                        "description": "Duplicated permission code",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "503": {
+                        "description": "Service is down",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    }
+                }
+            }
+        },
+        "/permission/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "AuthToken": []
+                    }
+                ],
+                "description": "Return the permission dto if successfully",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "permission"
+                ],
+                "summary": "Get specific permission with id",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/proto.Permission"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found permission",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "503": {
+                        "description": "Service is down",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "AuthToken": []
+                    }
+                ],
+                "description": "Return the permission dto if successfully",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "permission"
+                ],
+                "summary": "Delete the permission",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/proto.Permission"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found permission",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "503": {
+                        "description": "Service is down",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "AuthToken": []
+                    }
+                ],
+                "description": "Return the permission dto if successfully",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "permission"
+                ],
+                "summary": "Update the existing permission",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "permission dto",
+                        "name": "permission",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.PermissionDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/proto.Permission"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found permission",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "422": {
+                        "description": "Duplicated permission code",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "503": {
+                        "description": "Service is down",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    }
+                }
+            }
+        },
+        "/role": {
+            "get": {
+                "security": [
+                    {
+                        "AuthToken": []
+                    }
+                ],
+                "description": "Return the arrays of role dto if successfully",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "role"
+                ],
+                "summary": "Get all roles",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page",
+                        "name": "page",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/proto.Role"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid query param",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "503": {
+                        "description": "Service is down",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "AuthToken": []
+                    }
+                ],
+                "description": "Return the role dto if successfully",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "role"
+                ],
+                "summary": "Create the role",
+                "parameters": [
+                    {
+                        "description": "role dto",
+                        "name": "role",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.RoleDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/proto.Role"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found role",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "503": {
+                        "description": "Service is down",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    }
+                }
+            }
+        },
+        "/role/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "AuthToken": []
+                    }
+                ],
+                "description": "Return the role dto if successfully",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "role"
+                ],
+                "summary": "Get specific role with id",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/proto.Role"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found role",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "503": {
+                        "description": "Service is down",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "AuthToken": []
+                    }
+                ],
+                "description": "Return the role dto if successfully",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "role"
+                ],
+                "summary": "Delete the role",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/proto.Role"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found role",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "503": {
+                        "description": "Service is down",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "AuthToken": []
+                    }
+                ],
+                "description": "Return the role dto if successfully",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "role"
+                ],
+                "summary": "Update the existing role",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "role dto",
+                        "name": "role",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.RoleDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/proto.Role"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found role",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "503": {
+                        "description": "Service is down",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    }
+                }
+            }
+        },
+        "/role/{id}/permission/{permissionId}": {
+            "post": {
+                "security": [
+                    {
+                        "AuthToken": []
+                    }
+                ],
+                "description": "Return the role dto if successfully",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "role"
+                ],
+                "summary": "Add the permission to the role",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "permission id",
+                        "name": "permissionId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/proto.Role"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found role or permission",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "503": {
+                        "description": "Service is down",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "AuthToken": []
+                    }
+                ],
+                "description": "Return the role dto if successfully",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "role"
+                ],
+                "summary": "Remove the permission from the role",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "permission id",
+                        "name": "permissionId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/proto.Role"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found role or permission",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "503": {
+                        "description": "Service is down",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    }
+                }
+            }
+        },
+        "/team": {
+            "get": {
+                "security": [
+                    {
+                        "AuthToken": []
+                    }
+                ],
+                "description": "Return the arrays of team dto if successfully",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "team"
+                ],
+                "summary": "Get all teams",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated ids, return only these teams in the given order",
+                        "name": "ids",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/proto.Team"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid query param",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "503": {
+                        "description": "Service is down",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "AuthToken": []
+                    }
+                ],
+                "description": "Return the team dto if successfully",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "team"
+                ],
+                "summary": "Create the team",
+                "parameters": [
+                    {
+                        "description": "team dto",
+                        "name": "team",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.TeamDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/proto.Team"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found team",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "422": {
+                        "description": "A team cannot be its own ancestor",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "503": {
+                        "description": "Service is down",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    }
+                }
+            }
+        },
+        "/team/{id}": {
+            "get": {
+                "description": "Return the team dto if successfully",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "team"
+                ],
+                "summary": "Get specific team with id",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/proto.Team"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found team",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "503": {
+                        "description": "Service is down",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "AuthToken": []
+                    }
+                ],
+                "description": "Return the team dto if successfully",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "team"
+                ],
+                "summary": "Delete the team",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/proto.Team"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found team",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "503": {
+                        "description": "Service is down",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "AuthToken": []
+                    }
+                ],
+                "description": "Return the team dto if successfully, a member of the team must also own the parent team and the organization that it moves the team to",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "team"
+                ],
+                "summary": "Update the existing team",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "team dto",
+                        "name": "team",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.TeamDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/proto.Team"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found team",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "422": {
+                        "description": "A team cannot be its own ancestor",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "503": {
+                        "description": "Service is down",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    }
+                }
+            }
+        },
+        "/team/{id}/activity": {
+            "get": {
+                "security": [
+                    {
+                        "AuthToken": []
+                    }
+                ],
+                "description": "Return the page of log dto if successfully, the newest first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "team"
+                ],
+                "summary": "Get the activity logs of the team",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "POST",
+                            "EDIT",
+                            "LOGIN",
+                            "CHANGE_PASSWORD",
+                            "BOOKMARK"
+                        ],
+                        "type": "string",
+                        "description": "Log type",
+                        "name": "type",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.LogPagination"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID or log type",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found team",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "503": {
+                        "description": "Service is down",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    }
+                }
+            }
+        },
+        "/team/{id}/member": {
+            "get": {
+                "description": "Return the pagination of user dto if successfully",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "team"
+                ],
+                "summary": "Get the members of the team",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page",
+                        "name": "page",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/proto.UserPagination"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found team",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "503": {
+                        "description": "Service is down",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    }
+                }
             },
+            "post": {
+                "security": [
+                    {
+                        "AuthToken": []
+                    }
+                ],
+                "description": "Return the team dto if successfully",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "team"
+                ],
+                "summary": "Add the member to the team",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "member dto",
+                        "name": "member",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.MemberDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/proto.Team"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found team or user",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "503": {
+                        "description": "Service is down",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    }
+                }
+            }
+        },
+        "/team/{id}/member/{userId}": {
             "delete": {
                 "security": [
                     {
                         "AuthToken": []
                     }
                 ],
-                "description": "Return the organization dto if successfully",
+                "description": "Return the team dto if successfully",
                 "consumes": [
                     "application/json"
                 ],
@@ -480,9 +3206,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "organization"
+                    "team"
                 ],
-                "summary": "Delete the organization",
+                "summary": "Remove the member from the team",
                 "parameters": [
                     {
                         "type": "integer",
@@ -490,13 +3216,20 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "user id",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/proto.Organization"
+                            "$ref": "#/definitions/proto.Team"
                         }
                     },
                     "400": {
@@ -505,8 +3238,14 @@ const docTemplate = `{
                             "$ref": "#/definitions/dto.ResponseErr"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
                     "404": {
-                        "description": "Not found organization",
+                        "description": "Not found team or member",
                         "schema": {
                             "$ref": "#/definitions/dto.ResponseErr"
                         }
@@ -518,14 +3257,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "patch": {
-                "security": [
-                    {
-                        "AuthToken": []
-                    }
-                ],
-                "description": "Return the organization dto if successfully",
+            }
+        },
+        "/team/{id}/tree": {
+            "get": {
+                "description": "Return the team dto with the nested sub-teams if successfully",
                 "consumes": [
                     "application/json"
                 ],
@@ -533,9 +3269,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "organization"
+                    "team"
                 ],
-                "summary": "Update the existing organization",
+                "summary": "Get the sub-team hierarchy of the team",
                 "parameters": [
                     {
                         "type": "integer",
@@ -545,30 +3281,27 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "organization dto",
-                        "name": "organization",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.OrganizationDto"
-                        }
+                        "type": "integer",
+                        "description": "Depth of the tree",
+                        "name": "depth",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/proto.Organization"
+                            "$ref": "#/definitions/proto.Team"
                         }
                     },
                     "400": {
-                        "description": "Invalid ID",
+                        "description": "Invalid ID or depth",
                         "schema": {
                             "$ref": "#/definitions/dto.ResponseErr"
                         }
                     },
                     "404": {
-                        "description": "Not found organization",
+                        "description": "Not found team",
                         "schema": {
                             "$ref": "#/definitions/dto.ResponseErr"
                         }
@@ -582,14 +3315,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/team": {
+        "/user": {
             "get": {
                 "security": [
                     {
                         "AuthToken": []
                     }
                 ],
-                "description": "Return the arrays of team dto if successfully",
+                "description": "Return the arrays of user dto if successfully",
                 "consumes": [
                     "application/json"
                 ],
@@ -597,9 +3330,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "team"
+                    "user"
                 ],
-                "summary": "Get all teams",
+                "summary": "Get all users",
                 "parameters": [
                     {
                         "type": "integer",
@@ -612,23 +3345,29 @@ const docTemplate = `{
                         "description": "Page",
                         "name": "page",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated ids, return only these users in the given order",
+                        "name": "ids",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/proto.Team"
+                            "$ref": "#/definitions/proto.User"
                         }
                     },
                     "400": {
-                        "description": "Invalid query param",
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/dto.ResponseErr"
                         }
                     },
                     "503": {
-                        "description": "Service is down",
+                        "description": "Service Unavailable",
                         "schema": {
                             "$ref": "#/definitions/dto.ResponseErr"
                         }
@@ -641,7 +3380,7 @@ const docTemplate = `{
                         "AuthToken": []
                     }
                 ],
-                "description": "Return the team dto if successfully",
+                "description": "Return the user dto if successfully",
                 "consumes": [
                     "application/json"
                 ],
@@ -649,17 +3388,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "team"
+                    "user"
                 ],
-                "summary": "Create the team",
+                "summary": "Create the user",
                 "parameters": [
                     {
-                        "description": "team dto",
-                        "name": "team",
+                        "description": "User DTO",
+                        "name": "user",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.TeamDto"
+                            "$ref": "#/definitions/dto.UserDto"
                         }
                     }
                 ],
@@ -667,23 +3406,29 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/proto.Team"
+                            "$ref": "#/definitions/proto.User"
                         }
                     },
                     "400": {
-                        "description": "Invalid ID",
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/dto.ResponseErr"
                         }
                     },
                     "404": {
-                        "description": "Not found team",
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/dto.ResponseErr"
                         }
                     },
                     "503": {
-                        "description": "Service is down",
+                        "description": "Service Unavailable",
                         "schema": {
                             "$ref": "#/definitions/dto.ResponseErr"
                         }
@@ -691,9 +3436,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/team/{id}": {
+        "/user/{id}": {
             "get": {
-                "description": "Return the team dto if successfully",
+                "description": "Return the user dto if successfully",
                 "consumes": [
                     "application/json"
                 ],
@@ -701,9 +3446,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "team"
+                    "user"
                 ],
-                "summary": "Get specific team with id",
+                "summary": "Get specific user with id",
                 "parameters": [
                     {
                         "type": "integer",
@@ -717,23 +3462,23 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/proto.Team"
+                            "$ref": "#/definitions/proto.User"
                         }
                     },
                     "400": {
-                        "description": "Invalid ID",
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/dto.ResponseErr"
                         }
                     },
                     "404": {
-                        "description": "Not found team",
+                        "description": "Not found user",
                         "schema": {
                             "$ref": "#/definitions/dto.ResponseErr"
                         }
                     },
                     "503": {
-                        "description": "Service is down",
+                        "description": "Service Unavailable",
                         "schema": {
                             "$ref": "#/definitions/dto.ResponseErr"
                         }
@@ -746,7 +3491,7 @@ const docTemplate = `{
                         "AuthToken": []
                     }
                 ],
-                "description": "Return the team dto if successfully",
+                "description": "Return the user dto if successfully",
                 "consumes": [
                     "application/json"
                 ],
@@ -754,9 +3499,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "team"
+                    "user"
                 ],
-                "summary": "Delete the team",
+                "summary": "Delete the user",
                 "parameters": [
                     {
                         "type": "integer",
@@ -770,23 +3515,29 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/proto.Team"
+                            "$ref": "#/definitions/proto.User"
                         }
                     },
                     "400": {
-                        "description": "Invalid ID",
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/dto.ResponseErr"
                         }
                     },
                     "404": {
-                        "description": "Not found team",
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/dto.ResponseErr"
                         }
                     },
                     "503": {
-                        "description": "Service is down",
+                        "description": "Service Unavailable",
                         "schema": {
                             "$ref": "#/definitions/dto.ResponseErr"
                         }
@@ -799,7 +3550,7 @@ const docTemplate = `{
                         "AuthToken": []
                     }
                 ],
-                "description": "Return the team dto if successfully",
+                "description": "Return the user dto if successfully",
                 "consumes": [
                     "application/json"
                 ],
@@ -807,9 +3558,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "team"
+                    "user"
                 ],
-                "summary": "Update the existing team",
+                "summary": "Update the existing user",
                 "parameters": [
                     {
                         "type": "integer",
@@ -819,12 +3570,12 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "team dto",
-                        "name": "team",
+                        "description": "user dto",
+                        "name": "user",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.TeamDto"
+                            "$ref": "#/definitions/dto.UserDto"
                         }
                     }
                 ],
@@ -832,23 +3583,29 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/proto.Team"
+                            "$ref": "#/definitions/proto.User"
                         }
                     },
                     "400": {
-                        "description": "Invalid ID",
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/dto.ResponseErr"
                         }
                     },
                     "404": {
-                        "description": "Not found team",
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/dto.ResponseErr"
                         }
                     },
                     "503": {
-                        "description": "Service is down",
+                        "description": "Service Unavailable",
                         "schema": {
                             "$ref": "#/definitions/dto.ResponseErr"
                         }
@@ -856,14 +3613,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/user": {
+        "/user/{id}/activity": {
             "get": {
                 "security": [
                     {
                         "AuthToken": []
                     }
                 ],
-                "description": "Return the arrays of user dto if successfully",
+                "description": "Return the page of log dto if successfully, the newest first",
                 "consumes": [
                     "application/json"
                 ],
@@ -873,8 +3630,15 @@ const docTemplate = `{
                 "tags": [
                     "user"
                 ],
-                "summary": "Get all users",
+                "summary": "Get the activity logs of the user",
                 "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
                     {
                         "type": "integer",
                         "description": "Limit",
@@ -886,36 +3650,69 @@ const docTemplate = `{
                         "description": "Page",
                         "name": "page",
                         "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "POST",
+                            "EDIT",
+                            "LOGIN",
+                            "CHANGE_PASSWORD",
+                            "BOOKMARK"
+                        ],
+                        "type": "string",
+                        "description": "Log type",
+                        "name": "type",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/proto.User"
+                            "$ref": "#/definitions/dto.LogPagination"
                         }
                     },
                     "400": {
-                        "description": "Bad Request",
+                        "description": "Invalid ID or log type",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid token",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "403": {
+                        "description": "Insufficient permission",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "404": {
+                        "description": "Not found user",
                         "schema": {
                             "$ref": "#/definitions/dto.ResponseErr"
                         }
                     },
                     "503": {
-                        "description": "Service Unavailable",
+                        "description": "Service is down",
                         "schema": {
                             "$ref": "#/definitions/dto.ResponseErr"
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/user/{id}/address": {
+            "get": {
                 "security": [
                     {
                         "AuthToken": []
                     }
                 ],
-                "description": "Return the user dto if successfully",
+                "description": "Return the location dto if successfully",
                 "consumes": [
                     "application/json"
                 ],
@@ -925,49 +3722,56 @@ const docTemplate = `{
                 "tags": [
                     "user"
                 ],
-                "summary": "Create the user",
+                "summary": "Get the address of the user",
                 "parameters": [
                     {
-                        "description": "User DTO",
-                        "name": "user",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.UserDto"
-                        }
+                        "type": "integer",
+                        "description": "id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/proto.User"
+                            "$ref": "#/definitions/proto.Location"
                         }
                     },
                     "400": {
-                        "description": "Bad Request",
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/dto.ResponseErr"
                         }
                     },
                     "404": {
-                        "description": "Not Found",
+                        "description": "Not found user or address",
                         "schema": {
                             "$ref": "#/definitions/dto.ResponseErr"
                         }
                     },
                     "503": {
-                        "description": "Service Unavailable",
+                        "description": "Service is down",
                         "schema": {
                             "$ref": "#/definitions/dto.ResponseErr"
                         }
                     }
                 }
-            }
-        },
-        "/user/{id}": {
-            "get": {
-                "description": "Return the user dto if successfully",
+            },
+            "put": {
+                "security": [
+                    {
+                        "AuthToken": []
+                    }
+                ],
+                "description": "Return the location dto if successfully",
                 "consumes": [
                     "application/json"
                 ],
@@ -977,7 +3781,7 @@ const docTemplate = `{
                 "tags": [
                     "user"
                 ],
-                "summary": "Get specific user with id",
+                "summary": "Create or update the address of the user",
                 "parameters": [
                     {
                         "type": "integer",
@@ -985,42 +3789,54 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "location dto",
+                        "name": "address",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.LocationDto"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/proto.User"
+                            "$ref": "#/definitions/proto.Location"
                         }
                     },
                     "400": {
-                        "description": "Bad Request",
+                        "description": "Invalid ID",
                         "schema": {
                             "$ref": "#/definitions/dto.ResponseErr"
                         }
                     },
-                    "404": {
-                        "description": "Not found user",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/dto.ResponseErr"
                         }
                     },
-                    "503": {
-                        "description": "Service Unavailable",
+                    "404": {
+                        "description": "Not found user",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "503": {
+                        "description": "Service is down",
                         "schema": {
                             "$ref": "#/definitions/dto.ResponseErr"
                         }
                     }
-                }
-            },
-            "delete": {
-                "security": [
-                    {
-                        "AuthToken": []
-                    }
-                ],
-                "description": "Return the user dto if successfully",
+                }
+            }
+        },
+        "/user/{id}/contact": {
+            "get": {
+                "description": "Return the contact dto if successfully",
                 "consumes": [
                     "application/json"
                 ],
@@ -1030,7 +3846,7 @@ const docTemplate = `{
                 "tags": [
                     "user"
                 ],
-                "summary": "Delete the user",
+                "summary": "Get the contact of the user",
                 "parameters": [
                     {
                         "type": "integer",
@@ -1044,36 +3860,36 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/proto.User"
+                            "$ref": "#/definitions/proto.Contact"
                         }
                     },
                     "400": {
-                        "description": "Bad Request",
+                        "description": "Invalid ID",
                         "schema": {
                             "$ref": "#/definitions/dto.ResponseErr"
                         }
                     },
                     "404": {
-                        "description": "Not Found",
+                        "description": "Not found user or contact",
                         "schema": {
                             "$ref": "#/definitions/dto.ResponseErr"
                         }
                     },
                     "503": {
-                        "description": "Service Unavailable",
+                        "description": "Service is down",
                         "schema": {
                             "$ref": "#/definitions/dto.ResponseErr"
                         }
                     }
                 }
             },
-            "patch": {
+            "put": {
                 "security": [
                     {
                         "AuthToken": []
                     }
                 ],
-                "description": "Return the user dto if successfully",
+                "description": "Return the contact dto if successfully",
                 "consumes": [
                     "application/json"
                 ],
@@ -1083,7 +3899,7 @@ const docTemplate = `{
                 "tags": [
                     "user"
                 ],
-                "summary": "Update the existing user",
+                "summary": "Create or update the contact of the user",
                 "parameters": [
                     {
                         "type": "integer",
@@ -1093,12 +3909,12 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "user dto",
-                        "name": "user",
+                        "description": "contact dto",
+                        "name": "contact",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.UserDto"
+                            "$ref": "#/definitions/dto.ContactDto"
                         }
                     }
                 ],
@@ -1106,23 +3922,29 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/proto.User"
+                            "$ref": "#/definitions/proto.Contact"
                         }
                     },
                     "400": {
-                        "description": "Bad Request",
+                        "description": "Invalid ID",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseErr"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/dto.ResponseErr"
                         }
                     },
                     "404": {
-                        "description": "Not Found",
+                        "description": "Not found user",
                         "schema": {
                             "$ref": "#/definitions/dto.ResponseErr"
                         }
                     },
                     "503": {
-                        "description": "Service Unavailable",
+                        "description": "Service is down",
                         "schema": {
                             "$ref": "#/definitions/dto.ResponseErr"
                         }
@@ -1145,10 +3967,99 @@ const docTemplate = `{
                 "old_password": {
                     "type": "string",
                     "example": "password"
+                }
+            }
+        },
+        "dto.ContactDto": {
+            "type": "object",
+            "properties": {
+                "facebook": {
+                    "type": "string",
+                    "example": "https://www.facebook.com/samithiwat"
                 },
-                "user_id": {
-                    "type": "integer",
-                    "example": 1
+                "instagram": {
+                    "type": "string",
+                    "example": "https://www.instagram.com/samithiwat"
+                },
+                "linkedin": {
+                    "type": "string",
+                    "example": "https://www.linkedin.com/in/samithiwat"
+                },
+                "twitter": {
+                    "type": "string",
+                    "example": "https://twitter.com/samithiwat"
+                }
+            }
+        },
+        "dto.DeviceSession": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "current": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "ip": {
+                    "type": "string",
+                    "example": "127.0.0.1"
+                },
+                "last_seen_at": {
+                    "type": "string"
+                },
+                "user_agent": {
+                    "type": "string",
+                    "example": "Mozilla/5.0 (X11; Linux x86_64)"
+                }
+            }
+        },
+        "dto.LocationDto": {
+            "type": "object",
+            "required": [
+                "address",
+                "country",
+                "district",
+                "province",
+                "zipcode"
+            ],
+            "properties": {
+                "address": {
+                    "type": "string",
+                    "example": "254 Phayathai Road"
+                },
+                "country": {
+                    "type": "string",
+                    "example": "Thailand"
+                },
+                "district": {
+                    "type": "string",
+                    "example": "Pathumwan"
+                },
+                "province": {
+                    "type": "string",
+                    "example": "Bangkok"
+                },
+                "zipcode": {
+                    "type": "string",
+                    "maxLength": 10,
+                    "example": "10330"
+                }
+            }
+        },
+        "dto.LogPagination": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/proto.Log"
+                    }
+                },
+                "meta": {
+                    "$ref": "#/definitions/proto.PaginationMetadata"
                 }
             }
         },
@@ -1168,6 +4079,30 @@ const docTemplate = `{
                 }
             }
         },
+        "dto.MemberDto": {
+            "type": "object",
+            "required": [
+                "user_id"
+            ],
+            "properties": {
+                "user_id": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "dto.MemberRoleDto": {
+            "type": "object",
+            "required": [
+                "role_id"
+            ],
+            "properties": {
+                "role_id": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
         "dto.OrganizationDto": {
             "type": "object",
             "required": [
@@ -1175,17 +4110,50 @@ const docTemplate = `{
                 "name"
             ],
             "properties": {
+                "contact": {
+                    "$ref": "#/definitions/dto.ContactDto"
+                },
                 "description": {
                     "type": "string"
                 },
                 "email": {
                     "type": "string"
                 },
+                "location": {
+                    "$ref": "#/definitions/dto.LocationDto"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.PermissionDto": {
+            "type": "object",
+            "required": [
+                "code",
+                "name"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "user:update"
+                },
                 "name": {
                     "type": "string"
                 }
             }
         },
+        "dto.RecoveryCodes": {
+            "type": "object",
+            "properties": {
+                "codes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
         "dto.RedeemNewToken": {
             "type": "object",
             "required": [
@@ -1238,11 +4206,40 @@ const docTemplate = `{
                 "message": {
                     "type": "string"
                 },
+                "request_id": {
+                    "type": "string"
+                },
                 "status_code": {
                     "type": "integer"
                 }
             }
         },
+        "dto.RoleDto": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.Session": {
+            "type": "object",
+            "properties": {
+                "csrf_token": {
+                    "type": "string"
+                },
+                "expires_in": {
+                    "type": "integer",
+                    "example": 3600
+                }
+            }
+        },
         "dto.TeamDto": {
             "type": "object",
             "required": [
@@ -1254,6 +4251,91 @@ const docTemplate = `{
                 },
                 "name": {
                     "type": "string"
+                },
+                "organization_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "parent_team_id": {
+                    "description": "ParentTeamID moves the team under the parent team on update, an explicit null or 0 detaches it from its parent",
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "dto.TokenCacheStats": {
+            "type": "object",
+            "properties": {
+                "capacity": {
+                    "type": "integer"
+                },
+                "coalesced": {
+                    "type": "integer"
+                },
+                "hits": {
+                    "type": "integer"
+                },
+                "misses": {
+                    "type": "integer"
+                },
+                "size": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.TotpCode": {
+            "type": "object",
+            "required": [
+                "code"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "123456"
+                }
+            }
+        },
+        "dto.TotpSetup": {
+            "type": "object",
+            "properties": {
+                "qr_code": {
+                    "type": "string"
+                },
+                "secret": {
+                    "type": "string",
+                    "example": "JBSWY3DPEHPK3PXP"
+                },
+                "uri": {
+                    "type": "string",
+                    "example": "otpauth://totp/Samithiwat.dev:Smithy?algorithm=SHA1\u0026digits=6\u0026issuer=Samithiwat.dev\u0026period=30\u0026secret=JBSWY3DPEHPK3PXP"
+                }
+            }
+        },
+        "dto.TwoFactorChallenge": {
+            "type": "object",
+            "properties": {
+                "challenge_token": {
+                    "type": "string"
+                },
+                "expires_in": {
+                    "type": "integer",
+                    "example": 300
+                }
+            }
+        },
+        "dto.TwoFactorVerify": {
+            "type": "object",
+            "required": [
+                "challenge_token",
+                "code"
+            ],
+            "properties": {
+                "challenge_token": {
+                    "type": "string"
+                },
+                "code": {
+                    "type": "string",
+                    "example": "123456"
                 }
             }
         },
@@ -1265,6 +4347,12 @@ const docTemplate = `{
                 "lastname"
             ],
             "properties": {
+                "address": {
+                    "$ref": "#/definitions/dto.LocationDto"
+                },
+                "contact": {
+                    "$ref": "#/definitions/dto.ContactDto"
+                },
                 "display_name": {
                     "type": "string"
                 },
@@ -1403,6 +4491,26 @@ const docTemplate = `{
                 }
             }
         },
+        "proto.PaginationMetadata": {
+            "type": "object",
+            "properties": {
+                "currentPage": {
+                    "type": "integer"
+                },
+                "itemCount": {
+                    "type": "integer"
+                },
+                "itemsPerPage": {
+                    "type": "integer"
+                },
+                "totalItem": {
+                    "type": "integer"
+                },
+                "totalPage": {
+                    "type": "integer"
+                }
+            }
+        },
         "proto.Permission": {
             "type": "object",
             "properties": {
@@ -1527,6 +4635,20 @@ const docTemplate = `{
                     }
                 }
             }
+        },
+        "proto.UserPagination": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/proto.User"
+                    }
+                },
+                "meta": {
+                    "$ref": "#/definitions/proto.PaginationMetadata"
+                }
+            }
         }
     },
     "securityDefinitions": {
@@ -1552,6 +4674,14 @@ const docTemplate = `{
         {
             "description": "# Team Tag API Documentation\r\n**Team** functions goes here",
             "name": "team"
+        },
+        {
+            "description": "# Role Tag API Documentation\r\n**Role** functions goes here",
+            "name": "role"
+        },
+        {
+            "description": "# Permission Tag API Documentation\r\n**Permission** functions goes here",
+            "name": "permission"
         }
     ]
 }`
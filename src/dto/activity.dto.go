@@ -0,0 +1,14 @@
+package dto
+
+import "github.com/samithiwat/samithiwat-backend-gateway/src/proto"
+
+type ActivityQueryParams struct {
+	Limit int64  `query:"limit"`
+	Page  int64  `query:"page"`
+	Type  string `query:"type"`
+}
+
+type LogPagination struct {
+	Items []*proto.Log              `json:"items"`
+	Meta  *proto.PaginationMetadata `json:"meta"`
+}
@@ -14,8 +14,9 @@ type Login struct {
 	Password string `json:"password" validate:"required" example:"password"`
 }
 
+// ChangePassword always changes the password of the caller, the user id is taken from the token and never from the body
 type ChangePassword struct {
-	UserId      uint32 `json:"user_id" example:"1"`
+	UserId      uint32 `json:"-" swaggerignore:"true"`
 	OldPassword string `json:"old_password" validate:"required" example:"password"`
 	NewPassword string `json:"new_password" validate:"password" example:"new_password"`
 }
@@ -27,3 +28,68 @@ type Validate struct {
 type RedeemNewToken struct {
 	RefreshToken string `json:"refresh_token" validate:"required"`
 }
+
+type TokenCacheStats struct {
+	Hits      uint64 `json:"hits"`
+	Misses    uint64 `json:"misses"`
+	Coalesced uint64 `json:"coalesced"`
+	Size      int    `json:"size"`
+	Capacity  int    `json:"capacity"`
+}
+
+// Session is returned instead of the credential when the tokens are kept in cookies
+type Session struct {
+	ExpiresIn int32  `json:"expires_in" example:"3600"`
+	CsrfToken string `json:"csrf_token"`
+}
+
+type Cookie struct {
+	Name     string
+	Value    string
+	Path     string
+	Domain   string
+	MaxAge   int
+	Secure   bool
+	HttpOnly bool
+	SameSite string
+}
+
+// Principal is the caller that the auth guard authenticated, an api key client that does not act for a user has no user id
+type Principal struct {
+	UserID    int32
+	ApiKeyID  string
+	SessionID string
+	// Admin is set when a route of the owner access let the caller through by the admin permission instead of the ownership
+	Admin bool
+}
+
+func (p *Principal) IsUser() bool {
+	return p.UserID > 0
+}
+
+// SignedRequest is the part of the request that is covered by the signature of an api key client, the uri is the path
+// with the query string as the client sent it
+type SignedRequest struct {
+	Method    string
+	URI       string
+	Body      []byte
+	Timestamp string
+	Signature string
+}
+
+// ApiClient is the server to server client that is authenticated by an api key
+type ApiClient struct {
+	ID     string
+	Scopes []string
+	UserID int32
+}
+
+func (c *ApiClient) HasScope(scope string) bool {
+	for _, s := range c.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+
+	return false
+}
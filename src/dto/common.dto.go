@@ -1,6 +1,9 @@
 package dto
 
-import "github.com/go-playground/validator/v10"
+import (
+	"encoding/json"
+	"github.com/go-playground/validator/v10"
+)
 
 var validate = validator.New()
 
@@ -9,10 +12,12 @@ type PaginationQueryParams struct {
 	Page  int64 `query:"page"`
 }
 
+// ResponseErr is the error of a request, the request id is set by the gateway when the error is written
 type ResponseErr struct {
 	StatusCode int         `json:"status_code"`
 	Message    string      `json:"message"`
 	Data       interface{} `json:"data"`
+	RequestID  string      `json:"request_id,omitempty"`
 }
 
 type BadReqErrResponse struct {
@@ -21,3 +26,27 @@ type BadReqErrResponse struct {
 	Tag         string      `json:"tag"`
 	Value       interface{} `json:"value"`
 }
+
+// NullableID is an optional id of a request body, Set tells an absent field from an explicit null or 0 which both leave the ID at 0
+type NullableID struct {
+	Set bool
+	ID  int32 `validate:"gte=0"`
+}
+
+func (n *NullableID) UnmarshalJSON(data []byte) error {
+	n.Set = true
+	if string(data) == "null" {
+		n.ID = 0
+		return nil
+	}
+
+	return json.Unmarshal(data, &n.ID)
+}
+
+func (n NullableID) MarshalJSON() ([]byte, error) {
+	if n.ID == 0 {
+		return []byte("null"), nil
+	}
+
+	return json.Marshal(n.ID)
+}
@@ -0,0 +1,8 @@
+package dto
+
+type ContactDto struct {
+	Facebook  string `json:"facebook" validate:"omitempty,url" example:"https://www.facebook.com/samithiwat"`
+	Instagram string `json:"instagram" validate:"omitempty,url" example:"https://www.instagram.com/samithiwat"`
+	Twitter   string `json:"twitter" validate:"omitempty,url" example:"https://twitter.com/samithiwat"`
+	Linkedin  string `json:"linkedin" validate:"omitempty,url" example:"https://www.linkedin.com/in/samithiwat"`
+}
@@ -0,0 +1,9 @@
+package dto
+
+type LocationDto struct {
+	Address  string `json:"address" validate:"required" example:"254 Phayathai Road"`
+	District string `json:"district" validate:"required" example:"Pathumwan"`
+	Province string `json:"province" validate:"required" example:"Bangkok"`
+	Country  string `json:"country" validate:"required" example:"Thailand"`
+	Zipcode  string `json:"zipcode" validate:"required,numeric,max=10" example:"10330"`
+}
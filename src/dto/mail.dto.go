@@ -0,0 +1,8 @@
+package dto
+
+// Mail is a plain text mail that a Mailer delivers
+type Mail struct {
+	To      string
+	Subject string
+	Body    string
+}
@@ -0,0 +1,9 @@
+package dto
+
+type MemberDto struct {
+	UserID int32 `json:"user_id" validate:"required,gt=0" example:"1"`
+}
+
+type MemberRoleDto struct {
+	RoleID int32 `json:"role_id" validate:"required,gt=0" example:"1"`
+}
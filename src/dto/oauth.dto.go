@@ -0,0 +1,20 @@
+package dto
+
+// OAuthCallback is the query that the provider sends back to the redirect url
+type OAuthCallback struct {
+	Code             string `query:"code"`
+	State            string `query:"state"`
+	Error            string `query:"error"`
+	ErrorDescription string `query:"error_description"`
+}
+
+// OAuthIdentity is the user that the provider vouches for in its verified ID token
+type OAuthIdentity struct {
+	Provider    string
+	Subject     string
+	Email       string
+	Firstname   string
+	Lastname    string
+	DisplayName string
+	ImageUrl    string
+}
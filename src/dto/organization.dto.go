@@ -1,7 +1,9 @@
 package dto
 
 type OrganizationDto struct {
-	Name        string `json:"name" validate:"required"`
-	Email       string `json:"email" validate:"required"`
-	Description string `json:"description"`
+	Name        string       `json:"name" validate:"required"`
+	Email       string       `json:"email" validate:"required"`
+	Description string       `json:"description"`
+	Contact     *ContactDto  `json:"contact"`
+	Location    *LocationDto `json:"location"`
 }
@@ -0,0 +1,6 @@
+package dto
+
+type PermissionDto struct {
+	Name string `json:"name" validate:"required"`
+	Code string `json:"code" validate:"required,permission_code" example:"user:update"`
+}
@@ -0,0 +1,13 @@
+package dto
+
+import "time"
+
+// RateLimit is the state of the bucket of a caller after a request took a token from it, the reset is how long the
+// bucket takes to be full again and the retry after is how long a rejected caller has to wait for the next token
+type RateLimit struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	Reset      time.Duration
+	RetryAfter time.Duration
+}
@@ -0,0 +1,6 @@
+package dto
+
+type RoleDto struct {
+	Name        string `json:"name" validate:"required"`
+	Description string `json:"description"`
+}
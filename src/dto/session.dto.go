@@ -0,0 +1,19 @@
+package dto
+
+import "time"
+
+// Device is where a credential was issued to, it is kept with the session of the credential
+type Device struct {
+	UserAgent string
+	IP        string
+}
+
+// DeviceSession is an active session of the user, Current is the session of the token of the request
+type DeviceSession struct {
+	ID         string    `json:"id"`
+	UserAgent  string    `json:"user_agent" example:"Mozilla/5.0 (X11; Linux x86_64)"`
+	IP         string    `json:"ip" example:"127.0.0.1"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	Current    bool      `json:"current"`
+}
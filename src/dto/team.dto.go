@@ -1,6 +1,13 @@
 package dto
 
 type TeamDto struct {
-	Name        string `json:"name" validate:"required"`
-	Description string `json:"description"`
+	Name           string `json:"name" validate:"required"`
+	Description    string `json:"description"`
+	OrganizationID int32  `json:"organization_id" validate:"omitempty,gt=0" example:"1"`
+	// ParentTeamID moves the team under the parent team on update, an explicit null or 0 detaches it from its parent
+	ParentTeamID NullableID `json:"parent_team_id" swaggertype:"integer" example:"1"`
+}
+
+type TreeQueryParams struct {
+	Depth int `query:"depth"`
 }
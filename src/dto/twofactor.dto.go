@@ -0,0 +1,29 @@
+package dto
+
+// TotpSetup is the secret of the authenticator that is not confirmed yet, the qr code is a base64 encoded PNG of the uri
+type TotpSetup struct {
+	Secret string `json:"secret" example:"JBSWY3DPEHPK3PXP"`
+	Uri    string `json:"uri" example:"otpauth://totp/Samithiwat.dev:Smithy?algorithm=SHA1&digits=6&issuer=Samithiwat.dev&period=30&secret=JBSWY3DPEHPK3PXP"`
+	QrCode string `json:"qr_code"`
+}
+
+type TotpCode struct {
+	Code string `json:"code" validate:"required" example:"123456"`
+}
+
+// RecoveryCodes are shown only once, each of them can be used once instead of a code of the authenticator
+type RecoveryCodes struct {
+	Codes []string `json:"codes"`
+}
+
+// TwoFactorChallenge is returned by the login instead of the credential when the user has enabled the two-factor authentication
+type TwoFactorChallenge struct {
+	ChallengeToken string `json:"challenge_token"`
+	ExpiresIn      int32  `json:"expires_in" example:"300"`
+}
+
+// TwoFactorVerify exchanges the challenge for the credential, the code is a code of the authenticator or a recovery code
+type TwoFactorVerify struct {
+	ChallengeToken string `json:"challenge_token" validate:"required"`
+	Code           string `json:"code" validate:"required" example:"123456"`
+}
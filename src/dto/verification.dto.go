@@ -0,0 +1,5 @@
+package dto
+
+type VerifyEmail struct {
+	Token string `query:"token" validate:"required"`
+}
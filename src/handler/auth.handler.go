@@ -1,45 +1,135 @@
 package handler
 
 import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
 	"fmt"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/config"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/constant"
 	"github.com/samithiwat/samithiwat-backend-gateway/src/dto"
 	"github.com/samithiwat/samithiwat-backend-gateway/src/proto"
 	validate "github.com/samithiwat/samithiwat-backend-gateway/src/validator"
+	"math"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type AuthHandler struct {
-	service  AuthService
-	userSrv  UserService
-	validate *validate.DtoValidator
+	service     AuthService
+	userSrv     UserService
+	activitySrv ActivityService
+	tokenCache  TokenCache
+	lockoutSrv  LockoutService
+	verifier    EmailVerifier
+	twoFactor   TwoFactorChallenger
+	sessions    SessionService
+	emails      UserEmails
+	session     config.Session
+	validate    *validate.DtoValidator
 }
 
-func NewAuthHandler(s AuthService, u UserService, v *validate.DtoValidator) *AuthHandler {
+// AuthDeps are the collaborators of the AuthHandler besides the auth service, a disabled feature is given its Disabled service
+type AuthDeps struct {
+	Users      UserService
+	Activity   ActivityService
+	TokenCache TokenCache
+	Lockout    LockoutService
+	Verifier   EmailVerifier
+	TwoFactor  TwoFactorChallenger
+	Sessions   SessionService
+	Emails     UserEmails
+}
+
+func NewAuthHandler(s AuthService, deps AuthDeps, session config.Session, v *validate.DtoValidator) *AuthHandler {
 	return &AuthHandler{
-		service:  s,
-		validate: v,
-		userSrv:  u,
+		service:     s,
+		validate:    v,
+		userSrv:     deps.Users,
+		activitySrv: deps.Activity,
+		tokenCache:  deps.TokenCache,
+		lockoutSrv:  deps.Lockout,
+		verifier:    deps.Verifier,
+		twoFactor:   deps.TwoFactor,
+		sessions:    deps.Sessions,
+		emails:      deps.Emails,
+		session:     session,
 	}
 }
 
 type AuthContext interface {
+	UserContext() context.Context
 	Bind(interface{}) error
 	JSON(int, interface{})
-	UserID() int32
+	Principal() *dto.Principal
+	GetCookie(string) string
+	SetCookie(*dto.Cookie)
+	RequestHeader(string) string
+	SetResponseHeader(string, string)
+	IP() string
+	Device() *dto.Device
+	SessionParam() string
+	VerifyEmailQuery(*dto.VerifyEmail) error
 }
 
 type AuthService interface {
-	Register(*dto.Register) (*proto.User, *dto.ResponseErr)
-	Login(*dto.Login) (*proto.Credential, *dto.ResponseErr)
-	Logout(uint32) (bool, *dto.ResponseErr)
-	ChangePassword(*dto.ChangePassword) (bool, *dto.ResponseErr)
-	Validate(string) (uint32, *dto.ResponseErr)
-	RefreshToken(string) (*proto.Credential, *dto.ResponseErr)
+	Register(context.Context, *dto.Register) (*proto.User, *dto.ResponseErr)
+	Login(context.Context, *dto.Login) (*proto.Credential, *dto.ResponseErr)
+	Logout(context.Context, uint32) (bool, *dto.ResponseErr)
+	ChangePassword(context.Context, *dto.ChangePassword) (bool, *dto.ResponseErr)
+	Validate(context.Context, string) (uint32, *dto.ResponseErr)
+	RefreshToken(context.Context, string) (*proto.Credential, *dto.ResponseErr)
+}
+
+type TokenCache interface {
+	EvictUser(uint32)
+	Stats() *dto.TokenCacheStats
+}
+
+// EmailVerifier mails the verification links, the DisabledVerification does nothing when the verification is not enabled
+type EmailVerifier interface {
+	Send(uint32, string)
+	Resend(uint32) *dto.ResponseErr
+	Verify(string) *dto.ResponseErr
+}
+
+// SessionRecorder starts the session of an issued credential with the device that it was issued to
+type SessionRecorder interface {
+	Record(context.Context, uint32, *proto.Credential, *dto.Device)
+}
+
+// SessionRevoker rejects the tokens of every session of the user
+type SessionRevoker interface {
+	RevokeAll(context.Context, uint32) *dto.ResponseErr
+}
+
+// UserEmails remembers the email that the user logged in with, the upstream user has no email
+type UserEmails interface {
+	Remember(context.Context, uint32, string)
+	Find(context.Context, uint32) (string, *dto.ResponseErr)
+}
+
+type SessionService interface {
+	SessionRecorder
+	SessionRevoker
+	CheckRefresh(context.Context, string) *dto.ResponseErr
+	Rotate(context.Context, string, *proto.Credential, *dto.Device)
+	List(context.Context, uint32, string) ([]*dto.DeviceSession, *dto.ResponseErr)
+	Revoke(context.Context, uint32, string) *dto.ResponseErr
+}
+
+type LockoutService interface {
+	Check(context.Context, string, string) time.Duration
+	Fail(context.Context, string, string)
+	Reset(context.Context, string, string)
 }
 
 // Register is a function that register user account
 // @Summary Register user account
-// @Description Return the user dto if successfully
+// @Description Return the user dto if successfully, the verification link is mailed to the email when the email verification is enabled
 // @Param register body dto.Register true "register dto"
 // @Tags auth
 // @Accept json
@@ -69,12 +159,23 @@ func (h *AuthHandler) Register(c AuthContext) {
 		return
 	}
 
-	res, errRes := h.service.Register(&register)
+	if errors := h.validate.ValidatePassword("Password", register.Password, register.Email, register.Firstname, register.Lastname, register.DisplayName); errors != nil {
+		c.JSON(http.StatusBadRequest, &dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Invalid body request",
+			Data:       errors,
+		})
+		return
+	}
+
+	res, errRes := h.service.Register(c.UserContext(), &register)
 	if errRes != nil {
 		c.JSON(errRes.StatusCode, errRes)
 		return
 	}
 
+	h.verifier.Send(res.Id, register.Email)
+
 	c.JSON(http.StatusCreated, res)
 	return
 }
@@ -87,8 +188,11 @@ func (h *AuthHandler) Register(c AuthContext) {
 // @Accept json
 // @Produce json
 // @Success 201 {object} proto.Credential
+// @Success 200 {object} dto.Session "When the session is kept in cookies"
+// @Success 202 {object} dto.TwoFactorChallenge "When the user has enabled the two-factor authentication, the challenge is verified at /auth/2fa/verify"
 // @Failure 400 {object} dto.ResponseErr "Invalid request body"
 // @Failure 401 {object} dto.ResponseErr "Invalid email or username"
+// @Failure 429 {object} dto.ResponseErr "Too many attempts"
 // @Failure 503 {object} dto.ResponseErr "Service is down"
 // @Router /auth/login [post]
 func (h *AuthHandler) Login(c AuthContext) {
@@ -111,16 +215,140 @@ func (h *AuthHandler) Login(c AuthContext) {
 		return
 	}
 
-	res, errRes := h.service.Login(&login)
+	email := strings.ToLower(login.Email)
+	if h.lockedOut(c, constant.LockoutLoginEmail, email) || h.lockedOut(c, constant.LockoutLoginIP, c.IP()) {
+		return
+	}
+
+	res, errRes := h.service.Login(c.UserContext(), &login)
+	if errRes != nil {
+		if isCredentialErr(errRes) {
+			h.lockoutSrv.Fail(c.UserContext(), constant.LockoutLoginEmail, email)
+			h.lockoutSrv.Fail(c.UserContext(), constant.LockoutLoginIP, c.IP())
+		}
+
+		c.JSON(errRes.StatusCode, errRes)
+		return
+	}
+
+	// the ip is shared by the users behind the same proxy so only the email is forgiven
+	h.lockoutSrv.Reset(c.UserContext(), constant.LockoutLoginEmail, email)
+
+	// the credential does not carry the user id so it is resolved from the new access token, without it the two-factor
+	// authentication cannot be checked so the credential is not handed out
+	userId, errRes := h.service.Validate(c.UserContext(), res.AccessToken)
+	if errRes != nil {
+		c.JSON(errRes.StatusCode, errRes)
+		return
+	}
+
+	// the new password of the user is checked against its email
+	h.emails.Remember(c.UserContext(), userId, login.Email)
+
+	challenge, errRes := h.twoFactor.Challenge(c.UserContext(), userId, res)
 	if errRes != nil {
 		c.JSON(errRes.StatusCode, errRes)
 		return
 	}
 
+	if challenge != nil {
+		c.JSON(http.StatusAccepted, challenge)
+		return
+	}
+
+	h.activitySrv.Record(proto.LogType_LOGIN, userId, "Login")
+	h.sessions.Record(c.UserContext(), userId, res, c.Device())
+
+	if h.session.CookieMode() {
+		h.setSession(c, res)
+		return
+	}
+
 	c.JSON(http.StatusOK, res)
 	return
 }
 
+// VerifyEmail is a function that confirm the email of the user
+// @Summary Verify the email of user account
+// @Description Confirm the email with the token of the verification link
+// @Param token query string true "verification token"
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Success 204
+// @Failure 400 {object} dto.ResponseErr "Invalid or expired verification token"
+// @Failure 404 {object} dto.ResponseErr "Email verification is not enabled"
+// @Failure 503 {object} dto.ResponseErr "Service is down"
+// @Router /auth/verify-email [get]
+func (h *AuthHandler) VerifyEmail(c AuthContext) {
+	query := dto.VerifyEmail{}
+	if err := c.VerifyEmailQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, &dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Invalid query param",
+		})
+		return
+	}
+
+	if errors := h.validate.Validate(query); errors != nil {
+		c.JSON(http.StatusBadRequest, &dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Invalid query param",
+			Data:       errors,
+		})
+		return
+	}
+
+	if errRes := h.verifier.Verify(query.Token); errRes != nil {
+		c.JSON(errRes.StatusCode, errRes)
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+	return
+}
+
+// ResendVerification is a function that mail the verification link again
+// @Summary Resend the verification link
+// @Description Mail a new verification link to the email that the caller registered with, the resends are limited in a window
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Success 204
+// @Failure 401 {object} dto.ResponseErr "Invalid token"
+// @Failure 404 {object} dto.ResponseErr "Email verification is not enabled"
+// @Failure 409 {object} dto.ResponseErr "Email is already verified"
+// @Failure 429 {object} dto.ResponseErr "Too many attempts"
+// @Failure 503 {object} dto.ResponseErr "Service is down"
+// @Security     AuthToken
+// @Router /auth/resend-verification [post]
+func (h *AuthHandler) ResendVerification(c AuthContext) {
+	principal := c.Principal()
+	if !principal.IsUser() {
+		c.JSON(http.StatusUnauthorized, &dto.ResponseErr{
+			StatusCode: http.StatusUnauthorized,
+			Message:    "Invalid token",
+		})
+		return
+	}
+
+	key := strconv.Itoa(int(principal.UserID))
+	if h.lockedOut(c, constant.LockoutResendVerification, key) {
+		return
+	}
+
+	if errRes := h.verifier.Resend(uint32(principal.UserID)); errRes != nil {
+		c.JSON(errRes.StatusCode, errRes)
+		return
+	}
+
+	// every resend counts toward the limit, the lockout service treats it as an attempt
+	h.lockoutSrv.Fail(c.UserContext(), constant.LockoutResendVerification, key)
+
+	c.JSON(http.StatusNoContent, nil)
+	return
+}
+
 // Logout is a function log out from service
 // @Summary Logout user from service
 // @Description Return the user dto if successfully
@@ -133,9 +361,105 @@ func (h *AuthHandler) Login(c AuthContext) {
 // @Security     AuthToken
 // @Router /auth/logout [get]
 func (h *AuthHandler) Logout(c AuthContext) {
-	userId := c.UserID()
+	principal := c.Principal()
+	if !principal.IsUser() {
+		c.JSON(http.StatusUnauthorized, &dto.ResponseErr{
+			StatusCode: http.StatusUnauthorized,
+			Message:    "Invalid token",
+		})
+		return
+	}
+
+	userId := principal.UserID
+
+	res, errRes := h.service.Logout(c.UserContext(), uint32(userId))
+	if errRes != nil {
+		c.JSON(errRes.StatusCode, errRes)
+		return
+	}
+
+	if principal.SessionID != "" {
+		if errRes := h.sessions.Revoke(c.UserContext(), uint32(userId), principal.SessionID); errRes != nil && errRes.StatusCode != http.StatusNotFound {
+			c.JSON(errRes.StatusCode, errRes)
+			return
+		}
+	}
+
+	h.tokenCache.EvictUser(uint32(userId))
+
+	if h.session.CookieMode() {
+		h.clearSession(c)
+	}
+
+	c.JSON(http.StatusOK, res)
+	return
+}
+
+// LogoutAll is a function log out from every session
+// @Summary Logout user from every session
+// @Description Revoke every session of the user, the tokens of the sessions are rejected
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Success 204
+// @Failure 401 {object} dto.ResponseErr "Invalid token"
+// @Failure 503 {object} dto.ResponseErr "Service is down"
+// @Security     AuthToken
+// @Router /auth/logout-all [post]
+func (h *AuthHandler) LogoutAll(c AuthContext) {
+	principal := c.Principal()
+	if !principal.IsUser() {
+		c.JSON(http.StatusUnauthorized, &dto.ResponseErr{
+			StatusCode: http.StatusUnauthorized,
+			Message:    "Invalid token",
+		})
+		return
+	}
+
+	userId := uint32(principal.UserID)
+
+	if _, errRes := h.service.Logout(c.UserContext(), userId); errRes != nil {
+		c.JSON(errRes.StatusCode, errRes)
+		return
+	}
+
+	if errRes := h.sessions.RevokeAll(c.UserContext(), userId); errRes != nil {
+		c.JSON(errRes.StatusCode, errRes)
+		return
+	}
+
+	h.tokenCache.EvictUser(userId)
+
+	if h.session.CookieMode() {
+		h.clearSession(c)
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+	return
+}
 
-	res, errRes := h.service.Logout(uint32(userId))
+// ListSessions is a function that get the active sessions of the user
+// @Summary Get the sessions of user account
+// @Description Return the active sessions from the last seen, the session of the request is the current one
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Success 200 {object} []dto.DeviceSession
+// @Failure 401 {object} dto.ResponseErr "Invalid token"
+// @Failure 503 {object} dto.ResponseErr "Service is down"
+// @Security     AuthToken
+// @Router /auth/sessions [get]
+func (h *AuthHandler) ListSessions(c AuthContext) {
+	principal := c.Principal()
+	if !principal.IsUser() {
+		c.JSON(http.StatusUnauthorized, &dto.ResponseErr{
+			StatusCode: http.StatusUnauthorized,
+			Message:    "Invalid token",
+		})
+		return
+	}
+
+	res, errRes := h.sessions.List(c.UserContext(), uint32(principal.UserID), principal.SessionID)
 	if errRes != nil {
 		c.JSON(errRes.StatusCode, errRes)
 		return
@@ -145,6 +469,38 @@ func (h *AuthHandler) Logout(c AuthContext) {
 	return
 }
 
+// RevokeSession is a function that revoke a session of the user
+// @Summary Revoke a session of user account
+// @Description The tokens of the session are rejected after it is revoked
+// @Param id path string true "session id"
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Success 204
+// @Failure 401 {object} dto.ResponseErr "Invalid token"
+// @Failure 404 {object} dto.ResponseErr "Session not found"
+// @Failure 503 {object} dto.ResponseErr "Service is down"
+// @Security     AuthToken
+// @Router /auth/sessions/{id} [delete]
+func (h *AuthHandler) RevokeSession(c AuthContext) {
+	principal := c.Principal()
+	if !principal.IsUser() {
+		c.JSON(http.StatusUnauthorized, &dto.ResponseErr{
+			StatusCode: http.StatusUnauthorized,
+			Message:    "Invalid token",
+		})
+		return
+	}
+
+	if errRes := h.sessions.Revoke(c.UserContext(), uint32(principal.UserID), c.SessionParam()); errRes != nil {
+		c.JSON(errRes.StatusCode, errRes)
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+	return
+}
+
 // ChangePassword is a function that change password of the user account
 // @Summary ChangePassword of user account
 // @Description Return the true if successfully
@@ -160,6 +516,15 @@ func (h *AuthHandler) Logout(c AuthContext) {
 // @Security     AuthToken
 // @Router /auth/change-password [post]
 func (h *AuthHandler) ChangePassword(c AuthContext) {
+	principal := c.Principal()
+	if !principal.IsUser() {
+		c.JSON(http.StatusUnauthorized, &dto.ResponseErr{
+			StatusCode: http.StatusUnauthorized,
+			Message:    "Invalid token",
+		})
+		return
+	}
+
 	changePassword := dto.ChangePassword{}
 	err := c.Bind(&changePassword)
 	if err != nil {
@@ -179,12 +544,39 @@ func (h *AuthHandler) ChangePassword(c AuthContext) {
 		return
 	}
 
-	res, errRes := h.service.ChangePassword(&changePassword)
+	user, errRes := h.userSrv.FindOne(c.UserContext(), principal.UserID)
+	if errRes != nil {
+		c.JSON(errRes.StatusCode, errRes)
+		return
+	}
+
+	email, errRes := h.emails.Find(c.UserContext(), uint32(principal.UserID))
+	if errRes != nil {
+		c.JSON(errRes.StatusCode, errRes)
+		return
+	}
+
+	if errors := h.validate.ValidatePassword("NewPassword", changePassword.NewPassword, email, user.Firstname, user.Lastname, user.DisplayName); errors != nil {
+		c.JSON(http.StatusBadRequest, &dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Invalid body request",
+			Data:       errors,
+		})
+		return
+	}
+
+	// the caller can only change its own password whatever the body says
+	changePassword.UserId = uint32(principal.UserID)
+
+	res, errRes := h.service.ChangePassword(c.UserContext(), &changePassword)
 	if errRes != nil {
 		c.JSON(errRes.StatusCode, errRes)
 		return
 	}
 
+	h.tokenCache.EvictUser(changePassword.UserId)
+	h.activitySrv.Record(proto.LogType_CHANGE_PASSWORD, changePassword.UserId, "Change password")
+
 	c.JSON(http.StatusNoContent, res)
 	return
 }
@@ -201,11 +593,11 @@ func (h *AuthHandler) ChangePassword(c AuthContext) {
 // @Security     AuthToken
 // @Router /auth/me [get]
 func (h *AuthHandler) Validate(c AuthContext) {
-	id := c.UserID()
+	id := c.Principal().UserID
 
 	fmt.Println(id)
 
-	res, errRes := h.userSrv.FindOne(id)
+	res, errRes := h.userSrv.FindOne(c.UserContext(), id)
 	if errRes != nil {
 		c.JSON(errRes.StatusCode, errRes)
 		return
@@ -217,20 +609,23 @@ func (h *AuthHandler) Validate(c AuthContext) {
 
 // RefreshToken is a function that redeem new credentials
 // @Summary Redeem new token
-// @Description Return the credentials if successfully
+// @Description Return the credentials if successfully, the refresh token is the credential of the route so an expired access token is not needed. In the cookie mode the refresh token of the cookie needs the csrf header
 // @Param register body dto.RedeemNewToken true "refresh token dto"
 // @Tags auth
 // @Accept json
 // @Produce json
 // @Success 201 {object} proto.Credential
+// @Success 200 {object} dto.Session "When the session is kept in cookies"
 // @Failure 400 {object} dto.ResponseErr "Invalid request body"
 // @Failure 401 {object} dto.ResponseErr "Invalid refresh token"
+// @Failure 403 {object} dto.ResponseErr "Invalid CSRF token"
+// @Failure 429 {object} dto.ResponseErr "Too many attempts"
 // @Failure 503 {object} dto.ResponseErr "Service is down"
 // @Router /auth/token [post]
 func (h *AuthHandler) RefreshToken(c AuthContext) {
 	redeemNewToken := dto.RedeemNewToken{}
 	err := c.Bind(&redeemNewToken)
-	if err != nil {
+	if err != nil && !h.session.CookieMode() {
 		c.JSON(http.StatusBadRequest, &dto.ResponseErr{
 			StatusCode: http.StatusBadRequest,
 			Message:    "Cannot parse refresh token dto",
@@ -238,6 +633,13 @@ func (h *AuthHandler) RefreshToken(c AuthContext) {
 		return
 	}
 
+	// the browser does not send a body in the cookie mode, the refresh token comes from its cookie
+	fromCookie := false
+	if redeemNewToken.RefreshToken == "" && h.session.CookieMode() {
+		redeemNewToken.RefreshToken = c.GetCookie(h.session.RefreshCookie)
+		fromCookie = true
+	}
+
 	if errors := h.validate.Validate(redeemNewToken); errors != nil {
 		c.JSON(http.StatusBadRequest, &dto.ResponseErr{
 			StatusCode: http.StatusBadRequest,
@@ -247,12 +649,138 @@ func (h *AuthHandler) RefreshToken(c AuthContext) {
 		return
 	}
 
-	res, errRes := h.service.RefreshToken(redeemNewToken.RefreshToken)
+	// the route is public so the guard does not check the csrf token of the cookie that the browser sends on its own
+	if fromCookie && !h.validCsrf(c) {
+		c.JSON(http.StatusForbidden, &dto.ResponseErr{
+			StatusCode: http.StatusForbidden,
+			Message:    "Invalid CSRF token",
+		})
+		return
+	}
+
+	if h.lockedOut(c, constant.LockoutRefreshIP, c.IP()) {
+		return
+	}
+
+	if errRes := h.sessions.CheckRefresh(c.UserContext(), redeemNewToken.RefreshToken); errRes != nil {
+		c.JSON(errRes.StatusCode, errRes)
+		return
+	}
+
+	res, errRes := h.service.RefreshToken(c.UserContext(), redeemNewToken.RefreshToken)
 	if errRes != nil {
+		if isCredentialErr(errRes) {
+			h.lockoutSrv.Fail(c.UserContext(), constant.LockoutRefreshIP, c.IP())
+		}
+
 		c.JSON(errRes.StatusCode, errRes)
 		return
 	}
 
+	h.sessions.Rotate(c.UserContext(), redeemNewToken.RefreshToken, res, c.Device())
+
+	if h.session.CookieMode() {
+		h.setSession(c, res)
+		return
+	}
+
 	c.JSON(http.StatusOK, res)
 	return
 }
+
+// TokenCacheStats is a function that return the counters of the token validation cache
+// @Summary Get the token validation cache counters
+// @Description Return the hit, miss and size of the cache
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Success 200 {object} dto.TokenCacheStats
+// @Failure 401 {object} dto.ResponseErr "Invalid token"
+// @Failure 403 {object} dto.ResponseErr "Insufficient permission"
+// @Security     AuthToken
+// @Router /auth/token-cache [get]
+func (h *AuthHandler) TokenCacheStats(c AuthContext) {
+	c.JSON(http.StatusOK, h.tokenCache.Stats())
+	return
+}
+
+// lockedOut responds 429 with the seconds to wait in the Retry-After header when the key is locked out
+func (h *AuthHandler) lockedOut(c AuthContext, scope string, key string) bool {
+	wait := h.lockoutSrv.Check(c.UserContext(), scope, key)
+	if wait <= 0 {
+		return false
+	}
+
+	c.SetResponseHeader("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+	c.JSON(http.StatusTooManyRequests, &dto.ResponseErr{
+		StatusCode: http.StatusTooManyRequests,
+		Message:    "Too many attempts, try again later",
+		Data:       nil,
+	})
+
+	return true
+}
+
+// isCredentialErr tells the wrong credentials apart from the errors that are not the fault of the client (e.g. the service is down)
+func isCredentialErr(errRes *dto.ResponseErr) bool {
+	return errRes.StatusCode == http.StatusUnauthorized || errRes.StatusCode == http.StatusNotFound
+}
+
+// setSession keeps the tokens in HttpOnly cookies and gives the client a new CSRF token that it has to send back in the CSRF header
+func (h *AuthHandler) setSession(c AuthContext, credential *proto.Credential) {
+	writeSession(c, h.session, credential)
+}
+
+func (h *AuthHandler) validCsrf(c AuthContext) bool {
+	cookie := c.GetCookie(h.session.CsrfCookie)
+	header := c.RequestHeader(h.session.CsrfHeader)
+
+	return cookie != "" && subtle.ConstantTimeCompare([]byte(cookie), []byte(header)) == 1
+}
+
+func (h *AuthHandler) clearSession(c AuthContext) {
+	c.SetCookie(sessionCookie(h.session, h.session.AccessCookie, "", "/", -1, true))
+	c.SetCookie(sessionCookie(h.session, h.session.RefreshCookie, "", "/auth/token", -1, true))
+	c.SetCookie(sessionCookie(h.session, h.session.CsrfCookie, "", "/", -1, false))
+}
+
+type SessionContext interface {
+	JSON(int, interface{})
+	SetCookie(*dto.Cookie)
+}
+
+func writeSession(c SessionContext, session config.Session, credential *proto.Credential) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		c.JSON(http.StatusInternalServerError, &dto.ResponseErr{
+			StatusCode: http.StatusInternalServerError,
+			Message:    "Cannot create the session",
+		})
+		return
+	}
+	csrfToken := base64.RawURLEncoding.EncodeToString(b)
+
+	refreshMaxAge := int(session.RefreshMaxAge.Seconds())
+
+	c.SetCookie(sessionCookie(session, session.AccessCookie, credential.AccessToken, "/", int(credential.ExpiresIn), true))
+	c.SetCookie(sessionCookie(session, session.RefreshCookie, credential.RefreshToken, "/auth/token", refreshMaxAge, true))
+	c.SetCookie(sessionCookie(session, session.CsrfCookie, csrfToken, "/", refreshMaxAge, false))
+
+	c.JSON(http.StatusOK, &dto.Session{
+		ExpiresIn: credential.ExpiresIn,
+		CsrfToken: csrfToken,
+	})
+}
+
+func sessionCookie(session config.Session, name string, value string, path string, maxAge int, httpOnly bool) *dto.Cookie {
+	return &dto.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     path,
+		Domain:   session.Domain,
+		MaxAge:   maxAge,
+		Secure:   session.Secure,
+		HttpOnly: httpOnly,
+		SameSite: session.SameSite,
+	}
+}
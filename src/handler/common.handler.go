@@ -0,0 +1,27 @@
+package handler
+
+import (
+	"context"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/dto"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/proto"
+)
+
+type ContactService interface {
+	FindOne(context.Context, int32) (*proto.Contact, *dto.ResponseErr)
+	Create(context.Context, *dto.ContactDto) (*proto.Contact, *dto.ResponseErr)
+	Update(context.Context, int32, *dto.ContactDto) (*proto.Contact, *dto.ResponseErr)
+	Delete(context.Context, int32) (*proto.Contact, *dto.ResponseErr)
+}
+
+type LocationService interface {
+	FindOne(context.Context, int32) (*proto.Location, *dto.ResponseErr)
+	Create(context.Context, *dto.LocationDto) (*proto.Location, *dto.ResponseErr)
+	Update(context.Context, int32, *dto.LocationDto) (*proto.Location, *dto.ResponseErr)
+	Delete(context.Context, int32) (*proto.Location, *dto.ResponseErr)
+}
+
+type ActivityService interface {
+	Record(proto.LogType, uint32, string)
+	FindByUser(uint32) []*proto.Log
+	Paginate([]*proto.Log, *dto.ActivityQueryParams) (*dto.LogPagination, *dto.ResponseErr)
+}
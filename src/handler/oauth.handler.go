@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"context"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/config"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/dto"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/proto"
+	"net/http"
+)
+
+type OAuthHandler struct {
+	service     OAuthService
+	authSrv     AuthService
+	activitySrv ActivityService
+	twoFactor   TwoFactorChallenger
+	sessions    SessionRecorder
+	session     config.Session
+}
+
+func NewOAuthHandler(s OAuthService, a AuthService, activitySrv ActivityService, f TwoFactorChallenger, sessions SessionRecorder, session config.Session) *OAuthHandler {
+	return &OAuthHandler{
+		service:     s,
+		authSrv:     a,
+		activitySrv: activitySrv,
+		twoFactor:   f,
+		sessions:    sessions,
+		session:     session,
+	}
+}
+
+type OAuthContext interface {
+	UserContext() context.Context
+	Provider() string
+	OAuthCallbackQuery(*dto.OAuthCallback) error
+	Redirect(string, ...int) error
+	JSON(int, interface{})
+	SetCookie(*dto.Cookie)
+	Device() *dto.Device
+}
+
+type OAuthService interface {
+	AuthorizationUrl(string) (string, *dto.ResponseErr)
+	Exchange(string, *dto.OAuthCallback) (*dto.OAuthIdentity, *dto.ResponseErr)
+	SignIn(context.Context, *dto.OAuthIdentity) (*proto.Credential, *dto.ResponseErr)
+}
+
+// Login is a function that redirect the user to the login page of the provider
+// @Summary Login with an OpenID Connect provider
+// @Description Redirect to the provider, the provider redirects back to the callback
+// @Param provider path string true "provider name"
+// @Tags auth
+// @Success 302
+// @Failure 404 {object} dto.ResponseErr "Unknown provider"
+// @Failure 503 {object} dto.ResponseErr "Cannot reach the provider"
+// @Router /auth/oauth/{provider}/login [get]
+func (h *OAuthHandler) Login(c OAuthContext) {
+	url, errRes := h.service.AuthorizationUrl(c.Provider())
+	if errRes != nil {
+		c.JSON(errRes.StatusCode, errRes)
+		return
+	}
+
+	_ = c.Redirect(url, http.StatusFound)
+	return
+}
+
+// Callback is a function that login the user that the provider redirected back
+// @Summary Finish the login with an OpenID Connect provider
+// @Description Return the credentials if successfully, the account is created on the first login
+// @Param provider path string true "provider name"
+// @Param code query string true "authorization code"
+// @Param state query string true "state of the login"
+// @Tags auth
+// @Produce json
+// @Success 201 {object} proto.Credential
+// @Success 200 {object} dto.Session "When the session is kept in cookies"
+// @Success 202 {object} dto.TwoFactorChallenge "When the user has enabled the two-factor authentication, the challenge is verified at /auth/2fa/verify"
+// @Failure 400 {object} dto.ResponseErr "Invalid callback"
+// @Failure 401 {object} dto.ResponseErr "Invalid ID token"
+// @Failure 404 {object} dto.ResponseErr "Unknown provider"
+// @Failure 409 {object} dto.ResponseErr "Email is already registered with a password"
+// @Failure 503 {object} dto.ResponseErr "Service is down"
+// @Router /auth/oauth/{provider}/callback [get]
+func (h *OAuthHandler) Callback(c OAuthContext) {
+	query := dto.OAuthCallback{}
+	if err := c.OAuthCallbackQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, &dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Invalid callback",
+		})
+		return
+	}
+
+	identity, errRes := h.service.Exchange(c.Provider(), &query)
+	if errRes != nil {
+		c.JSON(errRes.StatusCode, errRes)
+		return
+	}
+
+	res, errRes := h.service.SignIn(c.UserContext(), identity)
+	if errRes != nil {
+		c.JSON(errRes.StatusCode, errRes)
+		return
+	}
+
+	// the provider proves the email but not the second factor, so the login is challenged like the login with a password
+	userId, errRes := h.authSrv.Validate(c.UserContext(), res.AccessToken)
+	if errRes != nil {
+		c.JSON(errRes.StatusCode, errRes)
+		return
+	}
+
+	challenge, errRes := h.twoFactor.Challenge(c.UserContext(), userId, res)
+	if errRes != nil {
+		c.JSON(errRes.StatusCode, errRes)
+		return
+	}
+
+	if challenge != nil {
+		c.JSON(http.StatusAccepted, challenge)
+		return
+	}
+
+	h.activitySrv.Record(proto.LogType_LOGIN, userId, "Login with "+identity.Provider)
+	h.sessions.Record(c.UserContext(), userId, res, c.Device())
+
+	if h.session.CookieMode() {
+		writeSession(c, h.session, res)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+	return
+}
@@ -1,6 +1,9 @@
 package handler
 
 import (
+	"context"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/common"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/constant"
 	"github.com/samithiwat/samithiwat-backend-gateway/src/dto"
 	"github.com/samithiwat/samithiwat-backend-gateway/src/proto"
 	validate "github.com/samithiwat/samithiwat-backend-gateway/src/validator"
@@ -8,30 +11,48 @@ import (
 )
 
 type OrganizationHandler struct {
-	service  OrganizationService
-	validate *validate.DtoValidator
+	service     OrganizationService
+	userSrv     UserService
+	roleSrv     RoleService
+	contactSrv  ContactService
+	locationSrv LocationService
+	activitySrv ActivityService
+	validate    *validate.DtoValidator
 }
 
-func NewOrganizationHandler(service OrganizationService, validate *validate.DtoValidator) *OrganizationHandler {
+func NewOrganizationHandler(service OrganizationService, userSrv UserService, roleSrv RoleService, contactSrv ContactService, locationSrv LocationService, activitySrv ActivityService, validate *validate.DtoValidator) *OrganizationHandler {
 	return &OrganizationHandler{
-		service:  service,
-		validate: validate,
+		service:     service,
+		userSrv:     userSrv,
+		roleSrv:     roleSrv,
+		contactSrv:  contactSrv,
+		locationSrv: locationSrv,
+		activitySrv: activitySrv,
+		validate:    validate,
 	}
 }
 
 type OrganizationContext interface {
+	UserContext() context.Context
 	Bind(interface{}) error
 	JSON(int, interface{})
 	ID() (int32, error)
 	PaginationQueryParam(*dto.PaginationQueryParams) error
+	IDsQueryParam() string
+	MemberID() (int32, error)
+	ActivityQueryParam(*dto.ActivityQueryParams) error
 }
 
 type OrganizationService interface {
-	FindAll(*dto.PaginationQueryParams) (*proto.OrganizationPagination, *dto.ResponseErr)
-	FindOne(int32) (*proto.Organization, *dto.ResponseErr)
-	Create(*dto.OrganizationDto) (*proto.Organization, *dto.ResponseErr)
-	Update(int32, *dto.OrganizationDto) (*proto.Organization, *dto.ResponseErr)
-	Delete(int32) (*proto.Organization, *dto.ResponseErr)
+	FindAll(context.Context, *dto.PaginationQueryParams) (*proto.OrganizationPagination, *dto.ResponseErr)
+	FindOne(context.Context, int32) (*proto.Organization, *dto.ResponseErr)
+	FindMulti(context.Context, []int32) ([]*proto.Organization, *dto.ResponseErr)
+	Create(context.Context, *dto.OrganizationDto) (*proto.Organization, *dto.ResponseErr)
+	Update(context.Context, int32, *dto.OrganizationDto) (*proto.Organization, *dto.ResponseErr)
+	Delete(context.Context, int32) (*proto.Organization, *dto.ResponseErr)
+	FindMembers(context.Context, int32, *dto.PaginationQueryParams) (*proto.UserPagination, *dto.ResponseErr)
+	AddMember(context.Context, int32, *proto.User) (*proto.Organization, *dto.ResponseErr)
+	RemoveMember(context.Context, int32, int32) (*proto.Organization, *dto.ResponseErr)
 }
 
 // FindAll is a function that get all organizations in database
@@ -39,6 +60,7 @@ type OrganizationService interface {
 // @Description Return the arrays of organization dto if successfully
 // @Param limit query int false "Limit"
 // @Param page query int false "Page"
+// @Param ids query string false "Comma separated ids, return only these organizations in the given order"
 // @Tags organization
 // @Accept json
 // @Produce json
@@ -47,6 +69,11 @@ type OrganizationService interface {
 // @Failure 503 {object} dto.ResponseErr "Service is down"
 // @Router /organization [get]
 func (h *OrganizationHandler) FindAll(c OrganizationContext) {
+	if ids := c.IDsQueryParam(); ids != "" {
+		h.findMulti(c, ids)
+		return
+	}
+
 	query := dto.PaginationQueryParams{}
 
 	err := c.PaginationQueryParam(&query)
@@ -58,7 +85,35 @@ func (h *OrganizationHandler) FindAll(c OrganizationContext) {
 		return
 	}
 
-	organizations, errRes := h.service.FindAll(&query)
+	organizations, errRes := h.service.FindAll(c.UserContext(), &query)
+	if errRes != nil {
+		c.JSON(errRes.StatusCode, errRes)
+		return
+	}
+
+	c.JSON(http.StatusOK, organizations)
+	return
+}
+
+func (h *OrganizationHandler) findMulti(c OrganizationContext, raw string) {
+	ids, err := common.ParseIDs(raw, constant.MaxBatchSize)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Invalid ids query param",
+			Data: []*dto.BadReqErrResponse{
+				{
+					Message:     err.Error(),
+					FailedField: "ids",
+					Tag:         "ids",
+					Value:       raw,
+				},
+			},
+		})
+		return
+	}
+
+	organizations, errRes := h.service.FindMulti(c.UserContext(), ids)
 	if errRes != nil {
 		c.JSON(errRes.StatusCode, errRes)
 		return
@@ -91,7 +146,7 @@ func (h *OrganizationHandler) FindOne(c OrganizationContext) {
 		return
 	}
 
-	organization, errRes := h.service.FindOne(id)
+	organization, errRes := h.service.FindOne(c.UserContext(), id)
 	if errRes != nil {
 		c.JSON(errRes.StatusCode, errRes)
 		return
@@ -111,6 +166,7 @@ func (h *OrganizationHandler) FindOne(c OrganizationContext) {
 // @Success 201 {object} proto.Organization
 // @Failure 400 {object} dto.ResponseErr "Invalid ID"
 // @Failure 404 {object} dto.ResponseErr "Not found organization"
+// @Failure 403 {object} dto.ResponseErr Insufficient permission
 // @Failure 503 {object} dto.ResponseErr "Service is down"
 // @Security     AuthToken
 // @Router /organization [post]
@@ -134,7 +190,7 @@ func (h *OrganizationHandler) Create(c OrganizationContext) {
 		return
 	}
 
-	organization, errRes := h.service.Create(&organizationDto)
+	organization, errRes := h.service.Create(c.UserContext(), &organizationDto)
 	if errRes != nil {
 		c.JSON(errRes.StatusCode, errRes)
 		return
@@ -155,6 +211,7 @@ func (h *OrganizationHandler) Create(c OrganizationContext) {
 // @Success 200 {object} proto.Organization
 // @Failure 400 {object} dto.ResponseErr "Invalid ID"
 // @Failure 404 {object} dto.ResponseErr "Not found organization"
+// @Failure 403 {object} dto.ResponseErr Insufficient permission
 // @Failure 503 {object} dto.ResponseErr "Service is down"
 // @Security     AuthToken
 // @Router /organization/{id} [patch]
@@ -187,7 +244,7 @@ func (h *OrganizationHandler) Update(c OrganizationContext) {
 		return
 	}
 
-	organization, errRes := h.service.Update(id, &organizationDto)
+	organization, errRes := h.service.Update(c.UserContext(), id, &organizationDto)
 	if errRes != nil {
 		c.JSON(errRes.StatusCode, errRes)
 		return
@@ -207,6 +264,7 @@ func (h *OrganizationHandler) Update(c OrganizationContext) {
 // @Success 200 {object} proto.Organization
 // @Failure 400 {object} dto.ResponseErr "Invalid ID"
 // @Failure 404 {object} dto.ResponseErr "Not found organization"
+// @Failure 403 {object} dto.ResponseErr Insufficient permission
 // @Failure 503 {object} dto.ResponseErr "Service is down"
 // @Security     AuthToken
 // @Router /organization/{id} [delete]
@@ -221,7 +279,7 @@ func (h *OrganizationHandler) Delete(c OrganizationContext) {
 		return
 	}
 
-	organization, errRes := h.service.Delete(id)
+	organization, errRes := h.service.Delete(c.UserContext(), id)
 	if errRes != nil {
 		c.JSON(errRes.StatusCode, errRes)
 		return
@@ -230,3 +288,578 @@ func (h *OrganizationHandler) Delete(c OrganizationContext) {
 	c.JSON(http.StatusOK, organization)
 	return
 }
+
+// FindContact is a function that get the contact of the organization
+// @Summary Get the contact of the organization
+// @Description Return the contact dto if successfully
+// @Param id path int true "id"
+// @Tags organization
+// @Accept json
+// @Produce json
+// @Success 200 {object} proto.Contact
+// @Failure 400 {object} dto.ResponseErr "Invalid ID"
+// @Failure 404 {object} dto.ResponseErr "Not found organization or contact"
+// @Failure 503 {object} dto.ResponseErr "Service is down"
+// @Router /organization/{id}/contact [get]
+func (h *OrganizationHandler) FindContact(c OrganizationContext) {
+	id, err := c.ID()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Invalid ID",
+		})
+		return
+	}
+
+	org, errRes := h.service.FindOne(c.UserContext(), id)
+	if errRes != nil {
+		c.JSON(errRes.StatusCode, errRes)
+		return
+	}
+
+	if org.Contact == nil {
+		c.JSON(http.StatusNotFound, &dto.ResponseErr{
+			StatusCode: http.StatusNotFound,
+			Message:    "Not found contact",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, org.Contact)
+	return
+}
+
+// UpdateContact is a function that create or update the contact of the organization
+// @Summary Create or update the contact of the organization
+// @Description Return the contact dto if successfully
+// @Param id path int true "id"
+// @Param contact body dto.ContactDto true "contact dto"
+// @Tags organization
+// @Accept json
+// @Produce json
+// @Success 200 {object} proto.Contact
+// @Failure 400 {object} dto.ResponseErr "Invalid ID"
+// @Failure 404 {object} dto.ResponseErr "Not found organization"
+// @Failure 403 {object} dto.ResponseErr Insufficient permission
+// @Failure 503 {object} dto.ResponseErr "Service is down"
+// @Security     AuthToken
+// @Router /organization/{id}/contact [put]
+func (h *OrganizationHandler) UpdateContact(c OrganizationContext) {
+	contactDto := dto.ContactDto{}
+	err := c.Bind(&contactDto)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Cannot parse contact dto",
+		})
+		return
+	}
+
+	if errors := h.validate.Validate(contactDto); errors != nil {
+		c.JSON(http.StatusBadRequest, &dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Invalid body request",
+			Data:       errors,
+		})
+		return
+	}
+
+	id, err := c.ID()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Invalid ID",
+		})
+		return
+	}
+
+	org, errRes := h.service.FindOne(c.UserContext(), id)
+	if errRes != nil {
+		c.JSON(errRes.StatusCode, errRes)
+		return
+	}
+
+	if org.Contact != nil && org.Contact.Id > 0 {
+		contact, errRes := h.contactSrv.Update(c.UserContext(), int32(org.Contact.Id), &contactDto)
+		if errRes != nil {
+			c.JSON(errRes.StatusCode, errRes)
+			return
+		}
+
+		c.JSON(http.StatusOK, contact)
+		return
+	}
+
+	org, errRes = h.service.Update(c.UserContext(), id, &dto.OrganizationDto{
+		Name:        org.Name,
+		Email:       org.Email,
+		Description: org.Description,
+		Contact:     &contactDto,
+	})
+	if errRes != nil {
+		c.JSON(errRes.StatusCode, errRes)
+		return
+	}
+
+	c.JSON(http.StatusOK, org.Contact)
+	return
+}
+
+// FindLocation is a function that get the location of the organization
+// @Summary Get the location of the organization
+// @Description Return the location dto if successfully
+// @Param id path int true "id"
+// @Tags organization
+// @Accept json
+// @Produce json
+// @Success 200 {object} proto.Location
+// @Failure 400 {object} dto.ResponseErr "Invalid ID"
+// @Failure 404 {object} dto.ResponseErr "Not found organization or location"
+// @Failure 503 {object} dto.ResponseErr "Service is down"
+// @Router /organization/{id}/location [get]
+func (h *OrganizationHandler) FindLocation(c OrganizationContext) {
+	id, err := c.ID()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Invalid ID",
+		})
+		return
+	}
+
+	org, errRes := h.service.FindOne(c.UserContext(), id)
+	if errRes != nil {
+		c.JSON(errRes.StatusCode, errRes)
+		return
+	}
+
+	if org.Location == nil {
+		c.JSON(http.StatusNotFound, &dto.ResponseErr{
+			StatusCode: http.StatusNotFound,
+			Message:    "Not found location",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, org.Location)
+	return
+}
+
+// UpdateLocation is a function that create or update the location of the organization
+// @Summary Create or update the location of the organization
+// @Description Return the location dto if successfully
+// @Param id path int true "id"
+// @Param location body dto.LocationDto true "location dto"
+// @Tags organization
+// @Accept json
+// @Produce json
+// @Success 200 {object} proto.Location
+// @Failure 400 {object} dto.ResponseErr "Invalid ID"
+// @Failure 404 {object} dto.ResponseErr "Not found organization"
+// @Failure 403 {object} dto.ResponseErr Insufficient permission
+// @Failure 503 {object} dto.ResponseErr "Service is down"
+// @Security     AuthToken
+// @Router /organization/{id}/location [put]
+func (h *OrganizationHandler) UpdateLocation(c OrganizationContext) {
+	locationDto := dto.LocationDto{}
+	err := c.Bind(&locationDto)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Cannot parse location dto",
+		})
+		return
+	}
+
+	if errors := h.validate.Validate(locationDto); errors != nil {
+		c.JSON(http.StatusBadRequest, &dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Invalid body request",
+			Data:       errors,
+		})
+		return
+	}
+
+	id, err := c.ID()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Invalid ID",
+		})
+		return
+	}
+
+	org, errRes := h.service.FindOne(c.UserContext(), id)
+	if errRes != nil {
+		c.JSON(errRes.StatusCode, errRes)
+		return
+	}
+
+	if org.Location != nil && org.Location.Id > 0 {
+		location, errRes := h.locationSrv.Update(c.UserContext(), int32(org.Location.Id), &locationDto)
+		if errRes != nil {
+			c.JSON(errRes.StatusCode, errRes)
+			return
+		}
+
+		c.JSON(http.StatusOK, location)
+		return
+	}
+
+	org, errRes = h.service.Update(c.UserContext(), id, &dto.OrganizationDto{
+		Name:        org.Name,
+		Email:       org.Email,
+		Description: org.Description,
+		Location:    &locationDto,
+	})
+	if errRes != nil {
+		c.JSON(errRes.StatusCode, errRes)
+		return
+	}
+
+	c.JSON(http.StatusOK, org.Location)
+	return
+}
+
+// FindMembers is a function that get the members of the organization
+// @Summary Get the members of the organization
+// @Description Return the pagination of user dto if successfully
+// @Param id path int true "id"
+// @Param limit query int false "Limit"
+// @Param page query int false "Page"
+// @Tags organization
+// @Accept json
+// @Produce json
+// @Success 200 {object} proto.UserPagination
+// @Failure 400 {object} dto.ResponseErr "Invalid ID"
+// @Failure 404 {object} dto.ResponseErr "Not found organization"
+// @Failure 503 {object} dto.ResponseErr "Service is down"
+// @Router /organization/{id}/member [get]
+func (h *OrganizationHandler) FindMembers(c OrganizationContext) {
+	id, err := c.ID()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Invalid ID",
+		})
+		return
+	}
+
+	query := dto.PaginationQueryParams{}
+
+	err = c.PaginationQueryParam(&query)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &dto.ResponseErr{
+			StatusCode: http.StatusInternalServerError,
+			Message:    "Cannot parse query param",
+		})
+		return
+	}
+
+	members, errRes := h.service.FindMembers(c.UserContext(), id, &query)
+	if errRes != nil {
+		c.JSON(errRes.StatusCode, errRes)
+		return
+	}
+
+	c.JSON(http.StatusOK, members)
+	return
+}
+
+// AddMember is a function that add the user to the organization
+// @Summary Add the member to the organization
+// @Description Return the organization dto if successfully
+// @Param id path int true "id"
+// @Param member body dto.MemberDto true "member dto"
+// @Tags organization
+// @Accept json
+// @Produce json
+// @Success 200 {object} proto.Organization
+// @Failure 400 {object} dto.ResponseErr "Invalid ID"
+// @Failure 404 {object} dto.ResponseErr "Not found organization or user"
+// @Failure 403 {object} dto.ResponseErr Insufficient permission
+// @Failure 503 {object} dto.ResponseErr "Service is down"
+// @Security     AuthToken
+// @Router /organization/{id}/member [post]
+func (h *OrganizationHandler) AddMember(c OrganizationContext) {
+	memberDto := dto.MemberDto{}
+	err := c.Bind(&memberDto)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Cannot parse member dto",
+		})
+		return
+	}
+
+	if errors := h.validate.Validate(memberDto); errors != nil {
+		c.JSON(http.StatusBadRequest, &dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Invalid body request",
+			Data:       errors,
+		})
+		return
+	}
+
+	id, err := c.ID()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Invalid ID",
+		})
+		return
+	}
+
+	user, errRes := h.userSrv.FindOne(c.UserContext(), memberDto.UserID)
+	if errRes != nil {
+		c.JSON(errRes.StatusCode, errRes)
+		return
+	}
+
+	organization, errRes := h.service.AddMember(c.UserContext(), id, user)
+	if errRes != nil {
+		c.JSON(errRes.StatusCode, errRes)
+		return
+	}
+
+	c.JSON(http.StatusOK, organization)
+	return
+}
+
+// RemoveMember is a function that remove the user from the organization
+// @Summary Remove the member from the organization
+// @Description Return the organization dto if successfully
+// @Param id path int true "id"
+// @Param userId path int true "user id"
+// @Tags organization
+// @Accept json
+// @Produce json
+// @Success 200 {object} proto.Organization
+// @Failure 400 {object} dto.ResponseErr "Invalid ID"
+// @Failure 404 {object} dto.ResponseErr "Not found organization or member"
+// @Failure 403 {object} dto.ResponseErr Insufficient permission
+// @Failure 503 {object} dto.ResponseErr "Service is down"
+// @Security     AuthToken
+// @Router /organization/{id}/member/{userId} [delete]
+func (h *OrganizationHandler) RemoveMember(c OrganizationContext) {
+	id, err := c.ID()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Invalid ID",
+		})
+		return
+	}
+
+	userId, err := c.MemberID()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Invalid user ID",
+		})
+		return
+	}
+
+	organization, errRes := h.service.RemoveMember(c.UserContext(), id, userId)
+	if errRes != nil {
+		c.JSON(errRes.StatusCode, errRes)
+		return
+	}
+
+	c.JSON(http.StatusOK, organization)
+	return
+}
+
+// AssignMemberRole is a function that set the organization role of the member
+// @Summary Assign the organization role to the member
+// @Description Return the role dto if successfully, the member loses the other roles of the organization
+// @Param id path int true "id"
+// @Param userId path int true "user id"
+// @Param role body dto.MemberRoleDto true "member role dto"
+// @Tags organization
+// @Accept json
+// @Produce json
+// @Success 200 {object} proto.Role
+// @Failure 400 {object} dto.ResponseErr "Invalid ID"
+// @Failure 404 {object} dto.ResponseErr "Not found organization, member or role"
+// @Failure 403 {object} dto.ResponseErr Insufficient permission
+// @Failure 503 {object} dto.ResponseErr "Service is down"
+// @Security     AuthToken
+// @Router /organization/{id}/member/{userId}/role [put]
+func (h *OrganizationHandler) AssignMemberRole(c OrganizationContext) {
+	roleDto := dto.MemberRoleDto{}
+	err := c.Bind(&roleDto)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Cannot parse member role dto",
+		})
+		return
+	}
+
+	if errors := h.validate.Validate(roleDto); errors != nil {
+		c.JSON(http.StatusBadRequest, &dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Invalid body request",
+			Data:       errors,
+		})
+		return
+	}
+
+	id, err := c.ID()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Invalid ID",
+		})
+		return
+	}
+
+	userId, err := c.MemberID()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Invalid user ID",
+		})
+		return
+	}
+
+	organization, errRes := h.service.FindOne(c.UserContext(), id)
+	if errRes != nil {
+		c.JSON(errRes.StatusCode, errRes)
+		return
+	}
+
+	var member *proto.User
+	for _, m := range organization.Members {
+		if int32(m.Id) == userId {
+			member = m
+			break
+		}
+	}
+
+	if member == nil {
+		c.JSON(http.StatusNotFound, &dto.ResponseErr{
+			StatusCode: http.StatusNotFound,
+			Message:    "The organization does not have this member",
+		})
+		return
+	}
+
+	var scope []int32
+	found := false
+	for _, role := range organization.Roles {
+		scope = append(scope, int32(role.Id))
+		if int32(role.Id) == roleDto.RoleID {
+			found = true
+		}
+	}
+
+	if !found {
+		c.JSON(http.StatusNotFound, &dto.ResponseErr{
+			StatusCode: http.StatusNotFound,
+			Message:    "The organization does not have this role",
+		})
+		return
+	}
+
+	role, errRes := h.roleSrv.AssignUser(c.UserContext(), scope, roleDto.RoleID, member)
+	if errRes != nil {
+		c.JSON(errRes.StatusCode, errRes)
+		return
+	}
+
+	c.JSON(http.StatusOK, role)
+	return
+}
+
+// FindTeams is a function that get the teams of the organization
+// @Summary Get the teams of the organization
+// @Description Return the arrays of team dto if successfully
+// @Param id path int true "id"
+// @Tags organization
+// @Accept json
+// @Produce json
+// @Success 200 {object} []proto.Team
+// @Failure 400 {object} dto.ResponseErr "Invalid ID"
+// @Failure 404 {object} dto.ResponseErr "Not found organization"
+// @Failure 503 {object} dto.ResponseErr "Service is down"
+// @Router /organization/{id}/teams [get]
+func (h *OrganizationHandler) FindTeams(c OrganizationContext) {
+	id, err := c.ID()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Invalid ID",
+		})
+		return
+	}
+
+	organization, errRes := h.service.FindOne(c.UserContext(), id)
+	if errRes != nil {
+		c.JSON(errRes.StatusCode, errRes)
+		return
+	}
+
+	teams := organization.Teams
+	if teams == nil {
+		teams = []*proto.Team{}
+	}
+
+	c.JSON(http.StatusOK, teams)
+	return
+}
+
+// FindActivity is a function that get the activity logs of the organization
+// @Summary Get the activity logs of the organization
+// @Description Return the page of log dto if successfully, the newest first
+// @Param id path int true "id"
+// @Param limit query int false "Limit"
+// @Param page query int false "Page"
+// @Param type query string false "Log type" Enums(POST, EDIT, LOGIN, CHANGE_PASSWORD, BOOKMARK)
+// @Tags organization
+// @Accept json
+// @Produce json
+// @Success 200 {object} dto.LogPagination
+// @Failure 400 {object} dto.ResponseErr "Invalid ID or log type"
+// @Failure 401 {object} dto.ResponseErr "Invalid token"
+// @Failure 404 {object} dto.ResponseErr "Not found organization"
+// @Failure 503 {object} dto.ResponseErr "Service is down"
+// @Security     AuthToken
+// @Router /organization/{id}/activity [get]
+func (h *OrganizationHandler) FindActivity(c OrganizationContext) {
+	id, err := c.ID()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Invalid ID",
+		})
+		return
+	}
+
+	query := dto.ActivityQueryParams{}
+
+	err = c.ActivityQueryParam(&query)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &dto.ResponseErr{
+			StatusCode: http.StatusInternalServerError,
+			Message:    "Cannot parse query param",
+		})
+		return
+	}
+
+	organization, errRes := h.service.FindOne(c.UserContext(), id)
+	if errRes != nil {
+		c.JSON(errRes.StatusCode, errRes)
+		return
+	}
+
+	res, errRes := h.activitySrv.Paginate(organization.Logs, &query)
+	if errRes != nil {
+		c.JSON(errRes.StatusCode, errRes)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+	return
+}
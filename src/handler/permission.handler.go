@@ -0,0 +1,270 @@
+package handler
+
+import (
+	"context"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/dto"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/proto"
+	validate "github.com/samithiwat/samithiwat-backend-gateway/src/validator"
+	"net/http"
+)
+
+type PermissionHandler struct {
+	service  PermissionService
+	validate *validate.DtoValidator
+}
+
+func NewPermissionHandler(service PermissionService, validate *validate.DtoValidator) *PermissionHandler {
+	return &PermissionHandler{
+		service:  service,
+		validate: validate,
+	}
+}
+
+type PermissionContext interface {
+	Bind(interface{}) error
+	JSON(int, interface{})
+	ID() (int32, error)
+	PaginationQueryParam(*dto.PaginationQueryParams) error
+	UserContext() context.Context
+}
+
+type PermissionService interface {
+	FindAll(context.Context, *dto.PaginationQueryParams) (*proto.PermissionPagination, *dto.ResponseErr)
+	FindOne(context.Context, int32) (*proto.Permission, *dto.ResponseErr)
+	FindByCode(context.Context, string) (*proto.Permission, *dto.ResponseErr)
+	Create(context.Context, *dto.PermissionDto) (*proto.Permission, *dto.ResponseErr)
+	Update(context.Context, int32, *dto.PermissionDto) (*proto.Permission, *dto.ResponseErr)
+	Delete(context.Context, int32) (*proto.Permission, *dto.ResponseErr)
+}
+
+// FindAll is a function that get all permissions in database
+// @Summary Get all permissions
+// @Description Return the arrays of permission dto if successfully
+// @Param limit query int false "Limit"
+// @Param page query int false "Page"
+// @Tags permission
+// @Accept json
+// @Produce json
+// @Success 200 {object} proto.Permission
+// @Failure 400 {object} dto.ResponseErr "Invalid query param"
+// @Failure 503 {object} dto.ResponseErr "Service is down"
+// @Security     AuthToken
+// @Router /permission [get]
+func (h *PermissionHandler) FindAll(c PermissionContext) {
+	query := dto.PaginationQueryParams{}
+
+	err := c.PaginationQueryParam(&query)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &dto.ResponseErr{
+			StatusCode: http.StatusInternalServerError,
+			Message:    "Cannot parse query param",
+		})
+		return
+	}
+
+	permissions, errRes := h.service.FindAll(c.UserContext(), &query)
+	if errRes != nil {
+		c.JSON(errRes.StatusCode, errRes)
+		return
+	}
+
+	c.JSON(http.StatusOK, permissions)
+	return
+}
+
+// FindOne is a function that get the specific permissions with id
+// @Summary Get specific permission with id
+// @Description Return the permission dto if successfully
+// @Param id path int true "id"
+// @Tags permission
+// @Accept json
+// @Produce json
+// @Success 200 {object} proto.Permission
+// @Failure 400 {object} dto.ResponseErr "Invalid ID"
+// @Failure 404 {object} dto.ResponseErr "Not found permission"
+// @Failure 503 {object} dto.ResponseErr "Service is down"
+// @Security     AuthToken
+// @Router /permission/{id} [get]
+func (h *PermissionHandler) FindOne(c PermissionContext) {
+	id, err := c.ID()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Invalid ID",
+		})
+		return
+	}
+
+	permission, errRes := h.service.FindOne(c.UserContext(), id)
+	if errRes != nil {
+		c.JSON(errRes.StatusCode, errRes)
+		return
+	}
+
+	c.JSON(http.StatusOK, permission)
+	return
+}
+
+// Create is a function that create the permission
+// @Summary Create the permission
+// @Description Return the permission dto if successfully
+// @Param permission body dto.PermissionDto true "permission dto"
+// @Tags permission
+// @Accept json
+// @Produce json
+// @Success 201 {object} proto.Permission
+// @Failure 400 {object} dto.ResponseErr "Invalid ID"
+// @Failure 404 {object} dto.ResponseErr "Not found permission"
+// @Failure 422 {object} dto.ResponseErr "Duplicated permission code"
+// @Failure 403 {object} dto.ResponseErr Insufficient permission
+// @Failure 503 {object} dto.ResponseErr "Service is down"
+// @Security     AuthToken
+// @Router /permission [post]
+func (h *PermissionHandler) Create(c PermissionContext) {
+	permissionDto := dto.PermissionDto{}
+	err := c.Bind(&permissionDto)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Cannot parse permission dto",
+		})
+		return
+	}
+
+	if errors := h.validate.Validate(permissionDto); errors != nil {
+		c.JSON(http.StatusBadRequest, &dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Invalid body request",
+			Data:       errors,
+		})
+		return
+	}
+
+	if errRes := h.checkDuplicatedCode(c.UserContext(), 0, permissionDto.Code); errRes != nil {
+		c.JSON(errRes.StatusCode, errRes)
+		return
+	}
+
+	permission, errRes := h.service.Create(c.UserContext(), &permissionDto)
+	if errRes != nil {
+		c.JSON(errRes.StatusCode, errRes)
+		return
+	}
+
+	c.JSON(http.StatusCreated, permission)
+	return
+}
+
+// Update is a function that update the permission
+// @Summary Update the existing permission
+// @Description Return the permission dto if successfully
+// @Param id path int true "id"
+// @Param permission body dto.PermissionDto true "permission dto"
+// @Tags permission
+// @Accept json
+// @Produce json
+// @Success 200 {object} proto.Permission
+// @Failure 400 {object} dto.ResponseErr "Invalid ID"
+// @Failure 404 {object} dto.ResponseErr "Not found permission"
+// @Failure 422 {object} dto.ResponseErr "Duplicated permission code"
+// @Failure 403 {object} dto.ResponseErr Insufficient permission
+// @Failure 503 {object} dto.ResponseErr "Service is down"
+// @Security     AuthToken
+// @Router /permission/{id} [patch]
+func (h *PermissionHandler) Update(c PermissionContext) {
+	permissionDto := dto.PermissionDto{}
+	err := c.Bind(&permissionDto)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Cannot parse permission dto",
+		})
+		return
+	}
+
+	if errors := h.validate.Validate(permissionDto); errors != nil {
+		c.JSON(http.StatusBadRequest, &dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Invalid body request",
+			Data:       errors,
+		})
+		return
+	}
+
+	id, err := c.ID()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Invalid ID",
+		})
+		return
+	}
+
+	if errRes := h.checkDuplicatedCode(c.UserContext(), id, permissionDto.Code); errRes != nil {
+		c.JSON(errRes.StatusCode, errRes)
+		return
+	}
+
+	permission, errRes := h.service.Update(c.UserContext(), id, &permissionDto)
+	if errRes != nil {
+		c.JSON(errRes.StatusCode, errRes)
+		return
+	}
+
+	c.JSON(http.StatusOK, permission)
+	return
+}
+
+// Delete is a function that delete the permission
+// @Summary Delete the permission
+// @Description Return the permission dto if successfully
+// @Param id path int true "id"
+// @Tags permission
+// @Accept json
+// @Produce json
+// @Success 200 {object} proto.Permission
+// @Failure 400 {object} dto.ResponseErr "Invalid ID"
+// @Failure 404 {object} dto.ResponseErr "Not found permission"
+// @Failure 403 {object} dto.ResponseErr Insufficient permission
+// @Failure 503 {object} dto.ResponseErr "Service is down"
+// @Security     AuthToken
+// @Router /permission/{id} [delete]
+func (h *PermissionHandler) Delete(c PermissionContext) {
+	id, err := c.ID()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Invalid ID",
+		})
+		return
+	}
+
+	permission, errRes := h.service.Delete(c.UserContext(), id)
+	if errRes != nil {
+		c.JSON(errRes.StatusCode, errRes)
+		return
+	}
+
+	c.JSON(http.StatusOK, permission)
+	return
+}
+
+func (h *PermissionHandler) checkDuplicatedCode(ctx context.Context, id int32, code string) *dto.ResponseErr {
+	permission, errRes := h.service.FindByCode(ctx, code)
+	if errRes != nil {
+		if errRes.StatusCode == http.StatusNotFound {
+			return nil
+		}
+
+		return errRes
+	}
+
+	if int32(permission.Id) != id {
+		return &dto.ResponseErr{
+			StatusCode: http.StatusUnprocessableEntity,
+			Message:    "Duplicated permission code",
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,337 @@
+package handler
+
+import (
+	"context"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/dto"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/proto"
+	validate "github.com/samithiwat/samithiwat-backend-gateway/src/validator"
+	"net/http"
+)
+
+type RoleHandler struct {
+	service       RoleService
+	permissionSrv PermissionService
+	validate      *validate.DtoValidator
+}
+
+func NewRoleHandler(service RoleService, permissionSrv PermissionService, validate *validate.DtoValidator) *RoleHandler {
+	return &RoleHandler{
+		service:       service,
+		permissionSrv: permissionSrv,
+		validate:      validate,
+	}
+}
+
+type RoleContext interface {
+	Bind(interface{}) error
+	JSON(int, interface{})
+	ID() (int32, error)
+	PermissionID() (int32, error)
+	PaginationQueryParam(*dto.PaginationQueryParams) error
+	UserContext() context.Context
+}
+
+type RoleService interface {
+	FindAll(context.Context, *dto.PaginationQueryParams) (*proto.RolePagination, *dto.ResponseErr)
+	FindOne(context.Context, int32) (*proto.Role, *dto.ResponseErr)
+	Create(context.Context, *dto.RoleDto) (*proto.Role, *dto.ResponseErr)
+	Update(context.Context, int32, *dto.RoleDto) (*proto.Role, *dto.ResponseErr)
+	Delete(context.Context, int32) (*proto.Role, *dto.ResponseErr)
+	AddPermission(context.Context, int32, *proto.Permission) (*proto.Role, *dto.ResponseErr)
+	RemovePermission(context.Context, int32, int32) (*proto.Role, *dto.ResponseErr)
+	AssignUser(context.Context, []int32, int32, *proto.User) (*proto.Role, *dto.ResponseErr)
+}
+
+// FindAll is a function that get all roles in database
+// @Summary Get all roles
+// @Description Return the arrays of role dto if successfully
+// @Param limit query int false "Limit"
+// @Param page query int false "Page"
+// @Tags role
+// @Accept json
+// @Produce json
+// @Success 200 {object} proto.Role
+// @Failure 400 {object} dto.ResponseErr "Invalid query param"
+// @Failure 503 {object} dto.ResponseErr "Service is down"
+// @Security     AuthToken
+// @Router /role [get]
+func (h *RoleHandler) FindAll(c RoleContext) {
+	query := dto.PaginationQueryParams{}
+
+	err := c.PaginationQueryParam(&query)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &dto.ResponseErr{
+			StatusCode: http.StatusInternalServerError,
+			Message:    "Cannot parse query param",
+		})
+		return
+	}
+
+	roles, errRes := h.service.FindAll(c.UserContext(), &query)
+	if errRes != nil {
+		c.JSON(errRes.StatusCode, errRes)
+		return
+	}
+
+	c.JSON(http.StatusOK, roles)
+	return
+}
+
+// FindOne is a function that get the specific roles with id
+// @Summary Get specific role with id
+// @Description Return the role dto if successfully
+// @Param id path int true "id"
+// @Tags role
+// @Accept json
+// @Produce json
+// @Success 200 {object} proto.Role
+// @Failure 400 {object} dto.ResponseErr "Invalid ID"
+// @Failure 404 {object} dto.ResponseErr "Not found role"
+// @Failure 503 {object} dto.ResponseErr "Service is down"
+// @Security     AuthToken
+// @Router /role/{id} [get]
+func (h *RoleHandler) FindOne(c RoleContext) {
+	id, err := c.ID()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Invalid ID",
+		})
+		return
+	}
+
+	role, errRes := h.service.FindOne(c.UserContext(), id)
+	if errRes != nil {
+		c.JSON(errRes.StatusCode, errRes)
+		return
+	}
+
+	c.JSON(http.StatusOK, role)
+	return
+}
+
+// Create is a function that create the role
+// @Summary Create the role
+// @Description Return the role dto if successfully
+// @Param role body dto.RoleDto true "role dto"
+// @Tags role
+// @Accept json
+// @Produce json
+// @Success 201 {object} proto.Role
+// @Failure 400 {object} dto.ResponseErr "Invalid ID"
+// @Failure 404 {object} dto.ResponseErr "Not found role"
+// @Failure 403 {object} dto.ResponseErr Insufficient permission
+// @Failure 503 {object} dto.ResponseErr "Service is down"
+// @Security     AuthToken
+// @Router /role [post]
+func (h *RoleHandler) Create(c RoleContext) {
+	roleDto := dto.RoleDto{}
+	err := c.Bind(&roleDto)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Cannot parse role dto",
+		})
+		return
+	}
+
+	if errors := h.validate.Validate(roleDto); errors != nil {
+		c.JSON(http.StatusBadRequest, &dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Invalid body request",
+			Data:       errors,
+		})
+		return
+	}
+
+	role, errRes := h.service.Create(c.UserContext(), &roleDto)
+	if errRes != nil {
+		c.JSON(errRes.StatusCode, errRes)
+		return
+	}
+
+	c.JSON(http.StatusCreated, role)
+	return
+}
+
+// Update is a function that update the role
+// @Summary Update the existing role
+// @Description Return the role dto if successfully
+// @Param id path int true "id"
+// @Param role body dto.RoleDto true "role dto"
+// @Tags role
+// @Accept json
+// @Produce json
+// @Success 200 {object} proto.Role
+// @Failure 400 {object} dto.ResponseErr "Invalid ID"
+// @Failure 404 {object} dto.ResponseErr "Not found role"
+// @Failure 403 {object} dto.ResponseErr Insufficient permission
+// @Failure 503 {object} dto.ResponseErr "Service is down"
+// @Security     AuthToken
+// @Router /role/{id} [patch]
+func (h *RoleHandler) Update(c RoleContext) {
+	roleDto := dto.RoleDto{}
+	err := c.Bind(&roleDto)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Cannot parse role dto",
+		})
+		return
+	}
+
+	if errors := h.validate.Validate(roleDto); errors != nil {
+		c.JSON(http.StatusBadRequest, &dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Invalid body request",
+			Data:       errors,
+		})
+		return
+	}
+
+	id, err := c.ID()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Invalid ID",
+		})
+		return
+	}
+
+	role, errRes := h.service.Update(c.UserContext(), id, &roleDto)
+	if errRes != nil {
+		c.JSON(errRes.StatusCode, errRes)
+		return
+	}
+
+	c.JSON(http.StatusOK, role)
+	return
+}
+
+// Delete is a function that delete the role
+// @Summary Delete the role
+// @Description Return the role dto if successfully
+// @Param id path int true "id"
+// @Tags role
+// @Accept json
+// @Produce json
+// @Success 200 {object} proto.Role
+// @Failure 400 {object} dto.ResponseErr "Invalid ID"
+// @Failure 404 {object} dto.ResponseErr "Not found role"
+// @Failure 403 {object} dto.ResponseErr Insufficient permission
+// @Failure 503 {object} dto.ResponseErr "Service is down"
+// @Security     AuthToken
+// @Router /role/{id} [delete]
+func (h *RoleHandler) Delete(c RoleContext) {
+	id, err := c.ID()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Invalid ID",
+		})
+		return
+	}
+
+	role, errRes := h.service.Delete(c.UserContext(), id)
+	if errRes != nil {
+		c.JSON(errRes.StatusCode, errRes)
+		return
+	}
+
+	c.JSON(http.StatusOK, role)
+	return
+}
+
+// AddPermission is a function that add the permission to the role
+// @Summary Add the permission to the role
+// @Description Return the role dto if successfully
+// @Param id path int true "id"
+// @Param permissionId path int true "permission id"
+// @Tags role
+// @Accept json
+// @Produce json
+// @Success 200 {object} proto.Role
+// @Failure 400 {object} dto.ResponseErr "Invalid ID"
+// @Failure 404 {object} dto.ResponseErr "Not found role or permission"
+// @Failure 403 {object} dto.ResponseErr Insufficient permission
+// @Failure 503 {object} dto.ResponseErr "Service is down"
+// @Security     AuthToken
+// @Router /role/{id}/permission/{permissionId} [post]
+func (h *RoleHandler) AddPermission(c RoleContext) {
+	id, err := c.ID()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Invalid ID",
+		})
+		return
+	}
+
+	permissionId, err := c.PermissionID()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Invalid permission ID",
+		})
+		return
+	}
+
+	permission, errRes := h.permissionSrv.FindOne(c.UserContext(), permissionId)
+	if errRes != nil {
+		c.JSON(errRes.StatusCode, errRes)
+		return
+	}
+
+	role, errRes := h.service.AddPermission(c.UserContext(), id, permission)
+	if errRes != nil {
+		c.JSON(errRes.StatusCode, errRes)
+		return
+	}
+
+	c.JSON(http.StatusOK, role)
+	return
+}
+
+// RemovePermission is a function that remove the permission from the role
+// @Summary Remove the permission from the role
+// @Description Return the role dto if successfully
+// @Param id path int true "id"
+// @Param permissionId path int true "permission id"
+// @Tags role
+// @Accept json
+// @Produce json
+// @Success 200 {object} proto.Role
+// @Failure 400 {object} dto.ResponseErr "Invalid ID"
+// @Failure 404 {object} dto.ResponseErr "Not found role or permission"
+// @Failure 403 {object} dto.ResponseErr Insufficient permission
+// @Failure 503 {object} dto.ResponseErr "Service is down"
+// @Security     AuthToken
+// @Router /role/{id}/permission/{permissionId} [delete]
+func (h *RoleHandler) RemovePermission(c RoleContext) {
+	id, err := c.ID()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Invalid ID",
+		})
+		return
+	}
+
+	permissionId, err := c.PermissionID()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Invalid permission ID",
+		})
+		return
+	}
+
+	role, errRes := h.service.RemovePermission(c.UserContext(), id, permissionId)
+	if errRes != nil {
+		c.JSON(errRes.StatusCode, errRes)
+		return
+	}
+
+	c.JSON(http.StatusOK, role)
+	return
+}
@@ -1,6 +1,10 @@
 package handler
 
 import (
+	"context"
+	"fmt"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/common"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/constant"
 	"github.com/samithiwat/samithiwat-backend-gateway/src/dto"
 	"github.com/samithiwat/samithiwat-backend-gateway/src/proto"
 	validate "github.com/samithiwat/samithiwat-backend-gateway/src/validator"
@@ -8,30 +12,59 @@ import (
 )
 
 type TeamHandler struct {
-	service  TeamService
-	validate *validate.DtoValidator
+	service     TeamService
+	userSrv     UserService
+	activitySrv ActivityService
+	owners      OwnerChecker
+	permissions PermissionChecker
+	validate    *validate.DtoValidator
 }
 
-func NewTeamHandler(service TeamService, validate *validate.DtoValidator) *TeamHandler {
+// OwnerChecker tells whether the user owns the resource with the id, the resource is one of the constant.Resource values
+type OwnerChecker interface {
+	IsOwner(context.Context, string, int32, int32) (bool, *dto.ResponseErr)
+}
+
+// PermissionChecker tells whether one of the roles of the user has the permission code
+type PermissionChecker interface {
+	HasPermission(context.Context, int32, string) (bool, *dto.ResponseErr)
+}
+
+func NewTeamHandler(service TeamService, userSrv UserService, activitySrv ActivityService, owners OwnerChecker, permissions PermissionChecker, validate *validate.DtoValidator) *TeamHandler {
 	return &TeamHandler{
-		service:  service,
-		validate: validate,
+		service:     service,
+		userSrv:     userSrv,
+		activitySrv: activitySrv,
+		owners:      owners,
+		permissions: permissions,
+		validate:    validate,
 	}
 }
 
 type TeamContext interface {
+	UserContext() context.Context
 	Bind(interface{}) error
 	JSON(int, interface{})
 	ID() (int32, error)
+	Principal() *dto.Principal
 	PaginationQueryParam(*dto.PaginationQueryParams) error
+	IDsQueryParam() string
+	MemberID() (int32, error)
+	TreeQueryParam(*dto.TreeQueryParams) error
+	ActivityQueryParam(*dto.ActivityQueryParams) error
 }
 
 type TeamService interface {
-	FindAll(*dto.PaginationQueryParams) (*proto.TeamPagination, *dto.ResponseErr)
-	FindOne(int32) (*proto.Team, *dto.ResponseErr)
-	Create(*dto.TeamDto) (*proto.Team, *dto.ResponseErr)
-	Update(int32, *dto.TeamDto) (*proto.Team, *dto.ResponseErr)
-	Delete(int32) (*proto.Team, *dto.ResponseErr)
+	FindAll(context.Context, *dto.PaginationQueryParams) (*proto.TeamPagination, *dto.ResponseErr)
+	FindOne(context.Context, int32) (*proto.Team, *dto.ResponseErr)
+	FindMulti(context.Context, []int32) ([]*proto.Team, *dto.ResponseErr)
+	Create(context.Context, *dto.TeamDto) (*proto.Team, *dto.ResponseErr)
+	Update(context.Context, int32, *dto.TeamDto) (*proto.Team, *dto.ResponseErr)
+	Delete(context.Context, int32) (*proto.Team, *dto.ResponseErr)
+	FindMembers(context.Context, int32, *dto.PaginationQueryParams) (*proto.UserPagination, *dto.ResponseErr)
+	AddMember(context.Context, int32, *proto.User) (*proto.Team, *dto.ResponseErr)
+	RemoveMember(context.Context, int32, int32) (*proto.Team, *dto.ResponseErr)
+	FindTree(context.Context, int32, int) (*proto.Team, *dto.ResponseErr)
 }
 
 // FindAll is a function that get all teams in database
@@ -39,6 +72,7 @@ type TeamService interface {
 // @Description Return the arrays of team dto if successfully
 // @Param limit query int false "Limit"
 // @Param page query int false "Page"
+// @Param ids query string false "Comma separated ids, return only these teams in the given order"
 // @Tags team
 // @Accept json
 // @Produce json
@@ -48,6 +82,11 @@ type TeamService interface {
 // @Security     AuthToken
 // @Router /team [get]
 func (h *TeamHandler) FindAll(c TeamContext) {
+	if ids := c.IDsQueryParam(); ids != "" {
+		h.findMulti(c, ids)
+		return
+	}
+
 	query := dto.PaginationQueryParams{}
 
 	err := c.PaginationQueryParam(&query)
@@ -59,7 +98,35 @@ func (h *TeamHandler) FindAll(c TeamContext) {
 		return
 	}
 
-	teams, errRes := h.service.FindAll(&query)
+	teams, errRes := h.service.FindAll(c.UserContext(), &query)
+	if errRes != nil {
+		c.JSON(errRes.StatusCode, errRes)
+		return
+	}
+
+	c.JSON(http.StatusOK, teams)
+	return
+}
+
+func (h *TeamHandler) findMulti(c TeamContext, raw string) {
+	ids, err := common.ParseIDs(raw, constant.MaxBatchSize)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Invalid ids query param",
+			Data: []*dto.BadReqErrResponse{
+				{
+					Message:     err.Error(),
+					FailedField: "ids",
+					Tag:         "ids",
+					Value:       raw,
+				},
+			},
+		})
+		return
+	}
+
+	teams, errRes := h.service.FindMulti(c.UserContext(), ids)
 	if errRes != nil {
 		c.JSON(errRes.StatusCode, errRes)
 		return
@@ -91,7 +158,7 @@ func (h *TeamHandler) FindOne(c TeamContext) {
 		return
 	}
 
-	team, errRes := h.service.FindOne(id)
+	team, errRes := h.service.FindOne(c.UserContext(), id)
 	if errRes != nil {
 		c.JSON(errRes.StatusCode, errRes)
 		return
@@ -111,6 +178,8 @@ func (h *TeamHandler) FindOne(c TeamContext) {
 // @Success 201 {object} proto.Team
 // @Failure 400 {object} dto.ResponseErr "Invalid ID"
 // @Failure 404 {object} dto.ResponseErr "Not found team"
+// @Failure 422 {object} dto.ResponseErr "A team cannot be its own ancestor"
+// @Failure 403 {object} dto.ResponseErr Insufficient permission
 // @Failure 503 {object} dto.ResponseErr "Service is down"
 // @Security     AuthToken
 // @Router /team [post]
@@ -134,7 +203,7 @@ func (h *TeamHandler) Create(c TeamContext) {
 		return
 	}
 
-	team, errRes := h.service.Create(&teamDto)
+	team, errRes := h.service.Create(c.UserContext(), &teamDto)
 	if errRes != nil {
 		c.JSON(errRes.StatusCode, errRes)
 		return
@@ -146,7 +215,7 @@ func (h *TeamHandler) Create(c TeamContext) {
 
 // Update is a function that update the team
 // @Summary Update the existing team
-// @Description Return the team dto if successfully
+// @Description Return the team dto if successfully, a member of the team must also own the parent team and the organization that it moves the team to
 // @Param id path int true "id"
 // @Param team body dto.TeamDto true "team dto"
 // @Tags team
@@ -155,6 +224,8 @@ func (h *TeamHandler) Create(c TeamContext) {
 // @Success 200 {object} proto.Team
 // @Failure 400 {object} dto.ResponseErr "Invalid ID"
 // @Failure 404 {object} dto.ResponseErr "Not found team"
+// @Failure 422 {object} dto.ResponseErr "A team cannot be its own ancestor"
+// @Failure 403 {object} dto.ResponseErr Insufficient permission
 // @Failure 503 {object} dto.ResponseErr "Service is down"
 // @Security     AuthToken
 // @Router /team/{id} [patch]
@@ -187,7 +258,11 @@ func (h *TeamHandler) Update(c TeamContext) {
 		return
 	}
 
-	team, errRes := h.service.Update(id, &teamDto)
+	if !h.canMove(c, id, &teamDto) {
+		return
+	}
+
+	team, errRes := h.service.Update(c.UserContext(), id, &teamDto)
 	if errRes != nil {
 		c.JSON(errRes.StatusCode, errRes)
 		return
@@ -207,6 +282,7 @@ func (h *TeamHandler) Update(c TeamContext) {
 // @Success 200 {object} proto.Team
 // @Failure 400 {object} dto.ResponseErr "Invalid ID"
 // @Failure 404 {object} dto.ResponseErr "Not found team"
+// @Failure 403 {object} dto.ResponseErr Insufficient permission
 // @Failure 503 {object} dto.ResponseErr "Service is down"
 // @Security     AuthToken
 // @Router /team/{id} [delete]
@@ -220,7 +296,7 @@ func (h *TeamHandler) Delete(c TeamContext) {
 		return
 	}
 
-	team, errRes := h.service.Delete(id)
+	team, errRes := h.service.Delete(c.UserContext(), id)
 	if errRes != nil {
 		c.JSON(errRes.StatusCode, errRes)
 		return
@@ -229,3 +305,325 @@ func (h *TeamHandler) Delete(c TeamContext) {
 	c.JSON(http.StatusOK, team)
 	return
 }
+
+// FindMembers is a function that get the members of the team
+// @Summary Get the members of the team
+// @Description Return the pagination of user dto if successfully
+// @Param id path int true "id"
+// @Param limit query int false "Limit"
+// @Param page query int false "Page"
+// @Tags team
+// @Accept json
+// @Produce json
+// @Success 200 {object} proto.UserPagination
+// @Failure 400 {object} dto.ResponseErr "Invalid ID"
+// @Failure 404 {object} dto.ResponseErr "Not found team"
+// @Failure 503 {object} dto.ResponseErr "Service is down"
+// @Router /team/{id}/member [get]
+func (h *TeamHandler) FindMembers(c TeamContext) {
+	id, err := c.ID()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Invalid ID",
+		})
+		return
+	}
+
+	query := dto.PaginationQueryParams{}
+
+	err = c.PaginationQueryParam(&query)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &dto.ResponseErr{
+			StatusCode: http.StatusInternalServerError,
+			Message:    "Cannot parse query param",
+		})
+		return
+	}
+
+	members, errRes := h.service.FindMembers(c.UserContext(), id, &query)
+	if errRes != nil {
+		c.JSON(errRes.StatusCode, errRes)
+		return
+	}
+
+	c.JSON(http.StatusOK, members)
+	return
+}
+
+// AddMember is a function that add the user to the team
+// @Summary Add the member to the team
+// @Description Return the team dto if successfully
+// @Param id path int true "id"
+// @Param member body dto.MemberDto true "member dto"
+// @Tags team
+// @Accept json
+// @Produce json
+// @Success 200 {object} proto.Team
+// @Failure 400 {object} dto.ResponseErr "Invalid ID"
+// @Failure 404 {object} dto.ResponseErr "Not found team or user"
+// @Failure 403 {object} dto.ResponseErr Insufficient permission
+// @Failure 503 {object} dto.ResponseErr "Service is down"
+// @Security     AuthToken
+// @Router /team/{id}/member [post]
+func (h *TeamHandler) AddMember(c TeamContext) {
+	memberDto := dto.MemberDto{}
+	err := c.Bind(&memberDto)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Cannot parse member dto",
+		})
+		return
+	}
+
+	if errors := h.validate.Validate(memberDto); errors != nil {
+		c.JSON(http.StatusBadRequest, &dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Invalid body request",
+			Data:       errors,
+		})
+		return
+	}
+
+	id, err := c.ID()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Invalid ID",
+		})
+		return
+	}
+
+	user, errRes := h.userSrv.FindOne(c.UserContext(), memberDto.UserID)
+	if errRes != nil {
+		c.JSON(errRes.StatusCode, errRes)
+		return
+	}
+
+	team, errRes := h.service.AddMember(c.UserContext(), id, user)
+	if errRes != nil {
+		c.JSON(errRes.StatusCode, errRes)
+		return
+	}
+
+	c.JSON(http.StatusOK, team)
+	return
+}
+
+// RemoveMember is a function that remove the user from the team
+// @Summary Remove the member from the team
+// @Description Return the team dto if successfully
+// @Param id path int true "id"
+// @Param userId path int true "user id"
+// @Tags team
+// @Accept json
+// @Produce json
+// @Success 200 {object} proto.Team
+// @Failure 400 {object} dto.ResponseErr "Invalid ID"
+// @Failure 404 {object} dto.ResponseErr "Not found team or member"
+// @Failure 403 {object} dto.ResponseErr Insufficient permission
+// @Failure 503 {object} dto.ResponseErr "Service is down"
+// @Security     AuthToken
+// @Router /team/{id}/member/{userId} [delete]
+func (h *TeamHandler) RemoveMember(c TeamContext) {
+	id, err := c.ID()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Invalid ID",
+		})
+		return
+	}
+
+	userId, err := c.MemberID()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Invalid user ID",
+		})
+		return
+	}
+
+	team, errRes := h.service.RemoveMember(c.UserContext(), id, userId)
+	if errRes != nil {
+		c.JSON(errRes.StatusCode, errRes)
+		return
+	}
+
+	c.JSON(http.StatusOK, team)
+	return
+}
+
+// FindTree is a function that get the team with its sub-team hierarchy
+// @Summary Get the sub-team hierarchy of the team
+// @Description Return the team dto with the nested sub-teams if successfully
+// @Param id path int true "id"
+// @Param depth query int false "Depth of the tree"
+// @Tags team
+// @Accept json
+// @Produce json
+// @Success 200 {object} proto.Team
+// @Failure 400 {object} dto.ResponseErr "Invalid ID or depth"
+// @Failure 404 {object} dto.ResponseErr "Not found team"
+// @Failure 503 {object} dto.ResponseErr "Service is down"
+// @Router /team/{id}/tree [get]
+func (h *TeamHandler) FindTree(c TeamContext) {
+	id, err := c.ID()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Invalid ID",
+		})
+		return
+	}
+
+	query := dto.TreeQueryParams{}
+
+	err = c.TreeQueryParam(&query)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &dto.ResponseErr{
+			StatusCode: http.StatusInternalServerError,
+			Message:    "Cannot parse query param",
+		})
+		return
+	}
+
+	if query.Depth <= 0 {
+		query.Depth = constant.DefaultTeamTreeDepth
+	}
+
+	if query.Depth > constant.MaxTeamTreeDepth {
+		c.JSON(http.StatusBadRequest, &dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("Depth must not exceed %d", constant.MaxTeamTreeDepth),
+		})
+		return
+	}
+
+	team, errRes := h.service.FindTree(c.UserContext(), id, query.Depth)
+	if errRes != nil {
+		c.JSON(errRes.StatusCode, errRes)
+		return
+	}
+
+	c.JSON(http.StatusOK, team)
+	return
+}
+
+// FindActivity is a function that get the activity logs of the team
+// @Summary Get the activity logs of the team
+// @Description Return the page of log dto if successfully, the newest first
+// @Param id path int true "id"
+// @Param limit query int false "Limit"
+// @Param page query int false "Page"
+// @Param type query string false "Log type" Enums(POST, EDIT, LOGIN, CHANGE_PASSWORD, BOOKMARK)
+// @Tags team
+// @Accept json
+// @Produce json
+// @Success 200 {object} dto.LogPagination
+// @Failure 400 {object} dto.ResponseErr "Invalid ID or log type"
+// @Failure 401 {object} dto.ResponseErr "Invalid token"
+// @Failure 404 {object} dto.ResponseErr "Not found team"
+// @Failure 503 {object} dto.ResponseErr "Service is down"
+// @Security     AuthToken
+// @Router /team/{id}/activity [get]
+func (h *TeamHandler) FindActivity(c TeamContext) {
+	id, err := c.ID()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Invalid ID",
+		})
+		return
+	}
+
+	query := dto.ActivityQueryParams{}
+
+	err = c.ActivityQueryParam(&query)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &dto.ResponseErr{
+			StatusCode: http.StatusInternalServerError,
+			Message:    "Cannot parse query param",
+		})
+		return
+	}
+
+	team, errRes := h.service.FindOne(c.UserContext(), id)
+	if errRes != nil {
+		c.JSON(errRes.StatusCode, errRes)
+		return
+	}
+
+	res, errRes := h.activitySrv.Paginate(team.Logs, &query)
+	if errRes != nil {
+		c.JSON(errRes.StatusCode, errRes)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+	return
+}
+
+// canMove responds the error and returns false when the caller moves the team under a parent team or into an organization
+// that it does not own, the membership of the team lets the caller edit the team but not the teams and organizations around it
+func (h *TeamHandler) canMove(c TeamContext, id int32, teamDto *dto.TeamDto) bool {
+	if teamDto.ParentTeamID.ID <= 0 && teamDto.OrganizationID <= 0 {
+		return true
+	}
+
+	principal := c.Principal()
+	if principal.Admin {
+		return true
+	}
+
+	if teamDto.ParentTeamID.ID > 0 && !h.owns(c, principal, constant.ResourceTeam, teamDto.ParentTeamID.ID) {
+		return false
+	}
+
+	if teamDto.OrganizationID <= 0 {
+		return true
+	}
+
+	team, errRes := h.service.FindOne(c.UserContext(), id)
+	if errRes != nil {
+		c.JSON(errRes.StatusCode, errRes)
+		return false
+	}
+
+	if team.Organization != nil && int32(team.Organization.Id) == teamDto.OrganizationID {
+		return true
+	}
+
+	return h.owns(c, principal, constant.ResourceOrganization, teamDto.OrganizationID)
+}
+
+// owns responds the error and returns false when the caller neither owns the resource nor has the admin permission of the
+// route, an admin that is a member of the team passed the guard as an owner so its permission is only checked here
+func (h *TeamHandler) owns(c TeamContext, principal *dto.Principal, resource string, id int32) bool {
+	owner, errRes := h.owners.IsOwner(c.UserContext(), resource, id, principal.UserID)
+	if errRes != nil {
+		c.JSON(errRes.StatusCode, errRes)
+		return false
+	}
+
+	// the scopes of the api key clients were checked by the guard, the permission of their user is not theirs
+	if !owner && principal.ApiKeyID == "" {
+		owner, errRes = h.permissions.HasPermission(c.UserContext(), principal.UserID, "team:update")
+		if errRes != nil {
+			c.JSON(errRes.StatusCode, errRes)
+			return false
+		}
+	}
+
+	if !owner {
+		c.JSON(http.StatusForbidden, &dto.ResponseErr{
+			StatusCode: http.StatusForbidden,
+			Message:    "Insufficient permission",
+			Data:       "team:update",
+		})
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,256 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/config"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/dto"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/proto"
+	validate "github.com/samithiwat/samithiwat-backend-gateway/src/validator"
+	"net/http"
+)
+
+type TwoFactorHandler struct {
+	service     TwoFactorService
+	userSrv     UserService
+	activitySrv ActivityService
+	sessions    SessionRecorder
+	session     config.Session
+	validate    *validate.DtoValidator
+}
+
+func NewTwoFactorHandler(s TwoFactorService, u UserService, a ActivityService, sessions SessionRecorder, session config.Session, v *validate.DtoValidator) *TwoFactorHandler {
+	return &TwoFactorHandler{
+		service:     s,
+		userSrv:     u,
+		activitySrv: a,
+		sessions:    sessions,
+		session:     session,
+		validate:    v,
+	}
+}
+
+type TwoFactorContext interface {
+	UserContext() context.Context
+	Bind(interface{}) error
+	JSON(int, interface{})
+	Principal() *dto.Principal
+	SetCookie(*dto.Cookie)
+	Device() *dto.Device
+}
+
+// TwoFactorChallenger holds the credential of the login when the user has enabled the two-factor authentication, the challenge is nil when it has not
+type TwoFactorChallenger interface {
+	Challenge(context.Context, uint32, *proto.Credential) (*dto.TwoFactorChallenge, *dto.ResponseErr)
+}
+
+type TwoFactorService interface {
+	TwoFactorChallenger
+	Setup(context.Context, uint32, string) (*dto.TotpSetup, *dto.ResponseErr)
+	Confirm(context.Context, uint32, string) (*dto.RecoveryCodes, *dto.ResponseErr)
+	Verify(context.Context, string, string) (uint32, *proto.Credential, *dto.ResponseErr)
+	Disable(context.Context, uint32, string) *dto.ResponseErr
+}
+
+// Setup is a function that create the secret of the authenticator
+// @Summary Set up the two-factor authentication
+// @Description Return the secret, the otpauth uri and its qr code as a base64 encoded PNG, the authenticator is used after it is confirmed
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Success 200 {object} dto.TotpSetup
+// @Failure 401 {object} dto.ResponseErr "Invalid token"
+// @Failure 404 {object} dto.ResponseErr "Two-factor authentication is not enabled"
+// @Failure 409 {object} dto.ResponseErr "Two-factor authentication is already enabled"
+// @Failure 503 {object} dto.ResponseErr "Service is down"
+// @Security     AuthToken
+// @Router /auth/2fa/setup [post]
+func (h *TwoFactorHandler) Setup(c TwoFactorContext) {
+	principal := c.Principal()
+	if !principal.IsUser() {
+		c.JSON(http.StatusUnauthorized, &dto.ResponseErr{
+			StatusCode: http.StatusUnauthorized,
+			Message:    "Invalid token",
+		})
+		return
+	}
+
+	user, errRes := h.userSrv.FindOne(c.UserContext(), principal.UserID)
+	if errRes != nil {
+		c.JSON(errRes.StatusCode, errRes)
+		return
+	}
+
+	account := user.DisplayName
+	if account == "" {
+		account = fmt.Sprintf("user-%v", principal.UserID)
+	}
+
+	res, errRes := h.service.Setup(c.UserContext(), uint32(principal.UserID), account)
+	if errRes != nil {
+		c.JSON(errRes.StatusCode, errRes)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+	return
+}
+
+// Confirm is a function that enable the authenticator of the setup
+// @Summary Confirm the two-factor authentication
+// @Description Return the recovery codes if successfully, they are shown only once
+// @Param code body dto.TotpCode true "totp code dto"
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Success 200 {object} dto.RecoveryCodes
+// @Failure 400 {object} dto.ResponseErr "Invalid code"
+// @Failure 401 {object} dto.ResponseErr "Invalid token"
+// @Failure 409 {object} dto.ResponseErr "Two-factor authentication is already enabled"
+// @Failure 503 {object} dto.ResponseErr "Service is down"
+// @Security     AuthToken
+// @Router /auth/2fa/confirm [post]
+func (h *TwoFactorHandler) Confirm(c TwoFactorContext) {
+	principal := c.Principal()
+	if !principal.IsUser() {
+		c.JSON(http.StatusUnauthorized, &dto.ResponseErr{
+			StatusCode: http.StatusUnauthorized,
+			Message:    "Invalid token",
+		})
+		return
+	}
+
+	code := dto.TotpCode{}
+	err := c.Bind(&code)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Cannot parse totp code dto",
+		})
+		return
+	}
+
+	if errors := h.validate.Validate(code); errors != nil {
+		c.JSON(http.StatusBadRequest, &dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Invalid body request",
+			Data:       errors,
+		})
+		return
+	}
+
+	res, errRes := h.service.Confirm(c.UserContext(), uint32(principal.UserID), code.Code)
+	if errRes != nil {
+		c.JSON(errRes.StatusCode, errRes)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+	return
+}
+
+// Disable is a function that remove the authenticator of the user
+// @Summary Disable the two-factor authentication
+// @Description Remove the authenticator when the code is a code of the authenticator or a recovery code, the logins are not challenged anymore
+// @Param code body dto.TotpCode true "totp code dto"
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Success 204
+// @Failure 400 {object} dto.ResponseErr "Invalid code"
+// @Failure 401 {object} dto.ResponseErr "Invalid token"
+// @Failure 429 {object} dto.ResponseErr "Too many attempts"
+// @Failure 503 {object} dto.ResponseErr "Service is down"
+// @Security     AuthToken
+// @Router /auth/2fa/disable [post]
+func (h *TwoFactorHandler) Disable(c TwoFactorContext) {
+	principal := c.Principal()
+	if !principal.IsUser() {
+		c.JSON(http.StatusUnauthorized, &dto.ResponseErr{
+			StatusCode: http.StatusUnauthorized,
+			Message:    "Invalid token",
+		})
+		return
+	}
+
+	code := dto.TotpCode{}
+	err := c.Bind(&code)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Cannot parse totp code dto",
+		})
+		return
+	}
+
+	if errors := h.validate.Validate(code); errors != nil {
+		c.JSON(http.StatusBadRequest, &dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Invalid body request",
+			Data:       errors,
+		})
+		return
+	}
+
+	if errRes := h.service.Disable(c.UserContext(), uint32(principal.UserID), code.Code); errRes != nil {
+		c.JSON(errRes.StatusCode, errRes)
+		return
+	}
+
+	h.activitySrv.Record(proto.LogType_EDIT, uint32(principal.UserID), "Disable two-factor authentication")
+
+	c.JSON(http.StatusNoContent, nil)
+	return
+}
+
+// Verify is a function that exchange the challenge of the login for the credentials
+// @Summary Verify the two-factor authentication of the login
+// @Description Return the credentials if successfully, the code is a code of the authenticator or a recovery code
+// @Param verify body dto.TwoFactorVerify true "two-factor verify dto"
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Success 201 {object} proto.Credential
+// @Success 200 {object} dto.Session "When the session is kept in cookies"
+// @Failure 400 {object} dto.ResponseErr "Invalid request body"
+// @Failure 401 {object} dto.ResponseErr "Invalid code"
+// @Failure 429 {object} dto.ResponseErr "Too many attempts"
+// @Failure 503 {object} dto.ResponseErr "Service is down"
+// @Router /auth/2fa/verify [post]
+func (h *TwoFactorHandler) Verify(c TwoFactorContext) {
+	verify := dto.TwoFactorVerify{}
+	err := c.Bind(&verify)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Cannot parse two-factor verify dto",
+		})
+		return
+	}
+
+	if errors := h.validate.Validate(verify); errors != nil {
+		c.JSON(http.StatusBadRequest, &dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Invalid body request",
+			Data:       errors,
+		})
+		return
+	}
+
+	userId, credential, errRes := h.service.Verify(c.UserContext(), verify.ChallengeToken, verify.Code)
+	if errRes != nil {
+		c.JSON(errRes.StatusCode, errRes)
+		return
+	}
+
+	h.activitySrv.Record(proto.LogType_LOGIN, userId, "Login with two-factor authentication")
+	h.sessions.Record(c.UserContext(), userId, credential, c.Device())
+
+	if h.session.CookieMode() {
+		writeSession(c, h.session, credential)
+		return
+	}
+
+	c.JSON(http.StatusOK, credential)
+	return
+}
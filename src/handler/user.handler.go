@@ -1,6 +1,9 @@
 package handler
 
 import (
+	"context"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/common"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/constant"
 	"github.com/samithiwat/samithiwat-backend-gateway/src/dto"
 	"github.com/samithiwat/samithiwat-backend-gateway/src/proto"
 	validate "github.com/samithiwat/samithiwat-backend-gateway/src/validator"
@@ -8,30 +11,40 @@ import (
 )
 
 type UserHandler struct {
-	service  UserService
-	validate *validate.DtoValidator
+	service     UserService
+	contactSrv  ContactService
+	locationSrv LocationService
+	activitySrv ActivityService
+	validate    *validate.DtoValidator
 }
 
-func NewUserHandler(service UserService, validate *validate.DtoValidator) *UserHandler {
+func NewUserHandler(service UserService, contactSrv ContactService, locationSrv LocationService, activitySrv ActivityService, validate *validate.DtoValidator) *UserHandler {
 	return &UserHandler{
-		service:  service,
-		validate: validate,
+		service:     service,
+		contactSrv:  contactSrv,
+		locationSrv: locationSrv,
+		activitySrv: activitySrv,
+		validate:    validate,
 	}
 }
 
 type UserContext interface {
+	UserContext() context.Context
 	Bind(interface{}) error
 	JSON(int, interface{})
 	ID() (int32, error)
 	PaginationQueryParam(*dto.PaginationQueryParams) error
+	IDsQueryParam() string
+	ActivityQueryParam(*dto.ActivityQueryParams) error
 }
 
 type UserService interface {
-	FindAll(*dto.PaginationQueryParams) (*proto.UserPagination, *dto.ResponseErr)
-	FindOne(int32) (*proto.User, *dto.ResponseErr)
-	Create(*dto.UserDto) (*proto.User, *dto.ResponseErr)
-	Update(int32, *dto.UserDto) (*proto.User, *dto.ResponseErr)
-	Delete(int32) (*proto.User, *dto.ResponseErr)
+	FindAll(context.Context, *dto.PaginationQueryParams) (*proto.UserPagination, *dto.ResponseErr)
+	FindOne(context.Context, int32) (*proto.User, *dto.ResponseErr)
+	FindMulti(context.Context, []int32) ([]*proto.User, *dto.ResponseErr)
+	Create(context.Context, *dto.UserDto) (*proto.User, *dto.ResponseErr)
+	Update(context.Context, int32, *dto.UserDto) (*proto.User, *dto.ResponseErr)
+	Delete(context.Context, int32) (*proto.User, *dto.ResponseErr)
 }
 
 // FindAll is a function that get all users in database
@@ -39,6 +52,7 @@ type UserService interface {
 // @Description Return the arrays of user dto if successfully
 // @Param limit query int false "Limit"
 // @Param page query int false "Page"
+// @Param ids query string false "Comma separated ids, return only these users in the given order"
 // @Tags user
 // @Accept json
 // @Produce json
@@ -48,6 +62,11 @@ type UserService interface {
 // @Security     AuthToken
 // @Router /user [get]
 func (h *UserHandler) FindAll(c UserContext) {
+	if ids := c.IDsQueryParam(); ids != "" {
+		h.findMulti(c, ids)
+		return
+	}
+
 	query := dto.PaginationQueryParams{}
 
 	err := c.PaginationQueryParam(&query)
@@ -59,12 +78,44 @@ func (h *UserHandler) FindAll(c UserContext) {
 		return
 	}
 
-	users, errRes := h.service.FindAll(&query)
+	users, errRes := h.service.FindAll(c.UserContext(), &query)
 	if errRes != nil {
 		c.JSON(errRes.StatusCode, errRes)
 		return
 	}
 
+	hideAddress(users.Items...)
+
+	c.JSON(http.StatusOK, users)
+	return
+}
+
+func (h *UserHandler) findMulti(c UserContext, raw string) {
+	ids, err := common.ParseIDs(raw, constant.MaxBatchSize)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Invalid ids query param",
+			Data: []*dto.BadReqErrResponse{
+				{
+					Message:     err.Error(),
+					FailedField: "ids",
+					Tag:         "ids",
+					Value:       raw,
+				},
+			},
+		})
+		return
+	}
+
+	users, errRes := h.service.FindMulti(c.UserContext(), ids)
+	if errRes != nil {
+		c.JSON(errRes.StatusCode, errRes)
+		return
+	}
+
+	hideAddress(users...)
+
 	c.JSON(http.StatusOK, users)
 	return
 }
@@ -91,12 +142,14 @@ func (h *UserHandler) FindOne(c UserContext) {
 		return
 	}
 
-	user, errRes := h.service.FindOne(id)
+	user, errRes := h.service.FindOne(c.UserContext(), id)
 	if errRes != nil {
 		c.JSON(errRes.StatusCode, errRes)
 		return
 	}
 
+	hideAddress(user)
+
 	c.JSON(http.StatusOK, user)
 	return
 }
@@ -111,6 +164,7 @@ func (h *UserHandler) FindOne(c UserContext) {
 // @Success 201 {object} proto.User
 // @Failure 400 {object} dto.ResponseErr Invalid ID
 // @Failure 404 {object} dto.ResponseErr Not found user
+// @Failure 403 {object} dto.ResponseErr Insufficient permission
 // @Failure 503 {object} dto.ResponseErr Service is down
 // @Security     AuthToken
 // @Router /user [post]
@@ -134,7 +188,7 @@ func (h *UserHandler) Create(c UserContext) {
 		return
 	}
 
-	user, errRes := h.service.Create(&userDto)
+	user, errRes := h.service.Create(c.UserContext(), &userDto)
 	if errRes != nil {
 		c.JSON(errRes.StatusCode, errRes)
 		return
@@ -155,6 +209,7 @@ func (h *UserHandler) Create(c UserContext) {
 // @Success 200 {object} proto.User
 // @Failure 400 {object} dto.ResponseErr Invalid ID
 // @Failure 404 {object} dto.ResponseErr Not found user
+// @Failure 403 {object} dto.ResponseErr Insufficient permission
 // @Failure 503 {object} dto.ResponseErr Service is down
 // @Security     AuthToken
 // @Router /user/{id} [patch]
@@ -187,7 +242,7 @@ func (h *UserHandler) Update(c UserContext) {
 		return
 	}
 
-	user, errRes := h.service.Update(id, &userDto)
+	user, errRes := h.service.Update(c.UserContext(), id, &userDto)
 	if errRes != nil {
 		c.JSON(errRes.StatusCode, errRes)
 		return
@@ -207,6 +262,7 @@ func (h *UserHandler) Update(c UserContext) {
 // @Success 200 {object} proto.User
 // @Failure 400 {object} dto.ResponseErr Invalid ID
 // @Failure 404 {object} dto.ResponseErr Not found user
+// @Failure 403 {object} dto.ResponseErr Insufficient permission
 // @Failure 503 {object} dto.ResponseErr Service is down
 // @Security     AuthToken
 // @Router /user/{id} [delete]
@@ -220,7 +276,7 @@ func (h *UserHandler) Delete(c UserContext) {
 		return
 	}
 
-	user, errRes := h.service.Delete(id)
+	user, errRes := h.service.Delete(c.UserContext(), id)
 	if errRes != nil {
 		c.JSON(errRes.StatusCode, errRes)
 		return
@@ -229,3 +285,306 @@ func (h *UserHandler) Delete(c UserContext) {
 	c.JSON(http.StatusOK, user)
 	return
 }
+
+// FindContact is a function that get the contact of the user
+// @Summary Get the contact of the user
+// @Description Return the contact dto if successfully
+// @Param id path int true "id"
+// @Tags user
+// @Accept json
+// @Produce json
+// @Success 200 {object} proto.Contact
+// @Failure 400 {object} dto.ResponseErr "Invalid ID"
+// @Failure 404 {object} dto.ResponseErr "Not found user or contact"
+// @Failure 503 {object} dto.ResponseErr "Service is down"
+// @Router /user/{id}/contact [get]
+func (h *UserHandler) FindContact(c UserContext) {
+	id, err := c.ID()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Invalid ID",
+		})
+		return
+	}
+
+	user, errRes := h.service.FindOne(c.UserContext(), id)
+	if errRes != nil {
+		c.JSON(errRes.StatusCode, errRes)
+		return
+	}
+
+	if user.Contact == nil {
+		c.JSON(http.StatusNotFound, &dto.ResponseErr{
+			StatusCode: http.StatusNotFound,
+			Message:    "Not found contact",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, user.Contact)
+	return
+}
+
+// UpdateContact is a function that create or update the contact of the user
+// @Summary Create or update the contact of the user
+// @Description Return the contact dto if successfully
+// @Param id path int true "id"
+// @Param contact body dto.ContactDto true "contact dto"
+// @Tags user
+// @Accept json
+// @Produce json
+// @Success 200 {object} proto.Contact
+// @Failure 400 {object} dto.ResponseErr "Invalid ID"
+// @Failure 404 {object} dto.ResponseErr "Not found user"
+// @Failure 403 {object} dto.ResponseErr Insufficient permission
+// @Failure 503 {object} dto.ResponseErr "Service is down"
+// @Security     AuthToken
+// @Router /user/{id}/contact [put]
+func (h *UserHandler) UpdateContact(c UserContext) {
+	contactDto := dto.ContactDto{}
+	err := c.Bind(&contactDto)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Cannot parse contact dto",
+		})
+		return
+	}
+
+	if errors := h.validate.Validate(contactDto); errors != nil {
+		c.JSON(http.StatusBadRequest, &dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Invalid body request",
+			Data:       errors,
+		})
+		return
+	}
+
+	id, err := c.ID()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Invalid ID",
+		})
+		return
+	}
+
+	user, errRes := h.service.FindOne(c.UserContext(), id)
+	if errRes != nil {
+		c.JSON(errRes.StatusCode, errRes)
+		return
+	}
+
+	if user.Contact != nil && user.Contact.Id > 0 {
+		contact, errRes := h.contactSrv.Update(c.UserContext(), int32(user.Contact.Id), &contactDto)
+		if errRes != nil {
+			c.JSON(errRes.StatusCode, errRes)
+			return
+		}
+
+		c.JSON(http.StatusOK, contact)
+		return
+	}
+
+	user, errRes = h.service.Update(c.UserContext(), id, &dto.UserDto{
+		Firstname:   user.Firstname,
+		Lastname:    user.Lastname,
+		DisplayName: user.DisplayName,
+		ImageUrl:    user.ImageUrl,
+		Contact:     &contactDto,
+	})
+	if errRes != nil {
+		c.JSON(errRes.StatusCode, errRes)
+		return
+	}
+
+	c.JSON(http.StatusOK, user.Contact)
+	return
+}
+
+// FindAddress is a function that get the address of the user
+// @Summary Get the address of the user
+// @Description Return the location dto if successfully
+// @Param id path int true "id"
+// @Tags user
+// @Accept json
+// @Produce json
+// @Success 200 {object} proto.Location
+// @Failure 400 {object} dto.ResponseErr "Invalid ID"
+// @Failure 404 {object} dto.ResponseErr "Not found user or address"
+// @Failure 403 {object} dto.ResponseErr Insufficient permission
+// @Failure 503 {object} dto.ResponseErr "Service is down"
+// @Security     AuthToken
+// @Router /user/{id}/address [get]
+func (h *UserHandler) FindAddress(c UserContext) {
+	id, err := c.ID()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Invalid ID",
+		})
+		return
+	}
+
+	user, errRes := h.service.FindOne(c.UserContext(), id)
+	if errRes != nil {
+		c.JSON(errRes.StatusCode, errRes)
+		return
+	}
+
+	if user.Address == nil {
+		c.JSON(http.StatusNotFound, &dto.ResponseErr{
+			StatusCode: http.StatusNotFound,
+			Message:    "Not found address",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, user.Address)
+	return
+}
+
+// UpdateAddress is a function that create or update the address of the user
+// @Summary Create or update the address of the user
+// @Description Return the location dto if successfully
+// @Param id path int true "id"
+// @Param address body dto.LocationDto true "location dto"
+// @Tags user
+// @Accept json
+// @Produce json
+// @Success 200 {object} proto.Location
+// @Failure 400 {object} dto.ResponseErr "Invalid ID"
+// @Failure 404 {object} dto.ResponseErr "Not found user"
+// @Failure 403 {object} dto.ResponseErr Insufficient permission
+// @Failure 503 {object} dto.ResponseErr "Service is down"
+// @Security     AuthToken
+// @Router /user/{id}/address [put]
+func (h *UserHandler) UpdateAddress(c UserContext) {
+	addressDto := dto.LocationDto{}
+	err := c.Bind(&addressDto)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Cannot parse location dto",
+		})
+		return
+	}
+
+	if errors := h.validate.Validate(addressDto); errors != nil {
+		c.JSON(http.StatusBadRequest, &dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Invalid body request",
+			Data:       errors,
+		})
+		return
+	}
+
+	id, err := c.ID()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Invalid ID",
+		})
+		return
+	}
+
+	user, errRes := h.service.FindOne(c.UserContext(), id)
+	if errRes != nil {
+		c.JSON(errRes.StatusCode, errRes)
+		return
+	}
+
+	if user.Address != nil && user.Address.Id > 0 {
+		address, errRes := h.locationSrv.Update(c.UserContext(), int32(user.Address.Id), &addressDto)
+		if errRes != nil {
+			c.JSON(errRes.StatusCode, errRes)
+			return
+		}
+
+		c.JSON(http.StatusOK, address)
+		return
+	}
+
+	user, errRes = h.service.Update(c.UserContext(), id, &dto.UserDto{
+		Firstname:   user.Firstname,
+		Lastname:    user.Lastname,
+		DisplayName: user.DisplayName,
+		ImageUrl:    user.ImageUrl,
+		Address:     &addressDto,
+	})
+	if errRes != nil {
+		c.JSON(errRes.StatusCode, errRes)
+		return
+	}
+
+	c.JSON(http.StatusOK, user.Address)
+	return
+}
+
+// FindActivity is a function that get the activity logs of the user
+// @Summary Get the activity logs of the user
+// @Description Return the page of log dto if successfully, the newest first
+// @Param id path int true "id"
+// @Param limit query int false "Limit"
+// @Param page query int false "Page"
+// @Param type query string false "Log type" Enums(POST, EDIT, LOGIN, CHANGE_PASSWORD, BOOKMARK)
+// @Tags user
+// @Accept json
+// @Produce json
+// @Success 200 {object} dto.LogPagination
+// @Failure 400 {object} dto.ResponseErr "Invalid ID or log type"
+// @Failure 401 {object} dto.ResponseErr "Invalid token"
+// @Failure 403 {object} dto.ResponseErr "Insufficient permission"
+// @Failure 404 {object} dto.ResponseErr "Not found user"
+// @Failure 503 {object} dto.ResponseErr "Service is down"
+// @Security     AuthToken
+// @Router /user/{id}/activity [get]
+func (h *UserHandler) FindActivity(c UserContext) {
+	id, err := c.ID()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Invalid ID",
+		})
+		return
+	}
+
+	query := dto.ActivityQueryParams{}
+
+	err = c.ActivityQueryParam(&query)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &dto.ResponseErr{
+			StatusCode: http.StatusInternalServerError,
+			Message:    "Cannot parse query param",
+		})
+		return
+	}
+
+	user, errRes := h.service.FindOne(c.UserContext(), id)
+	if errRes != nil {
+		c.JSON(errRes.StatusCode, errRes)
+		return
+	}
+
+	// the activities recorded by the gateway itself are not stored in the user service
+	logs := append(append([]*proto.Log{}, user.Logs...), h.activitySrv.FindByUser(user.Id)...)
+
+	res, errRes := h.activitySrv.Paginate(logs, &query)
+	if errRes != nil {
+		c.JSON(errRes.StatusCode, errRes)
+		return
+	}
+
+	c.JSON(http.StatusOK, res)
+	return
+}
+
+// hideAddress removes the home address from the users of the public lookups, it is only read through /user/:id/address
+func hideAddress(users ...*proto.User) {
+	for _, user := range users {
+		if user != nil {
+			user.Address = nil
+		}
+	}
+}
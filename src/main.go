@@ -2,7 +2,9 @@ package main
 
 import (
 	"context"
+	"encoding/base64"
 	"fmt"
+	"github.com/go-redis/redis/v8"
 	"github.com/samithiwat/samithiwat-backend-gateway/src/config"
 	"github.com/samithiwat/samithiwat-backend-gateway/src/constant"
 	_ "github.com/samithiwat/samithiwat-backend-gateway/src/docs"
@@ -36,7 +38,7 @@ import (
 // @securityDefinitions.apikey  AuthToken
 // @in                          header
 // @name                        Authorization
-// @description					Description for what is this security definition being used
+// @description					"Bearer <access token>" for the users or "ApiKey <id>.<secret>" for the server to server clients
 
 // @tag.name auth
 // @tag.description.markdown
@@ -50,71 +52,217 @@ import (
 // @tag.name team
 // @tag.description.markdown
 
+// @tag.name role
+// @tag.description.markdown
+
+// @tag.name permission
+// @tag.description.markdown
+
 func main() {
 	conf, err := config.LoadConfig()
 	if err != nil {
 		log.Fatal("Cannot load config", err.Error())
 	}
 
-	v, err := validator.NewValidator()
+	v, err := validator.NewValidatorWithPolicy(newPasswordPolicy(conf.PasswordPolicy))
 	if err != nil {
 		log.Fatal(err.Error())
 	}
 
-	smithConn, err := grpc.Dial(conf.Service.Samithiwat, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	smithConn, err := grpc.Dial(conf.Service.Samithiwat, grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithUnaryInterceptor(service.RequestIDInterceptor))
 	if err != nil {
 		log.Fatal("Cannot connect to samithiwat service: ", err.Error())
 	}
 
+	activitySrv := service.NewActivityService(newActivitySink(conf.Activity))
+
+	contactClient := proto.NewContactServiceClient(smithConn)
+	contactSrv := service.NewContactService(contactClient)
+
+	locationClient := proto.NewLocationServiceClient(smithConn)
+	locationSrv := service.NewLocationService(locationClient)
+
 	userClient := proto.NewUserServiceClient(smithConn)
 	userSrv := service.NewUserService(userClient)
-	userHandler := handler.NewUserHandler(userSrv, v)
+	userHandler := handler.NewUserHandler(userSrv, contactSrv, locationSrv, activitySrv, v)
 
 	teamClient := proto.NewTeamServiceClient(smithConn)
 	teamSrv := service.NewTeamService(teamClient)
-	teamHandler := handler.NewTeamHandler(teamSrv, v)
+
+	permClient := proto.NewPermissionServiceClient(smithConn)
+	permSrv := service.NewPermissionService(permClient)
+	permHandler := handler.NewPermissionHandler(permSrv, v)
+
+	roleClient := proto.NewRoleServiceClient(smithConn)
+	// the upstream cannot find the roles of a user, without the cache the permission guard pages through every role on
+	// every request
+	if conf.RoleCache.TTL <= 0 {
+		log.Fatal("The role cache ttl must be positive")
+	}
+	roleSrv := service.NewCachedRoleService(roleClient, conf.RoleCache.TTL)
+	roleHandler := handler.NewRoleHandler(roleSrv, permSrv, v)
 
 	orgClient := proto.NewOrganizationServiceClient(smithConn)
 	orgSrv := service.NewOrganizationService(orgClient)
-	orgHandler := handler.NewOrganizationHandler(orgSrv, v)
+	orgHandler := handler.NewOrganizationHandler(orgSrv, userSrv, roleSrv, contactSrv, locationSrv, activitySrv, v)
+
+	permGuard := middleware.NewPermissionGuard(roleSrv)
+	ownershipSrv := service.NewOwnershipService(teamSrv, orgSrv)
+	teamHandler := handler.NewTeamHandler(teamSrv, userSrv, activitySrv, ownershipSrv, &permGuard, v)
 
-	authConn, err := grpc.Dial(conf.Service.Auth, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	authConn, err := grpc.Dial(conf.Service.Auth, grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithUnaryInterceptor(service.RequestIDInterceptor))
 	if err != nil {
 		log.Fatal("Cannot connect to auth service: ", err.Error())
 	}
 
 	authClient := proto.NewAuthServiceClient(authConn)
 	authSrv := service.NewAuthService(authClient)
-	authHandler := handler.NewAuthHandler(authSrv, userSrv, v)
+	tokenCache := service.NewTokenCache(authSrv, conf.TokenCache.Size, conf.TokenCache.TTL)
+	mailer := newMailer(conf.Mail)
+	stores := newStores(conf.Store)
+	emailVerifier, verificationChecker := newEmailVerifier(conf.EmailVerification, mailer, stores)
+	lockoutSrv := newLockoutService(conf.LoginThrottle, conf.EmailVerification)
+	twoFactorSrv := newTwoFactorService(conf.TwoFactor, lockoutSrv, stores)
+	sessionSrv := service.NewSessionService(stores.sessions(conf.Sessions.Retention), conf.Sessions.Retention)
+	authHandler := handler.NewAuthHandler(authSrv, handler.AuthDeps{
+		Users:      userSrv,
+		Activity:   activitySrv,
+		TokenCache: tokenCache,
+		Lockout:    lockoutSrv,
+		Verifier:   emailVerifier,
+		TwoFactor:  twoFactorSrv,
+		Sessions:   sessionSrv,
+		Emails:     service.NewUserEmailService(stores.emails()),
+	}, conf.Session, v)
+	twoFactorHandler := handler.NewTwoFactorHandler(twoFactorSrv, userSrv, activitySrv, sessionSrv, conf.Session, v)
+
+	oauthHandler := handler.NewOAuthHandler(newOAuthService(conf.OAuth, authSrv), authSrv, activitySrv, twoFactorSrv, sessionSrv, conf.Session)
+
+	policies := map[string]middleware.Policy{}
+	for _, p := range conf.Policies {
+		policy, err := middleware.NewPolicy(p.Access, p.Permission, p.Owner)
+		if err != nil {
+			log.Fatalf("Invalid policy of %v %v: %v", p.Method, p.Path, err)
+		}
 
-	authGuard := middleware.NewAuthGuard(authSrv, constant.AuthExcludePath)
+		policies[middleware.PolicyKey(p.Method, p.Path)] = policy
+	}
 
-	r := router.NewFiberRouter(authGuard)
+	var apiKeys []*service.ApiKey
+	for _, k := range conf.ApiKeys.Keys {
+		key, err := service.NewApiKey(k.ID, k.Hash, k.Scopes, k.ExpiresAt, k.SigningSecret, k.UserID)
+		if err != nil {
+			log.Fatal("Invalid api key: ", err.Error())
+		}
 
-	r.PostAuth("/register", authHandler.Register)
-	r.PostAuth("/login", authHandler.Login)
-	r.GetAuth("/logout", authHandler.Logout)
-	r.PostAuth("/change-password", authHandler.ChangePassword)
-	r.GetAuth("/me", authHandler.Validate)
-	r.PostAuth("/token", authHandler.RefreshToken)
+		apiKeys = append(apiKeys, key)
+	}
+	apiKeySrv := service.NewApiKeyService(service.NewMemoryApiKeyStore(apiKeys), conf.ApiKeys.SignatureWindow)
 
-	r.GetUser("/", userHandler.FindAll)
-	r.GetUser("/:id", userHandler.FindOne)
-	r.CreateUser("/", userHandler.Create)
-	r.PatchUser("/:id", userHandler.Update)
-	r.DeleteUser("/:id", userHandler.Delete)
+	authGuard := middleware.NewAuthGuard(newTokenValidator(conf.Jwt, tokenCache), permGuard, policies, conf.Session, middleware.AuthGuardOptions{
+		ApiKeys:  apiKeySrv,
+		Owners:   ownershipSrv,
+		Verifier: verificationChecker,
+		Sessions: sessionSrv,
+	})
 
-	r.GetTeam("/", teamHandler.FindAll)
-	r.GetTeam("/:id", teamHandler.FindOne)
-	r.CreateTeam("/", teamHandler.Create)
-	r.PatchTeam("/:id", teamHandler.Update)
-	r.DeleteTeam("/:id", teamHandler.Delete)
+	rateLimiter := middleware.NewRateLimiter(service.NewRateLimitService(newRateLimitStore(conf.RateLimit), newRateLimitPolicies(conf.RateLimit)))
+
+	r := router.NewFiberRouter(authGuard, rateLimiter, conf.Proxy)
+
+	login := r.RateLimit(constant.RateLimitLogin)
+	auth := r.RateLimit(constant.RateLimitAuth)
+	read := r.RateLimit(constant.RateLimitRead)
+	write := r.RateLimit(constant.RateLimitWrite)
+
+	r.PostAuth("/register", authHandler.Register, middleware.Public(), login)
+	r.PostAuth("/login", authHandler.Login, middleware.Public(), login)
+	r.GetAuth("/logout", authHandler.Logout, middleware.Authenticated(), auth)
+	r.PostAuth("/logout-all", authHandler.LogoutAll, middleware.Authenticated(), auth)
+	r.GetAuth("/sessions", authHandler.ListSessions, middleware.Authenticated(), auth)
+	r.DeleteAuth("/sessions/:id", authHandler.RevokeSession, middleware.Authenticated(), auth)
+	r.PostAuth("/change-password", authHandler.ChangePassword, middleware.Authenticated(), auth)
+	r.GetAuth("/me", authHandler.Validate, middleware.Authenticated(), auth)
+	r.GetAuth("/verify-email", authHandler.VerifyEmail, middleware.Public(), auth)
+	r.PostAuth("/resend-verification", authHandler.ResendVerification, middleware.Authenticated(), auth)
+	r.PostTwoFactor("/2fa/setup", twoFactorHandler.Setup, middleware.Authenticated(), auth)
+	r.PostTwoFactor("/2fa/confirm", twoFactorHandler.Confirm, middleware.Authenticated(), auth)
+	r.PostTwoFactor("/2fa/verify", twoFactorHandler.Verify, middleware.Public(), login)
+	r.PostTwoFactor("/2fa/disable", twoFactorHandler.Disable, middleware.Authenticated(), auth)
+	r.PostAuth("/token", authHandler.RefreshToken, middleware.Public(), login)
+	r.GetAuth("/token-cache", authHandler.TokenCacheStats, middleware.RequirePermission("auth:token-cache"), auth)
+	r.GetOAuth("/oauth/:provider/login", oauthHandler.Login, middleware.Public(), auth)
+	r.GetOAuth("/oauth/:provider/callback", oauthHandler.Callback, middleware.Public(), auth)
+
+	r.Resource("/user", &router.Resource{
+		List:   router.Handle(userHandler.FindAll, middleware.Authenticated(), read),
+		Get:    router.Handle(userHandler.FindOne, middleware.Public(), read),
+		Create: router.Handle(userHandler.Create, middleware.RequirePermission("user:create"), write),
+		Update: router.Handle(userHandler.Update, middleware.RequireOwner(constant.ResourceUser, "user:update"), write),
+		Delete: router.Handle(userHandler.Delete, middleware.RequireOwner(constant.ResourceUser, "user:delete"), write),
+		Routes: []*router.Route{
+			router.On(http.MethodGet, "/:id/contact", userHandler.FindContact, middleware.Public(), read),
+			router.On(http.MethodPut, "/:id/contact", userHandler.UpdateContact, middleware.RequireOwner(constant.ResourceUser, "user:update"), write),
+			router.On(http.MethodGet, "/:id/address", userHandler.FindAddress, middleware.RequireOwner(constant.ResourceUser, "user:update"), read),
+			router.On(http.MethodGet, "/:id/activity", userHandler.FindActivity, middleware.RequireOwner(constant.ResourceUser, "user:update"), read),
+			router.On(http.MethodPut, "/:id/address", userHandler.UpdateAddress, middleware.RequireOwner(constant.ResourceUser, "user:update"), write),
+		},
+	})
+
+	r.Resource("/team", &router.Resource{
+		List:   router.Handle(teamHandler.FindAll, middleware.Authenticated(), read),
+		Get:    router.Handle(teamHandler.FindOne, middleware.Public(), read),
+		Create: router.Handle(teamHandler.Create, middleware.RequirePermission("team:create"), write),
+		Update: router.Handle(teamHandler.Update, middleware.RequireOwner(constant.ResourceTeam, "team:update"), write),
+		Delete: router.Handle(teamHandler.Delete, middleware.RequirePermission("team:delete"), write),
+		Routes: []*router.Route{
+			router.On(http.MethodGet, "/:id/member", teamHandler.FindMembers, middleware.Public(), read),
+			router.On(http.MethodGet, "/:id/tree", teamHandler.FindTree, middleware.Public(), read),
+			router.On(http.MethodGet, "/:id/activity", teamHandler.FindActivity, middleware.Authenticated(), read),
+			router.On(http.MethodPost, "/:id/member", teamHandler.AddMember, middleware.RequirePermission("team:update"), write),
+			router.On(http.MethodDelete, "/:id/member/:userId", teamHandler.RemoveMember, middleware.RequirePermission("team:update"), write),
+		},
+	})
+
+	r.Resource("/organization", &router.Resource{
+		List:   router.Handle(orgHandler.FindAll, middleware.Public(), read),
+		Get:    router.Handle(orgHandler.FindOne, middleware.Public(), read),
+		Create: router.Handle(orgHandler.Create, middleware.RequirePermission("organization:create"), write),
+		Update: router.Handle(orgHandler.Update, middleware.RequireOwner(constant.ResourceOrganization, "organization:update"), write),
+		Delete: router.Handle(orgHandler.Delete, middleware.RequirePermission("organization:delete"), write),
+		Routes: []*router.Route{
+			router.On(http.MethodGet, "/:id/contact", orgHandler.FindContact, middleware.Public(), read),
+			router.On(http.MethodPut, "/:id/contact", orgHandler.UpdateContact, middleware.RequireOwner(constant.ResourceOrganization, "organization:update"), write),
+			router.On(http.MethodGet, "/:id/location", orgHandler.FindLocation, middleware.Public(), read),
+			router.On(http.MethodPut, "/:id/location", orgHandler.UpdateLocation, middleware.RequireOwner(constant.ResourceOrganization, "organization:update"), write),
+			router.On(http.MethodGet, "/:id/member", orgHandler.FindMembers, middleware.Public(), read),
+			router.On(http.MethodGet, "/:id/teams", orgHandler.FindTeams, middleware.Public(), read),
+			router.On(http.MethodGet, "/:id/activity", orgHandler.FindActivity, middleware.Authenticated(), read),
+			router.On(http.MethodPost, "/:id/member", orgHandler.AddMember, middleware.RequirePermission("organization:update"), write),
+			router.On(http.MethodDelete, "/:id/member/:userId", orgHandler.RemoveMember, middleware.RequirePermission("organization:update"), write),
+			router.On(http.MethodPut, "/:id/member/:userId/role", orgHandler.AssignMemberRole, middleware.RequirePermission("organization:update"), write),
+		},
+	})
+
+	r.Resource("/role", &router.Resource{
+		List:   router.Handle(roleHandler.FindAll, middleware.Authenticated(), read),
+		Get:    router.Handle(roleHandler.FindOne, middleware.Authenticated(), read),
+		Create: router.Handle(roleHandler.Create, middleware.RequirePermission("role:create"), write),
+		Update: router.Handle(roleHandler.Update, middleware.RequirePermission("role:update"), write),
+		Delete: router.Handle(roleHandler.Delete, middleware.RequirePermission("role:delete"), write),
+		Routes: []*router.Route{
+			router.On(http.MethodPost, "/:id/permission/:permissionId", roleHandler.AddPermission, middleware.RequirePermission("role:update"), write),
+			router.On(http.MethodDelete, "/:id/permission/:permissionId", roleHandler.RemovePermission, middleware.RequirePermission("role:update"), write),
+		},
+	})
 
-	r.GetOrganization("/", orgHandler.FindAll)
-	r.GetOrganization("/:id", orgHandler.FindOne)
-	r.CreateOrganization("/", orgHandler.Create)
-	r.PatchOrganization("/:id", orgHandler.Update)
-	r.DeleteOrganization("/:id", orgHandler.Delete)
+	r.Resource("/permission", &router.Resource{
+		List:   router.Handle(permHandler.FindAll, middleware.Authenticated(), read),
+		Get:    router.Handle(permHandler.FindOne, middleware.Authenticated(), read),
+		Create: router.Handle(permHandler.Create, middleware.RequirePermission("permission:create"), write),
+		Update: router.Handle(permHandler.Update, middleware.RequirePermission("permission:update"), write),
+		Delete: router.Handle(permHandler.Delete, middleware.RequirePermission("permission:delete"), write),
+	})
 
 	go func() {
 		if err := r.Listen(fmt.Sprintf(":%v", conf.App.Port)); err != nil && err != http.ErrServerClosed {
@@ -131,6 +279,291 @@ func main() {
 	<-wait
 }
 
+func newActivitySink(conf config.Activity) service.ActivitySink {
+	switch conf.Sink {
+	case "memory":
+		return service.NewMemoryActivitySink(conf.Retain)
+	case "", "log":
+		// the log cannot be read back so the latest logs are also kept for /user/:id/activity
+		return service.MultiActivitySink{service.LogActivitySink{}, service.NewMemoryActivitySink(conf.Retain)}
+	default:
+		log.Fatalf("Unknown activity sink: %v", conf.Sink)
+		return nil
+	}
+}
+
+func newMailer(conf config.Mail) service.Mailer {
+	switch conf.Driver {
+	case "smtp":
+		return service.NewSmtpMailer(conf.Smtp.Host, conf.Smtp.Port, conf.Smtp.Username, conf.Smtp.Password, conf.From)
+	case "file":
+		return service.NewFileMailer(conf.File, conf.From)
+	case "", "log":
+		return service.LogMailer{}
+	default:
+		log.Fatalf("Unknown mail driver: %v", conf.Driver)
+		return nil
+	}
+}
+
+func newTokenValidator(conf config.Jwt, remote service.FallbackValidator) middleware.TokenValidator {
+	if conf.Mode == "" || conf.Mode == "remote" {
+		return remote
+	}
+
+	var keys []*service.JwtKey
+	for _, k := range conf.Keys {
+		key, err := service.NewJwtKey(k.Kid, k.Alg, k.Secret, k.PublicKey)
+		if err != nil {
+			log.Fatal("Invalid jwt key: ", err.Error())
+		}
+
+		keys = append(keys, key)
+	}
+
+	refreshInterval := conf.JwksRefreshInterval
+	if refreshInterval <= 0 {
+		refreshInterval = 10 * time.Minute
+	}
+
+	jwtSrv := service.NewJwtService(service.NewKeySet(keys, conf.JwksUrl, refreshInterval), service.JwtOptions{
+		Issuer:      conf.Issuer,
+		Audience:    conf.Audience,
+		ClockSkew:   conf.ClockSkew,
+		UserIDClaim: conf.UserIDClaim,
+	})
+
+	switch conf.Mode {
+	case "local":
+		return jwtSrv
+	case "hybrid":
+		return service.NewHybridTokenService(jwtSrv, remote)
+	default:
+		log.Fatalf("Unknown jwt mode: %v", conf.Mode)
+		return nil
+	}
+}
+
+func newLockoutService(conf config.LoginThrottle, verification config.EmailVerification) *service.LockoutService {
+	policy := func(maxFailures int) service.LockoutPolicy {
+		return service.LockoutPolicy{
+			Window:      conf.Window,
+			MaxFailures: maxFailures,
+			BaseLockout: conf.BaseLockout,
+			MaxLockout:  conf.MaxLockout,
+		}
+	}
+
+	return service.NewLockoutService(service.NewMemoryLockoutStore(), map[string]service.LockoutPolicy{
+		constant.LockoutLoginEmail:    policy(conf.MaxFailuresPerEmail),
+		constant.LockoutLoginIP:       policy(conf.MaxFailuresPerIP),
+		constant.LockoutRefreshIP:     policy(conf.MaxRefreshFailuresPerIP),
+		constant.LockoutTwoFactorUser: policy(conf.MaxTwoFactorFailures),
+		constant.LockoutResendVerification: {
+			Window:      verification.ResendWindow,
+			MaxFailures: verification.MaxResends,
+			BaseLockout: verification.ResendWindow,
+			MaxLockout:  verification.ResendWindow,
+		},
+	})
+}
+
+// newEmailVerifier returns the checker of the auth guard only when the verification is required, the required
+// verification needs the redis store because the users that are not pending are verified and the pending
+// verifications in memory are lost on a restart
+func newEmailVerifier(conf config.EmailVerification, mailer service.Mailer, stores *stores) (handler.EmailVerifier, middleware.VerificationChecker) {
+	if !conf.Enabled {
+		if conf.Required {
+			log.Fatal("The required email verification must be enabled")
+		}
+
+		return service.DisabledVerification{}, nil
+	}
+
+	if conf.Secret == "" {
+		log.Fatal("The email verification requires the secret")
+	}
+
+	if conf.Required && !stores.persistent() {
+		log.Fatal("The required email verification needs the redis store")
+	}
+
+	verificationSrv := service.NewEmailVerificationService(stores.verification(), mailer, conf.Secret, conf.TTL, conf.Url)
+	if !conf.Required {
+		return verificationSrv, nil
+	}
+
+	return verificationSrv, verificationSrv
+}
+
+// newTwoFactorService disables the two-factor authentication when the encryption key is not configured, the enrollments
+// need the redis store because a user whose enrollment is lost would log in with the password alone
+func newTwoFactorService(conf config.TwoFactor, lockout service.TwoFactorLockout, stores *stores) handler.TwoFactorService {
+	if conf.EncryptionKey == "" {
+		return service.DisabledTwoFactor{}
+	}
+
+	if !stores.persistent() {
+		log.Fatal("The two-factor authentication needs the redis store")
+	}
+
+	key, err := base64.StdEncoding.DecodeString(conf.EncryptionKey)
+	if err != nil {
+		log.Fatal("The two-factor encryption key must be base64 encoded: ", err.Error())
+	}
+
+	twoFactorSrv, err := service.NewTwoFactorService(stores.totp(), lockout, key, conf.Issuer, conf.ChallengeTTL, conf.RecoveryCodes)
+	if err != nil {
+		log.Fatal("Invalid two-factor config: ", err.Error())
+	}
+
+	return twoFactorSrv
+}
+
+// newRateLimitStore keeps the buckets in memory unless the redis backend is configured
+func newRateLimitStore(conf config.RateLimit) service.RateLimitStore {
+	switch conf.Backend {
+	case "memory":
+		return service.NewMemoryRateLimitStore()
+	case "redis":
+		return service.NewRedisRateLimitStore(newRedisClient(conf.Redis), conf.Redis.Prefix)
+	default:
+		log.Fatalf("Unknown rate limit backend: %v", conf.Backend)
+		return nil
+	}
+}
+
+func newRedisClient(conf config.Redis) *redis.Client {
+	return redis.NewClient(&redis.Options{
+		Addr:         conf.Addr,
+		Password:     conf.Password,
+		DB:           conf.DB,
+		PoolSize:     conf.PoolSize,
+		DialTimeout:  conf.Timeout,
+		ReadTimeout:  conf.Timeout,
+		WriteTimeout: conf.Timeout,
+	})
+}
+
+// stores builds the stores of the state that must survive a restart on the backend of the config, the client is nil
+// when they are kept in memory
+type stores struct {
+	client *redis.Client
+	prefix string
+}
+
+func newStores(conf config.Store) *stores {
+	switch conf.Backend {
+	case "memory":
+		return &stores{}
+	case "redis":
+		return &stores{client: newRedisClient(conf.Redis), prefix: conf.Redis.Prefix}
+	default:
+		log.Fatalf("Unknown store backend: %v", conf.Backend)
+		return nil
+	}
+}
+
+func (s *stores) persistent() bool {
+	return s.client != nil
+}
+
+func (s *stores) verification() service.VerificationStore {
+	if !s.persistent() {
+		return service.NewMemoryVerificationStore()
+	}
+
+	return service.NewRedisVerificationStore(s.client, s.prefix)
+}
+
+func (s *stores) totp() service.TotpStore {
+	if !s.persistent() {
+		return service.NewMemoryTotpStore()
+	}
+
+	return service.NewRedisTotpStore(s.client, s.prefix)
+}
+
+// sessions are only checked by the instance that issued the credential when they are kept in memory, the tokens issued
+// by another instance or before a restart are rejected
+func (s *stores) sessions(retention time.Duration) service.SessionStore {
+	if !s.persistent() {
+		return service.NewMemorySessionStore()
+	}
+
+	return service.NewRedisSessionStore(s.client, s.prefix, retention)
+}
+
+func (s *stores) emails() service.UserEmailStore {
+	if !s.persistent() {
+		return service.NewMemoryUserEmailStore()
+	}
+
+	return service.NewRedisUserEmailStore(s.client, s.prefix)
+}
+
+func newRateLimitPolicies(conf config.RateLimit) map[string]service.RateLimitPolicy {
+	policies := map[string]service.RateLimitPolicy{}
+	for name, policy := range conf.Policies {
+		policies[name] = service.RateLimitPolicy{
+			Limit:  policy.Limit,
+			Period: policy.Period,
+		}
+	}
+
+	return policies
+}
+
+// newPasswordPolicy loads the breached password list when it is configured
+func newPasswordPolicy(conf config.PasswordPolicy) *validator.PasswordPolicy {
+	policy := &validator.PasswordPolicy{
+		MinLength:     conf.MinLength,
+		MaxLength:     conf.MaxLength,
+		RequireUpper:  conf.RequireUpper,
+		RequireLower:  conf.RequireLower,
+		RequireDigit:  conf.RequireDigit,
+		RequireSymbol: conf.RequireSymbol,
+	}
+
+	if conf.BreachedList != "" {
+		breached, err := validator.LoadBreachedPasswords(conf.BreachedList, conf.BreachedFalsePositive)
+		if err != nil {
+			log.Fatal("Cannot load the breached password list: ", err.Error())
+		}
+
+		policy.Breached = breached
+	}
+
+	return policy
+}
+
+func newOAuthService(conf config.OAuth, accounts service.AccountService) *service.OAuthService {
+	if len(conf.Providers) > 0 && conf.AccountSecret == "" {
+		log.Fatal("The oauth providers require the account secret")
+	}
+
+	var providers []service.OAuthProvider
+	for _, p := range conf.Providers {
+		if p.Name == "" || p.Issuer == "" || p.ClientID == "" || p.RedirectUrl == "" {
+			log.Fatalf("The oauth provider %q requires the name, issuer, client id and redirect url", p.Name)
+		}
+
+		providers = append(providers, service.OAuthProvider{
+			Name:             p.Name,
+			Issuer:           p.Issuer,
+			ClientID:         p.ClientID,
+			ClientSecret:     p.ClientSecret,
+			RedirectUrl:      p.RedirectUrl,
+			Scopes:           p.Scopes,
+			AuthorizationUrl: p.AuthorizationUrl,
+			TokenUrl:         p.TokenUrl,
+			JwksUrl:          p.JwksUrl,
+		})
+	}
+
+	return service.NewOAuthService(providers, accounts, conf.AccountSecret, conf.StateTTL)
+}
+
 type operation func(ctx context.Context) error
 
 func gracefulShutdown(ctx context.Context, timeout time.Duration, ops map[string]operation) <-chan struct{} {
@@ -1,51 +1,154 @@
 package middleware
 
 import (
-	"github.com/samithiwat/samithiwat-backend-gateway/src/common"
+	"context"
+	"crypto/subtle"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/config"
 	"github.com/samithiwat/samithiwat-backend-gateway/src/dto"
-	"github.com/samithiwat/samithiwat-backend-gateway/src/handler"
 	"net/http"
 	"strconv"
+	"strings"
 )
 
 type AuthGuard struct {
-	service  handler.AuthService
-	excludes map[string]struct{}
+	service    TokenValidator
+	permission PermissionGuard
+	overrides  map[string]Policy
+	session    config.Session
+	apiKeys    ApiKeyAuthenticator
+	owners     OwnerResolver
+	verifier   VerificationChecker
+	sessions   SessionAuthenticator
 }
 
+// TokenValidator returns the id of the user that owns the token, the AuthService asks the auth service and the JwtService verifies it locally
+type TokenValidator interface {
+	Validate(context.Context, string) (uint32, *dto.ResponseErr)
+}
+
+// ApiKeyAuthenticator checks the credential of the ApiKey scheme and the signature of the request when the key requires one
+type ApiKeyAuthenticator interface {
+	Authenticate(string, *dto.SignedRequest) (*dto.ApiClient, *dto.ResponseErr)
+}
+
+// OwnerResolver tells whether the user owns the resource with the id, the resource is one of the constant.Resource values
+type OwnerResolver interface {
+	IsOwner(context.Context, string, int32, int32) (bool, *dto.ResponseErr)
+}
+
+// VerificationChecker tells whether the user verified its email
+type VerificationChecker interface {
+	IsVerified(uint32) (bool, *dto.ResponseErr)
+}
+
+// SessionAuthenticator returns the id of the session of the access token, it fails when the token has no session or the
+// session is revoked
+type SessionAuthenticator interface {
+	Authenticate(context.Context, string) (string, *dto.ResponseErr)
+}
+
+const (
+	SchemeBearer = "bearer"
+	SchemeApiKey = "apikey"
+
+	SignatureHeader          = "X-Signature"
+	SignatureTimestampHeader = "X-Signature-Timestamp"
+)
+
 type AuthContext interface {
+	UserContext() context.Context
 	Token() string
+	GetCookie(string) string
+	RequestHeader(string) string
 	Method() string
-	Path() string
+	RequestURI() string
+	Body() []byte
+	RoutePath() string
+	ID() (int32, error)
 	StoreValue(string, string)
 	JSON(int, interface{})
 	Next()
 }
 
-func NewAuthGuard(s handler.AuthService, e map[string]struct{}) AuthGuard {
+// AuthGuardOptions are the optional checks of the AuthGuard, the ApiKey scheme, the email verification and the sessions are
+// skipped when they are nil, the owners are needed by the routes with the owner policy
+type AuthGuardOptions struct {
+	ApiKeys  ApiKeyAuthenticator
+	Owners   OwnerResolver
+	Verifier VerificationChecker
+	Sessions SessionAuthenticator
+}
+
+// NewAuthGuard creates the guard of the routes, the overrides are keyed by PolicyKey and take precedence over the policy declared by the route,
+// the users that did not verify their email are rejected outside /auth when the verifier is not nil
+func NewAuthGuard(s TokenValidator, p PermissionGuard, overrides map[string]Policy, session config.Session, opts AuthGuardOptions) AuthGuard {
 	return AuthGuard{
-		service:  s,
-		excludes: e,
+		service:    s,
+		permission: p,
+		overrides:  overrides,
+		session:    session,
+		apiKeys:    opts.ApiKeys,
+		owners:     opts.Owners,
+		verifier:   opts.Verifier,
+		sessions:   opts.Sessions,
 	}
 }
 
-func (m *AuthGuard) Validate(ctx AuthContext) {
-	method := ctx.Method()
-	path := ctx.Path()
+// Validate returns the middleware of the route that declared the policy
+func (m *AuthGuard) Validate(policy Policy) func(ctx AuthContext) {
+	return func(ctx AuthContext) {
+		m.validate(ctx, m.resolve(ctx, policy))
+	}
+}
 
-	var id int32
-	ids := common.FindIntFromStr(path)
-	if len(ids) > 0 {
-		id = ids[0]
+func (m *AuthGuard) resolve(ctx AuthContext, policy Policy) Policy {
+	if override, ok := m.overrides[PolicyKey(ctx.Method(), ctx.RoutePath())]; ok {
+		return override
 	}
 
-	path = common.FormatPath(method, path, id)
-	if common.IsExisted(m.excludes, path) {
+	return policy
+}
+
+func (m *AuthGuard) validate(ctx AuthContext, policy Policy) {
+	if policy.Access == AccessPublic {
 		ctx.Next()
 		return
 	}
 
-	token := ctx.Token()
+	header := ctx.Token()
+	if header == "" {
+		m.validateCookie(ctx, policy)
+		return
+	}
+
+	scheme, credential, ok := ParseAuthorization(header)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, &dto.ResponseErr{
+			StatusCode: http.StatusUnauthorized,
+			Message:    "Invalid authorization header",
+		})
+		return
+	}
+
+	switch scheme {
+	case SchemeBearer:
+		m.validateBearer(ctx, policy, credential)
+	case SchemeApiKey:
+		m.validateApiKey(ctx, policy, credential)
+	default:
+		ctx.JSON(http.StatusUnauthorized, &dto.ResponseErr{
+			StatusCode: http.StatusUnauthorized,
+			Message:    "Unsupported authorization scheme",
+		})
+	}
+}
+
+func (m *AuthGuard) validateCookie(ctx AuthContext, policy Policy) {
+	token := ""
+	if m.session.CookieMode() {
+		token = ctx.GetCookie(m.session.AccessCookie)
+	}
+
 	if token == "" {
 		ctx.JSON(http.StatusUnauthorized, &dto.ResponseErr{
 			StatusCode: http.StatusUnauthorized,
@@ -54,12 +157,228 @@ func (m *AuthGuard) Validate(ctx AuthContext) {
 		return
 	}
 
-	userId, errRes := m.service.Validate(token)
+	// the browser sends the cookie on its own so the unsafe methods must prove they come from our client
+	if !isSafeMethod(ctx.Method()) && !m.validCsrf(ctx) {
+		ctx.JSON(http.StatusForbidden, &dto.ResponseErr{
+			StatusCode: http.StatusForbidden,
+			Message:    "Invalid CSRF token",
+		})
+		return
+	}
+
+	m.validateBearer(ctx, policy, token)
+}
+
+func (m *AuthGuard) validateBearer(ctx AuthContext, policy Policy, token string) {
+	userId, errRes := m.service.Validate(ctx.UserContext(), token)
 	if errRes != nil {
 		ctx.JSON(errRes.StatusCode, errRes)
 		return
 	}
 
+	sessionId := ""
+	if m.sessions != nil {
+		// the token is valid upstream until it expires so the revoked sessions are rejected here
+		sessionId, errRes = m.sessions.Authenticate(ctx.UserContext(), token)
+		if errRes != nil {
+			ctx.JSON(errRes.StatusCode, errRes)
+			return
+		}
+	}
+
+	if !m.verified(ctx, userId) || !m.authorizeUser(ctx, policy, int32(userId)) {
+		return
+	}
+
 	ctx.StoreValue("UserId", strconv.Itoa(int(userId)))
+	if sessionId != "" {
+		ctx.StoreValue("SessionId", sessionId)
+	}
+	ctx.Next()
+}
+
+// verified responds the error and returns false when the user did not verify its email, the /auth routes let the user through
+// so it can still verify, ask for the link again and log out
+func (m *AuthGuard) verified(ctx AuthContext, userId uint32) bool {
+	if m.verifier == nil || isAuthRoute(ctx.RoutePath()) {
+		return true
+	}
+
+	ok, errRes := m.verifier.IsVerified(userId)
+	if errRes != nil {
+		ctx.JSON(errRes.StatusCode, errRes)
+		return false
+	}
+
+	if !ok {
+		ctx.JSON(http.StatusForbidden, &dto.ResponseErr{
+			StatusCode: http.StatusForbidden,
+			Message:    "Email is not verified",
+		})
+		return false
+	}
+
+	return true
+}
+
+// authorizeUser responds the error and returns false when the user cannot access the route
+func (m *AuthGuard) authorizeUser(ctx AuthContext, policy Policy, userId int32) bool {
+	switch policy.Access {
+	case AccessPermission:
+		return m.requirePermission(ctx, policy, userId)
+	case AccessOwner:
+		owner, errRes := m.isOwner(ctx, policy, userId)
+		if errRes != nil {
+			ctx.JSON(errRes.StatusCode, errRes)
+			return false
+		}
+
+		if owner {
+			return true
+		}
+
+		// the admins manage the resources of the others with the permission of the route
+		if !m.requirePermission(ctx, policy, userId) {
+			return false
+		}
+
+		ctx.StoreValue("Admin", "true")
+		return true
+	default:
+		return true
+	}
+}
+
+func (m *AuthGuard) requirePermission(ctx AuthContext, policy Policy, userId int32) bool {
+	ok, errRes := m.permission.HasPermission(ctx.UserContext(), userId, policy.Permission)
+	if errRes != nil {
+		ctx.JSON(errRes.StatusCode, errRes)
+		return false
+	}
+
+	if !ok {
+		m.insufficientPermission(ctx, policy)
+		return false
+	}
+
+	return true
+}
+
+func (m *AuthGuard) isOwner(ctx AuthContext, policy Policy, userId int32) (bool, *dto.ResponseErr) {
+	id, err := ctx.ID()
+	if err != nil {
+		return false, &dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Invalid ID",
+		}
+	}
+
+	return m.owners.IsOwner(ctx.UserContext(), policy.Owner, id, userId)
+}
+
+func (m *AuthGuard) validateApiKey(ctx AuthContext, policy Policy, credential string) {
+	if m.apiKeys == nil {
+		ctx.JSON(http.StatusUnauthorized, &dto.ResponseErr{
+			StatusCode: http.StatusUnauthorized,
+			Message:    "Unsupported authorization scheme",
+		})
+		return
+	}
+
+	client, errRes := m.apiKeys.Authenticate(credential, &dto.SignedRequest{
+		Method:    ctx.Method(),
+		URI:       ctx.RequestURI(),
+		Body:      ctx.Body(),
+		Timestamp: ctx.RequestHeader(SignatureTimestampHeader),
+		Signature: ctx.RequestHeader(SignatureHeader),
+	})
+	if errRes != nil {
+		ctx.JSON(errRes.StatusCode, errRes)
+		return
+	}
+
+	// the api key clients are not users, their scopes are the permission codes they are allowed to use
+	switch policy.Access {
+	case AccessPermission:
+		if !client.HasScope(policy.Permission) {
+			m.insufficientPermission(ctx, policy)
+			return
+		}
+	case AccessOwner:
+		if client.HasScope(policy.Permission) {
+			ctx.StoreValue("Admin", "true")
+		} else if !m.clientOwns(ctx, policy, client) {
+			return
+		}
+	}
+
+	ctx.StoreValue("ApiKeyId", client.ID)
+	if client.UserID > 0 {
+		ctx.StoreValue("UserId", strconv.Itoa(int(client.UserID)))
+	}
 	ctx.Next()
 }
+
+// clientOwns checks the ownership of the user that the client acts for, it responds the error when the client does not own the resource
+func (m *AuthGuard) clientOwns(ctx AuthContext, policy Policy, client *dto.ApiClient) bool {
+	if client.UserID <= 0 {
+		m.insufficientPermission(ctx, policy)
+		return false
+	}
+
+	owner, errRes := m.isOwner(ctx, policy, client.UserID)
+	if errRes != nil {
+		ctx.JSON(errRes.StatusCode, errRes)
+		return false
+	}
+
+	if !owner {
+		m.insufficientPermission(ctx, policy)
+		return false
+	}
+
+	return true
+}
+
+func (m *AuthGuard) insufficientPermission(ctx AuthContext, policy Policy) {
+	ctx.JSON(http.StatusForbidden, &dto.ResponseErr{
+		StatusCode: http.StatusForbidden,
+		Message:    "Insufficient permission",
+		Data:       policy.Permission,
+	})
+}
+
+func (m *AuthGuard) validCsrf(ctx AuthContext) bool {
+	cookie := ctx.GetCookie(m.session.CsrfCookie)
+	header := ctx.RequestHeader(m.session.CsrfHeader)
+
+	return cookie != "" && subtle.ConstantTimeCompare([]byte(cookie), []byte(header)) == 1
+}
+
+func isAuthRoute(path string) bool {
+	return path == "/auth" || strings.HasPrefix(path, "/auth/")
+}
+
+func isSafeMethod(method string) bool {
+	switch method {
+	case "GET", "HEAD", "OPTIONS":
+		return true
+	default:
+		return false
+	}
+}
+
+// ParseAuthorization splits the Authorization header into the lower cased scheme and the credential
+func ParseAuthorization(header string) (string, string, bool) {
+	parts := strings.SplitN(strings.TrimSpace(header), " ", 2)
+	if len(parts) != 2 {
+		return "", "", false
+	}
+
+	credential := strings.TrimSpace(parts[1])
+	if parts[0] == "" || credential == "" {
+		return "", "", false
+	}
+
+	return strings.ToLower(parts[0]), credential, true
+}
@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"context"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/dto"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/proto"
+	"net/http"
+)
+
+type PermissionGuard struct {
+	service RoleService
+}
+
+type PermissionContext interface {
+	UserID() int32
+	UserContext() context.Context
+	JSON(int, interface{})
+	Next()
+}
+
+type RoleService interface {
+	FindByUser(context.Context, int32) ([]*proto.Role, *dto.ResponseErr)
+}
+
+func NewPermissionGuard(s RoleService) PermissionGuard {
+	return PermissionGuard{
+		service: s,
+	}
+}
+
+// Require returns the middleware that only let the caller through when one of its roles has the permission code
+func (m *PermissionGuard) Require(code string) func(ctx PermissionContext) {
+	return func(ctx PermissionContext) {
+		userId := ctx.UserID()
+		if userId <= 0 {
+			ctx.JSON(http.StatusUnauthorized, &dto.ResponseErr{
+				StatusCode: http.StatusUnauthorized,
+				Message:    "Invalid token",
+			})
+			return
+		}
+
+		ok, errRes := m.HasPermission(ctx.UserContext(), userId, code)
+		if errRes != nil {
+			ctx.JSON(errRes.StatusCode, errRes)
+			return
+		}
+
+		if !ok {
+			ctx.JSON(http.StatusForbidden, &dto.ResponseErr{
+				StatusCode: http.StatusForbidden,
+				Message:    "Insufficient permission",
+				Data:       code,
+			})
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+func (m *PermissionGuard) HasPermission(ctx context.Context, userId int32, code string) (bool, *dto.ResponseErr) {
+	roles, errRes := m.service.FindByUser(ctx, userId)
+	if errRes != nil {
+		return false, errRes
+	}
+
+	for _, role := range roles {
+		for _, perm := range role.Permissions {
+			if perm.Code == code {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"fmt"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/constant"
+	"strings"
+)
+
+type Access string
+
+const (
+	// AccessPublic lets everyone through without a token
+	AccessPublic Access = "public"
+	// AccessAuthenticated requires a valid token
+	AccessAuthenticated Access = "authenticated"
+	// AccessPermission requires a valid token and a role that has the permission code
+	AccessPermission Access = "permission"
+	// AccessOwner requires a valid token of the owner of the resource in the :id param, other callers need the permission code
+	AccessOwner Access = "owner"
+)
+
+// Policy is the access level that a route declares when it is registered, Owner is the resource that the owner access resolves
+type Policy struct {
+	Access     Access
+	Permission string
+	Owner      string
+}
+
+func Public() Policy {
+	return Policy{Access: AccessPublic}
+}
+
+func Authenticated() Policy {
+	return Policy{Access: AccessAuthenticated}
+}
+
+func RequirePermission(code string) Policy {
+	return Policy{Access: AccessPermission, Permission: code}
+}
+
+// RequireOwner lets the owner of the resource through, everyone else needs the admin permission
+func RequireOwner(resource string, adminPermission string) Policy {
+	return Policy{Access: AccessOwner, Permission: adminPermission, Owner: resource}
+}
+
+// NewPolicy builds a policy from its raw form, it is used for the policies that are loaded from the config
+func NewPolicy(access string, permission string, owner string) (Policy, error) {
+	switch Access(access) {
+	case AccessPublic, AccessAuthenticated:
+		if permission != "" {
+			return Policy{}, fmt.Errorf("%v access does not take a permission", access)
+		}
+	case AccessPermission:
+		if permission == "" {
+			return Policy{}, fmt.Errorf("permission access requires a permission code")
+		}
+	case AccessOwner:
+		if permission == "" {
+			return Policy{}, fmt.Errorf("owner access requires the permission code of the admins")
+		}
+
+		switch owner {
+		case constant.ResourceUser, constant.ResourceTeam, constant.ResourceOrganization:
+		default:
+			return Policy{}, fmt.Errorf("unknown owner resource %q", owner)
+		}
+	default:
+		return Policy{}, fmt.Errorf("unknown access %q", access)
+	}
+
+	if Access(access) != AccessOwner && owner != "" {
+		return Policy{}, fmt.Errorf("%v access does not take an owner", access)
+	}
+
+	return Policy{Access: Access(access), Permission: permission, Owner: owner}, nil
+}
+
+// PolicyKey is the key of the route in the policy overrides, the path is the route pattern (e.g. /user/:id) not the request path
+func PolicyKey(method string, path string) string {
+	if len(path) > 1 {
+		path = strings.TrimRight(path, "/")
+	}
+
+	return fmt.Sprintf("%v %v", strings.ToUpper(method), path)
+}
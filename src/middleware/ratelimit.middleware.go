@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"fmt"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/dto"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type RateLimitService interface {
+	Take(string, string) *dto.RateLimit
+}
+
+type RateLimitContext interface {
+	Principal() *dto.Principal
+	IP() string
+	SetResponseHeader(string, string)
+	JSON(int, interface{})
+	Next()
+}
+
+type RateLimiter struct {
+	service RateLimitService
+}
+
+func NewRateLimiter(s RateLimitService) *RateLimiter {
+	return &RateLimiter{service: s}
+}
+
+// Limit takes a token of the caller from the bucket of the policy, it runs after the auth guard so a user or an api
+// key client has its own bucket wherever it calls from and the anonymous callers are limited by their ip
+func (m *RateLimiter) Limit(policy string) func(ctx RateLimitContext) {
+	return func(ctx RateLimitContext) {
+		m.take(ctx, policy, rateLimitKey(ctx))
+	}
+}
+
+// LimitClient takes a token of the client address from the bucket of the policy whoever the caller claims to be, it
+// runs before the auth guard so the requests with invalid credentials are limited too
+func (m *RateLimiter) LimitClient(policy string) func(ctx RateLimitContext) {
+	return func(ctx RateLimitContext) {
+		m.take(ctx, policy, "ip:"+ctx.IP())
+	}
+}
+
+func (m *RateLimiter) take(ctx RateLimitContext, policy string, key string) {
+	state := m.service.Take(policy, key)
+	if state == nil {
+		ctx.Next()
+		return
+	}
+
+	ctx.SetResponseHeader("RateLimit-Limit", strconv.Itoa(state.Limit))
+	ctx.SetResponseHeader("RateLimit-Remaining", strconv.Itoa(state.Remaining))
+	ctx.SetResponseHeader("RateLimit-Reset", strconv.Itoa(seconds(state.Reset)))
+
+	if !state.Allowed {
+		ctx.SetResponseHeader("Retry-After", strconv.Itoa(seconds(state.RetryAfter)))
+		ctx.JSON(http.StatusTooManyRequests, &dto.ResponseErr{
+			StatusCode: http.StatusTooManyRequests,
+			Message:    "Too many requests, try again later",
+			Data:       nil,
+		})
+		return
+	}
+
+	ctx.Next()
+}
+
+func rateLimitKey(ctx RateLimitContext) string {
+	principal := ctx.Principal()
+
+	switch {
+	case principal.IsUser():
+		return fmt.Sprintf("user:%v", principal.UserID)
+	case principal.ApiKeyID != "":
+		return "apikey:" + principal.ApiKeyID
+	default:
+		return "ip:" + ctx.IP()
+	}
+}
+
+func seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
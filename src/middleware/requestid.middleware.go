@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"fmt"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/constant"
+	"regexp"
+)
+
+// requestIDPattern is the id that the gateway accepts from the caller, any other id is replaced so it cannot
+// break the log lines
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+type RequestIDContext interface {
+	RequestHeader(string) string
+	SetResponseHeader(string, string)
+	SetRequestID(string)
+	Next()
+}
+
+// RequestID keeps the id of the caller or gives the request a new one, the id is returned in the response header
+// and carried by the context of the request to the logs, the errors and the upstream calls
+func RequestID(ctx RequestIDContext) {
+	id := ctx.RequestHeader(constant.RequestIDHeader)
+	if !requestIDPattern.MatchString(id) {
+		id = newRequestID()
+	}
+
+	ctx.SetRequestID(id)
+	ctx.SetResponseHeader(constant.RequestIDHeader, id)
+	ctx.Next()
+}
+
+// newRequestID is a random (version 4) UUID
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
@@ -3,18 +3,40 @@ package router
 import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/samithiwat/samithiwat-backend-gateway/src/handler"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/middleware"
 )
 
-func (r *FiberRouter) GetAuth(path string, handler func(ctx handler.AuthContext)) {
-	r.auth.Get(path, func(c *fiber.Ctx) error {
+func (r *FiberRouter) GetAuth(path string, handler func(ctx handler.AuthContext), policy middleware.Policy, mw ...fiber.Handler) {
+	r.auth.Get(path, r.chain(policy, mw, func(c *fiber.Ctx) error {
 		handler(NewFiberCtx(c))
 		return nil
-	})
+	})...)
 }
 
-func (r *FiberRouter) PostAuth(path string, handler func(handler.AuthContext)) {
-	r.auth.Post(path, func(c *fiber.Ctx) error {
+func (r *FiberRouter) PostAuth(path string, handler func(handler.AuthContext), policy middleware.Policy, mw ...fiber.Handler) {
+	r.auth.Post(path, r.chain(policy, mw, func(c *fiber.Ctx) error {
 		handler(NewFiberCtx(c))
 		return nil
-	})
+	})...)
+}
+
+func (r *FiberRouter) DeleteAuth(path string, handler func(ctx handler.AuthContext), policy middleware.Policy, mw ...fiber.Handler) {
+	r.auth.Delete(path, r.chain(policy, mw, func(c *fiber.Ctx) error {
+		handler(NewFiberCtx(c))
+		return nil
+	})...)
+}
+
+func (r *FiberRouter) GetOAuth(path string, handler func(handler.OAuthContext), policy middleware.Policy, mw ...fiber.Handler) {
+	r.auth.Get(path, r.chain(policy, mw, func(c *fiber.Ctx) error {
+		handler(NewFiberCtx(c))
+		return nil
+	})...)
+}
+
+func (r *FiberRouter) PostTwoFactor(path string, handler func(handler.TwoFactorContext), policy middleware.Policy, mw ...fiber.Handler) {
+	r.auth.Post(path, r.chain(policy, mw, func(c *fiber.Ctx) error {
+		handler(NewFiberCtx(c))
+		return nil
+	})...)
 }
@@ -5,43 +5,86 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
 	"github.com/gofiber/fiber/v2/middleware/logger"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/common"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/config"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/constant"
 	"github.com/samithiwat/samithiwat-backend-gateway/src/dto"
 	"github.com/samithiwat/samithiwat-backend-gateway/src/middleware"
+	"net"
 	"strconv"
+	"strings"
+	"time"
 )
 
 type FiberRouter struct {
 	*fiber.App
-	auth fiber.Router
-	user fiber.Router
-	team fiber.Router
-	org  fiber.Router
+	authGuard middleware.AuthGuard
+	limiter   *middleware.RateLimiter
+	auth      fiber.Router
 }
 
-func NewFiberRouter(authGuard middleware.AuthGuard) *FiberRouter {
+// NewFiberRouter creates the router, the client address is read from the header of the proxy only when the request comes
+// from one of the trusted proxies
+func NewFiberRouter(authGuard middleware.AuthGuard, limiter *middleware.RateLimiter, proxy config.Proxy) *FiberRouter {
 	r := fiber.New(fiber.Config{
-		StrictRouting: true,
-		AppName:       "Samithiwat.dev API",
+		StrictRouting:           true,
+		AppName:                 "Samithiwat.dev API",
+		ProxyHeader:             proxy.Header,
+		EnableTrustedProxyCheck: true,
+		TrustedProxies:          proxy.TrustedProxies,
 	})
 
+	r.Use(func(c *fiber.Ctx) error {
+		middleware.RequestID(NewFiberCtx(c))
+		return nil
+	})
 	r.Use(cors.New())
-	r.Use(logger.New())
+	r.Use(logger.New(logger.Config{
+		Format: "[${time}] ${locals:RequestId} ${status} - ${latency} ${method} ${path}\n",
+	}))
+
+	if limiter != nil {
+		limitClient := limiter.LimitClient(constant.RateLimitClient)
+		r.Use(func(c *fiber.Ctx) error {
+			limitClient(NewFiberCtx(c))
+			return nil
+		})
+	}
 
 	r.Get("/docs/*", swagger.HandlerDefault)
 
-	auth := NewGroupRoute(r, "/auth", authGuard.Validate)
-	user := NewGroupRoute(r, "/user", authGuard.Validate)
-	team := NewGroupRoute(r, "/team", authGuard.Validate)
-	org := NewGroupRoute(r, "/organization", authGuard.Validate)
+	auth := r.Group("/auth")
 
-	return &FiberRouter{r, auth, user, team, org}
+	return &FiberRouter{r, authGuard, limiter, auth}
 }
 
-func NewGroupRoute(r *fiber.App, path string, middleware func(ctx middleware.AuthContext)) fiber.Router {
-	return r.Group(path, func(c *fiber.Ctx) error {
-		middleware(NewFiberCtx(c))
+// guard is registered on the route itself so the auth guard can see the route pattern that fiber resolved
+func (r *FiberRouter) guard(policy middleware.Policy) fiber.Handler {
+	validate := r.authGuard.Validate(policy)
+
+	return func(c *fiber.Ctx) error {
+		validate(NewFiberCtx(c))
 		return nil
-	})
+	}
+}
+
+// chain runs the auth guard, then the middlewares and then the handler
+func (r *FiberRouter) chain(policy middleware.Policy, mw []fiber.Handler, handler fiber.Handler) []fiber.Handler {
+	handlers := []fiber.Handler{r.guard(policy)}
+	handlers = append(handlers, mw...)
+
+	return append(handlers, handler)
+}
+
+// RateLimit limits the route by the rate limit policy, it is a middleware of the route so it runs after the auth guard
+// and keys the callers that the guard authenticated by their identity, the client policy is taken before the guard
+func (r *FiberRouter) RateLimit(policy string) fiber.Handler {
+	limit := r.limiter.Limit(policy)
+
+	return func(c *fiber.Ctx) error {
+		limit(NewFiberCtx(c))
+		return nil
+	}
 }
 
 type FiberCtx struct {
@@ -56,20 +99,94 @@ func (c *FiberCtx) Bind(v interface{}) error {
 	return c.Ctx.BodyParser(v)
 }
 
+// JSON writes the body, an error is written with the request id so the caller can report it
 func (c *FiberCtx) JSON(statusCode int, v interface{}) {
+	if errRes, ok := v.(*dto.ResponseErr); ok && errRes != nil {
+		stamped := *errRes
+		stamped.RequestID = c.RequestID()
+		v = &stamped
+	}
+
 	c.Ctx.Status(statusCode).JSON(v)
 }
 
+func (c *FiberCtx) RequestID() string {
+	id, _ := c.Ctx.Locals("RequestId").(string)
+
+	return id
+}
+
+// SetRequestID keeps the id for the logs and the errors and in the user context for the upstream calls
+func (c *FiberCtx) SetRequestID(id string) {
+	c.Ctx.Locals("RequestId", id)
+	c.Ctx.SetUserContext(common.WithRequestID(c.Ctx.UserContext(), id))
+}
+
 func (c *FiberCtx) ID() (id int32, err error) {
 	v, err := c.ParamsInt("id")
 
 	return int32(v), err
 }
 
+func (c *FiberCtx) PermissionID() (id int32, err error) {
+	v, err := c.ParamsInt("permissionId")
+
+	return int32(v), err
+}
+
+func (c *FiberCtx) MemberID() (id int32, err error) {
+	v, err := c.ParamsInt("userId")
+
+	return int32(v), err
+}
+
+func (c *FiberCtx) SessionParam() string {
+	return c.Params("id")
+}
+
+func (c *FiberCtx) Device() *dto.Device {
+	return &dto.Device{
+		UserAgent: c.Get(fiber.HeaderUserAgent),
+		IP:        c.IP(),
+	}
+}
+
+// IP returns the address of the client, the hops of the proxy header are walked from the nearest one and the first hop
+// that is not a trusted proxy is the client, so a client cannot choose its address by sending the header itself
+func (c *FiberCtx) IP() string {
+	remote := c.Context().RemoteIP().String()
+
+	conf := c.App().Config()
+	if conf.ProxyHeader == "" || !c.IsProxyTrusted() {
+		return remote
+	}
+
+	hops := strings.Split(c.Get(conf.ProxyHeader), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		ip := net.ParseIP(strings.TrimSpace(hops[i]))
+		if ip == nil {
+			break
+		}
+
+		if !isTrustedProxy(ip, conf.TrustedProxies) {
+			return ip.String()
+		}
+	}
+
+	return remote
+}
+
+func (c *FiberCtx) Provider() string {
+	return c.Params("provider")
+}
+
 func (c *FiberCtx) UserID() int32 {
-	id := c.Ctx.Locals("UserId")
+	id, ok := c.Ctx.Locals("UserId").(string)
+	if !ok {
+		return -1
+	}
 
-	result, err := strconv.Atoi(id.(string))
+	result, err := strconv.Atoi(id)
 	if err != nil {
 		result = -1
 	}
@@ -77,6 +194,21 @@ func (c *FiberCtx) UserID() int32 {
 	return int32(result)
 }
 
+func (c *FiberCtx) Principal() *dto.Principal {
+	apiKeyId, _ := c.Ctx.Locals("ApiKeyId").(string)
+
+	sessionId, _ := c.Ctx.Locals("SessionId").(string)
+
+	admin, _ := c.Ctx.Locals("Admin").(string)
+
+	return &dto.Principal{
+		UserID:    c.UserID(),
+		ApiKeyID:  apiKeyId,
+		SessionID: sessionId,
+		Admin:     admin == "true",
+	}
+}
+
 func (c *FiberCtx) PaginationQueryParam(query *dto.PaginationQueryParams) error {
 	if err := c.QueryParser(query); err != nil {
 		return err
@@ -85,16 +217,90 @@ func (c *FiberCtx) PaginationQueryParam(query *dto.PaginationQueryParams) error
 	return nil
 }
 
+func (c *FiberCtx) TreeQueryParam(query *dto.TreeQueryParams) error {
+	if err := c.QueryParser(query); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c *FiberCtx) ActivityQueryParam(query *dto.ActivityQueryParams) error {
+	if err := c.QueryParser(query); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c *FiberCtx) OAuthCallbackQuery(query *dto.OAuthCallback) error {
+	if err := c.QueryParser(query); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c *FiberCtx) VerifyEmailQuery(query *dto.VerifyEmail) error {
+	if err := c.QueryParser(query); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (c *FiberCtx) IDsQueryParam() string {
+	return c.Ctx.Query("ids")
+}
+
 func (c *FiberCtx) Token() string {
 	return c.Ctx.Get(fiber.HeaderAuthorization, "")
 }
 
+func (c *FiberCtx) RequestHeader(key string) string {
+	return c.Ctx.Get(key, "")
+}
+
+func (c *FiberCtx) GetCookie(name string) string {
+	return c.Ctx.Cookies(name)
+}
+
+func (c *FiberCtx) SetCookie(cookie *dto.Cookie) {
+	fc := &fiber.Cookie{
+		Name:     cookie.Name,
+		Value:    cookie.Value,
+		Path:     cookie.Path,
+		Domain:   cookie.Domain,
+		MaxAge:   cookie.MaxAge,
+		Secure:   cookie.Secure,
+		HTTPOnly: cookie.HttpOnly,
+		SameSite: cookie.SameSite,
+	}
+
+	// a negative max age is not written by fasthttp, the cookie is removed by an expiry in the past instead
+	if cookie.MaxAge < 0 {
+		fc.MaxAge = 0
+		fc.Expires = time.Unix(0, 0)
+	}
+
+	c.Ctx.Cookie(fc)
+}
+
+func (c *FiberCtx) SetResponseHeader(key string, value string) {
+	c.Ctx.Set(key, value)
+}
+
 func (c *FiberCtx) Method() string {
 	return c.Ctx.Method()
 }
 
-func (c *FiberCtx) Path() string {
-	return c.Ctx.Path()
+// RequestURI is the path with the query string as the client sent it
+func (c *FiberCtx) RequestURI() string {
+	return c.Ctx.OriginalURL()
+}
+
+func (c *FiberCtx) RoutePath() string {
+	return c.Ctx.Route().Path
 }
 
 func (c *FiberCtx) StoreValue(k string, v string) {
@@ -104,3 +310,17 @@ func (c *FiberCtx) StoreValue(k string, v string) {
 func (c *FiberCtx) Next() {
 	c.Ctx.Next()
 }
+
+func isTrustedProxy(ip net.IP, proxies []string) bool {
+	for _, proxy := range proxies {
+		if _, ipNet, err := net.ParseCIDR(proxy); err == nil {
+			if ipNet.Contains(ip) {
+				return true
+			}
+		} else if trusted := net.ParseIP(proxy); trusted != nil && trusted.Equal(ip) {
+			return true
+		}
+	}
+
+	return false
+}
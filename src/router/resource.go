@@ -0,0 +1,109 @@
+package router
+
+import (
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/middleware"
+	"net/http"
+	"reflect"
+)
+
+var fiberCtxType = reflect.TypeOf(&FiberCtx{})
+
+// Route binds a handler to a path of a resource, the handler is a func that takes one of the handler contexts
+// (e.g. handler.UserContext) and the middlewares run after the auth guard in their order
+type Route struct {
+	Method     string
+	Path       string
+	Handler    interface{}
+	Policy     middleware.Policy
+	Middleware []fiber.Handler
+}
+
+// Handle is a route of the resource whose method and path are set by the field of the resource it is assigned to
+func Handle(handler interface{}, policy middleware.Policy, mw ...fiber.Handler) *Route {
+	return &Route{
+		Handler:    handler,
+		Policy:     policy,
+		Middleware: mw,
+	}
+}
+
+// On is a route of the resource at the path under the group of the resource
+func On(method string, path string, handler interface{}, policy middleware.Policy, mw ...fiber.Handler) *Route {
+	return &Route{
+		Method:     method,
+		Path:       path,
+		Handler:    handler,
+		Policy:     policy,
+		Middleware: mw,
+	}
+}
+
+// Resource is the routes that are mounted under the group of a resource, a nil route is not mounted, the middlewares
+// of the resource run before the middlewares of each route
+type Resource struct {
+	List       *Route
+	Get        *Route
+	Create     *Route
+	Update     *Route
+	Delete     *Route
+	Routes     []*Route
+	Middleware []fiber.Handler
+}
+
+// Resource mounts the group of the resource at the prefix, list and create are at / and get, update and delete are
+// at /:id, the handler that does not take a context of FiberCtx panics while the routes are mounted
+func (r *FiberRouter) Resource(prefix string, resource *Resource) fiber.Router {
+	group := r.Group(prefix)
+
+	crud := []struct {
+		method string
+		path   string
+		route  *Route
+	}{
+		{http.MethodGet, "/", resource.List},
+		{http.MethodGet, "/:id", resource.Get},
+		{http.MethodPost, "/", resource.Create},
+		{http.MethodPatch, "/:id", resource.Update},
+		{http.MethodDelete, "/:id", resource.Delete},
+	}
+
+	for _, e := range crud {
+		if e.route == nil {
+			continue
+		}
+
+		r.mount(group, e.method, e.path, e.route, resource.Middleware)
+	}
+
+	for _, route := range resource.Routes {
+		r.mount(group, route.Method, route.Path, route, resource.Middleware)
+	}
+
+	return group
+}
+
+func (r *FiberRouter) mount(group fiber.Router, method string, path string, route *Route, mw []fiber.Handler) {
+	chained := append(append([]fiber.Handler{}, mw...), route.Middleware...)
+
+	group.Add(method, path, r.chain(route.Policy, chained, adapt(route.Handler))...)
+}
+
+// adapt checks the handler once while it is mounted so a request never calls a handler of the wrong type
+func adapt(handler interface{}) fiber.Handler {
+	fn := reflect.ValueOf(handler)
+	if fn.Kind() != reflect.Func || fn.IsNil() {
+		panic(fmt.Sprintf("router: the handler must be a func, got %T", handler))
+	}
+
+	t := fn.Type()
+	if t.NumIn() != 1 || t.NumOut() != 0 || !fiberCtxType.AssignableTo(t.In(0)) {
+		panic(fmt.Sprintf("router: the handler must take a context that FiberCtx implements, got %v", t))
+	}
+
+	return func(c *fiber.Ctx) error {
+		fn.Call([]reflect.Value{reflect.ValueOf(NewFiberCtx(c))})
+		return nil
+	}
+}
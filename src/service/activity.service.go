@@ -0,0 +1,216 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/constant"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/dto"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/proto"
+	"log"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ActivitySink is where the gateway writes the activity logs that it produces itself
+type ActivitySink interface {
+	Write(*proto.Log) error
+}
+
+// ActivityReader is implemented by the sinks that can give back what was written to them
+type ActivityReader interface {
+	FindByUser(uint32) []*proto.Log
+}
+
+type ActivityService struct {
+	sink ActivitySink
+}
+
+func NewActivityService(sink ActivitySink) *ActivityService {
+	return &ActivityService{
+		sink: sink,
+	}
+}
+
+func (s *ActivityService) Record(logType proto.LogType, userId uint32, title string) {
+	entry := &proto.Log{
+		Title:     title,
+		Type:      logType,
+		User:      &proto.User{Id: userId},
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+	}
+
+	if err := s.sink.Write(entry); err != nil {
+		log.Printf("cannot record %v activity of user %v: %v\n", logType, userId, err)
+	}
+}
+
+func (s *ActivityService) FindByUser(userId uint32) []*proto.Log {
+	reader, ok := s.sink.(ActivityReader)
+	if !ok {
+		return nil
+	}
+
+	return reader.FindByUser(userId)
+}
+
+// Paginate filters the logs by type, sorts them from the newest and slices the requested page
+func (ActivityService) Paginate(logs []*proto.Log, query *dto.ActivityQueryParams) (*dto.LogPagination, *dto.ResponseErr) {
+	items := []*proto.Log{}
+
+	if query.Type != "" {
+		logType, ok := proto.LogType_value[query.Type]
+		if !ok {
+			return nil, &dto.ResponseErr{
+				StatusCode: http.StatusBadRequest,
+				Message:    "Invalid log type",
+				Data:       nil,
+			}
+		}
+
+		for _, l := range logs {
+			if l.Type == proto.LogType(logType) {
+				items = append(items, l)
+			}
+		}
+	} else {
+		items = append(items, logs...)
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Timestamp > items[j].Timestamp
+	})
+
+	page := PaginateLogs(items, query.Limit, query.Page)
+
+	return page, nil
+}
+
+func PaginateLogs(logs []*proto.Log, limit int64, page int64) *dto.LogPagination {
+	if limit <= 0 {
+		limit = constant.DefaultPageLimit
+	}
+	if limit > constant.FetchAllLimit {
+		limit = constant.FetchAllLimit
+	}
+
+	if page <= 0 {
+		page = 1
+	}
+
+	total := int64(len(logs))
+	start := (page - 1) * limit
+	if start > total {
+		start = total
+	}
+	end := start + limit
+	if end > total {
+		end = total
+	}
+
+	return &dto.LogPagination{
+		Items: logs[start:end],
+		Meta: &proto.PaginationMetadata{
+			TotalItem:    total,
+			ItemCount:    end - start,
+			ItemsPerPage: limit,
+			TotalPage:    (total + limit - 1) / limit,
+			CurrentPage:  page,
+		},
+	}
+}
+
+// LogActivitySink writes the activity logs to the standard logger
+type LogActivitySink struct{}
+
+func (LogActivitySink) Write(entry *proto.Log) error {
+	b, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("activity: %s\n", b)
+
+	return nil
+}
+
+// MultiActivitySink writes the activity logs to every sink and reads them back from the first sink that is readable
+type MultiActivitySink []ActivitySink
+
+func (s MultiActivitySink) Write(entry *proto.Log) (err error) {
+	for _, sink := range s {
+		if e := sink.Write(entry); e != nil && err == nil {
+			err = e
+		}
+	}
+
+	return
+}
+
+func (s MultiActivitySink) FindByUser(userId uint32) []*proto.Log {
+	for _, sink := range s {
+		if reader, ok := sink.(ActivityReader); ok {
+			return reader.FindByUser(userId)
+		}
+	}
+
+	return nil
+}
+
+// MemoryActivitySink keeps the activity logs in memory, it is meant for tests and single instance deployments.
+// It keeps the latest retain logs of each user, a retain of 0 keeps all of them
+type MemoryActivitySink struct {
+	mu     sync.RWMutex
+	retain int
+	logs   map[uint32][]*proto.Log
+}
+
+func NewMemoryActivitySink(retain int) *MemoryActivitySink {
+	return &MemoryActivitySink{
+		retain: retain,
+		logs:   map[uint32][]*proto.Log{},
+	}
+}
+
+func (s *MemoryActivitySink) Write(entry *proto.Log) error {
+	if entry.User == nil {
+		return fmt.Errorf("activity log must belong to a user")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	logs := append(s.logs[entry.User.Id], entry)
+	if s.retain > 0 && len(logs) > s.retain {
+		// copy so the dropped logs are not held by the backing array
+		logs = append([]*proto.Log{}, logs[len(logs)-s.retain:]...)
+	}
+	s.logs[entry.User.Id] = logs
+
+	return nil
+}
+
+func (s *MemoryActivitySink) FindByUser(userId uint32) []*proto.Log {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if len(s.logs[userId]) == 0 {
+		return nil
+	}
+
+	return append([]*proto.Log{}, s.logs[userId]...)
+}
+
+// Logs returns the logs of every user, the logs of a user are in the order they were written
+func (s *MemoryActivitySink) Logs() []*proto.Log {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var result []*proto.Log
+	for _, logs := range s.logs {
+		result = append(result, logs...)
+	}
+
+	return result
+}
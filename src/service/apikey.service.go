@@ -0,0 +1,209 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/dto"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ApiKey is a key of a server to server client, only the sha256 hash of its secret is kept
+type ApiKey struct {
+	ID            string
+	Hash          []byte
+	Scopes        []string
+	ExpiresAt     time.Time
+	SigningSecret string
+	UserID        int32
+}
+
+// NewApiKey builds a key from the config, the hash is the hex encoded sha256 of the secret and the expiry is RFC3339 or empty
+func NewApiKey(id string, hash string, scopes []string, expiresAt string, signingSecret string, userId int32) (*ApiKey, error) {
+	if id == "" || strings.Contains(id, ".") {
+		return nil, fmt.Errorf("invalid api key id %q", id)
+	}
+
+	h, err := hex.DecodeString(hash)
+	if err != nil || len(h) != sha256.Size {
+		return nil, fmt.Errorf("api key %q must have a hex encoded sha256 hash", id)
+	}
+
+	key := &ApiKey{
+		ID:            id,
+		Hash:          h,
+		Scopes:        scopes,
+		SigningSecret: signingSecret,
+		UserID:        userId,
+	}
+
+	if expiresAt != "" {
+		key.ExpiresAt, err = time.Parse(time.RFC3339, expiresAt)
+		if err != nil {
+			return nil, fmt.Errorf("invalid expiry of api key %q: %v", id, err)
+		}
+	}
+
+	return key, nil
+}
+
+// ApiKeyStore finds the api key by its id, it returns nil when the key does not exist
+type ApiKeyStore interface {
+	FindApiKey(string) (*ApiKey, error)
+}
+
+type MemoryApiKeyStore struct {
+	keys map[string]*ApiKey
+}
+
+func NewMemoryApiKeyStore(keys []*ApiKey) *MemoryApiKeyStore {
+	s := &MemoryApiKeyStore{keys: map[string]*ApiKey{}}
+	for _, k := range keys {
+		s.keys[k.ID] = k
+	}
+
+	return s
+}
+
+func (s *MemoryApiKeyStore) FindApiKey(id string) (*ApiKey, error) {
+	return s.keys[id], nil
+}
+
+// ApiKeyService authenticates the ApiKey scheme, the credential is "<id>.<secret>"
+type ApiKeyService struct {
+	store  ApiKeyStore
+	window time.Duration
+	now    func() time.Time
+
+	mu      sync.Mutex
+	seen    map[string]time.Time
+	sweeper sweeper
+}
+
+func NewApiKeyService(store ApiKeyStore, window time.Duration) *ApiKeyService {
+	return &ApiKeyService{
+		store:  store,
+		window: window,
+		now:    time.Now,
+		seen:   map[string]time.Time{},
+	}
+}
+
+func (s *ApiKeyService) Authenticate(credential string, req *dto.SignedRequest) (*dto.ApiClient, *dto.ResponseErr) {
+	parts := strings.SplitN(credential, ".", 2)
+	if len(parts) != 2 {
+		return nil, unauthorized("Invalid API key")
+	}
+
+	key, err := s.store.FindApiKey(parts[0])
+	if err != nil {
+		log.Printf("cannot find api key %q: %v\n", parts[0], err)
+		return nil, &dto.ResponseErr{
+			StatusCode: http.StatusServiceUnavailable,
+			Message:    "Cannot verify the API key",
+			Data:       nil,
+		}
+	}
+
+	if key == nil {
+		return nil, unauthorized("Invalid API key")
+	}
+
+	hash := sha256.Sum256([]byte(parts[1]))
+	if subtle.ConstantTimeCompare(hash[:], key.Hash) != 1 {
+		return nil, unauthorized("Invalid API key")
+	}
+
+	if !key.ExpiresAt.IsZero() && !s.now().Before(key.ExpiresAt) {
+		return nil, unauthorized("API key is expired")
+	}
+
+	if key.SigningSecret != "" {
+		if errRes := s.verifySignature(key, req); errRes != nil {
+			return nil, errRes
+		}
+	}
+
+	return &dto.ApiClient{
+		ID:     key.ID,
+		Scopes: key.Scopes,
+		UserID: key.UserID,
+	}, nil
+}
+
+// verifySignature checks the hex encoded HMAC-SHA256 of "<timestamp>\n<method>\n<uri>\n<hex sha256 of the body>" where the
+// uri is the path with the query string, a signature is only accepted once and only while its timestamp is inside the window
+func (s *ApiKeyService) verifySignature(key *ApiKey, req *dto.SignedRequest) *dto.ResponseErr {
+	if req.Signature == "" || req.Timestamp == "" {
+		return unauthorized("Request signature is required")
+	}
+
+	ts, err := strconv.ParseInt(req.Timestamp, 10, 64)
+	if err != nil {
+		return unauthorized("Invalid request signature")
+	}
+
+	now := s.now()
+	signedAt := time.Unix(ts, 0)
+	if signedAt.Before(now.Add(-s.window)) || signedAt.After(now.Add(s.window)) {
+		return unauthorized("Request signature is expired")
+	}
+
+	sig, err := hex.DecodeString(req.Signature)
+	if err != nil || !hmac.Equal(sig, SignRequest(key.SigningSecret, req)) {
+		return unauthorized("Invalid request signature")
+	}
+
+	if !s.markSeen(key.ID+":"+req.Signature, signedAt) {
+		return unauthorized("Request signature is already used")
+	}
+
+	return nil
+}
+
+func (s *ApiKeyService) markSeen(id string, signedAt time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sweeper.run(s.now(), func() {
+		now := s.now()
+		for k, t := range s.seen {
+			if t.Add(s.window).Before(now) {
+				delete(s.seen, k)
+			}
+		}
+	})
+
+	if _, ok := s.seen[id]; ok {
+		return false
+	}
+
+	s.seen[id] = signedAt
+
+	return true
+}
+
+// SignRequest returns the HMAC-SHA256 signature of the request, the clients sign their requests the same way
+func SignRequest(secret string, req *dto.SignedRequest) []byte {
+	body := sha256.Sum256(req.Body)
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strings.Join([]string{req.Timestamp, req.Method, req.URI, hex.EncodeToString(body[:])}, "\n")))
+
+	return mac.Sum(nil)
+}
+
+func unauthorized(message string) *dto.ResponseErr {
+	return &dto.ResponseErr{
+		StatusCode: http.StatusUnauthorized,
+		Message:    message,
+		Data:       nil,
+	}
+}
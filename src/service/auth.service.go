@@ -4,7 +4,6 @@ import (
 	"context"
 	"github.com/samithiwat/samithiwat-backend-gateway/src/dto"
 	"github.com/samithiwat/samithiwat-backend-gateway/src/proto"
-	"log"
 	"net/http"
 	"time"
 )
@@ -19,8 +18,8 @@ func NewAuthService(client proto.AuthServiceClient) *AuthService {
 	}
 }
 
-func (s *AuthService) Register(register *dto.Register) (result *proto.User, err *dto.ResponseErr) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+func (s *AuthService) Register(ctx context.Context, register *dto.Register) (result *proto.User, err *dto.ResponseErr) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
 	r := s.DtoToRawRegister(register)
@@ -28,7 +27,7 @@ func (s *AuthService) Register(register *dto.Register) (result *proto.User, err
 	res, errRes := s.client.Register(ctx, &proto.RegisterRequest{Register: r})
 
 	if errRes != nil {
-		log.Printf("%v\n", errRes)
+		logUpstreamErr(ctx, errRes)
 		return nil, &dto.ResponseErr{
 			StatusCode: http.StatusServiceUnavailable,
 			Message:    "Service is down",
@@ -48,15 +47,15 @@ func (s *AuthService) Register(register *dto.Register) (result *proto.User, err
 	return
 }
 
-func (s *AuthService) Login(login *dto.Login) (result *proto.Credential, err *dto.ResponseErr) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+func (s *AuthService) Login(ctx context.Context, login *dto.Login) (result *proto.Credential, err *dto.ResponseErr) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
 	l := s.DtoToRawLogin(login)
 
 	res, errRes := s.client.Login(ctx, &proto.LoginRequest{Login: l})
 	if errRes != nil {
-		log.Printf("%v\n", errRes)
+		logUpstreamErr(ctx, errRes)
 		return nil, &dto.ResponseErr{
 			StatusCode: http.StatusServiceUnavailable,
 			Message:    "Service is down",
@@ -76,15 +75,15 @@ func (s *AuthService) Login(login *dto.Login) (result *proto.Credential, err *dt
 	return
 }
 
-func (s *AuthService) ChangePassword(changePwd *dto.ChangePassword) (result bool, err *dto.ResponseErr) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+func (s *AuthService) ChangePassword(ctx context.Context, changePwd *dto.ChangePassword) (result bool, err *dto.ResponseErr) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
 	chPwd := s.DtoToRawChangePassword(changePwd)
 
 	res, errRes := s.client.ChangePassword(ctx, &proto.ChangePasswordRequest{ChangePassword: chPwd})
 	if errRes != nil {
-		log.Printf("%v\n", errRes)
+		logUpstreamErr(ctx, errRes)
 		return false, &dto.ResponseErr{
 			StatusCode: http.StatusServiceUnavailable,
 			Message:    "Service is down",
@@ -104,13 +103,13 @@ func (s *AuthService) ChangePassword(changePwd *dto.ChangePassword) (result bool
 	return
 }
 
-func (s *AuthService) Logout(userId uint32) (result bool, err *dto.ResponseErr) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+func (s *AuthService) Logout(ctx context.Context, userId uint32) (result bool, err *dto.ResponseErr) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
 	res, errRes := s.client.Logout(ctx, &proto.LogoutRequest{UserId: uint32(userId)})
 	if errRes != nil {
-		log.Printf("%v\n", errRes)
+		logUpstreamErr(ctx, errRes)
 		return false, &dto.ResponseErr{
 			StatusCode: http.StatusServiceUnavailable,
 			Message:    "Service is down",
@@ -130,13 +129,13 @@ func (s *AuthService) Logout(userId uint32) (result bool, err *dto.ResponseErr)
 	return
 }
 
-func (s *AuthService) Validate(token string) (result uint32, err *dto.ResponseErr) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+func (s *AuthService) Validate(ctx context.Context, token string) (result uint32, err *dto.ResponseErr) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
 	res, errRes := s.client.Validate(ctx, &proto.ValidateRequest{Token: token})
 	if errRes != nil {
-		log.Println(errRes)
+		logUpstreamErr(ctx, errRes)
 		return 0, &dto.ResponseErr{
 			StatusCode: http.StatusServiceUnavailable,
 			Message:    "Service is down",
@@ -156,13 +155,13 @@ func (s *AuthService) Validate(token string) (result uint32, err *dto.ResponseEr
 	return
 }
 
-func (s *AuthService) RefreshToken(token string) (result *proto.Credential, err *dto.ResponseErr) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+func (s *AuthService) RefreshToken(ctx context.Context, token string) (result *proto.Credential, err *dto.ResponseErr) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
 	res, errRes := s.client.RefreshToken(ctx, &proto.RefreshTokenRequest{RefreshToken: token})
 	if errRes != nil {
-		log.Println(errRes)
+		logUpstreamErr(ctx, errRes)
 		return nil, &dto.ResponseErr{
 			StatusCode: http.StatusServiceUnavailable,
 			Message:    "Service is down",
@@ -0,0 +1,209 @@
+package service
+
+import (
+	"container/list"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/dto"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// TokenCache keeps the results of the remote Validate call in a bounded LRU, an entry lives for the TTL
+// or until the token expires, whichever comes first. A cache with no capacity passes every call through
+type TokenCache struct {
+	validator FallbackValidator
+	capacity  int
+	ttl       time.Duration
+	now       func() time.Time
+
+	mu         sync.Mutex
+	ll         *list.List
+	entries    map[string]*list.Element
+	byUser     map[uint32]map[string]struct{}
+	calls      map[string]*tokenCall
+	generation uint64
+
+	hits      uint64
+	misses    uint64
+	coalesced uint64
+}
+
+type tokenEntry struct {
+	token     string
+	userId    uint32
+	expiresAt time.Time
+}
+
+type tokenCall struct {
+	wg     sync.WaitGroup
+	userId uint32
+	err    *dto.ResponseErr
+}
+
+func NewTokenCache(validator FallbackValidator, capacity int, ttl time.Duration) *TokenCache {
+	return &TokenCache{
+		validator: validator,
+		capacity:  capacity,
+		ttl:       ttl,
+		now:       time.Now,
+		ll:        list.New(),
+		entries:   map[string]*list.Element{},
+		byUser:    map[uint32]map[string]struct{}{},
+		calls:     map[string]*tokenCall{},
+	}
+}
+
+// Validate coalesces the concurrent lookups of a token into the call of the first request, the call carries its request id
+func (c *TokenCache) Validate(ctx context.Context, token string) (uint32, *dto.ResponseErr) {
+	if c.capacity <= 0 {
+		return c.validator.Validate(ctx, token)
+	}
+
+	c.mu.Lock()
+	if userId, ok := c.get(token); ok {
+		c.mu.Unlock()
+		atomic.AddUint64(&c.hits, 1)
+		return userId, nil
+	}
+
+	// the concurrent lookups of the same token wait for the call that is already in flight
+	if call, ok := c.calls[token]; ok {
+		c.mu.Unlock()
+		atomic.AddUint64(&c.coalesced, 1)
+		call.wg.Wait()
+		return call.userId, call.err
+	}
+
+	call := &tokenCall{}
+	call.wg.Add(1)
+	c.calls[token] = call
+	generation := c.generation
+	c.mu.Unlock()
+
+	atomic.AddUint64(&c.misses, 1)
+	call.userId, call.err = c.validator.Validate(ctx, token)
+
+	c.mu.Lock()
+	delete(c.calls, token)
+	// the result is dropped when the user was evicted while the call was in flight
+	if call.err == nil && generation == c.generation {
+		c.add(token, call.userId)
+	}
+	c.mu.Unlock()
+
+	call.wg.Done()
+
+	return call.userId, call.err
+}
+
+// EvictUser removes every cached token of the user, it is called when the user logs out or changes the password
+func (c *TokenCache) EvictUser(userId uint32) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+
+	for token := range c.byUser[userId] {
+		if el, ok := c.entries[token]; ok {
+			c.remove(el)
+		}
+	}
+}
+
+func (c *TokenCache) Stats() *dto.TokenCacheStats {
+	c.mu.Lock()
+	size := c.ll.Len()
+	c.mu.Unlock()
+
+	return &dto.TokenCacheStats{
+		Hits:      atomic.LoadUint64(&c.hits),
+		Misses:    atomic.LoadUint64(&c.misses),
+		Coalesced: atomic.LoadUint64(&c.coalesced),
+		Size:      size,
+		Capacity:  c.capacity,
+	}
+}
+
+func (c *TokenCache) get(token string) (uint32, bool) {
+	el, ok := c.entries[token]
+	if !ok {
+		return 0, false
+	}
+
+	entry := el.Value.(*tokenEntry)
+	if !c.now().Before(entry.expiresAt) {
+		c.remove(el)
+		return 0, false
+	}
+
+	c.ll.MoveToFront(el)
+
+	return entry.userId, true
+}
+
+func (c *TokenCache) add(token string, userId uint32) {
+	expiresAt := c.now().Add(c.ttl)
+	if exp, ok := tokenExpiry(token); ok && exp.Before(expiresAt) {
+		expiresAt = exp
+	}
+
+	if !c.now().Before(expiresAt) {
+		return
+	}
+
+	if el, ok := c.entries[token]; ok {
+		c.remove(el)
+	}
+
+	c.entries[token] = c.ll.PushFront(&tokenEntry{token: token, userId: userId, expiresAt: expiresAt})
+	if c.byUser[userId] == nil {
+		c.byUser[userId] = map[string]struct{}{}
+	}
+	c.byUser[userId][token] = struct{}{}
+
+	for c.ll.Len() > c.capacity {
+		c.remove(c.ll.Back())
+	}
+}
+
+func (c *TokenCache) remove(el *list.Element) {
+	entry := c.ll.Remove(el).(*tokenEntry)
+	delete(c.entries, entry.token)
+
+	tokens := c.byUser[entry.userId]
+	delete(tokens, entry.token)
+	if len(tokens) == 0 {
+		delete(c.byUser, entry.userId)
+	}
+}
+
+// tokenExpiry reads the exp claim without checking the signature, it is only used after the auth service accepted the token
+func tokenExpiry(token string) (time.Time, bool) {
+	token = strings.TrimSpace(token)
+	if len(token) > 7 && strings.EqualFold(token[:7], "Bearer ") {
+		token = strings.TrimSpace(token[7:])
+	}
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}, false
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	claims := struct {
+		Exp *float64 `json:"exp"`
+	}{}
+	if err := json.Unmarshal(b, &claims); err != nil || claims.Exp == nil {
+		return time.Time{}, false
+	}
+
+	return time.Unix(int64(*claims.Exp), 0), true
+}
@@ -1,6 +1,16 @@
 package service
 
-import "fmt"
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/constant"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/dto"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/proto"
+	"strings"
+)
 
 func FormatErr(errors []string) string {
 	result := ""
@@ -12,3 +22,95 @@ func FormatErr(errors []string) string {
 	}
 	return result
 }
+
+func responseErr(statusCode int, message string) *dto.ResponseErr {
+	return &dto.ResponseErr{
+		StatusCode: statusCode,
+		Message:    message,
+		Data:       nil,
+	}
+}
+
+func ToUintIDs(ids []int32) []uint32 {
+	result := make([]uint32, 0, len(ids))
+	for _, id := range ids {
+		result = append(result, uint32(id))
+	}
+	return result
+}
+
+// PaginateUsers slices the users that come embedded in another resource into the same shape as the upstream pagination
+func PaginateUsers(users []*proto.User, query *dto.PaginationQueryParams) *proto.UserPagination {
+	limit := query.Limit
+	if limit <= 0 {
+		limit = constant.DefaultPageLimit
+	}
+	if limit > constant.FetchAllLimit {
+		limit = constant.FetchAllLimit
+	}
+
+	page := query.Page
+	if page <= 0 {
+		page = 1
+	}
+
+	total := int64(len(users))
+	start := (page - 1) * limit
+	if start > total {
+		start = total
+	}
+	end := start + limit
+	if end > total {
+		end = total
+	}
+
+	return &proto.UserPagination{
+		Items: users[start:end],
+		Meta: &proto.PaginationMetadata{
+			TotalItem:    total,
+			ItemCount:    end - start,
+			ItemsPerPage: limit,
+			TotalPage:    (total + limit - 1) / limit,
+			CurrentPage:  page,
+		},
+	}
+}
+
+// signClaims encodes the claims into a "<base64 json>.<base64 HMAC-SHA256>" token, it is the format of the reset and the verification tokens
+func signClaims(secret []byte, claims interface{}) (string, error) {
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(hmacSum(secret, encoded)), nil
+}
+
+// parseClaims decodes the token of signClaims into the claims, it returns false when the token is malformed or the signature does not match
+func parseClaims(secret []byte, token string, claims interface{}) bool {
+	parts := strings.Split(token, ".")
+	if len(parts) != 2 {
+		return false
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil || !hmac.Equal(sig, hmacSum(secret, parts[0])) {
+		return false
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return false
+	}
+
+	return json.Unmarshal(payload, claims) == nil
+}
+
+func hmacSum(secret []byte, payload string) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(payload))
+
+	return mac.Sum(nil)
+}
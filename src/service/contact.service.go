@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/dto"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/proto"
+	"net/http"
+	"time"
+)
+
+type ContactService struct {
+	client proto.ContactServiceClient
+}
+
+func NewContactService(client proto.ContactServiceClient) *ContactService {
+	return &ContactService{
+		client: client,
+	}
+}
+
+func (s *ContactService) FindOne(ctx context.Context, id int32) (result *proto.Contact, err *dto.ResponseErr) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	res, errRes := s.client.FindOne(ctx, &proto.FindOneContactRequest{Id: id})
+	if errRes != nil {
+		logUpstreamErr(ctx, errRes)
+		return nil, &dto.ResponseErr{
+			StatusCode: http.StatusServiceUnavailable,
+			Message:    "Service is down",
+			Data:       nil,
+		}
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return nil, &dto.ResponseErr{
+			StatusCode: int(res.StatusCode),
+			Message:    FormatErr(res.Errors),
+			Data:       nil,
+		}
+	}
+
+	result = res.Data
+
+	return
+}
+
+func (s *ContactService) Create(ctx context.Context, contactDto *dto.ContactDto) (result *proto.Contact, err *dto.ResponseErr) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	contact := s.DtoToRaw(contactDto)
+
+	res, errRes := s.client.Create(ctx, &proto.CreateContactRequest{Contact: contact})
+	if errRes != nil {
+		logUpstreamErr(ctx, errRes)
+		return nil, &dto.ResponseErr{
+			StatusCode: http.StatusServiceUnavailable,
+			Message:    "Service is down",
+			Data:       nil,
+		}
+	}
+
+	if res.StatusCode != http.StatusCreated {
+		return nil, &dto.ResponseErr{
+			StatusCode: int(res.StatusCode),
+			Message:    FormatErr(res.Errors),
+			Data:       nil,
+		}
+	}
+
+	result = res.Data
+	return
+}
+
+func (s *ContactService) Update(ctx context.Context, id int32, contactDto *dto.ContactDto) (result *proto.Contact, err *dto.ResponseErr) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	contact := s.DtoToRaw(contactDto)
+	contact.Id = uint32(id)
+
+	res, errRes := s.client.Update(ctx, &proto.UpdateContactRequest{Contact: contact})
+	if errRes != nil {
+		logUpstreamErr(ctx, errRes)
+		return nil, &dto.ResponseErr{
+			StatusCode: http.StatusServiceUnavailable,
+			Message:    "Service is down",
+			Data:       nil,
+		}
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return nil, &dto.ResponseErr{
+			StatusCode: int(res.StatusCode),
+			Message:    FormatErr(res.Errors),
+			Data:       nil,
+		}
+	}
+
+	result = res.Data
+
+	return
+}
+
+func (s *ContactService) Delete(ctx context.Context, id int32) (result *proto.Contact, err *dto.ResponseErr) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	res, errRes := s.client.Delete(ctx, &proto.DeleteContactRequest{Id: id})
+	if errRes != nil {
+		logUpstreamErr(ctx, errRes)
+		return nil, &dto.ResponseErr{
+			StatusCode: http.StatusServiceUnavailable,
+			Message:    "Service is down",
+			Data:       nil,
+		}
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return nil, &dto.ResponseErr{
+			StatusCode: int(res.StatusCode),
+			Message:    FormatErr(res.Errors),
+			Data:       nil,
+		}
+	}
+
+	result = res.Data
+
+	return
+}
+
+func (ContactService) DtoToRaw(contactDto *dto.ContactDto) *proto.Contact {
+	return &proto.Contact{
+		Facebook:  contactDto.Facebook,
+		Instagram: contactDto.Instagram,
+		Twitter:   contactDto.Twitter,
+		Linkedin:  contactDto.Linkedin,
+	}
+}
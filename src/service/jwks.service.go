@@ -0,0 +1,278 @@
+package service
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrKeyUnavailable is returned when the key that signed the token cannot be found, the token may still be valid
+var ErrKeyUnavailable = errors.New("signing key is not available")
+
+// minJwksRefetch is the shortest time between two fetches of the JWKS document that are triggered by an unknown key id
+const minJwksRefetch = 30 * time.Second
+
+// JwtKey is a key that verifies the signature of the tokens, Key is []byte for HMAC, *rsa.PublicKey or *ecdsa.PublicKey
+type JwtKey struct {
+	Kid string
+	Alg string
+	Key interface{}
+}
+
+// NewJwtKey builds a key from the config, the secret is used by the HS algorithms and the PEM public key by the others
+func NewJwtKey(kid string, alg string, secret string, publicKey string) (*JwtKey, error) {
+	if _, ok := jwtAlgorithms[alg]; !ok {
+		return nil, fmt.Errorf("unsupported algorithm %q", alg)
+	}
+
+	if alg[:2] == "HS" {
+		if secret == "" {
+			return nil, fmt.Errorf("key %q requires a secret", kid)
+		}
+
+		return &JwtKey{Kid: kid, Alg: alg, Key: []byte(secret)}, nil
+	}
+
+	block, _ := pem.Decode([]byte(publicKey))
+	if block == nil {
+		return nil, fmt.Errorf("key %q requires a PEM public key", kid)
+	}
+
+	pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse the public key of %q: %v", kid, err)
+	}
+
+	return &JwtKey{Kid: kid, Alg: alg, Key: pub}, nil
+}
+
+type jwk struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+	K   string `json:"k"`
+}
+
+func (k *jwk) toKey() (*JwtKey, error) {
+	if k.Use != "" && k.Use != "sig" {
+		return nil, fmt.Errorf("key %q is not a signing key", k.Kid)
+	}
+
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+
+		alg := k.Alg
+		if alg == "" {
+			alg = "RS256"
+		}
+
+		return &JwtKey{
+			Kid: k.Kid,
+			Alg: alg,
+			Key: &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())},
+		}, nil
+	case "EC":
+		curves := map[string]struct {
+			curve elliptic.Curve
+			alg   string
+		}{
+			"P-256": {elliptic.P256(), "ES256"},
+			"P-384": {elliptic.P384(), "ES384"},
+		}
+
+		c, ok := curves[k.Crv]
+		if !ok {
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, err
+		}
+
+		return &JwtKey{
+			Kid: k.Kid,
+			Alg: c.alg,
+			Key: &ecdsa.PublicKey{Curve: c.curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)},
+		}, nil
+	case "oct":
+		secret, err := base64.RawURLEncoding.DecodeString(k.K)
+		if err != nil {
+			return nil, err
+		}
+
+		alg := k.Alg
+		if alg == "" {
+			alg = "HS256"
+		}
+
+		return &JwtKey{Kid: k.Kid, Alg: alg, Key: secret}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+// KeySet holds the keys from the config and the keys of the JWKS document, the document is refetched
+// when it is older than the refresh interval or when a token is signed by a key id that the set does not know
+type KeySet struct {
+	mu              sync.RWMutex
+	static          []*JwtKey
+	remote          []*JwtKey
+	url             string
+	client          *http.Client
+	refreshInterval time.Duration
+	fetchedAt       time.Time
+	now             func() time.Time
+}
+
+func NewKeySet(static []*JwtKey, url string, refreshInterval time.Duration) *KeySet {
+	return &KeySet{
+		static:          static,
+		url:             url,
+		client:          &http.Client{Timeout: 10 * time.Second},
+		refreshInterval: refreshInterval,
+		now:             time.Now,
+	}
+}
+
+// Find returns the keys that can verify the token, every key of the algorithm when the token has no key id
+func (s *KeySet) Find(kid string, alg string) ([]*JwtKey, error) {
+	if s.url != "" && s.isStale() {
+		s.refresh()
+	}
+
+	keys := s.match(kid, alg)
+	if len(keys) == 0 && kid != "" && s.url != "" && s.canRefetch() {
+		s.refresh()
+		keys = s.match(kid, alg)
+	}
+
+	if len(keys) == 0 {
+		return nil, ErrKeyUnavailable
+	}
+
+	return keys, nil
+}
+
+func (s *KeySet) match(kid string, alg string) []*JwtKey {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var result []*JwtKey
+	for _, keys := range [][]*JwtKey{s.static, s.remote} {
+		for _, k := range keys {
+			if k.Alg != alg {
+				continue
+			}
+			if kid != "" && k.Kid != kid {
+				continue
+			}
+			result = append(result, k)
+		}
+	}
+
+	return result
+}
+
+func (s *KeySet) isStale() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.fetchedAt.IsZero() || s.now().Sub(s.fetchedAt) > s.refreshInterval
+}
+
+func (s *KeySet) canRefetch() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.now().Sub(s.fetchedAt) > minJwksRefetch
+}
+
+// refresh keeps the previous keys when the document cannot be fetched so a short outage of the auth service does not reject every token
+func (s *KeySet) refresh() {
+	keys, err := s.fetch()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.fetchedAt = s.now()
+	if err != nil {
+		log.Printf("cannot fetch the JWKS document: %v\n", err)
+		return
+	}
+
+	s.remote = keys
+}
+
+func (s *KeySet) fetch() ([]*JwtKey, error) {
+	res, err := s.client.Get(s.url)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %v", res.StatusCode)
+	}
+
+	doc := struct {
+		Keys []*jwk `json:"keys"`
+	}{}
+	if err := json.NewDecoder(res.Body).Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	var keys []*JwtKey
+	for _, k := range doc.Keys {
+		key, err := k.toKey()
+		if err != nil {
+			log.Printf("skip JWKS key %q: %v\n", k.Kid, err)
+			continue
+		}
+
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+var jwtAlgorithms = map[string]crypto.Hash{
+	"HS256": crypto.SHA256,
+	"HS384": crypto.SHA384,
+	"HS512": crypto.SHA512,
+	"RS256": crypto.SHA256,
+	"RS384": crypto.SHA384,
+	"RS512": crypto.SHA512,
+	"ES256": crypto.SHA256,
+	"ES384": crypto.SHA384,
+}
@@ -0,0 +1,288 @@
+package service
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/dto"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type JwtOptions struct {
+	Issuer      string
+	Audience    string
+	ClockSkew   time.Duration
+	UserIDClaim string
+}
+
+// JwtService verifies the access tokens in the gateway instead of asking the auth service
+type JwtService struct {
+	keys    *KeySet
+	options JwtOptions
+	now     func() time.Time
+}
+
+func NewJwtService(keys *KeySet, options JwtOptions) *JwtService {
+	if options.UserIDClaim == "" {
+		options.UserIDClaim = "sub"
+	}
+
+	return &JwtService{
+		keys:    keys,
+		options: options,
+		now:     time.Now,
+	}
+}
+
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+func (s *JwtService) Validate(_ context.Context, token string) (uint32, *dto.ResponseErr) {
+	userId, err := s.Verify(token)
+	if errors.Is(err, ErrKeyUnavailable) {
+		return 0, &dto.ResponseErr{
+			StatusCode: http.StatusServiceUnavailable,
+			Message:    "Cannot verify the token",
+			Data:       nil,
+		}
+	}
+
+	if err != nil {
+		return 0, &dto.ResponseErr{
+			StatusCode: http.StatusUnauthorized,
+			Message:    "Invalid token",
+			Data:       nil,
+		}
+	}
+
+	return userId, nil
+}
+
+// Verify checks the signature and the claims of the token and returns the id of its user
+func (s *JwtService) Verify(token string) (uint32, error) {
+	claims, err := s.VerifyClaims(token)
+	if err != nil {
+		return 0, err
+	}
+
+	return userIDFromClaim(claims[s.options.UserIDClaim])
+}
+
+// VerifyClaims checks the signature, the expiry, the issuer and the audience of the token and returns all of its claims
+func (s *JwtService) VerifyClaims(token string) (map[string]interface{}, error) {
+	token = strings.TrimSpace(token)
+	if len(token) > 7 && strings.EqualFold(token[:7], "Bearer ") {
+		token = strings.TrimSpace(token[7:])
+	}
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("malformed token")
+	}
+
+	header := jwtHeader{}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("invalid header: %v", err)
+	}
+
+	if _, ok := jwtAlgorithms[header.Alg]; !ok {
+		return nil, fmt.Errorf("unsupported algorithm %q", header.Alg)
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature: %v", err)
+	}
+
+	keys, err := s.keys.Find(header.Kid, header.Alg)
+	if err != nil {
+		return nil, err
+	}
+
+	signed := []byte(parts[0] + "." + parts[1])
+
+	verified := false
+	for _, key := range keys {
+		if verifySignature(key, signed, sig) {
+			verified = true
+			break
+		}
+	}
+
+	if !verified {
+		return nil, errors.New("invalid signature")
+	}
+
+	claims := map[string]interface{}{}
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("invalid claims: %v", err)
+	}
+
+	if err := s.checkClaims(claims); err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
+
+func (s *JwtService) checkClaims(claims map[string]interface{}) error {
+	now := s.now()
+
+	exp, ok := numericClaim(claims, "exp")
+	if !ok {
+		return errors.New("token does not expire")
+	}
+	if now.After(time.Unix(exp, 0).Add(s.options.ClockSkew)) {
+		return errors.New("token is expired")
+	}
+
+	if nbf, ok := numericClaim(claims, "nbf"); ok && now.Add(s.options.ClockSkew).Before(time.Unix(nbf, 0)) {
+		return errors.New("token is not valid yet")
+	}
+
+	if iat, ok := numericClaim(claims, "iat"); ok && now.Add(s.options.ClockSkew).Before(time.Unix(iat, 0)) {
+		return errors.New("token is issued in the future")
+	}
+
+	if s.options.Issuer != "" && claims["iss"] != s.options.Issuer {
+		return errors.New("invalid issuer")
+	}
+
+	if s.options.Audience != "" && !hasAudience(claims["aud"], s.options.Audience) {
+		return errors.New("invalid audience")
+	}
+
+	return nil
+}
+
+func decodeSegment(seg string, v interface{}) error {
+	b, err := base64.RawURLEncoding.DecodeString(seg)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(b, v)
+}
+
+func verifySignature(key *JwtKey, signed []byte, sig []byte) bool {
+	hash := jwtAlgorithms[key.Alg]
+	h := hash.New()
+	h.Write(signed)
+	digest := h.Sum(nil)
+
+	switch k := key.Key.(type) {
+	case []byte:
+		mac := hmac.New(hash.New, k)
+		mac.Write(signed)
+		return hmac.Equal(sig, mac.Sum(nil))
+	case *rsa.PublicKey:
+		return rsa.VerifyPKCS1v15(k, hash, digest, sig) == nil
+	case *ecdsa.PublicKey:
+		size := (k.Curve.Params().BitSize + 7) / 8
+		if len(sig) != 2*size {
+			return false
+		}
+		r := new(big.Int).SetBytes(sig[:size])
+		s := new(big.Int).SetBytes(sig[size:])
+		return ecdsa.Verify(k, digest, r, s)
+	default:
+		return false
+	}
+}
+
+func numericClaim(claims map[string]interface{}, name string) (int64, bool) {
+	v, ok := claims[name].(float64)
+	if !ok {
+		return 0, false
+	}
+
+	return int64(v), true
+}
+
+func hasAudience(aud interface{}, want string) bool {
+	switch v := aud.(type) {
+	case string:
+		return v == want
+	case []interface{}:
+		for _, a := range v {
+			if a == want {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func userIDFromClaim(v interface{}) (uint32, error) {
+	var id uint64
+	var err error
+
+	switch c := v.(type) {
+	case float64:
+		id = uint64(c)
+		if float64(id) != c {
+			err = errors.New("user id must be an integer")
+		}
+	case string:
+		id, err = strconv.ParseUint(c, 10, 32)
+	default:
+		err = errors.New("token does not have the user id")
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	if id == 0 || id > uint64(^uint32(0)) {
+		return 0, errors.New("invalid user id")
+	}
+
+	return uint32(id), nil
+}
+
+// FallbackValidator is for the remote Validate call, the AuthService implements it
+type FallbackValidator interface {
+	Validate(context.Context, string) (uint32, *dto.ResponseErr)
+}
+
+// HybridTokenService verifies the tokens locally and only asks the auth service when the signing key is not available
+type HybridTokenService struct {
+	local    *JwtService
+	fallback FallbackValidator
+}
+
+func NewHybridTokenService(local *JwtService, fallback FallbackValidator) *HybridTokenService {
+	return &HybridTokenService{
+		local:    local,
+		fallback: fallback,
+	}
+}
+
+func (s *HybridTokenService) Validate(ctx context.Context, token string) (uint32, *dto.ResponseErr) {
+	userId, err := s.local.Verify(token)
+	if errors.Is(err, ErrKeyUnavailable) {
+		return s.fallback.Validate(ctx, token)
+	}
+
+	if err != nil {
+		return 0, &dto.ResponseErr{
+			StatusCode: http.StatusUnauthorized,
+			Message:    "Invalid token",
+			Data:       nil,
+		}
+	}
+
+	return userId, nil
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/dto"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/proto"
+	"net/http"
+	"time"
+)
+
+type LocationService struct {
+	client proto.LocationServiceClient
+}
+
+func NewLocationService(client proto.LocationServiceClient) *LocationService {
+	return &LocationService{
+		client: client,
+	}
+}
+
+func (s *LocationService) FindOne(ctx context.Context, id int32) (result *proto.Location, err *dto.ResponseErr) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	res, errRes := s.client.FindOne(ctx, &proto.FindOneLocationRequest{Id: id})
+	if errRes != nil {
+		logUpstreamErr(ctx, errRes)
+		return nil, &dto.ResponseErr{
+			StatusCode: http.StatusServiceUnavailable,
+			Message:    "Service is down",
+			Data:       nil,
+		}
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return nil, &dto.ResponseErr{
+			StatusCode: int(res.StatusCode),
+			Message:    FormatErr(res.Errors),
+			Data:       nil,
+		}
+	}
+
+	result = res.Data
+
+	return
+}
+
+func (s *LocationService) Create(ctx context.Context, locationDto *dto.LocationDto) (result *proto.Location, err *dto.ResponseErr) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	location := s.DtoToRaw(locationDto)
+
+	res, errRes := s.client.Create(ctx, &proto.CreateLocationRequest{Location: location})
+	if errRes != nil {
+		logUpstreamErr(ctx, errRes)
+		return nil, &dto.ResponseErr{
+			StatusCode: http.StatusServiceUnavailable,
+			Message:    "Service is down",
+			Data:       nil,
+		}
+	}
+
+	if res.StatusCode != http.StatusCreated {
+		return nil, &dto.ResponseErr{
+			StatusCode: int(res.StatusCode),
+			Message:    FormatErr(res.Errors),
+			Data:       nil,
+		}
+	}
+
+	result = res.Data
+	return
+}
+
+func (s *LocationService) Update(ctx context.Context, id int32, locationDto *dto.LocationDto) (result *proto.Location, err *dto.ResponseErr) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	location := s.DtoToRaw(locationDto)
+	location.Id = uint32(id)
+
+	res, errRes := s.client.Update(ctx, &proto.UpdateLocationRequest{Location: location})
+	if errRes != nil {
+		logUpstreamErr(ctx, errRes)
+		return nil, &dto.ResponseErr{
+			StatusCode: http.StatusServiceUnavailable,
+			Message:    "Service is down",
+			Data:       nil,
+		}
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return nil, &dto.ResponseErr{
+			StatusCode: int(res.StatusCode),
+			Message:    FormatErr(res.Errors),
+			Data:       nil,
+		}
+	}
+
+	result = res.Data
+
+	return
+}
+
+func (s *LocationService) Delete(ctx context.Context, id int32) (result *proto.Location, err *dto.ResponseErr) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	res, errRes := s.client.Delete(ctx, &proto.DeleteLocationRequest{Id: id})
+	if errRes != nil {
+		logUpstreamErr(ctx, errRes)
+		return nil, &dto.ResponseErr{
+			StatusCode: http.StatusServiceUnavailable,
+			Message:    "Service is down",
+			Data:       nil,
+		}
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return nil, &dto.ResponseErr{
+			StatusCode: int(res.StatusCode),
+			Message:    FormatErr(res.Errors),
+			Data:       nil,
+		}
+	}
+
+	result = res.Data
+
+	return
+}
+
+func (LocationService) DtoToRaw(locationDto *dto.LocationDto) *proto.Location {
+	return &proto.Location{
+		Address:  locationDto.Address,
+		District: locationDto.District,
+		Province: locationDto.Province,
+		Country:  locationDto.Country,
+		Zipcode:  locationDto.Zipcode,
+	}
+}
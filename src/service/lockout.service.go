@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// LockoutPolicy locks the key out when it fails MaxFailures times inside the Window, every lockout in a row
+// doubles the lock duration starting from BaseLockout up to MaxLockout
+type LockoutPolicy struct {
+	Window      time.Duration
+	MaxFailures int
+	BaseLockout time.Duration
+	MaxLockout  time.Duration
+}
+
+type LockoutState struct {
+	Failures    []time.Time
+	Lockouts    int
+	LockedUntil time.Time
+	ExpiresAt   time.Time
+}
+
+// LockoutStore keeps the failed attempts, the store may drop a state after its ExpiresAt
+type LockoutStore interface {
+	Get(string) (*LockoutState, error)
+	Set(string, *LockoutState) error
+	Delete(string) error
+}
+
+type LockoutService struct {
+	store    LockoutStore
+	policies map[string]LockoutPolicy
+	now      func() time.Time
+	mu       sync.Mutex
+}
+
+func NewLockoutService(store LockoutStore, policies map[string]LockoutPolicy) *LockoutService {
+	return &LockoutService{
+		store:    store,
+		policies: policies,
+		now:      time.Now,
+	}
+}
+
+// Check returns how long the key is still locked out, zero when it may try again
+func (s *LockoutService) Check(ctx context.Context, scope string, key string) time.Duration {
+	if _, ok := s.policies[scope]; !ok {
+		return 0
+	}
+
+	state, err := s.store.Get(scope + ":" + key)
+	if err != nil {
+		logf(ctx, "cannot get the lockout of %v %v: %v\n", scope, key, err)
+		return 0
+	}
+
+	if state == nil {
+		return 0
+	}
+
+	if wait := state.LockedUntil.Sub(s.now()); wait > 0 {
+		return wait
+	}
+
+	return 0
+}
+
+func (s *LockoutService) Fail(ctx context.Context, scope string, key string) {
+	policy, ok := s.policies[scope]
+	if !ok || policy.MaxFailures <= 0 {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := scope + ":" + key
+	state, err := s.store.Get(id)
+	if err != nil {
+		logf(ctx, "cannot get the lockout of %v %v: %v\n", scope, key, err)
+		return
+	}
+
+	now := s.now()
+	if state == nil {
+		state = &LockoutState{}
+	}
+
+	// the failures that slid out of the window are forgotten, so are the previous lockouts after a quiet window
+	var failures []time.Time
+	for _, f := range state.Failures {
+		if now.Sub(f) < policy.Window {
+			failures = append(failures, f)
+		}
+	}
+	if len(failures) == 0 && now.Sub(state.LockedUntil) > policy.Window {
+		state.Lockouts = 0
+	}
+	state.Failures = append(failures, now)
+
+	if len(state.Failures) >= policy.MaxFailures {
+		state.Lockouts++
+		state.LockedUntil = now.Add(lockoutDuration(policy, state.Lockouts))
+		state.Failures = nil
+	}
+
+	state.ExpiresAt = now.Add(policy.Window)
+	if state.LockedUntil.Add(policy.Window).After(state.ExpiresAt) {
+		state.ExpiresAt = state.LockedUntil.Add(policy.Window)
+	}
+
+	if err := s.store.Set(id, state); err != nil {
+		logf(ctx, "cannot set the lockout of %v %v: %v\n", scope, key, err)
+	}
+}
+
+func (s *LockoutService) Reset(ctx context.Context, scope string, key string) {
+	if err := s.store.Delete(scope + ":" + key); err != nil {
+		logf(ctx, "cannot reset the lockout of %v %v: %v\n", scope, key, err)
+	}
+}
+
+func lockoutDuration(policy LockoutPolicy, lockouts int) time.Duration {
+	d := policy.BaseLockout
+	for i := 1; i < lockouts; i++ {
+		d *= 2
+		if policy.MaxLockout > 0 && d >= policy.MaxLockout {
+			return policy.MaxLockout
+		}
+	}
+
+	if policy.MaxLockout > 0 && d > policy.MaxLockout {
+		return policy.MaxLockout
+	}
+
+	return d
+}
+
+type MemoryLockoutStore struct {
+	mu      sync.Mutex
+	states  map[string]*LockoutState
+	sweeper sweeper
+	now     func() time.Time
+}
+
+func NewMemoryLockoutStore() *MemoryLockoutStore {
+	return &MemoryLockoutStore{
+		states: map[string]*LockoutState{},
+		now:    time.Now,
+	}
+}
+
+func (s *MemoryLockoutStore) Get(key string) (*LockoutState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, ok := s.states[key]
+	if !ok {
+		return nil, nil
+	}
+
+	if !s.now().Before(state.ExpiresAt) {
+		delete(s.states, key)
+		return nil, nil
+	}
+
+	copied := *state
+	copied.Failures = append([]time.Time{}, state.Failures...)
+
+	return &copied, nil
+}
+
+func (s *MemoryLockoutStore) Set(key string, state *LockoutState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweeper.run(now, func() {
+		for k, st := range s.states {
+			if !now.Before(st.ExpiresAt) {
+				delete(s.states, k)
+			}
+		}
+	})
+
+	s.states[key] = state
+
+	return nil
+}
+
+func (s *MemoryLockoutStore) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.states, key)
+
+	return nil
+}
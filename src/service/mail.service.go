@@ -0,0 +1,91 @@
+package service
+
+import (
+	"fmt"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/dto"
+	"log"
+	"net/smtp"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+type Mailer interface {
+	Send(*dto.Mail) error
+}
+
+type SmtpMailer struct {
+	addr string
+	auth smtp.Auth
+	from string
+}
+
+// NewSmtpMailer sends the mails through the SMTP server, the server is not authenticated when the username is empty
+func NewSmtpMailer(host string, port int, username string, password string, from string) *SmtpMailer {
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+
+	return &SmtpMailer{
+		addr: fmt.Sprintf("%v:%v", host, port),
+		auth: auth,
+		from: from,
+	}
+}
+
+func (m *SmtpMailer) Send(mail *dto.Mail) error {
+	return smtp.SendMail(m.addr, m.auth, m.from, []string{mail.To}, formatMail(m.from, mail))
+}
+
+// LogMailer prints the mails instead of sending them, it is for the development
+type LogMailer struct{}
+
+func (LogMailer) Send(mail *dto.Mail) error {
+	log.Printf("mail to %v: %v\n%v\n", mail.To, mail.Subject, mail.Body)
+
+	return nil
+}
+
+// FileMailer appends the mails to the file, it is for the development and the end to end tests
+type FileMailer struct {
+	mu   sync.Mutex
+	path string
+	from string
+}
+
+func NewFileMailer(path string, from string) *FileMailer {
+	return &FileMailer{
+		path: path,
+		from: from,
+	}
+}
+
+func (m *FileMailer) Send(mail *dto.Mail) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	f, err := os.OpenFile(m.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = f.Write(append(formatMail(m.from, mail), "\r\n"...))
+
+	return err
+}
+
+func formatMail(from string, mail *dto.Mail) []byte {
+	headers := []string{
+		"From: " + from,
+		"To: " + mail.To,
+		"Subject: " + mail.Subject,
+		"Date: " + time.Now().Format(time.RFC1123Z),
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=UTF-8",
+	}
+
+	return []byte(strings.Join(headers, "\r\n") + "\r\n\r\n" + mail.Body + "\r\n")
+}
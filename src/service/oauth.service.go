@@ -0,0 +1,395 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/dto"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/proto"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// OAuthProvider is an OpenID Connect provider, the endpoints that are empty are discovered from the issuer
+type OAuthProvider struct {
+	Name             string
+	Issuer           string
+	ClientID         string
+	ClientSecret     string
+	RedirectUrl      string
+	Scopes           []string
+	AuthorizationUrl string
+	TokenUrl         string
+	JwksUrl          string
+}
+
+// AccountService is the part of the AuthService that signs in the users of the providers
+type AccountService interface {
+	Register(context.Context, *dto.Register) (*proto.User, *dto.ResponseErr)
+	Login(context.Context, *dto.Login) (*proto.Credential, *dto.ResponseErr)
+}
+
+type oauthProvider struct {
+	mu       sync.Mutex
+	config   OAuthProvider
+	verifier *JwtService
+}
+
+type oauthState struct {
+	provider  string
+	verifier  string
+	nonce     string
+	expiresAt time.Time
+}
+
+// OAuthService runs the authorization code flow with PKCE, the pending logins are kept in memory until the provider calls back
+type OAuthService struct {
+	providers     map[string]*oauthProvider
+	accounts      AccountService
+	accountSecret []byte
+	stateTTL      time.Duration
+	client        *http.Client
+	now           func() time.Time
+
+	mu      sync.Mutex
+	states  map[string]*oauthState
+	sweeper sweeper
+}
+
+func NewOAuthService(providers []OAuthProvider, accounts AccountService, accountSecret string, stateTTL time.Duration) *OAuthService {
+	s := &OAuthService{
+		providers:     map[string]*oauthProvider{},
+		accounts:      accounts,
+		accountSecret: []byte(accountSecret),
+		stateTTL:      stateTTL,
+		client:        &http.Client{Timeout: 10 * time.Second},
+		now:           time.Now,
+		states:        map[string]*oauthState{},
+	}
+
+	for _, p := range providers {
+		s.providers[p.Name] = &oauthProvider{config: p}
+	}
+
+	return s
+}
+
+// AuthorizationUrl starts the login and returns the url of the provider that the user has to be redirected to
+func (s *OAuthService) AuthorizationUrl(name string) (string, *dto.ResponseErr) {
+	p, errRes := s.provider(name)
+	if errRes != nil {
+		return "", errRes
+	}
+
+	state, err := randomToken()
+	if err != nil {
+		return "", responseErr(http.StatusInternalServerError, "Cannot start the login")
+	}
+	verifier, err := randomToken()
+	if err != nil {
+		return "", responseErr(http.StatusInternalServerError, "Cannot start the login")
+	}
+	nonce, err := randomToken()
+	if err != nil {
+		return "", responseErr(http.StatusInternalServerError, "Cannot start the login")
+	}
+
+	s.saveState(state, &oauthState{
+		provider:  name,
+		verifier:  verifier,
+		nonce:     nonce,
+		expiresAt: s.now().Add(s.stateTTL),
+	})
+
+	challenge := sha256.Sum256([]byte(verifier))
+
+	scopes := p.config.Scopes
+	if len(scopes) == 0 {
+		scopes = []string{"openid", "email", "profile"}
+	}
+
+	query := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {p.config.ClientID},
+		"redirect_uri":          {p.config.RedirectUrl},
+		"scope":                 {strings.Join(scopes, " ")},
+		"state":                 {state},
+		"nonce":                 {nonce},
+		"code_challenge":        {base64.RawURLEncoding.EncodeToString(challenge[:])},
+		"code_challenge_method": {"S256"},
+	}
+
+	sep := "?"
+	if strings.Contains(p.config.AuthorizationUrl, "?") {
+		sep = "&"
+	}
+
+	return p.config.AuthorizationUrl + sep + query.Encode(), nil
+}
+
+// Exchange redeems the code of the callback and returns the identity in the verified ID token
+func (s *OAuthService) Exchange(name string, callback *dto.OAuthCallback) (*dto.OAuthIdentity, *dto.ResponseErr) {
+	p, errRes := s.provider(name)
+	if errRes != nil {
+		return nil, errRes
+	}
+
+	if callback.Error != "" {
+		return nil, &dto.ResponseErr{
+			StatusCode: http.StatusUnauthorized,
+			Message:    "Login is denied by the provider",
+			Data:       callback.Error,
+		}
+	}
+
+	state := s.takeState(callback.State)
+	if state == nil || state.provider != name || callback.Code == "" {
+		return nil, responseErr(http.StatusBadRequest, "Invalid callback")
+	}
+
+	idToken, errRes := s.redeem(p, callback.Code, state.verifier)
+	if errRes != nil {
+		return nil, errRes
+	}
+
+	claims, err := p.verifier.VerifyClaims(idToken)
+	if errors.Is(err, ErrKeyUnavailable) {
+		return nil, responseErr(http.StatusServiceUnavailable, "Cannot verify the ID token")
+	}
+
+	if err != nil {
+		log.Printf("invalid ID token from %v: %v\n", name, err)
+		return nil, responseErr(http.StatusUnauthorized, "Invalid ID token")
+	}
+
+	if nonce, _ := claims["nonce"].(string); nonce != state.nonce {
+		return nil, responseErr(http.StatusUnauthorized, "Invalid ID token")
+	}
+
+	return identityFromClaims(name, claims)
+}
+
+// SignIn logs in the account of the identity and creates it on the first login, the password of the account is derived
+// from the identity because the auth service cannot issue a credential without one
+func (s *OAuthService) SignIn(ctx context.Context, identity *dto.OAuthIdentity) (*proto.Credential, *dto.ResponseErr) {
+	login := &dto.Login{
+		Email:    identity.Email,
+		Password: s.accountPassword(identity),
+	}
+
+	credential, errRes := s.accounts.Login(ctx, login)
+	if errRes == nil {
+		return credential, nil
+	}
+
+	if errRes.StatusCode != http.StatusUnauthorized && errRes.StatusCode != http.StatusNotFound {
+		return nil, errRes
+	}
+
+	_, errRes = s.accounts.Register(ctx, &dto.Register{
+		Email:       identity.Email,
+		Password:    login.Password,
+		Firstname:   identity.Firstname,
+		Lastname:    identity.Lastname,
+		DisplayName: identity.DisplayName,
+		ImageUrl:    identity.ImageUrl,
+	})
+	if errRes != nil {
+		// the email belongs to an account with its own password, it is not linked to the provider silently
+		if errRes.StatusCode == http.StatusUnprocessableEntity {
+			return nil, responseErr(http.StatusConflict, "Email is already registered with a password")
+		}
+
+		return nil, errRes
+	}
+
+	return s.accounts.Login(ctx, login)
+}
+
+func (s *OAuthService) accountPassword(identity *dto.OAuthIdentity) string {
+	mac := hmac.New(sha256.New, s.accountSecret)
+	mac.Write([]byte(identity.Provider + "\n" + identity.Subject))
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func (s *OAuthService) provider(name string) (*oauthProvider, *dto.ResponseErr) {
+	p, ok := s.providers[name]
+	if !ok {
+		return nil, responseErr(http.StatusNotFound, "Unknown provider")
+	}
+
+	if err := s.discover(p); err != nil {
+		log.Printf("cannot discover the provider %v: %v\n", name, err)
+		return nil, responseErr(http.StatusServiceUnavailable, "Cannot reach the provider")
+	}
+
+	return p, nil
+}
+
+// discover fills the missing endpoints from the discovery document of the issuer, a failed discovery is retried on the next login
+func (s *OAuthService) discover(p *oauthProvider) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.verifier != nil {
+		return nil
+	}
+
+	if p.config.AuthorizationUrl == "" || p.config.TokenUrl == "" || p.config.JwksUrl == "" {
+		doc := struct {
+			Issuer                string `json:"issuer"`
+			AuthorizationEndpoint string `json:"authorization_endpoint"`
+			TokenEndpoint         string `json:"token_endpoint"`
+			JwksUri               string `json:"jwks_uri"`
+		}{}
+
+		res, err := s.client.Get(strings.TrimRight(p.config.Issuer, "/") + "/.well-known/openid-configuration")
+		if err != nil {
+			return err
+		}
+		defer res.Body.Close()
+
+		if res.StatusCode != http.StatusOK {
+			return fmt.Errorf("unexpected status %v", res.StatusCode)
+		}
+
+		if err := json.NewDecoder(res.Body).Decode(&doc); err != nil {
+			return err
+		}
+
+		if doc.Issuer != p.config.Issuer {
+			return fmt.Errorf("the document is issued by %q", doc.Issuer)
+		}
+
+		if p.config.AuthorizationUrl == "" {
+			p.config.AuthorizationUrl = doc.AuthorizationEndpoint
+		}
+		if p.config.TokenUrl == "" {
+			p.config.TokenUrl = doc.TokenEndpoint
+		}
+		if p.config.JwksUrl == "" {
+			p.config.JwksUrl = doc.JwksUri
+		}
+	}
+
+	p.verifier = NewJwtService(NewKeySet(nil, p.config.JwksUrl, time.Hour), JwtOptions{
+		Issuer:    p.config.Issuer,
+		Audience:  p.config.ClientID,
+		ClockSkew: time.Minute,
+	})
+
+	return nil
+}
+
+func (s *OAuthService) redeem(p *oauthProvider, code string, verifier string) (string, *dto.ResponseErr) {
+	res, err := s.client.PostForm(p.config.TokenUrl, url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.config.RedirectUrl},
+		"client_id":     {p.config.ClientID},
+		"client_secret": {p.config.ClientSecret},
+		"code_verifier": {verifier},
+	})
+	if err != nil {
+		log.Printf("cannot redeem the code of %v: %v\n", p.config.Name, err)
+		return "", responseErr(http.StatusServiceUnavailable, "Cannot reach the provider")
+	}
+	defer res.Body.Close()
+
+	body := struct {
+		IdToken string `json:"id_token"`
+	}{}
+
+	if res.StatusCode != http.StatusOK || json.NewDecoder(res.Body).Decode(&body) != nil || body.IdToken == "" {
+		return "", responseErr(http.StatusUnauthorized, "Cannot redeem the code")
+	}
+
+	return body.IdToken, nil
+}
+
+func (s *OAuthService) saveState(key string, state *oauthState) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweeper.run(now, func() {
+		for k, st := range s.states {
+			if !now.Before(st.expiresAt) {
+				delete(s.states, k)
+			}
+		}
+	})
+
+	s.states[key] = state
+}
+
+// takeState returns the pending login only once so the callback cannot be replayed
+func (s *OAuthService) takeState(key string) *oauthState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, ok := s.states[key]
+	if !ok {
+		return nil
+	}
+
+	delete(s.states, key)
+	if !s.now().Before(state.expiresAt) {
+		return nil
+	}
+
+	return state
+}
+
+func identityFromClaims(provider string, claims map[string]interface{}) (*dto.OAuthIdentity, *dto.ResponseErr) {
+	str := func(name string) string {
+		v, _ := claims[name].(string)
+		return v
+	}
+
+	identity := &dto.OAuthIdentity{
+		Provider:    provider,
+		Subject:     str("sub"),
+		Email:       str("email"),
+		Firstname:   str("given_name"),
+		Lastname:    str("family_name"),
+		DisplayName: str("name"),
+		ImageUrl:    str("picture"),
+	}
+
+	if identity.Subject == "" || identity.Email == "" {
+		return nil, responseErr(http.StatusUnauthorized, "The provider does not share the email")
+	}
+
+	if verified, ok := claims["email_verified"].(bool); ok && !verified {
+		return nil, responseErr(http.StatusForbidden, "Email is not verified by the provider")
+	}
+
+	if identity.DisplayName == "" {
+		identity.DisplayName = strings.SplitN(identity.Email, "@", 2)[0]
+	}
+	if identity.Firstname == "" {
+		identity.Firstname = identity.DisplayName
+	}
+
+	return identity, nil
+}
+
+func randomToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -2,9 +2,9 @@ package service
 
 import (
 	"context"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/common"
 	"github.com/samithiwat/samithiwat-backend-gateway/src/dto"
 	"github.com/samithiwat/samithiwat-backend-gateway/src/proto"
-	"log"
 	"net/http"
 	"time"
 )
@@ -19,8 +19,8 @@ func NewOrganizationService(client proto.OrganizationServiceClient) *Organizatio
 	}
 }
 
-func (s *OrganizationService) FindAll(query *dto.PaginationQueryParams) (result *proto.OrganizationPagination, err *dto.ResponseErr) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+func (s *OrganizationService) FindAll(ctx context.Context, query *dto.PaginationQueryParams) (result *proto.OrganizationPagination, err *dto.ResponseErr) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
 	req := &proto.FindAllOrganizationRequest{
@@ -30,7 +30,7 @@ func (s *OrganizationService) FindAll(query *dto.PaginationQueryParams) (result
 
 	res, errRes := s.client.FindAll(ctx, req)
 	if errRes != nil {
-		log.Printf("%v\n", errRes)
+		logUpstreamErr(ctx, errRes)
 		return nil, &dto.ResponseErr{
 			StatusCode: http.StatusServiceUnavailable,
 			Message:    "Service is down",
@@ -51,13 +51,13 @@ func (s *OrganizationService) FindAll(query *dto.PaginationQueryParams) (result
 	return
 }
 
-func (s *OrganizationService) FindOne(id int32) (result *proto.Organization, err *dto.ResponseErr) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+func (s *OrganizationService) FindOne(ctx context.Context, id int32) (result *proto.Organization, err *dto.ResponseErr) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
 	res, errRes := s.client.FindOne(ctx, &proto.FindOneOrganizationRequest{Id: id})
 	if errRes != nil {
-		log.Printf("%v\n", errRes)
+		logUpstreamErr(ctx, errRes)
 		return nil, &dto.ResponseErr{
 			StatusCode: http.StatusServiceUnavailable,
 			Message:    "Service is down",
@@ -78,15 +78,13 @@ func (s *OrganizationService) FindOne(id int32) (result *proto.Organization, err
 	return
 }
 
-func (s *OrganizationService) Create(organizationDto *dto.OrganizationDto) (result *proto.Organization, err *dto.ResponseErr) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+func (s *OrganizationService) FindMulti(ctx context.Context, ids []int32) (result []*proto.Organization, err *dto.ResponseErr) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
-	organization := s.DtoToRaw(organizationDto)
-
-	res, errRes := s.client.Create(ctx, &proto.CreateOrganizationRequest{Organization: organization})
+	res, errRes := s.client.FindMulti(ctx, &proto.FindMultiOrganizationRequest{Ids: ToUintIDs(ids)})
 	if errRes != nil {
-		log.Printf("%v\n", errRes)
+		logUpstreamErr(ctx, errRes)
 		return nil, &dto.ResponseErr{
 			StatusCode: http.StatusServiceUnavailable,
 			Message:    "Service is down",
@@ -94,7 +92,7 @@ func (s *OrganizationService) Create(organizationDto *dto.OrganizationDto) (resu
 		}
 	}
 
-	if res.StatusCode != http.StatusCreated {
+	if res.StatusCode != http.StatusOK {
 		return nil, &dto.ResponseErr{
 			StatusCode: int(res.StatusCode),
 			Message:    FormatErr(res.Errors),
@@ -102,20 +100,23 @@ func (s *OrganizationService) Create(organizationDto *dto.OrganizationDto) (resu
 		}
 	}
 
-	result = res.Data
+	result = []*proto.Organization{}
+	for _, i := range common.OrderByIDs(ids, len(res.Data), func(i int) uint32 { return res.Data[i].Id }) {
+		result = append(result, res.Data[i])
+	}
+
 	return
 }
 
-func (s *OrganizationService) Update(id int32, organizationDto *dto.OrganizationDto) (result *proto.Organization, err *dto.ResponseErr) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+func (s *OrganizationService) Create(ctx context.Context, organizationDto *dto.OrganizationDto) (result *proto.Organization, err *dto.ResponseErr) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
 	organization := s.DtoToRaw(organizationDto)
-	organization.Id = uint32(id)
 
-	res, errRes := s.client.Update(ctx, &proto.UpdateOrganizationRequest{Organization: organization})
+	res, errRes := s.client.Create(ctx, &proto.CreateOrganizationRequest{Organization: organization})
 	if errRes != nil {
-		log.Printf("%v\n", errRes)
+		logUpstreamErr(ctx, errRes)
 		return nil, &dto.ResponseErr{
 			StatusCode: http.StatusServiceUnavailable,
 			Message:    "Service is down",
@@ -123,7 +124,7 @@ func (s *OrganizationService) Update(id int32, organizationDto *dto.Organization
 		}
 	}
 
-	if res.StatusCode != http.StatusOK {
+	if res.StatusCode != http.StatusCreated {
 		return nil, &dto.ResponseErr{
 			StatusCode: int(res.StatusCode),
 			Message:    FormatErr(res.Errors),
@@ -132,17 +133,75 @@ func (s *OrganizationService) Update(id int32, organizationDto *dto.Organization
 	}
 
 	result = res.Data
-
 	return
 }
 
-func (s *OrganizationService) Delete(id int32) (result *proto.Organization, err *dto.ResponseErr) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+func (s *OrganizationService) Update(ctx context.Context, id int32, organizationDto *dto.OrganizationDto) (result *proto.Organization, err *dto.ResponseErr) {
+	organization := s.DtoToRaw(organizationDto)
+	organization.Id = uint32(id)
+
+	return s.update(ctx, organization)
+}
+
+func (s *OrganizationService) FindMembers(ctx context.Context, id int32, query *dto.PaginationQueryParams) (result *proto.UserPagination, err *dto.ResponseErr) {
+	organization, err := s.FindOne(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return PaginateUsers(organization.Members, query), nil
+}
+
+func (s *OrganizationService) AddMember(ctx context.Context, id int32, user *proto.User) (result *proto.Organization, err *dto.ResponseErr) {
+	organization, err := s.FindOne(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, member := range organization.Members {
+		if member.Id == user.Id {
+			return organization, nil
+		}
+	}
+
+	organization.Members = append(organization.Members, user)
+
+	return s.update(ctx, organization)
+}
+
+func (s *OrganizationService) RemoveMember(ctx context.Context, id int32, userId int32) (result *proto.Organization, err *dto.ResponseErr) {
+	organization, err := s.FindOne(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var members []*proto.User
+	for _, member := range organization.Members {
+		if int32(member.Id) != userId {
+			members = append(members, member)
+		}
+	}
+
+	if len(members) == len(organization.Members) {
+		return nil, &dto.ResponseErr{
+			StatusCode: http.StatusNotFound,
+			Message:    "The organization does not have this member",
+			Data:       nil,
+		}
+	}
+
+	organization.Members = members
+
+	return s.update(ctx, organization)
+}
+
+func (s *OrganizationService) Delete(ctx context.Context, id int32) (result *proto.Organization, err *dto.ResponseErr) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
 	res, errRes := s.client.Delete(ctx, &proto.DeleteOrganizationRequest{Id: id})
 	if errRes != nil {
-		log.Printf("%v\n", errRes)
+		logUpstreamErr(ctx, errRes)
 		return nil, &dto.ResponseErr{
 			StatusCode: http.StatusServiceUnavailable,
 			Message:    "Service is down",
@@ -164,9 +223,46 @@ func (s *OrganizationService) Delete(id int32) (result *proto.Organization, err
 }
 
 func (OrganizationService) DtoToRaw(organizationDto *dto.OrganizationDto) *proto.Organization {
-	return &proto.Organization{
+	organization := &proto.Organization{
 		Name:        organizationDto.Name,
 		Email:       organizationDto.Email,
 		Description: organizationDto.Description,
 	}
+
+	if organizationDto.Contact != nil {
+		organization.Contact = ContactService{}.DtoToRaw(organizationDto.Contact)
+	}
+
+	if organizationDto.Location != nil {
+		organization.Location = LocationService{}.DtoToRaw(organizationDto.Location)
+	}
+
+	return organization
+}
+
+func (s *OrganizationService) update(ctx context.Context, organization *proto.Organization) (result *proto.Organization, err *dto.ResponseErr) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	res, errRes := s.client.Update(ctx, &proto.UpdateOrganizationRequest{Organization: organization})
+	if errRes != nil {
+		logUpstreamErr(ctx, errRes)
+		return nil, &dto.ResponseErr{
+			StatusCode: http.StatusServiceUnavailable,
+			Message:    "Service is down",
+			Data:       nil,
+		}
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return nil, &dto.ResponseErr{
+			StatusCode: int(res.StatusCode),
+			Message:    FormatErr(res.Errors),
+			Data:       nil,
+		}
+	}
+
+	result = res.Data
+
+	return
 }
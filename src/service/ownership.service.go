@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/constant"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/dto"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/proto"
+	"net/http"
+)
+
+type TeamFinder interface {
+	FindOne(context.Context, int32) (*proto.Team, *dto.ResponseErr)
+}
+
+type OrganizationFinder interface {
+	FindOne(context.Context, int32) (*proto.Organization, *dto.ResponseErr)
+}
+
+// OwnershipService resolves the owners of the resources, a user owns itself and the upstream has no owner
+// of a team or an organization so their members own them. The membership only lets a member edit the team or the
+// organization itself, a team moved under another team or into another organization needs the ownership of that one too
+type OwnershipService struct {
+	teamSrv TeamFinder
+	orgSrv  OrganizationFinder
+}
+
+func NewOwnershipService(teamSrv TeamFinder, orgSrv OrganizationFinder) *OwnershipService {
+	return &OwnershipService{
+		teamSrv: teamSrv,
+		orgSrv:  orgSrv,
+	}
+}
+
+func (s *OwnershipService) IsOwner(ctx context.Context, resource string, id int32, userId int32) (bool, *dto.ResponseErr) {
+	switch resource {
+	case constant.ResourceUser:
+		return id == userId, nil
+	case constant.ResourceTeam:
+		team, errRes := s.teamSrv.FindOne(ctx, id)
+		if errRes != nil {
+			return false, errRes
+		}
+
+		return isMember(team.Members, userId), nil
+	case constant.ResourceOrganization:
+		org, errRes := s.orgSrv.FindOne(ctx, id)
+		if errRes != nil {
+			return false, errRes
+		}
+
+		return isMember(org.Members, userId), nil
+	default:
+		return false, &dto.ResponseErr{
+			StatusCode: http.StatusInternalServerError,
+			Message:    "Unknown resource",
+			Data:       resource,
+		}
+	}
+}
+
+func isMember(members []*proto.User, userId int32) bool {
+	for _, m := range members {
+		if int32(m.Id) == userId {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,203 @@
+package service
+
+import (
+	"context"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/constant"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/dto"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/proto"
+	"net/http"
+	"time"
+)
+
+type PermissionService struct {
+	client proto.PermissionServiceClient
+}
+
+func NewPermissionService(client proto.PermissionServiceClient) *PermissionService {
+	return &PermissionService{
+		client: client,
+	}
+}
+
+func (s *PermissionService) FindAll(ctx context.Context, query *dto.PaginationQueryParams) (result *proto.PermissionPagination, err *dto.ResponseErr) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	req := &proto.FindAllPermissionRequest{
+		Page:  query.Page,
+		Limit: query.Limit,
+	}
+
+	res, errRes := s.client.FindAll(ctx, req)
+	if errRes != nil {
+		logUpstreamErr(ctx, errRes)
+		return nil, &dto.ResponseErr{
+			StatusCode: http.StatusServiceUnavailable,
+			Message:    "Service is down",
+			Data:       nil,
+		}
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return nil, &dto.ResponseErr{
+			StatusCode: int(res.StatusCode),
+			Message:    FormatErr(res.Errors),
+			Data:       nil,
+		}
+	}
+
+	result = res.Data
+
+	return
+}
+
+func (s *PermissionService) FindOne(ctx context.Context, id int32) (result *proto.Permission, err *dto.ResponseErr) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	res, errRes := s.client.FindOne(ctx, &proto.FindOnePermissionRequest{Id: id})
+	if errRes != nil {
+		logUpstreamErr(ctx, errRes)
+		return nil, &dto.ResponseErr{
+			StatusCode: http.StatusServiceUnavailable,
+			Message:    "Service is down",
+			Data:       nil,
+		}
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return nil, &dto.ResponseErr{
+			StatusCode: int(res.StatusCode),
+			Message:    FormatErr(res.Errors),
+			Data:       nil,
+		}
+	}
+
+	result = res.Data
+
+	return
+}
+
+func (s *PermissionService) FindByCode(ctx context.Context, code string) (result *proto.Permission, err *dto.ResponseErr) {
+	query := &dto.PaginationQueryParams{
+		Limit: constant.FetchAllLimit,
+		Page:  1,
+	}
+
+	for {
+		permissions, errRes := s.FindAll(ctx, query)
+		if errRes != nil {
+			return nil, errRes
+		}
+
+		for _, perm := range permissions.Items {
+			if perm.Code == code {
+				return perm, nil
+			}
+		}
+
+		if permissions.Meta == nil || query.Page >= permissions.Meta.TotalPage {
+			break
+		}
+
+		query.Page++
+	}
+
+	return nil, &dto.ResponseErr{
+		StatusCode: http.StatusNotFound,
+		Message:    "Not found permission",
+		Data:       nil,
+	}
+}
+
+func (s *PermissionService) Create(ctx context.Context, permissionDto *dto.PermissionDto) (result *proto.Permission, err *dto.ResponseErr) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	permission := s.DtoToRaw(permissionDto)
+
+	res, errRes := s.client.Create(ctx, &proto.CreatePermissionRequest{Permission: permission})
+	if errRes != nil {
+		logUpstreamErr(ctx, errRes)
+		return nil, &dto.ResponseErr{
+			StatusCode: http.StatusServiceUnavailable,
+			Message:    "Service is down",
+			Data:       nil,
+		}
+	}
+
+	if res.StatusCode != http.StatusCreated {
+		return nil, &dto.ResponseErr{
+			StatusCode: int(res.StatusCode),
+			Message:    FormatErr(res.Errors),
+			Data:       nil,
+		}
+	}
+
+	result = res.Data
+	return
+}
+
+func (s *PermissionService) Update(ctx context.Context, id int32, permissionDto *dto.PermissionDto) (result *proto.Permission, err *dto.ResponseErr) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	permission := s.DtoToRaw(permissionDto)
+	permission.Id = uint32(id)
+
+	res, errRes := s.client.Update(ctx, &proto.UpdatePermissionRequest{Permission: permission})
+	if errRes != nil {
+		logUpstreamErr(ctx, errRes)
+		return nil, &dto.ResponseErr{
+			StatusCode: http.StatusServiceUnavailable,
+			Message:    "Service is down",
+			Data:       nil,
+		}
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return nil, &dto.ResponseErr{
+			StatusCode: int(res.StatusCode),
+			Message:    FormatErr(res.Errors),
+			Data:       nil,
+		}
+	}
+
+	result = res.Data
+
+	return
+}
+
+func (s *PermissionService) Delete(ctx context.Context, id int32) (result *proto.Permission, err *dto.ResponseErr) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	res, errRes := s.client.Delete(ctx, &proto.DeletePermissionRequest{Id: id})
+	if errRes != nil {
+		logUpstreamErr(ctx, errRes)
+		return nil, &dto.ResponseErr{
+			StatusCode: http.StatusServiceUnavailable,
+			Message:    "Service is down",
+			Data:       nil,
+		}
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return nil, &dto.ResponseErr{
+			StatusCode: int(res.StatusCode),
+			Message:    FormatErr(res.Errors),
+			Data:       nil,
+		}
+	}
+
+	result = res.Data
+
+	return
+}
+
+func (PermissionService) DtoToRaw(permissionDto *dto.PermissionDto) *proto.Permission {
+	return &proto.Permission{
+		Name: permissionDto.Name,
+		Code: permissionDto.Code,
+	}
+}
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"github.com/samithiwat/samithiwat-backend-gateway/src/dto"
+	"log"
+	"math"
+	"sync"
+	"time"
+)
+
+// RateLimitPolicy is a token bucket that holds Limit tokens and refills all of them over the Period, a request takes
+// one token so a caller may burst Limit requests and then make Limit requests per Period
+type RateLimitPolicy struct {
+	Limit  int
+	Period time.Duration
+}
+
+func (p RateLimitPolicy) rate() float64 {
+	return float64(p.Limit) / float64(p.Period)
+}
+
+// RateLimitStore takes a token from the bucket of the key atomically and returns the tokens left in it, the store may
+// drop a bucket that is full again
+type RateLimitStore interface {
+	Take(string, RateLimitPolicy, time.Time) (allowed bool, tokens float64, err error)
+}
+
+type RateLimitService struct {
+	store    RateLimitStore
+	policies map[string]RateLimitPolicy
+	now      func() time.Time
+}
+
+func NewRateLimitService(store RateLimitStore, policies map[string]RateLimitPolicy) *RateLimitService {
+	return &RateLimitService{
+		store:    store,
+		policies: policies,
+		now:      time.Now,
+	}
+}
+
+// Take takes a token of the caller from the bucket of the policy, it is nil when the policy is not configured or the
+// store fails so the requests are not rejected because of the limiter
+func (s *RateLimitService) Take(name string, key string) *dto.RateLimit {
+	policy, ok := s.policies[name]
+	if !ok || policy.Limit <= 0 || policy.Period <= 0 {
+		return nil
+	}
+
+	allowed, tokens, err := s.store.Take(name+":"+key, policy, s.now())
+	if err != nil {
+		log.Printf("cannot take the rate limit of %v %v: %v\n", name, key, err)
+		return nil
+	}
+
+	return bucketState(policy, allowed, tokens)
+}
+
+func bucketState(policy RateLimitPolicy, allowed bool, tokens float64) *dto.RateLimit {
+	state := &dto.RateLimit{
+		Allowed:   allowed,
+		Limit:     policy.Limit,
+		Remaining: int(math.Floor(tokens)),
+		Reset:     time.Duration((float64(policy.Limit) - tokens) / policy.rate()),
+	}
+
+	if !allowed {
+		state.RetryAfter = time.Duration((1 - tokens) / policy.rate())
+	}
+
+	return state
+}
+
+// refill adds the tokens of the time since the bucket was updated and takes one when there is one
+func refill(policy RateLimitPolicy, tokens float64, elapsed time.Duration) (bool, float64) {
+	if elapsed > 0 {
+		tokens = math.Min(float64(policy.Limit), tokens+float64(elapsed)*policy.rate())
+	}
+
+	if tokens < 1 {
+		return false, tokens
+	}
+
+	return true, tokens - 1
+}
+
+type memoryBucket struct {
+	tokens    float64
+	updatedAt time.Time
+	expiresAt time.Time
+}
+
+// MemoryRateLimitStore keeps the buckets of a single gateway instance, the full buckets are swept every minute
+type MemoryRateLimitStore struct {
+	mu      sync.Mutex
+	buckets map[string]*memoryBucket
+	sweeper sweeper
+}
+
+func NewMemoryRateLimitStore() *MemoryRateLimitStore {
+	return &MemoryRateLimitStore{
+		buckets: map[string]*memoryBucket{},
+	}
+}
+
+func (s *MemoryRateLimitStore) Take(key string, policy RateLimitPolicy, now time.Time) (bool, float64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sweeper.run(now, func() {
+		for key, bucket := range s.buckets {
+			if !now.Before(bucket.expiresAt) {
+				delete(s.buckets, key)
+			}
+		}
+	})
+
+	bucket, ok := s.buckets[key]
+	if !ok {
+		bucket = &memoryBucket{tokens: float64(policy.Limit), updatedAt: now}
+		s.buckets[key] = bucket
+	}
+
+	allowed, tokens := refill(policy, bucket.tokens, now.Sub(bucket.updatedAt))
+	bucket.tokens = tokens
+	bucket.updatedAt = now
+	bucket.expiresAt = now.Add(policy.Period)
+
+	return allowed, tokens, nil
+}
@@ -0,0 +1,327 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/go-redis/redis/v8"
+	"github.com/pkg/errors"
+	"strconv"
+	"time"
+)
+
+// rateLimitScript refills and takes a token of the bucket in the server so the gateway instances share the buckets,
+// the tokens are returned as a string because lua numbers are truncated to integers in the reply
+const rateLimitScript = `
+local limit = tonumber(ARGV[1])
+local period = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'updated')
+local tokens = tonumber(state[1]) or limit
+local updated = tonumber(state[2]) or now
+if now > updated then
+  tokens = math.min(limit, tokens + (now - updated) * limit / period)
+end
+local allowed = 0
+if tokens >= 1 then
+  tokens = tokens - 1
+  allowed = 1
+end
+redis.call('HMSET', KEYS[1], 'tokens', tostring(tokens), 'updated', now)
+redis.call('PEXPIRE', KEYS[1], period)
+return {allowed, tostring(tokens)}
+`
+
+var rateLimitRedisScript = redis.NewScript(rateLimitScript)
+
+// RedisRateLimitStore keeps the buckets in a Redis server that is shared by the gateway instances, the timeouts of the
+// requests are the timeouts of the client
+type RedisRateLimitStore struct {
+	client redis.Scripter
+	prefix string
+}
+
+func NewRedisRateLimitStore(client redis.Scripter, prefix string) *RedisRateLimitStore {
+	return &RedisRateLimitStore{
+		client: client,
+		prefix: prefix,
+	}
+}
+
+func (s *RedisRateLimitStore) Take(key string, policy RateLimitPolicy, now time.Time) (bool, float64, error) {
+	// the script is sent again when the server does not have it cached
+	reply, err := rateLimitRedisScript.Run(
+		context.Background(),
+		s.client,
+		[]string{s.prefix + key},
+		policy.Limit,
+		policy.Period.Milliseconds(),
+		now.UnixNano()/int64(time.Millisecond),
+	).Slice()
+	if err != nil {
+		return false, 0, err
+	}
+
+	if len(reply) != 2 {
+		return false, 0, errors.Errorf("unexpected rate limit reply %v", reply)
+	}
+
+	allowed, _ := reply[0].(int64)
+	raw, _ := reply[1].(string)
+	tokens, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return false, 0, errors.Wrap(err, "invalid rate limit tokens")
+	}
+
+	return allowed == 1, tokens, nil
+}
+
+// RedisVerificationStore keeps the pending verifications in a Redis server so they survive a restart and are shared by
+// the gateway instances
+type RedisVerificationStore struct {
+	client redis.Cmdable
+	prefix string
+}
+
+func NewRedisVerificationStore(client redis.Cmdable, prefix string) *RedisVerificationStore {
+	return &RedisVerificationStore{
+		client: client,
+		prefix: prefix,
+	}
+}
+
+func (s *RedisVerificationStore) Get(userId uint32) (string, bool, error) {
+	email, err := s.client.Get(context.Background(), redisUserKey(s.prefix, "verification", userId)).Result()
+	if err == redis.Nil {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+
+	return email, true, nil
+}
+
+func (s *RedisVerificationStore) Set(userId uint32, email string) error {
+	return s.client.Set(context.Background(), redisUserKey(s.prefix, "verification", userId), email, 0).Err()
+}
+
+func (s *RedisVerificationStore) Delete(userId uint32) error {
+	return s.client.Del(context.Background(), redisUserKey(s.prefix, "verification", userId)).Err()
+}
+
+// RedisTotpStore keeps the enrollments as json in a Redis server, the secrets are already encrypted by the service
+type RedisTotpStore struct {
+	client redis.Cmdable
+	prefix string
+}
+
+func NewRedisTotpStore(client redis.Cmdable, prefix string) *RedisTotpStore {
+	return &RedisTotpStore{
+		client: client,
+		prefix: prefix,
+	}
+}
+
+func (s *RedisTotpStore) Get(userId uint32) (*TotpEnrollment, error) {
+	raw, err := s.client.Get(context.Background(), redisUserKey(s.prefix, "totp", userId)).Bytes()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	enrollment := &TotpEnrollment{}
+	if err := json.Unmarshal(raw, enrollment); err != nil {
+		return nil, errors.Wrap(err, "invalid totp enrollment")
+	}
+
+	return enrollment, nil
+}
+
+func (s *RedisTotpStore) Set(userId uint32, enrollment *TotpEnrollment) error {
+	raw, err := json.Marshal(enrollment)
+	if err != nil {
+		return err
+	}
+
+	return s.client.Set(context.Background(), redisUserKey(s.prefix, "totp", userId), raw, 0).Err()
+}
+
+func (s *RedisTotpStore) Delete(userId uint32) error {
+	return s.client.Del(context.Background(), redisUserKey(s.prefix, "totp", userId)).Err()
+}
+
+// RedisSessionStore keeps the sessions as json in a Redis server with keys from the token hashes and the users to the
+// sessions, the keys expire after the retention that the service drops the sessions at, a zero retention keeps them
+type RedisSessionStore struct {
+	client    redis.Cmdable
+	prefix    string
+	retention time.Duration
+}
+
+func NewRedisSessionStore(client redis.Cmdable, prefix string, retention time.Duration) *RedisSessionStore {
+	return &RedisSessionStore{
+		client:    client,
+		prefix:    prefix,
+		retention: retention,
+	}
+}
+
+func (s *RedisSessionStore) Get(id string) (*UserSession, error) {
+	raw, err := s.client.Get(context.Background(), s.key(id)).Bytes()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	session := &UserSession{}
+	if err := json.Unmarshal(raw, session); err != nil {
+		return nil, errors.Wrap(err, "invalid session")
+	}
+
+	return session, nil
+}
+
+func (s *RedisSessionStore) FindByAccessToken(hash string) (*UserSession, error) {
+	return s.find(s.key("access:" + hash))
+}
+
+func (s *RedisSessionStore) FindByRefreshToken(hash string) (*UserSession, error) {
+	return s.find(s.key("refresh:" + hash))
+}
+
+func (s *RedisSessionStore) FindByUser(userId uint32) ([]*UserSession, error) {
+	key := redisUserKey(s.prefix, "session:user", userId)
+	ids, err := s.client.SMembers(context.Background(), key).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	var result []*UserSession
+	for _, id := range ids {
+		session, err := s.Get(id)
+		if err != nil {
+			return nil, err
+		}
+
+		// the session expired before the set of the user
+		if session == nil {
+			if err := s.client.SRem(context.Background(), key, id).Err(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		result = append(result, session)
+	}
+
+	return result, nil
+}
+
+func (s *RedisSessionStore) Save(session *UserSession) error {
+	old, err := s.Get(session.ID)
+	if err != nil {
+		return err
+	}
+
+	raw, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+
+	userKey := redisUserKey(s.prefix, "session:user", session.UserID)
+	_, err = s.client.TxPipelined(context.Background(), func(pipe redis.Pipeliner) error {
+		if old != nil {
+			s.unindex(pipe, old)
+		}
+
+		pipe.Set(context.Background(), s.key(session.ID), raw, s.retention)
+		pipe.Set(context.Background(), s.key("access:"+session.AccessTokenHash), session.ID, s.retention)
+		for _, token := range session.SupersededTokens {
+			pipe.Set(context.Background(), s.key("access:"+token.Hash), session.ID, s.retention)
+		}
+		pipe.Set(context.Background(), s.key("refresh:"+session.RefreshTokenHash), session.ID, s.retention)
+		pipe.SAdd(context.Background(), userKey, session.ID)
+		if s.retention > 0 {
+			pipe.Expire(context.Background(), userKey, s.retention)
+		}
+
+		return nil
+	})
+
+	return err
+}
+
+func (s *RedisSessionStore) Delete(id string) error {
+	old, err := s.Get(id)
+	if err != nil || old == nil {
+		return err
+	}
+
+	_, err = s.client.TxPipelined(context.Background(), func(pipe redis.Pipeliner) error {
+		s.unindex(pipe, old)
+		pipe.Del(context.Background(), s.key(id))
+		pipe.SRem(context.Background(), redisUserKey(s.prefix, "session:user", old.UserID), id)
+
+		return nil
+	})
+
+	return err
+}
+
+func (s *RedisSessionStore) find(indexKey string) (*UserSession, error) {
+	id, err := s.client.Get(context.Background(), indexKey).Result()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Get(id)
+}
+
+func (s *RedisSessionStore) unindex(pipe redis.Pipeliner, old *UserSession) {
+	pipe.Del(context.Background(), s.key("access:"+old.AccessTokenHash))
+	for _, token := range old.SupersededTokens {
+		pipe.Del(context.Background(), s.key("access:"+token.Hash))
+	}
+	pipe.Del(context.Background(), s.key("refresh:"+old.RefreshTokenHash))
+}
+
+func (s *RedisSessionStore) key(id string) string {
+	return s.prefix + "session:" + id
+}
+
+// RedisUserEmailStore keeps the emails of the users in a Redis server
+type RedisUserEmailStore struct {
+	client redis.Cmdable
+	prefix string
+}
+
+func NewRedisUserEmailStore(client redis.Cmdable, prefix string) *RedisUserEmailStore {
+	return &RedisUserEmailStore{
+		client: client,
+		prefix: prefix,
+	}
+}
+
+func (s *RedisUserEmailStore) Get(userId uint32) (string, error) {
+	email, err := s.client.Get(context.Background(), redisUserKey(s.prefix, "email", userId)).Result()
+	if err == redis.Nil {
+		return "", nil
+	}
+
+	return email, err
+}
+
+func (s *RedisUserEmailStore) Set(userId uint32, email string) error {
+	return s.client.Set(context.Background(), redisUserKey(s.prefix, "email", userId), email, 0).Err()
+}
+
+func redisUserKey(prefix string, kind string, userId uint32) string {
+	return prefix + kind + ":" + strconv.FormatUint(uint64(userId), 10)
+}
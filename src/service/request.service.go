@@ -0,0 +1,29 @@
+package service
+
+import (
+	"context"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/common"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/constant"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"log"
+)
+
+// RequestIDInterceptor sends the request id of the context to the upstream services in the metadata of every call
+func RequestIDInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	if id := common.RequestID(ctx); id != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, constant.RequestIDMetadata, id)
+	}
+
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
+
+// logUpstreamErr logs the failed call with the request id so the line can be matched with the logs of the upstream service
+func logUpstreamErr(ctx context.Context, err error) {
+	logf(ctx, "%v\n", err)
+}
+
+// logf writes the log line of a request prefixed with its request id
+func logf(ctx context.Context, format string, v ...interface{}) {
+	log.Printf("[%v] "+format, append([]interface{}{common.RequestID(ctx)}, v...)...)
+}
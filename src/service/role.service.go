@@ -0,0 +1,442 @@
+package service
+
+import (
+	"context"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/common"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/constant"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/dto"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/proto"
+	"net/http"
+	"sync"
+	"time"
+)
+
+type RoleService struct {
+	client proto.RoleServiceClient
+	cache  *userRoleCache
+}
+
+func NewRoleService(client proto.RoleServiceClient) *RoleService {
+	return &RoleService{
+		client: client,
+	}
+}
+
+// NewCachedRoleService keeps the roles of a user for the ttl so the permission guard does not page through all the
+// roles on every request, a zero ttl disables the cache
+func NewCachedRoleService(client proto.RoleServiceClient, ttl time.Duration) *RoleService {
+	s := NewRoleService(client)
+	if ttl > 0 {
+		s.cache = newUserRoleCache(ttl)
+	}
+
+	return s
+}
+
+func (s *RoleService) FindAll(ctx context.Context, query *dto.PaginationQueryParams) (result *proto.RolePagination, err *dto.ResponseErr) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	req := &proto.FindAllRoleRequest{
+		Page:  query.Page,
+		Limit: query.Limit,
+	}
+
+	res, errRes := s.client.FindAll(ctx, req)
+	if errRes != nil {
+		logUpstreamErr(ctx, errRes)
+		return nil, &dto.ResponseErr{
+			StatusCode: http.StatusServiceUnavailable,
+			Message:    "Service is down",
+			Data:       nil,
+		}
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return nil, &dto.ResponseErr{
+			StatusCode: int(res.StatusCode),
+			Message:    FormatErr(res.Errors),
+			Data:       nil,
+		}
+	}
+
+	result = res.Data
+
+	return
+}
+
+func (s *RoleService) FindOne(ctx context.Context, id int32) (result *proto.Role, err *dto.ResponseErr) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	res, errRes := s.client.FindOne(ctx, &proto.FindOneRoleRequest{Id: id})
+	if errRes != nil {
+		logUpstreamErr(ctx, errRes)
+		return nil, &dto.ResponseErr{
+			StatusCode: http.StatusServiceUnavailable,
+			Message:    "Service is down",
+			Data:       nil,
+		}
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return nil, &dto.ResponseErr{
+			StatusCode: int(res.StatusCode),
+			Message:    FormatErr(res.Errors),
+			Data:       nil,
+		}
+	}
+
+	result = res.Data
+
+	return
+}
+
+func (s *RoleService) FindMulti(ctx context.Context, ids []int32) (result []*proto.Role, err *dto.ResponseErr) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	res, errRes := s.client.FindMulti(ctx, &proto.FindMultiRoleRequest{Ids: ToUintIDs(ids)})
+	if errRes != nil {
+		logUpstreamErr(ctx, errRes)
+		return nil, &dto.ResponseErr{
+			StatusCode: http.StatusServiceUnavailable,
+			Message:    "Service is down",
+			Data:       nil,
+		}
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return nil, &dto.ResponseErr{
+			StatusCode: int(res.StatusCode),
+			Message:    FormatErr(res.Errors),
+			Data:       nil,
+		}
+	}
+
+	result = []*proto.Role{}
+	for _, i := range common.OrderByIDs(ids, len(res.Data), func(i int) uint32 { return res.Data[i].Id }) {
+		result = append(result, res.Data[i])
+	}
+
+	return
+}
+
+// FindByUser returns the roles that have the user, it is cached when the service has a cache
+func (s *RoleService) FindByUser(ctx context.Context, userId int32) (result []*proto.Role, err *dto.ResponseErr) {
+	roles, generation, ok := s.cache.get(userId)
+	if ok {
+		return roles, nil
+	}
+
+	result, err = s.findByUser(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	s.cache.set(userId, result, generation)
+
+	return result, nil
+}
+
+func (s *RoleService) findByUser(ctx context.Context, userId int32) (result []*proto.Role, err *dto.ResponseErr) {
+	query := &dto.PaginationQueryParams{
+		Limit: constant.FetchAllLimit,
+		Page:  1,
+	}
+
+	result = []*proto.Role{}
+
+	for {
+		roles, errRes := s.FindAll(ctx, query)
+		if errRes != nil {
+			return nil, errRes
+		}
+
+		for _, role := range roles.Items {
+			for _, user := range role.Users {
+				if user.Id == uint32(userId) {
+					result = append(result, role)
+					break
+				}
+			}
+		}
+
+		if roles.Meta == nil || query.Page >= roles.Meta.TotalPage {
+			break
+		}
+
+		query.Page++
+	}
+
+	return
+}
+
+func (s *RoleService) Create(ctx context.Context, roleDto *dto.RoleDto) (result *proto.Role, err *dto.ResponseErr) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	role := s.DtoToRaw(roleDto)
+
+	res, errRes := s.client.Create(ctx, &proto.CreateRoleRequest{Role: role})
+	if errRes != nil {
+		logUpstreamErr(ctx, errRes)
+		return nil, &dto.ResponseErr{
+			StatusCode: http.StatusServiceUnavailable,
+			Message:    "Service is down",
+			Data:       nil,
+		}
+	}
+
+	if res.StatusCode != http.StatusCreated {
+		return nil, &dto.ResponseErr{
+			StatusCode: int(res.StatusCode),
+			Message:    FormatErr(res.Errors),
+			Data:       nil,
+		}
+	}
+
+	result = res.Data
+	return
+}
+
+func (s *RoleService) Update(ctx context.Context, id int32, roleDto *dto.RoleDto) (result *proto.Role, err *dto.ResponseErr) {
+	role := s.DtoToRaw(roleDto)
+	role.Id = uint32(id)
+
+	return s.update(ctx, role)
+}
+
+func (s *RoleService) AddPermission(ctx context.Context, id int32, permission *proto.Permission) (result *proto.Role, err *dto.ResponseErr) {
+	role, err := s.FindOne(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, perm := range role.Permissions {
+		if perm.Id == permission.Id {
+			return role, nil
+		}
+	}
+
+	role.Permissions = append(role.Permissions, permission)
+
+	return s.update(ctx, role)
+}
+
+func (s *RoleService) RemovePermission(ctx context.Context, id int32, permissionId int32) (result *proto.Role, err *dto.ResponseErr) {
+	role, err := s.FindOne(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var permissions []*proto.Permission
+	for _, perm := range role.Permissions {
+		if int32(perm.Id) != permissionId {
+			permissions = append(permissions, perm)
+		}
+	}
+
+	if len(permissions) == len(role.Permissions) {
+		return nil, &dto.ResponseErr{
+			StatusCode: http.StatusNotFound,
+			Message:    "The role does not have this permission",
+			Data:       nil,
+		}
+	}
+
+	role.Permissions = permissions
+
+	return s.update(ctx, role)
+}
+
+// AssignUser gives the user the role and takes the other roles in the scope away from the user,
+// so the user holds only one role among them (e.g. the roles of an organization)
+func (s *RoleService) AssignUser(ctx context.Context, scope []int32, id int32, user *proto.User) (result *proto.Role, err *dto.ResponseErr) {
+	roles, err := s.FindMulti(ctx, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, role := range roles {
+		if int32(role.Id) == id {
+			result = role
+			continue
+		}
+
+		var users []*proto.User
+		for _, u := range role.Users {
+			if u.Id != user.Id {
+				users = append(users, u)
+			}
+		}
+
+		if len(users) != len(role.Users) {
+			role.Users = users
+			if _, err = s.update(ctx, role); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if result == nil {
+		result, err = s.FindOne(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, u := range result.Users {
+		if u.Id == user.Id {
+			return result, nil
+		}
+	}
+
+	result.Users = append(result.Users, user)
+
+	return s.update(ctx, result)
+}
+
+func (s *RoleService) Delete(ctx context.Context, id int32) (result *proto.Role, err *dto.ResponseErr) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	defer s.cache.evictAll()
+
+	res, errRes := s.client.Delete(ctx, &proto.DeleteRoleRequest{Id: id})
+	if errRes != nil {
+		logUpstreamErr(ctx, errRes)
+		return nil, &dto.ResponseErr{
+			StatusCode: http.StatusServiceUnavailable,
+			Message:    "Service is down",
+			Data:       nil,
+		}
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return nil, &dto.ResponseErr{
+			StatusCode: int(res.StatusCode),
+			Message:    FormatErr(res.Errors),
+			Data:       nil,
+		}
+	}
+
+	result = res.Data
+
+	return
+}
+
+func (RoleService) DtoToRaw(roleDto *dto.RoleDto) *proto.Role {
+	return &proto.Role{
+		Name:        roleDto.Name,
+		Description: roleDto.Description,
+	}
+}
+
+// update evicts the cached roles of every user even when it fails, the upstream may have applied a timed out update
+func (s *RoleService) update(ctx context.Context, role *proto.Role) (result *proto.Role, err *dto.ResponseErr) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	defer s.cache.evictAll()
+
+	res, errRes := s.client.Update(ctx, &proto.UpdateRoleRequest{Role: role})
+	if errRes != nil {
+		logUpstreamErr(ctx, errRes)
+		return nil, &dto.ResponseErr{
+			StatusCode: http.StatusServiceUnavailable,
+			Message:    "Service is down",
+			Data:       nil,
+		}
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return nil, &dto.ResponseErr{
+			StatusCode: int(res.StatusCode),
+			Message:    FormatErr(res.Errors),
+			Data:       nil,
+		}
+	}
+
+	result = res.Data
+
+	return
+}
+
+// userRoleCache keeps the roles of the users for the ttl, a change of any role evicts every user because the
+// permissions and the users of the role may both have changed, a nil cache keeps nothing
+type userRoleCache struct {
+	ttl        time.Duration
+	now        func() time.Time
+	mu         sync.Mutex
+	entries    map[int32]*userRoles
+	generation uint64
+	sweeper    sweeper
+}
+
+type userRoles struct {
+	roles     []*proto.Role
+	expiresAt time.Time
+}
+
+func newUserRoleCache(ttl time.Duration) *userRoleCache {
+	return &userRoleCache{
+		ttl:     ttl,
+		now:     time.Now,
+		entries: map[int32]*userRoles{},
+	}
+}
+
+// get returns the generation of the cache with the roles, the roles that are fetched on a miss are only kept when no
+// role was changed since
+func (c *userRoleCache) get(userId int32) ([]*proto.Role, uint64, bool) {
+	if c == nil {
+		return nil, 0, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[userId]
+	if !ok || !c.now().Before(entry.expiresAt) {
+		return nil, c.generation, false
+	}
+
+	return entry.roles, c.generation, true
+}
+
+func (c *userRoleCache) set(userId int32, roles []*proto.Role, generation uint64) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if generation != c.generation {
+		return
+	}
+
+	now := c.now()
+	c.sweeper.run(now, func() {
+		for id, entry := range c.entries {
+			if !now.Before(entry.expiresAt) {
+				delete(c.entries, id)
+			}
+		}
+	})
+
+	c.entries[userId] = &userRoles{roles: roles, expiresAt: now.Add(c.ttl)}
+}
+
+func (c *userRoleCache) evictAll() {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+	c.entries = map[int32]*userRoles{}
+}
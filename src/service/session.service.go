@@ -0,0 +1,398 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/dto"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/proto"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// sessionTouchInterval is how often the last seen time of a session is written, the requests in between do not write the store
+const sessionTouchInterval = time.Minute
+
+// UserSession is the session of an issued credential, the tokens are kept as sha256 hashes and a revoked session is kept
+// until it is dropped so its tokens stay rejected
+type UserSession struct {
+	ID                   string
+	UserID               uint32
+	UserAgent            string
+	IP                   string
+	AccessTokenHash      string
+	AccessTokenExpiresAt time.Time
+	RefreshTokenHash     string
+	// SupersededTokens are the access tokens that were rotated before they expired, they stay valid upstream so they
+	// still belong to the session
+	SupersededTokens []SupersededToken
+	CreatedAt        time.Time
+	LastSeenAt       time.Time
+	Revoked          bool
+}
+
+// SupersededToken is the hash of a rotated access token with its expiry
+type SupersededToken struct {
+	Hash      string
+	ExpiresAt time.Time
+}
+
+// SessionStore keeps the sessions, FindByAccessToken and FindByRefreshToken take the hash of the token and return nil
+// when no session has it, FindByAccessToken also finds the session of a superseded access token
+type SessionStore interface {
+	Get(string) (*UserSession, error)
+	FindByAccessToken(string) (*UserSession, error)
+	FindByRefreshToken(string) (*UserSession, error)
+	FindByUser(uint32) ([]*UserSession, error)
+	Save(*UserSession) error
+	Delete(string) error
+}
+
+type SessionService struct {
+	store     SessionStore
+	retention time.Duration
+	now       func() time.Time
+}
+
+func NewSessionService(store SessionStore, retention time.Duration) *SessionService {
+	return &SessionService{
+		store:     store,
+		retention: retention,
+		now:       time.Now,
+	}
+}
+
+// Record starts the session of the credential, the failures are only logged because the credential is already issued
+// and the tokens of a session that was not saved are rejected
+func (s *SessionService) Record(ctx context.Context, userId uint32, credential *proto.Credential, device *dto.Device) {
+	id, err := randomToken()
+	if err != nil {
+		logf(ctx, "cannot create the session of user %v: %v\n", userId, err)
+		return
+	}
+
+	s.drop(ctx, userId)
+
+	now := s.now()
+	if err := s.store.Save(&UserSession{
+		ID:                   id,
+		UserID:               userId,
+		UserAgent:            device.UserAgent,
+		IP:                   device.IP,
+		AccessTokenHash:      hashToken(credential.AccessToken),
+		AccessTokenExpiresAt: s.accessExpiry(now, credential),
+		RefreshTokenHash:     hashToken(credential.RefreshToken),
+		CreatedAt:            now,
+		LastSeenAt:           now,
+	}); err != nil {
+		logf(ctx, "cannot save the session of user %v: %v\n", userId, err)
+	}
+}
+
+// CheckRefresh rejects the refresh token of a revoked or unknown session before it is redeemed, a token without a session
+// cannot be revoked so it is not accepted
+func (s *SessionService) CheckRefresh(ctx context.Context, refreshToken string) *dto.ResponseErr {
+	session, err := s.store.FindByRefreshToken(hashToken(refreshToken))
+	if err != nil {
+		logf(ctx, "cannot find the session of the refresh token: %v\n", err)
+		return responseErr(http.StatusServiceUnavailable, "Service is down")
+	}
+
+	if session == nil {
+		return responseErr(http.StatusUnauthorized, "Session not found")
+	}
+
+	if session.Revoked {
+		return responseErr(http.StatusUnauthorized, "Session is revoked")
+	}
+
+	return nil
+}
+
+// Rotate moves the session of the redeemed refresh token to the new credential, the token was checked by CheckRefresh
+func (s *SessionService) Rotate(ctx context.Context, refreshToken string, credential *proto.Credential, device *dto.Device) {
+	session, err := s.store.FindByRefreshToken(hashToken(refreshToken))
+	if err != nil {
+		logf(ctx, "cannot find the session of the refresh token: %v\n", err)
+		return
+	}
+
+	if session == nil {
+		logf(ctx, "the session of the refresh token is gone\n")
+		return
+	}
+
+	// the previous access token is valid upstream until it expires, so it must still be rejected when the session is revoked
+	now := s.now()
+	var superseded []SupersededToken
+	for _, token := range session.SupersededTokens {
+		if now.Before(token.ExpiresAt) {
+			superseded = append(superseded, token)
+		}
+	}
+	if now.Before(session.AccessTokenExpiresAt) {
+		superseded = append(superseded, SupersededToken{Hash: session.AccessTokenHash, ExpiresAt: session.AccessTokenExpiresAt})
+	}
+
+	session.AccessTokenHash = hashToken(credential.AccessToken)
+	session.AccessTokenExpiresAt = s.accessExpiry(now, credential)
+	session.SupersededTokens = superseded
+	session.RefreshTokenHash = hashToken(credential.RefreshToken)
+	session.UserAgent = device.UserAgent
+	session.IP = device.IP
+	session.LastSeenAt = now
+	if err := s.store.Save(session); err != nil {
+		logf(ctx, "cannot save the session %v: %v\n", session.ID, err)
+	}
+}
+
+// Authenticate returns the id of the session of the access token, a token without a session cannot be revoked so it is
+// rejected
+func (s *SessionService) Authenticate(ctx context.Context, accessToken string) (string, *dto.ResponseErr) {
+	session, err := s.store.FindByAccessToken(hashToken(accessToken))
+	if err != nil {
+		logf(ctx, "cannot find the session of the access token: %v\n", err)
+		return "", responseErr(http.StatusServiceUnavailable, "Service is down")
+	}
+
+	if session == nil {
+		return "", responseErr(http.StatusUnauthorized, "Session not found")
+	}
+
+	if session.Revoked {
+		return "", responseErr(http.StatusUnauthorized, "Session is revoked")
+	}
+
+	now := s.now()
+	if now.Sub(session.LastSeenAt) >= sessionTouchInterval {
+		session.LastSeenAt = now
+		if err := s.store.Save(session); err != nil {
+			logf(ctx, "cannot save the session %v: %v\n", session.ID, err)
+		}
+	}
+
+	return session.ID, nil
+}
+
+// List returns the active sessions of the user from the last seen, the current session is the session of the request
+func (s *SessionService) List(ctx context.Context, userId uint32, current string) ([]*dto.DeviceSession, *dto.ResponseErr) {
+	sessions, err := s.store.FindByUser(userId)
+	if err != nil {
+		logf(ctx, "cannot find the sessions of user %v: %v\n", userId, err)
+		return nil, responseErr(http.StatusServiceUnavailable, "Service is down")
+	}
+
+	result := make([]*dto.DeviceSession, 0, len(sessions))
+	for _, session := range sessions {
+		if session.Revoked || s.stale(session) {
+			continue
+		}
+
+		result = append(result, &dto.DeviceSession{
+			ID:         session.ID,
+			UserAgent:  session.UserAgent,
+			IP:         session.IP,
+			CreatedAt:  session.CreatedAt,
+			LastSeenAt: session.LastSeenAt,
+			Current:    session.ID == current,
+		})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].LastSeenAt.After(result[j].LastSeenAt)
+	})
+
+	return result, nil
+}
+
+// Revoke rejects the tokens of the session, the session of another user is not found
+func (s *SessionService) Revoke(ctx context.Context, userId uint32, id string) *dto.ResponseErr {
+	session, err := s.store.Get(id)
+	if err != nil {
+		logf(ctx, "cannot get the session %v: %v\n", id, err)
+		return responseErr(http.StatusServiceUnavailable, "Service is down")
+	}
+
+	if session == nil || session.UserID != userId || session.Revoked {
+		return responseErr(http.StatusNotFound, "Session not found")
+	}
+
+	return s.revoke(ctx, session)
+}
+
+func (s *SessionService) RevokeAll(ctx context.Context, userId uint32) *dto.ResponseErr {
+	sessions, err := s.store.FindByUser(userId)
+	if err != nil {
+		logf(ctx, "cannot find the sessions of user %v: %v\n", userId, err)
+		return responseErr(http.StatusServiceUnavailable, "Service is down")
+	}
+
+	for _, session := range sessions {
+		if session.Revoked {
+			continue
+		}
+
+		if errRes := s.revoke(ctx, session); errRes != nil {
+			return errRes
+		}
+	}
+
+	return nil
+}
+
+func (s *SessionService) revoke(ctx context.Context, session *UserSession) *dto.ResponseErr {
+	session.Revoked = true
+	if err := s.store.Save(session); err != nil {
+		logf(ctx, "cannot revoke the session %v: %v\n", session.ID, err)
+		return responseErr(http.StatusServiceUnavailable, "Service is down")
+	}
+
+	return nil
+}
+
+// drop deletes the sessions of the user that were not seen for the retention, their tokens have expired by then
+func (s *SessionService) drop(ctx context.Context, userId uint32) {
+	sessions, err := s.store.FindByUser(userId)
+	if err != nil {
+		logf(ctx, "cannot find the sessions of user %v: %v\n", userId, err)
+		return
+	}
+
+	for _, session := range sessions {
+		if !s.stale(session) {
+			continue
+		}
+
+		if err := s.store.Delete(session.ID); err != nil {
+			logf(ctx, "cannot delete the session %v: %v\n", session.ID, err)
+		}
+	}
+}
+
+// accessExpiry is when the access token of the credential expires, a credential without an expiry is kept for the retention
+func (s *SessionService) accessExpiry(now time.Time, credential *proto.Credential) time.Time {
+	if credential.ExpiresIn > 0 {
+		return now.Add(time.Duration(credential.ExpiresIn) * time.Second)
+	}
+
+	return now.Add(s.retention)
+}
+
+func (s *SessionService) stale(session *UserSession) bool {
+	return s.retention > 0 && s.now().Sub(session.LastSeenAt) > s.retention
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// MemorySessionStore keeps copies of the sessions with the indexes of their tokens and users
+type MemorySessionStore struct {
+	mu       sync.RWMutex
+	sessions map[string]UserSession
+	access   map[string]string
+	refresh  map[string]string
+	users    map[uint32]map[string]struct{}
+}
+
+func NewMemorySessionStore() *MemorySessionStore {
+	return &MemorySessionStore{
+		sessions: map[string]UserSession{},
+		access:   map[string]string{},
+		refresh:  map[string]string{},
+		users:    map[uint32]map[string]struct{}{},
+	}
+}
+
+func (s *MemorySessionStore) Get(id string) (*UserSession, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.get(id), nil
+}
+
+func (s *MemorySessionStore) FindByAccessToken(hash string) (*UserSession, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.get(s.access[hash]), nil
+}
+
+func (s *MemorySessionStore) FindByRefreshToken(hash string) (*UserSession, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.get(s.refresh[hash]), nil
+}
+
+func (s *MemorySessionStore) FindByUser(userId uint32) ([]*UserSession, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var result []*UserSession
+	for id := range s.users[userId] {
+		result = append(result, s.get(id))
+	}
+
+	return result, nil
+}
+
+func (s *MemorySessionStore) Save(session *UserSession) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.unindex(session.ID)
+
+	s.sessions[session.ID] = *session
+	s.access[session.AccessTokenHash] = session.ID
+	for _, token := range session.SupersededTokens {
+		s.access[token.Hash] = session.ID
+	}
+	s.refresh[session.RefreshTokenHash] = session.ID
+	if s.users[session.UserID] == nil {
+		s.users[session.UserID] = map[string]struct{}{}
+	}
+	s.users[session.UserID][session.ID] = struct{}{}
+
+	return nil
+}
+
+func (s *MemorySessionStore) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.unindex(id)
+	delete(s.sessions, id)
+
+	return nil
+}
+
+func (s *MemorySessionStore) get(id string) *UserSession {
+	session, ok := s.sessions[id]
+	if !ok {
+		return nil
+	}
+
+	return &session
+}
+
+func (s *MemorySessionStore) unindex(id string) {
+	old, ok := s.sessions[id]
+	if !ok {
+		return
+	}
+
+	delete(s.access, old.AccessTokenHash)
+	for _, token := range old.SupersededTokens {
+		delete(s.access, token.Hash)
+	}
+	delete(s.refresh, old.RefreshTokenHash)
+	delete(s.users[old.UserID], id)
+	if len(s.users[old.UserID]) == 0 {
+		delete(s.users, old.UserID)
+	}
+}
@@ -0,0 +1,22 @@
+package service
+
+import "time"
+
+// sweepInterval is how often the in-memory state drops its expired entries, the writes in between do not scan it
+const sweepInterval = time.Minute
+
+// sweeper amortises the scan of the expired entries of an in-memory map so a flood of writes does not scan the map
+// on every write, the caller holds the lock of the map and the readers still check the expiry of what they read
+type sweeper struct {
+	last time.Time
+}
+
+// run calls sweep when the interval has passed since the last sweep
+func (s *sweeper) run(now time.Time, sweep func()) {
+	if now.Sub(s.last) < sweepInterval {
+		return
+	}
+
+	s.last = now
+	sweep()
+}
@@ -2,9 +2,10 @@ package service
 
 import (
 	"context"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/common"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/constant"
 	"github.com/samithiwat/samithiwat-backend-gateway/src/dto"
 	"github.com/samithiwat/samithiwat-backend-gateway/src/proto"
-	"log"
 	"net/http"
 	"time"
 )
@@ -19,8 +20,8 @@ func NewTeamService(client proto.TeamServiceClient) *TeamService {
 	}
 }
 
-func (s *TeamService) FindAll(query *dto.PaginationQueryParams) (result *proto.TeamPagination, err *dto.ResponseErr) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+func (s *TeamService) FindAll(ctx context.Context, query *dto.PaginationQueryParams) (result *proto.TeamPagination, err *dto.ResponseErr) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
 	req := &proto.FindAllTeamRequest{
@@ -30,7 +31,7 @@ func (s *TeamService) FindAll(query *dto.PaginationQueryParams) (result *proto.T
 
 	res, errRes := s.client.FindAll(ctx, req)
 	if errRes != nil {
-		log.Printf("%v\n", errRes)
+		logUpstreamErr(ctx, errRes)
 		return nil, &dto.ResponseErr{
 			StatusCode: http.StatusServiceUnavailable,
 			Message:    "Service is down",
@@ -51,13 +52,13 @@ func (s *TeamService) FindAll(query *dto.PaginationQueryParams) (result *proto.T
 	return
 }
 
-func (s *TeamService) FindOne(id int32) (result *proto.Team, err *dto.ResponseErr) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+func (s *TeamService) FindOne(ctx context.Context, id int32) (result *proto.Team, err *dto.ResponseErr) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
 	res, errRes := s.client.FindOne(ctx, &proto.FindOneTeamRequest{Id: id})
 	if errRes != nil {
-		log.Printf("%v\n", errRes)
+		logUpstreamErr(ctx, errRes)
 		return nil, &dto.ResponseErr{
 			StatusCode: http.StatusServiceUnavailable,
 			Message:    "Service is down",
@@ -78,15 +79,13 @@ func (s *TeamService) FindOne(id int32) (result *proto.Team, err *dto.ResponseEr
 	return
 }
 
-func (s *TeamService) Create(teamDto *dto.TeamDto) (result *proto.Team, err *dto.ResponseErr) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+func (s *TeamService) FindMulti(ctx context.Context, ids []int32) (result []*proto.Team, err *dto.ResponseErr) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
-	team := s.DtoToRaw(teamDto)
-
-	res, errRes := s.client.Create(ctx, &proto.CreateTeamRequest{Team: team})
+	res, errRes := s.client.FindMulti(ctx, &proto.FindMultiTeamRequest{Ids: ToUintIDs(ids)})
 	if errRes != nil {
-		log.Printf("%v\n", errRes)
+		logUpstreamErr(ctx, errRes)
 		return nil, &dto.ResponseErr{
 			StatusCode: http.StatusServiceUnavailable,
 			Message:    "Service is down",
@@ -94,7 +93,7 @@ func (s *TeamService) Create(teamDto *dto.TeamDto) (result *proto.Team, err *dto
 		}
 	}
 
-	if res.StatusCode != http.StatusCreated {
+	if res.StatusCode != http.StatusOK {
 		return nil, &dto.ResponseErr{
 			StatusCode: int(res.StatusCode),
 			Message:    FormatErr(res.Errors),
@@ -102,20 +101,187 @@ func (s *TeamService) Create(teamDto *dto.TeamDto) (result *proto.Team, err *dto
 		}
 	}
 
-	result = res.Data
+	result = []*proto.Team{}
+	for _, i := range common.OrderByIDs(ids, len(res.Data), func(i int) uint32 { return res.Data[i].Id }) {
+		result = append(result, res.Data[i])
+	}
+
 	return
 }
 
-func (s *TeamService) Update(id int32, teamDto *dto.TeamDto) (result *proto.Team, err *dto.ResponseErr) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+// Create creates the team and puts it under the parent team when the parent is set. The upstream has no transaction so a
+// team that cannot be put under its parent is deleted again instead of being left without it
+func (s *TeamService) Create(ctx context.Context, teamDto *dto.TeamDto) (result *proto.Team, err *dto.ResponseErr) {
+	var parent *proto.Team
+	if teamDto.ParentTeamID.ID > 0 {
+		parent, err = s.FindOne(ctx, teamDto.ParentTeamID.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	result, err = s.create(ctx, s.DtoToRaw(teamDto))
+	if err != nil || parent == nil {
+		return
+	}
+
+	parent.SubTeams = append(parent.SubTeams, result)
+	if _, err = s.update(ctx, parent); err != nil {
+		if _, undoErr := s.Delete(ctx, int32(result.Id)); undoErr != nil {
+			logf(ctx, "cannot delete the team %v that was not put under the team %v: %v\n", result.Id, parent.Id, undoErr.Message)
+		}
+
+		return nil, err
+	}
+
+	return
+}
+
+// Update updates the team and moves it under the parent team when the parent is set, an explicit null or 0 parent detaches
+// it from its parent. The upstream has no transaction so the team is updated before it is moved, a failed move keeps the
+// update of the team and puts the team back under its old parent
+func (s *TeamService) Update(ctx context.Context, id int32, teamDto *dto.TeamDto) (result *proto.Team, err *dto.ResponseErr) {
+	var parent *proto.Team
+	if teamDto.ParentTeamID.ID > 0 {
+		parent, err = s.findNewParent(ctx, id, teamDto.ParentTeamID.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	team := s.DtoToRaw(teamDto)
 	team.Id = uint32(id)
 
-	res, errRes := s.client.Update(ctx, &proto.UpdateTeamRequest{Team: team})
+	result, err = s.update(ctx, team)
+	if err != nil || !teamDto.ParentTeamID.Set {
+		return
+	}
+
+	if err = s.moveTo(ctx, result, parent); err != nil {
+		return nil, err
+	}
+
+	return
+}
+
+// FindTree returns the team with its sub-teams expanded down to the given depth, a negative depth means no limit
+func (s *TeamService) FindTree(ctx context.Context, id int32, depth int) (result *proto.Team, err *dto.ResponseErr) {
+	result, err = s.FindOne(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	visited := map[uint32]struct{}{result.Id: {}}
+	level := []*proto.Team{result}
+
+	for d := 0; depth < 0 || d < depth; d++ {
+		var ids []int32
+		for _, team := range level {
+			for _, sub := range team.SubTeams {
+				if _, ok := visited[sub.Id]; !ok {
+					visited[sub.Id] = struct{}{}
+					ids = append(ids, int32(sub.Id))
+				}
+			}
+		}
+
+		if len(ids) == 0 {
+			break
+		}
+
+		subTeams, errRes := s.FindMulti(ctx, ids)
+		if errRes != nil {
+			return nil, errRes
+		}
+
+		byID := map[uint32]*proto.Team{}
+		for _, sub := range subTeams {
+			byID[sub.Id] = sub
+		}
+
+		var next []*proto.Team
+		for _, team := range level {
+			var children []*proto.Team
+			for _, sub := range team.SubTeams {
+				if child, ok := byID[sub.Id]; ok {
+					children = append(children, child)
+					next = append(next, child)
+					delete(byID, sub.Id)
+				}
+			}
+			team.SubTeams = children
+		}
+
+		level = next
+	}
+
+	// the teams at the last level are not expanded, so drop their shallow sub-teams to make the cut explicit
+	for _, team := range level {
+		team.SubTeams = nil
+	}
+
+	return
+}
+
+func (s *TeamService) FindMembers(ctx context.Context, id int32, query *dto.PaginationQueryParams) (result *proto.UserPagination, err *dto.ResponseErr) {
+	team, err := s.FindOne(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return PaginateUsers(team.Members, query), nil
+}
+
+func (s *TeamService) AddMember(ctx context.Context, id int32, user *proto.User) (result *proto.Team, err *dto.ResponseErr) {
+	team, err := s.FindOne(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, member := range team.Members {
+		if member.Id == user.Id {
+			return team, nil
+		}
+	}
+
+	team.Members = append(team.Members, user)
+
+	return s.update(ctx, team)
+}
+
+func (s *TeamService) RemoveMember(ctx context.Context, id int32, userId int32) (result *proto.Team, err *dto.ResponseErr) {
+	team, err := s.FindOne(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var members []*proto.User
+	for _, member := range team.Members {
+		if int32(member.Id) != userId {
+			members = append(members, member)
+		}
+	}
+
+	if len(members) == len(team.Members) {
+		return nil, &dto.ResponseErr{
+			StatusCode: http.StatusNotFound,
+			Message:    "The team does not have this member",
+			Data:       nil,
+		}
+	}
+
+	team.Members = members
+
+	return s.update(ctx, team)
+}
+
+func (s *TeamService) Delete(ctx context.Context, id int32) (result *proto.Team, err *dto.ResponseErr) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	res, errRes := s.client.Delete(ctx, &proto.DeleteTeamRequest{Id: id})
 	if errRes != nil {
-		log.Printf("%v\n", errRes)
+		logUpstreamErr(ctx, errRes)
 		return nil, &dto.ResponseErr{
 			StatusCode: http.StatusServiceUnavailable,
 			Message:    "Service is down",
@@ -136,13 +302,26 @@ func (s *TeamService) Update(id int32, teamDto *dto.TeamDto) (result *proto.Team
 	return
 }
 
-func (s *TeamService) Delete(id int32) (result *proto.Team, err *dto.ResponseErr) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+func (TeamService) DtoToRaw(teamDto *dto.TeamDto) *proto.Team {
+	team := &proto.Team{
+		Name:        teamDto.Name,
+		Description: teamDto.Description,
+	}
+
+	if teamDto.OrganizationID > 0 {
+		team.Organization = &proto.Organization{Id: uint32(teamDto.OrganizationID)}
+	}
+
+	return team
+}
+
+func (s *TeamService) update(ctx context.Context, team *proto.Team) (result *proto.Team, err *dto.ResponseErr) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
-	res, errRes := s.client.Delete(ctx, &proto.DeleteTeamRequest{Id: id})
+	res, errRes := s.client.Update(ctx, &proto.UpdateTeamRequest{Team: team})
 	if errRes != nil {
-		log.Printf("%v\n", errRes)
+		logUpstreamErr(ctx, errRes)
 		return nil, &dto.ResponseErr{
 			StatusCode: http.StatusServiceUnavailable,
 			Message:    "Service is down",
@@ -163,9 +342,141 @@ func (s *TeamService) Delete(id int32) (result *proto.Team, err *dto.ResponseErr
 	return
 }
 
-func (TeamService) DtoToRaw(teamDto *dto.TeamDto) *proto.Team {
-	return &proto.Team{
-		Name:        teamDto.Name,
-		Description: teamDto.Description,
+func (s *TeamService) create(ctx context.Context, team *proto.Team) (result *proto.Team, err *dto.ResponseErr) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	res, errRes := s.client.Create(ctx, &proto.CreateTeamRequest{Team: team})
+	if errRes != nil {
+		logUpstreamErr(ctx, errRes)
+		return nil, &dto.ResponseErr{
+			StatusCode: http.StatusServiceUnavailable,
+			Message:    "Service is down",
+			Data:       nil,
+		}
+	}
+
+	if res.StatusCode != http.StatusCreated {
+		return nil, &dto.ResponseErr{
+			StatusCode: int(res.StatusCode),
+			Message:    FormatErr(res.Errors),
+			Data:       nil,
+		}
+	}
+
+	result = res.Data
+	return
+}
+
+// findNewParent loads the parent team and makes sure that it is not the team itself or one of its descendants
+func (s *TeamService) findNewParent(ctx context.Context, id int32, parentId int32) (*proto.Team, *dto.ResponseErr) {
+	cycleErr := &dto.ResponseErr{
+		StatusCode: http.StatusUnprocessableEntity,
+		Message:    "A team cannot be its own ancestor",
+		Data:       nil,
+	}
+
+	if id == parentId {
+		return nil, cycleErr
+	}
+
+	tree, err := s.FindTree(ctx, id, -1)
+	if err != nil {
+		return nil, err
 	}
+
+	if containsTeam(tree.SubTeams, uint32(parentId)) {
+		return nil, cycleErr
+	}
+
+	return s.FindOne(ctx, parentId)
+}
+
+// findParent walks through every team to find the one that has the team as its sub-team
+func (s *TeamService) findParent(ctx context.Context, id uint32) (*proto.Team, *dto.ResponseErr) {
+	query := &dto.PaginationQueryParams{
+		Limit: constant.FetchAllLimit,
+		Page:  1,
+	}
+
+	for {
+		teams, errRes := s.FindAll(ctx, query)
+		if errRes != nil {
+			return nil, errRes
+		}
+
+		for _, team := range teams.Items {
+			for _, sub := range team.SubTeams {
+				if sub.Id == id {
+					return team, nil
+				}
+			}
+		}
+
+		if teams.Meta == nil || query.Page >= teams.Meta.TotalPage {
+			break
+		}
+
+		query.Page++
+	}
+
+	return nil, nil
+}
+
+// moveTo moves the team under the parent, a nil parent detaches the team from its old parent. The team is removed from its
+// old parent before it is added to the new one, so the removal is undone when the addition fails
+func (s *TeamService) moveTo(ctx context.Context, team *proto.Team, parent *proto.Team) *dto.ResponseErr {
+	old, err := s.findParent(ctx, team.Id)
+	if err != nil {
+		return err
+	}
+
+	if old == nil {
+		if parent == nil {
+			return nil
+		}
+
+		parent.SubTeams = append(parent.SubTeams, team)
+		_, err = s.update(ctx, parent)
+
+		return err
+	}
+
+	if parent != nil && old.Id == parent.Id {
+		return nil
+	}
+
+	subTeams := old.SubTeams
+	old.SubTeams = nil
+	for _, sub := range subTeams {
+		if sub.Id != team.Id {
+			old.SubTeams = append(old.SubTeams, sub)
+		}
+	}
+
+	if _, err = s.update(ctx, old); err != nil || parent == nil {
+		return err
+	}
+
+	parent.SubTeams = append(parent.SubTeams, team)
+	if _, err = s.update(ctx, parent); err != nil {
+		old.SubTeams = subTeams
+		if _, undoErr := s.update(ctx, old); undoErr != nil {
+			logf(ctx, "cannot put the team %v back under the team %v: %v\n", team.Id, old.Id, undoErr.Message)
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func containsTeam(teams []*proto.Team, id uint32) bool {
+	for _, team := range teams {
+		if team.Id == id || containsTeam(team.SubTeams, id) {
+			return true
+		}
+	}
+
+	return false
 }
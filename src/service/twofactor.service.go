@@ -0,0 +1,525 @@
+package service
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/constant"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/dto"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/proto"
+	"github.com/skip2/go-qrcode"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	totpDigits = 6
+	totpPeriod = 30
+	// totpSkew is the number of steps before and after the current one that are accepted for the clock drift
+	totpSkew = 1
+
+	twoFactorMaxAttempts = 5
+)
+
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// TotpEnrollment is the authenticator of a user, the secret is encrypted and the recovery codes are sha256 hashes,
+// LastStep is the time step of the last accepted code so a code cannot be used twice
+type TotpEnrollment struct {
+	Secret        []byte
+	Confirmed     bool
+	LastStep      int64
+	RecoveryCodes []string
+}
+
+// TotpStore keeps the authenticators of the users, Get returns nil when the user has none
+type TotpStore interface {
+	Get(uint32) (*TotpEnrollment, error)
+	Set(uint32, *TotpEnrollment) error
+	Delete(uint32) error
+}
+
+// TwoFactorLockout counts the wrong codes of the users across their challenges, Check returns how long the user is locked out
+type TwoFactorLockout interface {
+	Check(context.Context, string, string) time.Duration
+	Fail(context.Context, string, string)
+	Reset(context.Context, string, string)
+}
+
+type twoFactorChallenge struct {
+	userId     uint32
+	credential *proto.Credential
+	expiresAt  time.Time
+	attempts   int
+}
+
+// TwoFactorService enrolls the RFC 6238 authenticators and holds the credential of a login until the code of the user is verified
+type TwoFactorService struct {
+	store         TotpStore
+	lockout       TwoFactorLockout
+	aead          cipher.AEAD
+	issuer        string
+	challengeTTL  time.Duration
+	recoveryCodes int
+	now           func() time.Time
+
+	mu         sync.Mutex
+	challenges map[string]*twoFactorChallenge
+	sweeper    sweeper
+}
+
+func NewTwoFactorService(store TotpStore, lockout TwoFactorLockout, encryptionKey []byte, issuer string, challengeTTL time.Duration, recoveryCodes int) (*TwoFactorService, error) {
+	if len(encryptionKey) != 32 {
+		return nil, fmt.Errorf("the encryption key must be 32 bytes, got %v", len(encryptionKey))
+	}
+
+	block, err := aes.NewCipher(encryptionKey)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TwoFactorService{
+		store:         store,
+		lockout:       lockout,
+		aead:          aead,
+		issuer:        issuer,
+		challengeTTL:  challengeTTL,
+		recoveryCodes: recoveryCodes,
+		now:           time.Now,
+		challenges:    map[string]*twoFactorChallenge{},
+	}, nil
+}
+
+// Setup creates a new secret that is used once the user confirms it with a code, the secret of an earlier setup that was not
+// confirmed is replaced
+func (s *TwoFactorService) Setup(ctx context.Context, userId uint32, account string) (*dto.TotpSetup, *dto.ResponseErr) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	enrollment, errRes := s.enrollment(ctx, userId)
+	if errRes != nil {
+		return nil, errRes
+	}
+
+	if enrollment != nil && enrollment.Confirmed {
+		return nil, responseErr(http.StatusConflict, "Two-factor authentication is already enabled")
+	}
+
+	secret := make([]byte, 20)
+	if _, err := rand.Read(secret); err != nil {
+		logf(ctx, "cannot create the totp secret of user %v: %v\n", userId, err)
+		return nil, responseErr(http.StatusInternalServerError, "Cannot create the secret")
+	}
+
+	encrypted, err := s.encrypt(secret)
+	if err != nil {
+		logf(ctx, "cannot encrypt the totp secret of user %v: %v\n", userId, err)
+		return nil, responseErr(http.StatusInternalServerError, "Cannot create the secret")
+	}
+
+	if err := s.store.Set(userId, &TotpEnrollment{Secret: encrypted}); err != nil {
+		logf(ctx, "cannot set the totp of user %v: %v\n", userId, err)
+		return nil, responseErr(http.StatusServiceUnavailable, "Service is down")
+	}
+
+	encoded := totpEncoding.EncodeToString(secret)
+	uri := s.uri(account, encoded)
+
+	png, err := qrcode.Encode(uri, qrcode.Medium, 256)
+	if err != nil {
+		logf(ctx, "cannot encode the totp qr code of user %v: %v\n", userId, err)
+		return nil, responseErr(http.StatusInternalServerError, "Cannot create the qr code")
+	}
+
+	return &dto.TotpSetup{
+		Secret: encoded,
+		Uri:    uri,
+		QrCode: base64.StdEncoding.EncodeToString(png),
+	}, nil
+}
+
+// Confirm enables the authenticator of the setup when the code matches and returns the recovery codes
+func (s *TwoFactorService) Confirm(ctx context.Context, userId uint32, code string) (*dto.RecoveryCodes, *dto.ResponseErr) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	enrollment, errRes := s.enrollment(ctx, userId)
+	if errRes != nil {
+		return nil, errRes
+	}
+
+	if enrollment == nil {
+		return nil, responseErr(http.StatusBadRequest, "Two-factor authentication is not set up")
+	}
+
+	if enrollment.Confirmed {
+		return nil, responseErr(http.StatusConflict, "Two-factor authentication is already enabled")
+	}
+
+	if !s.checkTotp(ctx, userId, enrollment, code) {
+		return nil, responseErr(http.StatusBadRequest, "Invalid code")
+	}
+
+	codes := make([]string, 0, s.recoveryCodes)
+	hashes := make([]string, 0, s.recoveryCodes)
+	for i := 0; i < s.recoveryCodes; i++ {
+		b := make([]byte, 10)
+		if _, err := rand.Read(b); err != nil {
+			logf(ctx, "cannot create the recovery codes of user %v: %v\n", userId, err)
+			return nil, responseErr(http.StatusInternalServerError, "Cannot create the recovery codes")
+		}
+
+		code := strings.ToLower(totpEncoding.EncodeToString(b))
+		codes = append(codes, code[:8]+"-"+code[8:])
+		hashes = append(hashes, hashRecoveryCode(code))
+	}
+
+	enrollment.Confirmed = true
+	enrollment.RecoveryCodes = hashes
+	if err := s.store.Set(userId, enrollment); err != nil {
+		logf(ctx, "cannot set the totp of user %v: %v\n", userId, err)
+		return nil, responseErr(http.StatusServiceUnavailable, "Service is down")
+	}
+
+	return &dto.RecoveryCodes{Codes: codes}, nil
+}
+
+// Challenge holds the credential of the login when the user has enabled the two-factor authentication, it returns nil
+// when the user has not so the credential is handed out right away
+func (s *TwoFactorService) Challenge(ctx context.Context, userId uint32, credential *proto.Credential) (*dto.TwoFactorChallenge, *dto.ResponseErr) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	enrollment, errRes := s.enrollment(ctx, userId)
+	if errRes != nil {
+		return nil, errRes
+	}
+
+	if enrollment == nil || !enrollment.Confirmed {
+		return nil, nil
+	}
+
+	token, err := randomToken()
+	if err != nil {
+		logf(ctx, "cannot create the challenge of user %v: %v\n", userId, err)
+		return nil, responseErr(http.StatusInternalServerError, "Cannot create the challenge")
+	}
+
+	now := s.now()
+	s.sweeper.run(now, func() {
+		for k, c := range s.challenges {
+			if !now.Before(c.expiresAt) {
+				delete(s.challenges, k)
+			}
+		}
+	})
+
+	s.challenges[token] = &twoFactorChallenge{
+		userId:     userId,
+		credential: credential,
+		expiresAt:  now.Add(s.challengeTTL),
+	}
+
+	return &dto.TwoFactorChallenge{
+		ChallengeToken: token,
+		ExpiresIn:      int32(s.challengeTTL.Seconds()),
+	}, nil
+}
+
+// Verify hands out the credential of the challenge when the code is a code of the authenticator or an unused recovery code,
+// the challenge is dropped after too many wrong codes so the user has to log in again and the wrong codes of every
+// challenge of the user count toward its lockout
+func (s *TwoFactorService) Verify(ctx context.Context, challengeToken string, code string) (uint32, *proto.Credential, *dto.ResponseErr) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	challenge, ok := s.challenges[challengeToken]
+	if !ok || !s.now().Before(challenge.expiresAt) {
+		delete(s.challenges, challengeToken)
+		return 0, nil, responseErr(http.StatusUnauthorized, "Invalid or expired challenge")
+	}
+
+	if errRes := s.lockedOut(ctx, challenge.userId); errRes != nil {
+		return 0, nil, errRes
+	}
+
+	enrollment, errRes := s.enrollment(ctx, challenge.userId)
+	if errRes != nil {
+		return 0, nil, errRes
+	}
+
+	if enrollment == nil || !s.checkCode(ctx, challenge.userId, enrollment, code) {
+		challenge.attempts++
+		if challenge.attempts >= twoFactorMaxAttempts {
+			delete(s.challenges, challengeToken)
+		}
+
+		return 0, nil, responseErr(http.StatusUnauthorized, "Invalid code")
+	}
+
+	if err := s.store.Set(challenge.userId, enrollment); err != nil {
+		logf(ctx, "cannot set the totp of user %v: %v\n", challenge.userId, err)
+		return 0, nil, responseErr(http.StatusServiceUnavailable, "Service is down")
+	}
+
+	delete(s.challenges, challengeToken)
+	s.lockout.Reset(ctx, constant.LockoutTwoFactorUser, lockoutKey(challenge.userId))
+
+	return challenge.userId, challenge.credential, nil
+}
+
+// Disable removes the authenticator of the user when the code is a code of the authenticator or an unused recovery code,
+// the wrong codes count toward the lockout of the user like the codes of the logins
+func (s *TwoFactorService) Disable(ctx context.Context, userId uint32, code string) *dto.ResponseErr {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if errRes := s.lockedOut(ctx, userId); errRes != nil {
+		return errRes
+	}
+
+	enrollment, errRes := s.enrollment(ctx, userId)
+	if errRes != nil {
+		return errRes
+	}
+
+	if enrollment == nil || !enrollment.Confirmed {
+		return responseErr(http.StatusBadRequest, "Two-factor authentication is not set up")
+	}
+
+	if !s.checkCode(ctx, userId, enrollment, code) {
+		return responseErr(http.StatusBadRequest, "Invalid code")
+	}
+
+	if err := s.store.Delete(userId); err != nil {
+		logf(ctx, "cannot delete the totp of user %v: %v\n", userId, err)
+		return responseErr(http.StatusServiceUnavailable, "Service is down")
+	}
+
+	s.lockout.Reset(ctx, constant.LockoutTwoFactorUser, lockoutKey(userId))
+
+	return nil
+}
+
+// lockedOut returns the error of a user that entered too many wrong codes
+func (s *TwoFactorService) lockedOut(ctx context.Context, userId uint32) *dto.ResponseErr {
+	if s.lockout.Check(ctx, constant.LockoutTwoFactorUser, lockoutKey(userId)) <= 0 {
+		return nil
+	}
+
+	return responseErr(http.StatusTooManyRequests, "Too many attempts, try again later")
+}
+
+// checkCode accepts a code of the authenticator or an unused recovery code, a wrong code is counted toward the lockout of
+// the user
+func (s *TwoFactorService) checkCode(ctx context.Context, userId uint32, enrollment *TotpEnrollment, code string) bool {
+	if s.checkTotp(ctx, userId, enrollment, code) || s.useRecoveryCode(enrollment, code) {
+		return true
+	}
+
+	s.lockout.Fail(ctx, constant.LockoutTwoFactorUser, lockoutKey(userId))
+
+	return false
+}
+
+func (s *TwoFactorService) enrollment(ctx context.Context, userId uint32) (*TotpEnrollment, *dto.ResponseErr) {
+	enrollment, err := s.store.Get(userId)
+	if err != nil {
+		logf(ctx, "cannot get the totp of user %v: %v\n", userId, err)
+		return nil, responseErr(http.StatusServiceUnavailable, "Service is down")
+	}
+
+	return enrollment, nil
+}
+
+// checkTotp accepts the code of the steps around the current one that come after the last accepted step, it moves the
+// last step of the enrollment forward and the caller stores it
+func (s *TwoFactorService) checkTotp(ctx context.Context, userId uint32, enrollment *TotpEnrollment, code string) bool {
+	if len(code) != totpDigits {
+		return false
+	}
+
+	secret, err := s.decrypt(enrollment.Secret)
+	if err != nil {
+		logf(ctx, "cannot decrypt the totp secret of user %v: %v\n", userId, err)
+		return false
+	}
+
+	current := s.now().Unix() / totpPeriod
+	for step := current - totpSkew; step <= current+totpSkew; step++ {
+		if step <= enrollment.LastStep {
+			continue
+		}
+
+		if subtle.ConstantTimeCompare([]byte(TotpCode(secret, step)), []byte(code)) == 1 {
+			enrollment.LastStep = step
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *TwoFactorService) useRecoveryCode(enrollment *TotpEnrollment, code string) bool {
+	hash := hashRecoveryCode(code)
+	for i, h := range enrollment.RecoveryCodes {
+		if subtle.ConstantTimeCompare([]byte(h), []byte(hash)) == 1 {
+			enrollment.RecoveryCodes = append(enrollment.RecoveryCodes[:i:i], enrollment.RecoveryCodes[i+1:]...)
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *TwoFactorService) uri(account string, secret string) string {
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", s.issuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprint(totpDigits))
+	query.Set("period", fmt.Sprint(totpPeriod))
+
+	return (&url.URL{
+		Scheme:   "otpauth",
+		Host:     "totp",
+		Path:     "/" + s.issuer + ":" + account,
+		RawQuery: query.Encode(),
+	}).String()
+}
+
+func (s *TwoFactorService) encrypt(plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	return s.aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func (s *TwoFactorService) decrypt(ciphertext []byte) ([]byte, error) {
+	if len(ciphertext) < s.aead.NonceSize() {
+		return nil, fmt.Errorf("the ciphertext is too short")
+	}
+
+	nonce := ciphertext[:s.aead.NonceSize()]
+
+	return s.aead.Open(nil, nonce, ciphertext[s.aead.NonceSize():], nil)
+}
+
+// TotpCode is the RFC 6238 code of the secret at the time step, it uses HMAC-SHA1 and 6 digits like the common authenticators
+func TotpCode(secret []byte, step int64) string {
+	msg := make([]byte, 8)
+	binary.BigEndian.PutUint64(msg, uint64(step))
+
+	mac := hmac.New(sha1.New, secret)
+	mac.Write(msg)
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	return fmt.Sprintf("%0*d", totpDigits, value%1000000)
+}
+
+func lockoutKey(userId uint32) string {
+	return strconv.FormatUint(uint64(userId), 10)
+}
+
+// hashRecoveryCode ignores the case and the separators so the user can type the code the way it is shown or not
+func hashRecoveryCode(code string) string {
+	code = strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	sum := sha256.Sum256([]byte(code))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// DisabledTwoFactor is used when the two-factor authentication is not configured, the logins are never challenged
+type DisabledTwoFactor struct{}
+
+func (DisabledTwoFactor) Setup(context.Context, uint32, string) (*dto.TotpSetup, *dto.ResponseErr) {
+	return nil, twoFactorDisabled()
+}
+
+func (DisabledTwoFactor) Confirm(context.Context, uint32, string) (*dto.RecoveryCodes, *dto.ResponseErr) {
+	return nil, twoFactorDisabled()
+}
+
+func (DisabledTwoFactor) Challenge(context.Context, uint32, *proto.Credential) (*dto.TwoFactorChallenge, *dto.ResponseErr) {
+	return nil, nil
+}
+
+func (DisabledTwoFactor) Verify(context.Context, string, string) (uint32, *proto.Credential, *dto.ResponseErr) {
+	return 0, nil, twoFactorDisabled()
+}
+
+func (DisabledTwoFactor) Disable(context.Context, uint32, string) *dto.ResponseErr {
+	return twoFactorDisabled()
+}
+
+func twoFactorDisabled() *dto.ResponseErr {
+	return responseErr(http.StatusNotFound, "Two-factor authentication is not enabled")
+}
+
+// MemoryTotpStore keeps copies of the enrollments so a caller cannot change the stored one without Set
+type MemoryTotpStore struct {
+	mu          sync.RWMutex
+	enrollments map[uint32]TotpEnrollment
+}
+
+func NewMemoryTotpStore() *MemoryTotpStore {
+	return &MemoryTotpStore{enrollments: map[uint32]TotpEnrollment{}}
+}
+
+func (s *MemoryTotpStore) Get(userId uint32) (*TotpEnrollment, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	enrollment, ok := s.enrollments[userId]
+	if !ok {
+		return nil, nil
+	}
+
+	enrollment.RecoveryCodes = append([]string(nil), enrollment.RecoveryCodes...)
+
+	return &enrollment, nil
+}
+
+func (s *MemoryTotpStore) Set(userId uint32, enrollment *TotpEnrollment) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	copied := *enrollment
+	copied.RecoveryCodes = append([]string(nil), enrollment.RecoveryCodes...)
+	s.enrollments[userId] = copied
+
+	return nil
+}
+
+func (s *MemoryTotpStore) Delete(userId uint32) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.enrollments, userId)
+
+	return nil
+}
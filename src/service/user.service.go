@@ -2,9 +2,9 @@ package service
 
 import (
 	"context"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/common"
 	"github.com/samithiwat/samithiwat-backend-gateway/src/dto"
 	"github.com/samithiwat/samithiwat-backend-gateway/src/proto"
-	"log"
 	"net/http"
 	"time"
 )
@@ -19,8 +19,8 @@ func NewUserService(client proto.UserServiceClient) *UserService {
 	}
 }
 
-func (s *UserService) FindAll(query *dto.PaginationQueryParams) (result *proto.UserPagination, err *dto.ResponseErr) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+func (s *UserService) FindAll(ctx context.Context, query *dto.PaginationQueryParams) (result *proto.UserPagination, err *dto.ResponseErr) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
 	req := &proto.FindAllUserRequest{
@@ -30,7 +30,7 @@ func (s *UserService) FindAll(query *dto.PaginationQueryParams) (result *proto.U
 
 	res, errRes := s.client.FindAll(ctx, req)
 	if errRes != nil {
-		log.Printf("%v\n", errRes)
+		logUpstreamErr(ctx, errRes)
 		return nil, &dto.ResponseErr{
 			StatusCode: http.StatusServiceUnavailable,
 			Message:    "Service is down",
@@ -51,13 +51,13 @@ func (s *UserService) FindAll(query *dto.PaginationQueryParams) (result *proto.U
 	return
 }
 
-func (s *UserService) FindOne(id int32) (result *proto.User, err *dto.ResponseErr) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+func (s *UserService) FindOne(ctx context.Context, id int32) (result *proto.User, err *dto.ResponseErr) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
 	res, errRes := s.client.FindOne(ctx, &proto.FindOneUserRequest{Id: id})
 	if errRes != nil {
-		log.Printf("%v\n", errRes)
+		logUpstreamErr(ctx, errRes)
 		return nil, &dto.ResponseErr{
 			StatusCode: http.StatusServiceUnavailable,
 			Message:    "Service is down",
@@ -78,15 +78,45 @@ func (s *UserService) FindOne(id int32) (result *proto.User, err *dto.ResponseEr
 	return
 }
 
-func (s *UserService) Create(userDto *dto.UserDto) (result *proto.User, err *dto.ResponseErr) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+func (s *UserService) FindMulti(ctx context.Context, ids []int32) (result []*proto.User, err *dto.ResponseErr) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	res, errRes := s.client.FindMulti(ctx, &proto.FindMultiUserRequest{Ids: ToUintIDs(ids)})
+	if errRes != nil {
+		logUpstreamErr(ctx, errRes)
+		return nil, &dto.ResponseErr{
+			StatusCode: http.StatusServiceUnavailable,
+			Message:    "Service is down",
+			Data:       nil,
+		}
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return nil, &dto.ResponseErr{
+			StatusCode: int(res.StatusCode),
+			Message:    FormatErr(res.Errors),
+			Data:       nil,
+		}
+	}
+
+	result = []*proto.User{}
+	for _, i := range common.OrderByIDs(ids, len(res.Data), func(i int) uint32 { return res.Data[i].Id }) {
+		result = append(result, res.Data[i])
+	}
+
+	return
+}
+
+func (s *UserService) Create(ctx context.Context, userDto *dto.UserDto) (result *proto.User, err *dto.ResponseErr) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
 	user := s.DtoToRaw(userDto)
 
 	res, errRes := s.client.Create(ctx, &proto.CreateUserRequest{User: user})
 	if errRes != nil {
-		log.Printf("%v\n", errRes)
+		logUpstreamErr(ctx, errRes)
 		return nil, &dto.ResponseErr{
 			StatusCode: http.StatusServiceUnavailable,
 			Message:    "Service is down",
@@ -106,8 +136,8 @@ func (s *UserService) Create(userDto *dto.UserDto) (result *proto.User, err *dto
 	return
 }
 
-func (s *UserService) Update(id int32, userDto *dto.UserDto) (result *proto.User, err *dto.ResponseErr) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+func (s *UserService) Update(ctx context.Context, id int32, userDto *dto.UserDto) (result *proto.User, err *dto.ResponseErr) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
 	user := s.DtoToRaw(userDto)
@@ -115,7 +145,7 @@ func (s *UserService) Update(id int32, userDto *dto.UserDto) (result *proto.User
 
 	res, errRes := s.client.Update(ctx, &proto.UpdateUserRequest{User: user})
 	if errRes != nil {
-		log.Printf("%v\n", errRes)
+		logUpstreamErr(ctx, errRes)
 		return nil, &dto.ResponseErr{
 			StatusCode: http.StatusServiceUnavailable,
 			Message:    "Service is down",
@@ -136,13 +166,13 @@ func (s *UserService) Update(id int32, userDto *dto.UserDto) (result *proto.User
 	return
 }
 
-func (s *UserService) Delete(id int32) (result *proto.User, err *dto.ResponseErr) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+func (s *UserService) Delete(ctx context.Context, id int32) (result *proto.User, err *dto.ResponseErr) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
 	res, errRes := s.client.Delete(ctx, &proto.DeleteUserRequest{Id: id})
 	if errRes != nil {
-		log.Printf("%v\n", errRes)
+		logUpstreamErr(ctx, errRes)
 		return nil, &dto.ResponseErr{
 			StatusCode: http.StatusServiceUnavailable,
 			Message:    "Service is down",
@@ -164,10 +194,20 @@ func (s *UserService) Delete(id int32) (result *proto.User, err *dto.ResponseErr
 }
 
 func (UserService) DtoToRaw(userDto *dto.UserDto) *proto.User {
-	return &proto.User{
+	user := &proto.User{
 		Firstname:   userDto.Firstname,
 		Lastname:    userDto.Lastname,
 		ImageUrl:    userDto.ImageUrl,
 		DisplayName: userDto.DisplayName,
 	}
+
+	if userDto.Contact != nil {
+		user.Contact = ContactService{}.DtoToRaw(userDto.Contact)
+	}
+
+	if userDto.Address != nil {
+		user.Address = LocationService{}.DtoToRaw(userDto.Address)
+	}
+
+	return user
 }
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/dto"
+	"net/http"
+	"sync"
+)
+
+// UserEmailStore keeps the email that each user last logged in with, Get returns an empty email for a user that did not
+// log in since the emails are kept
+type UserEmailStore interface {
+	Get(uint32) (string, error)
+	Set(uint32, string) error
+}
+
+// UserEmailService remembers the emails of the users, the upstream user has no email but the password policy rejects a
+// new password that is made of it
+type UserEmailService struct {
+	store UserEmailStore
+}
+
+func NewUserEmailService(store UserEmailStore) *UserEmailService {
+	return &UserEmailService{
+		store: store,
+	}
+}
+
+// Remember keeps the email of the user, the failures are only logged because the login already succeeded
+func (s *UserEmailService) Remember(ctx context.Context, userId uint32, email string) {
+	if err := s.store.Set(userId, email); err != nil {
+		logf(ctx, "cannot keep the email of user %v: %v\n", userId, err)
+	}
+}
+
+// Find returns the email of the user, it is empty when the user did not log in with a password since the emails are kept
+func (s *UserEmailService) Find(ctx context.Context, userId uint32) (string, *dto.ResponseErr) {
+	email, err := s.store.Get(userId)
+	if err != nil {
+		logf(ctx, "cannot get the email of user %v: %v\n", userId, err)
+		return "", responseErr(http.StatusServiceUnavailable, "Service is down")
+	}
+
+	return email, nil
+}
+
+type MemoryUserEmailStore struct {
+	mu     sync.RWMutex
+	emails map[uint32]string
+}
+
+func NewMemoryUserEmailStore() *MemoryUserEmailStore {
+	return &MemoryUserEmailStore{
+		emails: map[uint32]string{},
+	}
+}
+
+func (s *MemoryUserEmailStore) Get(userId uint32) (string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.emails[userId], nil
+}
+
+func (s *MemoryUserEmailStore) Set(userId uint32, email string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.emails[userId] = email
+
+	return nil
+}
@@ -0,0 +1,201 @@
+package service
+
+import (
+	"fmt"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/dto"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// VerificationStore keeps the email of the users that registered and did not verify it yet, the users that are not in the store
+// are verified so the accounts that were created before the verification was enabled keep their access
+type VerificationStore interface {
+	Get(uint32) (string, bool, error)
+	Set(uint32, string) error
+	Delete(uint32) error
+}
+
+type verificationClaims struct {
+	UserID    uint32 `json:"uid"`
+	Email     string `json:"email"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+type EmailVerificationService struct {
+	store  VerificationStore
+	mailer Mailer
+	secret []byte
+	ttl    time.Duration
+	url    string
+	now    func() time.Time
+}
+
+func NewEmailVerificationService(store VerificationStore, mailer Mailer, secret string, ttl time.Duration, url string) *EmailVerificationService {
+	return &EmailVerificationService{
+		store:  store,
+		mailer: mailer,
+		secret: []byte(secret),
+		ttl:    ttl,
+		url:    url,
+		now:    time.Now,
+	}
+}
+
+// Send marks the user as pending and mails the verification link, the failures are only logged because the account
+// already exists and the user can ask for the link again
+func (s *EmailVerificationService) Send(userId uint32, email string) {
+	if err := s.store.Set(userId, email); err != nil {
+		log.Printf("cannot set the verification of user %v: %v\n", userId, err)
+		return
+	}
+
+	if err := s.mail(userId, email); err != nil {
+		log.Printf("cannot send the verification mail of user %v: %v\n", userId, err)
+	}
+}
+
+// Resend mails a new verification link to the email that the pending user registered with
+func (s *EmailVerificationService) Resend(userId uint32) *dto.ResponseErr {
+	email, pending, err := s.store.Get(userId)
+	if err != nil {
+		log.Printf("cannot get the verification of user %v: %v\n", userId, err)
+		return verificationStoreErr()
+	}
+
+	if !pending {
+		return &dto.ResponseErr{
+			StatusCode: http.StatusConflict,
+			Message:    "Email is already verified",
+			Data:       nil,
+		}
+	}
+
+	if err := s.mail(userId, email); err != nil {
+		log.Printf("cannot send the verification mail of user %v: %v\n", userId, err)
+		return &dto.ResponseErr{
+			StatusCode: http.StatusServiceUnavailable,
+			Message:    "Cannot send the verification mail",
+			Data:       nil,
+		}
+	}
+
+	return nil
+}
+
+// Verify confirms the email of the user of the token, the token of a user that is already verified is accepted again
+func (s *EmailVerificationService) Verify(token string) *dto.ResponseErr {
+	claims := &verificationClaims{}
+	if !parseClaims(s.secret, token, claims) || claims.UserID == 0 || !s.now().Before(time.Unix(claims.ExpiresAt, 0)) {
+		return &dto.ResponseErr{
+			StatusCode: http.StatusBadRequest,
+			Message:    "Invalid or expired verification token",
+			Data:       nil,
+		}
+	}
+
+	if err := s.store.Delete(claims.UserID); err != nil {
+		log.Printf("cannot verify user %v: %v\n", claims.UserID, err)
+		return verificationStoreErr()
+	}
+
+	return nil
+}
+
+func (s *EmailVerificationService) IsVerified(userId uint32) (bool, *dto.ResponseErr) {
+	_, pending, err := s.store.Get(userId)
+	if err != nil {
+		log.Printf("cannot get the verification of user %v: %v\n", userId, err)
+		return false, verificationStoreErr()
+	}
+
+	return !pending, nil
+}
+
+func (s *EmailVerificationService) mail(userId uint32, email string) error {
+	token, err := signClaims(s.secret, &verificationClaims{
+		UserID:    userId,
+		Email:     email,
+		ExpiresAt: s.now().Add(s.ttl).Unix(),
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.mailer.Send(&dto.Mail{
+		To:      email,
+		Subject: "Verify your email",
+		Body:    fmt.Sprintf("Open the link below to verify your email, it expires in %v.\n\n%v\n", s.ttl, strings.Replace(s.url, "%s", token, 1)),
+	})
+}
+
+func verificationStoreErr() *dto.ResponseErr {
+	return &dto.ResponseErr{
+		StatusCode: http.StatusServiceUnavailable,
+		Message:    "Service is down",
+		Data:       nil,
+	}
+}
+
+// DisabledVerification is used when the email verification is not enabled, every user is verified
+type DisabledVerification struct{}
+
+func (DisabledVerification) Send(uint32, string) {}
+
+func (DisabledVerification) Resend(uint32) *dto.ResponseErr {
+	return verificationDisabled()
+}
+
+func (DisabledVerification) Verify(string) *dto.ResponseErr {
+	return verificationDisabled()
+}
+
+func (DisabledVerification) IsVerified(uint32) (bool, *dto.ResponseErr) {
+	return true, nil
+}
+
+func verificationDisabled() *dto.ResponseErr {
+	return &dto.ResponseErr{
+		StatusCode: http.StatusNotFound,
+		Message:    "Email verification is not enabled",
+		Data:       nil,
+	}
+}
+
+type MemoryVerificationStore struct {
+	mu      sync.RWMutex
+	pending map[uint32]string
+}
+
+func NewMemoryVerificationStore() *MemoryVerificationStore {
+	return &MemoryVerificationStore{pending: map[uint32]string{}}
+}
+
+func (s *MemoryVerificationStore) Get(userId uint32) (string, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	email, ok := s.pending[userId]
+
+	return email, ok, nil
+}
+
+func (s *MemoryVerificationStore) Set(userId uint32, email string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pending[userId] = email
+
+	return nil
+}
+
+func (s *MemoryVerificationStore) Delete(userId uint32) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.pending, userId)
+
+	return nil
+}
@@ -0,0 +1,49 @@
+package activity
+
+import (
+	"github.com/samithiwat/samithiwat-backend-gateway/src/dto"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/proto"
+	"github.com/stretchr/testify/mock"
+)
+
+type ServiceMock struct {
+	mock.Mock
+}
+
+func (s *ServiceMock) Record(logType proto.LogType, userId uint32, title string) {
+	s.Called(logType, userId, title)
+}
+
+func (s *ServiceMock) FindByUser(userId uint32) (res []*proto.Log) {
+	args := s.Called(userId)
+
+	if args.Get(0) != nil {
+		res = args.Get(0).([]*proto.Log)
+	}
+
+	return
+}
+
+func (s *ServiceMock) Paginate(logs []*proto.Log, query *dto.ActivityQueryParams) (res *dto.LogPagination, err *dto.ResponseErr) {
+	args := s.Called(logs, query)
+
+	if args.Get(0) != nil {
+		res = args.Get(0).(*dto.LogPagination)
+	}
+
+	if args.Get(1) != nil {
+		err = args.Get(1).(*dto.ResponseErr)
+	}
+
+	return
+}
+
+type SinkMock struct {
+	mock.Mock
+}
+
+func (s *SinkMock) Write(entry *proto.Log) error {
+	args := s.Called(entry)
+
+	return args.Error(0)
+}
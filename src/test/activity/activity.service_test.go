@@ -0,0 +1,191 @@
+package activity
+
+import (
+	"github.com/bxcodec/faker/v3"
+	"github.com/pkg/errors"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/dto"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/proto"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"testing"
+)
+
+type ActivityServiceTest struct {
+	suite.Suite
+	Logs []*proto.Log
+}
+
+func TestActivityService(t *testing.T) {
+	suite.Run(t, new(ActivityServiceTest))
+}
+
+func (s *ActivityServiceTest) SetupTest() {
+	s.Logs = []*proto.Log{
+		{
+			Title:     faker.Word(),
+			Type:      proto.LogType_POST,
+			User:      &proto.User{Id: 1},
+			Timestamp: "2022-01-01T00:00:00Z",
+		},
+		{
+			Title:     faker.Word(),
+			Type:      proto.LogType_LOGIN,
+			User:      &proto.User{Id: 1},
+			Timestamp: "2022-01-03T00:00:00Z",
+		},
+		{
+			Title:     faker.Word(),
+			Type:      proto.LogType_LOGIN,
+			User:      &proto.User{Id: 2},
+			Timestamp: "2022-01-02T00:00:00Z",
+		},
+	}
+}
+
+func (s *ActivityServiceTest) TestRecordSuccess() {
+	sink := service.NewMemoryActivitySink(0)
+
+	srv := service.NewActivityService(sink)
+	srv.Record(proto.LogType_LOGIN, 1, "Login")
+
+	logs := sink.Logs()
+
+	assert.Len(s.T(), logs, 1)
+	assert.Equal(s.T(), proto.LogType_LOGIN, logs[0].Type)
+	assert.Equal(s.T(), uint32(1), logs[0].User.Id)
+	assert.Equal(s.T(), "Login", logs[0].Title)
+	assert.NotEmpty(s.T(), logs[0].Timestamp)
+}
+
+func (s *ActivityServiceTest) TestRecordSinkErr() {
+	sink := new(SinkMock)
+
+	sink.On("Write", mock.AnythingOfType("*proto.Log")).Return(errors.New("Sink is down"))
+
+	srv := service.NewActivityService(sink)
+
+	assert.NotPanics(s.T(), func() {
+		srv.Record(proto.LogType_CHANGE_PASSWORD, 1, "Change password")
+	})
+	sink.AssertNumberOfCalls(s.T(), "Write", 1)
+}
+
+func (s *ActivityServiceTest) TestFindByUserSuccess() {
+	want := []*proto.Log{s.Logs[0], s.Logs[1]}
+
+	sink := service.NewMemoryActivitySink(0)
+	for _, l := range s.Logs {
+		_ = sink.Write(l)
+	}
+
+	srv := service.NewActivityService(sink)
+
+	actual := srv.FindByUser(1)
+
+	assert.Equal(s.T(), want, actual)
+}
+
+func (s *ActivityServiceTest) TestFindByUserNotReadableSink() {
+	srv := service.NewActivityService(service.LogActivitySink{})
+
+	actual := srv.FindByUser(1)
+
+	assert.Nil(s.T(), actual)
+}
+
+func (s *ActivityServiceTest) TestFindByUserMultiSink() {
+	want := []*proto.Log{s.Logs[0], s.Logs[1]}
+
+	sink := service.MultiActivitySink{service.LogActivitySink{}, service.NewMemoryActivitySink(0)}
+	for _, l := range s.Logs {
+		_ = sink.Write(l)
+	}
+
+	srv := service.NewActivityService(sink)
+
+	actual := srv.FindByUser(1)
+
+	assert.Equal(s.T(), want, actual)
+}
+
+func (s *ActivityServiceTest) TestFindByUserRetainLatest() {
+	want := []*proto.Log{s.Logs[1]}
+
+	sink := service.NewMemoryActivitySink(1)
+	for _, l := range s.Logs {
+		_ = sink.Write(l)
+	}
+
+	srv := service.NewActivityService(sink)
+
+	assert.Equal(s.T(), want, srv.FindByUser(1))
+	assert.Equal(s.T(), []*proto.Log{s.Logs[2]}, srv.FindByUser(2))
+}
+
+func (s *ActivityServiceTest) TestPaginateSuccess() {
+	want := &dto.LogPagination{
+		Items: []*proto.Log{s.Logs[1], s.Logs[2]},
+		Meta: &proto.PaginationMetadata{
+			TotalItem:    3,
+			ItemCount:    2,
+			ItemsPerPage: 2,
+			TotalPage:    2,
+			CurrentPage:  1,
+		},
+	}
+
+	srv := service.NewActivityService(service.NewMemoryActivitySink(0))
+
+	actual, err := srv.Paginate(s.Logs, &dto.ActivityQueryParams{Limit: 2, Page: 1})
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), want, actual)
+}
+
+func (s *ActivityServiceTest) TestPaginateFilterType() {
+	want := &dto.LogPagination{
+		Items: []*proto.Log{s.Logs[1], s.Logs[2]},
+		Meta: &proto.PaginationMetadata{
+			TotalItem:    2,
+			ItemCount:    2,
+			ItemsPerPage: 10,
+			TotalPage:    1,
+			CurrentPage:  1,
+		},
+	}
+
+	srv := service.NewActivityService(service.NewMemoryActivitySink(0))
+
+	actual, err := srv.Paginate(s.Logs, &dto.ActivityQueryParams{Type: "LOGIN"})
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), want, actual)
+}
+
+func (s *ActivityServiceTest) TestPaginateOutOfRange() {
+	srv := service.NewActivityService(service.NewMemoryActivitySink(0))
+
+	actual, err := srv.Paginate(s.Logs, &dto.ActivityQueryParams{Limit: 10, Page: 3})
+
+	assert.Nil(s.T(), err)
+	assert.Empty(s.T(), actual.Items)
+	assert.Equal(s.T(), int64(3), actual.Meta.TotalItem)
+}
+
+func (s *ActivityServiceTest) TestPaginateInvalidType() {
+	want := &dto.ResponseErr{
+		StatusCode: http.StatusBadRequest,
+		Message:    "Invalid log type",
+		Data:       nil,
+	}
+
+	srv := service.NewActivityService(service.NewMemoryActivitySink(0))
+
+	actual, err := srv.Paginate(s.Logs, &dto.ActivityQueryParams{Type: "UNKNOWN"})
+
+	assert.Nil(s.T(), actual)
+	assert.Equal(s.T(), want, err)
+}
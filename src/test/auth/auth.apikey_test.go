@@ -0,0 +1,165 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/dto"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+type ApiKeyServiceTest struct {
+	suite.Suite
+	Secret  string
+	Key     *service.ApiKey
+	Signed  *service.ApiKey
+	Expired *service.ApiKey
+	Store   *service.MemoryApiKeyStore
+}
+
+func TestApiKeyService(t *testing.T) {
+	suite.Run(t, new(ApiKeyServiceTest))
+}
+
+func (s *ApiKeyServiceTest) SetupTest() {
+	s.Secret = "secret"
+	hash := sha256.Sum256([]byte(s.Secret))
+
+	s.Key, _ = service.NewApiKey("billing", hex.EncodeToString(hash[:]), []string{"user:update"}, "", "", 0)
+	s.Signed, _ = service.NewApiKey("report", hex.EncodeToString(hash[:]), nil, "", "signing-secret", 3)
+	s.Expired, _ = service.NewApiKey("legacy", hex.EncodeToString(hash[:]), nil, "2020-01-01T00:00:00Z", "", 0)
+
+	s.Store = service.NewMemoryApiKeyStore([]*service.ApiKey{s.Key, s.Signed, s.Expired})
+}
+
+func (s *ApiKeyServiceTest) signed(timestamp time.Time) *dto.SignedRequest {
+	req := &dto.SignedRequest{
+		Method:    "POST",
+		URI:       "/user?page=1",
+		Body:      []byte(`{"firstname":"Samithiwat"}`),
+		Timestamp: strconv.FormatInt(timestamp.Unix(), 10),
+	}
+	req.Signature = hex.EncodeToString(service.SignRequest("signing-secret", req))
+
+	return req
+}
+
+func unauthorizedErr(message string) *dto.ResponseErr {
+	return &dto.ResponseErr{
+		StatusCode: http.StatusUnauthorized,
+		Message:    message,
+		Data:       nil,
+	}
+}
+
+func (s *ApiKeyServiceTest) TestAuthenticateSuccess() {
+	want := &dto.ApiClient{ID: "billing", Scopes: []string{"user:update"}}
+
+	srv := service.NewApiKeyService(s.Store, time.Minute)
+
+	client, err := srv.Authenticate("billing.secret", &dto.SignedRequest{})
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), want, client)
+}
+
+func (s *ApiKeyServiceTest) TestAuthenticateInvalidSecret() {
+	srv := service.NewApiKeyService(s.Store, time.Minute)
+
+	_, err := srv.Authenticate("billing.wrong", &dto.SignedRequest{})
+
+	assert.Equal(s.T(), unauthorizedErr("Invalid API key"), err)
+}
+
+func (s *ApiKeyServiceTest) TestAuthenticateUnknownKey() {
+	srv := service.NewApiKeyService(s.Store, time.Minute)
+
+	_, err := srv.Authenticate("unknown.secret", &dto.SignedRequest{})
+	assert.Equal(s.T(), unauthorizedErr("Invalid API key"), err)
+
+	_, err = srv.Authenticate("secret", &dto.SignedRequest{})
+	assert.Equal(s.T(), unauthorizedErr("Invalid API key"), err)
+}
+
+func (s *ApiKeyServiceTest) TestAuthenticateExpired() {
+	srv := service.NewApiKeyService(s.Store, time.Minute)
+
+	_, err := srv.Authenticate("legacy.secret", &dto.SignedRequest{})
+
+	assert.Equal(s.T(), unauthorizedErr("API key is expired"), err)
+}
+
+func (s *ApiKeyServiceTest) TestAuthenticateSignatureSuccess() {
+	srv := service.NewApiKeyService(s.Store, time.Minute)
+
+	client, err := srv.Authenticate("report.secret", s.signed(time.Now()))
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), int32(3), client.UserID)
+}
+
+func (s *ApiKeyServiceTest) TestAuthenticateSignatureRequired() {
+	srv := service.NewApiKeyService(s.Store, time.Minute)
+
+	_, err := srv.Authenticate("report.secret", &dto.SignedRequest{Method: "POST", URI: "/user"})
+
+	assert.Equal(s.T(), unauthorizedErr("Request signature is required"), err)
+}
+
+func (s *ApiKeyServiceTest) TestAuthenticateSignatureTampered() {
+	srv := service.NewApiKeyService(s.Store, time.Minute)
+
+	req := s.signed(time.Now())
+	req.Body = []byte(`{"firstname":"Someone"}`)
+
+	_, err := srv.Authenticate("report.secret", req)
+
+	assert.Equal(s.T(), unauthorizedErr("Invalid request signature"), err)
+}
+
+func (s *ApiKeyServiceTest) TestAuthenticateSignatureTamperedQuery() {
+	srv := service.NewApiKeyService(s.Store, time.Minute)
+
+	req := s.signed(time.Now())
+	req.URI = "/user?page=2"
+
+	_, err := srv.Authenticate("report.secret", req)
+
+	assert.Equal(s.T(), unauthorizedErr("Invalid request signature"), err)
+}
+
+func (s *ApiKeyServiceTest) TestAuthenticateSignatureExpired() {
+	srv := service.NewApiKeyService(s.Store, time.Minute)
+
+	_, err := srv.Authenticate("report.secret", s.signed(time.Now().Add(-2*time.Minute)))
+
+	assert.Equal(s.T(), unauthorizedErr("Request signature is expired"), err)
+}
+
+func (s *ApiKeyServiceTest) TestAuthenticateSignatureReplayed() {
+	srv := service.NewApiKeyService(s.Store, time.Minute)
+
+	req := s.signed(time.Now())
+
+	_, err := srv.Authenticate("report.secret", req)
+	assert.Nil(s.T(), err)
+
+	_, err = srv.Authenticate("report.secret", req)
+	assert.Equal(s.T(), unauthorizedErr("Request signature is already used"), err)
+}
+
+func (s *ApiKeyServiceTest) TestNewApiKeyInvalid() {
+	_, err := service.NewApiKey("billing", "not-a-hash", nil, "", "", 0)
+	assert.NotNil(s.T(), err)
+
+	_, err = service.NewApiKey("bill.ing", hex.EncodeToString(make([]byte, 32)), nil, "", "", 0)
+	assert.NotNil(s.T(), err)
+
+	_, err = service.NewApiKey("billing", hex.EncodeToString(make([]byte, 32)), nil, "tomorrow", "", 0)
+	assert.NotNil(s.T(), err)
+}
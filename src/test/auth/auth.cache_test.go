@@ -0,0 +1,182 @@
+package auth
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"github.com/bxcodec/faker/v3"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/dto"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+type TokenCacheTest struct {
+	suite.Suite
+	Token           string
+	UserId          uint32
+	UnauthorizedErr *dto.ResponseErr
+}
+
+func TestTokenCache(t *testing.T) {
+	suite.Run(t, new(TokenCacheTest))
+}
+
+func (u *TokenCacheTest) SetupTest() {
+	u.Token = faker.Word()
+	u.UserId = 1
+
+	u.UnauthorizedErr = &dto.ResponseErr{
+		StatusCode: http.StatusUnauthorized,
+		Message:    "Invalid token",
+		Data:       nil,
+	}
+}
+
+func tokenWithExpiry(exp time.Time) string {
+	claims, _ := json.Marshal(map[string]interface{}{"sub": "1", "exp": exp.Unix()})
+
+	return "e30." + base64.RawURLEncoding.EncodeToString(claims) + ".c2ln"
+}
+
+func (u *TokenCacheTest) TestValidateHit() {
+	srv := new(ServiceMock)
+	srv.On("Validate", u.Token).Return(int(u.UserId), nil)
+
+	cache := service.NewTokenCache(srv, 10, time.Minute)
+
+	for i := 0; i < 3; i++ {
+		userId, err := cache.Validate(context.Background(), u.Token)
+
+		assert.Nil(u.T(), err)
+		assert.Equal(u.T(), u.UserId, userId)
+	}
+
+	srv.AssertNumberOfCalls(u.T(), "Validate", 1)
+	assert.Equal(u.T(), &dto.TokenCacheStats{Hits: 2, Misses: 1, Size: 1, Capacity: 10}, cache.Stats())
+}
+
+func (u *TokenCacheTest) TestValidateErrNotCached() {
+	srv := new(ServiceMock)
+	srv.On("Validate", u.Token).Return(-1, u.UnauthorizedErr)
+
+	cache := service.NewTokenCache(srv, 10, time.Minute)
+
+	for i := 0; i < 2; i++ {
+		_, err := cache.Validate(context.Background(), u.Token)
+
+		assert.Equal(u.T(), u.UnauthorizedErr, err)
+	}
+
+	srv.AssertNumberOfCalls(u.T(), "Validate", 2)
+}
+
+func (u *TokenCacheTest) TestValidateTTLExpired() {
+	srv := new(ServiceMock)
+	srv.On("Validate", u.Token).Return(int(u.UserId), nil)
+
+	cache := service.NewTokenCache(srv, 10, 20*time.Millisecond)
+
+	_, _ = cache.Validate(context.Background(), u.Token)
+	time.Sleep(40 * time.Millisecond)
+	_, _ = cache.Validate(context.Background(), u.Token)
+
+	srv.AssertNumberOfCalls(u.T(), "Validate", 2)
+}
+
+func (u *TokenCacheTest) TestValidateCappedByTokenExpiry() {
+	token := tokenWithExpiry(time.Now().Add(-time.Second))
+
+	srv := new(ServiceMock)
+	srv.On("Validate", token).Return(int(u.UserId), nil)
+
+	cache := service.NewTokenCache(srv, 10, time.Hour)
+
+	_, _ = cache.Validate(context.Background(), token)
+	_, _ = cache.Validate(context.Background(), token)
+
+	srv.AssertNumberOfCalls(u.T(), "Validate", 2)
+	assert.Equal(u.T(), 0, cache.Stats().Size)
+}
+
+func (u *TokenCacheTest) TestValidateLeastRecentlyUsedEvicted() {
+	srv := new(ServiceMock)
+	srv.On("Validate", "a").Return(1, nil)
+	srv.On("Validate", "b").Return(2, nil)
+	srv.On("Validate", "c").Return(3, nil)
+
+	cache := service.NewTokenCache(srv, 2, time.Minute)
+
+	_, _ = cache.Validate(context.Background(), "a")
+	_, _ = cache.Validate(context.Background(), "b")
+	_, _ = cache.Validate(context.Background(), "a")
+	_, _ = cache.Validate(context.Background(), "c")
+	_, _ = cache.Validate(context.Background(), "a")
+	_, _ = cache.Validate(context.Background(), "b")
+
+	srv.AssertNumberOfCalls(u.T(), "Validate", 4)
+	assert.Equal(u.T(), 2, cache.Stats().Size)
+}
+
+func (u *TokenCacheTest) TestEvictUser() {
+	srv := new(ServiceMock)
+	srv.On("Validate", "a").Return(1, nil)
+	srv.On("Validate", "b").Return(1, nil)
+	srv.On("Validate", "c").Return(2, nil)
+
+	cache := service.NewTokenCache(srv, 10, time.Minute)
+
+	_, _ = cache.Validate(context.Background(), "a")
+	_, _ = cache.Validate(context.Background(), "b")
+	_, _ = cache.Validate(context.Background(), "c")
+
+	cache.EvictUser(1)
+
+	assert.Equal(u.T(), 1, cache.Stats().Size)
+
+	_, _ = cache.Validate(context.Background(), "a")
+	_, _ = cache.Validate(context.Background(), "c")
+
+	srv.AssertNumberOfCalls(u.T(), "Validate", 4)
+}
+
+func (u *TokenCacheTest) TestValidateCoalesced() {
+	srv := new(ServiceMock)
+	srv.On("Validate", u.Token).After(50*time.Millisecond).Return(int(u.UserId), nil)
+
+	cache := service.NewTokenCache(srv, 10, time.Minute)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			userId, err := cache.Validate(context.Background(), u.Token)
+
+			assert.Nil(u.T(), err)
+			assert.Equal(u.T(), u.UserId, userId)
+		}()
+	}
+	wg.Wait()
+
+	srv.AssertNumberOfCalls(u.T(), "Validate", 1)
+	stats := cache.Stats()
+	assert.Equal(u.T(), uint64(9), stats.Coalesced+stats.Hits)
+}
+
+func (u *TokenCacheTest) TestValidateDisabled() {
+	srv := new(ServiceMock)
+	srv.On("Validate", u.Token).Return(int(u.UserId), nil)
+
+	cache := service.NewTokenCache(srv, 0, time.Minute)
+
+	_, _ = cache.Validate(context.Background(), u.Token)
+	_, _ = cache.Validate(context.Background(), u.Token)
+
+	srv.AssertNumberOfCalls(u.T(), "Validate", 2)
+}
@@ -1,17 +1,26 @@
 package auth
 
 import (
+	"context"
 	"github.com/bxcodec/faker/v3"
 	"github.com/pkg/errors"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/config"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/constant"
 	"github.com/samithiwat/samithiwat-backend-gateway/src/dto"
 	"github.com/samithiwat/samithiwat-backend-gateway/src/handler"
 	"github.com/samithiwat/samithiwat-backend-gateway/src/proto"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/service"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/test/activity"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/test/twofactor"
 	"github.com/samithiwat/samithiwat-backend-gateway/src/test/user"
 	"github.com/samithiwat/samithiwat-backend-gateway/src/validator"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 	"net/http"
+	"strings"
 	"testing"
+	"time"
 )
 
 type AuthHandlerTest struct {
@@ -25,6 +34,10 @@ type AuthHandlerTest struct {
 	BadRequestErr   *dto.ResponseErr
 	UnauthorizedErr *dto.ResponseErr
 	ServiceDownErr  *dto.ResponseErr
+	Session         config.Session
+	CookieSession   config.Session
+	Sessions        *service.SessionService
+	Emails          *service.UserEmailService
 }
 
 func TestAuthHandler(t *testing.T) {
@@ -82,6 +95,23 @@ func (u *AuthHandlerTest) SetupTest() {
 		Data:       nil,
 	}
 
+	u.Session = config.Session{Mode: "header"}
+
+	u.Sessions = service.NewSessionService(service.NewMemorySessionStore(), time.Hour)
+
+	u.Emails = service.NewUserEmailService(service.NewMemoryUserEmailStore())
+
+	u.CookieSession = config.Session{
+		Mode:          "cookie",
+		AccessCookie:  "access_token",
+		RefreshCookie: "refresh_token",
+		CsrfCookie:    "csrf_token",
+		CsrfHeader:    "X-CSRF-Token",
+		Secure:        true,
+		SameSite:      "Strict",
+		RefreshMaxAge: 24 * time.Hour,
+	}
+
 	u.BadRequestErr = &dto.ResponseErr{
 		StatusCode: http.StatusBadRequest,
 		Message:    "Invalid request body",
@@ -94,6 +124,9 @@ func (u *AuthHandlerTest) TestRegisterSuccess() {
 
 	srv := new(ServiceMock)
 	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	tokenCache := new(TokenCacheMock)
+	lockoutSrv := new(LockoutServiceMock)
 	c := &ContextMock{
 		User:           u.User,
 		RegisterDto:    u.RegisterDto,
@@ -106,12 +139,37 @@ func (u *AuthHandlerTest) TestRegisterSuccess() {
 
 	v, _ := validator.NewValidator()
 
-	h := handler.NewAuthHandler(srv, userSrv, v)
+	h := handler.NewAuthHandler(srv, handler.AuthDeps{Users: userSrv, Activity: activitySrv, TokenCache: tokenCache, Lockout: lockoutSrv, Verifier: service.DisabledVerification{}, TwoFactor: service.DisabledTwoFactor{}, Sessions: u.Sessions, Emails: u.Emails}, u.Session, v)
 	h.Register(c)
 
 	assert.Equal(u.T(), want, c.V)
 }
 
+func (u *AuthHandlerTest) TestRegisterSendVerification() {
+	srv := new(ServiceMock)
+	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	tokenCache := new(TokenCacheMock)
+	lockoutSrv := new(LockoutServiceMock)
+	verifier := new(VerifierMock)
+	c := &ContextMock{
+		User:        u.User,
+		RegisterDto: u.RegisterDto,
+	}
+
+	srv.On("Register", c.RegisterDto).Return(u.User, nil)
+	c.On("Bind", &dto.Register{}).Return(nil)
+	verifier.On("Send", u.User.Id, u.RegisterDto.Email).Return()
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewAuthHandler(srv, handler.AuthDeps{Users: userSrv, Activity: activitySrv, TokenCache: tokenCache, Lockout: lockoutSrv, Verifier: verifier, TwoFactor: service.DisabledTwoFactor{}, Sessions: u.Sessions, Emails: u.Emails}, u.Session, v)
+	h.Register(c)
+
+	assert.Equal(u.T(), u.User, c.V)
+	verifier.AssertCalled(u.T(), "Send", u.User.Id, u.RegisterDto.Email)
+}
+
 func (u *AuthHandlerTest) TestRegisterErrEmailDuplicated() {
 	want := &dto.ResponseErr{
 		StatusCode: http.StatusUnprocessableEntity,
@@ -121,6 +179,9 @@ func (u *AuthHandlerTest) TestRegisterErrEmailDuplicated() {
 
 	srv := new(ServiceMock)
 	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	tokenCache := new(TokenCacheMock)
+	lockoutSrv := new(LockoutServiceMock)
 	c := &ContextMock{
 		User:           u.User,
 		RegisterDto:    u.RegisterDto,
@@ -133,7 +194,7 @@ func (u *AuthHandlerTest) TestRegisterErrEmailDuplicated() {
 
 	v, _ := validator.NewValidator()
 
-	h := handler.NewAuthHandler(srv, userSrv, v)
+	h := handler.NewAuthHandler(srv, handler.AuthDeps{Users: userSrv, Activity: activitySrv, TokenCache: tokenCache, Lockout: lockoutSrv, Verifier: service.DisabledVerification{}, TwoFactor: service.DisabledTwoFactor{}, Sessions: u.Sessions, Emails: u.Emails}, u.Session, v)
 	h.Register(c)
 
 	assert.Equal(u.T(), want, c.V)
@@ -148,6 +209,9 @@ func (u *AuthHandlerTest) TestRegisterInvalidDTO() {
 
 	srv := new(ServiceMock)
 	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	tokenCache := new(TokenCacheMock)
+	lockoutSrv := new(LockoutServiceMock)
 	c := &ContextMock{
 		User:           u.User,
 		RegisterDto:    u.RegisterDto,
@@ -160,7 +224,7 @@ func (u *AuthHandlerTest) TestRegisterInvalidDTO() {
 
 	v, _ := validator.NewValidator()
 
-	h := handler.NewAuthHandler(srv, userSrv, v)
+	h := handler.NewAuthHandler(srv, handler.AuthDeps{Users: userSrv, Activity: activitySrv, TokenCache: tokenCache, Lockout: lockoutSrv, Verifier: service.DisabledVerification{}, TwoFactor: service.DisabledTwoFactor{}, Sessions: u.Sessions, Emails: u.Emails}, u.Session, v)
 	h.Register(c)
 
 	assert.Equal(u.T(), want, c.V)
@@ -171,6 +235,9 @@ func (u *AuthHandlerTest) TestRegisterGrpcErr() {
 
 	srv := new(ServiceMock)
 	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	tokenCache := new(TokenCacheMock)
+	lockoutSrv := new(LockoutServiceMock)
 	c := &ContextMock{
 		User:           u.User,
 		RegisterDto:    u.RegisterDto,
@@ -183,7 +250,7 @@ func (u *AuthHandlerTest) TestRegisterGrpcErr() {
 
 	v, _ := validator.NewValidator()
 
-	h := handler.NewAuthHandler(srv, userSrv, v)
+	h := handler.NewAuthHandler(srv, handler.AuthDeps{Users: userSrv, Activity: activitySrv, TokenCache: tokenCache, Lockout: lockoutSrv, Verifier: service.DisabledVerification{}, TwoFactor: service.DisabledTwoFactor{}, Sessions: u.Sessions, Emails: u.Emails}, u.Session, v)
 
 	h.Register(c)
 
@@ -195,6 +262,9 @@ func (u *AuthHandlerTest) TestLoginSuccess() {
 
 	srv := new(ServiceMock)
 	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	tokenCache := new(TokenCacheMock)
+	lockoutSrv := new(LockoutServiceMock)
 	c := &ContextMock{
 		User:           u.User,
 		RegisterDto:    u.RegisterDto,
@@ -203,15 +273,80 @@ func (u *AuthHandlerTest) TestLoginSuccess() {
 	}
 
 	srv.On("Login", c.LoginDto).Return(u.Credential, nil)
+	srv.On("Validate", u.Credential.AccessToken).Return(int(u.User.Id), nil)
+	activitySrv.On("Record", proto.LogType_LOGIN, u.User.Id, "Login").Return()
 	c.On("Bind", &dto.Login{}).Return(nil)
+	lockoutSrv.On("Check", mock.Anything, mock.Anything).Return(time.Duration(0))
+	lockoutSrv.On("Fail", mock.Anything, mock.Anything).Return()
+	lockoutSrv.On("Reset", mock.Anything, mock.Anything).Return()
 
 	v, _ := validator.NewValidator()
 
-	h := handler.NewAuthHandler(srv, userSrv, v)
+	h := handler.NewAuthHandler(srv, handler.AuthDeps{Users: userSrv, Activity: activitySrv, TokenCache: tokenCache, Lockout: lockoutSrv, Verifier: service.DisabledVerification{}, TwoFactor: service.DisabledTwoFactor{}, Sessions: u.Sessions, Emails: u.Emails}, u.Session, v)
 
 	h.Login(c)
 
 	assert.Equal(u.T(), want, c.V)
+	activitySrv.AssertCalled(u.T(), "Record", proto.LogType_LOGIN, u.User.Id, "Login")
+	lockoutSrv.AssertCalled(u.T(), "Reset", constant.LockoutLoginEmail, strings.ToLower(u.LoginDto.Email))
+	lockoutSrv.AssertNotCalled(u.T(), "Reset", constant.LockoutLoginIP, mock.Anything)
+
+	email, _ := u.Emails.Find(context.Background(), u.User.Id)
+	assert.Equal(u.T(), u.LoginDto.Email, email)
+}
+
+func (u *AuthHandlerTest) TestLoginTwoFactorChallenge() {
+	want := &dto.TwoFactorChallenge{ChallengeToken: "challenge", ExpiresIn: 300}
+
+	srv := new(ServiceMock)
+	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	tokenCache := new(TokenCacheMock)
+	lockoutSrv := new(LockoutServiceMock)
+	twoFactorSrv := new(twofactor.ServiceMock)
+	c := &ContextMock{LoginDto: u.LoginDto}
+
+	srv.On("Login", c.LoginDto).Return(u.Credential, nil)
+	srv.On("Validate", u.Credential.AccessToken).Return(int(u.User.Id), nil)
+	twoFactorSrv.On("Challenge", u.User.Id, u.Credential).Return(want, nil)
+	c.On("Bind", &dto.Login{}).Return(nil)
+	lockoutSrv.On("Check", mock.Anything, mock.Anything).Return(time.Duration(0))
+	lockoutSrv.On("Reset", mock.Anything, mock.Anything).Return()
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewAuthHandler(srv, handler.AuthDeps{Users: userSrv, Activity: activitySrv, TokenCache: tokenCache, Lockout: lockoutSrv, Verifier: service.DisabledVerification{}, TwoFactor: twoFactorSrv, Sessions: u.Sessions, Emails: u.Emails}, u.CookieSession, v)
+
+	h.Login(c)
+
+	assert.Equal(u.T(), want, c.V)
+	assert.Empty(u.T(), c.Cookies)
+	activitySrv.AssertNotCalled(u.T(), "Record", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (u *AuthHandlerTest) TestLoginUnresolvedUser() {
+	srv := new(ServiceMock)
+	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	tokenCache := new(TokenCacheMock)
+	lockoutSrv := new(LockoutServiceMock)
+	twoFactorSrv := new(twofactor.ServiceMock)
+	c := &ContextMock{LoginDto: u.LoginDto}
+
+	srv.On("Login", c.LoginDto).Return(u.Credential, nil)
+	srv.On("Validate", u.Credential.AccessToken).Return(0, u.ServiceDownErr)
+	c.On("Bind", &dto.Login{}).Return(nil)
+	lockoutSrv.On("Check", mock.Anything, mock.Anything).Return(time.Duration(0))
+	lockoutSrv.On("Reset", mock.Anything, mock.Anything).Return()
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewAuthHandler(srv, handler.AuthDeps{Users: userSrv, Activity: activitySrv, TokenCache: tokenCache, Lockout: lockoutSrv, Verifier: service.DisabledVerification{}, TwoFactor: twoFactorSrv, Sessions: u.Sessions, Emails: u.Emails}, u.Session, v)
+
+	h.Login(c)
+
+	assert.Equal(u.T(), u.ServiceDownErr, c.V)
+	twoFactorSrv.AssertNotCalled(u.T(), "Challenge", mock.Anything, mock.Anything)
 }
 
 func (u *AuthHandlerTest) TestLoginUnAuthorizeErr() {
@@ -219,6 +354,9 @@ func (u *AuthHandlerTest) TestLoginUnAuthorizeErr() {
 
 	srv := new(ServiceMock)
 	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	tokenCache := new(TokenCacheMock)
+	lockoutSrv := new(LockoutServiceMock)
 	c := &ContextMock{
 		User:           u.User,
 		RegisterDto:    u.RegisterDto,
@@ -228,14 +366,19 @@ func (u *AuthHandlerTest) TestLoginUnAuthorizeErr() {
 
 	srv.On("Login", c.LoginDto).Return(nil, u.UnauthorizedErr)
 	c.On("Bind", &dto.Login{}).Return(nil)
+	lockoutSrv.On("Check", mock.Anything, mock.Anything).Return(time.Duration(0))
+	lockoutSrv.On("Fail", mock.Anything, mock.Anything).Return()
+	lockoutSrv.On("Reset", mock.Anything, mock.Anything).Return()
 
 	v, _ := validator.NewValidator()
 
-	h := handler.NewAuthHandler(srv, userSrv, v)
+	h := handler.NewAuthHandler(srv, handler.AuthDeps{Users: userSrv, Activity: activitySrv, TokenCache: tokenCache, Lockout: lockoutSrv, Verifier: service.DisabledVerification{}, TwoFactor: service.DisabledTwoFactor{}, Sessions: u.Sessions, Emails: u.Emails}, u.Session, v)
 
 	h.Login(c)
 
 	assert.Equal(u.T(), want, c.V)
+	lockoutSrv.AssertCalled(u.T(), "Fail", constant.LockoutLoginEmail, strings.ToLower(u.LoginDto.Email))
+	lockoutSrv.AssertCalled(u.T(), "Fail", constant.LockoutLoginIP, c.ClientIP)
 }
 
 func (u *AuthHandlerTest) TestLoginInvalidDTO() {
@@ -247,6 +390,9 @@ func (u *AuthHandlerTest) TestLoginInvalidDTO() {
 
 	srv := new(ServiceMock)
 	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	tokenCache := new(TokenCacheMock)
+	lockoutSrv := new(LockoutServiceMock)
 	c := &ContextMock{
 		User:           u.User,
 		RegisterDto:    u.RegisterDto,
@@ -256,10 +402,13 @@ func (u *AuthHandlerTest) TestLoginInvalidDTO() {
 
 	srv.On("Login", c.LoginDto).Return(nil, want)
 	c.On("Bind", &dto.Login{}).Return(nil)
+	lockoutSrv.On("Check", mock.Anything, mock.Anything).Return(time.Duration(0))
+	lockoutSrv.On("Fail", mock.Anything, mock.Anything).Return()
+	lockoutSrv.On("Reset", mock.Anything, mock.Anything).Return()
 
 	v, _ := validator.NewValidator()
 
-	h := handler.NewAuthHandler(srv, userSrv, v)
+	h := handler.NewAuthHandler(srv, handler.AuthDeps{Users: userSrv, Activity: activitySrv, TokenCache: tokenCache, Lockout: lockoutSrv, Verifier: service.DisabledVerification{}, TwoFactor: service.DisabledTwoFactor{}, Sessions: u.Sessions, Emails: u.Emails}, u.Session, v)
 
 	h.Login(c)
 
@@ -271,6 +420,9 @@ func (u *AuthHandlerTest) TestLoginGrpcErr() {
 
 	srv := new(ServiceMock)
 	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	tokenCache := new(TokenCacheMock)
+	lockoutSrv := new(LockoutServiceMock)
 	c := &ContextMock{
 		User:           u.User,
 		RegisterDto:    u.RegisterDto,
@@ -280,19 +432,61 @@ func (u *AuthHandlerTest) TestLoginGrpcErr() {
 
 	srv.On("Login", c.LoginDto).Return(nil, u.ServiceDownErr)
 	c.On("Bind", &dto.Login{}).Return(nil)
+	lockoutSrv.On("Check", mock.Anything, mock.Anything).Return(time.Duration(0))
+	lockoutSrv.On("Fail", mock.Anything, mock.Anything).Return()
+	lockoutSrv.On("Reset", mock.Anything, mock.Anything).Return()
 
 	v, _ := validator.NewValidator()
 
-	h := handler.NewAuthHandler(srv, userSrv, v)
+	h := handler.NewAuthHandler(srv, handler.AuthDeps{Users: userSrv, Activity: activitySrv, TokenCache: tokenCache, Lockout: lockoutSrv, Verifier: service.DisabledVerification{}, TwoFactor: service.DisabledTwoFactor{}, Sessions: u.Sessions, Emails: u.Emails}, u.Session, v)
 
 	h.Login(c)
 
 	assert.Equal(u.T(), want, c.V)
+	lockoutSrv.AssertNotCalled(u.T(), "Fail", mock.Anything, mock.Anything)
+}
+
+func (u *AuthHandlerTest) TestLoginLockedOut() {
+	want := &dto.ResponseErr{
+		StatusCode: http.StatusTooManyRequests,
+		Message:    "Too many attempts, try again later",
+		Data:       nil,
+	}
+
+	srv := new(ServiceMock)
+	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	tokenCache := new(TokenCacheMock)
+	lockoutSrv := new(LockoutServiceMock)
+	c := &ContextMock{
+		User:           u.User,
+		RegisterDto:    u.RegisterDto,
+		LoginDto:       u.LoginDto,
+		ChangePassword: u.ChangePassword,
+		ClientIP:       "10.0.0.1",
+	}
+
+	c.On("Bind", &dto.Login{}).Return(nil)
+	lockoutSrv.On("Check", constant.LockoutLoginEmail, strings.ToLower(u.LoginDto.Email)).Return(time.Duration(0))
+	lockoutSrv.On("Check", constant.LockoutLoginIP, "10.0.0.1").Return(90*time.Second + 500*time.Millisecond)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewAuthHandler(srv, handler.AuthDeps{Users: userSrv, Activity: activitySrv, TokenCache: tokenCache, Lockout: lockoutSrv, Verifier: service.DisabledVerification{}, TwoFactor: service.DisabledTwoFactor{}, Sessions: u.Sessions, Emails: u.Emails}, u.Session, v)
+
+	h.Login(c)
+
+	assert.Equal(u.T(), want, c.V)
+	assert.Equal(u.T(), "91", c.ResponseHeader["Retry-After"])
+	srv.AssertNotCalled(u.T(), "Login", mock.Anything)
 }
 
 func (u *AuthHandlerTest) TestLogoutSuccess() {
 	srv := new(ServiceMock)
 	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	tokenCache := new(TokenCacheMock)
+	lockoutSrv := new(LockoutServiceMock)
 	c := &ContextMock{
 		User:           u.User,
 		RegisterDto:    u.RegisterDto,
@@ -300,16 +494,18 @@ func (u *AuthHandlerTest) TestLogoutSuccess() {
 		ChangePassword: u.ChangePassword,
 	}
 
-	c.On("UserID").Return(int(u.User.Id))
+	c.On("Principal").Return(&dto.Principal{UserID: int32(u.User.Id)})
 	srv.On("Logout", u.User.Id).Return(true, nil)
+	tokenCache.On("EvictUser", u.User.Id)
 
 	v, _ := validator.NewValidator()
 
-	h := handler.NewAuthHandler(srv, userSrv, v)
+	h := handler.NewAuthHandler(srv, handler.AuthDeps{Users: userSrv, Activity: activitySrv, TokenCache: tokenCache, Lockout: lockoutSrv, Verifier: service.DisabledVerification{}, TwoFactor: service.DisabledTwoFactor{}, Sessions: u.Sessions, Emails: u.Emails}, u.Session, v)
 
 	h.Logout(c)
 
 	assert.True(u.T(), c.V.(bool))
+	tokenCache.AssertCalled(u.T(), "EvictUser", u.User.Id)
 }
 
 func (u *AuthHandlerTest) TestLogoutBadRequest() {
@@ -317,6 +513,9 @@ func (u *AuthHandlerTest) TestLogoutBadRequest() {
 
 	srv := new(ServiceMock)
 	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	tokenCache := new(TokenCacheMock)
+	lockoutSrv := new(LockoutServiceMock)
 	c := &ContextMock{
 		User:           u.User,
 		RegisterDto:    u.RegisterDto,
@@ -324,23 +523,52 @@ func (u *AuthHandlerTest) TestLogoutBadRequest() {
 		ChangePassword: u.ChangePassword,
 	}
 
-	c.On("UserID").Return(int(u.User.Id))
+	c.On("Principal").Return(&dto.Principal{UserID: int32(u.User.Id)})
 	srv.On("Logout", u.User.Id).Return(nil, u.BadRequestErr)
 
 	v, _ := validator.NewValidator()
 
-	h := handler.NewAuthHandler(srv, userSrv, v)
+	h := handler.NewAuthHandler(srv, handler.AuthDeps{Users: userSrv, Activity: activitySrv, TokenCache: tokenCache, Lockout: lockoutSrv, Verifier: service.DisabledVerification{}, TwoFactor: service.DisabledTwoFactor{}, Sessions: u.Sessions, Emails: u.Emails}, u.Session, v)
 
 	h.Logout(c)
 
 	assert.Equal(u.T(), want, c.V)
 }
 
+func (u *AuthHandlerTest) TestLogoutApiKeyClient() {
+	want := &dto.ResponseErr{
+		StatusCode: http.StatusUnauthorized,
+		Message:    "Invalid token",
+	}
+
+	srv := new(ServiceMock)
+	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	tokenCache := new(TokenCacheMock)
+	lockoutSrv := new(LockoutServiceMock)
+	c := &ContextMock{}
+
+	c.On("Principal").Return(&dto.Principal{UserID: -1, ApiKeyID: "billing"})
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewAuthHandler(srv, handler.AuthDeps{Users: userSrv, Activity: activitySrv, TokenCache: tokenCache, Lockout: lockoutSrv, Verifier: service.DisabledVerification{}, TwoFactor: service.DisabledTwoFactor{}, Sessions: u.Sessions, Emails: u.Emails}, u.Session, v)
+
+	h.Logout(c)
+
+	assert.Equal(u.T(), want, c.V)
+	srv.AssertNotCalled(u.T(), "Logout", mock.Anything)
+	tokenCache.AssertNotCalled(u.T(), "EvictUser", mock.Anything)
+}
+
 func (u *AuthHandlerTest) TestLogoutGrpcErr() {
 	want := u.ServiceDownErr
 
 	srv := new(ServiceMock)
 	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	tokenCache := new(TokenCacheMock)
+	lockoutSrv := new(LockoutServiceMock)
 	c := &ContextMock{
 		User:           u.User,
 		RegisterDto:    u.RegisterDto,
@@ -348,12 +576,12 @@ func (u *AuthHandlerTest) TestLogoutGrpcErr() {
 		ChangePassword: u.ChangePassword,
 	}
 
-	c.On("UserID").Return(int(u.User.Id))
+	c.On("Principal").Return(&dto.Principal{UserID: int32(u.User.Id)})
 	srv.On("Logout", u.User.Id).Return(nil, u.ServiceDownErr)
 
 	v, _ := validator.NewValidator()
 
-	h := handler.NewAuthHandler(srv, userSrv, v)
+	h := handler.NewAuthHandler(srv, handler.AuthDeps{Users: userSrv, Activity: activitySrv, TokenCache: tokenCache, Lockout: lockoutSrv, Verifier: service.DisabledVerification{}, TwoFactor: service.DisabledTwoFactor{}, Sessions: u.Sessions, Emails: u.Emails}, u.Session, v)
 
 	h.Logout(c)
 
@@ -363,6 +591,9 @@ func (u *AuthHandlerTest) TestLogoutGrpcErr() {
 func (u *AuthHandlerTest) TestChangePasswordSuccess() {
 	srv := new(ServiceMock)
 	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	tokenCache := new(TokenCacheMock)
+	lockoutSrv := new(LockoutServiceMock)
 	c := &ContextMock{
 		User:           u.User,
 		RegisterDto:    u.RegisterDto,
@@ -370,17 +601,22 @@ func (u *AuthHandlerTest) TestChangePasswordSuccess() {
 		ChangePassword: u.ChangePassword,
 	}
 
-	c.On("UserID").Return(int(u.User.Id))
+	c.On("Principal").Return(&dto.Principal{UserID: int32(u.User.Id)})
 	c.On("Bind", &dto.ChangePassword{}).Return(nil)
+	userSrv.On("FindOne", int32(u.User.Id)).Return(u.User, nil)
 	srv.On("ChangePassword", u.ChangePassword).Return(true, nil)
+	tokenCache.On("EvictUser", u.User.Id)
+	activitySrv.On("Record", proto.LogType_CHANGE_PASSWORD, u.User.Id, "Change password").Return()
 
 	v, _ := validator.NewValidator()
 
-	h := handler.NewAuthHandler(srv, userSrv, v)
+	h := handler.NewAuthHandler(srv, handler.AuthDeps{Users: userSrv, Activity: activitySrv, TokenCache: tokenCache, Lockout: lockoutSrv, Verifier: service.DisabledVerification{}, TwoFactor: service.DisabledTwoFactor{}, Sessions: u.Sessions, Emails: u.Emails}, u.Session, v)
 
 	h.ChangePassword(c)
 
 	assert.True(u.T(), c.V.(bool))
+	tokenCache.AssertCalled(u.T(), "EvictUser", u.User.Id)
+	activitySrv.AssertCalled(u.T(), "Record", proto.LogType_CHANGE_PASSWORD, u.User.Id, "Change password")
 }
 
 func (u *AuthHandlerTest) TestChangePasswordInvalidUserID() {
@@ -388,6 +624,9 @@ func (u *AuthHandlerTest) TestChangePasswordInvalidUserID() {
 
 	srv := new(ServiceMock)
 	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	tokenCache := new(TokenCacheMock)
+	lockoutSrv := new(LockoutServiceMock)
 	c := &ContextMock{
 		User:           u.User,
 		RegisterDto:    u.RegisterDto,
@@ -395,24 +634,90 @@ func (u *AuthHandlerTest) TestChangePasswordInvalidUserID() {
 		ChangePassword: u.ChangePassword,
 	}
 
-	c.On("UserID").Return(int(u.User.Id))
+	c.On("Principal").Return(&dto.Principal{UserID: int32(u.User.Id)})
 	c.On("Bind", &dto.ChangePassword{}).Return(nil)
 	srv.On("Logout", u.User.Id).Return(nil, u.BadRequestErr)
 
 	v, _ := validator.NewValidator()
 
-	h := handler.NewAuthHandler(srv, userSrv, v)
+	h := handler.NewAuthHandler(srv, handler.AuthDeps{Users: userSrv, Activity: activitySrv, TokenCache: tokenCache, Lockout: lockoutSrv, Verifier: service.DisabledVerification{}, TwoFactor: service.DisabledTwoFactor{}, Sessions: u.Sessions, Emails: u.Emails}, u.Session, v)
 
 	h.Logout(c)
 
 	assert.Equal(u.T(), want, c.V)
 }
 
+func (u *AuthHandlerTest) TestChangePasswordTakesUserFromPrincipal() {
+	srv := new(ServiceMock)
+	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	tokenCache := new(TokenCacheMock)
+	lockoutSrv := new(LockoutServiceMock)
+	c := &ContextMock{
+		User:           u.User,
+		RegisterDto:    u.RegisterDto,
+		LoginDto:       u.LoginDto,
+		ChangePassword: &dto.ChangePassword{UserId: 99, OldPassword: u.ChangePassword.OldPassword, NewPassword: u.ChangePassword.NewPassword},
+	}
+
+	want := &dto.ChangePassword{UserId: u.User.Id, OldPassword: u.ChangePassword.OldPassword, NewPassword: u.ChangePassword.NewPassword}
+
+	c.On("Principal").Return(&dto.Principal{UserID: int32(u.User.Id)})
+	c.On("Bind", &dto.ChangePassword{}).Return(nil)
+	userSrv.On("FindOne", int32(u.User.Id)).Return(u.User, nil)
+	srv.On("ChangePassword", want).Return(true, nil)
+	tokenCache.On("EvictUser", u.User.Id)
+	activitySrv.On("Record", proto.LogType_CHANGE_PASSWORD, u.User.Id, "Change password").Return()
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewAuthHandler(srv, handler.AuthDeps{Users: userSrv, Activity: activitySrv, TokenCache: tokenCache, Lockout: lockoutSrv, Verifier: service.DisabledVerification{}, TwoFactor: service.DisabledTwoFactor{}, Sessions: u.Sessions, Emails: u.Emails}, u.Session, v)
+
+	h.ChangePassword(c)
+
+	srv.AssertCalled(u.T(), "ChangePassword", want)
+	tokenCache.AssertNotCalled(u.T(), "EvictUser", uint32(99))
+}
+
+func (u *AuthHandlerTest) TestChangePasswordWithoutUser() {
+	want := &dto.ResponseErr{
+		StatusCode: http.StatusUnauthorized,
+		Message:    "Invalid token",
+		Data:       nil,
+	}
+
+	srv := new(ServiceMock)
+	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	tokenCache := new(TokenCacheMock)
+	lockoutSrv := new(LockoutServiceMock)
+	c := &ContextMock{
+		User:           u.User,
+		RegisterDto:    u.RegisterDto,
+		LoginDto:       u.LoginDto,
+		ChangePassword: u.ChangePassword,
+	}
+
+	c.On("Principal").Return(&dto.Principal{UserID: -1, ApiKeyID: "billing"})
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewAuthHandler(srv, handler.AuthDeps{Users: userSrv, Activity: activitySrv, TokenCache: tokenCache, Lockout: lockoutSrv, Verifier: service.DisabledVerification{}, TwoFactor: service.DisabledTwoFactor{}, Sessions: u.Sessions, Emails: u.Emails}, u.Session, v)
+
+	h.ChangePassword(c)
+
+	assert.Equal(u.T(), want, c.V)
+	srv.AssertNotCalled(u.T(), "ChangePassword", mock.Anything)
+}
+
 func (u *AuthHandlerTest) TestChangePasswordGrpcErr() {
 	want := u.ServiceDownErr
 
 	srv := new(ServiceMock)
 	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	tokenCache := new(TokenCacheMock)
+	lockoutSrv := new(LockoutServiceMock)
 	c := &ContextMock{
 		User:           u.User,
 		RegisterDto:    u.RegisterDto,
@@ -420,13 +725,14 @@ func (u *AuthHandlerTest) TestChangePasswordGrpcErr() {
 		ChangePassword: u.ChangePassword,
 	}
 
-	c.On("UserID").Return(int(u.User.Id))
+	c.On("Principal").Return(&dto.Principal{UserID: int32(u.User.Id)})
 	c.On("Bind", &dto.ChangePassword{}).Return(nil)
+	userSrv.On("FindOne", int32(u.User.Id)).Return(u.User, nil)
 	srv.On("ChangePassword", u.ChangePassword).Return(nil, u.ServiceDownErr)
 
 	v, _ := validator.NewValidator()
 
-	h := handler.NewAuthHandler(srv, userSrv, v)
+	h := handler.NewAuthHandler(srv, handler.AuthDeps{Users: userSrv, Activity: activitySrv, TokenCache: tokenCache, Lockout: lockoutSrv, Verifier: service.DisabledVerification{}, TwoFactor: service.DisabledTwoFactor{}, Sessions: u.Sessions, Emails: u.Emails}, u.Session, v)
 
 	h.ChangePassword(c)
 
@@ -438,6 +744,9 @@ func (u *AuthHandlerTest) TestValidateSuccess() {
 
 	srv := new(ServiceMock)
 	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	tokenCache := new(TokenCacheMock)
+	lockoutSrv := new(LockoutServiceMock)
 	c := &ContextMock{
 		User:           u.User,
 		RegisterDto:    u.RegisterDto,
@@ -445,12 +754,12 @@ func (u *AuthHandlerTest) TestValidateSuccess() {
 		ChangePassword: u.ChangePassword,
 	}
 
-	c.On("UserID").Return(int(u.User.Id))
+	c.On("Principal").Return(&dto.Principal{UserID: int32(u.User.Id)})
 	userSrv.On("FindOne", int32(u.User.Id)).Return(u.User, nil)
 
 	v, _ := validator.NewValidator()
 
-	h := handler.NewAuthHandler(srv, userSrv, v)
+	h := handler.NewAuthHandler(srv, handler.AuthDeps{Users: userSrv, Activity: activitySrv, TokenCache: tokenCache, Lockout: lockoutSrv, Verifier: service.DisabledVerification{}, TwoFactor: service.DisabledTwoFactor{}, Sessions: u.Sessions, Emails: u.Emails}, u.Session, v)
 
 	h.Validate(c)
 
@@ -462,6 +771,9 @@ func (u *AuthHandlerTest) TestValidateFail() {
 
 	srv := new(ServiceMock)
 	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	tokenCache := new(TokenCacheMock)
+	lockoutSrv := new(LockoutServiceMock)
 	c := &ContextMock{
 		User:           u.User,
 		RegisterDto:    u.RegisterDto,
@@ -469,12 +781,12 @@ func (u *AuthHandlerTest) TestValidateFail() {
 		ChangePassword: u.ChangePassword,
 	}
 
-	c.On("UserID").Return(int(u.User.Id))
+	c.On("Principal").Return(&dto.Principal{UserID: int32(u.User.Id)})
 	userSrv.On("FindOne", int32(u.User.Id)).Return(nil, u.UnauthorizedErr)
 
 	v, _ := validator.NewValidator()
 
-	h := handler.NewAuthHandler(srv, userSrv, v)
+	h := handler.NewAuthHandler(srv, handler.AuthDeps{Users: userSrv, Activity: activitySrv, TokenCache: tokenCache, Lockout: lockoutSrv, Verifier: service.DisabledVerification{}, TwoFactor: service.DisabledTwoFactor{}, Sessions: u.Sessions, Emails: u.Emails}, u.Session, v)
 
 	h.Validate(c)
 
@@ -486,6 +798,9 @@ func (u *AuthHandlerTest) TestValidateGrpcErr() {
 
 	srv := new(ServiceMock)
 	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	tokenCache := new(TokenCacheMock)
+	lockoutSrv := new(LockoutServiceMock)
 	c := &ContextMock{
 		User:           u.User,
 		RegisterDto:    u.RegisterDto,
@@ -493,12 +808,12 @@ func (u *AuthHandlerTest) TestValidateGrpcErr() {
 		ChangePassword: u.ChangePassword,
 	}
 
-	c.On("UserID").Return(int(u.User.Id))
+	c.On("Principal").Return(&dto.Principal{UserID: int32(u.User.Id)})
 	userSrv.On("FindOne", int32(u.User.Id)).Return(nil, u.ServiceDownErr)
 
 	v, _ := validator.NewValidator()
 
-	h := handler.NewAuthHandler(srv, userSrv, v)
+	h := handler.NewAuthHandler(srv, handler.AuthDeps{Users: userSrv, Activity: activitySrv, TokenCache: tokenCache, Lockout: lockoutSrv, Verifier: service.DisabledVerification{}, TwoFactor: service.DisabledTwoFactor{}, Sessions: u.Sessions, Emails: u.Emails}, u.Session, v)
 
 	h.Validate(c)
 
@@ -510,6 +825,9 @@ func (u *AuthHandlerTest) TestRefreshTokenSuccess() {
 
 	srv := new(ServiceMock)
 	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	tokenCache := new(TokenCacheMock)
+	lockoutSrv := new(LockoutServiceMock)
 	c := &ContextMock{
 		User:           u.User,
 		RegisterDto:    u.RegisterDto,
@@ -518,12 +836,16 @@ func (u *AuthHandlerTest) TestRefreshTokenSuccess() {
 		RefreshToken:   u.RefreshToken,
 	}
 
+	u.recordRefreshSession()
 	c.On("Bind", &dto.RedeemNewToken{}).Return(nil)
 	srv.On("RefreshToken", u.RefreshToken.RefreshToken).Return(u.Credential, nil)
+	lockoutSrv.On("Check", mock.Anything, mock.Anything).Return(time.Duration(0))
+	lockoutSrv.On("Fail", mock.Anything, mock.Anything).Return()
+	lockoutSrv.On("Reset", mock.Anything, mock.Anything).Return()
 
 	v, _ := validator.NewValidator()
 
-	h := handler.NewAuthHandler(srv, userSrv, v)
+	h := handler.NewAuthHandler(srv, handler.AuthDeps{Users: userSrv, Activity: activitySrv, TokenCache: tokenCache, Lockout: lockoutSrv, Verifier: service.DisabledVerification{}, TwoFactor: service.DisabledTwoFactor{}, Sessions: u.Sessions, Emails: u.Emails}, u.Session, v)
 
 	h.RefreshToken(c)
 
@@ -535,6 +857,9 @@ func (u *AuthHandlerTest) TestRefreshTokenUnauthorized() {
 
 	srv := new(ServiceMock)
 	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	tokenCache := new(TokenCacheMock)
+	lockoutSrv := new(LockoutServiceMock)
 	c := &ContextMock{
 		User:           u.User,
 		RegisterDto:    u.RegisterDto,
@@ -543,16 +868,21 @@ func (u *AuthHandlerTest) TestRefreshTokenUnauthorized() {
 		RefreshToken:   u.RefreshToken,
 	}
 
+	u.recordRefreshSession()
 	c.On("Bind", &dto.RedeemNewToken{}).Return(nil)
 	srv.On("RefreshToken", u.RefreshToken.RefreshToken).Return(nil, u.UnauthorizedErr)
+	lockoutSrv.On("Check", mock.Anything, mock.Anything).Return(time.Duration(0))
+	lockoutSrv.On("Fail", mock.Anything, mock.Anything).Return()
+	lockoutSrv.On("Reset", mock.Anything, mock.Anything).Return()
 
 	v, _ := validator.NewValidator()
 
-	h := handler.NewAuthHandler(srv, userSrv, v)
+	h := handler.NewAuthHandler(srv, handler.AuthDeps{Users: userSrv, Activity: activitySrv, TokenCache: tokenCache, Lockout: lockoutSrv, Verifier: service.DisabledVerification{}, TwoFactor: service.DisabledTwoFactor{}, Sessions: u.Sessions, Emails: u.Emails}, u.Session, v)
 
 	h.RefreshToken(c)
 
 	assert.Equal(u.T(), want, c.V)
+	lockoutSrv.AssertCalled(u.T(), "Fail", constant.LockoutRefreshIP, c.ClientIP)
 }
 
 func (u *AuthHandlerTest) TestRefreshTokenGrpcErr() {
@@ -560,6 +890,9 @@ func (u *AuthHandlerTest) TestRefreshTokenGrpcErr() {
 
 	srv := new(ServiceMock)
 	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	tokenCache := new(TokenCacheMock)
+	lockoutSrv := new(LockoutServiceMock)
 	c := &ContextMock{
 		User:           u.User,
 		RegisterDto:    u.RegisterDto,
@@ -568,14 +901,686 @@ func (u *AuthHandlerTest) TestRefreshTokenGrpcErr() {
 		RefreshToken:   u.RefreshToken,
 	}
 
+	u.recordRefreshSession()
 	c.On("Bind", &dto.RedeemNewToken{}).Return(nil)
 	srv.On("RefreshToken", u.RefreshToken.RefreshToken).Return(nil, u.ServiceDownErr)
+	lockoutSrv.On("Check", mock.Anything, mock.Anything).Return(time.Duration(0))
+	lockoutSrv.On("Fail", mock.Anything, mock.Anything).Return()
+	lockoutSrv.On("Reset", mock.Anything, mock.Anything).Return()
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewAuthHandler(srv, handler.AuthDeps{Users: userSrv, Activity: activitySrv, TokenCache: tokenCache, Lockout: lockoutSrv, Verifier: service.DisabledVerification{}, TwoFactor: service.DisabledTwoFactor{}, Sessions: u.Sessions, Emails: u.Emails}, u.Session, v)
+
+	h.RefreshToken(c)
+
+	assert.Equal(u.T(), want, c.V)
+}
+
+func (u *AuthHandlerTest) TestRefreshTokenWithoutSession() {
+	want := &dto.ResponseErr{
+		StatusCode: http.StatusUnauthorized,
+		Message:    "Session not found",
+		Data:       nil,
+	}
+
+	srv := new(ServiceMock)
+	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	tokenCache := new(TokenCacheMock)
+	lockoutSrv := new(LockoutServiceMock)
+	c := &ContextMock{
+		User:         u.User,
+		RefreshToken: u.RefreshToken,
+	}
+
+	c.On("Bind", &dto.RedeemNewToken{}).Return(nil)
+	lockoutSrv.On("Check", mock.Anything, mock.Anything).Return(time.Duration(0))
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewAuthHandler(srv, handler.AuthDeps{Users: userSrv, Activity: activitySrv, TokenCache: tokenCache, Lockout: lockoutSrv, Verifier: service.DisabledVerification{}, TwoFactor: service.DisabledTwoFactor{}, Sessions: u.Sessions, Emails: u.Emails}, u.Session, v)
+
+	h.RefreshToken(c)
+
+	assert.Equal(u.T(), want, c.V)
+	srv.AssertNotCalled(u.T(), "RefreshToken", mock.Anything)
+}
+
+func (u *AuthHandlerTest) TestRefreshTokenLockedOut() {
+	want := &dto.ResponseErr{
+		StatusCode: http.StatusTooManyRequests,
+		Message:    "Too many attempts, try again later",
+		Data:       nil,
+	}
+
+	srv := new(ServiceMock)
+	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	tokenCache := new(TokenCacheMock)
+	lockoutSrv := new(LockoutServiceMock)
+	c := &ContextMock{
+		User:           u.User,
+		RegisterDto:    u.RegisterDto,
+		LoginDto:       u.LoginDto,
+		ChangePassword: u.ChangePassword,
+		RefreshToken:   u.RefreshToken,
+		ClientIP:       "10.0.0.1",
+	}
+
+	c.On("Bind", &dto.RedeemNewToken{}).Return(nil)
+	lockoutSrv.On("Check", constant.LockoutRefreshIP, "10.0.0.1").Return(time.Minute)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewAuthHandler(srv, handler.AuthDeps{Users: userSrv, Activity: activitySrv, TokenCache: tokenCache, Lockout: lockoutSrv, Verifier: service.DisabledVerification{}, TwoFactor: service.DisabledTwoFactor{}, Sessions: u.Sessions, Emails: u.Emails}, u.Session, v)
+
+	h.RefreshToken(c)
+
+	assert.Equal(u.T(), want, c.V)
+	assert.Equal(u.T(), "60", c.ResponseHeader["Retry-After"])
+	srv.AssertNotCalled(u.T(), "RefreshToken", mock.Anything)
+}
+
+func (u *AuthHandlerTest) TestTokenCacheStats() {
+	want := &dto.TokenCacheStats{
+		Hits:     10,
+		Misses:   2,
+		Size:     2,
+		Capacity: 100,
+	}
+
+	srv := new(ServiceMock)
+	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	tokenCache := new(TokenCacheMock)
+	lockoutSrv := new(LockoutServiceMock)
+	c := &ContextMock{
+		User: u.User,
+	}
+
+	tokenCache.On("Stats").Return(want)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewAuthHandler(srv, handler.AuthDeps{Users: userSrv, Activity: activitySrv, TokenCache: tokenCache, Lockout: lockoutSrv, Verifier: service.DisabledVerification{}, TwoFactor: service.DisabledTwoFactor{}, Sessions: u.Sessions, Emails: u.Emails}, u.Session, v)
+
+	h.TokenCacheStats(c)
+
+	assert.Equal(u.T(), want, c.V)
+}
+
+func (u *AuthHandlerTest) TestLoginCookieSession() {
+	srv := new(ServiceMock)
+	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	tokenCache := new(TokenCacheMock)
+	lockoutSrv := new(LockoutServiceMock)
+	c := &ContextMock{
+		User:     u.User,
+		LoginDto: u.LoginDto,
+	}
+
+	srv.On("Login", c.LoginDto).Return(u.Credential, nil)
+	srv.On("Validate", u.Credential.AccessToken).Return(int(u.User.Id), nil)
+	activitySrv.On("Record", proto.LogType_LOGIN, u.User.Id, "Login").Return()
+	c.On("Bind", &dto.Login{}).Return(nil)
+	lockoutSrv.On("Check", mock.Anything, mock.Anything).Return(time.Duration(0))
+	lockoutSrv.On("Fail", mock.Anything, mock.Anything).Return()
+	lockoutSrv.On("Reset", mock.Anything, mock.Anything).Return()
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewAuthHandler(srv, handler.AuthDeps{Users: userSrv, Activity: activitySrv, TokenCache: tokenCache, Lockout: lockoutSrv, Verifier: service.DisabledVerification{}, TwoFactor: service.DisabledTwoFactor{}, Sessions: u.Sessions, Emails: u.Emails}, u.CookieSession, v)
+
+	h.Login(c)
+
+	session, ok := c.V.(*dto.Session)
+	assert.True(u.T(), ok)
+	assert.Equal(u.T(), u.Credential.ExpiresIn, session.ExpiresIn)
+	assert.NotEmpty(u.T(), session.CsrfToken)
+
+	access := c.Cookies["access_token"]
+	assert.Equal(u.T(), u.Credential.AccessToken, access.Value)
+	assert.True(u.T(), access.HttpOnly)
+	assert.True(u.T(), access.Secure)
+	assert.Equal(u.T(), "Strict", access.SameSite)
+	assert.Equal(u.T(), int(u.Credential.ExpiresIn), access.MaxAge)
+
+	refresh := c.Cookies["refresh_token"]
+	assert.Equal(u.T(), u.Credential.RefreshToken, refresh.Value)
+	assert.Equal(u.T(), "/auth/token", refresh.Path)
+	assert.True(u.T(), refresh.HttpOnly)
+
+	csrf := c.Cookies["csrf_token"]
+	assert.Equal(u.T(), session.CsrfToken, csrf.Value)
+	assert.False(u.T(), csrf.HttpOnly)
+}
+
+func (u *AuthHandlerTest) TestRefreshTokenFromCookie() {
+	srv := new(ServiceMock)
+	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	tokenCache := new(TokenCacheMock)
+	lockoutSrv := new(LockoutServiceMock)
+	c := &ContextMock{
+		User:         u.User,
+		RefreshToken: &dto.RedeemNewToken{},
+	}
+
+	u.recordRefreshSession()
+	c.On("Bind", &dto.RedeemNewToken{}).Return(errors.New("Unprocessable Entity"))
+	c.On("GetCookie", "refresh_token").Return(u.RefreshToken.RefreshToken)
+	c.On("GetCookie", "csrf_token").Return("csrf")
+	c.On("RequestHeader", "X-CSRF-Token").Return("csrf")
+	srv.On("RefreshToken", u.RefreshToken.RefreshToken).Return(u.Credential, nil)
+	lockoutSrv.On("Check", mock.Anything, mock.Anything).Return(time.Duration(0))
+	lockoutSrv.On("Fail", mock.Anything, mock.Anything).Return()
+	lockoutSrv.On("Reset", mock.Anything, mock.Anything).Return()
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewAuthHandler(srv, handler.AuthDeps{Users: userSrv, Activity: activitySrv, TokenCache: tokenCache, Lockout: lockoutSrv, Verifier: service.DisabledVerification{}, TwoFactor: service.DisabledTwoFactor{}, Sessions: u.Sessions, Emails: u.Emails}, u.CookieSession, v)
+
+	h.RefreshToken(c)
+
+	_, ok := c.V.(*dto.Session)
+	assert.True(u.T(), ok)
+	assert.Equal(u.T(), u.Credential.AccessToken, c.Cookies["access_token"].Value)
+	assert.Equal(u.T(), u.Credential.RefreshToken, c.Cookies["refresh_token"].Value)
+}
+
+func (u *AuthHandlerTest) TestRefreshTokenCookieInvalidCsrf() {
+	want := &dto.ResponseErr{
+		StatusCode: http.StatusForbidden,
+		Message:    "Invalid CSRF token",
+	}
+
+	srv := new(ServiceMock)
+	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	tokenCache := new(TokenCacheMock)
+	lockoutSrv := new(LockoutServiceMock)
+	c := &ContextMock{
+		User:         u.User,
+		RefreshToken: &dto.RedeemNewToken{},
+	}
+
+	c.On("Bind", &dto.RedeemNewToken{}).Return(errors.New("Unprocessable Entity"))
+	c.On("GetCookie", "refresh_token").Return(u.RefreshToken.RefreshToken)
+	c.On("GetCookie", "csrf_token").Return("csrf")
+	c.On("RequestHeader", "X-CSRF-Token").Return("")
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewAuthHandler(srv, handler.AuthDeps{Users: userSrv, Activity: activitySrv, TokenCache: tokenCache, Lockout: lockoutSrv, Verifier: service.DisabledVerification{}, TwoFactor: service.DisabledTwoFactor{}, Sessions: u.Sessions, Emails: u.Emails}, u.CookieSession, v)
+
+	h.RefreshToken(c)
+
+	assert.Equal(u.T(), want, c.V)
+	srv.AssertNumberOfCalls(u.T(), "RefreshToken", 0)
+}
+
+func (u *AuthHandlerTest) TestRefreshTokenCookieNotFound() {
+	srv := new(ServiceMock)
+	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	tokenCache := new(TokenCacheMock)
+	lockoutSrv := new(LockoutServiceMock)
+	c := &ContextMock{
+		User:         u.User,
+		RefreshToken: &dto.RedeemNewToken{},
+	}
+
+	c.On("Bind", &dto.RedeemNewToken{}).Return(errors.New("Unprocessable Entity"))
+	c.On("GetCookie", "refresh_token").Return("")
+	lockoutSrv.On("Check", mock.Anything, mock.Anything).Return(time.Duration(0))
+	lockoutSrv.On("Fail", mock.Anything, mock.Anything).Return()
+	lockoutSrv.On("Reset", mock.Anything, mock.Anything).Return()
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewAuthHandler(srv, handler.AuthDeps{Users: userSrv, Activity: activitySrv, TokenCache: tokenCache, Lockout: lockoutSrv, Verifier: service.DisabledVerification{}, TwoFactor: service.DisabledTwoFactor{}, Sessions: u.Sessions, Emails: u.Emails}, u.CookieSession, v)
+
+	h.RefreshToken(c)
+
+	assert.Equal(u.T(), http.StatusBadRequest, c.V.(*dto.ResponseErr).StatusCode)
+	srv.AssertNumberOfCalls(u.T(), "RefreshToken", 0)
+}
+
+func (u *AuthHandlerTest) TestLogoutClearCookieSession() {
+	srv := new(ServiceMock)
+	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	tokenCache := new(TokenCacheMock)
+	lockoutSrv := new(LockoutServiceMock)
+	c := &ContextMock{
+		User: u.User,
+	}
+
+	c.On("Principal").Return(&dto.Principal{UserID: int32(u.User.Id)})
+	srv.On("Logout", u.User.Id).Return(true, nil)
+	tokenCache.On("EvictUser", u.User.Id)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewAuthHandler(srv, handler.AuthDeps{Users: userSrv, Activity: activitySrv, TokenCache: tokenCache, Lockout: lockoutSrv, Verifier: service.DisabledVerification{}, TwoFactor: service.DisabledTwoFactor{}, Sessions: u.Sessions, Emails: u.Emails}, u.CookieSession, v)
+
+	h.Logout(c)
+
+	assert.True(u.T(), c.V.(bool))
+	for _, name := range []string{"access_token", "refresh_token", "csrf_token"} {
+		assert.Equal(u.T(), "", c.Cookies[name].Value)
+		assert.Equal(u.T(), -1, c.Cookies[name].MaxAge)
+	}
+}
+
+func (u *AuthHandlerTest) TestVerifyEmailSuccess() {
+	srv := new(ServiceMock)
+	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	tokenCache := new(TokenCacheMock)
+	lockoutSrv := new(LockoutServiceMock)
+	verifier := new(VerifierMock)
+	c := &ContextMock{VerifyEmail: &dto.VerifyEmail{Token: "token"}}
+
+	verifier.On("Verify", "token").Return(nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewAuthHandler(srv, handler.AuthDeps{Users: userSrv, Activity: activitySrv, TokenCache: tokenCache, Lockout: lockoutSrv, Verifier: verifier, TwoFactor: service.DisabledTwoFactor{}, Sessions: u.Sessions, Emails: u.Emails}, u.Session, v)
+	h.VerifyEmail(c)
+
+	assert.Nil(u.T(), c.V)
+	verifier.AssertCalled(u.T(), "Verify", "token")
+}
+
+func (u *AuthHandlerTest) TestVerifyEmailInvalidToken() {
+	want := &dto.ResponseErr{
+		StatusCode: http.StatusBadRequest,
+		Message:    "Invalid or expired verification token",
+		Data:       nil,
+	}
+
+	srv := new(ServiceMock)
+	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	tokenCache := new(TokenCacheMock)
+	lockoutSrv := new(LockoutServiceMock)
+	verifier := new(VerifierMock)
+	c := &ContextMock{VerifyEmail: &dto.VerifyEmail{Token: "token"}}
+
+	verifier.On("Verify", "token").Return(want)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewAuthHandler(srv, handler.AuthDeps{Users: userSrv, Activity: activitySrv, TokenCache: tokenCache, Lockout: lockoutSrv, Verifier: verifier, TwoFactor: service.DisabledTwoFactor{}, Sessions: u.Sessions, Emails: u.Emails}, u.Session, v)
+	h.VerifyEmail(c)
+
+	assert.Equal(u.T(), want, c.V)
+}
+
+func (u *AuthHandlerTest) TestVerifyEmailMissingToken() {
+	srv := new(ServiceMock)
+	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	tokenCache := new(TokenCacheMock)
+	lockoutSrv := new(LockoutServiceMock)
+	verifier := new(VerifierMock)
+	c := &ContextMock{VerifyEmail: &dto.VerifyEmail{}}
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewAuthHandler(srv, handler.AuthDeps{Users: userSrv, Activity: activitySrv, TokenCache: tokenCache, Lockout: lockoutSrv, Verifier: verifier, TwoFactor: service.DisabledTwoFactor{}, Sessions: u.Sessions, Emails: u.Emails}, u.Session, v)
+	h.VerifyEmail(c)
+
+	errRes, ok := c.V.(*dto.ResponseErr)
+	assert.True(u.T(), ok)
+	assert.Equal(u.T(), http.StatusBadRequest, errRes.StatusCode)
+	verifier.AssertNotCalled(u.T(), "Verify", mock.Anything)
+}
+
+func (u *AuthHandlerTest) TestResendVerificationSuccess() {
+	srv := new(ServiceMock)
+	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	tokenCache := new(TokenCacheMock)
+	lockoutSrv := new(LockoutServiceMock)
+	verifier := new(VerifierMock)
+	c := &ContextMock{User: u.User}
+
+	c.On("Principal").Return(&dto.Principal{UserID: 1})
+	lockoutSrv.On("Check", constant.LockoutResendVerification, "1").Return(time.Duration(0))
+	lockoutSrv.On("Fail", constant.LockoutResendVerification, "1").Return()
+	verifier.On("Resend", uint32(1)).Return(nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewAuthHandler(srv, handler.AuthDeps{Users: userSrv, Activity: activitySrv, TokenCache: tokenCache, Lockout: lockoutSrv, Verifier: verifier, TwoFactor: service.DisabledTwoFactor{}, Sessions: u.Sessions, Emails: u.Emails}, u.Session, v)
+	h.ResendVerification(c)
+
+	assert.Nil(u.T(), c.V)
+	lockoutSrv.AssertCalled(u.T(), "Fail", constant.LockoutResendVerification, "1")
+}
+
+func (u *AuthHandlerTest) TestResendVerificationLimited() {
+	want := &dto.ResponseErr{
+		StatusCode: http.StatusTooManyRequests,
+		Message:    "Too many attempts, try again later",
+		Data:       nil,
+	}
+
+	srv := new(ServiceMock)
+	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	tokenCache := new(TokenCacheMock)
+	lockoutSrv := new(LockoutServiceMock)
+	verifier := new(VerifierMock)
+	c := &ContextMock{User: u.User}
+
+	c.On("Principal").Return(&dto.Principal{UserID: 1})
+	lockoutSrv.On("Check", constant.LockoutResendVerification, "1").Return(30 * time.Minute)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewAuthHandler(srv, handler.AuthDeps{Users: userSrv, Activity: activitySrv, TokenCache: tokenCache, Lockout: lockoutSrv, Verifier: verifier, TwoFactor: service.DisabledTwoFactor{}, Sessions: u.Sessions, Emails: u.Emails}, u.Session, v)
+	h.ResendVerification(c)
+
+	assert.Equal(u.T(), want, c.V)
+	assert.Equal(u.T(), "1800", c.ResponseHeader["Retry-After"])
+	verifier.AssertNotCalled(u.T(), "Resend", mock.Anything)
+}
+
+func (u *AuthHandlerTest) TestResendVerificationAlreadyVerified() {
+	want := &dto.ResponseErr{
+		StatusCode: http.StatusConflict,
+		Message:    "Email is already verified",
+		Data:       nil,
+	}
+
+	srv := new(ServiceMock)
+	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	tokenCache := new(TokenCacheMock)
+	lockoutSrv := new(LockoutServiceMock)
+	verifier := new(VerifierMock)
+	c := &ContextMock{User: u.User}
+
+	c.On("Principal").Return(&dto.Principal{UserID: 1})
+	lockoutSrv.On("Check", constant.LockoutResendVerification, "1").Return(time.Duration(0))
+	verifier.On("Resend", uint32(1)).Return(want)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewAuthHandler(srv, handler.AuthDeps{Users: userSrv, Activity: activitySrv, TokenCache: tokenCache, Lockout: lockoutSrv, Verifier: verifier, TwoFactor: service.DisabledTwoFactor{}, Sessions: u.Sessions, Emails: u.Emails}, u.Session, v)
+	h.ResendVerification(c)
+
+	assert.Equal(u.T(), want, c.V)
+	lockoutSrv.AssertNotCalled(u.T(), "Fail", mock.Anything, mock.Anything)
+}
+
+func (u *AuthHandlerTest) TestRefreshTokenRevokedSession() {
+	want := &dto.ResponseErr{
+		StatusCode: http.StatusUnauthorized,
+		Message:    "Session is revoked",
+		Data:       nil,
+	}
+
+	srv := new(ServiceMock)
+	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	tokenCache := new(TokenCacheMock)
+	lockoutSrv := new(LockoutServiceMock)
+	sessionSrv := new(SessionServiceMock)
+	c := &ContextMock{RefreshToken: u.RefreshToken}
+
+	c.On("Bind", &dto.RedeemNewToken{}).Return(nil)
+	lockoutSrv.On("Check", mock.Anything, mock.Anything).Return(time.Duration(0))
+	sessionSrv.On("CheckRefresh", u.RefreshToken.RefreshToken).Return(want)
 
 	v, _ := validator.NewValidator()
 
-	h := handler.NewAuthHandler(srv, userSrv, v)
+	h := handler.NewAuthHandler(srv, handler.AuthDeps{Users: userSrv, Activity: activitySrv, TokenCache: tokenCache, Lockout: lockoutSrv, Verifier: service.DisabledVerification{}, TwoFactor: service.DisabledTwoFactor{}, Sessions: sessionSrv, Emails: u.Emails}, u.Session, v)
 
 	h.RefreshToken(c)
 
 	assert.Equal(u.T(), want, c.V)
+	srv.AssertNotCalled(u.T(), "RefreshToken", mock.Anything)
+}
+
+func (u *AuthHandlerTest) TestLoginRecordSession() {
+	srv := new(ServiceMock)
+	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	tokenCache := new(TokenCacheMock)
+	lockoutSrv := new(LockoutServiceMock)
+	sessionSrv := new(SessionServiceMock)
+	c := &ContextMock{LoginDto: u.LoginDto, UserAgent: "Mozilla/5.0", ClientIP: "10.0.0.1"}
+
+	srv.On("Login", c.LoginDto).Return(u.Credential, nil)
+	srv.On("Validate", u.Credential.AccessToken).Return(int(u.User.Id), nil)
+	activitySrv.On("Record", proto.LogType_LOGIN, u.User.Id, "Login").Return()
+	c.On("Bind", &dto.Login{}).Return(nil)
+	lockoutSrv.On("Check", mock.Anything, mock.Anything).Return(time.Duration(0))
+	lockoutSrv.On("Reset", mock.Anything, mock.Anything).Return()
+	sessionSrv.On("Record", u.User.Id, u.Credential, &dto.Device{UserAgent: "Mozilla/5.0", IP: "10.0.0.1"}).Return()
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewAuthHandler(srv, handler.AuthDeps{Users: userSrv, Activity: activitySrv, TokenCache: tokenCache, Lockout: lockoutSrv, Verifier: service.DisabledVerification{}, TwoFactor: service.DisabledTwoFactor{}, Sessions: sessionSrv, Emails: u.Emails}, u.Session, v)
+
+	h.Login(c)
+
+	assert.Equal(u.T(), u.Credential, c.V)
+	sessionSrv.AssertCalled(u.T(), "Record", u.User.Id, u.Credential, &dto.Device{UserAgent: "Mozilla/5.0", IP: "10.0.0.1"})
+}
+
+func (u *AuthHandlerTest) TestLogoutRevokeCurrentSession() {
+	srv := new(ServiceMock)
+	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	tokenCache := new(TokenCacheMock)
+	lockoutSrv := new(LockoutServiceMock)
+	sessionSrv := new(SessionServiceMock)
+	c := new(ContextMock)
+
+	c.On("Principal").Return(&dto.Principal{UserID: 1, SessionID: "session"})
+	srv.On("Logout", uint32(1)).Return(true, nil)
+	tokenCache.On("EvictUser", uint32(1)).Return()
+	sessionSrv.On("Revoke", uint32(1), "session").Return(nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewAuthHandler(srv, handler.AuthDeps{Users: userSrv, Activity: activitySrv, TokenCache: tokenCache, Lockout: lockoutSrv, Verifier: service.DisabledVerification{}, TwoFactor: service.DisabledTwoFactor{}, Sessions: sessionSrv, Emails: u.Emails}, u.Session, v)
+
+	h.Logout(c)
+
+	assert.Equal(u.T(), true, c.V)
+	sessionSrv.AssertCalled(u.T(), "Revoke", uint32(1), "session")
+}
+
+func (u *AuthHandlerTest) TestLogoutAllSuccess() {
+	srv := new(ServiceMock)
+	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	tokenCache := new(TokenCacheMock)
+	lockoutSrv := new(LockoutServiceMock)
+	sessionSrv := new(SessionServiceMock)
+	c := new(ContextMock)
+
+	c.On("Principal").Return(&dto.Principal{UserID: 1})
+	srv.On("Logout", uint32(1)).Return(true, nil)
+	tokenCache.On("EvictUser", uint32(1)).Return()
+	sessionSrv.On("RevokeAll", uint32(1)).Return(nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewAuthHandler(srv, handler.AuthDeps{Users: userSrv, Activity: activitySrv, TokenCache: tokenCache, Lockout: lockoutSrv, Verifier: service.DisabledVerification{}, TwoFactor: service.DisabledTwoFactor{}, Sessions: sessionSrv, Emails: u.Emails}, u.Session, v)
+
+	h.LogoutAll(c)
+
+	assert.Nil(u.T(), c.V)
+	sessionSrv.AssertCalled(u.T(), "RevokeAll", uint32(1))
+	tokenCache.AssertCalled(u.T(), "EvictUser", uint32(1))
+}
+
+func (u *AuthHandlerTest) TestLogoutAllGrpcErr() {
+	srv := new(ServiceMock)
+	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	tokenCache := new(TokenCacheMock)
+	lockoutSrv := new(LockoutServiceMock)
+	sessionSrv := new(SessionServiceMock)
+	c := new(ContextMock)
+
+	c.On("Principal").Return(&dto.Principal{UserID: 1})
+	srv.On("Logout", uint32(1)).Return(false, u.ServiceDownErr)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewAuthHandler(srv, handler.AuthDeps{Users: userSrv, Activity: activitySrv, TokenCache: tokenCache, Lockout: lockoutSrv, Verifier: service.DisabledVerification{}, TwoFactor: service.DisabledTwoFactor{}, Sessions: sessionSrv, Emails: u.Emails}, u.Session, v)
+
+	h.LogoutAll(c)
+
+	assert.Equal(u.T(), u.ServiceDownErr, c.V)
+	sessionSrv.AssertNotCalled(u.T(), "RevokeAll", mock.Anything)
+}
+
+func (u *AuthHandlerTest) TestListSessions() {
+	want := []*dto.DeviceSession{{ID: "session", UserAgent: "Mozilla/5.0", IP: "10.0.0.1", Current: true}}
+
+	srv := new(ServiceMock)
+	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	tokenCache := new(TokenCacheMock)
+	lockoutSrv := new(LockoutServiceMock)
+	sessionSrv := new(SessionServiceMock)
+	c := new(ContextMock)
+
+	c.On("Principal").Return(&dto.Principal{UserID: 1, SessionID: "session"})
+	sessionSrv.On("List", uint32(1), "session").Return(want, nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewAuthHandler(srv, handler.AuthDeps{Users: userSrv, Activity: activitySrv, TokenCache: tokenCache, Lockout: lockoutSrv, Verifier: service.DisabledVerification{}, TwoFactor: service.DisabledTwoFactor{}, Sessions: sessionSrv, Emails: u.Emails}, u.Session, v)
+
+	h.ListSessions(c)
+
+	assert.Equal(u.T(), want, c.V)
+}
+
+func (u *AuthHandlerTest) TestRevokeSessionNotFound() {
+	want := &dto.ResponseErr{
+		StatusCode: http.StatusNotFound,
+		Message:    "Session not found",
+		Data:       nil,
+	}
+
+	srv := new(ServiceMock)
+	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	tokenCache := new(TokenCacheMock)
+	lockoutSrv := new(LockoutServiceMock)
+	sessionSrv := new(SessionServiceMock)
+	c := &ContextMock{SessionId: "other"}
+
+	c.On("Principal").Return(&dto.Principal{UserID: 1})
+	sessionSrv.On("Revoke", uint32(1), "other").Return(want)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewAuthHandler(srv, handler.AuthDeps{Users: userSrv, Activity: activitySrv, TokenCache: tokenCache, Lockout: lockoutSrv, Verifier: service.DisabledVerification{}, TwoFactor: service.DisabledTwoFactor{}, Sessions: sessionSrv, Emails: u.Emails}, u.Session, v)
+
+	h.RevokeSession(c)
+
+	assert.Equal(u.T(), want, c.V)
+}
+
+func (u *AuthHandlerTest) TestRegisterPasswordContainsName() {
+	srv := new(ServiceMock)
+	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	tokenCache := new(TokenCacheMock)
+	lockoutSrv := new(LockoutServiceMock)
+	c := &ContextMock{
+		RegisterDto: &dto.Register{
+			Email:       "smithy@samithiwat.dev",
+			Password:    "Smithy2022!",
+			Firstname:   "Samithiwat",
+			Lastname:    "Boonchai",
+			DisplayName: "Smithy",
+		},
+	}
+
+	c.On("Bind", &dto.Register{}).Return(nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewAuthHandler(srv, handler.AuthDeps{Users: userSrv, Activity: activitySrv, TokenCache: tokenCache, Lockout: lockoutSrv, Verifier: service.DisabledVerification{}, TwoFactor: service.DisabledTwoFactor{}, Sessions: u.Sessions, Emails: u.Emails}, u.Session, v)
+
+	h.Register(c)
+
+	errRes := c.V.(*dto.ResponseErr)
+	assert.Equal(u.T(), http.StatusBadRequest, errRes.StatusCode)
+	assert.Equal(u.T(), "password_personal", errRes.Data.([]*dto.BadReqErrResponse)[0].Tag)
+	assert.Equal(u.T(), "Password", errRes.Data.([]*dto.BadReqErrResponse)[0].FailedField)
+	srv.AssertNotCalled(u.T(), "Register", mock.Anything)
+}
+
+func (u *AuthHandlerTest) TestChangePasswordContainsName() {
+	srv := new(ServiceMock)
+	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	tokenCache := new(TokenCacheMock)
+	lockoutSrv := new(LockoutServiceMock)
+	c := &ContextMock{
+		ChangePassword: &dto.ChangePassword{OldPassword: u.ChangePassword.OldPassword, NewPassword: "x" + u.User.DisplayName + "2022!"},
+	}
+
+	c.On("Principal").Return(&dto.Principal{UserID: int32(u.User.Id)})
+	c.On("Bind", &dto.ChangePassword{}).Return(nil)
+	userSrv.On("FindOne", int32(u.User.Id)).Return(u.User, nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewAuthHandler(srv, handler.AuthDeps{Users: userSrv, Activity: activitySrv, TokenCache: tokenCache, Lockout: lockoutSrv, Verifier: service.DisabledVerification{}, TwoFactor: service.DisabledTwoFactor{}, Sessions: u.Sessions, Emails: u.Emails}, u.Session, v)
+
+	h.ChangePassword(c)
+
+	errRes := c.V.(*dto.ResponseErr)
+	assert.Equal(u.T(), http.StatusBadRequest, errRes.StatusCode)
+	assert.Equal(u.T(), "password_personal", errRes.Data.([]*dto.BadReqErrResponse)[0].Tag)
+	srv.AssertNotCalled(u.T(), "ChangePassword", mock.Anything)
+}
+
+func (u *AuthHandlerTest) TestChangePasswordContainsEmail() {
+	srv := new(ServiceMock)
+	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	tokenCache := new(TokenCacheMock)
+	lockoutSrv := new(LockoutServiceMock)
+	c := &ContextMock{
+		ChangePassword: &dto.ChangePassword{OldPassword: u.ChangePassword.OldPassword, NewPassword: "Xsmithykerr2022!"},
+	}
+
+	c.On("Principal").Return(&dto.Principal{UserID: int32(u.User.Id)})
+	c.On("Bind", &dto.ChangePassword{}).Return(nil)
+	userSrv.On("FindOne", int32(u.User.Id)).Return(&proto.User{Id: u.User.Id, Firstname: "Samithiwat", Lastname: "Boonchai", DisplayName: "Smithy"}, nil)
+	u.Emails.Remember(context.Background(), u.User.Id, "smithykerr@samithiwat.dev")
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewAuthHandler(srv, handler.AuthDeps{Users: userSrv, Activity: activitySrv, TokenCache: tokenCache, Lockout: lockoutSrv, Verifier: service.DisabledVerification{}, TwoFactor: service.DisabledTwoFactor{}, Sessions: u.Sessions, Emails: u.Emails}, u.Session, v)
+
+	h.ChangePassword(c)
+
+	errRes := c.V.(*dto.ResponseErr)
+	assert.Equal(u.T(), http.StatusBadRequest, errRes.StatusCode)
+	assert.Equal(u.T(), "password_personal", errRes.Data.([]*dto.BadReqErrResponse)[0].Tag)
+	srv.AssertNotCalled(u.T(), "ChangePassword", mock.Anything)
+}
+
+// recordRefreshSession starts the session of the refresh token of the test, a refresh token without a session is rejected
+func (u *AuthHandlerTest) recordRefreshSession() {
+	u.Sessions.Record(context.Background(), 1, &proto.Credential{AccessToken: "session", RefreshToken: u.RefreshToken.RefreshToken}, &dto.Device{})
 }
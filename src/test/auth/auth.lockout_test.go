@@ -0,0 +1,124 @@
+package auth
+
+import (
+	"context"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type LockoutServiceTest struct {
+	suite.Suite
+	Store  *service.MemoryLockoutStore
+	Policy service.LockoutPolicy
+}
+
+func TestLockoutService(t *testing.T) {
+	suite.Run(t, new(LockoutServiceTest))
+}
+
+func (s *LockoutServiceTest) SetupTest() {
+	s.Store = service.NewMemoryLockoutStore()
+	s.Policy = service.LockoutPolicy{
+		Window:      time.Minute,
+		MaxFailures: 3,
+		BaseLockout: time.Minute,
+		MaxLockout:  3 * time.Minute,
+	}
+}
+
+func (s *LockoutServiceTest) newService() *service.LockoutService {
+	return service.NewLockoutService(s.Store, map[string]service.LockoutPolicy{"login:email": s.Policy})
+}
+
+// unlock moves the lock and the failures of the key into the past as if the time has passed
+func (s *LockoutServiceTest) unlock(key string, ago time.Duration) {
+	state, _ := s.Store.Get(key)
+	state.LockedUntil = time.Now().Add(-ago)
+	for i := range state.Failures {
+		state.Failures[i] = state.Failures[i].Add(-ago)
+	}
+	state.ExpiresAt = time.Now().Add(time.Hour)
+
+	_ = s.Store.Set(key, state)
+}
+
+func (s *LockoutServiceTest) TestLockAfterMaxFailures() {
+	srv := s.newService()
+
+	srv.Fail(context.Background(), "login:email", "a@b.c")
+	srv.Fail(context.Background(), "login:email", "a@b.c")
+
+	assert.Equal(s.T(), time.Duration(0), srv.Check(context.Background(), "login:email", "a@b.c"))
+
+	srv.Fail(context.Background(), "login:email", "a@b.c")
+
+	wait := srv.Check(context.Background(), "login:email", "a@b.c")
+	assert.True(s.T(), wait > 59*time.Second && wait <= time.Minute)
+	assert.Equal(s.T(), time.Duration(0), srv.Check(context.Background(), "login:email", "d@e.f"))
+}
+
+func (s *LockoutServiceTest) TestFailuresSlideOutOfWindow() {
+	srv := s.newService()
+
+	srv.Fail(context.Background(), "login:email", "a@b.c")
+	srv.Fail(context.Background(), "login:email", "a@b.c")
+	s.unlock("login:email:a@b.c", 2*time.Minute)
+	srv.Fail(context.Background(), "login:email", "a@b.c")
+
+	assert.Equal(s.T(), time.Duration(0), srv.Check(context.Background(), "login:email", "a@b.c"))
+}
+
+func (s *LockoutServiceTest) TestExponentialBackoff() {
+	srv := s.newService()
+
+	for _, want := range []time.Duration{time.Minute, 2 * time.Minute, 3 * time.Minute} {
+		for i := 0; i < s.Policy.MaxFailures; i++ {
+			srv.Fail(context.Background(), "login:email", "a@b.c")
+		}
+
+		wait := srv.Check(context.Background(), "login:email", "a@b.c")
+		assert.True(s.T(), wait > want-time.Second && wait <= want, "want %v got %v", want, wait)
+
+		s.unlock("login:email:a@b.c", time.Second)
+	}
+}
+
+func (s *LockoutServiceTest) TestBackoffForgottenAfterQuietWindow() {
+	srv := s.newService()
+
+	for i := 0; i < s.Policy.MaxFailures; i++ {
+		srv.Fail(context.Background(), "login:email", "a@b.c")
+	}
+	s.unlock("login:email:a@b.c", 2*time.Minute)
+
+	for i := 0; i < s.Policy.MaxFailures; i++ {
+		srv.Fail(context.Background(), "login:email", "a@b.c")
+	}
+
+	wait := srv.Check(context.Background(), "login:email", "a@b.c")
+	assert.True(s.T(), wait > 59*time.Second && wait <= time.Minute)
+}
+
+func (s *LockoutServiceTest) TestReset() {
+	srv := s.newService()
+
+	for i := 0; i < s.Policy.MaxFailures; i++ {
+		srv.Fail(context.Background(), "login:email", "a@b.c")
+	}
+	srv.Reset(context.Background(), "login:email", "a@b.c")
+
+	assert.Equal(s.T(), time.Duration(0), srv.Check(context.Background(), "login:email", "a@b.c"))
+}
+
+func (s *LockoutServiceTest) TestUnknownScope() {
+	srv := s.newService()
+
+	for i := 0; i < 10; i++ {
+		srv.Fail(context.Background(), "refresh:ip", "10.0.0.1")
+	}
+
+	assert.Equal(s.T(), time.Duration(0), srv.Check(context.Background(), "refresh:ip", "10.0.0.1"))
+}
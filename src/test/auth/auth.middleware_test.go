@@ -2,9 +2,14 @@ package auth
 
 import (
 	"github.com/bxcodec/faker/v3"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/config"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/constant"
 	"github.com/samithiwat/samithiwat-backend-gateway/src/dto"
 	"github.com/samithiwat/samithiwat-backend-gateway/src/middleware"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/proto"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/test/permission"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 	"math/rand"
 	"net/http"
@@ -14,10 +19,14 @@ import (
 
 type AuthGuardTest struct {
 	suite.Suite
-	ExcludePath     map[string]struct{}
+	Overrides       map[string]middleware.Policy
+	Session         config.Session
+	CookieSession   config.Session
 	UserId          int32
 	Token           string
+	Roles           []*proto.Role
 	UnauthorizedErr *dto.ResponseErr
+	ForbiddenErr    *dto.ResponseErr
 	ServiceDownErr  *dto.ResponseErr
 }
 
@@ -38,12 +47,35 @@ func (u *AuthGuardTest) SetupTest() {
 		Data:       nil,
 	}
 
+	u.ForbiddenErr = &dto.ResponseErr{
+		StatusCode: http.StatusForbidden,
+		Message:    "Insufficient permission",
+		Data:       "user:delete",
+	}
+
 	u.Token = faker.Word()
-	u.UserId = int32(rand.Intn(100))
+	u.UserId = int32(rand.Intn(100)) + 1
+
+	u.Roles = []*proto.Role{
+		{
+			Id:          1,
+			Name:        faker.Word(),
+			Permissions: []*proto.Permission{{Id: 1, Code: "user:update"}},
+		},
+	}
+
+	u.Session = config.Session{Mode: "header"}
+
+	u.CookieSession = config.Session{
+		Mode:         "cookie",
+		AccessCookie: "access_token",
+		CsrfCookie:   "csrf_token",
+		CsrfHeader:   "X-CSRF-Token",
+	}
 
-	u.ExcludePath = map[string]struct{}{
-		"POST /exclude":     {},
-		"POST /exclude/:id": {},
+	u.Overrides = map[string]middleware.Policy{
+		middleware.PolicyKey("GET", "/override/:id/member/:userId"): middleware.Public(),
+		middleware.PolicyKey("DELETE", "/override/:id"):             middleware.RequirePermission("user:delete"),
 	}
 }
 
@@ -51,17 +83,18 @@ func (u *AuthGuardTest) TestValidateSuccess() {
 	want := u.UserId
 
 	srv := new(ServiceMock)
+	roleSrv := new(permission.RoleServiceMock)
 	c := new(ContextMock)
 
 	c.On("Method").Return("POST")
-	c.On("Path").Return("/auth")
-	c.On("Token").Return(u.Token)
+	c.On("RoutePath").Return("/auth/me")
+	c.On("Token").Return("Bearer " + u.Token)
 	srv.On("Validate", u.Token).Return(int(u.UserId), nil)
 	c.On("StoreValue", "UserId", strconv.Itoa(int(u.UserId)))
 	c.On("Next")
 
-	h := middleware.NewAuthGuard(srv, u.ExcludePath)
-	h.Validate(c)
+	h := middleware.NewAuthGuard(srv, middleware.NewPermissionGuard(roleSrv), u.Overrides, u.Session, middleware.AuthGuardOptions{})
+	h.Validate(middleware.Authenticated())(c)
 
 	id, err := strconv.Atoi(c.Header["UserId"])
 
@@ -70,51 +103,113 @@ func (u *AuthGuardTest) TestValidateSuccess() {
 	c.AssertNumberOfCalls(u.T(), "Next", 1)
 }
 
-func (u *AuthGuardTest) TestValidateSkippedFromExcludePath() {
+func (u *AuthGuardTest) TestValidateSkippedFromPublicPolicy() {
 	srv := new(ServiceMock)
+	roleSrv := new(permission.RoleServiceMock)
 	c := new(ContextMock)
 
-	c.On("Method").Return("POST")
-	c.On("Path").Return("/exclude")
+	c.On("Method").Return("GET")
+	c.On("RoutePath").Return("/user/:id")
 	c.On("Token").Return("")
 	c.On("Next")
 
-	h := middleware.NewAuthGuard(srv, u.ExcludePath)
-	h.Validate(c)
+	h := middleware.NewAuthGuard(srv, middleware.NewPermissionGuard(roleSrv), u.Overrides, u.Session, middleware.AuthGuardOptions{})
+	h.Validate(middleware.Public())(c)
 
 	c.AssertNumberOfCalls(u.T(), "Next", 1)
 	c.AssertNumberOfCalls(u.T(), "Token", 0)
 }
 
-func (u *AuthGuardTest) TestValidateSkippedFromExcludePathWithID() {
+func (u *AuthGuardTest) TestValidateSkippedFromOverridePolicy() {
 	srv := new(ServiceMock)
+	roleSrv := new(permission.RoleServiceMock)
 	c := new(ContextMock)
 
-	c.On("Method").Return("POST")
-	c.On("Path").Return("/exclude/1")
+	c.On("Method").Return("GET")
+	c.On("RoutePath").Return("/override/:id/member/:userId")
 	c.On("Token").Return("")
 	c.On("Next")
 
-	h := middleware.NewAuthGuard(srv, u.ExcludePath)
-	h.Validate(c)
+	h := middleware.NewAuthGuard(srv, middleware.NewPermissionGuard(roleSrv), u.Overrides, u.Session, middleware.AuthGuardOptions{})
+	h.Validate(middleware.Authenticated())(c)
 
 	c.AssertNumberOfCalls(u.T(), "Next", 1)
 	c.AssertNumberOfCalls(u.T(), "Token", 0)
 }
 
+func (u *AuthGuardTest) TestValidatePermissionSuccess() {
+	srv := new(ServiceMock)
+	roleSrv := new(permission.RoleServiceMock)
+	c := new(ContextMock)
+
+	c.On("Method").Return("PATCH")
+	c.On("RoutePath").Return("/user/:id")
+	c.On("Token").Return("Bearer " + u.Token)
+	srv.On("Validate", u.Token).Return(int(u.UserId), nil)
+	roleSrv.On("FindByUser", u.UserId).Return(u.Roles, nil)
+	c.On("StoreValue", "UserId", strconv.Itoa(int(u.UserId)))
+	c.On("Next")
+
+	h := middleware.NewAuthGuard(srv, middleware.NewPermissionGuard(roleSrv), u.Overrides, u.Session, middleware.AuthGuardOptions{})
+	h.Validate(middleware.RequirePermission("user:update"))(c)
+
+	c.AssertNumberOfCalls(u.T(), "Next", 1)
+}
+
+func (u *AuthGuardTest) TestValidateInsufficientPermissionFromOverridePolicy() {
+	want := u.ForbiddenErr
+
+	srv := new(ServiceMock)
+	roleSrv := new(permission.RoleServiceMock)
+	c := new(ContextMock)
+
+	c.On("Method").Return("DELETE")
+	c.On("RoutePath").Return("/override/:id/")
+	c.On("Token").Return("Bearer " + u.Token)
+	srv.On("Validate", u.Token).Return(int(u.UserId), nil)
+	roleSrv.On("FindByUser", u.UserId).Return(u.Roles, nil)
+	c.On("Next")
+
+	h := middleware.NewAuthGuard(srv, middleware.NewPermissionGuard(roleSrv), u.Overrides, u.Session, middleware.AuthGuardOptions{})
+	h.Validate(middleware.Authenticated())(c)
+
+	assert.Equal(u.T(), want, c.V)
+	c.AssertNumberOfCalls(u.T(), "Next", 0)
+}
+
+func (u *AuthGuardTest) TestValidatePermissionGrpcErr() {
+	want := u.ServiceDownErr
+
+	srv := new(ServiceMock)
+	roleSrv := new(permission.RoleServiceMock)
+	c := new(ContextMock)
+
+	c.On("Method").Return("PATCH")
+	c.On("RoutePath").Return("/user/:id")
+	c.On("Token").Return("Bearer " + u.Token)
+	srv.On("Validate", u.Token).Return(int(u.UserId), nil)
+	roleSrv.On("FindByUser", u.UserId).Return(nil, u.ServiceDownErr)
+
+	h := middleware.NewAuthGuard(srv, middleware.NewPermissionGuard(roleSrv), u.Overrides, u.Session, middleware.AuthGuardOptions{})
+	h.Validate(middleware.RequirePermission("user:update"))(c)
+
+	assert.Equal(u.T(), want, c.V)
+}
+
 func (u *AuthGuardTest) TestValidateFailed() {
 	want := u.UnauthorizedErr
 
 	srv := new(ServiceMock)
+	roleSrv := new(permission.RoleServiceMock)
 	c := new(ContextMock)
 
 	c.On("Method").Return("POST")
-	c.On("Path").Return("/auth")
-	c.On("Token").Return(u.Token)
+	c.On("RoutePath").Return("/auth/me")
+	c.On("Token").Return("Bearer " + u.Token)
 	srv.On("Validate", u.Token).Return(-1, u.UnauthorizedErr)
 
-	h := middleware.NewAuthGuard(srv, u.ExcludePath)
-	h.Validate(c)
+	h := middleware.NewAuthGuard(srv, middleware.NewPermissionGuard(roleSrv), u.Overrides, u.Session, middleware.AuthGuardOptions{})
+	h.Validate(middleware.Authenticated())(c)
 
 	assert.Equal(u.T(), want, c.V)
 }
@@ -123,34 +218,581 @@ func (u *AuthGuardTest) TestValidateTokenNotIncluded() {
 	want := u.UnauthorizedErr
 
 	srv := new(ServiceMock)
+	roleSrv := new(permission.RoleServiceMock)
 	c := new(ContextMock)
 
 	c.On("Method").Return("POST")
-	c.On("Path").Return("/auth")
+	c.On("RoutePath").Return("/auth/me")
 	c.On("Token").Return("")
 	srv.On("Validate")
 
-	h := middleware.NewAuthGuard(srv, u.ExcludePath)
-	h.Validate(c)
+	h := middleware.NewAuthGuard(srv, middleware.NewPermissionGuard(roleSrv), u.Overrides, u.Session, middleware.AuthGuardOptions{})
+	h.Validate(middleware.Authenticated())(c)
 
 	assert.Equal(u.T(), want, c.V)
 	srv.AssertNumberOfCalls(u.T(), "Validate", 0)
-
 }
 
 func (u *AuthGuardTest) TestValidateTokenGrpcErr() {
 	want := u.ServiceDownErr
 
 	srv := new(ServiceMock)
+	roleSrv := new(permission.RoleServiceMock)
 	c := new(ContextMock)
 
 	c.On("Method").Return("POST")
-	c.On("Path").Return("/auth")
-	c.On("Token").Return(u.Token)
+	c.On("RoutePath").Return("/auth/me")
+	c.On("Token").Return("Bearer " + u.Token)
 	srv.On("Validate", u.Token).Return(-1, u.ServiceDownErr)
 
-	h := middleware.NewAuthGuard(srv, u.ExcludePath)
-	h.Validate(c)
+	h := middleware.NewAuthGuard(srv, middleware.NewPermissionGuard(roleSrv), u.Overrides, u.Session, middleware.AuthGuardOptions{})
+	h.Validate(middleware.Authenticated())(c)
 
 	assert.Equal(u.T(), want, c.V)
 }
+
+func (u *AuthGuardTest) TestNewPolicySuccess() {
+	want := middleware.RequirePermission("user:create")
+
+	policy, err := middleware.NewPolicy("permission", "user:create", "")
+
+	assert.Nil(u.T(), err)
+	assert.Equal(u.T(), want, policy)
+}
+
+func (u *AuthGuardTest) TestNewPolicyInvalid() {
+	_, err := middleware.NewPolicy("everyone", "", "")
+	assert.NotNil(u.T(), err)
+
+	_, err = middleware.NewPolicy("permission", "", "")
+	assert.NotNil(u.T(), err)
+
+	_, err = middleware.NewPolicy("public", "user:create", "")
+	assert.NotNil(u.T(), err)
+}
+
+func (u *AuthGuardTest) TestPolicyKey() {
+	assert.Equal(u.T(), "GET /user/:id", middleware.PolicyKey("get", "/user/:id/"))
+	assert.Equal(u.T(), "GET /", middleware.PolicyKey("GET", "/"))
+}
+
+func (u *AuthGuardTest) TestValidateFromCookie() {
+	srv := new(ServiceMock)
+	roleSrv := new(permission.RoleServiceMock)
+	c := new(ContextMock)
+
+	c.On("Method").Return("GET")
+	c.On("RoutePath").Return("/auth/me")
+	c.On("Token").Return("")
+	c.On("GetCookie", "access_token").Return(u.Token)
+	srv.On("Validate", u.Token).Return(int(u.UserId), nil)
+	c.On("StoreValue", "UserId", strconv.Itoa(int(u.UserId)))
+	c.On("Next")
+
+	h := middleware.NewAuthGuard(srv, middleware.NewPermissionGuard(roleSrv), u.Overrides, u.CookieSession, middleware.AuthGuardOptions{})
+	h.Validate(middleware.Authenticated())(c)
+
+	c.AssertNumberOfCalls(u.T(), "Next", 1)
+}
+
+func (u *AuthGuardTest) TestValidateFromCookieWithCsrf() {
+	csrf := faker.Word()
+
+	srv := new(ServiceMock)
+	roleSrv := new(permission.RoleServiceMock)
+	c := new(ContextMock)
+
+	c.On("Method").Return("POST")
+	c.On("RoutePath").Return("/auth/change-password")
+	c.On("Token").Return("")
+	c.On("GetCookie", "access_token").Return(u.Token)
+	c.On("GetCookie", "csrf_token").Return(csrf)
+	c.On("RequestHeader", "X-CSRF-Token").Return(csrf)
+	srv.On("Validate", u.Token).Return(int(u.UserId), nil)
+	c.On("StoreValue", "UserId", strconv.Itoa(int(u.UserId)))
+	c.On("Next")
+
+	h := middleware.NewAuthGuard(srv, middleware.NewPermissionGuard(roleSrv), u.Overrides, u.CookieSession, middleware.AuthGuardOptions{})
+	h.Validate(middleware.Authenticated())(c)
+
+	c.AssertNumberOfCalls(u.T(), "Next", 1)
+}
+
+func (u *AuthGuardTest) TestValidateFromCookieInvalidCsrf() {
+	want := &dto.ResponseErr{
+		StatusCode: http.StatusForbidden,
+		Message:    "Invalid CSRF token",
+	}
+
+	srv := new(ServiceMock)
+	roleSrv := new(permission.RoleServiceMock)
+	c := new(ContextMock)
+
+	c.On("Method").Return("POST")
+	c.On("RoutePath").Return("/auth/change-password")
+	c.On("Token").Return("")
+	c.On("GetCookie", "access_token").Return(u.Token)
+	c.On("GetCookie", "csrf_token").Return(faker.Word())
+	c.On("RequestHeader", "X-CSRF-Token").Return("")
+
+	h := middleware.NewAuthGuard(srv, middleware.NewPermissionGuard(roleSrv), u.Overrides, u.CookieSession, middleware.AuthGuardOptions{})
+	h.Validate(middleware.Authenticated())(c)
+
+	assert.Equal(u.T(), want, c.V)
+	srv.AssertNumberOfCalls(u.T(), "Validate", 0)
+}
+
+func (u *AuthGuardTest) TestValidateHeaderSkipsCsrf() {
+	srv := new(ServiceMock)
+	roleSrv := new(permission.RoleServiceMock)
+	c := new(ContextMock)
+
+	c.On("Method").Return("POST")
+	c.On("RoutePath").Return("/auth/change-password")
+	c.On("Token").Return("Bearer " + u.Token)
+	srv.On("Validate", u.Token).Return(int(u.UserId), nil)
+	c.On("StoreValue", "UserId", strconv.Itoa(int(u.UserId)))
+	c.On("Next")
+
+	h := middleware.NewAuthGuard(srv, middleware.NewPermissionGuard(roleSrv), u.Overrides, u.CookieSession, middleware.AuthGuardOptions{})
+	h.Validate(middleware.Authenticated())(c)
+
+	c.AssertNumberOfCalls(u.T(), "Next", 1)
+	c.AssertNumberOfCalls(u.T(), "RequestHeader", 0)
+}
+
+func (u *AuthGuardTest) TestValidateBareToken() {
+	want := &dto.ResponseErr{
+		StatusCode: http.StatusUnauthorized,
+		Message:    "Invalid authorization header",
+	}
+
+	srv := new(ServiceMock)
+	roleSrv := new(permission.RoleServiceMock)
+	c := new(ContextMock)
+
+	c.On("Method").Return("GET")
+	c.On("RoutePath").Return("/auth/me")
+	c.On("Token").Return(u.Token)
+
+	h := middleware.NewAuthGuard(srv, middleware.NewPermissionGuard(roleSrv), u.Overrides, u.Session, middleware.AuthGuardOptions{})
+	h.Validate(middleware.Authenticated())(c)
+
+	assert.Equal(u.T(), want, c.V)
+	srv.AssertNumberOfCalls(u.T(), "Validate", 0)
+}
+
+func (u *AuthGuardTest) TestValidateUnsupportedScheme() {
+	want := &dto.ResponseErr{
+		StatusCode: http.StatusUnauthorized,
+		Message:    "Unsupported authorization scheme",
+	}
+
+	srv := new(ServiceMock)
+	roleSrv := new(permission.RoleServiceMock)
+	c := new(ContextMock)
+
+	c.On("Method").Return("GET")
+	c.On("RoutePath").Return("/auth/me")
+	c.On("Token").Return("Basic " + u.Token)
+
+	h := middleware.NewAuthGuard(srv, middleware.NewPermissionGuard(roleSrv), u.Overrides, u.Session, middleware.AuthGuardOptions{})
+	h.Validate(middleware.Authenticated())(c)
+
+	assert.Equal(u.T(), want, c.V)
+	srv.AssertNumberOfCalls(u.T(), "Validate", 0)
+}
+
+func (u *AuthGuardTest) TestValidateApiKeySuccess() {
+	srv := new(ServiceMock)
+	roleSrv := new(permission.RoleServiceMock)
+	apiKeys := new(ApiKeyAuthenticatorMock)
+	c := new(ContextMock)
+
+	client := &dto.ApiClient{ID: "billing", Scopes: []string{"user:update"}}
+
+	c.On("Method").Return("PATCH")
+	c.On("RoutePath").Return("/user/:id")
+	c.On("RequestURI").Return("/user/1")
+	c.On("Body").Return([]byte("{}"))
+	c.On("Token").Return("ApiKey billing.secret")
+	c.On("RequestHeader", middleware.SignatureTimestampHeader).Return("")
+	c.On("RequestHeader", middleware.SignatureHeader).Return("")
+	apiKeys.On("Authenticate", "billing.secret", &dto.SignedRequest{Method: "PATCH", URI: "/user/1", Body: []byte("{}")}).Return(client, nil)
+	c.On("StoreValue", "ApiKeyId", "billing")
+	c.On("Next")
+
+	h := middleware.NewAuthGuard(srv, middleware.NewPermissionGuard(roleSrv), u.Overrides, u.Session, middleware.AuthGuardOptions{
+		ApiKeys: apiKeys,
+	})
+	h.Validate(middleware.RequirePermission("user:update"))(c)
+
+	c.AssertNumberOfCalls(u.T(), "Next", 1)
+	c.AssertNotCalled(u.T(), "StoreValue", "UserId", mock.Anything)
+	srv.AssertNumberOfCalls(u.T(), "Validate", 0)
+}
+
+func (u *AuthGuardTest) TestValidateApiKeyInsufficientScope() {
+	srv := new(ServiceMock)
+	roleSrv := new(permission.RoleServiceMock)
+	apiKeys := new(ApiKeyAuthenticatorMock)
+	c := new(ContextMock)
+
+	client := &dto.ApiClient{ID: "billing", Scopes: []string{"user:update"}}
+
+	c.On("Method").Return("DELETE")
+	c.On("RoutePath").Return("/user/:id")
+	c.On("RequestURI").Return("/user/1")
+	c.On("Body").Return([]byte(nil))
+	c.On("Token").Return("ApiKey billing.secret")
+	c.On("RequestHeader", mock.Anything).Return("")
+	apiKeys.On("Authenticate", "billing.secret", mock.Anything).Return(client, nil)
+
+	h := middleware.NewAuthGuard(srv, middleware.NewPermissionGuard(roleSrv), u.Overrides, u.Session, middleware.AuthGuardOptions{
+		ApiKeys: apiKeys,
+	})
+	h.Validate(middleware.RequirePermission("user:delete"))(c)
+
+	assert.Equal(u.T(), u.ForbiddenErr, c.V)
+	c.AssertNumberOfCalls(u.T(), "Next", 0)
+}
+
+func (u *AuthGuardTest) TestValidateApiKeyInvalid() {
+	want := &dto.ResponseErr{
+		StatusCode: http.StatusUnauthorized,
+		Message:    "Invalid API key",
+	}
+
+	srv := new(ServiceMock)
+	roleSrv := new(permission.RoleServiceMock)
+	apiKeys := new(ApiKeyAuthenticatorMock)
+	c := new(ContextMock)
+
+	c.On("Method").Return("GET")
+	c.On("RoutePath").Return("/auth/me")
+	c.On("RequestURI").Return("/auth/me")
+	c.On("Body").Return([]byte(nil))
+	c.On("Token").Return("ApiKey billing.wrong")
+	c.On("RequestHeader", mock.Anything).Return("")
+	apiKeys.On("Authenticate", "billing.wrong", mock.Anything).Return(nil, want)
+
+	h := middleware.NewAuthGuard(srv, middleware.NewPermissionGuard(roleSrv), u.Overrides, u.Session, middleware.AuthGuardOptions{
+		ApiKeys: apiKeys,
+	})
+	h.Validate(middleware.Authenticated())(c)
+
+	assert.Equal(u.T(), want, c.V)
+}
+
+func (u *AuthGuardTest) TestValidateOwnerSuccess() {
+	srv := new(ServiceMock)
+	roleSrv := new(permission.RoleServiceMock)
+	owners := new(OwnerResolverMock)
+	c := new(ContextMock)
+
+	c.On("Method").Return("PATCH")
+	c.On("RoutePath").Return("/team/:id")
+	c.On("Token").Return("Bearer " + u.Token)
+	c.On("ID").Return(5, nil)
+	srv.On("Validate", u.Token).Return(int(u.UserId), nil)
+	owners.On("IsOwner", constant.ResourceTeam, int32(5), u.UserId).Return(true, nil)
+	c.On("StoreValue", "UserId", strconv.Itoa(int(u.UserId)))
+	c.On("Next")
+
+	h := middleware.NewAuthGuard(srv, middleware.NewPermissionGuard(roleSrv), u.Overrides, u.Session, middleware.AuthGuardOptions{
+		Owners: owners,
+	})
+	h.Validate(middleware.RequireOwner(constant.ResourceTeam, "team:update"))(c)
+
+	c.AssertNumberOfCalls(u.T(), "Next", 1)
+	roleSrv.AssertNotCalled(u.T(), "FindByUser", mock.Anything)
+}
+
+func (u *AuthGuardTest) TestValidateNotOwnerWithAdminPermission() {
+	srv := new(ServiceMock)
+	roleSrv := new(permission.RoleServiceMock)
+	owners := new(OwnerResolverMock)
+	c := new(ContextMock)
+
+	c.On("Method").Return("PATCH")
+	c.On("RoutePath").Return("/user/:id")
+	c.On("Token").Return("Bearer " + u.Token)
+	c.On("ID").Return(int(u.UserId)+1, nil)
+	srv.On("Validate", u.Token).Return(int(u.UserId), nil)
+	owners.On("IsOwner", constant.ResourceUser, u.UserId+1, u.UserId).Return(false, nil)
+	roleSrv.On("FindByUser", u.UserId).Return(u.Roles, nil)
+	c.On("StoreValue", "Admin", "true")
+	c.On("StoreValue", "UserId", strconv.Itoa(int(u.UserId)))
+	c.On("Next")
+
+	h := middleware.NewAuthGuard(srv, middleware.NewPermissionGuard(roleSrv), u.Overrides, u.Session, middleware.AuthGuardOptions{
+		Owners: owners,
+	})
+	h.Validate(middleware.RequireOwner(constant.ResourceUser, "user:update"))(c)
+
+	c.AssertNumberOfCalls(u.T(), "Next", 1)
+	c.AssertCalled(u.T(), "StoreValue", "Admin", "true")
+}
+
+func (u *AuthGuardTest) TestValidateNotOwner() {
+	srv := new(ServiceMock)
+	roleSrv := new(permission.RoleServiceMock)
+	owners := new(OwnerResolverMock)
+	c := new(ContextMock)
+
+	c.On("Method").Return("DELETE")
+	c.On("RoutePath").Return("/user/:id")
+	c.On("Token").Return("Bearer " + u.Token)
+	c.On("ID").Return(int(u.UserId)+1, nil)
+	srv.On("Validate", u.Token).Return(int(u.UserId), nil)
+	owners.On("IsOwner", constant.ResourceUser, u.UserId+1, u.UserId).Return(false, nil)
+	roleSrv.On("FindByUser", u.UserId).Return(u.Roles, nil)
+
+	h := middleware.NewAuthGuard(srv, middleware.NewPermissionGuard(roleSrv), u.Overrides, u.Session, middleware.AuthGuardOptions{
+		Owners: owners,
+	})
+	h.Validate(middleware.RequireOwner(constant.ResourceUser, "user:delete"))(c)
+
+	assert.Equal(u.T(), u.ForbiddenErr, c.V)
+	c.AssertNumberOfCalls(u.T(), "Next", 0)
+}
+
+func (u *AuthGuardTest) TestValidateOwnerNotFound() {
+	want := &dto.ResponseErr{
+		StatusCode: http.StatusNotFound,
+		Message:    "Not found team",
+		Data:       nil,
+	}
+
+	srv := new(ServiceMock)
+	roleSrv := new(permission.RoleServiceMock)
+	owners := new(OwnerResolverMock)
+	c := new(ContextMock)
+
+	c.On("Method").Return("PATCH")
+	c.On("RoutePath").Return("/team/:id")
+	c.On("Token").Return("Bearer " + u.Token)
+	c.On("ID").Return(5, nil)
+	srv.On("Validate", u.Token).Return(int(u.UserId), nil)
+	owners.On("IsOwner", constant.ResourceTeam, int32(5), u.UserId).Return(false, want)
+
+	h := middleware.NewAuthGuard(srv, middleware.NewPermissionGuard(roleSrv), u.Overrides, u.Session, middleware.AuthGuardOptions{
+		Owners: owners,
+	})
+	h.Validate(middleware.RequireOwner(constant.ResourceTeam, "team:update"))(c)
+
+	assert.Equal(u.T(), want, c.V)
+	c.AssertNumberOfCalls(u.T(), "Next", 0)
+}
+
+func (u *AuthGuardTest) TestValidateApiKeyOwnerRouteWithScope() {
+	srv := new(ServiceMock)
+	roleSrv := new(permission.RoleServiceMock)
+	apiKeys := new(ApiKeyAuthenticatorMock)
+	owners := new(OwnerResolverMock)
+	c := new(ContextMock)
+
+	client := &dto.ApiClient{ID: "billing", Scopes: []string{"user:update"}}
+
+	c.On("Method").Return("PATCH")
+	c.On("RoutePath").Return("/user/:id")
+	c.On("RequestURI").Return("/user/2")
+	c.On("Body").Return([]byte("{}"))
+	c.On("Token").Return("ApiKey billing.secret")
+	c.On("RequestHeader", mock.Anything).Return("")
+	apiKeys.On("Authenticate", "billing.secret", mock.Anything).Return(client, nil)
+	c.On("StoreValue", mock.Anything, mock.Anything)
+	c.On("Next")
+
+	h := middleware.NewAuthGuard(srv, middleware.NewPermissionGuard(roleSrv), u.Overrides, u.Session, middleware.AuthGuardOptions{
+		ApiKeys: apiKeys,
+		Owners:  owners,
+	})
+	h.Validate(middleware.RequireOwner(constant.ResourceUser, "user:update"))(c)
+
+	c.AssertNumberOfCalls(u.T(), "Next", 1)
+	c.AssertCalled(u.T(), "StoreValue", "Admin", "true")
+	owners.AssertNotCalled(u.T(), "IsOwner", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (u *AuthGuardTest) TestValidateApiKeyOwner() {
+	srv := new(ServiceMock)
+	roleSrv := new(permission.RoleServiceMock)
+	apiKeys := new(ApiKeyAuthenticatorMock)
+	owners := new(OwnerResolverMock)
+	c := new(ContextMock)
+
+	client := &dto.ApiClient{ID: "profile", UserID: u.UserId}
+
+	c.On("Method").Return("PATCH")
+	c.On("RoutePath").Return("/user/:id")
+	c.On("RequestURI").Return("/user/" + strconv.Itoa(int(u.UserId)))
+	c.On("Body").Return([]byte("{}"))
+	c.On("Token").Return("ApiKey profile.secret")
+	c.On("RequestHeader", mock.Anything).Return("")
+	c.On("ID").Return(int(u.UserId), nil)
+	apiKeys.On("Authenticate", "profile.secret", mock.Anything).Return(client, nil)
+	owners.On("IsOwner", constant.ResourceUser, u.UserId, u.UserId).Return(true, nil)
+	c.On("StoreValue", mock.Anything, mock.Anything)
+	c.On("Next")
+
+	h := middleware.NewAuthGuard(srv, middleware.NewPermissionGuard(roleSrv), u.Overrides, u.Session, middleware.AuthGuardOptions{
+		ApiKeys: apiKeys,
+		Owners:  owners,
+	})
+	h.Validate(middleware.RequireOwner(constant.ResourceUser, "user:update"))(c)
+
+	c.AssertNumberOfCalls(u.T(), "Next", 1)
+	c.AssertNotCalled(u.T(), "StoreValue", "Admin", mock.Anything)
+}
+
+func (u *AuthGuardTest) TestNewOwnerPolicy() {
+	want := middleware.RequireOwner(constant.ResourceTeam, "team:update")
+
+	policy, err := middleware.NewPolicy("owner", "team:update", "team")
+	assert.Nil(u.T(), err)
+	assert.Equal(u.T(), want, policy)
+
+	_, err = middleware.NewPolicy("owner", "team:update", "role")
+	assert.NotNil(u.T(), err)
+
+	_, err = middleware.NewPolicy("owner", "", "team")
+	assert.NotNil(u.T(), err)
+
+	_, err = middleware.NewPolicy("authenticated", "", "team")
+	assert.NotNil(u.T(), err)
+}
+
+func (u *AuthGuardTest) TestParseAuthorization() {
+	scheme, credential, ok := middleware.ParseAuthorization("bearer  abc ")
+	assert.True(u.T(), ok)
+	assert.Equal(u.T(), middleware.SchemeBearer, scheme)
+	assert.Equal(u.T(), "abc", credential)
+
+	_, _, ok = middleware.ParseAuthorization("abc")
+	assert.False(u.T(), ok)
+
+	_, _, ok = middleware.ParseAuthorization("Bearer ")
+	assert.False(u.T(), ok)
+}
+
+func (u *AuthGuardTest) TestValidateUnverifiedUser() {
+	want := &dto.ResponseErr{
+		StatusCode: http.StatusForbidden,
+		Message:    "Email is not verified",
+	}
+
+	srv := new(ServiceMock)
+	roleSrv := new(permission.RoleServiceMock)
+	verifier := new(VerifierMock)
+	c := new(ContextMock)
+
+	c.On("Method").Return("GET")
+	c.On("RoutePath").Return("/user/:id")
+	c.On("Token").Return("Bearer " + u.Token)
+	srv.On("Validate", u.Token).Return(int(u.UserId), nil)
+	verifier.On("IsVerified", uint32(u.UserId)).Return(false, nil)
+
+	h := middleware.NewAuthGuard(srv, middleware.NewPermissionGuard(roleSrv), u.Overrides, u.Session, middleware.AuthGuardOptions{
+		Verifier: verifier,
+	})
+	h.Validate(middleware.Authenticated())(c)
+
+	assert.Equal(u.T(), want, c.V)
+	c.AssertNotCalled(u.T(), "Next")
+}
+
+func (u *AuthGuardTest) TestValidateUnverifiedUserAuthRoute() {
+	srv := new(ServiceMock)
+	roleSrv := new(permission.RoleServiceMock)
+	verifier := new(VerifierMock)
+	c := new(ContextMock)
+
+	c.On("Method").Return("POST")
+	c.On("RoutePath").Return("/auth/resend-verification")
+	c.On("Token").Return("Bearer " + u.Token)
+	srv.On("Validate", u.Token).Return(int(u.UserId), nil)
+	c.On("StoreValue", "UserId", strconv.Itoa(int(u.UserId)))
+	c.On("Next")
+
+	h := middleware.NewAuthGuard(srv, middleware.NewPermissionGuard(roleSrv), u.Overrides, u.Session, middleware.AuthGuardOptions{
+		Verifier: verifier,
+	})
+	h.Validate(middleware.Authenticated())(c)
+
+	c.AssertNumberOfCalls(u.T(), "Next", 1)
+	verifier.AssertNotCalled(u.T(), "IsVerified", mock.Anything)
+}
+
+func (u *AuthGuardTest) TestValidateVerifiedUser() {
+	srv := new(ServiceMock)
+	roleSrv := new(permission.RoleServiceMock)
+	verifier := new(VerifierMock)
+	c := new(ContextMock)
+
+	c.On("Method").Return("GET")
+	c.On("RoutePath").Return("/user/:id")
+	c.On("Token").Return("Bearer " + u.Token)
+	srv.On("Validate", u.Token).Return(int(u.UserId), nil)
+	verifier.On("IsVerified", uint32(u.UserId)).Return(true, nil)
+	c.On("StoreValue", "UserId", strconv.Itoa(int(u.UserId)))
+	c.On("Next")
+
+	h := middleware.NewAuthGuard(srv, middleware.NewPermissionGuard(roleSrv), u.Overrides, u.Session, middleware.AuthGuardOptions{
+		Verifier: verifier,
+	})
+	h.Validate(middleware.Authenticated())(c)
+
+	c.AssertNumberOfCalls(u.T(), "Next", 1)
+}
+
+func (u *AuthGuardTest) TestValidateRevokedSession() {
+	want := &dto.ResponseErr{
+		StatusCode: http.StatusUnauthorized,
+		Message:    "Session is revoked",
+		Data:       nil,
+	}
+
+	srv := new(ServiceMock)
+	roleSrv := new(permission.RoleServiceMock)
+	sessions := new(SessionServiceMock)
+	c := new(ContextMock)
+
+	c.On("Method").Return("GET")
+	c.On("RoutePath").Return("/user/:id")
+	c.On("Token").Return("Bearer " + u.Token)
+	srv.On("Validate", u.Token).Return(int(u.UserId), nil)
+	sessions.On("Authenticate", u.Token).Return("", want)
+
+	h := middleware.NewAuthGuard(srv, middleware.NewPermissionGuard(roleSrv), u.Overrides, u.Session, middleware.AuthGuardOptions{
+		Sessions: sessions,
+	})
+	h.Validate(middleware.Authenticated())(c)
+
+	assert.Equal(u.T(), want, c.V)
+	c.AssertNotCalled(u.T(), "Next")
+}
+
+func (u *AuthGuardTest) TestValidateStoreSessionId() {
+	srv := new(ServiceMock)
+	roleSrv := new(permission.RoleServiceMock)
+	sessions := new(SessionServiceMock)
+	c := new(ContextMock)
+
+	c.On("Method").Return("GET")
+	c.On("RoutePath").Return("/auth/sessions")
+	c.On("Token").Return("Bearer " + u.Token)
+	srv.On("Validate", u.Token).Return(int(u.UserId), nil)
+	sessions.On("Authenticate", u.Token).Return("session", nil)
+	c.On("StoreValue", "UserId", strconv.Itoa(int(u.UserId)))
+	c.On("StoreValue", "SessionId", "session")
+	c.On("Next")
+
+	h := middleware.NewAuthGuard(srv, middleware.NewPermissionGuard(roleSrv), u.Overrides, u.Session, middleware.AuthGuardOptions{
+		Sessions: sessions,
+	})
+	h.Validate(middleware.Authenticated())(c)
+
+	assert.Equal(u.T(), "session", c.Header["SessionId"])
+	c.AssertNumberOfCalls(u.T(), "Next", 1)
+}
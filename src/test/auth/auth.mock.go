@@ -6,6 +6,7 @@ import (
 	"github.com/samithiwat/samithiwat-backend-gateway/src/proto"
 	"github.com/stretchr/testify/mock"
 	"google.golang.org/grpc"
+	"time"
 )
 
 type ContextMock struct {
@@ -15,8 +16,14 @@ type ContextMock struct {
 	LoginDto       *dto.Login
 	ChangePassword *dto.ChangePassword
 	RefreshToken   *dto.RedeemNewToken
+	VerifyEmail    *dto.VerifyEmail
 	V              interface{}
 	Header         map[string]string
+	Cookies        map[string]*dto.Cookie
+	ResponseHeader map[string]string
+	ClientIP       string
+	UserAgent      string
+	SessionId      string
 }
 
 func (c *ContextMock) Bind(v interface{}) error {
@@ -35,10 +42,10 @@ func (c *ContextMock) Bind(v interface{}) error {
 	return args.Error(0)
 }
 
-func (c *ContextMock) UserID() int32 {
+func (c *ContextMock) Principal() *dto.Principal {
 	args := c.Called()
 
-	return int32(args.Int(0))
+	return args.Get(0).(*dto.Principal)
 }
 
 func (c *ContextMock) ID() (int32, error) {
@@ -63,13 +70,73 @@ func (c *ContextMock) StoreValue(key string, val string) {
 	c.Header = map[string]string{key: val}
 }
 
+func (c *ContextMock) GetCookie(name string) string {
+	args := c.Called(name)
+
+	return args.String(0)
+}
+
+func (c *ContextMock) SetCookie(cookie *dto.Cookie) {
+	if c.Cookies == nil {
+		c.Cookies = map[string]*dto.Cookie{}
+	}
+
+	c.Cookies[cookie.Name] = cookie
+}
+
+func (c *ContextMock) SetResponseHeader(key string, val string) {
+	if c.ResponseHeader == nil {
+		c.ResponseHeader = map[string]string{}
+	}
+
+	c.ResponseHeader[key] = val
+}
+
+func (c *ContextMock) VerifyEmailQuery(query *dto.VerifyEmail) error {
+	if c.VerifyEmail != nil {
+		*query = *c.VerifyEmail
+	}
+
+	return nil
+}
+
+func (c *ContextMock) IP() string {
+	return c.ClientIP
+}
+
+func (c *ContextMock) Device() *dto.Device {
+	return &dto.Device{UserAgent: c.UserAgent, IP: c.ClientIP}
+}
+
+func (c *ContextMock) SessionParam() string {
+	return c.SessionId
+}
+
+func (c *ContextMock) RequestHeader(key string) string {
+	args := c.Called(key)
+
+	return args.String(0)
+}
+
+func (c *ContextMock) RequestURI() string {
+	args := c.Called()
+
+	return args.String(0)
+}
+
+func (c *ContextMock) Body() []byte {
+	args := c.Called()
+
+	return args.Get(0).([]byte)
+}
+
 func (c *ContextMock) Method() string {
 	args := c.Called()
 
 	return args.String(0)
 }
 
-func (c *ContextMock) Path() string {
+func (c *ContextMock) RoutePath() string {
 	args := c.Called()
 
 	return args.String(0)
@@ -85,7 +152,7 @@ type ServiceMock struct {
 	mock.Mock
 }
 
-func (s *ServiceMock) Register(register *dto.Register) (res *proto.User, err *dto.ResponseErr) {
+func (s *ServiceMock) Register(_ context.Context, register *dto.Register) (res *proto.User, err *dto.ResponseErr) {
 	args := s.Called(register)
 
 	if args.Get(0) != nil {
@@ -99,7 +166,7 @@ func (s *ServiceMock) Register(register *dto.Register) (res *proto.User, err *dt
 	return
 }
 
-func (s *ServiceMock) Login(login *dto.Login) (res *proto.Credential, err *dto.ResponseErr) {
+func (s *ServiceMock) Login(_ context.Context, login *dto.Login) (res *proto.Credential, err *dto.ResponseErr) {
 	args := s.Called(login)
 
 	if args.Get(0) != nil {
@@ -113,7 +180,7 @@ func (s *ServiceMock) Login(login *dto.Login) (res *proto.Credential, err *dto.R
 	return
 }
 
-func (s *ServiceMock) Logout(userId uint32) (res bool, err *dto.ResponseErr) {
+func (s *ServiceMock) Logout(_ context.Context, userId uint32) (res bool, err *dto.ResponseErr) {
 	args := s.Called(userId)
 
 	if args.Get(0) != nil {
@@ -127,7 +194,7 @@ func (s *ServiceMock) Logout(userId uint32) (res bool, err *dto.ResponseErr) {
 	return
 }
 
-func (s *ServiceMock) ChangePassword(chPwd *dto.ChangePassword) (res bool, err *dto.ResponseErr) {
+func (s *ServiceMock) ChangePassword(_ context.Context, chPwd *dto.ChangePassword) (res bool, err *dto.ResponseErr) {
 	args := s.Called(chPwd)
 
 	if args.Get(0) != nil {
@@ -141,7 +208,7 @@ func (s *ServiceMock) ChangePassword(chPwd *dto.ChangePassword) (res bool, err *
 	return
 }
 
-func (s *ServiceMock) Validate(token string) (userId uint32, err *dto.ResponseErr) {
+func (s *ServiceMock) Validate(_ context.Context, token string) (userId uint32, err *dto.ResponseErr) {
 	args := s.Called(token)
 
 	if args.Get(1) != nil {
@@ -151,7 +218,7 @@ func (s *ServiceMock) Validate(token string) (userId uint32, err *dto.ResponseEr
 	return uint32(args.Int(0)), err
 }
 
-func (s *ServiceMock) RefreshToken(token string) (res *proto.Credential, err *dto.ResponseErr) {
+func (s *ServiceMock) RefreshToken(_ context.Context, token string) (res *proto.Credential, err *dto.ResponseErr) {
 	args := s.Called(token)
 
 	if args.Get(0) != nil {
@@ -223,3 +290,179 @@ func (c *ClientMock) RefreshToken(ctx context.Context, in *proto.RefreshTokenReq
 
 	return res, args.Error(1)
 }
+
+type TokenCacheMock struct {
+	mock.Mock
+}
+
+func (c *TokenCacheMock) EvictUser(userId uint32) {
+	_ = c.Called(userId)
+}
+
+func (c *TokenCacheMock) Stats() (res *dto.TokenCacheStats) {
+	args := c.Called()
+
+	if args.Get(0) != nil {
+		res = args.Get(0).(*dto.TokenCacheStats)
+	}
+
+	return
+}
+
+type LockoutServiceMock struct {
+	mock.Mock
+}
+
+func (l *LockoutServiceMock) Check(_ context.Context, scope string, key string) time.Duration {
+	args := l.Called(scope, key)
+
+	return args.Get(0).(time.Duration)
+}
+
+func (l *LockoutServiceMock) Fail(_ context.Context, scope string, key string) {
+	_ = l.Called(scope, key)
+}
+
+func (l *LockoutServiceMock) Reset(_ context.Context, scope string, key string) {
+	_ = l.Called(scope, key)
+}
+
+type SessionServiceMock struct {
+	mock.Mock
+}
+
+func (s *SessionServiceMock) Record(_ context.Context, userId uint32, credential *proto.Credential, device *dto.Device) {
+	_ = s.Called(userId, credential, device)
+}
+
+func (s *SessionServiceMock) CheckRefresh(_ context.Context, refreshToken string) (err *dto.ResponseErr) {
+	args := s.Called(refreshToken)
+
+	if args.Get(0) != nil {
+		err = args.Get(0).(*dto.ResponseErr)
+	}
+
+	return err
+}
+
+func (s *SessionServiceMock) Rotate(_ context.Context, refreshToken string, credential *proto.Credential, device *dto.Device) {
+	_ = s.Called(refreshToken, credential, device)
+}
+
+func (s *SessionServiceMock) List(_ context.Context, userId uint32, current string) (res []*dto.DeviceSession, err *dto.ResponseErr) {
+	args := s.Called(userId, current)
+
+	if args.Get(0) != nil {
+		res = args.Get(0).([]*dto.DeviceSession)
+	}
+
+	if args.Get(1) != nil {
+		err = args.Get(1).(*dto.ResponseErr)
+	}
+
+	return
+}
+
+func (s *SessionServiceMock) Revoke(_ context.Context, userId uint32, id string) *dto.ResponseErr {
+	args := s.Called(userId, id)
+
+	if args.Get(0) != nil {
+		return args.Get(0).(*dto.ResponseErr)
+	}
+
+	return nil
+}
+
+func (s *SessionServiceMock) RevokeAll(_ context.Context, userId uint32) *dto.ResponseErr {
+	args := s.Called(userId)
+
+	if args.Get(0) != nil {
+		return args.Get(0).(*dto.ResponseErr)
+	}
+
+	return nil
+}
+
+func (s *SessionServiceMock) Authenticate(_ context.Context, accessToken string) (string, *dto.ResponseErr) {
+	args := s.Called(accessToken)
+
+	if args.Get(1) != nil {
+		return args.String(0), args.Get(1).(*dto.ResponseErr)
+	}
+
+	return args.String(0), nil
+}
+
+type VerifierMock struct {
+	mock.Mock
+}
+
+func (v *VerifierMock) Send(userId uint32, email string) {
+	_ = v.Called(userId, email)
+}
+
+func (v *VerifierMock) Resend(userId uint32) *dto.ResponseErr {
+	args := v.Called(userId)
+
+	if args.Get(0) != nil {
+		return args.Get(0).(*dto.ResponseErr)
+	}
+
+	return nil
+}
+
+func (v *VerifierMock) Verify(token string) *dto.ResponseErr {
+	args := v.Called(token)
+
+	if args.Get(0) != nil {
+		return args.Get(0).(*dto.ResponseErr)
+	}
+
+	return nil
+}
+
+func (v *VerifierMock) IsVerified(userId uint32) (bool, *dto.ResponseErr) {
+	args := v.Called(userId)
+
+	if args.Get(1) != nil {
+		return args.Bool(0), args.Get(1).(*dto.ResponseErr)
+	}
+
+	return args.Bool(0), nil
+}
+
+type OwnerResolverMock struct {
+	mock.Mock
+}
+
+func (o *OwnerResolverMock) IsOwner(_ context.Context, resource string, id int32, userId int32) (bool, *dto.ResponseErr) {
+	args := o.Called(resource, id, userId)
+
+	if args.Get(1) != nil {
+		return args.Bool(0), args.Get(1).(*dto.ResponseErr)
+	}
+
+	return args.Bool(0), nil
+}
+
+type ApiKeyAuthenticatorMock struct {
+	mock.Mock
+}
+
+func (a *ApiKeyAuthenticatorMock) Authenticate(credential string, req *dto.SignedRequest) (res *dto.ApiClient, err *dto.ResponseErr) {
+	args := a.Called(credential, req)
+
+	if args.Get(0) != nil {
+		res = args.Get(0).(*dto.ApiClient)
+	}
+
+	if args.Get(1) != nil {
+		err = args.Get(1).(*dto.ResponseErr)
+	}
+
+	return
+}
+
+func (c *ContextMock) UserContext() context.Context {
+	return context.Background()
+}
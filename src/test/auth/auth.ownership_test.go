@@ -0,0 +1,96 @@
+package auth
+
+import (
+	"context"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/constant"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/dto"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/proto"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/service"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/test/organization"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/test/team"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"testing"
+)
+
+type OwnershipServiceTest struct {
+	suite.Suite
+	Team         *proto.Team
+	Organization *proto.Organization
+	NotFoundErr  *dto.ResponseErr
+}
+
+func TestOwnershipService(t *testing.T) {
+	suite.Run(t, new(OwnershipServiceTest))
+}
+
+func (s *OwnershipServiceTest) SetupTest() {
+	s.Team = &proto.Team{
+		Id:      1,
+		Members: []*proto.User{{Id: 2}, {Id: 3}},
+	}
+
+	s.Organization = &proto.Organization{
+		Id:      1,
+		Members: []*proto.User{{Id: 4}},
+	}
+
+	s.NotFoundErr = &dto.ResponseErr{
+		StatusCode: http.StatusNotFound,
+		Message:    "Not found team",
+		Data:       nil,
+	}
+}
+
+func (s *OwnershipServiceTest) TestUserOwnsItself() {
+	srv := service.NewOwnershipService(new(team.ServiceMock), new(organization.OrganizationServiceMock))
+
+	owner, err := srv.IsOwner(context.Background(), constant.ResourceUser, 2, 2)
+	assert.Nil(s.T(), err)
+	assert.True(s.T(), owner)
+
+	owner, err = srv.IsOwner(context.Background(), constant.ResourceUser, 3, 2)
+	assert.Nil(s.T(), err)
+	assert.False(s.T(), owner)
+}
+
+func (s *OwnershipServiceTest) TestTeamMemberIsOwner() {
+	teamSrv := new(team.ServiceMock)
+	teamSrv.On("FindOne", int32(1)).Return(s.Team, nil)
+
+	srv := service.NewOwnershipService(teamSrv, new(organization.OrganizationServiceMock))
+
+	owner, err := srv.IsOwner(context.Background(), constant.ResourceTeam, 1, 3)
+	assert.Nil(s.T(), err)
+	assert.True(s.T(), owner)
+
+	owner, err = srv.IsOwner(context.Background(), constant.ResourceTeam, 1, 4)
+	assert.Nil(s.T(), err)
+	assert.False(s.T(), owner)
+}
+
+func (s *OwnershipServiceTest) TestTeamNotFound() {
+	teamSrv := new(team.ServiceMock)
+	teamSrv.On("FindOne", int32(1)).Return(nil, s.NotFoundErr)
+
+	srv := service.NewOwnershipService(teamSrv, new(organization.OrganizationServiceMock))
+
+	_, err := srv.IsOwner(context.Background(), constant.ResourceTeam, 1, 3)
+	assert.Equal(s.T(), s.NotFoundErr, err)
+}
+
+func (s *OwnershipServiceTest) TestOrganizationMemberIsOwner() {
+	orgSrv := new(organization.OrganizationServiceMock)
+	orgSrv.On("FindOne", int32(1)).Return(s.Organization, nil)
+
+	srv := service.NewOwnershipService(new(team.ServiceMock), orgSrv)
+
+	owner, err := srv.IsOwner(context.Background(), constant.ResourceOrganization, 1, 4)
+	assert.Nil(s.T(), err)
+	assert.True(s.T(), owner)
+
+	owner, err = srv.IsOwner(context.Background(), constant.ResourceOrganization, 1, 2)
+	assert.Nil(s.T(), err)
+	assert.False(s.T(), owner)
+}
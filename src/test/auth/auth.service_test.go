@@ -1,6 +1,7 @@
 package auth
 
 import (
+	"context"
 	"github.com/bxcodec/faker/v3"
 	"github.com/pkg/errors"
 	"github.com/samithiwat/samithiwat-backend-gateway/src/dto"
@@ -107,7 +108,7 @@ func (s *AuthServiceTest) TestRegisterSuccess() {
 
 	srv := service.NewAuthService(client)
 
-	user, err := srv.Register(s.RegisterDto)
+	user, err := srv.Register(context.Background(), s.RegisterDto)
 
 	assert.Nil(s.T(), err)
 	assert.Equal(s.T(), want, user)
@@ -137,7 +138,7 @@ func (s *AuthServiceTest) TestRegisterEmailDuplicated() {
 
 	srv := service.NewAuthService(client)
 
-	res, err := srv.Register(s.RegisterDto)
+	res, err := srv.Register(context.Background(), s.RegisterDto)
 
 	assert.Nil(s.T(), res)
 	assert.Equal(s.T(), want, err)
@@ -159,7 +160,7 @@ func (s *AuthServiceTest) TestRegisterGrpcError() {
 
 	srv := service.NewAuthService(client)
 
-	res, err := srv.Register(s.RegisterDto)
+	res, err := srv.Register(context.Background(), s.RegisterDto)
 
 	assert.Nil(s.T(), res)
 	assert.Equal(s.T(), want, err)
@@ -181,7 +182,7 @@ func (s *AuthServiceTest) TestLoginSuccess() {
 
 	srv := service.NewAuthService(client)
 
-	res, err := srv.Login(s.LoginDto)
+	res, err := srv.Login(context.Background(), s.LoginDto)
 
 	assert.Nil(s.T(), err)
 	assert.Equal(s.T(), want, res)
@@ -207,7 +208,7 @@ func (s *AuthServiceTest) TestLoginUnauthorized() {
 
 	srv := service.NewAuthService(client)
 
-	res, err := srv.Login(s.LoginDto)
+	res, err := srv.Login(context.Background(), s.LoginDto)
 
 	assert.Nil(s.T(), res)
 	assert.Equal(s.T(), want, err)
@@ -225,7 +226,7 @@ func (s *AuthServiceTest) TestLoginGrpcError() {
 
 	srv := service.NewAuthService(client)
 
-	res, err := srv.Login(s.LoginDto)
+	res, err := srv.Login(context.Background(), s.LoginDto)
 
 	assert.Nil(s.T(), res)
 	assert.Equal(s.T(), want, err)
@@ -242,7 +243,7 @@ func (s *AuthServiceTest) TestLogoutSuccess() {
 
 	srv := service.NewAuthService(client)
 
-	res, err := srv.Logout(s.User.Id)
+	res, err := srv.Logout(context.Background(), s.User.Id)
 
 	assert.Nil(s.T(), err)
 	assert.True(s.T(), res)
@@ -265,7 +266,7 @@ func (s *AuthServiceTest) TestLogoutUnauthorized() {
 
 	srv := service.NewAuthService(client)
 
-	res, err := srv.Logout(s.User.Id)
+	res, err := srv.Logout(context.Background(), s.User.Id)
 
 	assert.False(s.T(), res)
 	assert.Equal(s.T(), want, err)
@@ -282,7 +283,7 @@ func (s *AuthServiceTest) TestLogoutGrpcError() {
 
 	srv := service.NewAuthService(client)
 
-	res, err := srv.Logout(s.User.Id)
+	res, err := srv.Logout(context.Background(), s.User.Id)
 
 	assert.False(s.T(), res)
 	assert.Equal(s.T(), want, err)
@@ -303,7 +304,7 @@ func (s *AuthServiceTest) TestChangePasswordSuccess() {
 
 	srv := service.NewAuthService(client)
 
-	res, err := srv.ChangePassword(s.ChangePassword)
+	res, err := srv.ChangePassword(context.Background(), s.ChangePassword)
 
 	assert.Nil(s.T(), err)
 	assert.True(s.T(), res)
@@ -330,7 +331,7 @@ func (s *AuthServiceTest) TestChangePasswordUnauthorized() {
 
 	srv := service.NewAuthService(client)
 
-	res, err := srv.ChangePassword(s.ChangePassword)
+	res, err := srv.ChangePassword(context.Background(), s.ChangePassword)
 
 	assert.False(s.T(), res)
 	assert.Equal(s.T(), want, err)
@@ -349,7 +350,7 @@ func (s *AuthServiceTest) TestChangePasswordGrpcError() {
 
 	srv := service.NewAuthService(client)
 
-	res, err := srv.ChangePassword(s.ChangePassword)
+	res, err := srv.ChangePassword(context.Background(), s.ChangePassword)
 
 	assert.False(s.T(), res)
 	assert.Equal(s.T(), want, err)
@@ -369,7 +370,7 @@ func (s *AuthServiceTest) TestValidateSuccess() {
 
 	srv := service.NewAuthService(client)
 
-	res, err := srv.Validate(token)
+	res, err := srv.Validate(context.Background(), token)
 
 	assert.Nil(s.T(), err)
 	assert.Equal(s.T(), want, res)
@@ -393,7 +394,7 @@ func (s *AuthServiceTest) TestValidateUnauthorized() {
 
 	srv := service.NewAuthService(client)
 
-	res, err := srv.Validate(token)
+	res, err := srv.Validate(context.Background(), token)
 
 	assert.Equal(s.T(), uint32(0), res)
 	assert.Equal(s.T(), want, err)
@@ -409,7 +410,7 @@ func (s *AuthServiceTest) TestValidateGrpcError() {
 
 	srv := service.NewAuthService(client)
 
-	res, err := srv.Validate(token)
+	res, err := srv.Validate(context.Background(), token)
 
 	assert.Equal(s.T(), uint32(0), res)
 	assert.Equal(s.T(), want, err)
@@ -429,7 +430,7 @@ func (s *AuthServiceTest) TestRefreshTokenSuccess() {
 
 	srv := service.NewAuthService(client)
 
-	res, err := srv.RefreshToken(token)
+	res, err := srv.RefreshToken(context.Background(), token)
 
 	assert.Nil(s.T(), err)
 	assert.Equal(s.T(), want, res)
@@ -453,7 +454,7 @@ func (s *AuthServiceTest) TestRefreshTokenUnauthorized() {
 
 	srv := service.NewAuthService(client)
 
-	res, err := srv.RefreshToken(token)
+	res, err := srv.RefreshToken(context.Background(), token)
 
 	assert.Nil(s.T(), res)
 	assert.Equal(s.T(), want, err)
@@ -469,7 +470,7 @@ func (s *AuthServiceTest) TestRefreshTokenGrpcError() {
 
 	srv := service.NewAuthService(client)
 
-	res, err := srv.RefreshToken(token)
+	res, err := srv.RefreshToken(context.Background(), token)
 
 	assert.Nil(s.T(), res)
 	assert.Equal(s.T(), want, err)
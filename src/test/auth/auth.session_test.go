@@ -0,0 +1,282 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/dto"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/proto"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"testing"
+	"time"
+)
+
+type SessionServiceTest struct {
+	suite.Suite
+	Credential  *proto.Credential
+	Device      *dto.Device
+	RevokedErr  *dto.ResponseErr
+	NotFoundErr *dto.ResponseErr
+}
+
+func TestSessionService(t *testing.T) {
+	suite.Run(t, new(SessionServiceTest))
+}
+
+func (s *SessionServiceTest) SetupTest() {
+	s.Credential = &proto.Credential{
+		AccessToken:  "access",
+		RefreshToken: "refresh",
+		ExpiresIn:    3600,
+	}
+	s.Device = &dto.Device{
+		UserAgent: "Mozilla/5.0",
+		IP:        "10.0.0.1",
+	}
+	s.RevokedErr = &dto.ResponseErr{
+		StatusCode: http.StatusUnauthorized,
+		Message:    "Session is revoked",
+		Data:       nil,
+	}
+	s.NotFoundErr = &dto.ResponseErr{
+		StatusCode: http.StatusUnauthorized,
+		Message:    "Session not found",
+		Data:       nil,
+	}
+}
+
+func (s *SessionServiceTest) TestRecordAndAuthenticate() {
+	srv := service.NewSessionService(service.NewMemorySessionStore(), time.Hour)
+
+	srv.Record(context.Background(), 1, s.Credential, s.Device)
+
+	id, errRes := srv.Authenticate(context.Background(), s.Credential.AccessToken)
+	assert.Nil(s.T(), errRes)
+	assert.NotEmpty(s.T(), id)
+
+	sessions, errRes := srv.List(context.Background(), 1, id)
+	assert.Nil(s.T(), errRes)
+	assert.Len(s.T(), sessions, 1)
+	assert.Equal(s.T(), id, sessions[0].ID)
+	assert.Equal(s.T(), s.Device.UserAgent, sessions[0].UserAgent)
+	assert.Equal(s.T(), s.Device.IP, sessions[0].IP)
+	assert.True(s.T(), sessions[0].Current)
+}
+
+func (s *SessionServiceTest) TestAuthenticateUntrackedToken() {
+	srv := service.NewSessionService(service.NewMemorySessionStore(), time.Hour)
+
+	id, errRes := srv.Authenticate(context.Background(), "untracked")
+
+	assert.Equal(s.T(), s.NotFoundErr, errRes)
+	assert.Empty(s.T(), id)
+}
+
+func (s *SessionServiceTest) TestRotate() {
+	srv := service.NewSessionService(service.NewMemorySessionStore(), time.Hour)
+	srv.Record(context.Background(), 1, s.Credential, s.Device)
+	id, _ := srv.Authenticate(context.Background(), s.Credential.AccessToken)
+
+	rotated := &proto.Credential{AccessToken: "access-2", RefreshToken: "refresh-2", ExpiresIn: 3600}
+	srv.Rotate(context.Background(), s.Credential.RefreshToken, rotated, s.Device)
+
+	oldId, _ := srv.Authenticate(context.Background(), s.Credential.AccessToken)
+	assert.Equal(s.T(), id, oldId)
+
+	newId, errRes := srv.Authenticate(context.Background(), rotated.AccessToken)
+	assert.Nil(s.T(), errRes)
+	assert.Equal(s.T(), id, newId)
+
+	sessions, _ := srv.List(context.Background(), 1, "")
+	assert.Len(s.T(), sessions, 1)
+}
+
+func (s *SessionServiceTest) TestRevokeRejectsSupersededToken() {
+	srv := service.NewSessionService(service.NewMemorySessionStore(), time.Hour)
+	srv.Record(context.Background(), 1, s.Credential, s.Device)
+
+	rotated := &proto.Credential{AccessToken: "access-2", RefreshToken: "refresh-2", ExpiresIn: 3600}
+	srv.Rotate(context.Background(), s.Credential.RefreshToken, rotated, s.Device)
+
+	assert.Nil(s.T(), srv.RevokeAll(context.Background(), 1))
+
+	_, errRes := srv.Authenticate(context.Background(), s.Credential.AccessToken)
+	assert.Equal(s.T(), s.RevokedErr, errRes)
+	_, errRes = srv.Authenticate(context.Background(), rotated.AccessToken)
+	assert.Equal(s.T(), s.RevokedErr, errRes)
+}
+
+func (s *SessionServiceTest) TestRotateDropsExpiredSupersededToken() {
+	hash := func(token string) string {
+		sum := sha256.Sum256([]byte(token))
+		return hex.EncodeToString(sum[:])
+	}
+
+	store := service.NewMemorySessionStore()
+	_ = store.Save(&service.UserSession{
+		ID:                   "session",
+		UserID:               1,
+		AccessTokenHash:      hash(s.Credential.AccessToken),
+		AccessTokenExpiresAt: time.Now().Add(time.Hour),
+		RefreshTokenHash:     hash(s.Credential.RefreshToken),
+		SupersededTokens:     []service.SupersededToken{{Hash: hash("expired"), ExpiresAt: time.Now().Add(-time.Minute)}},
+		CreatedAt:            time.Now(),
+		LastSeenAt:           time.Now(),
+	})
+
+	srv := service.NewSessionService(store, time.Hour)
+
+	rotated := &proto.Credential{AccessToken: "access-2", RefreshToken: "refresh-2", ExpiresIn: 3600}
+	srv.Rotate(context.Background(), s.Credential.RefreshToken, rotated, s.Device)
+
+	id, errRes := srv.Authenticate(context.Background(), "expired")
+	assert.Equal(s.T(), s.NotFoundErr, errRes)
+	assert.Empty(s.T(), id)
+	id, _ = srv.Authenticate(context.Background(), s.Credential.AccessToken)
+	assert.Equal(s.T(), "session", id)
+}
+
+func (s *SessionServiceTest) TestCheckRefreshUntrackedToken() {
+	srv := service.NewSessionService(service.NewMemorySessionStore(), time.Hour)
+
+	assert.Equal(s.T(), s.NotFoundErr, srv.CheckRefresh(context.Background(), "untracked"))
+}
+
+func (s *SessionServiceTest) TestRotateUntrackedRefreshToken() {
+	srv := service.NewSessionService(service.NewMemorySessionStore(), time.Hour)
+
+	srv.Rotate(context.Background(), "untracked", s.Credential, s.Device)
+
+	_, errRes := srv.Authenticate(context.Background(), s.Credential.AccessToken)
+	assert.Equal(s.T(), s.NotFoundErr, errRes)
+}
+
+func (s *SessionServiceTest) TestRevoke() {
+	srv := service.NewSessionService(service.NewMemorySessionStore(), time.Hour)
+	srv.Record(context.Background(), 1, s.Credential, s.Device)
+	id, _ := srv.Authenticate(context.Background(), s.Credential.AccessToken)
+
+	assert.Nil(s.T(), srv.Revoke(context.Background(), 1, id))
+
+	_, errRes := srv.Authenticate(context.Background(), s.Credential.AccessToken)
+	assert.Equal(s.T(), s.RevokedErr, errRes)
+	errRes = srv.CheckRefresh(context.Background(), s.Credential.RefreshToken)
+	assert.Equal(s.T(), s.RevokedErr, errRes)
+
+	sessions, _ := srv.List(context.Background(), 1, "")
+	assert.Empty(s.T(), sessions)
+}
+
+func (s *SessionServiceTest) TestRevokeSessionOfOtherUser() {
+	want := &dto.ResponseErr{
+		StatusCode: http.StatusNotFound,
+		Message:    "Session not found",
+		Data:       nil,
+	}
+
+	srv := service.NewSessionService(service.NewMemorySessionStore(), time.Hour)
+	srv.Record(context.Background(), 1, s.Credential, s.Device)
+	id, _ := srv.Authenticate(context.Background(), s.Credential.AccessToken)
+
+	assert.Equal(s.T(), want, srv.Revoke(context.Background(), 2, id))
+
+	_, errRes := srv.Authenticate(context.Background(), s.Credential.AccessToken)
+	assert.Nil(s.T(), errRes)
+}
+
+func (s *SessionServiceTest) TestRevokeAll() {
+	other := &proto.Credential{AccessToken: "access-2", RefreshToken: "refresh-2", ExpiresIn: 3600}
+	another := &proto.Credential{AccessToken: "access-3", RefreshToken: "refresh-3", ExpiresIn: 3600}
+
+	srv := service.NewSessionService(service.NewMemorySessionStore(), time.Hour)
+	srv.Record(context.Background(), 1, s.Credential, s.Device)
+	srv.Record(context.Background(), 1, other, s.Device)
+	srv.Record(context.Background(), 2, another, s.Device)
+
+	assert.Nil(s.T(), srv.RevokeAll(context.Background(), 1))
+
+	_, errRes := srv.Authenticate(context.Background(), s.Credential.AccessToken)
+	assert.Equal(s.T(), s.RevokedErr, errRes)
+	_, errRes = srv.Authenticate(context.Background(), other.AccessToken)
+	assert.Equal(s.T(), s.RevokedErr, errRes)
+	_, errRes = srv.Authenticate(context.Background(), another.AccessToken)
+	assert.Nil(s.T(), errRes)
+}
+
+func (s *SessionServiceTest) TestDropStaleSession() {
+	store := service.NewMemorySessionStore()
+	_ = store.Save(&service.UserSession{
+		ID:         "stale",
+		UserID:     1,
+		CreatedAt:  time.Now().Add(-3 * time.Hour),
+		LastSeenAt: time.Now().Add(-2 * time.Hour),
+	})
+
+	srv := service.NewSessionService(store, time.Hour)
+	srv.Record(context.Background(), 1, s.Credential, s.Device)
+
+	stale, _ := store.Get("stale")
+	assert.Nil(s.T(), stale)
+
+	sessions, _ := srv.List(context.Background(), 1, "")
+	assert.Len(s.T(), sessions, 1)
+}
+
+func (s *SessionServiceTest) TestRedisStore() {
+	server := miniredis.RunT(s.T())
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+
+	// the sessions are shared by the gateway instances
+	srv := service.NewSessionService(service.NewRedisSessionStore(client, "gateway:", time.Hour), time.Hour)
+	other := service.NewSessionService(service.NewRedisSessionStore(client, "gateway:", time.Hour), time.Hour)
+
+	srv.Record(context.Background(), 1, s.Credential, s.Device)
+
+	id, errRes := other.Authenticate(context.Background(), s.Credential.AccessToken)
+	assert.Nil(s.T(), errRes)
+	assert.NotEmpty(s.T(), id)
+	assert.True(s.T(), server.Exists("gateway:session:"+id))
+	assert.Equal(s.T(), time.Hour, server.TTL("gateway:session:"+id))
+
+	rotated := &proto.Credential{AccessToken: "access-2", RefreshToken: "refresh-2", ExpiresIn: 3600}
+	assert.Nil(s.T(), other.CheckRefresh(context.Background(), s.Credential.RefreshToken))
+	other.Rotate(context.Background(), s.Credential.RefreshToken, rotated, s.Device)
+
+	assert.Equal(s.T(), s.NotFoundErr, srv.CheckRefresh(context.Background(), s.Credential.RefreshToken))
+	newId, errRes := srv.Authenticate(context.Background(), rotated.AccessToken)
+	assert.Nil(s.T(), errRes)
+	assert.Equal(s.T(), id, newId)
+
+	assert.Nil(s.T(), srv.Revoke(context.Background(), 1, id))
+
+	_, errRes = other.Authenticate(context.Background(), s.Credential.AccessToken)
+	assert.Equal(s.T(), s.RevokedErr, errRes)
+	_, errRes = other.Authenticate(context.Background(), rotated.AccessToken)
+	assert.Equal(s.T(), s.RevokedErr, errRes)
+	assert.Equal(s.T(), s.RevokedErr, other.CheckRefresh(context.Background(), rotated.RefreshToken))
+
+	sessions, errRes := other.List(context.Background(), 1, "")
+	assert.Nil(s.T(), errRes)
+	assert.Empty(s.T(), sessions)
+}
+
+func (s *SessionServiceTest) TestRedisStoreDeletesExpiredSession() {
+	server := miniredis.RunT(s.T())
+	store := service.NewRedisSessionStore(redis.NewClient(&redis.Options{Addr: server.Addr()}), "gateway:", time.Hour)
+	srv := service.NewSessionService(store, time.Hour)
+
+	srv.Record(context.Background(), 1, s.Credential, s.Device)
+	server.FastForward(2 * time.Hour)
+
+	_, errRes := srv.Authenticate(context.Background(), s.Credential.AccessToken)
+	assert.Equal(s.T(), s.NotFoundErr, errRes)
+
+	sessions, err := store.FindByUser(1)
+	assert.Nil(s.T(), err)
+	assert.Empty(s.T(), sessions)
+}
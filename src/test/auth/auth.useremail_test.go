@@ -0,0 +1,63 @@
+package auth
+
+import (
+	"context"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/dto"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"testing"
+)
+
+type UserEmailServiceTest struct {
+	suite.Suite
+}
+
+func TestUserEmailService(t *testing.T) {
+	suite.Run(t, new(UserEmailServiceTest))
+}
+
+func (s *UserEmailServiceTest) TestRememberAndFind() {
+	srv := service.NewUserEmailService(service.NewMemoryUserEmailStore())
+
+	email, errRes := srv.Find(context.Background(), 1)
+	assert.Nil(s.T(), errRes)
+	assert.Empty(s.T(), email)
+
+	srv.Remember(context.Background(), 1, "smithy@samithiwat.dev")
+
+	email, errRes = srv.Find(context.Background(), 1)
+	assert.Nil(s.T(), errRes)
+	assert.Equal(s.T(), "smithy@samithiwat.dev", email)
+}
+
+func (s *UserEmailServiceTest) TestRedisStore() {
+	server := miniredis.RunT(s.T())
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+
+	service.NewUserEmailService(service.NewRedisUserEmailStore(client, "gateway:")).Remember(context.Background(), 1, "smithy@samithiwat.dev")
+	assert.True(s.T(), server.Exists("gateway:email:1"))
+
+	// the gateway restarted, only the store is shared
+	email, errRes := service.NewUserEmailService(service.NewRedisUserEmailStore(client, "gateway:")).Find(context.Background(), 1)
+	assert.Nil(s.T(), errRes)
+	assert.Equal(s.T(), "smithy@samithiwat.dev", email)
+}
+
+func (s *UserEmailServiceTest) TestRedisStoreDown() {
+	want := &dto.ResponseErr{
+		StatusCode: http.StatusServiceUnavailable,
+		Message:    "Service is down",
+		Data:       nil,
+	}
+
+	server := miniredis.RunT(s.T())
+	client := redis.NewClient(&redis.Options{Addr: server.Addr(), MaxRetries: -1})
+	server.Close()
+
+	_, errRes := service.NewUserEmailService(service.NewRedisUserEmailStore(client, "gateway:")).Find(context.Background(), 1)
+	assert.Equal(s.T(), want, errRes)
+}
@@ -0,0 +1,167 @@
+package auth
+
+import (
+	"errors"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/dto"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/service"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/test/mail"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"regexp"
+	"testing"
+	"time"
+)
+
+type VerificationServiceTest struct {
+	suite.Suite
+	Email      string
+	Url        string
+	InvalidErr *dto.ResponseErr
+}
+
+func TestVerificationService(t *testing.T) {
+	suite.Run(t, new(VerificationServiceTest))
+}
+
+func (s *VerificationServiceTest) SetupTest() {
+	s.Email = "smithy@samithiwat.dev"
+	s.Url = "https://samithiwat.dev/auth/verify-email?token=%s"
+	s.InvalidErr = &dto.ResponseErr{
+		StatusCode: http.StatusBadRequest,
+		Message:    "Invalid or expired verification token",
+		Data:       nil,
+	}
+}
+
+func (s *VerificationServiceTest) token(mail *dto.Mail) string {
+	match := regexp.MustCompile(`token=(\S+)`).FindStringSubmatch(mail.Body)
+	s.Len(match, 2)
+
+	return match[1]
+}
+
+func (s *VerificationServiceTest) TestSendAndVerify() {
+	mailer := new(mail.MailerMock)
+	srv := service.NewEmailVerificationService(service.NewMemoryVerificationStore(), mailer, "secret", time.Hour, s.Url)
+
+	srv.Send(1, s.Email)
+
+	verified, errRes := srv.IsVerified(1)
+	assert.Nil(s.T(), errRes)
+	assert.False(s.T(), verified)
+	assert.Len(s.T(), mailer.Mails, 1)
+	assert.Equal(s.T(), s.Email, mailer.Mails[0].To)
+
+	assert.Nil(s.T(), srv.Verify(s.token(mailer.Mails[0])))
+
+	verified, _ = srv.IsVerified(1)
+	assert.True(s.T(), verified)
+}
+
+func (s *VerificationServiceTest) TestUserBeforeVerificationIsVerified() {
+	srv := service.NewEmailVerificationService(service.NewMemoryVerificationStore(), new(mail.MailerMock), "secret", time.Hour, s.Url)
+
+	verified, errRes := srv.IsVerified(1)
+
+	assert.Nil(s.T(), errRes)
+	assert.True(s.T(), verified)
+}
+
+func (s *VerificationServiceTest) TestSendMailFailed() {
+	mailer := &mail.MailerMock{Err: errors.New("connection refused")}
+	srv := service.NewEmailVerificationService(service.NewMemoryVerificationStore(), mailer, "secret", time.Hour, s.Url)
+
+	srv.Send(1, s.Email)
+
+	verified, _ := srv.IsVerified(1)
+	assert.False(s.T(), verified)
+}
+
+func (s *VerificationServiceTest) TestResend() {
+	mailer := new(mail.MailerMock)
+	srv := service.NewEmailVerificationService(service.NewMemoryVerificationStore(), mailer, "secret", time.Hour, s.Url)
+
+	srv.Send(1, s.Email)
+
+	assert.Nil(s.T(), srv.Resend(1))
+	assert.Len(s.T(), mailer.Mails, 2)
+	assert.Equal(s.T(), s.Email, mailer.Mails[1].To)
+
+	assert.Nil(s.T(), srv.Verify(s.token(mailer.Mails[1])))
+	assert.Equal(s.T(), http.StatusConflict, srv.Resend(1).StatusCode)
+}
+
+func (s *VerificationServiceTest) TestVerifyExpiredToken() {
+	mailer := new(mail.MailerMock)
+	srv := service.NewEmailVerificationService(service.NewMemoryVerificationStore(), mailer, "secret", -time.Minute, s.Url)
+
+	srv.Send(1, s.Email)
+
+	assert.Equal(s.T(), s.InvalidErr, srv.Verify(s.token(mailer.Mails[0])))
+
+	verified, _ := srv.IsVerified(1)
+	assert.False(s.T(), verified)
+}
+
+func (s *VerificationServiceTest) TestVerifyTamperedToken() {
+	mailer := new(mail.MailerMock)
+	store := service.NewMemoryVerificationStore()
+	srv := service.NewEmailVerificationService(store, mailer, "secret", time.Hour, s.Url)
+	other := service.NewEmailVerificationService(store, mailer, "other", time.Hour, s.Url)
+
+	other.Send(1, s.Email)
+
+	assert.Equal(s.T(), s.InvalidErr, srv.Verify(s.token(mailer.Mails[0])))
+	assert.Equal(s.T(), s.InvalidErr, srv.Verify("token"))
+}
+
+func (s *VerificationServiceTest) TestDisabledVerification() {
+	srv := service.DisabledVerification{}
+
+	verified, errRes := srv.IsVerified(1)
+
+	assert.Nil(s.T(), errRes)
+	assert.True(s.T(), verified)
+	assert.Equal(s.T(), http.StatusNotFound, srv.Verify("token").StatusCode)
+}
+
+func (s *VerificationServiceTest) TestRedisStore() {
+	server := miniredis.RunT(s.T())
+	store := service.NewRedisVerificationStore(redis.NewClient(&redis.Options{Addr: server.Addr()}), "gateway:")
+
+	assert.Nil(s.T(), store.Set(1, s.Email))
+	assert.True(s.T(), server.Exists("gateway:verification:1"))
+
+	email, pending, err := store.Get(1)
+	assert.Nil(s.T(), err)
+	assert.True(s.T(), pending)
+	assert.Equal(s.T(), s.Email, email)
+
+	assert.Nil(s.T(), store.Delete(1))
+
+	_, pending, err = store.Get(1)
+	assert.Nil(s.T(), err)
+	assert.False(s.T(), pending)
+}
+
+func (s *VerificationServiceTest) TestRedisStoreDown() {
+	want := &dto.ResponseErr{
+		StatusCode: http.StatusServiceUnavailable,
+		Message:    "Service is down",
+		Data:       nil,
+	}
+
+	server := miniredis.RunT(s.T())
+	client := redis.NewClient(&redis.Options{Addr: server.Addr(), MaxRetries: -1})
+	server.Close()
+
+	srv := service.NewEmailVerificationService(service.NewRedisVerificationStore(client, "gateway:"), new(mail.MailerMock), "secret", time.Hour, s.Url)
+
+	verified, errRes := srv.IsVerified(1)
+
+	assert.False(s.T(), verified)
+	assert.Equal(s.T(), want, errRes)
+}
@@ -0,0 +1,117 @@
+package contact
+
+import (
+	"context"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/dto"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/proto"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc"
+)
+
+type ServiceMock struct {
+	mock.Mock
+}
+
+func (s *ServiceMock) FindOne(_ context.Context, id int32) (res *proto.Contact, err *dto.ResponseErr) {
+	args := s.Called(id)
+
+	if args.Get(0) != nil {
+		res = args.Get(0).(*proto.Contact)
+	}
+
+	if args.Get(1) != nil {
+		err = args.Get(1).(*dto.ResponseErr)
+	}
+
+	return
+}
+
+func (s *ServiceMock) Create(_ context.Context, contact *dto.ContactDto) (res *proto.Contact, err *dto.ResponseErr) {
+	args := s.Called(contact)
+
+	if args.Get(0) != nil {
+		res = args.Get(0).(*proto.Contact)
+	}
+
+	if args.Get(1) != nil {
+		err = args.Get(1).(*dto.ResponseErr)
+	}
+
+	return
+}
+
+func (s *ServiceMock) Update(_ context.Context, id int32, contact *dto.ContactDto) (res *proto.Contact, err *dto.ResponseErr) {
+	args := s.Called(id, contact)
+
+	if args.Get(0) != nil {
+		res = args.Get(0).(*proto.Contact)
+	}
+
+	if args.Get(1) != nil {
+		err = args.Get(1).(*dto.ResponseErr)
+	}
+
+	return
+}
+
+func (s *ServiceMock) Delete(_ context.Context, id int32) (res *proto.Contact, err *dto.ResponseErr) {
+	args := s.Called(id)
+
+	if args.Get(0) != nil {
+		res = args.Get(0).(*proto.Contact)
+	}
+
+	if args.Get(1) != nil {
+		err = args.Get(1).(*dto.ResponseErr)
+	}
+
+	return
+}
+
+type ClientMock struct {
+	mock.Mock
+}
+
+func (c *ClientMock) FindOne(ctx context.Context, in *proto.FindOneContactRequest, opts ...grpc.CallOption) (res *proto.ContactResponse, err error) {
+	args := c.Called(in)
+
+	if args.Get(0) != nil {
+		res = args.Get(0).(*proto.ContactResponse)
+	}
+
+	return res, args.Error(1)
+}
+
+func (c *ClientMock) FindMulti(ctx context.Context, in *proto.FindMultiContactRequest, opts ...grpc.CallOption) (*proto.ContactListResponse, error) {
+	return nil, nil
+}
+
+func (c *ClientMock) Create(ctx context.Context, in *proto.CreateContactRequest, opts ...grpc.CallOption) (res *proto.ContactResponse, err error) {
+	args := c.Called(in.Contact)
+
+	if args.Get(0) != nil {
+		res = args.Get(0).(*proto.ContactResponse)
+	}
+
+	return res, args.Error(1)
+}
+
+func (c *ClientMock) Update(ctx context.Context, in *proto.UpdateContactRequest, opts ...grpc.CallOption) (res *proto.ContactResponse, err error) {
+	args := c.Called(in.Contact)
+
+	if args.Get(0) != nil {
+		res = args.Get(0).(*proto.ContactResponse)
+	}
+
+	return res, args.Error(1)
+}
+
+func (c *ClientMock) Delete(ctx context.Context, in *proto.DeleteContactRequest, opts ...grpc.CallOption) (res *proto.ContactResponse, err error) {
+	args := c.Called(in)
+
+	if args.Get(0) != nil {
+		res = args.Get(0).(*proto.ContactResponse)
+	}
+
+	return res, args.Error(1)
+}
@@ -0,0 +1,270 @@
+package contact
+
+import (
+	"context"
+	"github.com/bxcodec/faker/v3"
+	"github.com/pkg/errors"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/dto"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/proto"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"testing"
+)
+
+type ContactServiceTest struct {
+	suite.Suite
+	Contact        *proto.Contact
+	ContactReq     *proto.Contact
+	ContactDto     *dto.ContactDto
+	NotFoundErr    *dto.ResponseErr
+	ServiceDownErr *dto.ResponseErr
+}
+
+func TestContactService(t *testing.T) {
+	suite.Run(t, new(ContactServiceTest))
+}
+
+func (s *ContactServiceTest) SetupTest() {
+	s.Contact = &proto.Contact{
+		Id:        1,
+		Facebook:  faker.URL(),
+		Instagram: faker.URL(),
+		Twitter:   faker.URL(),
+		Linkedin:  faker.URL(),
+	}
+
+	s.ContactReq = &proto.Contact{
+		Facebook:  s.Contact.Facebook,
+		Instagram: s.Contact.Instagram,
+		Twitter:   s.Contact.Twitter,
+		Linkedin:  s.Contact.Linkedin,
+	}
+
+	s.ContactDto = &dto.ContactDto{
+		Facebook:  s.Contact.Facebook,
+		Instagram: s.Contact.Instagram,
+		Twitter:   s.Contact.Twitter,
+		Linkedin:  s.Contact.Linkedin,
+	}
+
+	s.ServiceDownErr = &dto.ResponseErr{
+		StatusCode: http.StatusServiceUnavailable,
+		Message:    "Service is down",
+		Data:       nil,
+	}
+
+	s.NotFoundErr = &dto.ResponseErr{
+		StatusCode: http.StatusNotFound,
+		Message:    "Not found contact",
+		Data:       nil,
+	}
+}
+
+func (s *ContactServiceTest) TestFindOneContactService() {
+	want := s.Contact
+
+	var id int32
+	_ = faker.FakeData(&id)
+
+	client := new(ClientMock)
+
+	client.On("FindOne", &proto.FindOneContactRequest{Id: id}).Return(&proto.ContactResponse{
+		StatusCode: http.StatusOK,
+		Errors:     nil,
+		Data:       s.Contact,
+	}, nil)
+
+	srv := service.NewContactService(client)
+
+	contact, err := srv.FindOne(context.Background(), id)
+
+	assert.Nil(s.T(), err, "Must not got any error")
+	assert.Equal(s.T(), want, contact)
+}
+
+func (s *ContactServiceTest) TestFindOneNotFoundContactService() {
+	want := s.NotFoundErr
+
+	var id int32
+	_ = faker.FakeData(&id)
+
+	client := new(ClientMock)
+
+	client.On("FindOne", &proto.FindOneContactRequest{Id: id}).Return(&proto.ContactResponse{
+		StatusCode: http.StatusNotFound,
+		Errors:     []string{"Not found contact"},
+		Data:       nil,
+	}, nil)
+
+	srv := service.NewContactService(client)
+
+	contact, err := srv.FindOne(context.Background(), id)
+
+	assert.Nil(s.T(), contact)
+	assert.Equal(s.T(), want, err)
+}
+
+func (s *ContactServiceTest) TestFindOneGrpcErrContactService() {
+	want := s.ServiceDownErr
+
+	var id int32
+	_ = faker.FakeData(&id)
+
+	client := new(ClientMock)
+
+	client.On("FindOne", &proto.FindOneContactRequest{Id: id}).Return(&proto.ContactResponse{}, errors.New("Service is down"))
+
+	srv := service.NewContactService(client)
+
+	_, err := srv.FindOne(context.Background(), id)
+
+	assert.Equal(s.T(), want, err)
+}
+
+func (s *ContactServiceTest) TestCreateContactService() {
+	want := s.Contact
+
+	client := new(ClientMock)
+
+	client.On("Create", s.ContactReq).Return(&proto.ContactResponse{
+		StatusCode: http.StatusCreated,
+		Errors:     nil,
+		Data:       s.Contact,
+	}, nil)
+
+	srv := service.NewContactService(client)
+
+	contact, err := srv.Create(context.Background(), s.ContactDto)
+
+	assert.Nil(s.T(), err, "Must not got any error")
+	assert.Equal(s.T(), want, contact)
+}
+
+func (s *ContactServiceTest) TestCreateGrpcErrContactService() {
+	want := s.ServiceDownErr
+
+	client := new(ClientMock)
+
+	client.On("Create", s.ContactReq).Return(&proto.ContactResponse{}, errors.New("Service is down"))
+
+	srv := service.NewContactService(client)
+
+	_, err := srv.Create(context.Background(), s.ContactDto)
+
+	assert.Equal(s.T(), want, err)
+}
+
+func (s *ContactServiceTest) TestUpdateContactService() {
+	want := s.Contact
+
+	client := new(ClientMock)
+
+	client.On("Update", s.Contact).Return(&proto.ContactResponse{
+		StatusCode: http.StatusOK,
+		Errors:     nil,
+		Data:       s.Contact,
+	}, nil)
+
+	srv := service.NewContactService(client)
+
+	contact, err := srv.Update(context.Background(), 1, s.ContactDto)
+
+	assert.Nil(s.T(), err, "Must not got any error")
+	assert.Equal(s.T(), want, contact)
+}
+
+func (s *ContactServiceTest) TestUpdateNotFoundContactService() {
+	want := s.NotFoundErr
+
+	client := new(ClientMock)
+
+	client.On("Update", s.Contact).Return(&proto.ContactResponse{
+		StatusCode: http.StatusNotFound,
+		Errors:     []string{"Not found contact"},
+		Data:       nil,
+	}, nil)
+
+	srv := service.NewContactService(client)
+
+	contact, err := srv.Update(context.Background(), 1, s.ContactDto)
+
+	assert.Nil(s.T(), contact)
+	assert.Equal(s.T(), want, err)
+}
+
+func (s *ContactServiceTest) TestUpdateGrpcErrContactService() {
+	want := s.ServiceDownErr
+
+	client := new(ClientMock)
+
+	client.On("Update", s.Contact).Return(&proto.ContactResponse{}, errors.New("Service is down"))
+
+	srv := service.NewContactService(client)
+
+	_, err := srv.Update(context.Background(), 1, s.ContactDto)
+
+	assert.Equal(s.T(), want, err)
+}
+
+func (s *ContactServiceTest) TestDeleteContactService() {
+	want := s.Contact
+
+	var id int32
+	_ = faker.FakeData(&id)
+
+	client := new(ClientMock)
+
+	client.On("Delete", &proto.DeleteContactRequest{Id: id}).Return(&proto.ContactResponse{
+		StatusCode: http.StatusOK,
+		Errors:     nil,
+		Data:       s.Contact,
+	}, nil)
+
+	srv := service.NewContactService(client)
+
+	contact, err := srv.Delete(context.Background(), id)
+
+	assert.Nil(s.T(), err, "Must not got any error")
+	assert.Equal(s.T(), want, contact)
+}
+
+func (s *ContactServiceTest) TestDeleteNotFoundContactService() {
+	want := s.NotFoundErr
+
+	var id int32
+	_ = faker.FakeData(&id)
+
+	client := new(ClientMock)
+
+	client.On("Delete", &proto.DeleteContactRequest{Id: id}).Return(&proto.ContactResponse{
+		StatusCode: http.StatusNotFound,
+		Errors:     []string{"Not found contact"},
+		Data:       nil,
+	}, nil)
+
+	srv := service.NewContactService(client)
+
+	contact, err := srv.Delete(context.Background(), id)
+
+	assert.Nil(s.T(), contact)
+	assert.Equal(s.T(), want, err)
+}
+
+func (s *ContactServiceTest) TestDeleteGrpcErrContactService() {
+	want := s.ServiceDownErr
+
+	var id int32
+	_ = faker.FakeData(&id)
+
+	client := new(ClientMock)
+
+	client.On("Delete", &proto.DeleteContactRequest{Id: id}).Return(&proto.ContactResponse{}, errors.New("Service is down"))
+
+	srv := service.NewContactService(client)
+
+	_, err := srv.Delete(context.Background(), id)
+
+	assert.Equal(s.T(), want, err)
+}
@@ -0,0 +1,298 @@
+package jwt
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/dto"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/service"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/test/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type JwtServiceTest struct {
+	suite.Suite
+	Secret          []byte
+	RsaKey          *rsa.PrivateKey
+	RotatedRsaKey   *rsa.PrivateKey
+	EcKey           *ecdsa.PrivateKey
+	Options         service.JwtOptions
+	UnauthorizedErr *dto.ResponseErr
+}
+
+func TestJwtService(t *testing.T) {
+	suite.Run(t, new(JwtServiceTest))
+}
+
+func (s *JwtServiceTest) SetupSuite() {
+	s.RsaKey, _ = rsa.GenerateKey(rand.Reader, 2048)
+	s.RotatedRsaKey, _ = rsa.GenerateKey(rand.Reader, 2048)
+	s.EcKey, _ = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+}
+
+func (s *JwtServiceTest) SetupTest() {
+	s.Secret = []byte("secret")
+
+	s.Options = service.JwtOptions{
+		Issuer:    "samithiwat.dev",
+		Audience:  "gateway",
+		ClockSkew: 30 * time.Second,
+	}
+
+	s.UnauthorizedErr = &dto.ResponseErr{
+		StatusCode: http.StatusUnauthorized,
+		Message:    "Invalid token",
+		Data:       nil,
+	}
+}
+
+func (s *JwtServiceTest) claims(sub interface{}) map[string]interface{} {
+	now := time.Now()
+
+	return map[string]interface{}{
+		"sub": sub,
+		"iss": s.Options.Issuer,
+		"aud": []string{s.Options.Audience},
+		"iat": now.Unix(),
+		"exp": now.Add(time.Hour).Unix(),
+	}
+}
+
+func sign(alg string, kid string, key interface{}, claims map[string]interface{}) string {
+	header, _ := json.Marshal(map[string]string{"alg": alg, "kid": kid, "typ": "JWT"})
+	payload, _ := json.Marshal(claims)
+
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+
+	var sig []byte
+	switch k := key.(type) {
+	case []byte:
+		mac := hmac.New(crypto.SHA256.New, k)
+		mac.Write([]byte(signed))
+		sig = mac.Sum(nil)
+	case *rsa.PrivateKey:
+		digest := crypto.SHA256.New()
+		digest.Write([]byte(signed))
+		sig, _ = rsa.SignPKCS1v15(rand.Reader, k, crypto.SHA256, digest.Sum(nil))
+	case *ecdsa.PrivateKey:
+		digest := crypto.SHA256.New()
+		digest.Write([]byte(signed))
+		r, ss, _ := ecdsa.Sign(rand.Reader, k, digest.Sum(nil))
+		sig = append(r.FillBytes(make([]byte, 32)), ss.FillBytes(make([]byte, 32))...)
+	}
+
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func jwks(kid string, key *rsa.PrivateKey) map[string]interface{} {
+	return map[string]interface{}{
+		"kid": kid,
+		"kty": "RSA",
+		"alg": "RS256",
+		"use": "sig",
+		"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}
+}
+
+func (s *JwtServiceTest) hmacKeySet() *service.KeySet {
+	key, _ := service.NewJwtKey("hs", "HS256", string(s.Secret), "")
+
+	return service.NewKeySet([]*service.JwtKey{key}, "", time.Minute)
+}
+
+func (s *JwtServiceTest) TestValidateHmacSuccess() {
+	srv := service.NewJwtService(s.hmacKeySet(), s.Options)
+
+	userId, err := srv.Validate(context.Background(), "Bearer "+sign("HS256", "hs", s.Secret, s.claims("12")))
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), uint32(12), userId)
+}
+
+func (s *JwtServiceTest) TestValidateEcdsaSuccess() {
+	der, _ := x509.MarshalPKIXPublicKey(&s.EcKey.PublicKey)
+	pub := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+
+	key, err := service.NewJwtKey("ec", "ES256", "", string(pub))
+	assert.Nil(s.T(), err)
+
+	srv := service.NewJwtService(service.NewKeySet([]*service.JwtKey{key}, "", time.Minute), s.Options)
+
+	userId, errRes := srv.Validate(context.Background(), sign("ES256", "ec", s.EcKey, s.claims(float64(7))))
+
+	assert.Nil(s.T(), errRes)
+	assert.Equal(s.T(), uint32(7), userId)
+}
+
+func (s *JwtServiceTest) TestValidateJwksSuccess() {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"keys": []interface{}{jwks("rs-1", s.RsaKey)}})
+	}))
+	defer server.Close()
+
+	srv := service.NewJwtService(service.NewKeySet(nil, server.URL, time.Minute), s.Options)
+
+	userId, err := srv.Validate(context.Background(), sign("RS256", "rs-1", s.RsaKey, s.claims("3")))
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), uint32(3), userId)
+}
+
+func (s *JwtServiceTest) TestValidateJwksRotation() {
+	var rotated int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys := []interface{}{jwks("rs-1", s.RsaKey)}
+		if atomic.LoadInt32(&rotated) == 1 {
+			keys = []interface{}{jwks("rs-2", s.RotatedRsaKey)}
+		}
+
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"keys": keys})
+	}))
+	defer server.Close()
+
+	srv := service.NewJwtService(service.NewKeySet(nil, server.URL, time.Nanosecond), s.Options)
+
+	_, err := srv.Validate(context.Background(), sign("RS256", "rs-1", s.RsaKey, s.claims("3")))
+	assert.Nil(s.T(), err)
+
+	atomic.StoreInt32(&rotated, 1)
+
+	userId, err := srv.Validate(context.Background(), sign("RS256", "rs-2", s.RotatedRsaKey, s.claims("3")))
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), uint32(3), userId)
+
+	_, err = srv.Validate(context.Background(), sign("RS256", "rs-1", s.RsaKey, s.claims("3")))
+	assert.NotNil(s.T(), err)
+}
+
+func (s *JwtServiceTest) TestValidateExpiredWithinClockSkew() {
+	claims := s.claims("1")
+	claims["exp"] = time.Now().Add(-10 * time.Second).Unix()
+
+	srv := service.NewJwtService(s.hmacKeySet(), s.Options)
+
+	userId, err := srv.Validate(context.Background(), sign("HS256", "hs", s.Secret, claims))
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), uint32(1), userId)
+}
+
+func (s *JwtServiceTest) TestValidateExpired() {
+	claims := s.claims("1")
+	claims["exp"] = time.Now().Add(-time.Minute).Unix()
+
+	srv := service.NewJwtService(s.hmacKeySet(), s.Options)
+
+	_, err := srv.Validate(context.Background(), sign("HS256", "hs", s.Secret, claims))
+
+	assert.Equal(s.T(), s.UnauthorizedErr, err)
+}
+
+func (s *JwtServiceTest) TestValidateNotBefore() {
+	claims := s.claims("1")
+	claims["nbf"] = time.Now().Add(time.Minute).Unix()
+
+	srv := service.NewJwtService(s.hmacKeySet(), s.Options)
+
+	_, err := srv.Validate(context.Background(), sign("HS256", "hs", s.Secret, claims))
+
+	assert.Equal(s.T(), s.UnauthorizedErr, err)
+}
+
+func (s *JwtServiceTest) TestValidateInvalidIssuerAndAudience() {
+	srv := service.NewJwtService(s.hmacKeySet(), s.Options)
+
+	claims := s.claims("1")
+	claims["iss"] = "someone.else"
+	_, err := srv.Validate(context.Background(), sign("HS256", "hs", s.Secret, claims))
+	assert.Equal(s.T(), s.UnauthorizedErr, err)
+
+	claims = s.claims("1")
+	claims["aud"] = "another-service"
+	_, err = srv.Validate(context.Background(), sign("HS256", "hs", s.Secret, claims))
+	assert.Equal(s.T(), s.UnauthorizedErr, err)
+}
+
+func (s *JwtServiceTest) TestValidateInvalidSignature() {
+	srv := service.NewJwtService(s.hmacKeySet(), s.Options)
+
+	_, err := srv.Validate(context.Background(), sign("HS256", "hs", []byte("wrong"), s.claims("1")))
+
+	assert.Equal(s.T(), s.UnauthorizedErr, err)
+}
+
+func (s *JwtServiceTest) TestValidateUnsupportedAlgorithm() {
+	srv := service.NewJwtService(s.hmacKeySet(), s.Options)
+
+	_, err := srv.Validate(context.Background(), sign("none", "hs", s.Secret, s.claims("1")))
+
+	assert.Equal(s.T(), s.UnauthorizedErr, err)
+}
+
+func (s *JwtServiceTest) TestValidateKeyUnavailable() {
+	want := &dto.ResponseErr{
+		StatusCode: http.StatusServiceUnavailable,
+		Message:    "Cannot verify the token",
+		Data:       nil,
+	}
+
+	srv := service.NewJwtService(s.hmacKeySet(), s.Options)
+
+	_, err := srv.Validate(context.Background(), sign("RS256", "rs-1", s.RsaKey, s.claims("1")))
+
+	assert.Equal(s.T(), want, err)
+}
+
+func (s *JwtServiceTest) TestHybridFallbackWhenKeyUnavailable() {
+	token := sign("RS256", "rs-1", s.RsaKey, s.claims("1"))
+
+	fallback := new(auth.ServiceMock)
+	fallback.On("Validate", token).Return(5, nil)
+
+	srv := service.NewHybridTokenService(service.NewJwtService(s.hmacKeySet(), s.Options), fallback)
+
+	userId, err := srv.Validate(context.Background(), token)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), uint32(5), userId)
+	fallback.AssertNumberOfCalls(s.T(), "Validate", 1)
+}
+
+func (s *JwtServiceTest) TestHybridNoFallbackWhenTokenInvalid() {
+	fallback := new(auth.ServiceMock)
+
+	srv := service.NewHybridTokenService(service.NewJwtService(s.hmacKeySet(), s.Options), fallback)
+
+	_, err := srv.Validate(context.Background(), sign("HS256", "hs", []byte("wrong"), s.claims("1")))
+
+	assert.Equal(s.T(), s.UnauthorizedErr, err)
+	fallback.AssertNumberOfCalls(s.T(), "Validate", 0)
+}
+
+func (s *JwtServiceTest) TestNewJwtKeyInvalid() {
+	_, err := service.NewJwtKey("hs", "HS256", "", "")
+	assert.NotNil(s.T(), err)
+
+	_, err = service.NewJwtKey("rs", "RS256", "", "not a pem")
+	assert.NotNil(s.T(), err)
+
+	_, err = service.NewJwtKey("none", "none", "secret", "")
+	assert.NotNil(s.T(), err)
+}
@@ -0,0 +1,117 @@
+package location
+
+import (
+	"context"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/dto"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/proto"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc"
+)
+
+type ServiceMock struct {
+	mock.Mock
+}
+
+func (s *ServiceMock) FindOne(_ context.Context, id int32) (res *proto.Location, err *dto.ResponseErr) {
+	args := s.Called(id)
+
+	if args.Get(0) != nil {
+		res = args.Get(0).(*proto.Location)
+	}
+
+	if args.Get(1) != nil {
+		err = args.Get(1).(*dto.ResponseErr)
+	}
+
+	return
+}
+
+func (s *ServiceMock) Create(_ context.Context, location *dto.LocationDto) (res *proto.Location, err *dto.ResponseErr) {
+	args := s.Called(location)
+
+	if args.Get(0) != nil {
+		res = args.Get(0).(*proto.Location)
+	}
+
+	if args.Get(1) != nil {
+		err = args.Get(1).(*dto.ResponseErr)
+	}
+
+	return
+}
+
+func (s *ServiceMock) Update(_ context.Context, id int32, location *dto.LocationDto) (res *proto.Location, err *dto.ResponseErr) {
+	args := s.Called(id, location)
+
+	if args.Get(0) != nil {
+		res = args.Get(0).(*proto.Location)
+	}
+
+	if args.Get(1) != nil {
+		err = args.Get(1).(*dto.ResponseErr)
+	}
+
+	return
+}
+
+func (s *ServiceMock) Delete(_ context.Context, id int32) (res *proto.Location, err *dto.ResponseErr) {
+	args := s.Called(id)
+
+	if args.Get(0) != nil {
+		res = args.Get(0).(*proto.Location)
+	}
+
+	if args.Get(1) != nil {
+		err = args.Get(1).(*dto.ResponseErr)
+	}
+
+	return
+}
+
+type ClientMock struct {
+	mock.Mock
+}
+
+func (c *ClientMock) FindOne(ctx context.Context, in *proto.FindOneLocationRequest, opts ...grpc.CallOption) (res *proto.LocationResponse, err error) {
+	args := c.Called(in)
+
+	if args.Get(0) != nil {
+		res = args.Get(0).(*proto.LocationResponse)
+	}
+
+	return res, args.Error(1)
+}
+
+func (c *ClientMock) FindMulti(ctx context.Context, in *proto.FindMultiLocationRequest, opts ...grpc.CallOption) (*proto.LocationListResponse, error) {
+	return nil, nil
+}
+
+func (c *ClientMock) Create(ctx context.Context, in *proto.CreateLocationRequest, opts ...grpc.CallOption) (res *proto.LocationResponse, err error) {
+	args := c.Called(in.Location)
+
+	if args.Get(0) != nil {
+		res = args.Get(0).(*proto.LocationResponse)
+	}
+
+	return res, args.Error(1)
+}
+
+func (c *ClientMock) Update(ctx context.Context, in *proto.UpdateLocationRequest, opts ...grpc.CallOption) (res *proto.LocationResponse, err error) {
+	args := c.Called(in.Location)
+
+	if args.Get(0) != nil {
+		res = args.Get(0).(*proto.LocationResponse)
+	}
+
+	return res, args.Error(1)
+}
+
+func (c *ClientMock) Delete(ctx context.Context, in *proto.DeleteLocationRequest, opts ...grpc.CallOption) (res *proto.LocationResponse, err error) {
+	args := c.Called(in)
+
+	if args.Get(0) != nil {
+		res = args.Get(0).(*proto.LocationResponse)
+	}
+
+	return res, args.Error(1)
+}
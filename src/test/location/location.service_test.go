@@ -0,0 +1,273 @@
+package location
+
+import (
+	"context"
+	"github.com/bxcodec/faker/v3"
+	"github.com/pkg/errors"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/dto"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/proto"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"testing"
+)
+
+type LocationServiceTest struct {
+	suite.Suite
+	Location       *proto.Location
+	LocationReq    *proto.Location
+	LocationDto    *dto.LocationDto
+	NotFoundErr    *dto.ResponseErr
+	ServiceDownErr *dto.ResponseErr
+}
+
+func TestLocationService(t *testing.T) {
+	suite.Run(t, new(LocationServiceTest))
+}
+
+func (s *LocationServiceTest) SetupTest() {
+	s.Location = &proto.Location{
+		Id:       1,
+		Address:  faker.Sentence(),
+		District: faker.Word(),
+		Province: faker.Word(),
+		Country:  faker.Word(),
+		Zipcode:  "10330",
+	}
+
+	s.LocationReq = &proto.Location{
+		Address:  s.Location.Address,
+		District: s.Location.District,
+		Province: s.Location.Province,
+		Country:  s.Location.Country,
+		Zipcode:  s.Location.Zipcode,
+	}
+
+	s.LocationDto = &dto.LocationDto{
+		Address:  s.Location.Address,
+		District: s.Location.District,
+		Province: s.Location.Province,
+		Country:  s.Location.Country,
+		Zipcode:  s.Location.Zipcode,
+	}
+
+	s.ServiceDownErr = &dto.ResponseErr{
+		StatusCode: http.StatusServiceUnavailable,
+		Message:    "Service is down",
+		Data:       nil,
+	}
+
+	s.NotFoundErr = &dto.ResponseErr{
+		StatusCode: http.StatusNotFound,
+		Message:    "Not found location",
+		Data:       nil,
+	}
+}
+
+func (s *LocationServiceTest) TestFindOneLocationService() {
+	want := s.Location
+
+	var id int32
+	_ = faker.FakeData(&id)
+
+	client := new(ClientMock)
+
+	client.On("FindOne", &proto.FindOneLocationRequest{Id: id}).Return(&proto.LocationResponse{
+		StatusCode: http.StatusOK,
+		Errors:     nil,
+		Data:       s.Location,
+	}, nil)
+
+	srv := service.NewLocationService(client)
+
+	location, err := srv.FindOne(context.Background(), id)
+
+	assert.Nil(s.T(), err, "Must not got any error")
+	assert.Equal(s.T(), want, location)
+}
+
+func (s *LocationServiceTest) TestFindOneNotFoundLocationService() {
+	want := s.NotFoundErr
+
+	var id int32
+	_ = faker.FakeData(&id)
+
+	client := new(ClientMock)
+
+	client.On("FindOne", &proto.FindOneLocationRequest{Id: id}).Return(&proto.LocationResponse{
+		StatusCode: http.StatusNotFound,
+		Errors:     []string{"Not found location"},
+		Data:       nil,
+	}, nil)
+
+	srv := service.NewLocationService(client)
+
+	location, err := srv.FindOne(context.Background(), id)
+
+	assert.Nil(s.T(), location)
+	assert.Equal(s.T(), want, err)
+}
+
+func (s *LocationServiceTest) TestFindOneGrpcErrLocationService() {
+	want := s.ServiceDownErr
+
+	var id int32
+	_ = faker.FakeData(&id)
+
+	client := new(ClientMock)
+
+	client.On("FindOne", &proto.FindOneLocationRequest{Id: id}).Return(&proto.LocationResponse{}, errors.New("Service is down"))
+
+	srv := service.NewLocationService(client)
+
+	_, err := srv.FindOne(context.Background(), id)
+
+	assert.Equal(s.T(), want, err)
+}
+
+func (s *LocationServiceTest) TestCreateLocationService() {
+	want := s.Location
+
+	client := new(ClientMock)
+
+	client.On("Create", s.LocationReq).Return(&proto.LocationResponse{
+		StatusCode: http.StatusCreated,
+		Errors:     nil,
+		Data:       s.Location,
+	}, nil)
+
+	srv := service.NewLocationService(client)
+
+	location, err := srv.Create(context.Background(), s.LocationDto)
+
+	assert.Nil(s.T(), err, "Must not got any error")
+	assert.Equal(s.T(), want, location)
+}
+
+func (s *LocationServiceTest) TestCreateGrpcErrLocationService() {
+	want := s.ServiceDownErr
+
+	client := new(ClientMock)
+
+	client.On("Create", s.LocationReq).Return(&proto.LocationResponse{}, errors.New("Service is down"))
+
+	srv := service.NewLocationService(client)
+
+	_, err := srv.Create(context.Background(), s.LocationDto)
+
+	assert.Equal(s.T(), want, err)
+}
+
+func (s *LocationServiceTest) TestUpdateLocationService() {
+	want := s.Location
+
+	client := new(ClientMock)
+
+	client.On("Update", s.Location).Return(&proto.LocationResponse{
+		StatusCode: http.StatusOK,
+		Errors:     nil,
+		Data:       s.Location,
+	}, nil)
+
+	srv := service.NewLocationService(client)
+
+	location, err := srv.Update(context.Background(), 1, s.LocationDto)
+
+	assert.Nil(s.T(), err, "Must not got any error")
+	assert.Equal(s.T(), want, location)
+}
+
+func (s *LocationServiceTest) TestUpdateNotFoundLocationService() {
+	want := s.NotFoundErr
+
+	client := new(ClientMock)
+
+	client.On("Update", s.Location).Return(&proto.LocationResponse{
+		StatusCode: http.StatusNotFound,
+		Errors:     []string{"Not found location"},
+		Data:       nil,
+	}, nil)
+
+	srv := service.NewLocationService(client)
+
+	location, err := srv.Update(context.Background(), 1, s.LocationDto)
+
+	assert.Nil(s.T(), location)
+	assert.Equal(s.T(), want, err)
+}
+
+func (s *LocationServiceTest) TestUpdateGrpcErrLocationService() {
+	want := s.ServiceDownErr
+
+	client := new(ClientMock)
+
+	client.On("Update", s.Location).Return(&proto.LocationResponse{}, errors.New("Service is down"))
+
+	srv := service.NewLocationService(client)
+
+	_, err := srv.Update(context.Background(), 1, s.LocationDto)
+
+	assert.Equal(s.T(), want, err)
+}
+
+func (s *LocationServiceTest) TestDeleteLocationService() {
+	want := s.Location
+
+	var id int32
+	_ = faker.FakeData(&id)
+
+	client := new(ClientMock)
+
+	client.On("Delete", &proto.DeleteLocationRequest{Id: id}).Return(&proto.LocationResponse{
+		StatusCode: http.StatusOK,
+		Errors:     nil,
+		Data:       s.Location,
+	}, nil)
+
+	srv := service.NewLocationService(client)
+
+	location, err := srv.Delete(context.Background(), id)
+
+	assert.Nil(s.T(), err, "Must not got any error")
+	assert.Equal(s.T(), want, location)
+}
+
+func (s *LocationServiceTest) TestDeleteNotFoundLocationService() {
+	want := s.NotFoundErr
+
+	var id int32
+	_ = faker.FakeData(&id)
+
+	client := new(ClientMock)
+
+	client.On("Delete", &proto.DeleteLocationRequest{Id: id}).Return(&proto.LocationResponse{
+		StatusCode: http.StatusNotFound,
+		Errors:     []string{"Not found location"},
+		Data:       nil,
+	}, nil)
+
+	srv := service.NewLocationService(client)
+
+	location, err := srv.Delete(context.Background(), id)
+
+	assert.Nil(s.T(), location)
+	assert.Equal(s.T(), want, err)
+}
+
+func (s *LocationServiceTest) TestDeleteGrpcErrLocationService() {
+	want := s.ServiceDownErr
+
+	var id int32
+	_ = faker.FakeData(&id)
+
+	client := new(ClientMock)
+
+	client.On("Delete", &proto.DeleteLocationRequest{Id: id}).Return(&proto.LocationResponse{}, errors.New("Service is down"))
+
+	srv := service.NewLocationService(client)
+
+	_, err := srv.Delete(context.Background(), id)
+
+	assert.Equal(s.T(), want, err)
+}
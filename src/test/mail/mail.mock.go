@@ -0,0 +1,16 @@
+package mail
+
+import (
+	"github.com/samithiwat/samithiwat-backend-gateway/src/dto"
+)
+
+type MailerMock struct {
+	Mails []*dto.Mail
+	Err   error
+}
+
+func (m *MailerMock) Send(mail *dto.Mail) error {
+	m.Mails = append(m.Mails, mail)
+
+	return m.Err
+}
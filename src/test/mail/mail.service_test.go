@@ -0,0 +1,38 @@
+package mail
+
+import (
+	"github.com/samithiwat/samithiwat-backend-gateway/src/dto"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type MailerTest struct {
+	suite.Suite
+	Email string
+}
+
+func TestMailer(t *testing.T) {
+	suite.Run(t, new(MailerTest))
+}
+
+func (u *MailerTest) SetupTest() {
+	u.Email = "smithy@samithiwat.dev"
+}
+
+func (u *MailerTest) TestFileMailer() {
+	path := filepath.Join(u.T().TempDir(), "mail.log")
+
+	mailer := service.NewFileMailer(path, "no-reply@samithiwat.dev")
+
+	assert.Nil(u.T(), mailer.Send(&dto.Mail{To: u.Email, Subject: "Verify your email", Body: "body"}))
+
+	content, err := os.ReadFile(path)
+	assert.Nil(u.T(), err)
+	assert.True(u.T(), strings.Contains(string(content), "To: "+u.Email))
+	assert.True(u.T(), strings.Contains(string(content), "Subject: Verify your email"))
+}
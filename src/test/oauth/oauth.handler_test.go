@@ -0,0 +1,256 @@
+package oauth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base32"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/config"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/dto"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/handler"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/proto"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/service"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/test/activity"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/test/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+type OAuthHandlerTest struct {
+	suite.Suite
+	Query         *dto.OAuthCallback
+	Identity      *dto.OAuthIdentity
+	Credential    *proto.Credential
+	Session       config.Session
+	CookieSession config.Session
+	Sessions      *service.SessionService
+	Store         *service.MemorySessionStore
+}
+
+func TestOAuthHandler(t *testing.T) {
+	suite.Run(t, new(OAuthHandlerTest))
+}
+
+func (u *OAuthHandlerTest) SetupTest() {
+	u.Query = &dto.OAuthCallback{Code: "code", State: "state"}
+
+	u.Identity = &dto.OAuthIdentity{
+		Provider: "google",
+		Subject:  "248289761001",
+		Email:    "smithy@samithiwat.dev",
+	}
+
+	u.Credential = &proto.Credential{
+		AccessToken:  "access",
+		RefreshToken: "refresh",
+		ExpiresIn:    3600,
+	}
+
+	u.Session = config.Session{Mode: "header"}
+
+	u.Store = service.NewMemorySessionStore()
+	u.Sessions = service.NewSessionService(u.Store, time.Hour)
+
+	u.CookieSession = config.Session{
+		Mode:          "cookie",
+		AccessCookie:  "access_token",
+		RefreshCookie: "refresh_token",
+		CsrfCookie:    "csrf_token",
+		RefreshMaxAge: 24 * time.Hour,
+	}
+}
+
+func (u *OAuthHandlerTest) TestLoginRedirect() {
+	srv := new(ServiceMock)
+	authSrv := new(auth.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	c := new(ContextMock)
+
+	c.On("Provider").Return("google")
+	srv.On("AuthorizationUrl", "google").Return("https://accounts.google.com/o/oauth2/auth?state=state", nil)
+
+	h := handler.NewOAuthHandler(srv, authSrv, activitySrv, service.DisabledTwoFactor{}, u.Sessions, u.Session)
+	h.Login(c)
+
+	assert.Equal(u.T(), "https://accounts.google.com/o/oauth2/auth?state=state", c.RedirectTo)
+}
+
+func (u *OAuthHandlerTest) TestLoginUnknownProvider() {
+	want := &dto.ResponseErr{
+		StatusCode: http.StatusNotFound,
+		Message:    "Unknown provider",
+		Data:       nil,
+	}
+
+	srv := new(ServiceMock)
+	authSrv := new(auth.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	c := new(ContextMock)
+
+	c.On("Provider").Return("unknown")
+	srv.On("AuthorizationUrl", "unknown").Return("", want)
+
+	h := handler.NewOAuthHandler(srv, authSrv, activitySrv, service.DisabledTwoFactor{}, u.Sessions, u.Session)
+	h.Login(c)
+
+	assert.Equal(u.T(), want, c.V)
+	assert.Empty(u.T(), c.RedirectTo)
+}
+
+func (u *OAuthHandlerTest) TestCallbackSuccess() {
+	srv := new(ServiceMock)
+	authSrv := new(auth.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	c := &ContextMock{Query: u.Query}
+
+	c.On("Provider").Return("google")
+	c.On("OAuthCallbackQuery", mock.Anything).Return(nil)
+	srv.On("Exchange", "google", u.Query).Return(u.Identity, nil)
+	srv.On("SignIn", u.Identity).Return(u.Credential, nil)
+	authSrv.On("Validate", u.Credential.AccessToken).Return(1, nil)
+	activitySrv.On("Record", proto.LogType_LOGIN, uint32(1), "Login with google").Return()
+
+	h := handler.NewOAuthHandler(srv, authSrv, activitySrv, service.DisabledTwoFactor{}, u.Sessions, u.Session)
+	h.Callback(c)
+
+	assert.Equal(u.T(), u.Credential, c.V)
+	activitySrv.AssertCalled(u.T(), "Record", proto.LogType_LOGIN, uint32(1), "Login with google")
+
+	sessions, _ := u.Store.FindByUser(1)
+	assert.Len(u.T(), sessions, 1)
+}
+
+func (u *OAuthHandlerTest) TestCallbackTwoFactorChallenge() {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	u.Require().Nil(err)
+
+	stub := newStubProvider(key)
+	defer stub.server.Close()
+
+	authSrv := new(auth.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+
+	authSrv.On("Login", mock.Anything).Return(u.Credential, nil)
+	authSrv.On("Validate", u.Credential.AccessToken).Return(1, nil)
+
+	srv := service.NewOAuthService([]service.OAuthProvider{{
+		Name:         "stub",
+		Issuer:       stub.server.URL,
+		ClientID:     "gateway",
+		ClientSecret: "secret",
+		RedirectUrl:  "http://localhost:3000/auth/oauth/stub/callback",
+	}}, authSrv, "account-secret", time.Minute)
+
+	twoFactorSrv, err := service.NewTwoFactorService(service.NewMemoryTotpStore(), service.NewLockoutService(service.NewMemoryLockoutStore(), nil), []byte("0123456789abcdef0123456789abcdef"), "Samithiwat.dev", 5*time.Minute, 4)
+	u.Require().Nil(err)
+
+	setup, errRes := twoFactorSrv.Setup(context.Background(), 1, "Smithy")
+	u.Require().Nil(errRes)
+
+	secret, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(setup.Secret)
+	u.Require().Nil(err)
+
+	_, errRes = twoFactorSrv.Confirm(context.Background(), 1, service.TotpCode(secret, time.Now().Unix()/30))
+	u.Require().Nil(errRes)
+
+	location, errRes := srv.AuthorizationUrl("stub")
+	u.Require().Nil(errRes)
+
+	authorize, err := url.Parse(location)
+	u.Require().Nil(err)
+
+	stub.challenge = authorize.Query().Get("code_challenge")
+	stub.nonce = authorize.Query().Get("nonce")
+
+	c := &ContextMock{Query: &dto.OAuthCallback{Code: "code", State: authorize.Query().Get("state")}}
+
+	c.On("Provider").Return("stub")
+	c.On("OAuthCallbackQuery", mock.Anything).Return(nil)
+
+	h := handler.NewOAuthHandler(srv, authSrv, activitySrv, twoFactorSrv, u.Sessions, u.Session)
+	h.Callback(c)
+
+	challenge, ok := c.V.(*dto.TwoFactorChallenge)
+
+	assert.True(u.T(), ok)
+	assert.NotEmpty(u.T(), challenge.ChallengeToken)
+	activitySrv.AssertNotCalled(u.T(), "Record", mock.Anything, mock.Anything, mock.Anything)
+
+	sessions, _ := u.Store.FindByUser(1)
+	assert.Len(u.T(), sessions, 0)
+}
+
+func (u *OAuthHandlerTest) TestCallbackCookieSession() {
+	srv := new(ServiceMock)
+	authSrv := new(auth.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	c := &ContextMock{Query: u.Query}
+
+	c.On("Provider").Return("google")
+	c.On("OAuthCallbackQuery", mock.Anything).Return(nil)
+	srv.On("Exchange", "google", u.Query).Return(u.Identity, nil)
+	srv.On("SignIn", u.Identity).Return(u.Credential, nil)
+	authSrv.On("Validate", u.Credential.AccessToken).Return(1, nil)
+	activitySrv.On("Record", mock.Anything, mock.Anything, mock.Anything).Return()
+
+	h := handler.NewOAuthHandler(srv, authSrv, activitySrv, service.DisabledTwoFactor{}, u.Sessions, u.CookieSession)
+	h.Callback(c)
+
+	session, ok := c.V.(*dto.Session)
+	assert.True(u.T(), ok)
+	assert.Equal(u.T(), u.Credential.ExpiresIn, session.ExpiresIn)
+	assert.Equal(u.T(), u.Credential.AccessToken, c.Cookies["access_token"].Value)
+	assert.Equal(u.T(), u.Credential.RefreshToken, c.Cookies["refresh_token"].Value)
+	assert.Equal(u.T(), session.CsrfToken, c.Cookies["csrf_token"].Value)
+}
+
+func (u *OAuthHandlerTest) TestCallbackInvalidIdToken() {
+	want := &dto.ResponseErr{
+		StatusCode: http.StatusUnauthorized,
+		Message:    "Invalid ID token",
+		Data:       nil,
+	}
+
+	srv := new(ServiceMock)
+	authSrv := new(auth.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	c := &ContextMock{Query: u.Query}
+
+	c.On("Provider").Return("google")
+	c.On("OAuthCallbackQuery", mock.Anything).Return(nil)
+	srv.On("Exchange", "google", u.Query).Return(nil, want)
+
+	h := handler.NewOAuthHandler(srv, authSrv, activitySrv, service.DisabledTwoFactor{}, u.Sessions, u.Session)
+	h.Callback(c)
+
+	assert.Equal(u.T(), want, c.V)
+	srv.AssertNotCalled(u.T(), "SignIn", mock.Anything)
+}
+
+func (u *OAuthHandlerTest) TestCallbackEmailConflict() {
+	want := &dto.ResponseErr{
+		StatusCode: http.StatusConflict,
+		Message:    "Email is already registered with a password",
+		Data:       nil,
+	}
+
+	srv := new(ServiceMock)
+	authSrv := new(auth.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	c := &ContextMock{Query: u.Query}
+
+	c.On("Provider").Return("google")
+	c.On("OAuthCallbackQuery", mock.Anything).Return(nil)
+	srv.On("Exchange", "google", u.Query).Return(u.Identity, nil)
+	srv.On("SignIn", u.Identity).Return(nil, want)
+
+	h := handler.NewOAuthHandler(srv, authSrv, activitySrv, service.DisabledTwoFactor{}, u.Sessions, u.Session)
+	h.Callback(c)
+
+	assert.Equal(u.T(), want, c.V)
+}
@@ -0,0 +1,102 @@
+package oauth
+
+import (
+	"context"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/dto"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/proto"
+	"github.com/stretchr/testify/mock"
+)
+
+type ContextMock struct {
+	mock.Mock
+	V          interface{}
+	Query      *dto.OAuthCallback
+	RedirectTo string
+	Cookies    map[string]*dto.Cookie
+	UserAgent  string
+	ClientIP   string
+}
+
+func (c *ContextMock) Provider() string {
+	args := c.Called()
+
+	return args.String(0)
+}
+
+func (c *ContextMock) OAuthCallbackQuery(query *dto.OAuthCallback) error {
+	args := c.Called(query)
+
+	if c.Query != nil {
+		*query = *c.Query
+	}
+
+	return args.Error(0)
+}
+
+func (c *ContextMock) Redirect(location string, _ ...int) error {
+	c.RedirectTo = location
+
+	return nil
+}
+
+func (c *ContextMock) JSON(_ int, v interface{}) {
+	c.V = v
+}
+
+func (c *ContextMock) SetCookie(cookie *dto.Cookie) {
+	if c.Cookies == nil {
+		c.Cookies = map[string]*dto.Cookie{}
+	}
+
+	c.Cookies[cookie.Name] = cookie
+}
+
+func (c *ContextMock) Device() *dto.Device {
+	return &dto.Device{UserAgent: c.UserAgent, IP: c.ClientIP}
+}
+
+type ServiceMock struct {
+	mock.Mock
+}
+
+func (s *ServiceMock) AuthorizationUrl(provider string) (string, *dto.ResponseErr) {
+	args := s.Called(provider)
+
+	if args.Get(1) != nil {
+		return args.String(0), args.Get(1).(*dto.ResponseErr)
+	}
+
+	return args.String(0), nil
+}
+
+func (s *ServiceMock) Exchange(provider string, query *dto.OAuthCallback) (res *dto.OAuthIdentity, err *dto.ResponseErr) {
+	args := s.Called(provider, query)
+
+	if args.Get(0) != nil {
+		res = args.Get(0).(*dto.OAuthIdentity)
+	}
+
+	if args.Get(1) != nil {
+		err = args.Get(1).(*dto.ResponseErr)
+	}
+
+	return
+}
+
+func (s *ServiceMock) SignIn(_ context.Context, identity *dto.OAuthIdentity) (res *proto.Credential, err *dto.ResponseErr) {
+	args := s.Called(identity)
+
+	if args.Get(0) != nil {
+		res = args.Get(0).(*proto.Credential)
+	}
+
+	if args.Get(1) != nil {
+		err = args.Get(1).(*dto.ResponseErr)
+	}
+
+	return
+}
+
+func (c *ContextMock) UserContext() context.Context {
+	return context.Background()
+}
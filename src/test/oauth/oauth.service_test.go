@@ -0,0 +1,344 @@
+package oauth
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/dto"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/proto"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/service"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/test/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// stubProvider is a local OpenID Connect provider, it only redeems the code when the verifier matches the last challenge
+type stubProvider struct {
+	server      *httptest.Server
+	key         *rsa.PrivateKey
+	challenge   string
+	nonce       string
+	claims      map[string]interface{}
+	discoveries int32
+}
+
+func newStubProvider(key *rsa.PrivateKey) *stubProvider {
+	p := &stubProvider{key: key, claims: map[string]interface{}{}}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&p.discoveries, 1)
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 p.server.URL,
+			"authorization_endpoint": p.server.URL + "/authorize",
+			"token_endpoint":         p.server.URL + "/token",
+			"jwks_uri":               p.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]interface{}{{
+				"kid": "stub",
+				"kty": "RSA",
+				"alg": "RS256",
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+
+		challenge := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+		if r.PostForm.Get("grant_type") != "authorization_code" || r.PostForm.Get("code") != "code" ||
+			r.PostForm.Get("client_id") != "gateway" ||
+			base64.RawURLEncoding.EncodeToString(challenge[:]) != p.challenge {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":"invalid_grant"}`))
+			return
+		}
+
+		_ = json.NewEncoder(w).Encode(map[string]string{"id_token": p.idToken()})
+	})
+
+	p.server = httptest.NewServer(mux)
+
+	return p
+}
+
+func (p *stubProvider) idToken() string {
+	claims := map[string]interface{}{
+		"iss":            p.server.URL,
+		"aud":            "gateway",
+		"sub":            "248289761001",
+		"email":          "smithy@samithiwat.dev",
+		"email_verified": true,
+		"given_name":     "Samithiwat",
+		"family_name":    "Boonchai",
+		"name":           "Smithy",
+		"picture":        "https://samithiwat.dev/smithy.png",
+		"nonce":          p.nonce,
+		"iat":            time.Now().Unix(),
+		"exp":            time.Now().Add(time.Hour).Unix(),
+	}
+	for k, v := range p.claims {
+		claims[k] = v
+	}
+
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "kid": "stub", "typ": "JWT"})
+	payload, _ := json.Marshal(claims)
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+
+	digest := sha256.Sum256([]byte(signed))
+	sig, _ := rsa.SignPKCS1v15(rand.Reader, p.key, crypto.SHA256, digest[:])
+
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+type OAuthServiceTest struct {
+	suite.Suite
+	Key        *rsa.PrivateKey
+	Stub       *stubProvider
+	Accounts   *auth.ServiceMock
+	Credential *proto.Credential
+	Identity   *dto.OAuthIdentity
+}
+
+func TestOAuthService(t *testing.T) {
+	suite.Run(t, new(OAuthServiceTest))
+}
+
+func (s *OAuthServiceTest) SetupSuite() {
+	s.Key, _ = rsa.GenerateKey(rand.Reader, 2048)
+}
+
+func (s *OAuthServiceTest) SetupTest() {
+	s.Stub = newStubProvider(s.Key)
+	s.Accounts = new(auth.ServiceMock)
+
+	s.Credential = &proto.Credential{
+		AccessToken:  "access",
+		RefreshToken: "refresh",
+		ExpiresIn:    3600,
+	}
+
+	s.Identity = &dto.OAuthIdentity{
+		Provider:    "stub",
+		Subject:     "248289761001",
+		Email:       "smithy@samithiwat.dev",
+		Firstname:   "Samithiwat",
+		Lastname:    "Boonchai",
+		DisplayName: "Smithy",
+		ImageUrl:    "https://samithiwat.dev/smithy.png",
+	}
+}
+
+func (s *OAuthServiceTest) TearDownTest() {
+	s.Stub.server.Close()
+}
+
+func (s *OAuthServiceTest) newService() *service.OAuthService {
+	return service.NewOAuthService([]service.OAuthProvider{{
+		Name:         "stub",
+		Issuer:       s.Stub.server.URL,
+		ClientID:     "gateway",
+		ClientSecret: "secret",
+		RedirectUrl:  "http://localhost:3000/auth/oauth/stub/callback",
+	}}, s.Accounts, "account-secret", time.Minute)
+}
+
+// start begins the login like the browser would and returns the state that the provider sends back
+func (s *OAuthServiceTest) start(srv *service.OAuthService) string {
+	location, errRes := srv.AuthorizationUrl("stub")
+	s.Require().Nil(errRes)
+
+	u, err := url.Parse(location)
+	s.Require().Nil(err)
+
+	s.Stub.challenge = u.Query().Get("code_challenge")
+	s.Stub.nonce = u.Query().Get("nonce")
+
+	return u.Query().Get("state")
+}
+
+func (s *OAuthServiceTest) TestAuthorizationUrl() {
+	srv := s.newService()
+
+	location, errRes := srv.AuthorizationUrl("stub")
+
+	assert.Nil(s.T(), errRes)
+	assert.True(s.T(), strings.HasPrefix(location, s.Stub.server.URL+"/authorize?"))
+
+	u, _ := url.Parse(location)
+	q := u.Query()
+	assert.Equal(s.T(), "code", q.Get("response_type"))
+	assert.Equal(s.T(), "gateway", q.Get("client_id"))
+	assert.Equal(s.T(), "openid email profile", q.Get("scope"))
+	assert.Equal(s.T(), "S256", q.Get("code_challenge_method"))
+	assert.NotEmpty(s.T(), q.Get("code_challenge"))
+	assert.NotEmpty(s.T(), q.Get("state"))
+	assert.NotEmpty(s.T(), q.Get("nonce"))
+
+	_, _ = srv.AuthorizationUrl("stub")
+	assert.Equal(s.T(), int32(1), atomic.LoadInt32(&s.Stub.discoveries))
+}
+
+func (s *OAuthServiceTest) TestUnknownProvider() {
+	srv := s.newService()
+
+	_, errRes := srv.AuthorizationUrl("unknown")
+
+	assert.Equal(s.T(), http.StatusNotFound, errRes.StatusCode)
+}
+
+func (s *OAuthServiceTest) TestExchangeSuccess() {
+	srv := s.newService()
+	state := s.start(srv)
+
+	identity, errRes := srv.Exchange("stub", &dto.OAuthCallback{Code: "code", State: state})
+
+	assert.Nil(s.T(), errRes)
+	assert.Equal(s.T(), s.Identity, identity)
+}
+
+func (s *OAuthServiceTest) TestExchangeReplayedState() {
+	srv := s.newService()
+	state := s.start(srv)
+
+	_, errRes := srv.Exchange("stub", &dto.OAuthCallback{Code: "code", State: state})
+	assert.Nil(s.T(), errRes)
+
+	_, errRes = srv.Exchange("stub", &dto.OAuthCallback{Code: "code", State: state})
+	assert.Equal(s.T(), http.StatusBadRequest, errRes.StatusCode)
+}
+
+func (s *OAuthServiceTest) TestExchangeUnknownState() {
+	srv := s.newService()
+	s.start(srv)
+
+	_, errRes := srv.Exchange("stub", &dto.OAuthCallback{Code: "code", State: "forged"})
+
+	assert.Equal(s.T(), http.StatusBadRequest, errRes.StatusCode)
+}
+
+func (s *OAuthServiceTest) TestExchangeWrongVerifier() {
+	srv := s.newService()
+	state := s.start(srv)
+	s.Stub.challenge = "another-challenge"
+
+	_, errRes := srv.Exchange("stub", &dto.OAuthCallback{Code: "code", State: state})
+
+	assert.Equal(s.T(), http.StatusUnauthorized, errRes.StatusCode)
+	assert.Equal(s.T(), "Cannot redeem the code", errRes.Message)
+}
+
+func (s *OAuthServiceTest) TestExchangeInvalidNonce() {
+	srv := s.newService()
+	state := s.start(srv)
+	s.Stub.claims["nonce"] = "another-nonce"
+
+	_, errRes := srv.Exchange("stub", &dto.OAuthCallback{Code: "code", State: state})
+
+	assert.Equal(s.T(), http.StatusUnauthorized, errRes.StatusCode)
+	assert.Equal(s.T(), "Invalid ID token", errRes.Message)
+}
+
+func (s *OAuthServiceTest) TestExchangeInvalidAudience() {
+	srv := s.newService()
+	state := s.start(srv)
+	s.Stub.claims["aud"] = "another-client"
+
+	_, errRes := srv.Exchange("stub", &dto.OAuthCallback{Code: "code", State: state})
+
+	assert.Equal(s.T(), http.StatusUnauthorized, errRes.StatusCode)
+}
+
+func (s *OAuthServiceTest) TestExchangeEmailNotVerified() {
+	srv := s.newService()
+	state := s.start(srv)
+	s.Stub.claims["email_verified"] = false
+
+	_, errRes := srv.Exchange("stub", &dto.OAuthCallback{Code: "code", State: state})
+
+	assert.Equal(s.T(), http.StatusForbidden, errRes.StatusCode)
+}
+
+func (s *OAuthServiceTest) TestExchangeDenied() {
+	srv := s.newService()
+	state := s.start(srv)
+
+	_, errRes := srv.Exchange("stub", &dto.OAuthCallback{State: state, Error: "access_denied"})
+
+	assert.Equal(s.T(), http.StatusUnauthorized, errRes.StatusCode)
+	assert.Equal(s.T(), "access_denied", errRes.Data)
+}
+
+func (s *OAuthServiceTest) TestSignInExistingAccount() {
+	var passwords []string
+	s.Accounts.On("Login", mock.Anything).Run(func(args mock.Arguments) {
+		login := args.Get(0).(*dto.Login)
+		assert.Equal(s.T(), s.Identity.Email, login.Email)
+		passwords = append(passwords, login.Password)
+	}).Return(s.Credential, nil)
+
+	srv := s.newService()
+
+	credential, errRes := srv.SignIn(context.Background(), s.Identity)
+	_, _ = srv.SignIn(context.Background(), s.Identity)
+
+	assert.Nil(s.T(), errRes)
+	assert.Equal(s.T(), s.Credential, credential)
+	assert.Len(s.T(), passwords, 2)
+	assert.Equal(s.T(), passwords[0], passwords[1])
+	assert.True(s.T(), len(passwords[0]) >= 8)
+	s.Accounts.AssertNotCalled(s.T(), "Register", mock.Anything)
+}
+
+func (s *OAuthServiceTest) TestSignInFirstLogin() {
+	unauthorized := &dto.ResponseErr{StatusCode: http.StatusUnauthorized, Message: "Invalid email or password"}
+
+	s.Accounts.On("Login", mock.Anything).Return(nil, unauthorized).Once()
+	s.Accounts.On("Register", mock.Anything).Return(&proto.User{Id: 1}, nil)
+	s.Accounts.On("Login", mock.Anything).Return(s.Credential, nil).Once()
+
+	srv := s.newService()
+
+	credential, errRes := srv.SignIn(context.Background(), s.Identity)
+
+	assert.Nil(s.T(), errRes)
+	assert.Equal(s.T(), s.Credential, credential)
+
+	register := s.Accounts.Calls[1].Arguments.Get(0).(*dto.Register)
+	assert.Equal(s.T(), s.Identity.Email, register.Email)
+	assert.Equal(s.T(), s.Identity.DisplayName, register.DisplayName)
+	assert.Equal(s.T(), s.Accounts.Calls[0].Arguments.Get(0).(*dto.Login).Password, register.Password)
+}
+
+func (s *OAuthServiceTest) TestSignInEmailRegisteredWithPassword() {
+	unauthorized := &dto.ResponseErr{StatusCode: http.StatusUnauthorized, Message: "Invalid email or password"}
+	duplicated := &dto.ResponseErr{StatusCode: http.StatusUnprocessableEntity, Message: "Email is already existed"}
+
+	s.Accounts.On("Login", mock.Anything).Return(nil, unauthorized)
+	s.Accounts.On("Register", mock.Anything).Return(nil, duplicated)
+
+	srv := s.newService()
+
+	_, errRes := srv.SignIn(context.Background(), s.Identity)
+
+	assert.Equal(s.T(), http.StatusConflict, errRes.StatusCode)
+	s.Accounts.AssertNumberOfCalls(s.T(), "Login", 1)
+}
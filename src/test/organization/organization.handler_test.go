@@ -3,13 +3,21 @@ package organization
 import (
 	"github.com/bxcodec/faker/v3"
 	"github.com/pkg/errors"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/constant"
 	"github.com/samithiwat/samithiwat-backend-gateway/src/dto"
 	"github.com/samithiwat/samithiwat-backend-gateway/src/handler"
 	"github.com/samithiwat/samithiwat-backend-gateway/src/proto"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/test/activity"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/test/contact"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/test/location"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/test/role"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/test/user"
 	"github.com/samithiwat/samithiwat-backend-gateway/src/validator"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 	"net/http"
+	"strconv"
+	"strings"
 	"testing"
 )
 
@@ -22,6 +30,12 @@ type OrganizationHandlerTest struct {
 	NotFoundErr     *dto.ResponseErr
 	ServiceDownErr  *dto.ResponseErr
 	InvalidIDErr    *dto.ResponseErr
+	Contact         *proto.Contact
+	ContactDto      *dto.ContactDto
+	Location        *proto.Location
+	LocationDto     *dto.LocationDto
+	Member          *proto.User
+	Role            *proto.Role
 }
 
 func TestOrganizationHandler(t *testing.T) {
@@ -59,7 +73,12 @@ func (u *OrganizationHandlerTest) SetupTest() {
 
 	u.Organizations = append(u.Organizations, u.Organization, Organization2, Organization3, Organization4)
 
-	_ = faker.FakeData(&u.OrganizationDto)
+	u.OrganizationDto = &dto.OrganizationDto{
+		Name:        faker.Word(),
+		Email:       faker.Email(),
+		Description: faker.Sentence(),
+	}
+
 	_ = faker.FakeData(&u.Query)
 
 	u.ServiceDownErr = &dto.ResponseErr{
@@ -78,6 +97,51 @@ func (u *OrganizationHandlerTest) SetupTest() {
 		StatusCode: http.StatusBadRequest,
 		Message:    "Invalid ID",
 	}
+
+	u.Contact = &proto.Contact{
+		Id:        1,
+		Facebook:  faker.URL(),
+		Instagram: faker.URL(),
+		Twitter:   faker.URL(),
+		Linkedin:  faker.URL(),
+	}
+
+	u.ContactDto = &dto.ContactDto{
+		Facebook:  u.Contact.Facebook,
+		Instagram: u.Contact.Instagram,
+		Twitter:   u.Contact.Twitter,
+		Linkedin:  u.Contact.Linkedin,
+	}
+
+	u.Location = &proto.Location{
+		Id:       1,
+		Address:  faker.Sentence(),
+		District: faker.Word(),
+		Province: faker.Word(),
+		Country:  faker.Word(),
+		Zipcode:  "10330",
+	}
+
+	u.LocationDto = &dto.LocationDto{
+		Address:  u.Location.Address,
+		District: u.Location.District,
+		Province: u.Location.Province,
+		Country:  u.Location.Country,
+		Zipcode:  u.Location.Zipcode,
+	}
+
+	u.Member = &proto.User{
+		Id:        5,
+		Firstname: faker.FirstName(),
+		Lastname:  faker.LastName(),
+		ImageUrl:  faker.URL(),
+	}
+
+	u.Role = &proto.Role{
+		Id:          2,
+		Name:        faker.Word(),
+		Description: faker.Sentence(),
+	}
 }
 
 func (u *OrganizationHandlerTest) TestFindAllOrganization() {
@@ -93,6 +157,11 @@ func (u *OrganizationHandlerTest) TestFindAllOrganization() {
 	}
 
 	srv := new(OrganizationServiceMock)
+	userSrv := new(user.ServiceMock)
+	roleSrv := new(role.ServiceMock)
+	contactSrv := new(contact.ServiceMock)
+	locationSrv := new(location.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
 	c := &ContextMock{
 		Organization:    u.Organization,
 		Organizations:   u.Organizations,
@@ -105,7 +174,7 @@ func (u *OrganizationHandlerTest) TestFindAllOrganization() {
 
 	v, _ := validator.NewValidator()
 
-	h := handler.NewOrganizationHandler(srv, v)
+	h := handler.NewOrganizationHandler(srv, userSrv, roleSrv, contactSrv, locationSrv, activitySrv, v)
 	h.FindAll(c)
 
 	assert.Equal(u.T(), want, c.V)
@@ -118,6 +187,11 @@ func (u *OrganizationHandlerTest) TestFindAllInvalidQueryParamOrganization() {
 	}
 
 	srv := new(OrganizationServiceMock)
+	userSrv := new(user.ServiceMock)
+	roleSrv := new(role.ServiceMock)
+	contactSrv := new(contact.ServiceMock)
+	locationSrv := new(location.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
 	c := &ContextMock{
 		Organization:    u.Organization,
 		Organizations:   u.Organizations,
@@ -130,7 +204,7 @@ func (u *OrganizationHandlerTest) TestFindAllInvalidQueryParamOrganization() {
 
 	v, _ := validator.NewValidator()
 
-	h := handler.NewOrganizationHandler(srv, v)
+	h := handler.NewOrganizationHandler(srv, userSrv, roleSrv, contactSrv, locationSrv, activitySrv, v)
 
 	h.FindAll(c)
 
@@ -141,6 +215,11 @@ func (u *OrganizationHandlerTest) TestFindAllGrpcErrOrganization() {
 	want := u.ServiceDownErr
 
 	srv := new(OrganizationServiceMock)
+	userSrv := new(user.ServiceMock)
+	roleSrv := new(role.ServiceMock)
+	contactSrv := new(contact.ServiceMock)
+	locationSrv := new(location.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
 	c := &ContextMock{
 		Organization:    u.Organization,
 		Organizations:   u.Organizations,
@@ -153,7 +232,7 @@ func (u *OrganizationHandlerTest) TestFindAllGrpcErrOrganization() {
 
 	v, _ := validator.NewValidator()
 
-	h := handler.NewOrganizationHandler(srv, v)
+	h := handler.NewOrganizationHandler(srv, userSrv, roleSrv, contactSrv, locationSrv, activitySrv, v)
 
 	h.FindAll(c)
 
@@ -164,6 +243,11 @@ func (u *OrganizationHandlerTest) TestFindOneOrganization() {
 	want := u.Organization
 
 	srv := new(OrganizationServiceMock)
+	userSrv := new(user.ServiceMock)
+	roleSrv := new(role.ServiceMock)
+	contactSrv := new(contact.ServiceMock)
+	locationSrv := new(location.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
 	c := &ContextMock{
 		Organization:    u.Organization,
 		Organizations:   u.Organizations,
@@ -176,7 +260,7 @@ func (u *OrganizationHandlerTest) TestFindOneOrganization() {
 
 	v, _ := validator.NewValidator()
 
-	h := handler.NewOrganizationHandler(srv, v)
+	h := handler.NewOrganizationHandler(srv, userSrv, roleSrv, contactSrv, locationSrv, activitySrv, v)
 
 	h.FindOne(c)
 
@@ -187,6 +271,11 @@ func (u *OrganizationHandlerTest) TestFindOneInvalidRequestParamIDOrganization()
 	want := u.InvalidIDErr
 
 	srv := new(OrganizationServiceMock)
+	userSrv := new(user.ServiceMock)
+	roleSrv := new(role.ServiceMock)
+	contactSrv := new(contact.ServiceMock)
+	locationSrv := new(location.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
 	c := &ContextMock{
 		Organization:    u.Organization,
 		Organizations:   u.Organizations,
@@ -199,7 +288,7 @@ func (u *OrganizationHandlerTest) TestFindOneInvalidRequestParamIDOrganization()
 
 	v, _ := validator.NewValidator()
 
-	h := handler.NewOrganizationHandler(srv, v)
+	h := handler.NewOrganizationHandler(srv, userSrv, roleSrv, contactSrv, locationSrv, activitySrv, v)
 	h.FindOne(c)
 
 	assert.Equal(u.T(), want, c.V)
@@ -209,6 +298,11 @@ func (u *OrganizationHandlerTest) TestFindOneErrorNotFoundOrganization() {
 	want := u.NotFoundErr
 
 	srv := new(OrganizationServiceMock)
+	userSrv := new(user.ServiceMock)
+	roleSrv := new(role.ServiceMock)
+	contactSrv := new(contact.ServiceMock)
+	locationSrv := new(location.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
 	c := &ContextMock{
 		Organization:    u.Organization,
 		Organizations:   u.Organizations,
@@ -221,7 +315,7 @@ func (u *OrganizationHandlerTest) TestFindOneErrorNotFoundOrganization() {
 
 	v, _ := validator.NewValidator()
 
-	h := handler.NewOrganizationHandler(srv, v)
+	h := handler.NewOrganizationHandler(srv, userSrv, roleSrv, contactSrv, locationSrv, activitySrv, v)
 
 	h.FindOne(c)
 
@@ -232,6 +326,11 @@ func (u *OrganizationHandlerTest) TestFindOneGrpcErrOrganization() {
 	want := u.ServiceDownErr
 
 	srv := new(OrganizationServiceMock)
+	userSrv := new(user.ServiceMock)
+	roleSrv := new(role.ServiceMock)
+	contactSrv := new(contact.ServiceMock)
+	locationSrv := new(location.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
 	c := &ContextMock{
 		Organization:    u.Organization,
 		Organizations:   u.Organizations,
@@ -244,7 +343,7 @@ func (u *OrganizationHandlerTest) TestFindOneGrpcErrOrganization() {
 
 	v, _ := validator.NewValidator()
 
-	h := handler.NewOrganizationHandler(srv, v)
+	h := handler.NewOrganizationHandler(srv, userSrv, roleSrv, contactSrv, locationSrv, activitySrv, v)
 
 	h.FindOne(c)
 
@@ -255,6 +354,11 @@ func (u *OrganizationHandlerTest) TestCreateOrganization() {
 	want := u.Organization
 
 	srv := new(OrganizationServiceMock)
+	userSrv := new(user.ServiceMock)
+	roleSrv := new(role.ServiceMock)
+	contactSrv := new(contact.ServiceMock)
+	locationSrv := new(location.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
 	c := &ContextMock{
 		Organization:    u.Organization,
 		Organizations:   u.Organizations,
@@ -267,7 +371,7 @@ func (u *OrganizationHandlerTest) TestCreateOrganization() {
 
 	v, _ := validator.NewValidator()
 
-	h := handler.NewOrganizationHandler(srv, v)
+	h := handler.NewOrganizationHandler(srv, userSrv, roleSrv, contactSrv, locationSrv, activitySrv, v)
 	h.Create(c)
 
 	assert.Equal(u.T(), want, c.V)
@@ -280,6 +384,11 @@ func (u *OrganizationHandlerTest) TestCreateErrorDuplicatedOrganization() {
 	}
 
 	srv := new(OrganizationServiceMock)
+	userSrv := new(user.ServiceMock)
+	roleSrv := new(role.ServiceMock)
+	contactSrv := new(contact.ServiceMock)
+	locationSrv := new(location.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
 	c := &ContextMock{
 		Organization:    u.Organization,
 		Organizations:   u.Organizations,
@@ -292,7 +401,7 @@ func (u *OrganizationHandlerTest) TestCreateErrorDuplicatedOrganization() {
 
 	v, _ := validator.NewValidator()
 
-	h := handler.NewOrganizationHandler(srv, v)
+	h := handler.NewOrganizationHandler(srv, userSrv, roleSrv, contactSrv, locationSrv, activitySrv, v)
 	h.Create(c)
 
 	assert.Equal(u.T(), want, c.V)
@@ -305,6 +414,11 @@ func (u *OrganizationHandlerTest) TestCreateInvalidBodyRequest() {
 	}
 
 	srv := new(OrganizationServiceMock)
+	userSrv := new(user.ServiceMock)
+	roleSrv := new(role.ServiceMock)
+	contactSrv := new(contact.ServiceMock)
+	locationSrv := new(location.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
 	c := &ContextMock{
 		Organization:    u.Organization,
 		Organizations:   u.Organizations,
@@ -317,7 +431,7 @@ func (u *OrganizationHandlerTest) TestCreateInvalidBodyRequest() {
 
 	v, _ := validator.NewValidator()
 
-	h := handler.NewOrganizationHandler(srv, v)
+	h := handler.NewOrganizationHandler(srv, userSrv, roleSrv, contactSrv, locationSrv, activitySrv, v)
 	h.Create(c)
 
 	assert.Equal(u.T(), want, c.V)
@@ -327,6 +441,11 @@ func (u *OrganizationHandlerTest) TestCreateGrpcErrOrganization() {
 	want := u.ServiceDownErr
 
 	srv := new(OrganizationServiceMock)
+	userSrv := new(user.ServiceMock)
+	roleSrv := new(role.ServiceMock)
+	contactSrv := new(contact.ServiceMock)
+	locationSrv := new(location.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
 	c := &ContextMock{
 		Organization:    u.Organization,
 		Organizations:   u.Organizations,
@@ -339,7 +458,7 @@ func (u *OrganizationHandlerTest) TestCreateGrpcErrOrganization() {
 
 	v, _ := validator.NewValidator()
 
-	h := handler.NewOrganizationHandler(srv, v)
+	h := handler.NewOrganizationHandler(srv, userSrv, roleSrv, contactSrv, locationSrv, activitySrv, v)
 
 	h.Create(c)
 
@@ -350,6 +469,11 @@ func (u *OrganizationHandlerTest) TestUpdateOrganization() {
 	want := u.Organization
 
 	srv := new(OrganizationServiceMock)
+	userSrv := new(user.ServiceMock)
+	roleSrv := new(role.ServiceMock)
+	contactSrv := new(contact.ServiceMock)
+	locationSrv := new(location.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
 	c := &ContextMock{
 		Organization:    u.Organization,
 		Organizations:   u.Organizations,
@@ -363,7 +487,7 @@ func (u *OrganizationHandlerTest) TestUpdateOrganization() {
 
 	v, _ := validator.NewValidator()
 
-	h := handler.NewOrganizationHandler(srv, v)
+	h := handler.NewOrganizationHandler(srv, userSrv, roleSrv, contactSrv, locationSrv, activitySrv, v)
 
 	h.Update(c)
 
@@ -374,6 +498,11 @@ func (u *OrganizationHandlerTest) TestUpdateInvalidRequestParamIDOrganization()
 	want := u.InvalidIDErr
 
 	srv := new(OrganizationServiceMock)
+	userSrv := new(user.ServiceMock)
+	roleSrv := new(role.ServiceMock)
+	contactSrv := new(contact.ServiceMock)
+	locationSrv := new(location.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
 	c := &ContextMock{
 		Organization:    u.Organization,
 		Organizations:   u.Organizations,
@@ -387,7 +516,7 @@ func (u *OrganizationHandlerTest) TestUpdateInvalidRequestParamIDOrganization()
 
 	v, _ := validator.NewValidator()
 
-	h := handler.NewOrganizationHandler(srv, v)
+	h := handler.NewOrganizationHandler(srv, userSrv, roleSrv, contactSrv, locationSrv, activitySrv, v)
 
 	h.Update(c)
 
@@ -401,6 +530,11 @@ func (u *OrganizationHandlerTest) TestUpdateInvalidBodyRequest() {
 	}
 
 	srv := new(OrganizationServiceMock)
+	userSrv := new(user.ServiceMock)
+	roleSrv := new(role.ServiceMock)
+	contactSrv := new(contact.ServiceMock)
+	locationSrv := new(location.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
 	c := &ContextMock{
 		Organization:    u.Organization,
 		Organizations:   u.Organizations,
@@ -414,7 +548,7 @@ func (u *OrganizationHandlerTest) TestUpdateInvalidBodyRequest() {
 
 	v, _ := validator.NewValidator()
 
-	h := handler.NewOrganizationHandler(srv, v)
+	h := handler.NewOrganizationHandler(srv, userSrv, roleSrv, contactSrv, locationSrv, activitySrv, v)
 	h.Create(c)
 
 	assert.Equal(u.T(), want, c.V)
@@ -424,6 +558,11 @@ func (u *OrganizationHandlerTest) TestUpdateErrorNotFoundOrganization() {
 	want := u.NotFoundErr
 
 	srv := new(OrganizationServiceMock)
+	userSrv := new(user.ServiceMock)
+	roleSrv := new(role.ServiceMock)
+	contactSrv := new(contact.ServiceMock)
+	locationSrv := new(location.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
 	c := &ContextMock{
 		Organization:    u.Organization,
 		Organizations:   u.Organizations,
@@ -437,7 +576,7 @@ func (u *OrganizationHandlerTest) TestUpdateErrorNotFoundOrganization() {
 
 	v, _ := validator.NewValidator()
 
-	h := handler.NewOrganizationHandler(srv, v)
+	h := handler.NewOrganizationHandler(srv, userSrv, roleSrv, contactSrv, locationSrv, activitySrv, v)
 	h.Update(c)
 
 	assert.Equal(u.T(), want, c.V)
@@ -447,6 +586,11 @@ func (u *OrganizationHandlerTest) TestUpdateGrpcErrOrganization() {
 	want := u.ServiceDownErr
 
 	srv := new(OrganizationServiceMock)
+	userSrv := new(user.ServiceMock)
+	roleSrv := new(role.ServiceMock)
+	contactSrv := new(contact.ServiceMock)
+	locationSrv := new(location.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
 	c := &ContextMock{
 		Organization:    u.Organization,
 		Organizations:   u.Organizations,
@@ -460,7 +604,7 @@ func (u *OrganizationHandlerTest) TestUpdateGrpcErrOrganization() {
 
 	v, _ := validator.NewValidator()
 
-	h := handler.NewOrganizationHandler(srv, v)
+	h := handler.NewOrganizationHandler(srv, userSrv, roleSrv, contactSrv, locationSrv, activitySrv, v)
 
 	h.Update(c)
 
@@ -471,6 +615,11 @@ func (u *OrganizationHandlerTest) TestDeleteOrganization() {
 	want := u.Organization
 
 	srv := new(OrganizationServiceMock)
+	userSrv := new(user.ServiceMock)
+	roleSrv := new(role.ServiceMock)
+	contactSrv := new(contact.ServiceMock)
+	locationSrv := new(location.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
 	c := &ContextMock{
 		Organization:    u.Organization,
 		Organizations:   u.Organizations,
@@ -483,7 +632,7 @@ func (u *OrganizationHandlerTest) TestDeleteOrganization() {
 
 	v, _ := validator.NewValidator()
 
-	h := handler.NewOrganizationHandler(srv, v)
+	h := handler.NewOrganizationHandler(srv, userSrv, roleSrv, contactSrv, locationSrv, activitySrv, v)
 
 	h.Delete(c)
 
@@ -494,6 +643,11 @@ func (u *OrganizationHandlerTest) TestDeleteInvalidRequestParamIDOrganization()
 	want := u.InvalidIDErr
 
 	srv := new(OrganizationServiceMock)
+	userSrv := new(user.ServiceMock)
+	roleSrv := new(role.ServiceMock)
+	contactSrv := new(contact.ServiceMock)
+	locationSrv := new(location.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
 	c := &ContextMock{
 		Organization:    u.Organization,
 		Organizations:   u.Organizations,
@@ -506,7 +660,7 @@ func (u *OrganizationHandlerTest) TestDeleteInvalidRequestParamIDOrganization()
 
 	v, _ := validator.NewValidator()
 
-	h := handler.NewOrganizationHandler(srv, v)
+	h := handler.NewOrganizationHandler(srv, userSrv, roleSrv, contactSrv, locationSrv, activitySrv, v)
 
 	h.Delete(c)
 
@@ -517,6 +671,11 @@ func (u *OrganizationHandlerTest) TestDeleteErrorNotFoundOrganization() {
 	want := u.NotFoundErr
 
 	srv := new(OrganizationServiceMock)
+	userSrv := new(user.ServiceMock)
+	roleSrv := new(role.ServiceMock)
+	contactSrv := new(contact.ServiceMock)
+	locationSrv := new(location.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
 	c := &ContextMock{
 		Organization:    u.Organization,
 		Organizations:   u.Organizations,
@@ -529,7 +688,7 @@ func (u *OrganizationHandlerTest) TestDeleteErrorNotFoundOrganization() {
 
 	v, _ := validator.NewValidator()
 
-	h := handler.NewOrganizationHandler(srv, v)
+	h := handler.NewOrganizationHandler(srv, userSrv, roleSrv, contactSrv, locationSrv, activitySrv, v)
 
 	h.Delete(c)
 
@@ -540,6 +699,11 @@ func (u *OrganizationHandlerTest) TestDeleteGrpcErrOrganization() {
 	want := u.ServiceDownErr
 
 	srv := new(OrganizationServiceMock)
+	userSrv := new(user.ServiceMock)
+	roleSrv := new(role.ServiceMock)
+	contactSrv := new(contact.ServiceMock)
+	locationSrv := new(location.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
 	c := &ContextMock{
 		Organization:    u.Organization,
 		Organizations:   u.Organizations,
@@ -552,9 +716,710 @@ func (u *OrganizationHandlerTest) TestDeleteGrpcErrOrganization() {
 
 	v, _ := validator.NewValidator()
 
-	h := handler.NewOrganizationHandler(srv, v)
+	h := handler.NewOrganizationHandler(srv, userSrv, roleSrv, contactSrv, locationSrv, activitySrv, v)
 
 	h.Delete(c)
 
 	assert.Equal(u.T(), want, c.V)
 }
+
+func (u *OrganizationHandlerTest) TestFindContactOrganization() {
+	want := u.Contact
+
+	srv := new(OrganizationServiceMock)
+	userSrv := new(user.ServiceMock)
+	roleSrv := new(role.ServiceMock)
+	contactSrv := new(contact.ServiceMock)
+	locationSrv := new(location.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	c := &ContextMock{
+		Organization:    u.Organization,
+		Organizations:   u.Organizations,
+		OrganizationDto: u.OrganizationDto,
+		Query:           u.Query,
+	}
+
+	u.Organization.Contact = u.Contact
+
+	srv.On("FindOne", int32(1)).Return(u.Organization, nil)
+	c.On("ID").Return(1, nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewOrganizationHandler(srv, userSrv, roleSrv, contactSrv, locationSrv, activitySrv, v)
+	h.FindContact(c)
+
+	assert.Equal(u.T(), want, c.V)
+}
+
+func (u *OrganizationHandlerTest) TestFindLocationNotFoundOrganization() {
+	want := &dto.ResponseErr{
+		StatusCode: http.StatusNotFound,
+		Message:    "Not found location",
+	}
+
+	srv := new(OrganizationServiceMock)
+	userSrv := new(user.ServiceMock)
+	roleSrv := new(role.ServiceMock)
+	contactSrv := new(contact.ServiceMock)
+	locationSrv := new(location.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	c := &ContextMock{
+		Organization:    u.Organization,
+		Organizations:   u.Organizations,
+		OrganizationDto: u.OrganizationDto,
+		Query:           u.Query,
+	}
+
+	srv.On("FindOne", int32(1)).Return(u.Organization, nil)
+	c.On("ID").Return(1, nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewOrganizationHandler(srv, userSrv, roleSrv, contactSrv, locationSrv, activitySrv, v)
+	h.FindLocation(c)
+
+	assert.Equal(u.T(), want, c.V)
+}
+
+func (u *OrganizationHandlerTest) TestUpdateExistedLocationOrganization() {
+	want := u.Location
+
+	srv := new(OrganizationServiceMock)
+	userSrv := new(user.ServiceMock)
+	roleSrv := new(role.ServiceMock)
+	contactSrv := new(contact.ServiceMock)
+	locationSrv := new(location.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	c := &ContextMock{
+		Organization:    u.Organization,
+		Organizations:   u.Organizations,
+		OrganizationDto: u.OrganizationDto,
+		LocationDto:     u.LocationDto,
+		Query:           u.Query,
+	}
+
+	u.Organization.Location = u.Location
+
+	srv.On("FindOne", int32(1)).Return(u.Organization, nil)
+	locationSrv.On("Update", int32(u.Location.Id), u.LocationDto).Return(u.Location, nil)
+	c.On("Bind", &dto.LocationDto{}).Return(nil)
+	c.On("ID").Return(1, nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewOrganizationHandler(srv, userSrv, roleSrv, contactSrv, locationSrv, activitySrv, v)
+	h.UpdateLocation(c)
+
+	assert.Equal(u.T(), want, c.V)
+}
+
+func (u *OrganizationHandlerTest) TestUpdateNewContactOrganization() {
+	want := u.Contact
+
+	srv := new(OrganizationServiceMock)
+	userSrv := new(user.ServiceMock)
+	roleSrv := new(role.ServiceMock)
+	contactSrv := new(contact.ServiceMock)
+	locationSrv := new(location.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	c := &ContextMock{
+		Organization:    u.Organization,
+		Organizations:   u.Organizations,
+		OrganizationDto: u.OrganizationDto,
+		ContactDto:      u.ContactDto,
+		Query:           u.Query,
+	}
+
+	updated := &proto.Organization{
+		Id:          u.Organization.Id,
+		Name:        u.Organization.Name,
+		Email:       u.Organization.Email,
+		Description: u.Organization.Description,
+		Contact:     u.Contact,
+	}
+
+	srv.On("FindOne", int32(1)).Return(u.Organization, nil)
+	srv.On("Update", int32(1), &dto.OrganizationDto{
+		Name:        u.Organization.Name,
+		Email:       u.Organization.Email,
+		Description: u.Organization.Description,
+		Contact:     u.ContactDto,
+	}).Return(updated, nil)
+	c.On("Bind", &dto.ContactDto{}).Return(nil)
+	c.On("ID").Return(1, nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewOrganizationHandler(srv, userSrv, roleSrv, contactSrv, locationSrv, activitySrv, v)
+	h.UpdateContact(c)
+
+	assert.Equal(u.T(), want, c.V)
+}
+
+func (u *OrganizationHandlerTest) TestUpdateLocationGrpcErrOrganization() {
+	want := u.ServiceDownErr
+
+	srv := new(OrganizationServiceMock)
+	userSrv := new(user.ServiceMock)
+	roleSrv := new(role.ServiceMock)
+	contactSrv := new(contact.ServiceMock)
+	locationSrv := new(location.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	c := &ContextMock{
+		Organization:    u.Organization,
+		Organizations:   u.Organizations,
+		OrganizationDto: u.OrganizationDto,
+		LocationDto:     u.LocationDto,
+		Query:           u.Query,
+	}
+
+	u.Organization.Location = u.Location
+
+	srv.On("FindOne", int32(1)).Return(u.Organization, nil)
+	locationSrv.On("Update", int32(u.Location.Id), u.LocationDto).Return(nil, u.ServiceDownErr)
+	c.On("Bind", &dto.LocationDto{}).Return(nil)
+	c.On("ID").Return(1, nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewOrganizationHandler(srv, userSrv, roleSrv, contactSrv, locationSrv, activitySrv, v)
+	h.UpdateLocation(c)
+
+	assert.Equal(u.T(), want, c.V)
+}
+
+func (u *OrganizationHandlerTest) TestFindMultiOrganization() {
+	want := []*proto.Organization{u.Organizations[2], u.Organizations[0]}
+
+	srv := new(OrganizationServiceMock)
+	userSrv := new(user.ServiceMock)
+	roleSrv := new(role.ServiceMock)
+	contactSrv := new(contact.ServiceMock)
+	locationSrv := new(location.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	c := &ContextMock{
+		Organization:    u.Organization,
+		Organizations:   u.Organizations,
+		OrganizationDto: u.OrganizationDto,
+		IDs:             "3,1",
+		Query:           u.Query,
+	}
+
+	srv.On("FindMulti", []int32{3, 1}).Return(want, nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewOrganizationHandler(srv, userSrv, roleSrv, contactSrv, locationSrv, activitySrv, v)
+	h.FindAll(c)
+
+	assert.Equal(u.T(), want, c.V)
+	srv.AssertNumberOfCalls(u.T(), "FindAll", 0)
+}
+
+func (u *OrganizationHandlerTest) TestFindMultiInvalidIDsOrganization() {
+	srv := new(OrganizationServiceMock)
+	userSrv := new(user.ServiceMock)
+	roleSrv := new(role.ServiceMock)
+	contactSrv := new(contact.ServiceMock)
+	locationSrv := new(location.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	c := &ContextMock{
+		Organization:    u.Organization,
+		Organizations:   u.Organizations,
+		OrganizationDto: u.OrganizationDto,
+		IDs:             "1,abc",
+		Query:           u.Query,
+	}
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewOrganizationHandler(srv, userSrv, roleSrv, contactSrv, locationSrv, activitySrv, v)
+	h.FindAll(c)
+
+	errRes, ok := c.V.(*dto.ResponseErr)
+
+	assert.True(u.T(), ok)
+	assert.Equal(u.T(), http.StatusBadRequest, errRes.StatusCode)
+	assert.Equal(u.T(), "Invalid ids query param", errRes.Message)
+	srv.AssertNumberOfCalls(u.T(), "FindMulti", 0)
+}
+
+func (u *OrganizationHandlerTest) TestFindMultiExceedMaxBatchSizeOrganization() {
+	var ids []string
+	for i := 1; i <= constant.MaxBatchSize+1; i++ {
+		ids = append(ids, strconv.Itoa(i))
+	}
+
+	srv := new(OrganizationServiceMock)
+	userSrv := new(user.ServiceMock)
+	roleSrv := new(role.ServiceMock)
+	contactSrv := new(contact.ServiceMock)
+	locationSrv := new(location.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	c := &ContextMock{
+		Organization:    u.Organization,
+		Organizations:   u.Organizations,
+		OrganizationDto: u.OrganizationDto,
+		IDs:             strings.Join(ids, ","),
+		Query:           u.Query,
+	}
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewOrganizationHandler(srv, userSrv, roleSrv, contactSrv, locationSrv, activitySrv, v)
+	h.FindAll(c)
+
+	errRes, ok := c.V.(*dto.ResponseErr)
+
+	assert.True(u.T(), ok)
+	assert.Equal(u.T(), http.StatusBadRequest, errRes.StatusCode)
+	srv.AssertNumberOfCalls(u.T(), "FindMulti", 0)
+}
+
+func (u *OrganizationHandlerTest) TestFindMultiGrpcErrOrganization() {
+	want := u.ServiceDownErr
+
+	srv := new(OrganizationServiceMock)
+	userSrv := new(user.ServiceMock)
+	roleSrv := new(role.ServiceMock)
+	contactSrv := new(contact.ServiceMock)
+	locationSrv := new(location.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	c := &ContextMock{
+		Organization:    u.Organization,
+		Organizations:   u.Organizations,
+		OrganizationDto: u.OrganizationDto,
+		IDs:             "1,2",
+		Query:           u.Query,
+	}
+
+	srv.On("FindMulti", []int32{1, 2}).Return(nil, u.ServiceDownErr)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewOrganizationHandler(srv, userSrv, roleSrv, contactSrv, locationSrv, activitySrv, v)
+	h.FindAll(c)
+
+	assert.Equal(u.T(), want, c.V)
+}
+
+func (u *OrganizationHandlerTest) TestFindMembersOrganization() {
+	want := &proto.UserPagination{
+		Items: []*proto.User{u.Member},
+		Meta: &proto.PaginationMetadata{
+			TotalItem:    1,
+			ItemCount:    1,
+			ItemsPerPage: 10,
+			TotalPage:    1,
+			CurrentPage:  1,
+		},
+	}
+
+	srv := new(OrganizationServiceMock)
+	userSrv := new(user.ServiceMock)
+	roleSrv := new(role.ServiceMock)
+	contactSrv := new(contact.ServiceMock)
+	locationSrv := new(location.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	c := &ContextMock{
+		Organization:    u.Organization,
+		Organizations:   u.Organizations,
+		OrganizationDto: u.OrganizationDto,
+		MemberDto:       &dto.MemberDto{UserID: 5},
+		MemberRoleDto:   &dto.MemberRoleDto{RoleID: 2},
+		Query:           u.Query,
+	}
+
+	srv.On("FindMembers", int32(1), u.Query).Return(want, nil)
+	c.On("ID").Return(1, nil)
+	c.On("PaginationQueryParam", &dto.PaginationQueryParams{}).Return(nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewOrganizationHandler(srv, userSrv, roleSrv, contactSrv, locationSrv, activitySrv, v)
+	h.FindMembers(c)
+
+	assert.Equal(u.T(), want, c.V)
+}
+
+func (u *OrganizationHandlerTest) TestAddMemberOrganization() {
+	want := &proto.Organization{
+		Id:      u.Organization.Id,
+		Name:    u.Organization.Name,
+		Members: []*proto.User{u.Member},
+	}
+
+	srv := new(OrganizationServiceMock)
+	userSrv := new(user.ServiceMock)
+	roleSrv := new(role.ServiceMock)
+	contactSrv := new(contact.ServiceMock)
+	locationSrv := new(location.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	c := &ContextMock{
+		Organization:    u.Organization,
+		Organizations:   u.Organizations,
+		OrganizationDto: u.OrganizationDto,
+		MemberDto:       &dto.MemberDto{UserID: 5},
+		MemberRoleDto:   &dto.MemberRoleDto{RoleID: 2},
+		Query:           u.Query,
+	}
+
+	userSrv.On("FindOne", int32(5)).Return(u.Member, nil)
+	srv.On("AddMember", int32(1), u.Member).Return(want, nil)
+	c.On("Bind", &dto.MemberDto{}).Return(nil)
+	c.On("ID").Return(1, nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewOrganizationHandler(srv, userSrv, roleSrv, contactSrv, locationSrv, activitySrv, v)
+	h.AddMember(c)
+
+	assert.Equal(u.T(), want, c.V)
+}
+
+func (u *OrganizationHandlerTest) TestAddMemberNotFoundUserOrganization() {
+	want := &dto.ResponseErr{
+		StatusCode: http.StatusNotFound,
+		Message:    "Not found user",
+	}
+
+	srv := new(OrganizationServiceMock)
+	userSrv := new(user.ServiceMock)
+	roleSrv := new(role.ServiceMock)
+	contactSrv := new(contact.ServiceMock)
+	locationSrv := new(location.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	c := &ContextMock{
+		Organization:    u.Organization,
+		Organizations:   u.Organizations,
+		OrganizationDto: u.OrganizationDto,
+		MemberDto:       &dto.MemberDto{UserID: 5},
+		MemberRoleDto:   &dto.MemberRoleDto{RoleID: 2},
+		Query:           u.Query,
+	}
+
+	userSrv.On("FindOne", int32(5)).Return(nil, want)
+	c.On("Bind", &dto.MemberDto{}).Return(nil)
+	c.On("ID").Return(1, nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewOrganizationHandler(srv, userSrv, roleSrv, contactSrv, locationSrv, activitySrv, v)
+	h.AddMember(c)
+
+	assert.Equal(u.T(), want, c.V)
+	srv.AssertNumberOfCalls(u.T(), "AddMember", 0)
+}
+
+func (u *OrganizationHandlerTest) TestRemoveMemberOrganization() {
+	want := u.Organization
+
+	srv := new(OrganizationServiceMock)
+	userSrv := new(user.ServiceMock)
+	roleSrv := new(role.ServiceMock)
+	contactSrv := new(contact.ServiceMock)
+	locationSrv := new(location.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	c := &ContextMock{
+		Organization:    u.Organization,
+		Organizations:   u.Organizations,
+		OrganizationDto: u.OrganizationDto,
+		MemberDto:       &dto.MemberDto{UserID: 5},
+		MemberRoleDto:   &dto.MemberRoleDto{RoleID: 2},
+		Query:           u.Query,
+	}
+
+	srv.On("RemoveMember", int32(1), int32(5)).Return(u.Organization, nil)
+	c.On("ID").Return(1, nil)
+	c.On("MemberID").Return(5, nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewOrganizationHandler(srv, userSrv, roleSrv, contactSrv, locationSrv, activitySrv, v)
+	h.RemoveMember(c)
+
+	assert.Equal(u.T(), want, c.V)
+}
+
+func (u *OrganizationHandlerTest) TestAssignMemberRoleOrganization() {
+	want := &proto.Role{
+		Id:    u.Role.Id,
+		Name:  u.Role.Name,
+		Users: []*proto.User{u.Member},
+	}
+
+	u.Organization.Members = []*proto.User{u.Member}
+	u.Organization.Roles = []*proto.Role{{Id: 1}, u.Role}
+
+	srv := new(OrganizationServiceMock)
+	userSrv := new(user.ServiceMock)
+	roleSrv := new(role.ServiceMock)
+	contactSrv := new(contact.ServiceMock)
+	locationSrv := new(location.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	c := &ContextMock{
+		Organization:    u.Organization,
+		Organizations:   u.Organizations,
+		OrganizationDto: u.OrganizationDto,
+		MemberDto:       &dto.MemberDto{UserID: 5},
+		MemberRoleDto:   &dto.MemberRoleDto{RoleID: 2},
+		Query:           u.Query,
+	}
+
+	srv.On("FindOne", int32(1)).Return(u.Organization, nil)
+	roleSrv.On("AssignUser", []int32{1, 2}, int32(2), u.Member).Return(want, nil)
+	c.On("Bind", &dto.MemberRoleDto{}).Return(nil)
+	c.On("ID").Return(1, nil)
+	c.On("MemberID").Return(5, nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewOrganizationHandler(srv, userSrv, roleSrv, contactSrv, locationSrv, activitySrv, v)
+	h.AssignMemberRole(c)
+
+	assert.Equal(u.T(), want, c.V)
+}
+
+func (u *OrganizationHandlerTest) TestAssignMemberRoleNotMemberOrganization() {
+	want := &dto.ResponseErr{
+		StatusCode: http.StatusNotFound,
+		Message:    "The organization does not have this member",
+	}
+
+	u.Organization.Roles = []*proto.Role{u.Role}
+
+	srv := new(OrganizationServiceMock)
+	userSrv := new(user.ServiceMock)
+	roleSrv := new(role.ServiceMock)
+	contactSrv := new(contact.ServiceMock)
+	locationSrv := new(location.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	c := &ContextMock{
+		Organization:    u.Organization,
+		Organizations:   u.Organizations,
+		OrganizationDto: u.OrganizationDto,
+		MemberDto:       &dto.MemberDto{UserID: 5},
+		MemberRoleDto:   &dto.MemberRoleDto{RoleID: 2},
+		Query:           u.Query,
+	}
+
+	srv.On("FindOne", int32(1)).Return(u.Organization, nil)
+	c.On("Bind", &dto.MemberRoleDto{}).Return(nil)
+	c.On("ID").Return(1, nil)
+	c.On("MemberID").Return(5, nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewOrganizationHandler(srv, userSrv, roleSrv, contactSrv, locationSrv, activitySrv, v)
+	h.AssignMemberRole(c)
+
+	assert.Equal(u.T(), want, c.V)
+	roleSrv.AssertNumberOfCalls(u.T(), "AssignUser", 0)
+}
+
+func (u *OrganizationHandlerTest) TestAssignMemberRoleNotOrganizationRoleOrganization() {
+	want := &dto.ResponseErr{
+		StatusCode: http.StatusNotFound,
+		Message:    "The organization does not have this role",
+	}
+
+	u.Organization.Members = []*proto.User{u.Member}
+	u.Organization.Roles = []*proto.Role{{Id: 1}}
+
+	srv := new(OrganizationServiceMock)
+	userSrv := new(user.ServiceMock)
+	roleSrv := new(role.ServiceMock)
+	contactSrv := new(contact.ServiceMock)
+	locationSrv := new(location.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	c := &ContextMock{
+		Organization:    u.Organization,
+		Organizations:   u.Organizations,
+		OrganizationDto: u.OrganizationDto,
+		MemberDto:       &dto.MemberDto{UserID: 5},
+		MemberRoleDto:   &dto.MemberRoleDto{RoleID: 2},
+		Query:           u.Query,
+	}
+
+	srv.On("FindOne", int32(1)).Return(u.Organization, nil)
+	c.On("Bind", &dto.MemberRoleDto{}).Return(nil)
+	c.On("ID").Return(1, nil)
+	c.On("MemberID").Return(5, nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewOrganizationHandler(srv, userSrv, roleSrv, contactSrv, locationSrv, activitySrv, v)
+	h.AssignMemberRole(c)
+
+	assert.Equal(u.T(), want, c.V)
+	roleSrv.AssertNumberOfCalls(u.T(), "AssignUser", 0)
+}
+
+func (u *OrganizationHandlerTest) TestAssignMemberRoleInvalidBodyRequestOrganization() {
+	srv := new(OrganizationServiceMock)
+	userSrv := new(user.ServiceMock)
+	roleSrv := new(role.ServiceMock)
+	contactSrv := new(contact.ServiceMock)
+	locationSrv := new(location.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	c := &ContextMock{
+		Organization:    u.Organization,
+		Organizations:   u.Organizations,
+		OrganizationDto: u.OrganizationDto,
+		MemberDto:       &dto.MemberDto{UserID: 5},
+		MemberRoleDto:   &dto.MemberRoleDto{RoleID: 2},
+		Query:           u.Query,
+	}
+
+	c.MemberRoleDto = &dto.MemberRoleDto{}
+	c.On("Bind", &dto.MemberRoleDto{}).Return(nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewOrganizationHandler(srv, userSrv, roleSrv, contactSrv, locationSrv, activitySrv, v)
+	h.AssignMemberRole(c)
+
+	errRes, ok := c.V.(*dto.ResponseErr)
+
+	assert.True(u.T(), ok)
+	assert.Equal(u.T(), http.StatusBadRequest, errRes.StatusCode)
+	srv.AssertNumberOfCalls(u.T(), "FindOne", 0)
+}
+
+func (u *OrganizationHandlerTest) TestFindTeamsOrganization() {
+	want := []*proto.Team{{Id: 1, Name: faker.Word()}, {Id: 2, Name: faker.Word()}}
+
+	u.Organization.Teams = want
+
+	srv := new(OrganizationServiceMock)
+	userSrv := new(user.ServiceMock)
+	roleSrv := new(role.ServiceMock)
+	contactSrv := new(contact.ServiceMock)
+	locationSrv := new(location.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	c := &ContextMock{
+		Organization:    u.Organization,
+		Organizations:   u.Organizations,
+		OrganizationDto: u.OrganizationDto,
+		Query:           u.Query,
+	}
+
+	srv.On("FindOne", int32(1)).Return(u.Organization, nil)
+	c.On("ID").Return(1, nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewOrganizationHandler(srv, userSrv, roleSrv, contactSrv, locationSrv, activitySrv, v)
+	h.FindTeams(c)
+
+	assert.Equal(u.T(), want, c.V)
+}
+
+func (u *OrganizationHandlerTest) TestFindTeamsNotFoundOrganization() {
+	want := u.NotFoundErr
+
+	srv := new(OrganizationServiceMock)
+	userSrv := new(user.ServiceMock)
+	roleSrv := new(role.ServiceMock)
+	contactSrv := new(contact.ServiceMock)
+	locationSrv := new(location.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	c := &ContextMock{
+		Organization:    u.Organization,
+		Organizations:   u.Organizations,
+		OrganizationDto: u.OrganizationDto,
+		Query:           u.Query,
+	}
+
+	srv.On("FindOne", int32(1)).Return(nil, u.NotFoundErr)
+	c.On("ID").Return(1, nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewOrganizationHandler(srv, userSrv, roleSrv, contactSrv, locationSrv, activitySrv, v)
+	h.FindTeams(c)
+
+	assert.Equal(u.T(), want, c.V)
+}
+
+func (u *OrganizationHandlerTest) TestFindActivityOrganization() {
+	u.Organization.Logs = []*proto.Log{
+		{
+			Title:     faker.Word(),
+			Type:      proto.LogType_POST,
+			User:      &proto.User{Id: 1},
+			Timestamp: "2022-01-01T00:00:00Z",
+		},
+	}
+
+	query := &dto.ActivityQueryParams{
+		Type: "POST",
+	}
+
+	want := &dto.LogPagination{
+		Items: u.Organization.Logs,
+		Meta: &proto.PaginationMetadata{
+			TotalItem:    1,
+			ItemCount:    1,
+			ItemsPerPage: 10,
+			TotalPage:    1,
+			CurrentPage:  1,
+		},
+	}
+
+	srv := new(OrganizationServiceMock)
+	userSrv := new(user.ServiceMock)
+	roleSrv := new(role.ServiceMock)
+	contactSrv := new(contact.ServiceMock)
+	locationSrv := new(location.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	c := &ContextMock{
+		Organization:    u.Organization,
+		Organizations:   u.Organizations,
+		OrganizationDto: u.OrganizationDto,
+		Query:           u.Query,
+		ActivityQuery:   query,
+	}
+
+	srv.On("FindOne", int32(1)).Return(u.Organization, nil)
+	activitySrv.On("Paginate", u.Organization.Logs, query).Return(want, nil)
+	c.On("ID").Return(1, nil)
+	c.On("ActivityQueryParam", &dto.ActivityQueryParams{}).Return(nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewOrganizationHandler(srv, userSrv, roleSrv, contactSrv, locationSrv, activitySrv, v)
+	h.FindActivity(c)
+
+	assert.Equal(u.T(), want, c.V)
+}
+
+func (u *OrganizationHandlerTest) TestFindActivityNotFoundOrganization() {
+	want := u.NotFoundErr
+
+	srv := new(OrganizationServiceMock)
+	userSrv := new(user.ServiceMock)
+	roleSrv := new(role.ServiceMock)
+	contactSrv := new(contact.ServiceMock)
+	locationSrv := new(location.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	c := &ContextMock{
+		Organization:    u.Organization,
+		Organizations:   u.Organizations,
+		OrganizationDto: u.OrganizationDto,
+		Query:           u.Query,
+		ActivityQuery:   &dto.ActivityQueryParams{},
+	}
+
+	srv.On("FindOne", int32(1)).Return(nil, u.NotFoundErr)
+	c.On("ID").Return(1, nil)
+	c.On("ActivityQueryParam", &dto.ActivityQueryParams{}).Return(nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewOrganizationHandler(srv, userSrv, roleSrv, contactSrv, locationSrv, activitySrv, v)
+	h.FindActivity(c)
+
+	assert.Equal(u.T(), want, c.V)
+}
@@ -14,13 +14,30 @@ type ContextMock struct {
 	Organization    *proto.Organization
 	Organizations   []*proto.Organization
 	OrganizationDto *dto.OrganizationDto
+	ContactDto      *dto.ContactDto
+	LocationDto     *dto.LocationDto
+	MemberDto       *dto.MemberDto
+	MemberRoleDto   *dto.MemberRoleDto
+	IDs             string
 	Query           *dto.PaginationQueryParams
+	ActivityQuery   *dto.ActivityQueryParams
 }
 
 func (c *ContextMock) Bind(v interface{}) error {
 	args := c.Called(v)
 
-	*v.(*dto.OrganizationDto) = *c.OrganizationDto
+	switch v.(type) {
+	case *dto.OrganizationDto:
+		*v.(*dto.OrganizationDto) = *c.OrganizationDto
+	case *dto.ContactDto:
+		*v.(*dto.ContactDto) = *c.ContactDto
+	case *dto.LocationDto:
+		*v.(*dto.LocationDto) = *c.LocationDto
+	case *dto.MemberDto:
+		*v.(*dto.MemberDto) = *c.MemberDto
+	case *dto.MemberRoleDto:
+		*v.(*dto.MemberRoleDto) = *c.MemberRoleDto
+	}
 
 	return args.Error(0)
 }
@@ -43,11 +60,29 @@ func (c *ContextMock) PaginationQueryParam(query *dto.PaginationQueryParams) err
 	return args.Error(0)
 }
 
+func (c *ContextMock) ActivityQueryParam(query *dto.ActivityQueryParams) error {
+	args := c.Called(query)
+
+	*query = *c.ActivityQuery
+
+	return args.Error(0)
+}
+
+func (c *ContextMock) IDsQueryParam() string {
+	return c.IDs
+}
+
+func (c *ContextMock) MemberID() (int32, error) {
+	args := c.Called()
+
+	return int32(args.Int(0)), args.Error(1)
+}
+
 type OrganizationServiceMock struct {
 	mock.Mock
 }
 
-func (s *OrganizationServiceMock) FindAll(query *dto.PaginationQueryParams) (res *proto.OrganizationPagination, err *dto.ResponseErr) {
+func (s *OrganizationServiceMock) FindAll(_ context.Context, query *dto.PaginationQueryParams) (res *proto.OrganizationPagination, err *dto.ResponseErr) {
 	args := s.Called(query)
 
 	if args.Get(0) != nil {
@@ -61,7 +96,7 @@ func (s *OrganizationServiceMock) FindAll(query *dto.PaginationQueryParams) (res
 	return
 }
 
-func (s *OrganizationServiceMock) FindOne(id int32) (res *proto.Organization, err *dto.ResponseErr) {
+func (s *OrganizationServiceMock) FindOne(_ context.Context, id int32) (res *proto.Organization, err *dto.ResponseErr) {
 	args := s.Called(id)
 
 	if args.Get(0) != nil {
@@ -75,7 +110,21 @@ func (s *OrganizationServiceMock) FindOne(id int32) (res *proto.Organization, er
 	return
 }
 
-func (s *OrganizationServiceMock) Create(org *dto.OrganizationDto) (res *proto.Organization, err *dto.ResponseErr) {
+func (s *OrganizationServiceMock) FindMulti(_ context.Context, ids []int32) (res []*proto.Organization, err *dto.ResponseErr) {
+	args := s.Called(ids)
+
+	if args.Get(0) != nil {
+		res = args.Get(0).([]*proto.Organization)
+	}
+
+	if args.Get(1) != nil {
+		err = args.Get(1).(*dto.ResponseErr)
+	}
+
+	return
+}
+
+func (s *OrganizationServiceMock) Create(_ context.Context, org *dto.OrganizationDto) (res *proto.Organization, err *dto.ResponseErr) {
 	args := s.Called(org)
 
 	if args.Get(0) != nil {
@@ -89,7 +138,7 @@ func (s *OrganizationServiceMock) Create(org *dto.OrganizationDto) (res *proto.O
 	return
 }
 
-func (s *OrganizationServiceMock) Update(id int32, org *dto.OrganizationDto) (res *proto.Organization, err *dto.ResponseErr) {
+func (s *OrganizationServiceMock) Update(_ context.Context, id int32, org *dto.OrganizationDto) (res *proto.Organization, err *dto.ResponseErr) {
 	args := s.Called(id, org)
 
 	if args.Get(0) != nil {
@@ -103,7 +152,7 @@ func (s *OrganizationServiceMock) Update(id int32, org *dto.OrganizationDto) (re
 	return
 }
 
-func (s *OrganizationServiceMock) Delete(id int32) (res *proto.Organization, err *dto.ResponseErr) {
+func (s *OrganizationServiceMock) Delete(_ context.Context, id int32) (res *proto.Organization, err *dto.ResponseErr) {
 	args := s.Called(id)
 
 	if args.Get(0) != nil {
@@ -117,6 +166,48 @@ func (s *OrganizationServiceMock) Delete(id int32) (res *proto.Organization, err
 	return
 }
 
+func (s *OrganizationServiceMock) FindMembers(_ context.Context, id int32, query *dto.PaginationQueryParams) (res *proto.UserPagination, err *dto.ResponseErr) {
+	args := s.Called(id, query)
+
+	if args.Get(0) != nil {
+		res = args.Get(0).(*proto.UserPagination)
+	}
+
+	if args.Get(1) != nil {
+		err = args.Get(1).(*dto.ResponseErr)
+	}
+
+	return
+}
+
+func (s *OrganizationServiceMock) AddMember(_ context.Context, id int32, user *proto.User) (res *proto.Organization, err *dto.ResponseErr) {
+	args := s.Called(id, user)
+
+	if args.Get(0) != nil {
+		res = args.Get(0).(*proto.Organization)
+	}
+
+	if args.Get(1) != nil {
+		err = args.Get(1).(*dto.ResponseErr)
+	}
+
+	return
+}
+
+func (s *OrganizationServiceMock) RemoveMember(_ context.Context, id int32, userId int32) (res *proto.Organization, err *dto.ResponseErr) {
+	args := s.Called(id, userId)
+
+	if args.Get(0) != nil {
+		res = args.Get(0).(*proto.Organization)
+	}
+
+	if args.Get(1) != nil {
+		err = args.Get(1).(*dto.ResponseErr)
+	}
+
+	return
+}
+
 type ClientMock struct {
 	mock.Mock
 }
@@ -141,8 +232,14 @@ func (c *ClientMock) FindOne(ctx context.Context, in *proto.FindOneOrganizationR
 	return res, args.Error(1)
 }
 
-func (c *ClientMock) FindMulti(ctx context.Context, in *proto.FindMultiOrganizationRequest, opts ...grpc.CallOption) (*proto.OrganizationListResponse, error) {
-	return nil, nil
+func (c *ClientMock) FindMulti(ctx context.Context, in *proto.FindMultiOrganizationRequest, opts ...grpc.CallOption) (res *proto.OrganizationListResponse, err error) {
+	args := c.Called(in)
+
+	if args.Get(0) != nil {
+		res = args.Get(0).(*proto.OrganizationListResponse)
+	}
+
+	return res, args.Error(1)
 }
 
 func (c *ClientMock) Create(ctx context.Context, in *proto.CreateOrganizationRequest, opts ...grpc.CallOption) (res *proto.OrganizationResponse, err error) {
@@ -174,3 +271,7 @@ func (c *ClientMock) Delete(ctx context.Context, in *proto.DeleteOrganizationReq
 
 	return res, args.Error(1)
 }
+
+func (c *ContextMock) UserContext() context.Context {
+	return context.Background()
+}
@@ -1,6 +1,7 @@
 package organization
 
 import (
+	"context"
 	"github.com/bxcodec/faker/v3"
 	"github.com/pkg/errors"
 	"github.com/samithiwat/samithiwat-backend-gateway/src/dto"
@@ -110,7 +111,7 @@ func (s *OrganizationServiceTest) TestFindAllOrganizationService() {
 
 	srv := service.NewOrganizationService(client)
 
-	organizations, err := srv.FindAll(s.Query)
+	organizations, err := srv.FindAll(context.Background(), s.Query)
 
 	assert.Nil(s.T(), err, "Must not got any error")
 	assert.Equal(s.T(), want, organizations)
@@ -128,7 +129,7 @@ func (s *OrganizationServiceTest) TestFindAllGrpcErrOrganizationService() {
 
 	srv := service.NewOrganizationService(client)
 
-	_, err := srv.FindAll(s.Query)
+	_, err := srv.FindAll(context.Background(), s.Query)
 
 	assert.Equal(s.T(), want, err)
 }
@@ -149,7 +150,7 @@ func (s *OrganizationServiceTest) TestFindOneOrganizationService() {
 
 	srv := service.NewOrganizationService(client)
 
-	organization, err := srv.FindOne(id)
+	organization, err := srv.FindOne(context.Background(), id)
 
 	assert.Nil(s.T(), err, "Must not got any error")
 	assert.Equal(s.T(), want, organization)
@@ -171,7 +172,7 @@ func (s *OrganizationServiceTest) TestFindOneNotFoundOrganizationService() {
 
 	srv := service.NewOrganizationService(client)
 
-	organization, err := srv.FindOne(id)
+	organization, err := srv.FindOne(context.Background(), id)
 
 	assert.Nil(s.T(), organization)
 	assert.Equal(s.T(), want, err)
@@ -189,7 +190,7 @@ func (s *OrganizationServiceTest) TestFindOneGrpcErrOrganizationService() {
 
 	srv := service.NewOrganizationService(client)
 
-	_, err := srv.FindOne(id)
+	_, err := srv.FindOne(context.Background(), id)
 
 	assert.Equal(s.T(), want, err)
 }
@@ -207,7 +208,7 @@ func (s *OrganizationServiceTest) TestCreateOrganizationService() {
 
 	srv := service.NewOrganizationService(client)
 
-	organization, err := srv.Create(s.OrganizationDto)
+	organization, err := srv.Create(context.Background(), s.OrganizationDto)
 
 	assert.Nil(s.T(), err, "Must not got any error")
 	assert.Equal(s.T(), want, organization)
@@ -230,7 +231,7 @@ func (s *OrganizationServiceTest) TestCreateDuplicatedOrganizationService() {
 
 	srv := service.NewOrganizationService(client)
 
-	organization, err := srv.Create(s.OrganizationDto)
+	organization, err := srv.Create(context.Background(), s.OrganizationDto)
 
 	assert.Nil(s.T(), organization)
 	assert.Equal(s.T(), want, err)
@@ -245,7 +246,7 @@ func (s *OrganizationServiceTest) TestCreateGrpcErrOrganizationService() {
 
 	srv := service.NewOrganizationService(client)
 
-	_, err := srv.Create(s.OrganizationDto)
+	_, err := srv.Create(context.Background(), s.OrganizationDto)
 
 	assert.Equal(s.T(), want, err)
 }
@@ -263,7 +264,7 @@ func (s *OrganizationServiceTest) TestUpdateOrganizationService() {
 
 	srv := service.NewOrganizationService(client)
 
-	organization, err := srv.Update(1, s.OrganizationDto)
+	organization, err := srv.Update(context.Background(), 1, s.OrganizationDto)
 
 	assert.Nil(s.T(), err, "Must not got any error")
 	assert.Equal(s.T(), want, organization)
@@ -282,7 +283,7 @@ func (s *OrganizationServiceTest) TestUpdateNotFoundOrganizationService() {
 
 	srv := service.NewOrganizationService(client)
 
-	organization, err := srv.Update(1, s.OrganizationDto)
+	organization, err := srv.Update(context.Background(), 1, s.OrganizationDto)
 
 	assert.Nil(s.T(), organization)
 	assert.Equal(s.T(), want, err)
@@ -297,7 +298,7 @@ func (s *OrganizationServiceTest) TestUpdateGrpcErrOrganizationService() {
 
 	srv := service.NewOrganizationService(client)
 
-	_, err := srv.Update(1, s.OrganizationDto)
+	_, err := srv.Update(context.Background(), 1, s.OrganizationDto)
 
 	assert.Equal(s.T(), want, err)
 }
@@ -318,7 +319,7 @@ func (s *OrganizationServiceTest) TestDeleteOrganizationService() {
 
 	srv := service.NewOrganizationService(client)
 
-	organization, err := srv.Delete(id)
+	organization, err := srv.Delete(context.Background(), id)
 
 	assert.Nil(s.T(), err, "Must not got any error")
 	assert.Equal(s.T(), want, organization)
@@ -340,7 +341,7 @@ func (s *OrganizationServiceTest) TestDeleteNotFoundOrganizationService() {
 
 	srv := service.NewOrganizationService(client)
 
-	organization, err := srv.Delete(id)
+	organization, err := srv.Delete(context.Background(), id)
 
 	assert.Nil(s.T(), organization)
 	assert.Equal(s.T(), want, err)
@@ -358,7 +359,188 @@ func (s *OrganizationServiceTest) TestDeleteGrpcErrOrganizationService() {
 
 	srv := service.NewOrganizationService(client)
 
-	_, err := srv.Delete(id)
+	_, err := srv.Delete(context.Background(), id)
 
 	assert.Equal(s.T(), want, err)
 }
+
+func (s *OrganizationServiceTest) TestFindMultiOrganizationService() {
+	want := []*proto.Organization{s.Organizations[2], s.Organizations[0], s.Organizations[1]}
+
+	client := new(ClientMock)
+
+	client.On("FindMulti", &proto.FindMultiOrganizationRequest{Ids: []uint32{3, 1, 2}}).Return(&proto.OrganizationListResponse{
+		StatusCode: http.StatusOK,
+		Errors:     nil,
+		Data:       []*proto.Organization{s.Organizations[0], s.Organizations[1], s.Organizations[2]},
+	}, nil)
+
+	srv := service.NewOrganizationService(client)
+
+	organizations, err := srv.FindMulti(context.Background(), []int32{3, 1, 2})
+
+	assert.Nil(s.T(), err, "Must not got any error")
+	assert.Equal(s.T(), want, organizations)
+}
+
+func (s *OrganizationServiceTest) TestFindMultiSomeNotFoundOrganizationService() {
+	want := []*proto.Organization{s.Organizations[1]}
+
+	client := new(ClientMock)
+
+	client.On("FindMulti", &proto.FindMultiOrganizationRequest{Ids: []uint32{10, 2}}).Return(&proto.OrganizationListResponse{
+		StatusCode: http.StatusOK,
+		Errors:     nil,
+		Data:       []*proto.Organization{s.Organizations[1]},
+	}, nil)
+
+	srv := service.NewOrganizationService(client)
+
+	organizations, err := srv.FindMulti(context.Background(), []int32{10, 2})
+
+	assert.Nil(s.T(), err, "Must not got any error")
+	assert.Equal(s.T(), want, organizations)
+}
+
+func (s *OrganizationServiceTest) TestFindMultiGrpcErrOrganizationService() {
+	want := s.ServiceDownErr
+
+	client := new(ClientMock)
+
+	client.On("FindMulti", &proto.FindMultiOrganizationRequest{Ids: []uint32{1, 2}}).Return(nil, errors.New("Service is down"))
+
+	srv := service.NewOrganizationService(client)
+
+	_, err := srv.FindMulti(context.Background(), []int32{1, 2})
+
+	assert.Equal(s.T(), want, err)
+}
+
+func (s *OrganizationServiceTest) TestFindMembersOrganizationService() {
+	members := []*proto.User{{Id: 1}, {Id: 2}, {Id: 3}}
+
+	want := &proto.UserPagination{
+		Items: members,
+		Meta: &proto.PaginationMetadata{
+			TotalItem:    3,
+			ItemCount:    3,
+			ItemsPerPage: 10,
+			TotalPage:    1,
+			CurrentPage:  1,
+		},
+	}
+
+	client := new(ClientMock)
+
+	client.On("FindOne", &proto.FindOneOrganizationRequest{Id: 1}).Return(&proto.OrganizationResponse{
+		StatusCode: http.StatusOK,
+		Errors:     nil,
+		Data: &proto.Organization{
+			Id:      s.Organization.Id,
+			Name:    s.Organization.Name,
+			Members: members,
+		},
+	}, nil)
+
+	srv := service.NewOrganizationService(client)
+
+	result, err := srv.FindMembers(context.Background(), 1, &dto.PaginationQueryParams{})
+
+	assert.Nil(s.T(), err, "Must not got any error")
+	assert.Equal(s.T(), want, result)
+}
+
+func (s *OrganizationServiceTest) TestAddMemberOrganizationService() {
+	member := &proto.User{Id: 5}
+
+	want := &proto.Organization{
+		Id:          s.Organization.Id,
+		Name:        s.Organization.Name,
+		Email:       s.Organization.Email,
+		Description: s.Organization.Description,
+		Members:     []*proto.User{member},
+	}
+
+	client := new(ClientMock)
+
+	client.On("FindOne", &proto.FindOneOrganizationRequest{Id: 1}).Return(&proto.OrganizationResponse{
+		StatusCode: http.StatusOK,
+		Errors:     nil,
+		Data: &proto.Organization{
+			Id:          s.Organization.Id,
+			Name:        s.Organization.Name,
+			Email:       s.Organization.Email,
+			Description: s.Organization.Description,
+		},
+	}, nil)
+	client.On("Update", want).Return(&proto.OrganizationResponse{
+		StatusCode: http.StatusOK,
+		Errors:     nil,
+		Data:       want,
+	}, nil)
+
+	srv := service.NewOrganizationService(client)
+
+	organization, err := srv.AddMember(context.Background(), 1, member)
+
+	assert.Nil(s.T(), err, "Must not got any error")
+	assert.Equal(s.T(), want, organization)
+}
+
+func (s *OrganizationServiceTest) TestRemoveMemberOrganizationService() {
+	want := &proto.Organization{
+		Id:      s.Organization.Id,
+		Name:    s.Organization.Name,
+		Members: []*proto.User{{Id: 2}},
+	}
+
+	client := new(ClientMock)
+
+	client.On("FindOne", &proto.FindOneOrganizationRequest{Id: 1}).Return(&proto.OrganizationResponse{
+		StatusCode: http.StatusOK,
+		Errors:     nil,
+		Data: &proto.Organization{
+			Id:      s.Organization.Id,
+			Name:    s.Organization.Name,
+			Members: []*proto.User{{Id: 2}, {Id: 5}},
+		},
+	}, nil)
+	client.On("Update", want).Return(&proto.OrganizationResponse{
+		StatusCode: http.StatusOK,
+		Errors:     nil,
+		Data:       want,
+	}, nil)
+
+	srv := service.NewOrganizationService(client)
+
+	organization, err := srv.RemoveMember(context.Background(), 1, 5)
+
+	assert.Nil(s.T(), err, "Must not got any error")
+	assert.Equal(s.T(), want, organization)
+}
+
+func (s *OrganizationServiceTest) TestRemoveNotMemberOrganizationService() {
+	want := &dto.ResponseErr{
+		StatusCode: http.StatusNotFound,
+		Message:    "The organization does not have this member",
+		Data:       nil,
+	}
+
+	client := new(ClientMock)
+
+	client.On("FindOne", &proto.FindOneOrganizationRequest{Id: 1}).Return(&proto.OrganizationResponse{
+		StatusCode: http.StatusOK,
+		Errors:     nil,
+		Data: &proto.Organization{
+			Id:      s.Organization.Id,
+			Members: []*proto.User{{Id: 2}},
+		},
+	}, nil)
+
+	srv := service.NewOrganizationService(client)
+
+	organization, err := srv.RemoveMember(context.Background(), 1, 5)
+
+	assert.Nil(s.T(), organization)
+	assert.Equal(s.T(), want, err)
+}
@@ -0,0 +1,672 @@
+package permission
+
+import (
+	"github.com/bxcodec/faker/v3"
+	"github.com/pkg/errors"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/dto"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/handler"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/proto"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/validator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"testing"
+)
+
+type PermissionHandlerTest struct {
+	suite.Suite
+	Permission     *proto.Permission
+	Permissions    []*proto.Permission
+	PermissionDto  *dto.PermissionDto
+	Query          *dto.PaginationQueryParams
+	NotFoundErr    *dto.ResponseErr
+	ServiceDownErr *dto.ResponseErr
+	InvalidIDErr   *dto.ResponseErr
+}
+
+func TestPermissionHandler(t *testing.T) {
+	suite.Run(t, new(PermissionHandlerTest))
+}
+
+func (u *PermissionHandlerTest) SetupTest() {
+	u.Permission = &proto.Permission{
+		Id:   1,
+		Name: faker.Word(),
+		Code: faker.Word() + ":" + faker.Word(),
+	}
+
+	Permission2 := &proto.Permission{
+		Id:   2,
+		Name: faker.Word(),
+		Code: faker.Word() + ":" + faker.Word(),
+	}
+
+	Permission3 := &proto.Permission{
+		Id:   3,
+		Name: faker.Word(),
+		Code: faker.Word() + ":" + faker.Word(),
+	}
+
+	Permission4 := &proto.Permission{
+		Id:   4,
+		Name: faker.Word(),
+		Code: faker.Word() + ":" + faker.Word(),
+	}
+
+	u.PermissionDto = &dto.PermissionDto{
+		Name: faker.Word(),
+		Code: "user:update",
+	}
+
+	_ = faker.FakeData(&u.Query)
+
+	u.Permissions = append(u.Permissions, u.Permission, Permission2, Permission3, Permission4)
+
+	u.ServiceDownErr = &dto.ResponseErr{
+		StatusCode: http.StatusServiceUnavailable,
+		Message:    "Service is down",
+		Data:       nil,
+	}
+
+	u.NotFoundErr = &dto.ResponseErr{
+		StatusCode: http.StatusNotFound,
+		Message:    "Not found permission",
+		Data:       nil,
+	}
+
+	u.InvalidIDErr = &dto.ResponseErr{
+		StatusCode: http.StatusBadRequest,
+		Message:    "Invalid ID",
+	}
+}
+
+func (u *PermissionHandlerTest) TestFindAllPermission() {
+	want := &proto.PermissionPagination{
+		Items: u.Permissions,
+		Meta: &proto.PaginationMetadata{
+			TotalItem:    4,
+			ItemCount:    4,
+			ItemsPerPage: 10,
+			TotalPage:    1,
+			CurrentPage:  1,
+		},
+	}
+
+	srv := new(ServiceMock)
+	c := &ContextMock{
+		Permission:    u.Permission,
+		Permissions:   u.Permissions,
+		PermissionDto: u.PermissionDto,
+		Query:         u.Query,
+	}
+
+	srv.On("FindAll", u.Query).Return(want, nil)
+	c.On("PaginationQueryParam", &dto.PaginationQueryParams{}).Return(nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewPermissionHandler(srv, v)
+	h.FindAll(c)
+
+	assert.Equal(u.T(), want, c.V)
+}
+
+func (u *PermissionHandlerTest) TestFindAllInvalidQueryParamPermission() {
+	want := &dto.ResponseErr{
+		StatusCode: http.StatusInternalServerError,
+		Message:    "Cannot parse query param",
+	}
+
+	srv := new(ServiceMock)
+	c := &ContextMock{
+		Permission:    u.Permission,
+		Permissions:   u.Permissions,
+		PermissionDto: u.PermissionDto,
+		Query:         u.Query,
+	}
+
+	srv.On("FindAll", u.Query).Return(nil, nil)
+	c.On("PaginationQueryParam", &dto.PaginationQueryParams{}).Return(errors.New("Cannot parse query param"))
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewPermissionHandler(srv, v)
+
+	h.FindAll(c)
+
+	assert.Equal(u.T(), want, c.V)
+}
+
+func (u *PermissionHandlerTest) TestFindAllGrpcErrPermission() {
+	want := u.ServiceDownErr
+
+	srv := new(ServiceMock)
+	c := &ContextMock{
+		Permission:    u.Permission,
+		Permissions:   u.Permissions,
+		PermissionDto: u.PermissionDto,
+		Query:         u.Query,
+	}
+
+	srv.On("FindAll", u.Query).Return(nil, u.ServiceDownErr)
+	c.On("PaginationQueryParam", &dto.PaginationQueryParams{}).Return(nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewPermissionHandler(srv, v)
+
+	h.FindAll(c)
+
+	assert.Equal(u.T(), want, c.V)
+}
+
+func (u *PermissionHandlerTest) TestFindOnePermission() {
+	want := u.Permission
+
+	srv := new(ServiceMock)
+	c := &ContextMock{
+		Permission:    u.Permission,
+		Permissions:   u.Permissions,
+		PermissionDto: u.PermissionDto,
+		Query:         u.Query,
+	}
+
+	srv.On("FindOne", int32(1)).Return(u.Permission, nil)
+	c.On("ID").Return(1, nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewPermissionHandler(srv, v)
+
+	h.FindOne(c)
+
+	assert.Equal(u.T(), want, c.V)
+}
+
+func (u *PermissionHandlerTest) TestFindOneInvalidRequestParamIDPermission() {
+	want := u.InvalidIDErr
+
+	srv := new(ServiceMock)
+	c := &ContextMock{
+		Permission:    u.Permission,
+		Permissions:   u.Permissions,
+		PermissionDto: u.PermissionDto,
+		Query:         u.Query,
+	}
+
+	srv.On("FindOne", int32(1)).Return(nil, nil)
+	c.On("ID").Return(-1, errors.New("Invalid ID"))
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewPermissionHandler(srv, v)
+	h.FindOne(c)
+
+	assert.Equal(u.T(), want, c.V)
+}
+
+func (u *PermissionHandlerTest) TestFindOneErrorNotFoundPermission() {
+	want := u.NotFoundErr
+
+	srv := new(ServiceMock)
+	c := &ContextMock{
+		Permission:    u.Permission,
+		Permissions:   u.Permissions,
+		PermissionDto: u.PermissionDto,
+		Query:         u.Query,
+	}
+
+	srv.On("FindOne", int32(1)).Return(nil, u.NotFoundErr)
+	c.On("ID").Return(1, nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewPermissionHandler(srv, v)
+
+	h.FindOne(c)
+
+	assert.Equal(u.T(), want, c.V)
+}
+
+func (u *PermissionHandlerTest) TestFindOneGrpcErrPermission() {
+	want := u.ServiceDownErr
+
+	srv := new(ServiceMock)
+	c := &ContextMock{
+		Permission:    u.Permission,
+		Permissions:   u.Permissions,
+		PermissionDto: u.PermissionDto,
+		Query:         u.Query,
+	}
+
+	srv.On("FindOne", int32(1)).Return(nil, u.ServiceDownErr)
+	c.On("ID").Return(1, nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewPermissionHandler(srv, v)
+
+	h.FindOne(c)
+
+	assert.Equal(u.T(), want, c.V)
+}
+
+func (u *PermissionHandlerTest) TestCreatePermission() {
+	want := u.Permission
+
+	srv := new(ServiceMock)
+	c := &ContextMock{
+		Permission:    u.Permission,
+		Permissions:   u.Permissions,
+		PermissionDto: u.PermissionDto,
+		Query:         u.Query,
+	}
+
+	srv.On("Create", u.PermissionDto).Return(u.Permission, nil)
+	srv.On("FindByCode", u.PermissionDto.Code).Return(nil, u.NotFoundErr)
+	c.On("Bind", &dto.PermissionDto{}).Return(nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewPermissionHandler(srv, v)
+	h.Create(c)
+
+	assert.Equal(u.T(), want, c.V)
+}
+
+func (u *PermissionHandlerTest) TestCreateErrorDuplicatedPermission() {
+	want := &dto.ResponseErr{
+		StatusCode: http.StatusUnprocessableEntity,
+		Message:    "Duplicated permission name",
+	}
+
+	srv := new(ServiceMock)
+	c := &ContextMock{
+		Permission:    u.Permission,
+		Permissions:   u.Permissions,
+		PermissionDto: u.PermissionDto,
+		Query:         u.Query,
+	}
+
+	srv.On("Create", u.PermissionDto).Return(nil, want)
+	srv.On("FindByCode", u.PermissionDto.Code).Return(nil, u.NotFoundErr)
+	c.On("Bind", &dto.PermissionDto{}).Return(nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewPermissionHandler(srv, v)
+	h.Create(c)
+
+	assert.Equal(u.T(), want, c.V)
+}
+
+func (u *PermissionHandlerTest) TestCreateInvalidBodyRequest() {
+	want := &dto.ResponseErr{
+		StatusCode: http.StatusBadRequest,
+		Message:    "Cannot parse permission dto",
+	}
+
+	srv := new(ServiceMock)
+	c := &ContextMock{
+		Permission:    u.Permission,
+		Permissions:   u.Permissions,
+		PermissionDto: u.PermissionDto,
+		Query:         u.Query,
+	}
+
+	srv.On("Create", u.PermissionDto).Return(nil, nil)
+	srv.On("FindByCode", u.PermissionDto.Code).Return(nil, u.NotFoundErr)
+	c.On("Bind", &dto.PermissionDto{}).Return(errors.New("Cannot parse body request"))
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewPermissionHandler(srv, v)
+	h.Create(c)
+
+	assert.Equal(u.T(), want, c.V)
+}
+
+func (u *PermissionHandlerTest) TestCreateGrpcErrPermission() {
+	want := u.ServiceDownErr
+
+	srv := new(ServiceMock)
+	c := &ContextMock{
+		Permission:    u.Permission,
+		Permissions:   u.Permissions,
+		PermissionDto: u.PermissionDto,
+		Query:         u.Query,
+	}
+
+	srv.On("Create", u.PermissionDto).Return(nil, u.ServiceDownErr)
+	srv.On("FindByCode", u.PermissionDto.Code).Return(nil, u.NotFoundErr)
+	c.On("Bind", &dto.PermissionDto{}).Return(nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewPermissionHandler(srv, v)
+
+	h.Create(c)
+
+	assert.Equal(u.T(), want, c.V)
+}
+
+func (u *PermissionHandlerTest) TestUpdatePermission() {
+	want := u.Permission
+
+	srv := new(ServiceMock)
+	c := &ContextMock{
+		Permission:    u.Permission,
+		Permissions:   u.Permissions,
+		PermissionDto: u.PermissionDto,
+		Query:         u.Query,
+	}
+
+	srv.On("Update", int32(1), u.PermissionDto).Return(u.Permission, nil)
+	srv.On("FindByCode", u.PermissionDto.Code).Return(nil, u.NotFoundErr)
+	c.On("Bind", &dto.PermissionDto{}).Return(nil)
+	c.On("ID").Return(1, nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewPermissionHandler(srv, v)
+
+	h.Update(c)
+
+	assert.Equal(u.T(), want, c.V)
+}
+
+func (u *PermissionHandlerTest) TestUpdateInvalidRequestParamIDPermission() {
+	want := u.InvalidIDErr
+
+	srv := new(ServiceMock)
+	c := &ContextMock{
+		Permission:    u.Permission,
+		Permissions:   u.Permissions,
+		PermissionDto: u.PermissionDto,
+		Query:         u.Query,
+	}
+
+	srv.On("Update", int32(1), u.PermissionDto).Return(nil, nil)
+	srv.On("FindByCode", u.PermissionDto.Code).Return(nil, u.NotFoundErr)
+	c.On("ID").Return(-1, errors.New("Invalid ID"))
+	c.On("Bind", &dto.PermissionDto{}).Return(nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewPermissionHandler(srv, v)
+
+	h.Update(c)
+
+	assert.Equal(u.T(), want, c.V)
+}
+
+func (u *PermissionHandlerTest) TestUpdateInvalidBodyRequest() {
+	want := &dto.ResponseErr{
+		StatusCode: http.StatusBadRequest,
+		Message:    "Cannot parse permission dto",
+	}
+
+	srv := new(ServiceMock)
+	c := &ContextMock{
+		Permission:    u.Permission,
+		Permissions:   u.Permissions,
+		PermissionDto: u.PermissionDto,
+		Query:         u.Query,
+	}
+
+	srv.On("Update", int32(1), u.PermissionDto).Return(nil, nil)
+	srv.On("FindByCode", u.PermissionDto.Code).Return(nil, u.NotFoundErr)
+	c.On("ID").Return(1, nil)
+	c.On("Bind", &dto.PermissionDto{}).Return(errors.New("Cannot parse permission dto"))
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewPermissionHandler(srv, v)
+	h.Create(c)
+
+	assert.Equal(u.T(), want, c.V)
+}
+
+func (u *PermissionHandlerTest) TestUpdateErrorNotFoundPermission() {
+	want := u.NotFoundErr
+
+	srv := new(ServiceMock)
+	c := &ContextMock{
+		Permission:    u.Permission,
+		Permissions:   u.Permissions,
+		PermissionDto: u.PermissionDto,
+		Query:         u.Query,
+	}
+
+	srv.On("Update", int32(1), u.PermissionDto).Return(nil, u.NotFoundErr)
+	srv.On("FindByCode", u.PermissionDto.Code).Return(nil, u.NotFoundErr)
+	c.On("ID").Return(1, nil)
+	c.On("Bind", &dto.PermissionDto{}).Return(nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewPermissionHandler(srv, v)
+	h.Update(c)
+
+	assert.Equal(u.T(), want, c.V)
+}
+
+func (u *PermissionHandlerTest) TestUpdateGrpcErrPermission() {
+	want := u.ServiceDownErr
+
+	srv := new(ServiceMock)
+	c := &ContextMock{
+		Permission:    u.Permission,
+		Permissions:   u.Permissions,
+		PermissionDto: u.PermissionDto,
+		Query:         u.Query,
+	}
+
+	srv.On("Update", int32(1), u.PermissionDto).Return(nil, u.ServiceDownErr)
+	srv.On("FindByCode", u.PermissionDto.Code).Return(nil, u.NotFoundErr)
+	c.On("Bind", &dto.PermissionDto{}).Return(nil)
+	c.On("ID").Return(1, nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewPermissionHandler(srv, v)
+
+	h.Update(c)
+
+	assert.Equal(u.T(), want, c.V)
+}
+
+func (u *PermissionHandlerTest) TestDeletePermission() {
+	want := u.Permission
+
+	srv := new(ServiceMock)
+	c := &ContextMock{
+		Permission:    u.Permission,
+		Permissions:   u.Permissions,
+		PermissionDto: u.PermissionDto,
+		Query:         u.Query,
+	}
+
+	srv.On("Delete", int32(1)).Return(u.Permission, nil)
+	c.On("ID").Return(1, nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewPermissionHandler(srv, v)
+
+	h.Delete(c)
+
+	assert.Equal(u.T(), want, c.V)
+}
+
+func (u *PermissionHandlerTest) TestDeleteInvalidRequestParamIDPermission() {
+	want := u.InvalidIDErr
+
+	srv := new(ServiceMock)
+	c := &ContextMock{
+		Permission:    u.Permission,
+		Permissions:   u.Permissions,
+		PermissionDto: u.PermissionDto,
+		Query:         u.Query,
+	}
+
+	srv.On("Delete", int32(1)).Return(nil, nil)
+	c.On("ID").Return(-1, errors.New("Invalid ID"))
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewPermissionHandler(srv, v)
+
+	h.Delete(c)
+
+	assert.Equal(u.T(), want, c.V)
+}
+
+func (u *PermissionHandlerTest) TestDeleteErrorNotFoundPermission() {
+	want := u.NotFoundErr
+
+	srv := new(ServiceMock)
+	c := &ContextMock{
+		Permission:    u.Permission,
+		Permissions:   u.Permissions,
+		PermissionDto: u.PermissionDto,
+		Query:         u.Query,
+	}
+
+	srv.On("Delete", int32(1)).Return(nil, u.NotFoundErr)
+	c.On("ID").Return(1, nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewPermissionHandler(srv, v)
+
+	h.Delete(c)
+
+	assert.Equal(u.T(), want, c.V)
+}
+
+func (u *PermissionHandlerTest) TestDeleteGrpcErrPermission() {
+	want := u.ServiceDownErr
+
+	srv := new(ServiceMock)
+	c := &ContextMock{
+		Permission:    u.Permission,
+		Permissions:   u.Permissions,
+		PermissionDto: u.PermissionDto,
+		Query:         u.Query,
+	}
+
+	srv.On("Delete", int32(1)).Return(nil, u.ServiceDownErr)
+	c.On("ID").Return(1, nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewPermissionHandler(srv, v)
+
+	h.Delete(c)
+
+	assert.Equal(u.T(), want, c.V)
+}
+
+func (u *PermissionHandlerTest) TestCreateDuplicatedCodePermission() {
+	want := &dto.ResponseErr{
+		StatusCode: http.StatusUnprocessableEntity,
+		Message:    "Duplicated permission code",
+	}
+
+	srv := new(ServiceMock)
+	c := &ContextMock{
+		Permission:    u.Permission,
+		Permissions:   u.Permissions,
+		PermissionDto: u.PermissionDto,
+		Query:         u.Query,
+	}
+
+	srv.On("FindByCode", u.PermissionDto.Code).Return(u.Permission, nil)
+	c.On("Bind", &dto.PermissionDto{}).Return(nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewPermissionHandler(srv, v)
+	h.Create(c)
+
+	assert.Equal(u.T(), want, c.V)
+	srv.AssertNumberOfCalls(u.T(), "Create", 0)
+}
+
+func (u *PermissionHandlerTest) TestCreateMalformedCodePermission() {
+	srv := new(ServiceMock)
+	c := &ContextMock{
+		Permission: u.Permission,
+		PermissionDto: &dto.PermissionDto{
+			Name: faker.Word(),
+			Code: "User Update",
+		},
+		Query: u.Query,
+	}
+
+	c.On("Bind", &dto.PermissionDto{}).Return(nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewPermissionHandler(srv, v)
+	h.Create(c)
+
+	errRes, ok := c.V.(*dto.ResponseErr)
+
+	assert.True(u.T(), ok)
+	assert.Equal(u.T(), http.StatusBadRequest, errRes.StatusCode)
+	assert.Equal(u.T(), "permission_code", errRes.Data.([]*dto.BadReqErrResponse)[0].Tag)
+	srv.AssertNumberOfCalls(u.T(), "FindByCode", 0)
+}
+
+func (u *PermissionHandlerTest) TestUpdateKeepCodePermission() {
+	want := u.Permission
+
+	srv := new(ServiceMock)
+	c := &ContextMock{
+		Permission:    u.Permission,
+		Permissions:   u.Permissions,
+		PermissionDto: u.PermissionDto,
+		Query:         u.Query,
+	}
+
+	srv.On("FindByCode", u.PermissionDto.Code).Return(u.Permission, nil)
+	srv.On("Update", int32(1), u.PermissionDto).Return(u.Permission, nil)
+	c.On("Bind", &dto.PermissionDto{}).Return(nil)
+	c.On("ID").Return(1, nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewPermissionHandler(srv, v)
+	h.Update(c)
+
+	assert.Equal(u.T(), want, c.V)
+}
+
+func (u *PermissionHandlerTest) TestUpdateDuplicatedCodePermission() {
+	want := &dto.ResponseErr{
+		StatusCode: http.StatusUnprocessableEntity,
+		Message:    "Duplicated permission code",
+	}
+
+	srv := new(ServiceMock)
+	c := &ContextMock{
+		Permission:    u.Permission,
+		Permissions:   u.Permissions,
+		PermissionDto: u.PermissionDto,
+		Query:         u.Query,
+	}
+
+	srv.On("FindByCode", u.PermissionDto.Code).Return(u.Permissions[1], nil)
+	c.On("Bind", &dto.PermissionDto{}).Return(nil)
+	c.On("ID").Return(1, nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewPermissionHandler(srv, v)
+	h.Update(c)
+
+	assert.Equal(u.T(), want, c.V)
+	srv.AssertNumberOfCalls(u.T(), "Update", 0)
+}
@@ -0,0 +1,143 @@
+package permission
+
+import (
+	"github.com/bxcodec/faker/v3"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/dto"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/middleware"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"testing"
+)
+
+type PermissionGuardTest struct {
+	suite.Suite
+	UserId         int32
+	Roles          []*proto.Role
+	ForbiddenErr   *dto.ResponseErr
+	ServiceDownErr *dto.ResponseErr
+}
+
+func TestPermissionGuard(t *testing.T) {
+	suite.Run(t, new(PermissionGuardTest))
+}
+
+func (u *PermissionGuardTest) SetupTest() {
+	u.UserId = 1
+
+	u.Roles = []*proto.Role{
+		{
+			Id:   1,
+			Name: faker.Word(),
+			Permissions: []*proto.Permission{
+				{Id: 1, Name: faker.Word(), Code: "user:update"},
+			},
+		},
+		{
+			Id:   2,
+			Name: faker.Word(),
+			Permissions: []*proto.Permission{
+				{Id: 2, Name: faker.Word(), Code: "team:create"},
+				{Id: 3, Name: faker.Word(), Code: "team:delete"},
+			},
+		},
+	}
+
+	u.ForbiddenErr = &dto.ResponseErr{
+		StatusCode: http.StatusForbidden,
+		Message:    "Insufficient permission",
+		Data:       "team:update",
+	}
+
+	u.ServiceDownErr = &dto.ResponseErr{
+		StatusCode: http.StatusServiceUnavailable,
+		Message:    "Service is down",
+		Data:       nil,
+	}
+}
+
+func (u *PermissionGuardTest) TestRequireSuccess() {
+	srv := new(RoleServiceMock)
+	c := new(GuardContextMock)
+
+	c.On("UserID").Return(int(u.UserId))
+	c.On("Next")
+	srv.On("FindByUser", u.UserId).Return(u.Roles, nil)
+
+	g := middleware.NewPermissionGuard(srv)
+	g.Require("team:delete")(c)
+
+	assert.Nil(u.T(), c.V)
+	c.AssertNumberOfCalls(u.T(), "Next", 1)
+}
+
+func (u *PermissionGuardTest) TestRequireForbidden() {
+	want := u.ForbiddenErr
+
+	srv := new(RoleServiceMock)
+	c := new(GuardContextMock)
+
+	c.On("UserID").Return(int(u.UserId))
+	c.On("Next")
+	srv.On("FindByUser", u.UserId).Return(u.Roles, nil)
+
+	g := middleware.NewPermissionGuard(srv)
+	g.Require("team:update")(c)
+
+	assert.Equal(u.T(), want, c.V)
+	c.AssertNumberOfCalls(u.T(), "Next", 0)
+}
+
+func (u *PermissionGuardTest) TestRequireWithoutRoles() {
+	srv := new(RoleServiceMock)
+	c := new(GuardContextMock)
+
+	c.On("UserID").Return(int(u.UserId))
+	c.On("Next")
+	srv.On("FindByUser", u.UserId).Return([]*proto.Role{}, nil)
+
+	g := middleware.NewPermissionGuard(srv)
+	g.Require("user:update")(c)
+
+	errRes, ok := c.V.(*dto.ResponseErr)
+
+	assert.True(u.T(), ok)
+	assert.Equal(u.T(), http.StatusForbidden, errRes.StatusCode)
+	c.AssertNumberOfCalls(u.T(), "Next", 0)
+}
+
+func (u *PermissionGuardTest) TestRequireUnauthenticated() {
+	srv := new(RoleServiceMock)
+	c := new(GuardContextMock)
+
+	c.On("UserID").Return(-1)
+	c.On("Next")
+
+	g := middleware.NewPermissionGuard(srv)
+	g.Require("user:update")(c)
+
+	errRes, ok := c.V.(*dto.ResponseErr)
+
+	assert.True(u.T(), ok)
+	assert.Equal(u.T(), http.StatusUnauthorized, errRes.StatusCode)
+	srv.AssertNumberOfCalls(u.T(), "FindByUser", 0)
+	c.AssertNumberOfCalls(u.T(), "Next", 0)
+}
+
+func (u *PermissionGuardTest) TestRequireGrpcErr() {
+	want := u.ServiceDownErr
+
+	srv := new(RoleServiceMock)
+	c := new(GuardContextMock)
+
+	c.On("UserID").Return(int(u.UserId))
+	c.On("Next")
+	srv.On("FindByUser", u.UserId).Return(nil, u.ServiceDownErr)
+
+	g := middleware.NewPermissionGuard(srv)
+	g.Require("user:update")(c)
+
+	assert.Equal(u.T(), want, c.V)
+	c.AssertNumberOfCalls(u.T(), "Next", 0)
+}
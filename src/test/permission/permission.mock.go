@@ -0,0 +1,232 @@
+package permission
+
+import (
+	"context"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/dto"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/proto"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc"
+)
+
+type ContextMock struct {
+	mock.Mock
+	V             interface{}
+	Permission    *proto.Permission
+	Permissions   []*proto.Permission
+	PermissionDto *dto.PermissionDto
+	Query         *dto.PaginationQueryParams
+}
+
+func (c *ContextMock) Bind(v interface{}) error {
+	args := c.Called(v)
+
+	*v.(*dto.PermissionDto) = *c.PermissionDto
+
+	return args.Error(0)
+}
+
+func (c *ContextMock) JSON(_ int, v interface{}) {
+	c.V = v
+}
+
+func (c *ContextMock) ID() (int32, error) {
+	args := c.Called()
+
+	return int32(args.Int(0)), args.Error(1)
+}
+
+func (c *ContextMock) PaginationQueryParam(query *dto.PaginationQueryParams) error {
+	args := c.Called(query)
+
+	*query = *c.Query
+
+	return args.Error(0)
+}
+
+type ServiceMock struct {
+	mock.Mock
+}
+
+func (s *ServiceMock) FindAll(_ context.Context, query *dto.PaginationQueryParams) (res *proto.PermissionPagination, err *dto.ResponseErr) {
+	args := s.Called(query)
+
+	if args.Get(0) != nil {
+		res = args.Get(0).(*proto.PermissionPagination)
+	}
+
+	if args.Get(1) != nil {
+		err = args.Get(1).(*dto.ResponseErr)
+	}
+
+	return
+}
+
+func (s *ServiceMock) FindOne(_ context.Context, id int32) (res *proto.Permission, err *dto.ResponseErr) {
+	args := s.Called(id)
+
+	if args.Get(0) != nil {
+		res = args.Get(0).(*proto.Permission)
+	}
+
+	if args.Get(1) != nil {
+		err = args.Get(1).(*dto.ResponseErr)
+	}
+
+	return
+}
+
+func (s *ServiceMock) FindByCode(_ context.Context, code string) (res *proto.Permission, err *dto.ResponseErr) {
+	args := s.Called(code)
+
+	if args.Get(0) != nil {
+		res = args.Get(0).(*proto.Permission)
+	}
+
+	if args.Get(1) != nil {
+		err = args.Get(1).(*dto.ResponseErr)
+	}
+
+	return
+}
+
+func (s *ServiceMock) Create(_ context.Context, permission *dto.PermissionDto) (res *proto.Permission, err *dto.ResponseErr) {
+	args := s.Called(permission)
+
+	if args.Get(0) != nil {
+		res = args.Get(0).(*proto.Permission)
+	}
+
+	if args.Get(1) != nil {
+		err = args.Get(1).(*dto.ResponseErr)
+	}
+
+	return
+}
+
+func (s *ServiceMock) Update(_ context.Context, id int32, permission *dto.PermissionDto) (res *proto.Permission, err *dto.ResponseErr) {
+	args := s.Called(id, permission)
+
+	if args.Get(0) != nil {
+		res = args.Get(0).(*proto.Permission)
+	}
+
+	if args.Get(1) != nil {
+		err = args.Get(1).(*dto.ResponseErr)
+	}
+
+	return
+}
+
+func (s *ServiceMock) Delete(_ context.Context, id int32) (res *proto.Permission, err *dto.ResponseErr) {
+	args := s.Called(id)
+
+	if args.Get(0) != nil {
+		res = args.Get(0).(*proto.Permission)
+	}
+
+	if args.Get(1) != nil {
+		err = args.Get(1).(*dto.ResponseErr)
+	}
+
+	return
+}
+
+type ClientMock struct {
+	mock.Mock
+}
+
+func (c *ClientMock) FindAll(ctx context.Context, in *proto.FindAllPermissionRequest, opts ...grpc.CallOption) (res *proto.PermissionListResponse, err error) {
+	args := c.Called(in)
+
+	if args.Get(0) != nil {
+		res = args.Get(0).(*proto.PermissionListResponse)
+	}
+
+	return res, args.Error(1)
+}
+
+func (c *ClientMock) FindOne(ctx context.Context, in *proto.FindOnePermissionRequest, opts ...grpc.CallOption) (res *proto.PermissionResponse, err error) {
+	args := c.Called(in)
+
+	if args.Get(0) != nil {
+		res = args.Get(0).(*proto.PermissionResponse)
+	}
+
+	return res, args.Error(1)
+}
+
+func (c *ClientMock) Create(ctx context.Context, in *proto.CreatePermissionRequest, opts ...grpc.CallOption) (res *proto.PermissionResponse, err error) {
+	args := c.Called(in.Permission)
+
+	if args.Get(0) != nil {
+		res = args.Get(0).(*proto.PermissionResponse)
+	}
+
+	return res, args.Error(1)
+}
+
+func (c *ClientMock) Update(ctx context.Context, in *proto.UpdatePermissionRequest, opts ...grpc.CallOption) (res *proto.PermissionResponse, err error) {
+	args := c.Called(in.Permission)
+
+	if args.Get(0) != nil {
+		res = args.Get(0).(*proto.PermissionResponse)
+	}
+
+	return res, args.Error(1)
+}
+
+func (c *ClientMock) Delete(ctx context.Context, in *proto.DeletePermissionRequest, opts ...grpc.CallOption) (res *proto.PermissionResponse, err error) {
+	args := c.Called(in)
+
+	if args.Get(0) != nil {
+		res = args.Get(0).(*proto.PermissionResponse)
+	}
+
+	return res, args.Error(1)
+}
+
+// RoleServiceMock lets the permission guard run with fixed roles instead of the upstream role service
+type RoleServiceMock struct {
+	mock.Mock
+}
+
+func (s *RoleServiceMock) FindByUser(_ context.Context, userId int32) (res []*proto.Role, err *dto.ResponseErr) {
+	args := s.Called(userId)
+
+	if args.Get(0) != nil {
+		res = args.Get(0).([]*proto.Role)
+	}
+
+	if args.Get(1) != nil {
+		err = args.Get(1).(*dto.ResponseErr)
+	}
+
+	return
+}
+
+type GuardContextMock struct {
+	mock.Mock
+	V interface{}
+}
+
+func (c *GuardContextMock) UserContext() context.Context {
+	return context.Background()
+}
+
+func (c *GuardContextMock) UserID() int32 {
+	args := c.Called()
+
+	return int32(args.Int(0))
+}
+
+func (c *GuardContextMock) JSON(_ int, v interface{}) {
+	c.V = v
+}
+
+func (c *GuardContextMock) Next() {
+	_ = c.Called()
+}
+
+func (c *ContextMock) UserContext() context.Context {
+	return context.Background()
+}
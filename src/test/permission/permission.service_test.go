@@ -0,0 +1,422 @@
+package permission
+
+import (
+	"context"
+	"github.com/bxcodec/faker/v3"
+	"github.com/pkg/errors"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/constant"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/dto"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/proto"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"testing"
+)
+
+type PermissionServiceTest struct {
+	suite.Suite
+	Permission     *proto.Permission
+	PermissionReq  *proto.Permission
+	Permissions    []*proto.Permission
+	PermissionDto  *dto.PermissionDto
+	Query          *dto.PaginationQueryParams
+	NotFoundErr    *dto.ResponseErr
+	ServiceDownErr *dto.ResponseErr
+}
+
+func TestPermissionService(t *testing.T) {
+	suite.Run(t, new(PermissionServiceTest))
+}
+
+func (s *PermissionServiceTest) SetupTest() {
+	s.Permission = &proto.Permission{
+		Id:   1,
+		Name: faker.Word(),
+		Code: faker.Word() + ":" + faker.Word(),
+	}
+
+	s.PermissionReq = &proto.Permission{
+		Name: s.Permission.Name,
+		Code: s.Permission.Code,
+	}
+
+	s.PermissionDto = &dto.PermissionDto{
+		Name: s.Permission.Name,
+		Code: s.Permission.Code,
+	}
+
+	Permission2 := &proto.Permission{
+		Id:   2,
+		Name: faker.Word(),
+		Code: faker.Word() + ":" + faker.Word(),
+	}
+
+	Permission3 := &proto.Permission{
+		Id:   3,
+		Name: faker.Word(),
+		Code: faker.Word() + ":" + faker.Word(),
+	}
+
+	Permission4 := &proto.Permission{
+		Id:   4,
+		Name: faker.Word(),
+		Code: faker.Word() + ":" + faker.Word(),
+	}
+
+	s.Permissions = append(s.Permissions, s.Permission, Permission2, Permission3, Permission4)
+
+	_ = faker.FakeData(&s.Query)
+
+	s.ServiceDownErr = &dto.ResponseErr{
+		StatusCode: http.StatusServiceUnavailable,
+		Message:    "Service is down",
+		Data:       nil,
+	}
+
+	s.NotFoundErr = &dto.ResponseErr{
+		StatusCode: http.StatusNotFound,
+		Message:    "Not found permission",
+		Data:       nil,
+	}
+}
+
+func (s *PermissionServiceTest) TestFindAllPermissionService() {
+	want := &proto.PermissionPagination{
+		Items: s.Permissions,
+		Meta: &proto.PaginationMetadata{
+			TotalItem:    4,
+			ItemCount:    4,
+			ItemsPerPage: 10,
+			TotalPage:    1,
+			CurrentPage:  1,
+		},
+	}
+
+	client := new(ClientMock)
+
+	client.On("FindAll", &proto.FindAllPermissionRequest{
+		Limit: s.Query.Limit,
+		Page:  s.Query.Page,
+	}).Return(&proto.PermissionListResponse{
+		StatusCode: http.StatusOK,
+		Errors:     nil,
+		Data:       want,
+	}, nil)
+
+	srv := service.NewPermissionService(client)
+
+	permissions, err := srv.FindAll(context.Background(), s.Query)
+
+	assert.Nil(s.T(), err, "Must not got any error")
+	assert.Equal(s.T(), want, permissions)
+}
+
+func (s *PermissionServiceTest) TestFindAllGrpcErrPermissionService() {
+	want := s.ServiceDownErr
+
+	client := new(ClientMock)
+
+	client.On("FindAll", &proto.FindAllPermissionRequest{
+		Limit: s.Query.Limit,
+		Page:  s.Query.Page,
+	}).Return(nil, errors.New("Service is down"))
+
+	srv := service.NewPermissionService(client)
+
+	_, err := srv.FindAll(context.Background(), s.Query)
+
+	assert.Equal(s.T(), want, err)
+}
+
+func (s *PermissionServiceTest) TestFindByCodePermissionService() {
+	want := s.Permissions[3]
+
+	client := new(ClientMock)
+
+	client.On("FindAll", &proto.FindAllPermissionRequest{
+		Limit: constant.FetchAllLimit,
+		Page:  1,
+	}).Return(&proto.PermissionListResponse{
+		StatusCode: http.StatusOK,
+		Errors:     nil,
+		Data: &proto.PermissionPagination{
+			Items: s.Permissions[:2],
+			Meta:  &proto.PaginationMetadata{TotalPage: 2, CurrentPage: 1},
+		},
+	}, nil)
+
+	client.On("FindAll", &proto.FindAllPermissionRequest{
+		Limit: constant.FetchAllLimit,
+		Page:  2,
+	}).Return(&proto.PermissionListResponse{
+		StatusCode: http.StatusOK,
+		Errors:     nil,
+		Data: &proto.PermissionPagination{
+			Items: s.Permissions[2:],
+			Meta:  &proto.PaginationMetadata{TotalPage: 2, CurrentPage: 2},
+		},
+	}, nil)
+
+	srv := service.NewPermissionService(client)
+
+	permission, err := srv.FindByCode(context.Background(), want.Code)
+
+	assert.Nil(s.T(), err, "Must not got any error")
+	assert.Equal(s.T(), want, permission)
+}
+
+func (s *PermissionServiceTest) TestFindByCodeNotFoundPermissionService() {
+	want := s.NotFoundErr
+
+	client := new(ClientMock)
+
+	client.On("FindAll", &proto.FindAllPermissionRequest{
+		Limit: constant.FetchAllLimit,
+		Page:  1,
+	}).Return(&proto.PermissionListResponse{
+		StatusCode: http.StatusOK,
+		Errors:     nil,
+		Data: &proto.PermissionPagination{
+			Items: s.Permissions,
+			Meta:  &proto.PaginationMetadata{TotalPage: 1, CurrentPage: 1},
+		},
+	}, nil)
+
+	srv := service.NewPermissionService(client)
+
+	permission, err := srv.FindByCode(context.Background(), "not:existed")
+
+	assert.Nil(s.T(), permission)
+	assert.Equal(s.T(), want, err)
+}
+
+func (s *PermissionServiceTest) TestFindOnePermissionService() {
+	want := s.Permission
+
+	var id int32
+	_ = faker.FakeData(&id)
+
+	client := new(ClientMock)
+
+	client.On("FindOne", &proto.FindOnePermissionRequest{Id: id}).Return(&proto.PermissionResponse{
+		StatusCode: http.StatusOK,
+		Errors:     nil,
+		Data:       s.Permission,
+	}, nil)
+
+	srv := service.NewPermissionService(client)
+
+	permission, err := srv.FindOne(context.Background(), id)
+
+	assert.Nil(s.T(), err, "Must not got any error")
+	assert.Equal(s.T(), want, permission)
+}
+
+func (s *PermissionServiceTest) TestFindOneNotFoundPermissionService() {
+	want := s.NotFoundErr
+
+	var id int32
+	_ = faker.FakeData(&id)
+
+	client := new(ClientMock)
+
+	client.On("FindOne", &proto.FindOnePermissionRequest{Id: id}).Return(&proto.PermissionResponse{
+		StatusCode: http.StatusNotFound,
+		Errors:     []string{"Not found permission"},
+		Data:       nil,
+	}, nil)
+
+	srv := service.NewPermissionService(client)
+
+	permission, err := srv.FindOne(context.Background(), id)
+
+	assert.Nil(s.T(), permission)
+	assert.Equal(s.T(), want, err)
+}
+
+func (s *PermissionServiceTest) TestFindOneGrpcErrPermissionService() {
+	want := s.ServiceDownErr
+
+	var id int32
+	_ = faker.FakeData(&id)
+
+	client := new(ClientMock)
+
+	client.On("FindOne", &proto.FindOnePermissionRequest{Id: id}).Return(&proto.PermissionResponse{}, errors.New("Service is down"))
+
+	srv := service.NewPermissionService(client)
+
+	_, err := srv.FindOne(context.Background(), id)
+
+	assert.Equal(s.T(), want, err)
+}
+
+func (s *PermissionServiceTest) TestCreatePermissionService() {
+	want := s.Permission
+
+	client := new(ClientMock)
+
+	client.On("Create", s.PermissionReq).Return(&proto.PermissionResponse{
+		StatusCode: http.StatusCreated,
+		Errors:     nil,
+		Data:       s.Permission,
+	}, nil)
+
+	srv := service.NewPermissionService(client)
+
+	permission, err := srv.Create(context.Background(), s.PermissionDto)
+
+	assert.Nil(s.T(), err, "Must not got any error")
+	assert.Equal(s.T(), want, permission)
+}
+
+func (s *PermissionServiceTest) TestCreateDuplicatedPermissionService() {
+	want := &dto.ResponseErr{
+		StatusCode: http.StatusUnprocessableEntity,
+		Message:    "Duplicated email or permissionname",
+		Data:       nil,
+	}
+
+	client := new(ClientMock)
+
+	client.On("Create", s.PermissionReq).Return(&proto.PermissionResponse{
+		StatusCode: http.StatusUnprocessableEntity,
+		Errors:     []string{"Duplicated email or permissionname"},
+		Data:       nil,
+	}, nil)
+
+	srv := service.NewPermissionService(client)
+
+	permission, err := srv.Create(context.Background(), s.PermissionDto)
+
+	assert.Nil(s.T(), permission)
+	assert.Equal(s.T(), want, err)
+}
+
+func (s *PermissionServiceTest) TestCreateGrpcErrPermissionService() {
+	want := s.ServiceDownErr
+
+	client := new(ClientMock)
+
+	client.On("Create", s.PermissionReq).Return(&proto.PermissionResponse{}, errors.New("Service is down"))
+
+	srv := service.NewPermissionService(client)
+
+	_, err := srv.Create(context.Background(), s.PermissionDto)
+
+	assert.Equal(s.T(), want, err)
+}
+
+func (s *PermissionServiceTest) TestUpdatePermissionService() {
+	want := s.Permission
+
+	client := new(ClientMock)
+
+	client.On("Update", s.Permission).Return(&proto.PermissionResponse{
+		StatusCode: http.StatusOK,
+		Errors:     nil,
+		Data:       s.Permission,
+	}, nil)
+
+	srv := service.NewPermissionService(client)
+
+	permission, err := srv.Update(context.Background(), 1, s.PermissionDto)
+
+	assert.Nil(s.T(), err, "Must not got any error")
+	assert.Equal(s.T(), want, permission)
+}
+
+func (s *PermissionServiceTest) TestUpdateNotFoundPermissionService() {
+	want := s.NotFoundErr
+
+	client := new(ClientMock)
+
+	client.On("Update", s.Permission).Return(&proto.PermissionResponse{
+		StatusCode: http.StatusNotFound,
+		Errors:     []string{"Not found permission"},
+		Data:       nil,
+	}, nil)
+
+	srv := service.NewPermissionService(client)
+
+	permission, err := srv.Update(context.Background(), 1, s.PermissionDto)
+
+	assert.Nil(s.T(), permission)
+	assert.Equal(s.T(), want, err)
+}
+
+func (s *PermissionServiceTest) TestUpdateGrpcErrPermissionService() {
+	want := s.ServiceDownErr
+
+	client := new(ClientMock)
+
+	client.On("Update", s.Permission).Return(&proto.PermissionResponse{}, errors.New("Service is down"))
+
+	srv := service.NewPermissionService(client)
+
+	_, err := srv.Update(context.Background(), 1, s.PermissionDto)
+
+	assert.Equal(s.T(), want, err)
+}
+
+func (s *PermissionServiceTest) TestDeletePermissionService() {
+	want := s.Permission
+
+	var id int32
+	_ = faker.FakeData(&id)
+
+	client := new(ClientMock)
+
+	client.On("Delete", &proto.DeletePermissionRequest{Id: id}).Return(&proto.PermissionResponse{
+		StatusCode: http.StatusOK,
+		Errors:     nil,
+		Data:       s.Permission,
+	}, nil)
+
+	srv := service.NewPermissionService(client)
+
+	permission, err := srv.Delete(context.Background(), id)
+
+	assert.Nil(s.T(), err, "Must not got any error")
+	assert.Equal(s.T(), want, permission)
+}
+
+func (s *PermissionServiceTest) TestDeleteNotFoundPermissionService() {
+	want := s.NotFoundErr
+
+	var id int32
+	_ = faker.FakeData(&id)
+
+	client := new(ClientMock)
+
+	client.On("Delete", &proto.DeletePermissionRequest{Id: id}).Return(&proto.PermissionResponse{
+		StatusCode: http.StatusNotFound,
+		Errors:     []string{"Not found permission"},
+		Data:       nil,
+	}, nil)
+
+	srv := service.NewPermissionService(client)
+
+	permission, err := srv.Delete(context.Background(), id)
+
+	assert.Nil(s.T(), permission)
+	assert.Equal(s.T(), want, err)
+}
+
+func (s *PermissionServiceTest) TestDeleteGrpcErrPermissionService() {
+	want := s.ServiceDownErr
+
+	var id int32
+	_ = faker.FakeData(&id)
+
+	client := new(ClientMock)
+
+	client.On("Delete", &proto.DeletePermissionRequest{Id: id}).Return(&proto.PermissionResponse{}, errors.New("Service is down"))
+
+	srv := service.NewPermissionService(client)
+
+	_, err := srv.Delete(context.Background(), id)
+
+	assert.Equal(s.T(), want, err)
+}
@@ -0,0 +1,120 @@
+package ratelimit
+
+import (
+	"github.com/samithiwat/samithiwat-backend-gateway/src/dto"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"testing"
+	"time"
+)
+
+type RateLimiterTest struct {
+	suite.Suite
+	Allowed  *dto.RateLimit
+	Rejected *dto.RateLimit
+}
+
+func TestRateLimiter(t *testing.T) {
+	suite.Run(t, new(RateLimiterTest))
+}
+
+func (u *RateLimiterTest) SetupTest() {
+	u.Allowed = &dto.RateLimit{Allowed: true, Limit: 10, Remaining: 9, Reset: 6 * time.Second}
+	u.Rejected = &dto.RateLimit{Allowed: false, Limit: 10, Remaining: 0, Reset: 59500 * time.Millisecond, RetryAfter: 5500 * time.Millisecond}
+}
+
+func (u *RateLimiterTest) TestAllowed() {
+	srv := new(ServiceMock)
+	c := &ContextMock{ClientIP: "10.0.0.1"}
+
+	c.On("Principal").Return(&dto.Principal{UserID: -1})
+	c.On("Next")
+	srv.On("Take", "login", "ip:10.0.0.1").Return(u.Allowed)
+
+	middleware.NewRateLimiter(srv).Limit("login")(c)
+
+	c.AssertNumberOfCalls(u.T(), "Next", 1)
+	assert.Equal(u.T(), map[string]string{
+		"RateLimit-Limit":     "10",
+		"RateLimit-Remaining": "9",
+		"RateLimit-Reset":     "6",
+	}, c.ResponseHeader)
+}
+
+func (u *RateLimiterTest) TestRejected() {
+	want := &dto.ResponseErr{
+		StatusCode: http.StatusTooManyRequests,
+		Message:    "Too many requests, try again later",
+		Data:       nil,
+	}
+
+	srv := new(ServiceMock)
+	c := &ContextMock{ClientIP: "10.0.0.1"}
+
+	c.On("Principal").Return(&dto.Principal{UserID: -1})
+	srv.On("Take", "login", "ip:10.0.0.1").Return(u.Rejected)
+
+	middleware.NewRateLimiter(srv).Limit("login")(c)
+
+	assert.Equal(u.T(), http.StatusTooManyRequests, c.Status)
+	assert.Equal(u.T(), want, c.V)
+	assert.Equal(u.T(), "6", c.ResponseHeader["Retry-After"])
+	assert.Equal(u.T(), "60", c.ResponseHeader["RateLimit-Reset"])
+	assert.Equal(u.T(), "0", c.ResponseHeader["RateLimit-Remaining"])
+	c.AssertNotCalled(u.T(), "Next")
+}
+
+func (u *RateLimiterTest) TestKeyOnUser() {
+	srv := new(ServiceMock)
+	c := &ContextMock{ClientIP: "10.0.0.1"}
+
+	c.On("Principal").Return(&dto.Principal{UserID: 1})
+	c.On("Next")
+	srv.On("Take", "read", "user:1").Return(u.Allowed)
+
+	middleware.NewRateLimiter(srv).Limit("read")(c)
+
+	srv.AssertCalled(u.T(), "Take", "read", "user:1")
+}
+
+func (u *RateLimiterTest) TestKeyOnApiKey() {
+	srv := new(ServiceMock)
+	c := &ContextMock{ClientIP: "10.0.0.1"}
+
+	c.On("Principal").Return(&dto.Principal{UserID: -1, ApiKeyID: "billing"})
+	c.On("Next")
+	srv.On("Take", "read", "apikey:billing").Return(u.Allowed)
+
+	middleware.NewRateLimiter(srv).Limit("read")(c)
+
+	srv.AssertCalled(u.T(), "Take", "read", "apikey:billing")
+}
+
+func (u *RateLimiterTest) TestPolicyNotConfigured() {
+	srv := new(ServiceMock)
+	c := &ContextMock{ClientIP: "10.0.0.1"}
+
+	c.On("Principal").Return(&dto.Principal{UserID: -1})
+	c.On("Next")
+	srv.On("Take", "write", "ip:10.0.0.1").Return(nil)
+
+	middleware.NewRateLimiter(srv).Limit("write")(c)
+
+	c.AssertNumberOfCalls(u.T(), "Next", 1)
+	assert.Nil(u.T(), c.ResponseHeader)
+}
+
+func (u *RateLimiterTest) TestLimitClientKeyOnIP() {
+	srv := new(ServiceMock)
+	c := &ContextMock{ClientIP: "10.0.0.1"}
+
+	c.On("Next")
+	srv.On("Take", "client", "ip:10.0.0.1").Return(u.Allowed)
+
+	middleware.NewRateLimiter(srv).LimitClient("client")(c)
+
+	c.AssertNumberOfCalls(u.T(), "Next", 1)
+	c.AssertNotCalled(u.T(), "Principal")
+}
@@ -0,0 +1,55 @@
+package ratelimit
+
+import (
+	"github.com/samithiwat/samithiwat-backend-gateway/src/dto"
+	"github.com/stretchr/testify/mock"
+)
+
+type ContextMock struct {
+	mock.Mock
+	V              interface{}
+	Status         int
+	ClientIP       string
+	ResponseHeader map[string]string
+}
+
+func (c *ContextMock) Principal() *dto.Principal {
+	args := c.Called()
+
+	return args.Get(0).(*dto.Principal)
+}
+
+func (c *ContextMock) IP() string {
+	return c.ClientIP
+}
+
+func (c *ContextMock) SetResponseHeader(key string, val string) {
+	if c.ResponseHeader == nil {
+		c.ResponseHeader = map[string]string{}
+	}
+
+	c.ResponseHeader[key] = val
+}
+
+func (c *ContextMock) JSON(status int, v interface{}) {
+	c.Status = status
+	c.V = v
+}
+
+func (c *ContextMock) Next() {
+	_ = c.Called()
+}
+
+type ServiceMock struct {
+	mock.Mock
+}
+
+func (s *ServiceMock) Take(policy string, key string) *dto.RateLimit {
+	args := s.Called(policy, key)
+
+	if args.Get(0) != nil {
+		return args.Get(0).(*dto.RateLimit)
+	}
+
+	return nil
+}
@@ -0,0 +1,158 @@
+package ratelimit
+
+import (
+	"bufio"
+	"github.com/go-redis/redis/v8"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeRedis answers the commands with the replies of the test and records them with their names in upper case
+type fakeRedis struct {
+	listener net.Listener
+	reply    func([]string) string
+	mu       sync.Mutex
+	commands [][]string
+}
+
+func newFakeRedis(reply func([]string) string) *fakeRedis {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		panic(err)
+	}
+
+	f := &fakeRedis{listener: listener, reply: reply}
+	go f.serve()
+
+	return f
+}
+
+func (f *fakeRedis) serve() {
+	for {
+		conn, err := f.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		go f.handle(conn)
+	}
+}
+
+func (f *fakeRedis) handle(conn net.Conn) {
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+
+		n, _ := strconv.Atoi(strings.TrimSpace(line[1:]))
+		args := make([]string, n)
+		for i := range args {
+			header, _ := r.ReadString('\n')
+			size, _ := strconv.Atoi(strings.TrimSpace(header[1:]))
+			buf := make([]byte, size+2)
+			if _, err := io.ReadFull(r, buf); err != nil {
+				return
+			}
+			args[i] = string(buf[:size])
+		}
+		args[0] = strings.ToUpper(args[0])
+
+		f.mu.Lock()
+		f.commands = append(f.commands, args)
+		f.mu.Unlock()
+
+		_, _ = conn.Write([]byte(f.reply(args)))
+	}
+}
+
+func (f *fakeRedis) names() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var result []string
+	for _, c := range f.commands {
+		result = append(result, c[0])
+	}
+
+	return result
+}
+
+type RedisRateLimitStoreTest struct {
+	suite.Suite
+	Policy service.RateLimitPolicy
+}
+
+func TestRedisRateLimitStore(t *testing.T) {
+	suite.Run(t, new(RedisRateLimitStoreTest))
+}
+
+func (s *RedisRateLimitStoreTest) SetupTest() {
+	s.Policy = service.RateLimitPolicy{Limit: 3, Period: time.Minute}
+}
+
+func (s *RedisRateLimitStoreTest) TestTakeFallsBackToEval() {
+	server := newFakeRedis(func(args []string) string {
+		switch args[0] {
+		case "AUTH", "SELECT":
+			return "+OK\r\n"
+		case "EVALSHA":
+			return "-NOSCRIPT No matching script. Please use EVAL.\r\n"
+		default:
+			return "*2\r\n:1\r\n$3\r\n1.5\r\n"
+		}
+	})
+	defer server.listener.Close()
+
+	client := redis.NewClient(&redis.Options{Addr: server.listener.Addr().String(), Password: "secret", DB: 2, PoolSize: 1})
+	store := service.NewRedisRateLimitStore(client, "ratelimit:")
+
+	allowed, tokens, err := store.Take("login:ip:10.0.0.1", s.Policy, time.Unix(1, 0))
+
+	assert.Nil(s.T(), err)
+	assert.True(s.T(), allowed)
+	assert.Equal(s.T(), 1.5, tokens)
+	assert.Equal(s.T(), []string{"AUTH", "SELECT", "EVALSHA", "EVAL"}, server.names())
+
+	eval := server.commands[3]
+	assert.Equal(s.T(), []string{"1", "ratelimit:login:ip:10.0.0.1", "3", "60000", "1000"}, eval[2:])
+}
+
+func (s *RedisRateLimitStoreTest) TestTakeRejected() {
+	server := newFakeRedis(func(args []string) string {
+		return "*2\r\n:0\r\n$4\r\n0.25\r\n"
+	})
+	defer server.listener.Close()
+
+	store := service.NewRedisRateLimitStore(redis.NewClient(&redis.Options{Addr: server.listener.Addr().String(), PoolSize: 1}), "")
+
+	allowed, tokens, err := store.Take("key", s.Policy, time.Now())
+
+	assert.Nil(s.T(), err)
+	assert.False(s.T(), allowed)
+	assert.Equal(s.T(), 0.25, tokens)
+	assert.Equal(s.T(), []string{"EVALSHA"}, server.names())
+}
+
+func (s *RedisRateLimitStoreTest) TestServerDown() {
+	listener, _ := net.Listen("tcp", "127.0.0.1:0")
+	addr := listener.Addr().String()
+	_ = listener.Close()
+
+	store := service.NewRedisRateLimitStore(redis.NewClient(&redis.Options{Addr: addr, MaxRetries: -1}), "")
+
+	_, _, err := store.Take("key", s.Policy, time.Now())
+
+	assert.NotNil(s.T(), err)
+}
@@ -0,0 +1,81 @@
+package ratelimit
+
+import (
+	"github.com/pkg/errors"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type RateLimitServiceTest struct {
+	suite.Suite
+	Policy service.RateLimitPolicy
+}
+
+func TestRateLimitService(t *testing.T) {
+	suite.Run(t, new(RateLimitServiceTest))
+}
+
+func (s *RateLimitServiceTest) SetupTest() {
+	s.Policy = service.RateLimitPolicy{Limit: 3, Period: time.Minute}
+}
+
+type failingStore struct{}
+
+func (failingStore) Take(string, service.RateLimitPolicy, time.Time) (bool, float64, error) {
+	return false, 0, errors.New("connection refused")
+}
+
+func (s *RateLimitServiceTest) TestBurstThenReject() {
+	srv := service.NewRateLimitService(service.NewMemoryRateLimitStore(), map[string]service.RateLimitPolicy{"login": s.Policy})
+
+	for i := 2; i >= 0; i-- {
+		state := srv.Take("login", "ip:10.0.0.1")
+		assert.True(s.T(), state.Allowed)
+		assert.Equal(s.T(), 3, state.Limit)
+		assert.Equal(s.T(), i, state.Remaining)
+	}
+
+	state := srv.Take("login", "ip:10.0.0.1")
+	assert.False(s.T(), state.Allowed)
+	assert.True(s.T(), state.RetryAfter > 19*time.Second && state.RetryAfter <= 20*time.Second)
+	assert.True(s.T(), state.Reset > 59*time.Second && state.Reset <= time.Minute)
+
+	assert.True(s.T(), srv.Take("login", "ip:10.0.0.2").Allowed)
+}
+
+func (s *RateLimitServiceTest) TestPolicyNotConfigured() {
+	srv := service.NewRateLimitService(service.NewMemoryRateLimitStore(), map[string]service.RateLimitPolicy{"login": s.Policy})
+
+	assert.Nil(s.T(), srv.Take("read", "ip:10.0.0.1"))
+}
+
+func (s *RateLimitServiceTest) TestStoreFailureDoesNotLimit() {
+	srv := service.NewRateLimitService(failingStore{}, map[string]service.RateLimitPolicy{"login": s.Policy})
+
+	assert.Nil(s.T(), srv.Take("login", "ip:10.0.0.1"))
+}
+
+func (s *RateLimitServiceTest) TestMemoryStoreRefill() {
+	store := service.NewMemoryRateLimitStore()
+	now := time.Now()
+
+	for i := 0; i < 3; i++ {
+		allowed, _, _ := store.Take("key", s.Policy, now)
+		assert.True(s.T(), allowed)
+	}
+
+	allowed, tokens, _ := store.Take("key", s.Policy, now)
+	assert.False(s.T(), allowed)
+	assert.Equal(s.T(), float64(0), tokens)
+
+	allowed, tokens, _ = store.Take("key", s.Policy, now.Add(20*time.Second))
+	assert.True(s.T(), allowed)
+	assert.InDelta(s.T(), 0, tokens, 0.0001)
+
+	allowed, tokens, _ = store.Take("key", s.Policy, now.Add(time.Hour))
+	assert.True(s.T(), allowed)
+	assert.InDelta(s.T(), 2, tokens, 0.0001)
+}
@@ -0,0 +1,76 @@
+package request
+
+import (
+	"github.com/samithiwat/samithiwat-backend-gateway/src/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+type RequestIDTest struct {
+	suite.Suite
+}
+
+func TestRequestID(t *testing.T) {
+	suite.Run(t, new(RequestIDTest))
+}
+
+func (u *RequestIDTest) TestKeepIncomingId() {
+	c := &ContextMock{Header: map[string]string{"X-Request-ID": "edge-7f3a:42"}}
+
+	c.On("Next")
+
+	middleware.RequestID(c)
+
+	assert.Equal(u.T(), "edge-7f3a:42", c.ID)
+	assert.Equal(u.T(), "edge-7f3a:42", c.ResponseHeader["X-Request-ID"])
+	c.AssertNumberOfCalls(u.T(), "Next", 1)
+}
+
+func (u *RequestIDTest) TestGenerateId() {
+	c := &ContextMock{}
+
+	c.On("Next")
+
+	middleware.RequestID(c)
+
+	assert.Regexp(u.T(), uuidPattern, c.ID)
+	assert.Equal(u.T(), c.ID, c.ResponseHeader["X-Request-ID"])
+	c.AssertNumberOfCalls(u.T(), "Next", 1)
+}
+
+func (u *RequestIDTest) TestReplaceInvalidId() {
+	invalid := []string{
+		"spoofed\nline",
+		"has space",
+		strings.Repeat("a", 129),
+	}
+
+	for _, id := range invalid {
+		c := &ContextMock{Header: map[string]string{"X-Request-ID": id}}
+
+		c.On("Next")
+
+		middleware.RequestID(c)
+
+		assert.Regexp(u.T(), uuidPattern, c.ID, id)
+		assert.Equal(u.T(), c.ID, c.ResponseHeader["X-Request-ID"], id)
+	}
+}
+
+func (u *RequestIDTest) TestGeneratedIdsAreUnique() {
+	a := &ContextMock{}
+	b := &ContextMock{}
+
+	a.On("Next")
+	b.On("Next")
+
+	middleware.RequestID(a)
+	middleware.RequestID(b)
+
+	assert.NotEqual(u.T(), a.ID, b.ID)
+}
@@ -0,0 +1,32 @@
+package request
+
+import (
+	"github.com/stretchr/testify/mock"
+)
+
+type ContextMock struct {
+	mock.Mock
+	Header         map[string]string
+	ResponseHeader map[string]string
+	ID             string
+}
+
+func (c *ContextMock) RequestHeader(key string) string {
+	return c.Header[key]
+}
+
+func (c *ContextMock) SetResponseHeader(key string, val string) {
+	if c.ResponseHeader == nil {
+		c.ResponseHeader = map[string]string{}
+	}
+
+	c.ResponseHeader[key] = val
+}
+
+func (c *ContextMock) SetRequestID(id string) {
+	c.ID = id
+}
+
+func (c *ContextMock) Next() {
+	_ = c.Called()
+}
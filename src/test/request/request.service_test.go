@@ -0,0 +1,59 @@
+package request
+
+import (
+	"context"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/common"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"testing"
+)
+
+type RequestIDInterceptorTest struct {
+	suite.Suite
+}
+
+func TestRequestIDInterceptor(t *testing.T) {
+	suite.Run(t, new(RequestIDInterceptorTest))
+}
+
+// invoke runs the interceptor and returns the outgoing metadata that reached the invoker
+func invoke(ctx context.Context) (metadata.MD, error) {
+	var md metadata.MD
+
+	err := service.RequestIDInterceptor(ctx, "/user.UserService/FindOne", nil, nil, nil,
+		func(ctx context.Context, _ string, _, _ interface{}, _ *grpc.ClientConn, _ ...grpc.CallOption) error {
+			md, _ = metadata.FromOutgoingContext(ctx)
+			return nil
+		})
+
+	return md, err
+}
+
+func (u *RequestIDInterceptorTest) TestAttachRequestId() {
+	ctx := common.WithRequestID(context.Background(), "edge-7f3a")
+
+	md, err := invoke(ctx)
+
+	assert.Nil(u.T(), err)
+	assert.Equal(u.T(), []string{"edge-7f3a"}, md.Get("x-request-id"))
+}
+
+func (u *RequestIDInterceptorTest) TestKeepExistingMetadata() {
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer token")
+	ctx = common.WithRequestID(ctx, "edge-7f3a")
+
+	md, _ := invoke(ctx)
+
+	assert.Equal(u.T(), []string{"Bearer token"}, md.Get("authorization"))
+	assert.Equal(u.T(), []string{"edge-7f3a"}, md.Get("x-request-id"))
+}
+
+func (u *RequestIDInterceptorTest) TestNoRequestId() {
+	md, err := invoke(context.Background())
+
+	assert.Nil(u.T(), err)
+	assert.Empty(u.T(), md.Get("x-request-id"))
+}
@@ -0,0 +1,716 @@
+package role
+
+import (
+	"github.com/bxcodec/faker/v3"
+	"github.com/pkg/errors"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/dto"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/handler"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/proto"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/test/permission"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/validator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"testing"
+)
+
+type RoleHandlerTest struct {
+	suite.Suite
+	Role           *proto.Role
+	Roles          []*proto.Role
+	RoleDto        *dto.RoleDto
+	Query          *dto.PaginationQueryParams
+	NotFoundErr    *dto.ResponseErr
+	ServiceDownErr *dto.ResponseErr
+	InvalidIDErr   *dto.ResponseErr
+	Permission     *proto.Permission
+}
+
+func TestRoleHandler(t *testing.T) {
+	suite.Run(t, new(RoleHandlerTest))
+}
+
+func (u *RoleHandlerTest) SetupTest() {
+	u.Role = &proto.Role{
+		Id:          1,
+		Name:        faker.Word(),
+		Description: faker.Sentence(),
+	}
+
+	Role2 := &proto.Role{
+		Id:          2,
+		Name:        faker.Word(),
+		Description: faker.Sentence(),
+	}
+
+	Role3 := &proto.Role{
+		Id:          3,
+		Name:        faker.Word(),
+		Description: faker.Sentence(),
+	}
+
+	Role4 := &proto.Role{
+		Id:          4,
+		Name:        faker.Word(),
+		Description: faker.Sentence(),
+	}
+
+	_ = faker.FakeData(&u.RoleDto)
+	_ = faker.FakeData(&u.Query)
+
+	u.Roles = append(u.Roles, u.Role, Role2, Role3, Role4)
+
+	u.ServiceDownErr = &dto.ResponseErr{
+		StatusCode: http.StatusServiceUnavailable,
+		Message:    "Service is down",
+		Data:       nil,
+	}
+
+	u.NotFoundErr = &dto.ResponseErr{
+		StatusCode: http.StatusNotFound,
+		Message:    "Not found role",
+		Data:       nil,
+	}
+
+	u.InvalidIDErr = &dto.ResponseErr{
+		StatusCode: http.StatusBadRequest,
+		Message:    "Invalid ID",
+	}
+
+	u.Permission = &proto.Permission{
+		Id:   1,
+		Name: faker.Word(),
+		Code: "role:update",
+	}
+}
+
+func (u *RoleHandlerTest) TestFindAllRole() {
+	want := &proto.RolePagination{
+		Items: u.Roles,
+		Meta: &proto.PaginationMetadata{
+			TotalItem:    4,
+			ItemCount:    4,
+			ItemsPerPage: 10,
+			TotalPage:    1,
+			CurrentPage:  1,
+		},
+	}
+
+	srv := new(ServiceMock)
+	permSrv := new(permission.ServiceMock)
+	c := &ContextMock{
+		Role:    u.Role,
+		Roles:   u.Roles,
+		RoleDto: u.RoleDto,
+		Query:   u.Query,
+	}
+
+	srv.On("FindAll", u.Query).Return(want, nil)
+	c.On("PaginationQueryParam", &dto.PaginationQueryParams{}).Return(nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewRoleHandler(srv, permSrv, v)
+	h.FindAll(c)
+
+	assert.Equal(u.T(), want, c.V)
+}
+
+func (u *RoleHandlerTest) TestFindAllInvalidQueryParamRole() {
+	want := &dto.ResponseErr{
+		StatusCode: http.StatusInternalServerError,
+		Message:    "Cannot parse query param",
+	}
+
+	srv := new(ServiceMock)
+	permSrv := new(permission.ServiceMock)
+	c := &ContextMock{
+		Role:    u.Role,
+		Roles:   u.Roles,
+		RoleDto: u.RoleDto,
+		Query:   u.Query,
+	}
+
+	srv.On("FindAll", u.Query).Return(nil, nil)
+	c.On("PaginationQueryParam", &dto.PaginationQueryParams{}).Return(errors.New("Cannot parse query param"))
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewRoleHandler(srv, permSrv, v)
+
+	h.FindAll(c)
+
+	assert.Equal(u.T(), want, c.V)
+}
+
+func (u *RoleHandlerTest) TestFindAllGrpcErrRole() {
+	want := u.ServiceDownErr
+
+	srv := new(ServiceMock)
+	permSrv := new(permission.ServiceMock)
+	c := &ContextMock{
+		Role:    u.Role,
+		Roles:   u.Roles,
+		RoleDto: u.RoleDto,
+		Query:   u.Query,
+	}
+
+	srv.On("FindAll", u.Query).Return(nil, u.ServiceDownErr)
+	c.On("PaginationQueryParam", &dto.PaginationQueryParams{}).Return(nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewRoleHandler(srv, permSrv, v)
+
+	h.FindAll(c)
+
+	assert.Equal(u.T(), want, c.V)
+}
+
+func (u *RoleHandlerTest) TestFindOneRole() {
+	want := u.Role
+
+	srv := new(ServiceMock)
+	permSrv := new(permission.ServiceMock)
+	c := &ContextMock{
+		Role:    u.Role,
+		Roles:   u.Roles,
+		RoleDto: u.RoleDto,
+		Query:   u.Query,
+	}
+
+	srv.On("FindOne", int32(1)).Return(u.Role, nil)
+	c.On("ID").Return(1, nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewRoleHandler(srv, permSrv, v)
+
+	h.FindOne(c)
+
+	assert.Equal(u.T(), want, c.V)
+}
+
+func (u *RoleHandlerTest) TestFindOneInvalidRequestParamIDRole() {
+	want := u.InvalidIDErr
+
+	srv := new(ServiceMock)
+	permSrv := new(permission.ServiceMock)
+	c := &ContextMock{
+		Role:    u.Role,
+		Roles:   u.Roles,
+		RoleDto: u.RoleDto,
+		Query:   u.Query,
+	}
+
+	srv.On("FindOne", int32(1)).Return(nil, nil)
+	c.On("ID").Return(-1, errors.New("Invalid ID"))
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewRoleHandler(srv, permSrv, v)
+	h.FindOne(c)
+
+	assert.Equal(u.T(), want, c.V)
+}
+
+func (u *RoleHandlerTest) TestFindOneErrorNotFoundRole() {
+	want := u.NotFoundErr
+
+	srv := new(ServiceMock)
+	permSrv := new(permission.ServiceMock)
+	c := &ContextMock{
+		Role:    u.Role,
+		Roles:   u.Roles,
+		RoleDto: u.RoleDto,
+		Query:   u.Query,
+	}
+
+	srv.On("FindOne", int32(1)).Return(nil, u.NotFoundErr)
+	c.On("ID").Return(1, nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewRoleHandler(srv, permSrv, v)
+
+	h.FindOne(c)
+
+	assert.Equal(u.T(), want, c.V)
+}
+
+func (u *RoleHandlerTest) TestFindOneGrpcErrRole() {
+	want := u.ServiceDownErr
+
+	srv := new(ServiceMock)
+	permSrv := new(permission.ServiceMock)
+	c := &ContextMock{
+		Role:    u.Role,
+		Roles:   u.Roles,
+		RoleDto: u.RoleDto,
+		Query:   u.Query,
+	}
+
+	srv.On("FindOne", int32(1)).Return(nil, u.ServiceDownErr)
+	c.On("ID").Return(1, nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewRoleHandler(srv, permSrv, v)
+
+	h.FindOne(c)
+
+	assert.Equal(u.T(), want, c.V)
+}
+
+func (u *RoleHandlerTest) TestCreateRole() {
+	want := u.Role
+
+	srv := new(ServiceMock)
+	permSrv := new(permission.ServiceMock)
+	c := &ContextMock{
+		Role:    u.Role,
+		Roles:   u.Roles,
+		RoleDto: u.RoleDto,
+		Query:   u.Query,
+	}
+
+	srv.On("Create", u.RoleDto).Return(u.Role, nil)
+	c.On("Bind", &dto.RoleDto{}).Return(nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewRoleHandler(srv, permSrv, v)
+	h.Create(c)
+
+	assert.Equal(u.T(), want, c.V)
+}
+
+func (u *RoleHandlerTest) TestCreateErrorDuplicatedRole() {
+	want := &dto.ResponseErr{
+		StatusCode: http.StatusUnprocessableEntity,
+		Message:    "Duplicated role name",
+	}
+
+	srv := new(ServiceMock)
+	permSrv := new(permission.ServiceMock)
+	c := &ContextMock{
+		Role:    u.Role,
+		Roles:   u.Roles,
+		RoleDto: u.RoleDto,
+		Query:   u.Query,
+	}
+
+	srv.On("Create", u.RoleDto).Return(nil, want)
+	c.On("Bind", &dto.RoleDto{}).Return(nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewRoleHandler(srv, permSrv, v)
+	h.Create(c)
+
+	assert.Equal(u.T(), want, c.V)
+}
+
+func (u *RoleHandlerTest) TestCreateInvalidBodyRequest() {
+	want := &dto.ResponseErr{
+		StatusCode: http.StatusBadRequest,
+		Message:    "Cannot parse role dto",
+	}
+
+	srv := new(ServiceMock)
+	permSrv := new(permission.ServiceMock)
+	c := &ContextMock{
+		Role:    u.Role,
+		Roles:   u.Roles,
+		RoleDto: u.RoleDto,
+		Query:   u.Query,
+	}
+
+	srv.On("Create", u.RoleDto).Return(nil, nil)
+	c.On("Bind", &dto.RoleDto{}).Return(errors.New("Cannot parse body request"))
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewRoleHandler(srv, permSrv, v)
+	h.Create(c)
+
+	assert.Equal(u.T(), want, c.V)
+}
+
+func (u *RoleHandlerTest) TestCreateGrpcErrRole() {
+	want := u.ServiceDownErr
+
+	srv := new(ServiceMock)
+	permSrv := new(permission.ServiceMock)
+	c := &ContextMock{
+		Role:    u.Role,
+		Roles:   u.Roles,
+		RoleDto: u.RoleDto,
+		Query:   u.Query,
+	}
+
+	srv.On("Create", u.RoleDto).Return(nil, u.ServiceDownErr)
+	c.On("Bind", &dto.RoleDto{}).Return(nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewRoleHandler(srv, permSrv, v)
+
+	h.Create(c)
+
+	assert.Equal(u.T(), want, c.V)
+}
+
+func (u *RoleHandlerTest) TestUpdateRole() {
+	want := u.Role
+
+	srv := new(ServiceMock)
+	permSrv := new(permission.ServiceMock)
+	c := &ContextMock{
+		Role:    u.Role,
+		Roles:   u.Roles,
+		RoleDto: u.RoleDto,
+		Query:   u.Query,
+	}
+
+	srv.On("Update", int32(1), u.RoleDto).Return(u.Role, nil)
+	c.On("Bind", &dto.RoleDto{}).Return(nil)
+	c.On("ID").Return(1, nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewRoleHandler(srv, permSrv, v)
+
+	h.Update(c)
+
+	assert.Equal(u.T(), want, c.V)
+}
+
+func (u *RoleHandlerTest) TestUpdateInvalidRequestParamIDRole() {
+	want := u.InvalidIDErr
+
+	srv := new(ServiceMock)
+	permSrv := new(permission.ServiceMock)
+	c := &ContextMock{
+		Role:    u.Role,
+		Roles:   u.Roles,
+		RoleDto: u.RoleDto,
+		Query:   u.Query,
+	}
+
+	srv.On("Update", int32(1), u.RoleDto).Return(nil, nil)
+	c.On("ID").Return(-1, errors.New("Invalid ID"))
+	c.On("Bind", &dto.RoleDto{}).Return(nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewRoleHandler(srv, permSrv, v)
+
+	h.Update(c)
+
+	assert.Equal(u.T(), want, c.V)
+}
+
+func (u *RoleHandlerTest) TestUpdateInvalidBodyRequest() {
+	want := &dto.ResponseErr{
+		StatusCode: http.StatusBadRequest,
+		Message:    "Cannot parse role dto",
+	}
+
+	srv := new(ServiceMock)
+	permSrv := new(permission.ServiceMock)
+	c := &ContextMock{
+		Role:    u.Role,
+		Roles:   u.Roles,
+		RoleDto: u.RoleDto,
+		Query:   u.Query,
+	}
+
+	srv.On("Update", int32(1), u.RoleDto).Return(nil, nil)
+	c.On("ID").Return(1, nil)
+	c.On("Bind", &dto.RoleDto{}).Return(errors.New("Cannot parse role dto"))
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewRoleHandler(srv, permSrv, v)
+	h.Create(c)
+
+	assert.Equal(u.T(), want, c.V)
+}
+
+func (u *RoleHandlerTest) TestUpdateErrorNotFoundRole() {
+	want := u.NotFoundErr
+
+	srv := new(ServiceMock)
+	permSrv := new(permission.ServiceMock)
+	c := &ContextMock{
+		Role:    u.Role,
+		Roles:   u.Roles,
+		RoleDto: u.RoleDto,
+		Query:   u.Query,
+	}
+
+	srv.On("Update", int32(1), u.RoleDto).Return(nil, u.NotFoundErr)
+	c.On("ID").Return(1, nil)
+	c.On("Bind", &dto.RoleDto{}).Return(nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewRoleHandler(srv, permSrv, v)
+	h.Update(c)
+
+	assert.Equal(u.T(), want, c.V)
+}
+
+func (u *RoleHandlerTest) TestUpdateGrpcErrRole() {
+	want := u.ServiceDownErr
+
+	srv := new(ServiceMock)
+	permSrv := new(permission.ServiceMock)
+	c := &ContextMock{
+		Role:    u.Role,
+		Roles:   u.Roles,
+		RoleDto: u.RoleDto,
+		Query:   u.Query,
+	}
+
+	srv.On("Update", int32(1), u.RoleDto).Return(nil, u.ServiceDownErr)
+	c.On("Bind", &dto.RoleDto{}).Return(nil)
+	c.On("ID").Return(1, nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewRoleHandler(srv, permSrv, v)
+
+	h.Update(c)
+
+	assert.Equal(u.T(), want, c.V)
+}
+
+func (u *RoleHandlerTest) TestDeleteRole() {
+	want := u.Role
+
+	srv := new(ServiceMock)
+	permSrv := new(permission.ServiceMock)
+	c := &ContextMock{
+		Role:    u.Role,
+		Roles:   u.Roles,
+		RoleDto: u.RoleDto,
+		Query:   u.Query,
+	}
+
+	srv.On("Delete", int32(1)).Return(u.Role, nil)
+	c.On("ID").Return(1, nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewRoleHandler(srv, permSrv, v)
+
+	h.Delete(c)
+
+	assert.Equal(u.T(), want, c.V)
+}
+
+func (u *RoleHandlerTest) TestDeleteInvalidRequestParamIDRole() {
+	want := u.InvalidIDErr
+
+	srv := new(ServiceMock)
+	permSrv := new(permission.ServiceMock)
+	c := &ContextMock{
+		Role:    u.Role,
+		Roles:   u.Roles,
+		RoleDto: u.RoleDto,
+		Query:   u.Query,
+	}
+
+	srv.On("Delete", int32(1)).Return(nil, nil)
+	c.On("ID").Return(-1, errors.New("Invalid ID"))
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewRoleHandler(srv, permSrv, v)
+
+	h.Delete(c)
+
+	assert.Equal(u.T(), want, c.V)
+}
+
+func (u *RoleHandlerTest) TestDeleteErrorNotFoundRole() {
+	want := u.NotFoundErr
+
+	srv := new(ServiceMock)
+	permSrv := new(permission.ServiceMock)
+	c := &ContextMock{
+		Role:    u.Role,
+		Roles:   u.Roles,
+		RoleDto: u.RoleDto,
+		Query:   u.Query,
+	}
+
+	srv.On("Delete", int32(1)).Return(nil, u.NotFoundErr)
+	c.On("ID").Return(1, nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewRoleHandler(srv, permSrv, v)
+
+	h.Delete(c)
+
+	assert.Equal(u.T(), want, c.V)
+}
+
+func (u *RoleHandlerTest) TestDeleteGrpcErrRole() {
+	want := u.ServiceDownErr
+
+	srv := new(ServiceMock)
+	permSrv := new(permission.ServiceMock)
+	c := &ContextMock{
+		Role:    u.Role,
+		Roles:   u.Roles,
+		RoleDto: u.RoleDto,
+		Query:   u.Query,
+	}
+
+	srv.On("Delete", int32(1)).Return(nil, u.ServiceDownErr)
+	c.On("ID").Return(1, nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewRoleHandler(srv, permSrv, v)
+
+	h.Delete(c)
+
+	assert.Equal(u.T(), want, c.V)
+}
+
+func (u *RoleHandlerTest) TestAddPermissionRole() {
+	want := &proto.Role{
+		Id:          u.Role.Id,
+		Name:        u.Role.Name,
+		Description: u.Role.Description,
+		Permissions: []*proto.Permission{u.Permission},
+	}
+
+	srv := new(ServiceMock)
+	permSrv := new(permission.ServiceMock)
+	c := &ContextMock{
+		Role:    u.Role,
+		Roles:   u.Roles,
+		RoleDto: u.RoleDto,
+		Query:   u.Query,
+	}
+
+	permSrv.On("FindOne", int32(1)).Return(u.Permission, nil)
+	srv.On("AddPermission", int32(1), u.Permission).Return(want, nil)
+	c.On("ID").Return(1, nil)
+	c.On("PermissionID").Return(1, nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewRoleHandler(srv, permSrv, v)
+	h.AddPermission(c)
+
+	assert.Equal(u.T(), want, c.V)
+}
+
+func (u *RoleHandlerTest) TestAddPermissionInvalidPermissionIDRole() {
+	want := &dto.ResponseErr{
+		StatusCode: http.StatusBadRequest,
+		Message:    "Invalid permission ID",
+	}
+
+	srv := new(ServiceMock)
+	permSrv := new(permission.ServiceMock)
+	c := &ContextMock{
+		Role:    u.Role,
+		Roles:   u.Roles,
+		RoleDto: u.RoleDto,
+		Query:   u.Query,
+	}
+
+	c.On("ID").Return(1, nil)
+	c.On("PermissionID").Return(-1, errors.New("Invalid ID"))
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewRoleHandler(srv, permSrv, v)
+	h.AddPermission(c)
+
+	assert.Equal(u.T(), want, c.V)
+}
+
+func (u *RoleHandlerTest) TestAddPermissionNotFoundPermissionRole() {
+	want := &dto.ResponseErr{
+		StatusCode: http.StatusNotFound,
+		Message:    "Not found permission",
+	}
+
+	srv := new(ServiceMock)
+	permSrv := new(permission.ServiceMock)
+	c := &ContextMock{
+		Role:    u.Role,
+		Roles:   u.Roles,
+		RoleDto: u.RoleDto,
+		Query:   u.Query,
+	}
+
+	permSrv.On("FindOne", int32(1)).Return(nil, want)
+	c.On("ID").Return(1, nil)
+	c.On("PermissionID").Return(1, nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewRoleHandler(srv, permSrv, v)
+	h.AddPermission(c)
+
+	assert.Equal(u.T(), want, c.V)
+	srv.AssertNumberOfCalls(u.T(), "AddPermission", 0)
+}
+
+func (u *RoleHandlerTest) TestRemovePermissionRole() {
+	want := u.Role
+
+	srv := new(ServiceMock)
+	permSrv := new(permission.ServiceMock)
+	c := &ContextMock{
+		Role:    u.Role,
+		Roles:   u.Roles,
+		RoleDto: u.RoleDto,
+		Query:   u.Query,
+	}
+
+	srv.On("RemovePermission", int32(1), int32(1)).Return(u.Role, nil)
+	c.On("ID").Return(1, nil)
+	c.On("PermissionID").Return(1, nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewRoleHandler(srv, permSrv, v)
+	h.RemovePermission(c)
+
+	assert.Equal(u.T(), want, c.V)
+}
+
+func (u *RoleHandlerTest) TestRemovePermissionGrpcErrRole() {
+	want := u.ServiceDownErr
+
+	srv := new(ServiceMock)
+	permSrv := new(permission.ServiceMock)
+	c := &ContextMock{
+		Role:    u.Role,
+		Roles:   u.Roles,
+		RoleDto: u.RoleDto,
+		Query:   u.Query,
+	}
+
+	srv.On("RemovePermission", int32(1), int32(1)).Return(nil, u.ServiceDownErr)
+	c.On("ID").Return(1, nil)
+	c.On("PermissionID").Return(1, nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewRoleHandler(srv, permSrv, v)
+	h.RemovePermission(c)
+
+	assert.Equal(u.T(), want, c.V)
+}
@@ -0,0 +1,238 @@
+package role
+
+import (
+	"context"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/common"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/dto"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/proto"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc"
+)
+
+type ContextMock struct {
+	mock.Mock
+	V       interface{}
+	Role    *proto.Role
+	Roles   []*proto.Role
+	RoleDto *dto.RoleDto
+	Query   *dto.PaginationQueryParams
+}
+
+func (c *ContextMock) Bind(v interface{}) error {
+	args := c.Called(v)
+
+	*v.(*dto.RoleDto) = *c.RoleDto
+
+	return args.Error(0)
+}
+
+func (c *ContextMock) JSON(_ int, v interface{}) {
+	c.V = v
+}
+
+func (c *ContextMock) ID() (int32, error) {
+	args := c.Called()
+
+	return int32(args.Int(0)), args.Error(1)
+}
+
+func (c *ContextMock) PermissionID() (int32, error) {
+	args := c.Called()
+
+	return int32(args.Int(0)), args.Error(1)
+}
+
+func (c *ContextMock) PaginationQueryParam(query *dto.PaginationQueryParams) error {
+	args := c.Called(query)
+
+	*query = *c.Query
+
+	return args.Error(0)
+}
+
+type ServiceMock struct {
+	mock.Mock
+}
+
+func (s *ServiceMock) FindAll(_ context.Context, query *dto.PaginationQueryParams) (res *proto.RolePagination, err *dto.ResponseErr) {
+	args := s.Called(query)
+
+	if args.Get(0) != nil {
+		res = args.Get(0).(*proto.RolePagination)
+	}
+
+	if args.Get(1) != nil {
+		err = args.Get(1).(*dto.ResponseErr)
+	}
+
+	return
+}
+
+func (s *ServiceMock) FindOne(_ context.Context, id int32) (res *proto.Role, err *dto.ResponseErr) {
+	args := s.Called(id)
+
+	if args.Get(0) != nil {
+		res = args.Get(0).(*proto.Role)
+	}
+
+	if args.Get(1) != nil {
+		err = args.Get(1).(*dto.ResponseErr)
+	}
+
+	return
+}
+
+func (s *ServiceMock) Create(_ context.Context, role *dto.RoleDto) (res *proto.Role, err *dto.ResponseErr) {
+	args := s.Called(role)
+
+	if args.Get(0) != nil {
+		res = args.Get(0).(*proto.Role)
+	}
+
+	if args.Get(1) != nil {
+		err = args.Get(1).(*dto.ResponseErr)
+	}
+
+	return
+}
+
+func (s *ServiceMock) Update(_ context.Context, id int32, role *dto.RoleDto) (res *proto.Role, err *dto.ResponseErr) {
+	args := s.Called(id, role)
+
+	if args.Get(0) != nil {
+		res = args.Get(0).(*proto.Role)
+	}
+
+	if args.Get(1) != nil {
+		err = args.Get(1).(*dto.ResponseErr)
+	}
+
+	return
+}
+
+func (s *ServiceMock) Delete(_ context.Context, id int32) (res *proto.Role, err *dto.ResponseErr) {
+	args := s.Called(id)
+
+	if args.Get(0) != nil {
+		res = args.Get(0).(*proto.Role)
+	}
+
+	if args.Get(1) != nil {
+		err = args.Get(1).(*dto.ResponseErr)
+	}
+
+	return
+}
+
+func (s *ServiceMock) AddPermission(_ context.Context, id int32, permission *proto.Permission) (res *proto.Role, err *dto.ResponseErr) {
+	args := s.Called(id, permission)
+
+	if args.Get(0) != nil {
+		res = args.Get(0).(*proto.Role)
+	}
+
+	if args.Get(1) != nil {
+		err = args.Get(1).(*dto.ResponseErr)
+	}
+
+	return
+}
+
+func (s *ServiceMock) RemovePermission(_ context.Context, id int32, permissionId int32) (res *proto.Role, err *dto.ResponseErr) {
+	args := s.Called(id, permissionId)
+
+	if args.Get(0) != nil {
+		res = args.Get(0).(*proto.Role)
+	}
+
+	if args.Get(1) != nil {
+		err = args.Get(1).(*dto.ResponseErr)
+	}
+
+	return
+}
+
+func (s *ServiceMock) AssignUser(_ context.Context, scope []int32, id int32, user *proto.User) (res *proto.Role, err *dto.ResponseErr) {
+	args := s.Called(scope, id, user)
+
+	if args.Get(0) != nil {
+		res = args.Get(0).(*proto.Role)
+	}
+
+	if args.Get(1) != nil {
+		err = args.Get(1).(*dto.ResponseErr)
+	}
+
+	return
+}
+
+type ClientMock struct {
+	mock.Mock
+	RequestIDs []string
+}
+
+func (c *ClientMock) FindAll(ctx context.Context, in *proto.FindAllRoleRequest, opts ...grpc.CallOption) (res *proto.RolePaginationResponse, err error) {
+	args := c.Called(in)
+
+	c.RequestIDs = append(c.RequestIDs, common.RequestID(ctx))
+
+	if args.Get(0) != nil {
+		res = args.Get(0).(*proto.RolePaginationResponse)
+	}
+
+	return res, args.Error(1)
+}
+
+func (c *ClientMock) FindOne(ctx context.Context, in *proto.FindOneRoleRequest, opts ...grpc.CallOption) (res *proto.RoleResponse, err error) {
+	args := c.Called(in)
+
+	if args.Get(0) != nil {
+		res = args.Get(0).(*proto.RoleResponse)
+	}
+
+	return res, args.Error(1)
+}
+
+func (c *ClientMock) FindMulti(ctx context.Context, in *proto.FindMultiRoleRequest, opts ...grpc.CallOption) (res *proto.RoleListResponse, err error) {
+	args := c.Called(in)
+
+	if args.Get(0) != nil {
+		res = args.Get(0).(*proto.RoleListResponse)
+	}
+
+	return res, args.Error(1)
+}
+
+func (c *ClientMock) Create(ctx context.Context, in *proto.CreateRoleRequest, opts ...grpc.CallOption) (res *proto.RoleResponse, err error) {
+	args := c.Called(in.Role)
+
+	if args.Get(0) != nil {
+		res = args.Get(0).(*proto.RoleResponse)
+	}
+
+	return res, args.Error(1)
+}
+
+func (c *ClientMock) Update(ctx context.Context, in *proto.UpdateRoleRequest, opts ...grpc.CallOption) (res *proto.RoleResponse, err error) {
+	args := c.Called(in.Role)
+
+	if args.Get(0) != nil {
+		res = args.Get(0).(*proto.RoleResponse)
+	}
+
+	return res, args.Error(1)
+}
+
+func (c *ClientMock) Delete(ctx context.Context, in *proto.DeleteRoleRequest, opts ...grpc.CallOption) (res *proto.RoleResponse, err error) {
+	args := c.Called(in)
+
+	if args.Get(0) != nil {
+		res = args.Get(0).(*proto.RoleResponse)
+	}
+
+	return res, args.Error(1)
+}
+
+func (c *ContextMock) UserContext() context.Context {
+	return context.Background()
+}
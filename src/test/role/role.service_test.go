@@ -0,0 +1,732 @@
+package role
+
+import (
+	"context"
+	"github.com/bxcodec/faker/v3"
+	"github.com/pkg/errors"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/common"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/constant"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/dto"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/proto"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"testing"
+	"time"
+)
+
+type RoleServiceTest struct {
+	suite.Suite
+	Role           *proto.Role
+	RoleReq        *proto.Role
+	Roles          []*proto.Role
+	RoleDto        *dto.RoleDto
+	Query          *dto.PaginationQueryParams
+	NotFoundErr    *dto.ResponseErr
+	ServiceDownErr *dto.ResponseErr
+}
+
+func TestRoleService(t *testing.T) {
+	suite.Run(t, new(RoleServiceTest))
+}
+
+func (s *RoleServiceTest) SetupTest() {
+	s.Role = &proto.Role{
+		Id:          1,
+		Name:        faker.Word(),
+		Description: faker.Sentence(),
+	}
+
+	s.RoleReq = &proto.Role{
+		Name:        s.Role.Name,
+		Description: s.Role.Description,
+	}
+
+	s.RoleDto = &dto.RoleDto{
+		Name:        s.Role.Name,
+		Description: s.Role.Description,
+	}
+
+	Role2 := &proto.Role{
+		Id:          2,
+		Name:        faker.Word(),
+		Description: faker.Sentence(),
+	}
+
+	Role3 := &proto.Role{
+		Id:          3,
+		Name:        faker.Word(),
+		Description: faker.Sentence(),
+	}
+
+	Role4 := &proto.Role{
+		Id:          4,
+		Name:        faker.Word(),
+		Description: faker.Sentence(),
+	}
+
+	s.Roles = append(s.Roles, s.Role, Role2, Role3, Role4)
+
+	_ = faker.FakeData(&s.Query)
+
+	s.ServiceDownErr = &dto.ResponseErr{
+		StatusCode: http.StatusServiceUnavailable,
+		Message:    "Service is down",
+		Data:       nil,
+	}
+
+	s.NotFoundErr = &dto.ResponseErr{
+		StatusCode: http.StatusNotFound,
+		Message:    "Not found role",
+		Data:       nil,
+	}
+}
+
+func (s *RoleServiceTest) TestFindAllRoleService() {
+	want := &proto.RolePagination{
+		Items: s.Roles,
+		Meta: &proto.PaginationMetadata{
+			TotalItem:    4,
+			ItemCount:    4,
+			ItemsPerPage: 10,
+			TotalPage:    1,
+			CurrentPage:  1,
+		},
+	}
+
+	client := new(ClientMock)
+
+	client.On("FindAll", &proto.FindAllRoleRequest{
+		Limit: s.Query.Limit,
+		Page:  s.Query.Page,
+	}).Return(&proto.RolePaginationResponse{
+		StatusCode: http.StatusOK,
+		Errors:     nil,
+		Data:       want,
+	}, nil)
+
+	srv := service.NewRoleService(client)
+
+	roles, err := srv.FindAll(context.Background(), s.Query)
+
+	assert.Nil(s.T(), err, "Must not got any error")
+	assert.Equal(s.T(), want, roles)
+}
+
+func (s *RoleServiceTest) TestFindAllGrpcErrRoleService() {
+	want := s.ServiceDownErr
+
+	client := new(ClientMock)
+
+	client.On("FindAll", &proto.FindAllRoleRequest{
+		Limit: s.Query.Limit,
+		Page:  s.Query.Page,
+	}).Return(nil, errors.New("Service is down"))
+
+	srv := service.NewRoleService(client)
+
+	_, err := srv.FindAll(context.Background(), s.Query)
+
+	assert.Equal(s.T(), want, err)
+}
+
+func (s *RoleServiceTest) TestFindOneRoleService() {
+	want := s.Role
+
+	var id int32
+	_ = faker.FakeData(&id)
+
+	client := new(ClientMock)
+
+	client.On("FindOne", &proto.FindOneRoleRequest{Id: id}).Return(&proto.RoleResponse{
+		StatusCode: http.StatusOK,
+		Errors:     nil,
+		Data:       s.Role,
+	}, nil)
+
+	srv := service.NewRoleService(client)
+
+	role, err := srv.FindOne(context.Background(), id)
+
+	assert.Nil(s.T(), err, "Must not got any error")
+	assert.Equal(s.T(), want, role)
+}
+
+func (s *RoleServiceTest) TestFindOneNotFoundRoleService() {
+	want := s.NotFoundErr
+
+	var id int32
+	_ = faker.FakeData(&id)
+
+	client := new(ClientMock)
+
+	client.On("FindOne", &proto.FindOneRoleRequest{Id: id}).Return(&proto.RoleResponse{
+		StatusCode: http.StatusNotFound,
+		Errors:     []string{"Not found role"},
+		Data:       nil,
+	}, nil)
+
+	srv := service.NewRoleService(client)
+
+	role, err := srv.FindOne(context.Background(), id)
+
+	assert.Nil(s.T(), role)
+	assert.Equal(s.T(), want, err)
+}
+
+func (s *RoleServiceTest) TestFindOneGrpcErrRoleService() {
+	want := s.ServiceDownErr
+
+	var id int32
+	_ = faker.FakeData(&id)
+
+	client := new(ClientMock)
+
+	client.On("FindOne", &proto.FindOneRoleRequest{Id: id}).Return(&proto.RoleResponse{}, errors.New("Service is down"))
+
+	srv := service.NewRoleService(client)
+
+	_, err := srv.FindOne(context.Background(), id)
+
+	assert.Equal(s.T(), want, err)
+}
+
+func (s *RoleServiceTest) TestCreateRoleService() {
+	want := s.Role
+
+	client := new(ClientMock)
+
+	client.On("Create", s.RoleReq).Return(&proto.RoleResponse{
+		StatusCode: http.StatusCreated,
+		Errors:     nil,
+		Data:       s.Role,
+	}, nil)
+
+	srv := service.NewRoleService(client)
+
+	role, err := srv.Create(context.Background(), s.RoleDto)
+
+	assert.Nil(s.T(), err, "Must not got any error")
+	assert.Equal(s.T(), want, role)
+}
+
+func (s *RoleServiceTest) TestCreateDuplicatedRoleService() {
+	want := &dto.ResponseErr{
+		StatusCode: http.StatusUnprocessableEntity,
+		Message:    "Duplicated email or rolename",
+		Data:       nil,
+	}
+
+	client := new(ClientMock)
+
+	client.On("Create", s.RoleReq).Return(&proto.RoleResponse{
+		StatusCode: http.StatusUnprocessableEntity,
+		Errors:     []string{"Duplicated email or rolename"},
+		Data:       nil,
+	}, nil)
+
+	srv := service.NewRoleService(client)
+
+	role, err := srv.Create(context.Background(), s.RoleDto)
+
+	assert.Nil(s.T(), role)
+	assert.Equal(s.T(), want, err)
+}
+
+func (s *RoleServiceTest) TestCreateGrpcErrRoleService() {
+	want := s.ServiceDownErr
+
+	client := new(ClientMock)
+
+	client.On("Create", s.RoleReq).Return(&proto.RoleResponse{}, errors.New("Service is down"))
+
+	srv := service.NewRoleService(client)
+
+	_, err := srv.Create(context.Background(), s.RoleDto)
+
+	assert.Equal(s.T(), want, err)
+}
+
+func (s *RoleServiceTest) TestUpdateRoleService() {
+	want := s.Role
+
+	client := new(ClientMock)
+
+	client.On("Update", s.Role).Return(&proto.RoleResponse{
+		StatusCode: http.StatusOK,
+		Errors:     nil,
+		Data:       s.Role,
+	}, nil)
+
+	srv := service.NewRoleService(client)
+
+	role, err := srv.Update(context.Background(), 1, s.RoleDto)
+
+	assert.Nil(s.T(), err, "Must not got any error")
+	assert.Equal(s.T(), want, role)
+}
+
+func (s *RoleServiceTest) TestUpdateNotFoundRoleService() {
+	want := s.NotFoundErr
+
+	client := new(ClientMock)
+
+	client.On("Update", s.Role).Return(&proto.RoleResponse{
+		StatusCode: http.StatusNotFound,
+		Errors:     []string{"Not found role"},
+		Data:       nil,
+	}, nil)
+
+	srv := service.NewRoleService(client)
+
+	role, err := srv.Update(context.Background(), 1, s.RoleDto)
+
+	assert.Nil(s.T(), role)
+	assert.Equal(s.T(), want, err)
+}
+
+func (s *RoleServiceTest) TestUpdateGrpcErrRoleService() {
+	want := s.ServiceDownErr
+
+	client := new(ClientMock)
+
+	client.On("Update", s.Role).Return(&proto.RoleResponse{}, errors.New("Service is down"))
+
+	srv := service.NewRoleService(client)
+
+	_, err := srv.Update(context.Background(), 1, s.RoleDto)
+
+	assert.Equal(s.T(), want, err)
+}
+
+func (s *RoleServiceTest) TestDeleteRoleService() {
+	want := s.Role
+
+	var id int32
+	_ = faker.FakeData(&id)
+
+	client := new(ClientMock)
+
+	client.On("Delete", &proto.DeleteRoleRequest{Id: id}).Return(&proto.RoleResponse{
+		StatusCode: http.StatusOK,
+		Errors:     nil,
+		Data:       s.Role,
+	}, nil)
+
+	srv := service.NewRoleService(client)
+
+	role, err := srv.Delete(context.Background(), id)
+
+	assert.Nil(s.T(), err, "Must not got any error")
+	assert.Equal(s.T(), want, role)
+}
+
+func (s *RoleServiceTest) TestDeleteNotFoundRoleService() {
+	want := s.NotFoundErr
+
+	var id int32
+	_ = faker.FakeData(&id)
+
+	client := new(ClientMock)
+
+	client.On("Delete", &proto.DeleteRoleRequest{Id: id}).Return(&proto.RoleResponse{
+		StatusCode: http.StatusNotFound,
+		Errors:     []string{"Not found role"},
+		Data:       nil,
+	}, nil)
+
+	srv := service.NewRoleService(client)
+
+	role, err := srv.Delete(context.Background(), id)
+
+	assert.Nil(s.T(), role)
+	assert.Equal(s.T(), want, err)
+}
+
+func (s *RoleServiceTest) TestDeleteGrpcErrRoleService() {
+	want := s.ServiceDownErr
+
+	var id int32
+	_ = faker.FakeData(&id)
+
+	client := new(ClientMock)
+
+	client.On("Delete", &proto.DeleteRoleRequest{Id: id}).Return(&proto.RoleResponse{}, errors.New("Service is down"))
+
+	srv := service.NewRoleService(client)
+
+	_, err := srv.Delete(context.Background(), id)
+
+	assert.Equal(s.T(), want, err)
+}
+
+func (s *RoleServiceTest) TestAddPermissionRoleService() {
+	permission := &proto.Permission{
+		Id:   1,
+		Name: faker.Word(),
+		Code: "role:update",
+	}
+
+	want := &proto.Role{
+		Id:          s.Role.Id,
+		Name:        s.Role.Name,
+		Description: s.Role.Description,
+		Permissions: []*proto.Permission{permission},
+	}
+
+	client := new(ClientMock)
+
+	client.On("FindOne", &proto.FindOneRoleRequest{Id: 1}).Return(&proto.RoleResponse{
+		StatusCode: http.StatusOK,
+		Errors:     nil,
+		Data: &proto.Role{
+			Id:          s.Role.Id,
+			Name:        s.Role.Name,
+			Description: s.Role.Description,
+		},
+	}, nil)
+	client.On("Update", want).Return(&proto.RoleResponse{
+		StatusCode: http.StatusOK,
+		Errors:     nil,
+		Data:       want,
+	}, nil)
+
+	srv := service.NewRoleService(client)
+
+	role, err := srv.AddPermission(context.Background(), 1, permission)
+
+	assert.Nil(s.T(), err, "Must not got any error")
+	assert.Equal(s.T(), want, role)
+}
+
+func (s *RoleServiceTest) TestAddPermissionNotFoundRoleService() {
+	want := s.NotFoundErr
+
+	client := new(ClientMock)
+
+	client.On("FindOne", &proto.FindOneRoleRequest{Id: 1}).Return(&proto.RoleResponse{
+		StatusCode: http.StatusNotFound,
+		Errors:     []string{"Not found role"},
+		Data:       nil,
+	}, nil)
+
+	srv := service.NewRoleService(client)
+
+	role, err := srv.AddPermission(context.Background(), 1, &proto.Permission{Id: 1})
+
+	assert.Nil(s.T(), role)
+	assert.Equal(s.T(), want, err)
+	client.AssertNumberOfCalls(s.T(), "Update", 0)
+}
+
+func (s *RoleServiceTest) TestRemovePermissionRoleService() {
+	permission := &proto.Permission{
+		Id:   1,
+		Name: faker.Word(),
+		Code: "role:update",
+	}
+
+	want := &proto.Role{
+		Id:          s.Role.Id,
+		Name:        s.Role.Name,
+		Description: s.Role.Description,
+	}
+
+	client := new(ClientMock)
+
+	client.On("FindOne", &proto.FindOneRoleRequest{Id: 1}).Return(&proto.RoleResponse{
+		StatusCode: http.StatusOK,
+		Errors:     nil,
+		Data: &proto.Role{
+			Id:          s.Role.Id,
+			Name:        s.Role.Name,
+			Description: s.Role.Description,
+			Permissions: []*proto.Permission{permission},
+		},
+	}, nil)
+	client.On("Update", want).Return(&proto.RoleResponse{
+		StatusCode: http.StatusOK,
+		Errors:     nil,
+		Data:       want,
+	}, nil)
+
+	srv := service.NewRoleService(client)
+
+	role, err := srv.RemovePermission(context.Background(), 1, 1)
+
+	assert.Nil(s.T(), err, "Must not got any error")
+	assert.Equal(s.T(), want, role)
+}
+
+func (s *RoleServiceTest) TestRemovePermissionNotAssignedRoleService() {
+	want := &dto.ResponseErr{
+		StatusCode: http.StatusNotFound,
+		Message:    "The role does not have this permission",
+		Data:       nil,
+	}
+
+	client := new(ClientMock)
+
+	client.On("FindOne", &proto.FindOneRoleRequest{Id: 1}).Return(&proto.RoleResponse{
+		StatusCode: http.StatusOK,
+		Errors:     nil,
+		Data:       s.Role,
+	}, nil)
+
+	srv := service.NewRoleService(client)
+
+	role, err := srv.RemovePermission(context.Background(), 1, 1)
+
+	assert.Nil(s.T(), role)
+	assert.Equal(s.T(), want, err)
+	client.AssertNumberOfCalls(s.T(), "Update", 0)
+}
+
+func (s *RoleServiceTest) TestFindByUserRoleService() {
+	user := &proto.User{Id: 5}
+	s.Roles[1].Users = []*proto.User{{Id: 2}, user}
+	s.Roles[3].Users = []*proto.User{user}
+
+	want := []*proto.Role{s.Roles[1], s.Roles[3]}
+
+	client := new(ClientMock)
+
+	client.On("FindAll", &proto.FindAllRoleRequest{
+		Limit: constant.FetchAllLimit,
+		Page:  1,
+	}).Return(&proto.RolePaginationResponse{
+		StatusCode: http.StatusOK,
+		Errors:     nil,
+		Data: &proto.RolePagination{
+			Items: s.Roles[:2],
+			Meta:  &proto.PaginationMetadata{TotalPage: 2, CurrentPage: 1},
+		},
+	}, nil)
+
+	client.On("FindAll", &proto.FindAllRoleRequest{
+		Limit: constant.FetchAllLimit,
+		Page:  2,
+	}).Return(&proto.RolePaginationResponse{
+		StatusCode: http.StatusOK,
+		Errors:     nil,
+		Data: &proto.RolePagination{
+			Items: s.Roles[2:],
+			Meta:  &proto.PaginationMetadata{TotalPage: 2, CurrentPage: 2},
+		},
+	}, nil)
+
+	srv := service.NewRoleService(client)
+
+	roles, err := srv.FindByUser(context.Background(), 5)
+
+	assert.Nil(s.T(), err, "Must not got any error")
+	assert.Equal(s.T(), want, roles)
+}
+
+func (s *RoleServiceTest) TestFindByUserCarriesRequestIdRoleService() {
+	client := new(ClientMock)
+
+	client.On("FindAll", &proto.FindAllRoleRequest{
+		Limit: constant.FetchAllLimit,
+		Page:  1,
+	}).Return(&proto.RolePaginationResponse{
+		StatusCode: http.StatusOK,
+		Errors:     nil,
+		Data:       &proto.RolePagination{Items: s.Roles},
+	}, nil)
+
+	srv := service.NewRoleService(client)
+
+	_, err := srv.FindByUser(common.WithRequestID(context.Background(), "edge-7f3a"), 5)
+
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), []string{"edge-7f3a"}, client.RequestIDs)
+}
+
+func (s *RoleServiceTest) TestFindByUserCachedRoleService() {
+	user := &proto.User{Id: 5}
+	s.Roles[1].Users = []*proto.User{user}
+
+	client := new(ClientMock)
+
+	client.On("FindAll", &proto.FindAllRoleRequest{
+		Limit: constant.FetchAllLimit,
+		Page:  1,
+	}).Return(&proto.RolePaginationResponse{
+		StatusCode: http.StatusOK,
+		Errors:     nil,
+		Data:       &proto.RolePagination{Items: s.Roles},
+	}, nil)
+
+	srv := service.NewCachedRoleService(client, time.Minute)
+
+	first, err := srv.FindByUser(context.Background(), 5)
+	assert.Nil(s.T(), err)
+
+	second, err := srv.FindByUser(context.Background(), 5)
+	assert.Nil(s.T(), err)
+
+	assert.Equal(s.T(), []*proto.Role{s.Roles[1]}, first)
+	assert.Equal(s.T(), first, second)
+	client.AssertNumberOfCalls(s.T(), "FindAll", 1)
+}
+
+func (s *RoleServiceTest) TestFindByUserEvictedOnRoleChangeRoleService() {
+	role := &proto.Role{Id: s.Roles[0].Id, Name: s.Roles[0].Name}
+
+	client := new(ClientMock)
+
+	client.On("FindAll", &proto.FindAllRoleRequest{
+		Limit: constant.FetchAllLimit,
+		Page:  1,
+	}).Return(&proto.RolePaginationResponse{
+		StatusCode: http.StatusOK,
+		Errors:     nil,
+		Data:       &proto.RolePagination{Items: s.Roles},
+	}, nil)
+	client.On("Update", role).Return(&proto.RoleResponse{
+		StatusCode: http.StatusOK,
+		Errors:     nil,
+		Data:       role,
+	}, nil)
+
+	srv := service.NewCachedRoleService(client, time.Minute)
+
+	_, _ = srv.FindByUser(context.Background(), 5)
+	_, err := srv.Update(context.Background(), int32(role.Id), &dto.RoleDto{Name: role.Name})
+	assert.Nil(s.T(), err)
+	_, _ = srv.FindByUser(context.Background(), 5)
+
+	client.AssertNumberOfCalls(s.T(), "FindAll", 2)
+}
+
+func (s *RoleServiceTest) TestFindByUserNotCachedRoleService() {
+	client := new(ClientMock)
+
+	client.On("FindAll", &proto.FindAllRoleRequest{
+		Limit: constant.FetchAllLimit,
+		Page:  1,
+	}).Return(&proto.RolePaginationResponse{
+		StatusCode: http.StatusOK,
+		Errors:     nil,
+		Data:       &proto.RolePagination{Items: s.Roles},
+	}, nil)
+
+	srv := service.NewCachedRoleService(client, 0)
+
+	_, _ = srv.FindByUser(context.Background(), 5)
+	_, _ = srv.FindByUser(context.Background(), 5)
+
+	client.AssertNumberOfCalls(s.T(), "FindAll", 2)
+}
+
+func (s *RoleServiceTest) TestFindByUserGrpcErrRoleService() {
+	want := s.ServiceDownErr
+
+	client := new(ClientMock)
+
+	client.On("FindAll", &proto.FindAllRoleRequest{
+		Limit: constant.FetchAllLimit,
+		Page:  1,
+	}).Return(nil, errors.New("Service is down"))
+
+	srv := service.NewRoleService(client)
+
+	_, err := srv.FindByUser(context.Background(), 5)
+
+	assert.Equal(s.T(), want, err)
+}
+
+func (s *RoleServiceTest) TestAssignUserRoleService() {
+	user := &proto.User{Id: 5}
+
+	oldRole := &proto.Role{
+		Id:    s.Roles[0].Id,
+		Name:  s.Roles[0].Name,
+		Users: []*proto.User{{Id: 2}, user},
+	}
+
+	newRole := &proto.Role{
+		Id:   s.Roles[1].Id,
+		Name: s.Roles[1].Name,
+	}
+
+	removed := &proto.Role{
+		Id:    s.Roles[0].Id,
+		Name:  s.Roles[0].Name,
+		Users: []*proto.User{{Id: 2}},
+	}
+
+	want := &proto.Role{
+		Id:    s.Roles[1].Id,
+		Name:  s.Roles[1].Name,
+		Users: []*proto.User{user},
+	}
+
+	client := new(ClientMock)
+
+	client.On("FindMulti", &proto.FindMultiRoleRequest{Ids: []uint32{1, 2}}).Return(&proto.RoleListResponse{
+		StatusCode: http.StatusOK,
+		Errors:     nil,
+		Data:       []*proto.Role{newRole, oldRole},
+	}, nil)
+	client.On("Update", removed).Return(&proto.RoleResponse{
+		StatusCode: http.StatusOK,
+		Errors:     nil,
+		Data:       removed,
+	}, nil)
+	client.On("Update", want).Return(&proto.RoleResponse{
+		StatusCode: http.StatusOK,
+		Errors:     nil,
+		Data:       want,
+	}, nil)
+
+	srv := service.NewRoleService(client)
+
+	role, err := srv.AssignUser(context.Background(), []int32{1, 2}, 2, user)
+
+	assert.Nil(s.T(), err, "Must not got any error")
+	assert.Equal(s.T(), want, role)
+	client.AssertNumberOfCalls(s.T(), "Update", 2)
+}
+
+func (s *RoleServiceTest) TestAssignUserAlreadyAssignedRoleService() {
+	user := &proto.User{Id: 5}
+
+	want := &proto.Role{
+		Id:    s.Roles[1].Id,
+		Name:  s.Roles[1].Name,
+		Users: []*proto.User{user},
+	}
+
+	client := new(ClientMock)
+
+	client.On("FindMulti", &proto.FindMultiRoleRequest{Ids: []uint32{1, 2}}).Return(&proto.RoleListResponse{
+		StatusCode: http.StatusOK,
+		Errors:     nil,
+		Data:       []*proto.Role{{Id: s.Roles[0].Id}, want},
+	}, nil)
+
+	srv := service.NewRoleService(client)
+
+	role, err := srv.AssignUser(context.Background(), []int32{1, 2}, 2, user)
+
+	assert.Nil(s.T(), err, "Must not got any error")
+	assert.Equal(s.T(), want, role)
+	client.AssertNumberOfCalls(s.T(), "Update", 0)
+}
+
+func (s *RoleServiceTest) TestAssignUserGrpcErrRoleService() {
+	want := s.ServiceDownErr
+
+	client := new(ClientMock)
+
+	client.On("FindMulti", &proto.FindMultiRoleRequest{Ids: []uint32{1, 2}}).Return(nil, errors.New("Service is down"))
+
+	srv := service.NewRoleService(client)
+
+	_, err := srv.AssignUser(context.Background(), []int32{1, 2}, 2, &proto.User{Id: 5})
+
+	assert.Equal(s.T(), want, err)
+}
@@ -0,0 +1,51 @@
+package router
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/config"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/dto"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/middleware"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/router"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/service"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/test/auth"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/test/permission"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"time"
+)
+
+// TestSignedQuery runs a signed api key request through the guard, the signature covers the query string so a changed
+// query is rejected
+func (u *ResourceRouterTest) TestSignedQuery() {
+	hash := sha256.Sum256([]byte("secret"))
+	key, _ := service.NewApiKey("report", hex.EncodeToString(hash[:]), []string{"role:read"}, "", "signing-secret", 0)
+	apiKeys := service.NewApiKeyService(service.NewMemoryApiKeyStore([]*service.ApiKey{key}), time.Minute)
+
+	guard := middleware.NewAuthGuard(new(auth.ServiceMock), middleware.NewPermissionGuard(new(permission.RoleServiceMock)), nil, config.Session{Mode: "header"}, middleware.AuthGuardOptions{ApiKeys: apiKeys})
+
+	r := router.NewFiberRouter(guard, nil, config.Proxy{})
+	r.Resource("/role", &router.Resource{
+		List: router.Handle(reply("list"), middleware.RequirePermission("role:read")),
+	})
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	signature := hex.EncodeToString(service.SignRequest("signing-secret", &dto.SignedRequest{Method: http.MethodGet, URI: "/role?limit=10&page=1", Timestamp: timestamp}))
+
+	request := func(uri string) int {
+		req := httptest.NewRequest(http.MethodGet, uri, nil)
+		req.Header.Set("Authorization", "ApiKey report.secret")
+		req.Header.Set(middleware.SignatureTimestampHeader, timestamp)
+		req.Header.Set(middleware.SignatureHeader, signature)
+
+		res, err := r.Test(req)
+		assert.Nil(u.T(), err)
+
+		return res.StatusCode
+	}
+
+	assert.Equal(u.T(), http.StatusUnauthorized, request("/role?limit=1000&page=1"))
+	assert.Equal(u.T(), http.StatusOK, request("/role?limit=10&page=1"))
+}
@@ -0,0 +1,64 @@
+package router
+
+import (
+	"github.com/samithiwat/samithiwat-backend-gateway/src/config"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/handler"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/middleware"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/router"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/test/auth"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/test/permission"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+)
+
+// clientIP mounts a route that replies the client address seen by the handlers, the requests of the test come from 0.0.0.0
+func (u *ResourceRouterTest) clientIP(proxy config.Proxy, forwardedFor string) string {
+	guard := middleware.NewAuthGuard(new(auth.ServiceMock), middleware.NewPermissionGuard(new(permission.RoleServiceMock)), nil, config.Session{Mode: "header"}, middleware.AuthGuardOptions{})
+
+	r := router.NewFiberRouter(guard, nil, proxy)
+	r.GetAuth("/ip", func(c handler.AuthContext) {
+		c.JSON(http.StatusOK, c.IP())
+	}, middleware.Public())
+
+	req := httptest.NewRequest(http.MethodGet, "/auth/ip", nil)
+	if forwardedFor != "" {
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+	}
+
+	res, err := r.Test(req)
+	assert.Nil(u.T(), err)
+
+	body, _ := io.ReadAll(res.Body)
+
+	return strings.Trim(string(body), `"`)
+}
+
+func (u *ResourceRouterTest) TestClientIPWithoutTrustedProxy() {
+	ip := u.clientIP(config.Proxy{Header: "X-Forwarded-For"}, "203.0.113.7")
+
+	assert.Equal(u.T(), "0.0.0.0", ip)
+}
+
+func (u *ResourceRouterTest) TestClientIPFromTrustedProxy() {
+	ip := u.clientIP(config.Proxy{Header: "X-Forwarded-For", TrustedProxies: []string{"0.0.0.0"}}, "203.0.113.7")
+
+	assert.Equal(u.T(), "203.0.113.7", ip)
+}
+
+func (u *ResourceRouterTest) TestClientIPSkipsTrustedHops() {
+	proxy := config.Proxy{Header: "X-Forwarded-For", TrustedProxies: []string{"0.0.0.0", "10.0.0.0/8"}}
+
+	// the client sent its own header, the proxies appended the address they saw
+	ip := u.clientIP(proxy, "198.51.100.1, 203.0.113.7, 10.0.0.5")
+
+	assert.Equal(u.T(), "203.0.113.7", ip)
+}
+
+func (u *ResourceRouterTest) TestClientIPWithoutHeader() {
+	ip := u.clientIP(config.Proxy{Header: "X-Forwarded-For", TrustedProxies: []string{"0.0.0.0"}}, "")
+
+	assert.Equal(u.T(), "0.0.0.0", ip)
+}
@@ -0,0 +1,47 @@
+package router
+
+import (
+	"github.com/samithiwat/samithiwat-backend-gateway/src/config"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/dto"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/middleware"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/router"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/service"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/test/auth"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/test/permission"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"time"
+)
+
+// TestClientLimitedBeforeGuard floods a guarded route with an invalid token, the guard rejects every request so only the
+// client bucket taken before it can limit them
+func (u *ResourceRouterTest) TestClientLimitedBeforeGuard() {
+	authSrv := new(auth.ServiceMock)
+	authSrv.On("Validate", "bogus").Return(0, &dto.ResponseErr{StatusCode: http.StatusUnauthorized, Message: "Invalid token"})
+
+	limiter := middleware.NewRateLimiter(service.NewRateLimitService(service.NewMemoryRateLimitStore(), map[string]service.RateLimitPolicy{
+		"client": {Limit: 2, Period: time.Minute},
+	}))
+
+	guard := middleware.NewAuthGuard(authSrv, middleware.NewPermissionGuard(new(permission.RoleServiceMock)), nil, config.Session{Mode: "header"}, middleware.AuthGuardOptions{})
+
+	r := router.NewFiberRouter(guard, limiter, config.Proxy{})
+	r.Resource("/role", &router.Resource{
+		List: router.Handle(reply("list"), middleware.Authenticated()),
+	})
+
+	var statuses []int
+	for i := 0; i < 3; i++ {
+		req := httptest.NewRequest(http.MethodGet, "/role", nil)
+		req.Header.Set("Authorization", "Bearer bogus")
+
+		res, err := r.Test(req)
+		assert.Nil(u.T(), err)
+
+		statuses = append(statuses, res.StatusCode)
+	}
+
+	assert.Equal(u.T(), []int{http.StatusUnauthorized, http.StatusUnauthorized, http.StatusTooManyRequests}, statuses)
+	authSrv.AssertNumberOfCalls(u.T(), "Validate", 2)
+}
@@ -0,0 +1,49 @@
+package router
+
+import (
+	"encoding/json"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/common"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/dto"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/handler"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/middleware"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/router"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+)
+
+func (u *ResourceRouterTest) TestRequestIdInUserContext() {
+	var seen string
+
+	u.Router.Resource("/user", &router.Resource{
+		List: router.Handle(func(c handler.UserContext) {
+			seen = common.RequestID(c.UserContext())
+			c.JSON(http.StatusOK, seen)
+		}, middleware.Public()),
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/user", nil)
+	req.Header.Set("X-Request-ID", "edge-7f3a")
+
+	res, err := u.Router.Test(req)
+
+	assert.Nil(u.T(), err)
+	assert.Equal(u.T(), "edge-7f3a", seen)
+	assert.Equal(u.T(), "edge-7f3a", res.Header.Get("X-Request-ID"))
+}
+
+func (u *ResourceRouterTest) TestRequestIdInResponseErr() {
+	u.Router.Resource("/role", &router.Resource{
+		List: router.Handle(reply("list"), middleware.Authenticated()),
+	})
+
+	res, err := u.Router.Test(httptest.NewRequest(http.MethodGet, "/role", nil))
+	assert.Nil(u.T(), err)
+
+	errRes := dto.ResponseErr{}
+	assert.Nil(u.T(), json.NewDecoder(res.Body).Decode(&errRes))
+
+	assert.Equal(u.T(), http.StatusUnauthorized, errRes.StatusCode)
+	assert.NotEmpty(u.T(), errRes.RequestID)
+	assert.Equal(u.T(), res.Header.Get("X-Request-ID"), errRes.RequestID)
+}
@@ -0,0 +1,136 @@
+package router
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/config"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/handler"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/middleware"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/router"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/test/auth"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/test/permission"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type ResourceRouterTest struct {
+	suite.Suite
+	Router *router.FiberRouter
+}
+
+func TestResourceRouter(t *testing.T) {
+	suite.Run(t, new(ResourceRouterTest))
+}
+
+func (u *ResourceRouterTest) SetupTest() {
+	guard := middleware.NewAuthGuard(new(auth.ServiceMock), middleware.NewPermissionGuard(new(permission.RoleServiceMock)), nil, config.Session{Mode: "header"}, middleware.AuthGuardOptions{})
+
+	u.Router = router.NewFiberRouter(guard, nil, config.Proxy{})
+}
+
+func reply(body string) func(handler.RoleContext) {
+	return func(c handler.RoleContext) {
+		c.JSON(http.StatusOK, body)
+	}
+}
+
+func (u *ResourceRouterTest) request(method string, path string) (int, string) {
+	res, err := u.Router.Test(httptest.NewRequest(method, path, nil))
+	assert.Nil(u.T(), err)
+
+	body, _ := io.ReadAll(res.Body)
+
+	return res.StatusCode, strings.Trim(string(body), `"`)
+}
+
+func (u *ResourceRouterTest) TestMountCrud() {
+	u.Router.Resource("/role", &router.Resource{
+		List:   router.Handle(reply("list"), middleware.Public()),
+		Get:    router.Handle(reply("get"), middleware.Public()),
+		Create: router.Handle(reply("create"), middleware.Public()),
+		Update: router.Handle(reply("update"), middleware.Public()),
+		Delete: router.Handle(reply("delete"), middleware.Public()),
+		Routes: []*router.Route{
+			router.On(http.MethodPost, "/:id/permission/:permissionId", reply("add permission"), middleware.Public()),
+		},
+	})
+
+	cases := []struct {
+		method string
+		path   string
+		want   string
+	}{
+		{http.MethodGet, "/role", "list"},
+		{http.MethodGet, "/role/1", "get"},
+		{http.MethodPost, "/role", "create"},
+		{http.MethodPatch, "/role/1", "update"},
+		{http.MethodDelete, "/role/1", "delete"},
+		{http.MethodPost, "/role/1/permission/2", "add permission"},
+	}
+
+	for _, c := range cases {
+		status, body := u.request(c.method, c.path)
+
+		assert.Equal(u.T(), http.StatusOK, status, c.path)
+		assert.Equal(u.T(), c.want, body, c.path)
+	}
+}
+
+func (u *ResourceRouterTest) TestNilRouteIsNotMounted() {
+	u.Router.Resource("/permission", &router.Resource{
+		List: router.Handle(reply("list"), middleware.Public()),
+	})
+
+	status, _ := u.request(http.MethodDelete, "/permission/1")
+
+	assert.NotEqual(u.T(), http.StatusOK, status)
+}
+
+func (u *ResourceRouterTest) TestRouteGuarded() {
+	u.Router.Resource("/role", &router.Resource{
+		List: router.Handle(reply("list"), middleware.Authenticated()),
+	})
+
+	status, _ := u.request(http.MethodGet, "/role")
+
+	assert.Equal(u.T(), http.StatusUnauthorized, status)
+}
+
+func (u *ResourceRouterTest) TestMiddlewareOrder() {
+	var order []string
+	track := func(name string) fiber.Handler {
+		return func(c *fiber.Ctx) error {
+			order = append(order, name)
+			return c.Next()
+		}
+	}
+
+	u.Router.Resource("/role", &router.Resource{
+		List:       router.Handle(reply("list"), middleware.Public(), track("route")),
+		Middleware: []fiber.Handler{track("resource")},
+	})
+
+	status, body := u.request(http.MethodGet, "/role")
+
+	assert.Equal(u.T(), http.StatusOK, status)
+	assert.Equal(u.T(), "list", body)
+	assert.Equal(u.T(), []string{"resource", "route"}, order)
+}
+
+func (u *ResourceRouterTest) TestInvalidHandlerPanics() {
+	assert.Panics(u.T(), func() {
+		u.Router.Resource("/role", &router.Resource{
+			List: router.Handle(func(string) {}, middleware.Public()),
+		})
+	})
+
+	assert.Panics(u.T(), func() {
+		u.Router.Resource("/role", &router.Resource{
+			List: router.Handle(nil, middleware.Public()),
+		})
+	})
+}
@@ -0,0 +1,90 @@
+package router
+
+import (
+	"context"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/config"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/dto"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/handler"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/middleware"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/proto"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/router"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/service"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/test/activity"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/test/auth"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/test/permission"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/test/user"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/validator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"time"
+)
+
+// TestRefreshWithRefreshCookie refreshes the session of a browser whose access cookie has expired, it only sends the
+// refresh cookie and the csrf token
+func (u *ResourceRouterTest) TestRefreshWithRefreshCookie() {
+	session := config.Session{
+		Mode:          "cookie",
+		AccessCookie:  "access_token",
+		RefreshCookie: "refresh_token",
+		CsrfCookie:    "csrf_token",
+		CsrfHeader:    "X-CSRF-Token",
+		RefreshMaxAge: 24 * time.Hour,
+	}
+
+	old := &proto.Credential{AccessToken: "old-access", RefreshToken: "old-refresh", ExpiresIn: 3600}
+	renewed := &proto.Credential{AccessToken: "new-access", RefreshToken: "new-refresh", ExpiresIn: 3600}
+
+	authSrv := new(auth.ServiceMock)
+	lockoutSrv := new(auth.LockoutServiceMock)
+
+	authSrv.On("RefreshToken", old.RefreshToken).Return(renewed, nil)
+	lockoutSrv.On("Check", mock.Anything, mock.Anything).Return(time.Duration(0))
+
+	sessions := service.NewSessionService(service.NewMemorySessionStore(), time.Hour)
+	sessions.Record(context.Background(), 1, old, &dto.Device{})
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewAuthHandler(authSrv, handler.AuthDeps{
+		Users:      new(user.ServiceMock),
+		Activity:   new(activity.ServiceMock),
+		TokenCache: new(auth.TokenCacheMock),
+		Lockout:    lockoutSrv,
+		Verifier:   service.DisabledVerification{},
+		TwoFactor:  service.DisabledTwoFactor{},
+		Sessions:   sessions,
+		Emails:     service.NewUserEmailService(service.NewMemoryUserEmailStore()),
+	}, session, v)
+
+	guard := middleware.NewAuthGuard(authSrv, middleware.NewPermissionGuard(new(permission.RoleServiceMock)), nil, session, middleware.AuthGuardOptions{Sessions: sessions})
+
+	r := router.NewFiberRouter(guard, nil, config.Proxy{})
+	r.PostAuth("/token", h.RefreshToken, middleware.Public())
+
+	req := httptest.NewRequest(http.MethodPost, "/auth/token", nil)
+	req.AddCookie(&http.Cookie{Name: "refresh_token", Value: old.RefreshToken})
+	req.AddCookie(&http.Cookie{Name: "csrf_token", Value: "csrf"})
+	req.Header.Set("X-CSRF-Token", "csrf")
+
+	res, err := r.Test(req)
+	assert.Nil(u.T(), err)
+	assert.Equal(u.T(), http.StatusOK, res.StatusCode)
+
+	cookies := map[string]string{}
+	for _, cookie := range res.Cookies() {
+		cookies[cookie.Name] = cookie.Value
+	}
+	assert.Equal(u.T(), renewed.AccessToken, cookies["access_token"])
+	assert.Equal(u.T(), renewed.RefreshToken, cookies["refresh_token"])
+
+	// the session of the refresh token moved to the new credential of its owner
+	id, errRes := sessions.Authenticate(context.Background(), renewed.AccessToken)
+	assert.Nil(u.T(), errRes)
+	assert.NotEmpty(u.T(), id)
+
+	list, _ := sessions.List(context.Background(), 1, id)
+	assert.Len(u.T(), list, 1)
+	authSrv.AssertNotCalled(u.T(), "Validate", mock.Anything)
+}
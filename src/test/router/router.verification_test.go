@@ -0,0 +1,60 @@
+package router
+
+import (
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/config"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/middleware"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/router"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/service"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/test/auth"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/test/mail"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/test/permission"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"time"
+)
+
+// TestRequiredVerification runs the required email verification through the guard, the pending verification is kept in
+// redis so a restarted gateway still rejects the user until the email is verified
+func (u *ResourceRouterTest) TestRequiredVerification() {
+	server := miniredis.RunT(u.T())
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+
+	authSrv := new(auth.ServiceMock)
+	authSrv.On("Validate", "token").Return(1, nil)
+
+	mailer := new(mail.MailerMock)
+	service.NewEmailVerificationService(service.NewRedisVerificationStore(client, "gateway:"), mailer, "secret", time.Hour, "https://samithiwat.dev/verify?token=%s").
+		Send(1, "smithy@samithiwat.dev")
+
+	// the gateway restarted, only the store is shared with the service that sent the link
+	verificationSrv := service.NewEmailVerificationService(service.NewRedisVerificationStore(client, "gateway:"), mailer, "secret", time.Hour, "https://samithiwat.dev/verify?token=%s")
+
+	guard := middleware.NewAuthGuard(authSrv, middleware.NewPermissionGuard(new(permission.RoleServiceMock)), nil, config.Session{Mode: "header"}, middleware.AuthGuardOptions{Verifier: verificationSrv})
+
+	r := router.NewFiberRouter(guard, nil, config.Proxy{})
+	r.Resource("/role", &router.Resource{
+		List: router.Handle(reply("list"), middleware.Authenticated()),
+	})
+
+	request := func() int {
+		req := httptest.NewRequest(http.MethodGet, "/role", nil)
+		req.Header.Set("Authorization", "Bearer token")
+
+		res, err := r.Test(req)
+		assert.Nil(u.T(), err)
+
+		return res.StatusCode
+	}
+
+	assert.Equal(u.T(), http.StatusForbidden, request())
+
+	token := regexp.MustCompile(`token=(\S+)`).FindStringSubmatch(mailer.Mails[0].Body)
+	assert.Len(u.T(), token, 2)
+	assert.Nil(u.T(), verificationSrv.Verify(token[1]))
+
+	assert.Equal(u.T(), http.StatusOK, request())
+}
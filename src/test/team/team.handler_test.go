@@ -3,13 +3,19 @@ package team
 import (
 	"github.com/bxcodec/faker/v3"
 	"github.com/pkg/errors"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/constant"
 	"github.com/samithiwat/samithiwat-backend-gateway/src/dto"
 	"github.com/samithiwat/samithiwat-backend-gateway/src/handler"
 	"github.com/samithiwat/samithiwat-backend-gateway/src/proto"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/test/activity"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/test/user"
 	"github.com/samithiwat/samithiwat-backend-gateway/src/validator"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 	"net/http"
+	"strconv"
+	"strings"
 	"testing"
 )
 
@@ -22,6 +28,8 @@ type TeamHandlerTest struct {
 	NotFoundErr    *dto.ResponseErr
 	ServiceDownErr *dto.ResponseErr
 	InvalidIDErr   *dto.ResponseErr
+	Member         *proto.User
+	MemberDto      *dto.MemberDto
 }
 
 func TestTeamHandler(t *testing.T) {
@@ -53,9 +61,24 @@ func (u *TeamHandlerTest) SetupTest() {
 		Description: faker.Sentence(),
 	}
 
-	_ = faker.FakeData(&u.TeamDto)
+	u.TeamDto = &dto.TeamDto{
+		Name:        faker.Word(),
+		Description: faker.Sentence(),
+	}
+
 	_ = faker.FakeData(&u.Query)
 
+	u.Member = &proto.User{
+		Id:        5,
+		Firstname: faker.FirstName(),
+		Lastname:  faker.LastName(),
+		ImageUrl:  faker.URL(),
+	}
+
+	u.MemberDto = &dto.MemberDto{
+		UserID: 5,
+	}
+
 	u.Teams = append(u.Teams, u.Team, Team2, Team3, Team4)
 
 	u.ServiceDownErr = &dto.ResponseErr{
@@ -89,6 +112,8 @@ func (u *TeamHandlerTest) TestFindAllTeam() {
 	}
 
 	srv := new(ServiceMock)
+	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
 	c := &ContextMock{
 		Team:    u.Team,
 		Teams:   u.Teams,
@@ -101,7 +126,7 @@ func (u *TeamHandlerTest) TestFindAllTeam() {
 
 	v, _ := validator.NewValidator()
 
-	h := handler.NewTeamHandler(srv, v)
+	h := handler.NewTeamHandler(srv, userSrv, activitySrv, new(OwnerCheckerMock), new(PermissionCheckerMock), v)
 	h.FindAll(c)
 
 	assert.Equal(u.T(), want, c.V)
@@ -114,6 +139,8 @@ func (u *TeamHandlerTest) TestFindAllInvalidQueryParamTeam() {
 	}
 
 	srv := new(ServiceMock)
+	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
 	c := &ContextMock{
 		Team:    u.Team,
 		Teams:   u.Teams,
@@ -126,7 +153,7 @@ func (u *TeamHandlerTest) TestFindAllInvalidQueryParamTeam() {
 
 	v, _ := validator.NewValidator()
 
-	h := handler.NewTeamHandler(srv, v)
+	h := handler.NewTeamHandler(srv, userSrv, activitySrv, new(OwnerCheckerMock), new(PermissionCheckerMock), v)
 
 	h.FindAll(c)
 
@@ -137,6 +164,8 @@ func (u *TeamHandlerTest) TestFindAllGrpcErrTeam() {
 	want := u.ServiceDownErr
 
 	srv := new(ServiceMock)
+	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
 	c := &ContextMock{
 		Team:    u.Team,
 		Teams:   u.Teams,
@@ -149,7 +178,7 @@ func (u *TeamHandlerTest) TestFindAllGrpcErrTeam() {
 
 	v, _ := validator.NewValidator()
 
-	h := handler.NewTeamHandler(srv, v)
+	h := handler.NewTeamHandler(srv, userSrv, activitySrv, new(OwnerCheckerMock), new(PermissionCheckerMock), v)
 
 	h.FindAll(c)
 
@@ -160,6 +189,8 @@ func (u *TeamHandlerTest) TestFindOneTeam() {
 	want := u.Team
 
 	srv := new(ServiceMock)
+	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
 	c := &ContextMock{
 		Team:    u.Team,
 		Teams:   u.Teams,
@@ -172,7 +203,7 @@ func (u *TeamHandlerTest) TestFindOneTeam() {
 
 	v, _ := validator.NewValidator()
 
-	h := handler.NewTeamHandler(srv, v)
+	h := handler.NewTeamHandler(srv, userSrv, activitySrv, new(OwnerCheckerMock), new(PermissionCheckerMock), v)
 
 	h.FindOne(c)
 
@@ -183,6 +214,8 @@ func (u *TeamHandlerTest) TestFindOneInvalidRequestParamIDTeam() {
 	want := u.InvalidIDErr
 
 	srv := new(ServiceMock)
+	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
 	c := &ContextMock{
 		Team:    u.Team,
 		Teams:   u.Teams,
@@ -195,7 +228,7 @@ func (u *TeamHandlerTest) TestFindOneInvalidRequestParamIDTeam() {
 
 	v, _ := validator.NewValidator()
 
-	h := handler.NewTeamHandler(srv, v)
+	h := handler.NewTeamHandler(srv, userSrv, activitySrv, new(OwnerCheckerMock), new(PermissionCheckerMock), v)
 	h.FindOne(c)
 
 	assert.Equal(u.T(), want, c.V)
@@ -205,6 +238,8 @@ func (u *TeamHandlerTest) TestFindOneErrorNotFoundTeam() {
 	want := u.NotFoundErr
 
 	srv := new(ServiceMock)
+	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
 	c := &ContextMock{
 		Team:    u.Team,
 		Teams:   u.Teams,
@@ -217,7 +252,7 @@ func (u *TeamHandlerTest) TestFindOneErrorNotFoundTeam() {
 
 	v, _ := validator.NewValidator()
 
-	h := handler.NewTeamHandler(srv, v)
+	h := handler.NewTeamHandler(srv, userSrv, activitySrv, new(OwnerCheckerMock), new(PermissionCheckerMock), v)
 
 	h.FindOne(c)
 
@@ -228,6 +263,8 @@ func (u *TeamHandlerTest) TestFindOneGrpcErrTeam() {
 	want := u.ServiceDownErr
 
 	srv := new(ServiceMock)
+	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
 	c := &ContextMock{
 		Team:    u.Team,
 		Teams:   u.Teams,
@@ -240,7 +277,7 @@ func (u *TeamHandlerTest) TestFindOneGrpcErrTeam() {
 
 	v, _ := validator.NewValidator()
 
-	h := handler.NewTeamHandler(srv, v)
+	h := handler.NewTeamHandler(srv, userSrv, activitySrv, new(OwnerCheckerMock), new(PermissionCheckerMock), v)
 
 	h.FindOne(c)
 
@@ -251,6 +288,8 @@ func (u *TeamHandlerTest) TestCreateTeam() {
 	want := u.Team
 
 	srv := new(ServiceMock)
+	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
 	c := &ContextMock{
 		Team:    u.Team,
 		Teams:   u.Teams,
@@ -263,7 +302,7 @@ func (u *TeamHandlerTest) TestCreateTeam() {
 
 	v, _ := validator.NewValidator()
 
-	h := handler.NewTeamHandler(srv, v)
+	h := handler.NewTeamHandler(srv, userSrv, activitySrv, new(OwnerCheckerMock), new(PermissionCheckerMock), v)
 	h.Create(c)
 
 	assert.Equal(u.T(), want, c.V)
@@ -276,6 +315,8 @@ func (u *TeamHandlerTest) TestCreateErrorDuplicatedTeam() {
 	}
 
 	srv := new(ServiceMock)
+	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
 	c := &ContextMock{
 		Team:    u.Team,
 		Teams:   u.Teams,
@@ -288,7 +329,7 @@ func (u *TeamHandlerTest) TestCreateErrorDuplicatedTeam() {
 
 	v, _ := validator.NewValidator()
 
-	h := handler.NewTeamHandler(srv, v)
+	h := handler.NewTeamHandler(srv, userSrv, activitySrv, new(OwnerCheckerMock), new(PermissionCheckerMock), v)
 	h.Create(c)
 
 	assert.Equal(u.T(), want, c.V)
@@ -301,6 +342,8 @@ func (u *TeamHandlerTest) TestCreateInvalidBodyRequest() {
 	}
 
 	srv := new(ServiceMock)
+	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
 	c := &ContextMock{
 		Team:    u.Team,
 		Teams:   u.Teams,
@@ -313,7 +356,7 @@ func (u *TeamHandlerTest) TestCreateInvalidBodyRequest() {
 
 	v, _ := validator.NewValidator()
 
-	h := handler.NewTeamHandler(srv, v)
+	h := handler.NewTeamHandler(srv, userSrv, activitySrv, new(OwnerCheckerMock), new(PermissionCheckerMock), v)
 	h.Create(c)
 
 	assert.Equal(u.T(), want, c.V)
@@ -323,6 +366,8 @@ func (u *TeamHandlerTest) TestCreateGrpcErrTeam() {
 	want := u.ServiceDownErr
 
 	srv := new(ServiceMock)
+	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
 	c := &ContextMock{
 		Team:    u.Team,
 		Teams:   u.Teams,
@@ -335,7 +380,7 @@ func (u *TeamHandlerTest) TestCreateGrpcErrTeam() {
 
 	v, _ := validator.NewValidator()
 
-	h := handler.NewTeamHandler(srv, v)
+	h := handler.NewTeamHandler(srv, userSrv, activitySrv, new(OwnerCheckerMock), new(PermissionCheckerMock), v)
 
 	h.Create(c)
 
@@ -346,6 +391,8 @@ func (u *TeamHandlerTest) TestUpdateTeam() {
 	want := u.Team
 
 	srv := new(ServiceMock)
+	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
 	c := &ContextMock{
 		Team:    u.Team,
 		Teams:   u.Teams,
@@ -359,17 +406,204 @@ func (u *TeamHandlerTest) TestUpdateTeam() {
 
 	v, _ := validator.NewValidator()
 
-	h := handler.NewTeamHandler(srv, v)
+	h := handler.NewTeamHandler(srv, userSrv, activitySrv, new(OwnerCheckerMock), new(PermissionCheckerMock), v)
 
 	h.Update(c)
 
 	assert.Equal(u.T(), want, c.V)
 }
 
+func (u *TeamHandlerTest) TestUpdateMoveToOwnedParent() {
+	teamDto := &dto.TeamDto{Name: u.TeamDto.Name, ParentTeamID: dto.NullableID{ID: 2, Set: true}}
+
+	srv := new(ServiceMock)
+	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	owners := new(OwnerCheckerMock)
+	permissions := new(PermissionCheckerMock)
+	c := &ContextMock{TeamDto: teamDto}
+
+	c.On("Bind", &dto.TeamDto{}).Return(nil)
+	c.On("ID").Return(1, nil)
+	c.On("Principal").Return(&dto.Principal{UserID: 1})
+	owners.On("IsOwner", constant.ResourceTeam, int32(2), int32(1)).Return(true, nil)
+	srv.On("Update", int32(1), teamDto).Return(u.Team, nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewTeamHandler(srv, userSrv, activitySrv, owners, permissions, v)
+	h.Update(c)
+
+	assert.Equal(u.T(), u.Team, c.V)
+	permissions.AssertNotCalled(u.T(), "HasPermission", mock.Anything, mock.Anything)
+}
+
+func (u *TeamHandlerTest) TestUpdateMoveToParentOfOthers() {
+	want := &dto.ResponseErr{
+		StatusCode: http.StatusForbidden,
+		Message:    "Insufficient permission",
+		Data:       "team:update",
+	}
+	teamDto := &dto.TeamDto{Name: u.TeamDto.Name, ParentTeamID: dto.NullableID{ID: 2, Set: true}}
+
+	srv := new(ServiceMock)
+	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	owners := new(OwnerCheckerMock)
+	permissions := new(PermissionCheckerMock)
+	c := &ContextMock{TeamDto: teamDto}
+
+	c.On("Bind", &dto.TeamDto{}).Return(nil)
+	c.On("ID").Return(1, nil)
+	c.On("Principal").Return(&dto.Principal{UserID: 1})
+	owners.On("IsOwner", constant.ResourceTeam, int32(2), int32(1)).Return(false, nil)
+	permissions.On("HasPermission", int32(1), "team:update").Return(false, nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewTeamHandler(srv, userSrv, activitySrv, owners, permissions, v)
+	h.Update(c)
+
+	assert.Equal(u.T(), want, c.V)
+	srv.AssertNotCalled(u.T(), "Update", mock.Anything, mock.Anything)
+}
+
+func (u *TeamHandlerTest) TestUpdateMoveByMemberWithPermission() {
+	teamDto := &dto.TeamDto{Name: u.TeamDto.Name, ParentTeamID: dto.NullableID{ID: 2, Set: true}}
+
+	srv := new(ServiceMock)
+	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	owners := new(OwnerCheckerMock)
+	permissions := new(PermissionCheckerMock)
+	c := &ContextMock{TeamDto: teamDto}
+
+	c.On("Bind", &dto.TeamDto{}).Return(nil)
+	c.On("ID").Return(1, nil)
+	c.On("Principal").Return(&dto.Principal{UserID: 1})
+	owners.On("IsOwner", constant.ResourceTeam, int32(2), int32(1)).Return(false, nil)
+	permissions.On("HasPermission", int32(1), "team:update").Return(true, nil)
+	srv.On("Update", int32(1), teamDto).Return(u.Team, nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewTeamHandler(srv, userSrv, activitySrv, owners, permissions, v)
+	h.Update(c)
+
+	assert.Equal(u.T(), u.Team, c.V)
+}
+
+func (u *TeamHandlerTest) TestUpdateMoveByAdmin() {
+	teamDto := &dto.TeamDto{Name: u.TeamDto.Name, OrganizationID: 3, ParentTeamID: dto.NullableID{ID: 2, Set: true}}
+
+	srv := new(ServiceMock)
+	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	owners := new(OwnerCheckerMock)
+	permissions := new(PermissionCheckerMock)
+	c := &ContextMock{TeamDto: teamDto}
+
+	c.On("Bind", &dto.TeamDto{}).Return(nil)
+	c.On("ID").Return(1, nil)
+	c.On("Principal").Return(&dto.Principal{UserID: 1, Admin: true})
+	srv.On("Update", int32(1), teamDto).Return(u.Team, nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewTeamHandler(srv, userSrv, activitySrv, owners, permissions, v)
+	h.Update(c)
+
+	assert.Equal(u.T(), u.Team, c.V)
+	owners.AssertNotCalled(u.T(), "IsOwner", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (u *TeamHandlerTest) TestUpdateMoveToOrganizationOfOthers() {
+	want := &dto.ResponseErr{
+		StatusCode: http.StatusForbidden,
+		Message:    "Insufficient permission",
+		Data:       "team:update",
+	}
+	teamDto := &dto.TeamDto{Name: u.TeamDto.Name, OrganizationID: 3}
+
+	srv := new(ServiceMock)
+	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	owners := new(OwnerCheckerMock)
+	permissions := new(PermissionCheckerMock)
+	c := &ContextMock{TeamDto: teamDto}
+
+	c.On("Bind", &dto.TeamDto{}).Return(nil)
+	c.On("ID").Return(1, nil)
+	c.On("Principal").Return(&dto.Principal{UserID: 1})
+	srv.On("FindOne", int32(1)).Return(&proto.Team{Id: 1, Organization: &proto.Organization{Id: 2}}, nil)
+	owners.On("IsOwner", constant.ResourceOrganization, int32(3), int32(1)).Return(false, nil)
+	permissions.On("HasPermission", int32(1), "team:update").Return(false, nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewTeamHandler(srv, userSrv, activitySrv, owners, permissions, v)
+	h.Update(c)
+
+	assert.Equal(u.T(), want, c.V)
+	srv.AssertNotCalled(u.T(), "Update", mock.Anything, mock.Anything)
+}
+
+func (u *TeamHandlerTest) TestUpdateKeepOrganization() {
+	teamDto := &dto.TeamDto{Name: u.TeamDto.Name, OrganizationID: 2}
+
+	srv := new(ServiceMock)
+	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	owners := new(OwnerCheckerMock)
+	permissions := new(PermissionCheckerMock)
+	c := &ContextMock{TeamDto: teamDto}
+
+	c.On("Bind", &dto.TeamDto{}).Return(nil)
+	c.On("ID").Return(1, nil)
+	c.On("Principal").Return(&dto.Principal{UserID: 1})
+	srv.On("FindOne", int32(1)).Return(&proto.Team{Id: 1, Organization: &proto.Organization{Id: 2}}, nil)
+	srv.On("Update", int32(1), teamDto).Return(u.Team, nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewTeamHandler(srv, userSrv, activitySrv, owners, permissions, v)
+	h.Update(c)
+
+	assert.Equal(u.T(), u.Team, c.V)
+	owners.AssertNotCalled(u.T(), "IsOwner", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (u *TeamHandlerTest) TestUpdateMoveByApiKeyWithoutOwnership() {
+	teamDto := &dto.TeamDto{Name: u.TeamDto.Name, ParentTeamID: dto.NullableID{ID: 2, Set: true}}
+
+	srv := new(ServiceMock)
+	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	owners := new(OwnerCheckerMock)
+	permissions := new(PermissionCheckerMock)
+	c := &ContextMock{TeamDto: teamDto}
+
+	c.On("Bind", &dto.TeamDto{}).Return(nil)
+	c.On("ID").Return(1, nil)
+	c.On("Principal").Return(&dto.Principal{UserID: 1, ApiKeyID: "ci"})
+	owners.On("IsOwner", constant.ResourceTeam, int32(2), int32(1)).Return(false, nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewTeamHandler(srv, userSrv, activitySrv, owners, permissions, v)
+	h.Update(c)
+
+	assert.Equal(u.T(), http.StatusForbidden, c.V.(*dto.ResponseErr).StatusCode)
+	permissions.AssertNotCalled(u.T(), "HasPermission", mock.Anything, mock.Anything)
+	srv.AssertNotCalled(u.T(), "Update", mock.Anything, mock.Anything)
+}
+
 func (u *TeamHandlerTest) TestUpdateInvalidRequestParamIDTeam() {
 	want := u.InvalidIDErr
 
 	srv := new(ServiceMock)
+	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
 	c := &ContextMock{
 		Team:    u.Team,
 		Teams:   u.Teams,
@@ -383,7 +617,7 @@ func (u *TeamHandlerTest) TestUpdateInvalidRequestParamIDTeam() {
 
 	v, _ := validator.NewValidator()
 
-	h := handler.NewTeamHandler(srv, v)
+	h := handler.NewTeamHandler(srv, userSrv, activitySrv, new(OwnerCheckerMock), new(PermissionCheckerMock), v)
 
 	h.Update(c)
 
@@ -397,6 +631,8 @@ func (u *TeamHandlerTest) TestUpdateInvalidBodyRequest() {
 	}
 
 	srv := new(ServiceMock)
+	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
 	c := &ContextMock{
 		Team:    u.Team,
 		Teams:   u.Teams,
@@ -410,7 +646,7 @@ func (u *TeamHandlerTest) TestUpdateInvalidBodyRequest() {
 
 	v, _ := validator.NewValidator()
 
-	h := handler.NewTeamHandler(srv, v)
+	h := handler.NewTeamHandler(srv, userSrv, activitySrv, new(OwnerCheckerMock), new(PermissionCheckerMock), v)
 	h.Create(c)
 
 	assert.Equal(u.T(), want, c.V)
@@ -420,6 +656,8 @@ func (u *TeamHandlerTest) TestUpdateErrorNotFoundTeam() {
 	want := u.NotFoundErr
 
 	srv := new(ServiceMock)
+	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
 	c := &ContextMock{
 		Team:    u.Team,
 		Teams:   u.Teams,
@@ -433,7 +671,7 @@ func (u *TeamHandlerTest) TestUpdateErrorNotFoundTeam() {
 
 	v, _ := validator.NewValidator()
 
-	h := handler.NewTeamHandler(srv, v)
+	h := handler.NewTeamHandler(srv, userSrv, activitySrv, new(OwnerCheckerMock), new(PermissionCheckerMock), v)
 	h.Update(c)
 
 	assert.Equal(u.T(), want, c.V)
@@ -443,6 +681,8 @@ func (u *TeamHandlerTest) TestUpdateGrpcErrTeam() {
 	want := u.ServiceDownErr
 
 	srv := new(ServiceMock)
+	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
 	c := &ContextMock{
 		Team:    u.Team,
 		Teams:   u.Teams,
@@ -456,7 +696,7 @@ func (u *TeamHandlerTest) TestUpdateGrpcErrTeam() {
 
 	v, _ := validator.NewValidator()
 
-	h := handler.NewTeamHandler(srv, v)
+	h := handler.NewTeamHandler(srv, userSrv, activitySrv, new(OwnerCheckerMock), new(PermissionCheckerMock), v)
 
 	h.Update(c)
 
@@ -467,6 +707,8 @@ func (u *TeamHandlerTest) TestDeleteTeam() {
 	want := u.Team
 
 	srv := new(ServiceMock)
+	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
 	c := &ContextMock{
 		Team:    u.Team,
 		Teams:   u.Teams,
@@ -479,7 +721,7 @@ func (u *TeamHandlerTest) TestDeleteTeam() {
 
 	v, _ := validator.NewValidator()
 
-	h := handler.NewTeamHandler(srv, v)
+	h := handler.NewTeamHandler(srv, userSrv, activitySrv, new(OwnerCheckerMock), new(PermissionCheckerMock), v)
 
 	h.Delete(c)
 
@@ -490,6 +732,8 @@ func (u *TeamHandlerTest) TestDeleteInvalidRequestParamIDTeam() {
 	want := u.InvalidIDErr
 
 	srv := new(ServiceMock)
+	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
 	c := &ContextMock{
 		Team:    u.Team,
 		Teams:   u.Teams,
@@ -502,7 +746,7 @@ func (u *TeamHandlerTest) TestDeleteInvalidRequestParamIDTeam() {
 
 	v, _ := validator.NewValidator()
 
-	h := handler.NewTeamHandler(srv, v)
+	h := handler.NewTeamHandler(srv, userSrv, activitySrv, new(OwnerCheckerMock), new(PermissionCheckerMock), v)
 
 	h.Delete(c)
 
@@ -513,6 +757,8 @@ func (u *TeamHandlerTest) TestDeleteErrorNotFoundTeam() {
 	want := u.NotFoundErr
 
 	srv := new(ServiceMock)
+	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
 	c := &ContextMock{
 		Team:    u.Team,
 		Teams:   u.Teams,
@@ -525,7 +771,7 @@ func (u *TeamHandlerTest) TestDeleteErrorNotFoundTeam() {
 
 	v, _ := validator.NewValidator()
 
-	h := handler.NewTeamHandler(srv, v)
+	h := handler.NewTeamHandler(srv, userSrv, activitySrv, new(OwnerCheckerMock), new(PermissionCheckerMock), v)
 
 	h.Delete(c)
 
@@ -536,6 +782,8 @@ func (u *TeamHandlerTest) TestDeleteGrpcErrTeam() {
 	want := u.ServiceDownErr
 
 	srv := new(ServiceMock)
+	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
 	c := &ContextMock{
 		Team:    u.Team,
 		Teams:   u.Teams,
@@ -548,9 +796,536 @@ func (u *TeamHandlerTest) TestDeleteGrpcErrTeam() {
 
 	v, _ := validator.NewValidator()
 
-	h := handler.NewTeamHandler(srv, v)
+	h := handler.NewTeamHandler(srv, userSrv, activitySrv, new(OwnerCheckerMock), new(PermissionCheckerMock), v)
 
 	h.Delete(c)
 
 	assert.Equal(u.T(), want, c.V)
 }
+
+func (u *TeamHandlerTest) TestFindMultiTeam() {
+	want := []*proto.Team{u.Teams[2], u.Teams[0]}
+
+	srv := new(ServiceMock)
+	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	c := &ContextMock{
+		Team:    u.Team,
+		Teams:   u.Teams,
+		TeamDto: u.TeamDto,
+		IDs:     "3,1",
+		Query:   u.Query,
+	}
+
+	srv.On("FindMulti", []int32{3, 1}).Return(want, nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewTeamHandler(srv, userSrv, activitySrv, new(OwnerCheckerMock), new(PermissionCheckerMock), v)
+	h.FindAll(c)
+
+	assert.Equal(u.T(), want, c.V)
+	srv.AssertNumberOfCalls(u.T(), "FindAll", 0)
+}
+
+func (u *TeamHandlerTest) TestFindMultiInvalidIDsTeam() {
+	srv := new(ServiceMock)
+	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	c := &ContextMock{
+		Team:    u.Team,
+		Teams:   u.Teams,
+		TeamDto: u.TeamDto,
+		IDs:     "1,abc",
+		Query:   u.Query,
+	}
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewTeamHandler(srv, userSrv, activitySrv, new(OwnerCheckerMock), new(PermissionCheckerMock), v)
+	h.FindAll(c)
+
+	errRes, ok := c.V.(*dto.ResponseErr)
+
+	assert.True(u.T(), ok)
+	assert.Equal(u.T(), http.StatusBadRequest, errRes.StatusCode)
+	assert.Equal(u.T(), "Invalid ids query param", errRes.Message)
+	srv.AssertNumberOfCalls(u.T(), "FindMulti", 0)
+}
+
+func (u *TeamHandlerTest) TestFindMultiExceedMaxBatchSizeTeam() {
+	var ids []string
+	for i := 1; i <= constant.MaxBatchSize+1; i++ {
+		ids = append(ids, strconv.Itoa(i))
+	}
+
+	srv := new(ServiceMock)
+	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	c := &ContextMock{
+		Team:    u.Team,
+		Teams:   u.Teams,
+		TeamDto: u.TeamDto,
+		IDs:     strings.Join(ids, ","),
+		Query:   u.Query,
+	}
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewTeamHandler(srv, userSrv, activitySrv, new(OwnerCheckerMock), new(PermissionCheckerMock), v)
+	h.FindAll(c)
+
+	errRes, ok := c.V.(*dto.ResponseErr)
+
+	assert.True(u.T(), ok)
+	assert.Equal(u.T(), http.StatusBadRequest, errRes.StatusCode)
+	srv.AssertNumberOfCalls(u.T(), "FindMulti", 0)
+}
+
+func (u *TeamHandlerTest) TestFindMultiGrpcErrTeam() {
+	want := u.ServiceDownErr
+
+	srv := new(ServiceMock)
+	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	c := &ContextMock{
+		Team:    u.Team,
+		Teams:   u.Teams,
+		TeamDto: u.TeamDto,
+		IDs:     "1,2",
+		Query:   u.Query,
+	}
+
+	srv.On("FindMulti", []int32{1, 2}).Return(nil, u.ServiceDownErr)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewTeamHandler(srv, userSrv, activitySrv, new(OwnerCheckerMock), new(PermissionCheckerMock), v)
+	h.FindAll(c)
+
+	assert.Equal(u.T(), want, c.V)
+}
+
+func (u *TeamHandlerTest) TestFindMembersTeam() {
+	want := &proto.UserPagination{
+		Items: []*proto.User{u.Member},
+		Meta: &proto.PaginationMetadata{
+			TotalItem:    1,
+			ItemCount:    1,
+			ItemsPerPage: 10,
+			TotalPage:    1,
+			CurrentPage:  1,
+		},
+	}
+
+	srv := new(ServiceMock)
+	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	c := &ContextMock{
+		Team:      u.Team,
+		Teams:     u.Teams,
+		TeamDto:   u.TeamDto,
+		MemberDto: u.MemberDto,
+		Query:     u.Query,
+	}
+
+	srv.On("FindMembers", int32(1), u.Query).Return(want, nil)
+	c.On("ID").Return(1, nil)
+	c.On("PaginationQueryParam", &dto.PaginationQueryParams{}).Return(nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewTeamHandler(srv, userSrv, activitySrv, new(OwnerCheckerMock), new(PermissionCheckerMock), v)
+	h.FindMembers(c)
+
+	assert.Equal(u.T(), want, c.V)
+}
+
+func (u *TeamHandlerTest) TestFindMembersNotFoundTeam() {
+	want := u.NotFoundErr
+
+	srv := new(ServiceMock)
+	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	c := &ContextMock{
+		Team:      u.Team,
+		Teams:     u.Teams,
+		TeamDto:   u.TeamDto,
+		MemberDto: u.MemberDto,
+		Query:     u.Query,
+	}
+
+	srv.On("FindMembers", int32(1), u.Query).Return(nil, u.NotFoundErr)
+	c.On("ID").Return(1, nil)
+	c.On("PaginationQueryParam", &dto.PaginationQueryParams{}).Return(nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewTeamHandler(srv, userSrv, activitySrv, new(OwnerCheckerMock), new(PermissionCheckerMock), v)
+	h.FindMembers(c)
+
+	assert.Equal(u.T(), want, c.V)
+}
+
+func (u *TeamHandlerTest) TestAddMemberTeam() {
+	want := &proto.Team{
+		Id:          u.Team.Id,
+		Name:        u.Team.Name,
+		Description: u.Team.Description,
+		Members:     []*proto.User{u.Member},
+	}
+
+	srv := new(ServiceMock)
+	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	c := &ContextMock{
+		Team:      u.Team,
+		Teams:     u.Teams,
+		TeamDto:   u.TeamDto,
+		MemberDto: u.MemberDto,
+		Query:     u.Query,
+	}
+
+	userSrv.On("FindOne", int32(5)).Return(u.Member, nil)
+	srv.On("AddMember", int32(1), u.Member).Return(want, nil)
+	c.On("Bind", &dto.MemberDto{}).Return(nil)
+	c.On("ID").Return(1, nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewTeamHandler(srv, userSrv, activitySrv, new(OwnerCheckerMock), new(PermissionCheckerMock), v)
+	h.AddMember(c)
+
+	assert.Equal(u.T(), want, c.V)
+}
+
+func (u *TeamHandlerTest) TestAddMemberNotFoundUserTeam() {
+	want := &dto.ResponseErr{
+		StatusCode: http.StatusNotFound,
+		Message:    "Not found user",
+	}
+
+	srv := new(ServiceMock)
+	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	c := &ContextMock{
+		Team:      u.Team,
+		Teams:     u.Teams,
+		TeamDto:   u.TeamDto,
+		MemberDto: u.MemberDto,
+		Query:     u.Query,
+	}
+
+	userSrv.On("FindOne", int32(5)).Return(nil, want)
+	c.On("Bind", &dto.MemberDto{}).Return(nil)
+	c.On("ID").Return(1, nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewTeamHandler(srv, userSrv, activitySrv, new(OwnerCheckerMock), new(PermissionCheckerMock), v)
+	h.AddMember(c)
+
+	assert.Equal(u.T(), want, c.V)
+	srv.AssertNumberOfCalls(u.T(), "AddMember", 0)
+}
+
+func (u *TeamHandlerTest) TestAddMemberInvalidBodyRequestTeam() {
+	srv := new(ServiceMock)
+	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	c := &ContextMock{
+		Team:      u.Team,
+		Teams:     u.Teams,
+		TeamDto:   u.TeamDto,
+		MemberDto: &dto.MemberDto{},
+		Query:     u.Query,
+	}
+
+	c.On("Bind", &dto.MemberDto{}).Return(nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewTeamHandler(srv, userSrv, activitySrv, new(OwnerCheckerMock), new(PermissionCheckerMock), v)
+	h.AddMember(c)
+
+	errRes, ok := c.V.(*dto.ResponseErr)
+
+	assert.True(u.T(), ok)
+	assert.Equal(u.T(), http.StatusBadRequest, errRes.StatusCode)
+	userSrv.AssertNumberOfCalls(u.T(), "FindOne", 0)
+}
+
+func (u *TeamHandlerTest) TestRemoveMemberTeam() {
+	want := u.Team
+
+	srv := new(ServiceMock)
+	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	c := &ContextMock{
+		Team:      u.Team,
+		Teams:     u.Teams,
+		TeamDto:   u.TeamDto,
+		MemberDto: u.MemberDto,
+		Query:     u.Query,
+	}
+
+	srv.On("RemoveMember", int32(1), int32(5)).Return(u.Team, nil)
+	c.On("ID").Return(1, nil)
+	c.On("MemberID").Return(5, nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewTeamHandler(srv, userSrv, activitySrv, new(OwnerCheckerMock), new(PermissionCheckerMock), v)
+	h.RemoveMember(c)
+
+	assert.Equal(u.T(), want, c.V)
+}
+
+func (u *TeamHandlerTest) TestRemoveMemberInvalidUserIDTeam() {
+	want := &dto.ResponseErr{
+		StatusCode: http.StatusBadRequest,
+		Message:    "Invalid user ID",
+	}
+
+	srv := new(ServiceMock)
+	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	c := &ContextMock{
+		Team:      u.Team,
+		Teams:     u.Teams,
+		TeamDto:   u.TeamDto,
+		MemberDto: u.MemberDto,
+		Query:     u.Query,
+	}
+
+	c.On("ID").Return(1, nil)
+	c.On("MemberID").Return(-1, errors.New("Invalid ID"))
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewTeamHandler(srv, userSrv, activitySrv, new(OwnerCheckerMock), new(PermissionCheckerMock), v)
+	h.RemoveMember(c)
+
+	assert.Equal(u.T(), want, c.V)
+}
+
+func (u *TeamHandlerTest) TestFindTreeTeam() {
+	want := &proto.Team{
+		Id:       u.Team.Id,
+		Name:     u.Team.Name,
+		SubTeams: []*proto.Team{u.Teams[1], u.Teams[2]},
+	}
+
+	srv := new(ServiceMock)
+	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	c := &ContextMock{
+		Team:      u.Team,
+		Teams:     u.Teams,
+		TeamDto:   u.TeamDto,
+		Query:     u.Query,
+		TreeQuery: &dto.TreeQueryParams{Depth: 2},
+	}
+
+	srv.On("FindTree", int32(1), 2).Return(want, nil)
+	c.On("ID").Return(1, nil)
+	c.On("TreeQueryParam", &dto.TreeQueryParams{}).Return(nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewTeamHandler(srv, userSrv, activitySrv, new(OwnerCheckerMock), new(PermissionCheckerMock), v)
+	h.FindTree(c)
+
+	assert.Equal(u.T(), want, c.V)
+}
+
+func (u *TeamHandlerTest) TestFindTreeDefaultDepthTeam() {
+	want := u.Team
+
+	srv := new(ServiceMock)
+	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	c := &ContextMock{
+		Team:      u.Team,
+		Teams:     u.Teams,
+		TeamDto:   u.TeamDto,
+		Query:     u.Query,
+		TreeQuery: &dto.TreeQueryParams{},
+	}
+
+	srv.On("FindTree", int32(1), constant.DefaultTeamTreeDepth).Return(want, nil)
+	c.On("ID").Return(1, nil)
+	c.On("TreeQueryParam", &dto.TreeQueryParams{}).Return(nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewTeamHandler(srv, userSrv, activitySrv, new(OwnerCheckerMock), new(PermissionCheckerMock), v)
+	h.FindTree(c)
+
+	assert.Equal(u.T(), want, c.V)
+}
+
+func (u *TeamHandlerTest) TestFindTreeExceedMaxDepthTeam() {
+	srv := new(ServiceMock)
+	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	c := &ContextMock{
+		Team:      u.Team,
+		Teams:     u.Teams,
+		TeamDto:   u.TeamDto,
+		Query:     u.Query,
+		TreeQuery: &dto.TreeQueryParams{Depth: constant.MaxTeamTreeDepth + 1},
+	}
+
+	c.On("ID").Return(1, nil)
+	c.On("TreeQueryParam", &dto.TreeQueryParams{}).Return(nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewTeamHandler(srv, userSrv, activitySrv, new(OwnerCheckerMock), new(PermissionCheckerMock), v)
+	h.FindTree(c)
+
+	errRes, ok := c.V.(*dto.ResponseErr)
+
+	assert.True(u.T(), ok)
+	assert.Equal(u.T(), http.StatusBadRequest, errRes.StatusCode)
+	srv.AssertNumberOfCalls(u.T(), "FindTree", 0)
+}
+
+func (u *TeamHandlerTest) TestUpdateCycleTeam() {
+	want := &dto.ResponseErr{
+		StatusCode: http.StatusUnprocessableEntity,
+		Message:    "A team cannot be its own ancestor",
+	}
+
+	teamDto := &dto.TeamDto{
+		Name:         u.Team.Name,
+		ParentTeamID: dto.NullableID{Set: true, ID: 1},
+	}
+
+	srv := new(ServiceMock)
+	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	c := &ContextMock{
+		Team:    u.Team,
+		Teams:   u.Teams,
+		TeamDto: teamDto,
+		Query:   u.Query,
+	}
+
+	srv.On("Update", int32(1), teamDto).Return(nil, want)
+	c.On("Bind", &dto.TeamDto{}).Return(nil)
+	c.On("ID").Return(1, nil)
+	c.On("Principal").Return(&dto.Principal{UserID: 1, Admin: true})
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewTeamHandler(srv, userSrv, activitySrv, new(OwnerCheckerMock), new(PermissionCheckerMock), v)
+	h.Update(c)
+
+	assert.Equal(u.T(), want, c.V)
+}
+
+func (u *TeamHandlerTest) TestCreateInvalidParentTeam() {
+	srv := new(ServiceMock)
+	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	c := &ContextMock{
+		Team:  u.Team,
+		Teams: u.Teams,
+		TeamDto: &dto.TeamDto{
+			Name:         u.Team.Name,
+			ParentTeamID: dto.NullableID{Set: true, ID: -1},
+		},
+		Query: u.Query,
+	}
+
+	c.On("Bind", &dto.TeamDto{}).Return(nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewTeamHandler(srv, userSrv, activitySrv, new(OwnerCheckerMock), new(PermissionCheckerMock), v)
+	h.Create(c)
+
+	errRes, ok := c.V.(*dto.ResponseErr)
+
+	assert.True(u.T(), ok)
+	assert.Equal(u.T(), http.StatusBadRequest, errRes.StatusCode)
+	srv.AssertNumberOfCalls(u.T(), "Create", 0)
+}
+
+func (u *TeamHandlerTest) TestFindActivityTeam() {
+	u.Team.Logs = []*proto.Log{
+		{
+			Title:     faker.Word(),
+			Type:      proto.LogType_EDIT,
+			User:      u.Member,
+			Timestamp: "2022-01-01T00:00:00Z",
+		},
+	}
+
+	query := &dto.ActivityQueryParams{
+		Limit: 10,
+		Page:  1,
+	}
+
+	want := &dto.LogPagination{
+		Items: u.Team.Logs,
+		Meta: &proto.PaginationMetadata{
+			TotalItem:    1,
+			ItemCount:    1,
+			ItemsPerPage: 10,
+			TotalPage:    1,
+			CurrentPage:  1,
+		},
+	}
+
+	srv := new(ServiceMock)
+	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	c := &ContextMock{
+		Team:          u.Team,
+		Teams:         u.Teams,
+		TeamDto:       u.TeamDto,
+		Query:         u.Query,
+		ActivityQuery: query,
+	}
+
+	srv.On("FindOne", int32(1)).Return(u.Team, nil)
+	activitySrv.On("Paginate", u.Team.Logs, query).Return(want, nil)
+	c.On("ID").Return(1, nil)
+	c.On("ActivityQueryParam", &dto.ActivityQueryParams{}).Return(nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewTeamHandler(srv, userSrv, activitySrv, new(OwnerCheckerMock), new(PermissionCheckerMock), v)
+	h.FindActivity(c)
+
+	assert.Equal(u.T(), want, c.V)
+}
+
+func (u *TeamHandlerTest) TestFindActivityNotFoundTeam() {
+	want := u.NotFoundErr
+
+	srv := new(ServiceMock)
+	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	c := &ContextMock{
+		Team:          u.Team,
+		Teams:         u.Teams,
+		TeamDto:       u.TeamDto,
+		Query:         u.Query,
+		ActivityQuery: &dto.ActivityQueryParams{},
+	}
+
+	srv.On("FindOne", int32(1)).Return(nil, u.NotFoundErr)
+	c.On("ID").Return(1, nil)
+	c.On("ActivityQueryParam", &dto.ActivityQueryParams{}).Return(nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewTeamHandler(srv, userSrv, activitySrv, new(OwnerCheckerMock), new(PermissionCheckerMock), v)
+	h.FindActivity(c)
+
+	assert.Equal(u.T(), want, c.V)
+}
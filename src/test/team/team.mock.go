@@ -10,17 +10,26 @@ import (
 
 type ContextMock struct {
 	mock.Mock
-	V       interface{}
-	Team    *proto.Team
-	Teams   []*proto.Team
-	TeamDto *dto.TeamDto
-	Query   *dto.PaginationQueryParams
+	V             interface{}
+	Team          *proto.Team
+	Teams         []*proto.Team
+	TeamDto       *dto.TeamDto
+	MemberDto     *dto.MemberDto
+	IDs           string
+	Query         *dto.PaginationQueryParams
+	TreeQuery     *dto.TreeQueryParams
+	ActivityQuery *dto.ActivityQueryParams
 }
 
 func (c *ContextMock) Bind(v interface{}) error {
 	args := c.Called(v)
 
-	*v.(*dto.TeamDto) = *c.TeamDto
+	switch v.(type) {
+	case *dto.TeamDto:
+		*v.(*dto.TeamDto) = *c.TeamDto
+	case *dto.MemberDto:
+		*v.(*dto.MemberDto) = *c.MemberDto
+	}
 
 	return args.Error(0)
 }
@@ -35,6 +44,12 @@ func (c *ContextMock) ID() (int32, error) {
 	return int32(args.Int(0)), args.Error(1)
 }
 
+func (c *ContextMock) Principal() *dto.Principal {
+	args := c.Called()
+
+	return args.Get(0).(*dto.Principal)
+}
+
 func (c *ContextMock) PaginationQueryParam(query *dto.PaginationQueryParams) error {
 	args := c.Called(query)
 
@@ -43,11 +58,37 @@ func (c *ContextMock) PaginationQueryParam(query *dto.PaginationQueryParams) err
 	return args.Error(0)
 }
 
+func (c *ContextMock) ActivityQueryParam(query *dto.ActivityQueryParams) error {
+	args := c.Called(query)
+
+	*query = *c.ActivityQuery
+
+	return args.Error(0)
+}
+
+func (c *ContextMock) IDsQueryParam() string {
+	return c.IDs
+}
+
+func (c *ContextMock) MemberID() (int32, error) {
+	args := c.Called()
+
+	return int32(args.Int(0)), args.Error(1)
+}
+
+func (c *ContextMock) TreeQueryParam(query *dto.TreeQueryParams) error {
+	args := c.Called(query)
+
+	*query = *c.TreeQuery
+
+	return args.Error(0)
+}
+
 type ServiceMock struct {
 	mock.Mock
 }
 
-func (s *ServiceMock) FindAll(query *dto.PaginationQueryParams) (res *proto.TeamPagination, err *dto.ResponseErr) {
+func (s *ServiceMock) FindAll(_ context.Context, query *dto.PaginationQueryParams) (res *proto.TeamPagination, err *dto.ResponseErr) {
 	args := s.Called(query)
 
 	if args.Get(0) != nil {
@@ -61,7 +102,7 @@ func (s *ServiceMock) FindAll(query *dto.PaginationQueryParams) (res *proto.Team
 	return
 }
 
-func (s *ServiceMock) FindOne(id int32) (res *proto.Team, err *dto.ResponseErr) {
+func (s *ServiceMock) FindOne(_ context.Context, id int32) (res *proto.Team, err *dto.ResponseErr) {
 	args := s.Called(id)
 
 	if args.Get(0) != nil {
@@ -75,7 +116,21 @@ func (s *ServiceMock) FindOne(id int32) (res *proto.Team, err *dto.ResponseErr)
 	return
 }
 
-func (s *ServiceMock) Create(team *dto.TeamDto) (res *proto.Team, err *dto.ResponseErr) {
+func (s *ServiceMock) FindMulti(_ context.Context, ids []int32) (res []*proto.Team, err *dto.ResponseErr) {
+	args := s.Called(ids)
+
+	if args.Get(0) != nil {
+		res = args.Get(0).([]*proto.Team)
+	}
+
+	if args.Get(1) != nil {
+		err = args.Get(1).(*dto.ResponseErr)
+	}
+
+	return
+}
+
+func (s *ServiceMock) Create(_ context.Context, team *dto.TeamDto) (res *proto.Team, err *dto.ResponseErr) {
 	args := s.Called(team)
 
 	if args.Get(0) != nil {
@@ -89,7 +144,7 @@ func (s *ServiceMock) Create(team *dto.TeamDto) (res *proto.Team, err *dto.Respo
 	return
 }
 
-func (s *ServiceMock) Update(id int32, team *dto.TeamDto) (res *proto.Team, err *dto.ResponseErr) {
+func (s *ServiceMock) Update(_ context.Context, id int32, team *dto.TeamDto) (res *proto.Team, err *dto.ResponseErr) {
 	args := s.Called(id, team)
 
 	if args.Get(0) != nil {
@@ -103,7 +158,7 @@ func (s *ServiceMock) Update(id int32, team *dto.TeamDto) (res *proto.Team, err
 	return
 }
 
-func (s *ServiceMock) Delete(id int32) (res *proto.Team, err *dto.ResponseErr) {
+func (s *ServiceMock) Delete(_ context.Context, id int32) (res *proto.Team, err *dto.ResponseErr) {
 	args := s.Called(id)
 
 	if args.Get(0) != nil {
@@ -117,6 +172,62 @@ func (s *ServiceMock) Delete(id int32) (res *proto.Team, err *dto.ResponseErr) {
 	return
 }
 
+func (s *ServiceMock) FindMembers(_ context.Context, id int32, query *dto.PaginationQueryParams) (res *proto.UserPagination, err *dto.ResponseErr) {
+	args := s.Called(id, query)
+
+	if args.Get(0) != nil {
+		res = args.Get(0).(*proto.UserPagination)
+	}
+
+	if args.Get(1) != nil {
+		err = args.Get(1).(*dto.ResponseErr)
+	}
+
+	return
+}
+
+func (s *ServiceMock) AddMember(_ context.Context, id int32, user *proto.User) (res *proto.Team, err *dto.ResponseErr) {
+	args := s.Called(id, user)
+
+	if args.Get(0) != nil {
+		res = args.Get(0).(*proto.Team)
+	}
+
+	if args.Get(1) != nil {
+		err = args.Get(1).(*dto.ResponseErr)
+	}
+
+	return
+}
+
+func (s *ServiceMock) RemoveMember(_ context.Context, id int32, userId int32) (res *proto.Team, err *dto.ResponseErr) {
+	args := s.Called(id, userId)
+
+	if args.Get(0) != nil {
+		res = args.Get(0).(*proto.Team)
+	}
+
+	if args.Get(1) != nil {
+		err = args.Get(1).(*dto.ResponseErr)
+	}
+
+	return
+}
+
+func (s *ServiceMock) FindTree(_ context.Context, id int32, depth int) (res *proto.Team, err *dto.ResponseErr) {
+	args := s.Called(id, depth)
+
+	if args.Get(0) != nil {
+		res = args.Get(0).(*proto.Team)
+	}
+
+	if args.Get(1) != nil {
+		err = args.Get(1).(*dto.ResponseErr)
+	}
+
+	return
+}
+
 type ClientMock struct {
 	mock.Mock
 }
@@ -141,8 +252,14 @@ func (c *ClientMock) FindOne(ctx context.Context, in *proto.FindOneTeamRequest,
 	return res, args.Error(1)
 }
 
-func (c *ClientMock) FindMulti(ctx context.Context, in *proto.FindMultiTeamRequest, opts ...grpc.CallOption) (*proto.TeamListResponse, error) {
-	return nil, nil
+func (c *ClientMock) FindMulti(ctx context.Context, in *proto.FindMultiTeamRequest, opts ...grpc.CallOption) (res *proto.TeamListResponse, err error) {
+	args := c.Called(in)
+
+	if args.Get(0) != nil {
+		res = args.Get(0).(*proto.TeamListResponse)
+	}
+
+	return res, args.Error(1)
 }
 
 func (c *ClientMock) Create(ctx context.Context, in *proto.CreateTeamRequest, opts ...grpc.CallOption) (res *proto.TeamResponse, err error) {
@@ -174,3 +291,35 @@ func (c *ClientMock) Delete(ctx context.Context, in *proto.DeleteTeamRequest, op
 
 	return res, args.Error(1)
 }
+
+func (c *ContextMock) UserContext() context.Context {
+	return context.Background()
+}
+
+type OwnerCheckerMock struct {
+	mock.Mock
+}
+
+func (s *OwnerCheckerMock) IsOwner(_ context.Context, resource string, id int32, userId int32) (owner bool, err *dto.ResponseErr) {
+	args := s.Called(resource, id, userId)
+
+	if args.Get(1) != nil {
+		err = args.Get(1).(*dto.ResponseErr)
+	}
+
+	return args.Bool(0), err
+}
+
+type PermissionCheckerMock struct {
+	mock.Mock
+}
+
+func (s *PermissionCheckerMock) HasPermission(_ context.Context, userId int32, code string) (ok bool, err *dto.ResponseErr) {
+	args := s.Called(userId, code)
+
+	if args.Get(1) != nil {
+		err = args.Get(1).(*dto.ResponseErr)
+	}
+
+	return args.Bool(0), err
+}
@@ -1,8 +1,10 @@
 package team
 
 import (
+	"context"
 	"github.com/bxcodec/faker/v3"
 	"github.com/pkg/errors"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/constant"
 	"github.com/samithiwat/samithiwat-backend-gateway/src/dto"
 	"github.com/samithiwat/samithiwat-backend-gateway/src/proto"
 	"github.com/samithiwat/samithiwat-backend-gateway/src/service"
@@ -104,7 +106,7 @@ func (s *TeamServiceTest) TestFindAllTeamService() {
 
 	srv := service.NewTeamService(client)
 
-	teams, err := srv.FindAll(s.Query)
+	teams, err := srv.FindAll(context.Background(), s.Query)
 
 	assert.Nil(s.T(), err, "Must not got any error")
 	assert.Equal(s.T(), want, teams)
@@ -122,7 +124,7 @@ func (s *TeamServiceTest) TestFindAllGrpcErrTeamService() {
 
 	srv := service.NewTeamService(client)
 
-	_, err := srv.FindAll(s.Query)
+	_, err := srv.FindAll(context.Background(), s.Query)
 
 	assert.Equal(s.T(), want, err)
 }
@@ -143,7 +145,7 @@ func (s *TeamServiceTest) TestFindOneTeamService() {
 
 	srv := service.NewTeamService(client)
 
-	team, err := srv.FindOne(id)
+	team, err := srv.FindOne(context.Background(), id)
 
 	assert.Nil(s.T(), err, "Must not got any error")
 	assert.Equal(s.T(), want, team)
@@ -165,7 +167,7 @@ func (s *TeamServiceTest) TestFindOneNotFoundTeamService() {
 
 	srv := service.NewTeamService(client)
 
-	team, err := srv.FindOne(id)
+	team, err := srv.FindOne(context.Background(), id)
 
 	assert.Nil(s.T(), team)
 	assert.Equal(s.T(), want, err)
@@ -183,7 +185,7 @@ func (s *TeamServiceTest) TestFindOneGrpcErrTeamService() {
 
 	srv := service.NewTeamService(client)
 
-	_, err := srv.FindOne(id)
+	_, err := srv.FindOne(context.Background(), id)
 
 	assert.Equal(s.T(), want, err)
 }
@@ -201,7 +203,7 @@ func (s *TeamServiceTest) TestCreateTeamService() {
 
 	srv := service.NewTeamService(client)
 
-	team, err := srv.Create(s.TeamDto)
+	team, err := srv.Create(context.Background(), s.TeamDto)
 
 	assert.Nil(s.T(), err, "Must not got any error")
 	assert.Equal(s.T(), want, team)
@@ -224,7 +226,7 @@ func (s *TeamServiceTest) TestCreateDuplicatedTeamService() {
 
 	srv := service.NewTeamService(client)
 
-	team, err := srv.Create(s.TeamDto)
+	team, err := srv.Create(context.Background(), s.TeamDto)
 
 	assert.Nil(s.T(), team)
 	assert.Equal(s.T(), want, err)
@@ -239,7 +241,7 @@ func (s *TeamServiceTest) TestCreateGrpcErrTeamService() {
 
 	srv := service.NewTeamService(client)
 
-	_, err := srv.Create(s.TeamDto)
+	_, err := srv.Create(context.Background(), s.TeamDto)
 
 	assert.Equal(s.T(), want, err)
 }
@@ -257,7 +259,7 @@ func (s *TeamServiceTest) TestUpdateTeamService() {
 
 	srv := service.NewTeamService(client)
 
-	team, err := srv.Update(1, s.TeamDto)
+	team, err := srv.Update(context.Background(), 1, s.TeamDto)
 
 	assert.Nil(s.T(), err, "Must not got any error")
 	assert.Equal(s.T(), want, team)
@@ -276,7 +278,7 @@ func (s *TeamServiceTest) TestUpdateNotFoundTeamService() {
 
 	srv := service.NewTeamService(client)
 
-	team, err := srv.Update(1, s.TeamDto)
+	team, err := srv.Update(context.Background(), 1, s.TeamDto)
 
 	assert.Nil(s.T(), team)
 	assert.Equal(s.T(), want, err)
@@ -291,7 +293,7 @@ func (s *TeamServiceTest) TestUpdateGrpcErrTeamService() {
 
 	srv := service.NewTeamService(client)
 
-	_, err := srv.Update(1, s.TeamDto)
+	_, err := srv.Update(context.Background(), 1, s.TeamDto)
 
 	assert.Equal(s.T(), want, err)
 }
@@ -312,7 +314,7 @@ func (s *TeamServiceTest) TestDeleteTeamService() {
 
 	srv := service.NewTeamService(client)
 
-	team, err := srv.Delete(id)
+	team, err := srv.Delete(context.Background(), id)
 
 	assert.Nil(s.T(), err, "Must not got any error")
 	assert.Equal(s.T(), want, team)
@@ -334,7 +336,7 @@ func (s *TeamServiceTest) TestDeleteNotFoundTeamService() {
 
 	srv := service.NewTeamService(client)
 
-	team, err := srv.Delete(id)
+	team, err := srv.Delete(context.Background(), id)
 
 	assert.Nil(s.T(), team)
 	assert.Equal(s.T(), want, err)
@@ -352,7 +354,654 @@ func (s *TeamServiceTest) TestDeleteGrpcErrTeamService() {
 
 	srv := service.NewTeamService(client)
 
-	_, err := srv.Delete(id)
+	_, err := srv.Delete(context.Background(), id)
 
 	assert.Equal(s.T(), want, err)
 }
+
+func (s *TeamServiceTest) TestFindMultiTeamService() {
+	want := []*proto.Team{s.Teams[2], s.Teams[0], s.Teams[1]}
+
+	client := new(ClientMock)
+
+	client.On("FindMulti", &proto.FindMultiTeamRequest{Ids: []uint32{3, 1, 2}}).Return(&proto.TeamListResponse{
+		StatusCode: http.StatusOK,
+		Errors:     nil,
+		Data:       []*proto.Team{s.Teams[0], s.Teams[1], s.Teams[2]},
+	}, nil)
+
+	srv := service.NewTeamService(client)
+
+	teams, err := srv.FindMulti(context.Background(), []int32{3, 1, 2})
+
+	assert.Nil(s.T(), err, "Must not got any error")
+	assert.Equal(s.T(), want, teams)
+}
+
+func (s *TeamServiceTest) TestFindMultiSomeNotFoundTeamService() {
+	want := []*proto.Team{s.Teams[1]}
+
+	client := new(ClientMock)
+
+	client.On("FindMulti", &proto.FindMultiTeamRequest{Ids: []uint32{10, 2}}).Return(&proto.TeamListResponse{
+		StatusCode: http.StatusOK,
+		Errors:     nil,
+		Data:       []*proto.Team{s.Teams[1]},
+	}, nil)
+
+	srv := service.NewTeamService(client)
+
+	teams, err := srv.FindMulti(context.Background(), []int32{10, 2})
+
+	assert.Nil(s.T(), err, "Must not got any error")
+	assert.Equal(s.T(), want, teams)
+}
+
+func (s *TeamServiceTest) TestFindMultiGrpcErrTeamService() {
+	want := s.ServiceDownErr
+
+	client := new(ClientMock)
+
+	client.On("FindMulti", &proto.FindMultiTeamRequest{Ids: []uint32{1, 2}}).Return(nil, errors.New("Service is down"))
+
+	srv := service.NewTeamService(client)
+
+	_, err := srv.FindMulti(context.Background(), []int32{1, 2})
+
+	assert.Equal(s.T(), want, err)
+}
+
+func (s *TeamServiceTest) TestFindMembersTeamService() {
+	members := []*proto.User{{Id: 1}, {Id: 2}, {Id: 3}}
+
+	want := &proto.UserPagination{
+		Items: members[2:],
+		Meta: &proto.PaginationMetadata{
+			TotalItem:    3,
+			ItemCount:    1,
+			ItemsPerPage: 2,
+			TotalPage:    2,
+			CurrentPage:  2,
+		},
+	}
+
+	client := new(ClientMock)
+
+	client.On("FindOne", &proto.FindOneTeamRequest{Id: 1}).Return(&proto.TeamResponse{
+		StatusCode: http.StatusOK,
+		Errors:     nil,
+		Data: &proto.Team{
+			Id:      s.Team.Id,
+			Name:    s.Team.Name,
+			Members: members,
+		},
+	}, nil)
+
+	srv := service.NewTeamService(client)
+
+	result, err := srv.FindMembers(context.Background(), 1, &dto.PaginationQueryParams{Limit: 2, Page: 2})
+
+	assert.Nil(s.T(), err, "Must not got any error")
+	assert.Equal(s.T(), want, result)
+}
+
+func (s *TeamServiceTest) TestFindMembersNotFoundTeamService() {
+	want := s.NotFoundErr
+
+	client := new(ClientMock)
+
+	client.On("FindOne", &proto.FindOneTeamRequest{Id: 1}).Return(&proto.TeamResponse{
+		StatusCode: http.StatusNotFound,
+		Errors:     []string{"Not found team"},
+		Data:       nil,
+	}, nil)
+
+	srv := service.NewTeamService(client)
+
+	result, err := srv.FindMembers(context.Background(), 1, &dto.PaginationQueryParams{})
+
+	assert.Nil(s.T(), result)
+	assert.Equal(s.T(), want, err)
+}
+
+func (s *TeamServiceTest) TestAddMemberTeamService() {
+	member := &proto.User{
+		Id:        5,
+		Firstname: faker.FirstName(),
+		Lastname:  faker.LastName(),
+	}
+
+	want := &proto.Team{
+		Id:          s.Team.Id,
+		Name:        s.Team.Name,
+		Description: s.Team.Description,
+		Members:     []*proto.User{member},
+	}
+
+	client := new(ClientMock)
+
+	client.On("FindOne", &proto.FindOneTeamRequest{Id: 1}).Return(&proto.TeamResponse{
+		StatusCode: http.StatusOK,
+		Errors:     nil,
+		Data: &proto.Team{
+			Id:          s.Team.Id,
+			Name:        s.Team.Name,
+			Description: s.Team.Description,
+		},
+	}, nil)
+	client.On("Update", want).Return(&proto.TeamResponse{
+		StatusCode: http.StatusOK,
+		Errors:     nil,
+		Data:       want,
+	}, nil)
+
+	srv := service.NewTeamService(client)
+
+	team, err := srv.AddMember(context.Background(), 1, member)
+
+	assert.Nil(s.T(), err, "Must not got any error")
+	assert.Equal(s.T(), want, team)
+}
+
+func (s *TeamServiceTest) TestAddExistedMemberTeamService() {
+	member := &proto.User{Id: 5}
+
+	want := &proto.Team{
+		Id:      s.Team.Id,
+		Name:    s.Team.Name,
+		Members: []*proto.User{member},
+	}
+
+	client := new(ClientMock)
+
+	client.On("FindOne", &proto.FindOneTeamRequest{Id: 1}).Return(&proto.TeamResponse{
+		StatusCode: http.StatusOK,
+		Errors:     nil,
+		Data:       want,
+	}, nil)
+
+	srv := service.NewTeamService(client)
+
+	team, err := srv.AddMember(context.Background(), 1, member)
+
+	assert.Nil(s.T(), err, "Must not got any error")
+	assert.Equal(s.T(), want, team)
+	client.AssertNumberOfCalls(s.T(), "Update", 0)
+}
+
+func (s *TeamServiceTest) TestRemoveMemberTeamService() {
+	want := &proto.Team{
+		Id:      s.Team.Id,
+		Name:    s.Team.Name,
+		Members: []*proto.User{{Id: 2}},
+	}
+
+	client := new(ClientMock)
+
+	client.On("FindOne", &proto.FindOneTeamRequest{Id: 1}).Return(&proto.TeamResponse{
+		StatusCode: http.StatusOK,
+		Errors:     nil,
+		Data: &proto.Team{
+			Id:      s.Team.Id,
+			Name:    s.Team.Name,
+			Members: []*proto.User{{Id: 2}, {Id: 5}},
+		},
+	}, nil)
+	client.On("Update", want).Return(&proto.TeamResponse{
+		StatusCode: http.StatusOK,
+		Errors:     nil,
+		Data:       want,
+	}, nil)
+
+	srv := service.NewTeamService(client)
+
+	team, err := srv.RemoveMember(context.Background(), 1, 5)
+
+	assert.Nil(s.T(), err, "Must not got any error")
+	assert.Equal(s.T(), want, team)
+}
+
+func (s *TeamServiceTest) TestRemoveNotMemberTeamService() {
+	want := &dto.ResponseErr{
+		StatusCode: http.StatusNotFound,
+		Message:    "The team does not have this member",
+		Data:       nil,
+	}
+
+	client := new(ClientMock)
+
+	client.On("FindOne", &proto.FindOneTeamRequest{Id: 1}).Return(&proto.TeamResponse{
+		StatusCode: http.StatusOK,
+		Errors:     nil,
+		Data: &proto.Team{
+			Id:      s.Team.Id,
+			Members: []*proto.User{{Id: 2}},
+		},
+	}, nil)
+
+	srv := service.NewTeamService(client)
+
+	team, err := srv.RemoveMember(context.Background(), 1, 5)
+
+	assert.Nil(s.T(), team)
+	assert.Equal(s.T(), want, err)
+}
+
+func (s *TeamServiceTest) TestFindTreeTeamService() {
+	want := &proto.Team{
+		Id:   1,
+		Name: s.Team.Name,
+		SubTeams: []*proto.Team{
+			{
+				Id:       2,
+				SubTeams: []*proto.Team{{Id: 4}},
+			},
+			{Id: 3},
+		},
+	}
+
+	client := new(ClientMock)
+
+	client.On("FindOne", &proto.FindOneTeamRequest{Id: 1}).Return(&proto.TeamResponse{
+		StatusCode: http.StatusOK,
+		Errors:     nil,
+		Data: &proto.Team{
+			Id:       1,
+			Name:     s.Team.Name,
+			SubTeams: []*proto.Team{{Id: 2}, {Id: 3}},
+		},
+	}, nil)
+	client.On("FindMulti", &proto.FindMultiTeamRequest{Ids: []uint32{2, 3}}).Return(&proto.TeamListResponse{
+		StatusCode: http.StatusOK,
+		Errors:     nil,
+		Data: []*proto.Team{
+			{Id: 3},
+			{Id: 2, SubTeams: []*proto.Team{{Id: 4}}},
+		},
+	}, nil)
+	client.On("FindMulti", &proto.FindMultiTeamRequest{Ids: []uint32{4}}).Return(&proto.TeamListResponse{
+		StatusCode: http.StatusOK,
+		Errors:     nil,
+		Data: []*proto.Team{
+			{Id: 4, SubTeams: []*proto.Team{{Id: 5}}},
+		},
+	}, nil)
+
+	srv := service.NewTeamService(client)
+
+	team, err := srv.FindTree(context.Background(), 1, 2)
+
+	assert.Nil(s.T(), err, "Must not got any error")
+	assert.Equal(s.T(), want, team)
+}
+
+func (s *TeamServiceTest) TestFindTreeStopOnCycleTeamService() {
+	want := &proto.Team{
+		Id: 1,
+		SubTeams: []*proto.Team{
+			{Id: 2},
+		},
+	}
+
+	client := new(ClientMock)
+
+	client.On("FindOne", &proto.FindOneTeamRequest{Id: 1}).Return(&proto.TeamResponse{
+		StatusCode: http.StatusOK,
+		Errors:     nil,
+		Data:       &proto.Team{Id: 1, SubTeams: []*proto.Team{{Id: 2}}},
+	}, nil)
+	client.On("FindMulti", &proto.FindMultiTeamRequest{Ids: []uint32{2}}).Return(&proto.TeamListResponse{
+		StatusCode: http.StatusOK,
+		Errors:     nil,
+		Data:       []*proto.Team{{Id: 2, SubTeams: []*proto.Team{{Id: 1}}}},
+	}, nil)
+
+	srv := service.NewTeamService(client)
+
+	team, err := srv.FindTree(context.Background(), 1, -1)
+
+	assert.Nil(s.T(), err, "Must not got any error")
+	assert.Equal(s.T(), want, team)
+	client.AssertNumberOfCalls(s.T(), "FindMulti", 1)
+}
+
+func (s *TeamServiceTest) TestUpdateSelfParentTeamService() {
+	want := &dto.ResponseErr{
+		StatusCode: http.StatusUnprocessableEntity,
+		Message:    "A team cannot be its own ancestor",
+		Data:       nil,
+	}
+
+	client := new(ClientMock)
+
+	srv := service.NewTeamService(client)
+
+	team, err := srv.Update(context.Background(), 1, &dto.TeamDto{Name: s.Team.Name, ParentTeamID: dto.NullableID{Set: true, ID: 1}})
+
+	assert.Nil(s.T(), team)
+	assert.Equal(s.T(), want, err)
+	client.AssertNumberOfCalls(s.T(), "Update", 0)
+}
+
+func (s *TeamServiceTest) TestUpdateDescendantParentTeamService() {
+	want := &dto.ResponseErr{
+		StatusCode: http.StatusUnprocessableEntity,
+		Message:    "A team cannot be its own ancestor",
+		Data:       nil,
+	}
+
+	client := new(ClientMock)
+
+	client.On("FindOne", &proto.FindOneTeamRequest{Id: 1}).Return(&proto.TeamResponse{
+		StatusCode: http.StatusOK,
+		Errors:     nil,
+		Data:       &proto.Team{Id: 1, SubTeams: []*proto.Team{{Id: 2}}},
+	}, nil)
+	client.On("FindMulti", &proto.FindMultiTeamRequest{Ids: []uint32{2}}).Return(&proto.TeamListResponse{
+		StatusCode: http.StatusOK,
+		Errors:     nil,
+		Data:       []*proto.Team{{Id: 2, SubTeams: []*proto.Team{{Id: 3}}}},
+	}, nil)
+	client.On("FindMulti", &proto.FindMultiTeamRequest{Ids: []uint32{3}}).Return(&proto.TeamListResponse{
+		StatusCode: http.StatusOK,
+		Errors:     nil,
+		Data:       []*proto.Team{{Id: 3}},
+	}, nil)
+
+	srv := service.NewTeamService(client)
+
+	team, err := srv.Update(context.Background(), 1, &dto.TeamDto{Name: s.Team.Name, ParentTeamID: dto.NullableID{Set: true, ID: 3}})
+
+	assert.Nil(s.T(), team)
+	assert.Equal(s.T(), want, err)
+	client.AssertNumberOfCalls(s.T(), "Update", 0)
+}
+
+func (s *TeamServiceTest) TestUpdateMoveParentTeamService() {
+	want := &proto.Team{
+		Id:          s.Team.Id,
+		Name:        s.Team.Name,
+		Description: s.Team.Description,
+	}
+
+	oldParent := &proto.Team{Id: 2, SubTeams: []*proto.Team{{Id: 1}, {Id: 4}}}
+	newParent := &proto.Team{Id: 3}
+
+	client := new(ClientMock)
+
+	client.On("FindOne", &proto.FindOneTeamRequest{Id: 1}).Return(&proto.TeamResponse{
+		StatusCode: http.StatusOK,
+		Errors:     nil,
+		Data:       &proto.Team{Id: 1},
+	}, nil)
+	client.On("FindOne", &proto.FindOneTeamRequest{Id: 3}).Return(&proto.TeamResponse{
+		StatusCode: http.StatusOK,
+		Errors:     nil,
+		Data:       newParent,
+	}, nil)
+	client.On("FindAll", &proto.FindAllTeamRequest{
+		Limit: constant.FetchAllLimit,
+		Page:  1,
+	}).Return(&proto.TeamPaginationResponse{
+		StatusCode: http.StatusOK,
+		Errors:     nil,
+		Data: &proto.TeamPagination{
+			Items: []*proto.Team{oldParent, newParent},
+			Meta:  &proto.PaginationMetadata{TotalPage: 1, CurrentPage: 1},
+		},
+	}, nil)
+	client.On("Update", s.Team).Return(&proto.TeamResponse{
+		StatusCode: http.StatusOK,
+		Errors:     nil,
+		Data:       want,
+	}, nil)
+	client.On("Update", &proto.Team{Id: 2, SubTeams: []*proto.Team{{Id: 4}}}).Return(&proto.TeamResponse{
+		StatusCode: http.StatusOK,
+		Errors:     nil,
+		Data:       oldParent,
+	}, nil)
+	client.On("Update", &proto.Team{Id: 3, SubTeams: []*proto.Team{want}}).Return(&proto.TeamResponse{
+		StatusCode: http.StatusOK,
+		Errors:     nil,
+		Data:       newParent,
+	}, nil)
+
+	srv := service.NewTeamService(client)
+
+	team, err := srv.Update(context.Background(), 1, &dto.TeamDto{
+		Name:         s.Team.Name,
+		Description:  s.Team.Description,
+		ParentTeamID: dto.NullableID{Set: true, ID: 3},
+	})
+
+	assert.Nil(s.T(), err, "Must not got any error")
+	assert.Equal(s.T(), want, team)
+	client.AssertNumberOfCalls(s.T(), "Update", 3)
+}
+
+func (s *TeamServiceTest) TestUpdateDetachParentTeamService() {
+	want := &proto.Team{
+		Id:          s.Team.Id,
+		Name:        s.Team.Name,
+		Description: s.Team.Description,
+	}
+
+	client := new(ClientMock)
+
+	client.On("FindAll", &proto.FindAllTeamRequest{
+		Limit: constant.FetchAllLimit,
+		Page:  1,
+	}).Return(&proto.TeamPaginationResponse{
+		StatusCode: http.StatusOK,
+		Errors:     nil,
+		Data: &proto.TeamPagination{
+			Items: []*proto.Team{{Id: 2, SubTeams: []*proto.Team{{Id: 1}, {Id: 4}}}},
+			Meta:  &proto.PaginationMetadata{TotalPage: 1, CurrentPage: 1},
+		},
+	}, nil)
+	client.On("Update", s.Team).Return(&proto.TeamResponse{
+		StatusCode: http.StatusOK,
+		Errors:     nil,
+		Data:       want,
+	}, nil)
+	client.On("Update", &proto.Team{Id: 2, SubTeams: []*proto.Team{{Id: 4}}}).Return(&proto.TeamResponse{
+		StatusCode: http.StatusOK,
+		Errors:     nil,
+		Data:       &proto.Team{Id: 2, SubTeams: []*proto.Team{{Id: 4}}},
+	}, nil)
+
+	srv := service.NewTeamService(client)
+
+	team, err := srv.Update(context.Background(), 1, &dto.TeamDto{
+		Name:         s.Team.Name,
+		Description:  s.Team.Description,
+		ParentTeamID: dto.NullableID{Set: true},
+	})
+
+	assert.Nil(s.T(), err, "Must not got any error")
+	assert.Equal(s.T(), want, team)
+	client.AssertNumberOfCalls(s.T(), "Update", 2)
+	client.AssertNumberOfCalls(s.T(), "FindOne", 0)
+}
+
+func (s *TeamServiceTest) TestUpdateMoveParentFailedTeamService() {
+	want := &dto.ResponseErr{
+		StatusCode: http.StatusServiceUnavailable,
+		Message:    "Service is down",
+		Data:       nil,
+	}
+
+	updated := &proto.Team{
+		Id:          s.Team.Id,
+		Name:        s.Team.Name,
+		Description: s.Team.Description,
+	}
+
+	client := new(ClientMock)
+
+	client.On("FindOne", &proto.FindOneTeamRequest{Id: 1}).Return(&proto.TeamResponse{
+		StatusCode: http.StatusOK,
+		Errors:     nil,
+		Data:       &proto.Team{Id: 1},
+	}, nil)
+	client.On("FindOne", &proto.FindOneTeamRequest{Id: 3}).Return(&proto.TeamResponse{
+		StatusCode: http.StatusOK,
+		Errors:     nil,
+		Data:       &proto.Team{Id: 3},
+	}, nil)
+	client.On("FindAll", &proto.FindAllTeamRequest{
+		Limit: constant.FetchAllLimit,
+		Page:  1,
+	}).Return(&proto.TeamPaginationResponse{
+		StatusCode: http.StatusOK,
+		Errors:     nil,
+		Data: &proto.TeamPagination{
+			Items: []*proto.Team{{Id: 2, SubTeams: []*proto.Team{{Id: 1}, {Id: 4}}}},
+			Meta:  &proto.PaginationMetadata{TotalPage: 1, CurrentPage: 1},
+		},
+	}, nil)
+	client.On("Update", s.Team).Return(&proto.TeamResponse{
+		StatusCode: http.StatusOK,
+		Errors:     nil,
+		Data:       updated,
+	}, nil)
+	client.On("Update", &proto.Team{Id: 2, SubTeams: []*proto.Team{{Id: 4}}}).Return(&proto.TeamResponse{
+		StatusCode: http.StatusOK,
+		Errors:     nil,
+		Data:       &proto.Team{Id: 2},
+	}, nil)
+	client.On("Update", &proto.Team{Id: 3, SubTeams: []*proto.Team{updated}}).Return(nil, errors.New("Service is down"))
+	client.On("Update", &proto.Team{Id: 2, SubTeams: []*proto.Team{{Id: 1}, {Id: 4}}}).Return(&proto.TeamResponse{
+		StatusCode: http.StatusOK,
+		Errors:     nil,
+		Data:       &proto.Team{Id: 2},
+	}, nil)
+
+	srv := service.NewTeamService(client)
+
+	team, err := srv.Update(context.Background(), 1, &dto.TeamDto{
+		Name:         s.Team.Name,
+		Description:  s.Team.Description,
+		ParentTeamID: dto.NullableID{Set: true, ID: 3},
+	})
+
+	assert.Nil(s.T(), team)
+	assert.Equal(s.T(), want, err)
+	client.AssertNumberOfCalls(s.T(), "Update", 4)
+	client.AssertCalled(s.T(), "Update", &proto.Team{Id: 2, SubTeams: []*proto.Team{{Id: 1}, {Id: 4}}})
+}
+
+func (s *TeamServiceTest) TestCreateWithParentTeamService() {
+	want := s.Team
+
+	client := new(ClientMock)
+
+	client.On("FindOne", &proto.FindOneTeamRequest{Id: 2}).Return(&proto.TeamResponse{
+		StatusCode: http.StatusOK,
+		Errors:     nil,
+		Data:       &proto.Team{Id: 2},
+	}, nil)
+	client.On("Create", &proto.Team{
+		Name:         s.Team.Name,
+		Description:  s.Team.Description,
+		Organization: &proto.Organization{Id: 5},
+	}).Return(&proto.TeamResponse{
+		StatusCode: http.StatusCreated,
+		Errors:     nil,
+		Data:       s.Team,
+	}, nil)
+	client.On("Update", &proto.Team{Id: 2, SubTeams: []*proto.Team{s.Team}}).Return(&proto.TeamResponse{
+		StatusCode: http.StatusOK,
+		Errors:     nil,
+		Data:       &proto.Team{Id: 2, SubTeams: []*proto.Team{s.Team}},
+	}, nil)
+
+	srv := service.NewTeamService(client)
+
+	team, err := srv.Create(context.Background(), &dto.TeamDto{
+		Name:           s.Team.Name,
+		Description:    s.Team.Description,
+		OrganizationID: 5,
+		ParentTeamID:   dto.NullableID{Set: true, ID: 2},
+	})
+
+	assert.Nil(s.T(), err, "Must not got any error")
+	assert.Equal(s.T(), want, team)
+}
+
+func (s *TeamServiceTest) TestCreateAttachParentFailedTeamService() {
+	want := s.ServiceDownErr
+
+	client := new(ClientMock)
+
+	client.On("FindOne", &proto.FindOneTeamRequest{Id: 2}).Return(&proto.TeamResponse{
+		StatusCode: http.StatusOK,
+		Errors:     nil,
+		Data:       &proto.Team{Id: 2},
+	}, nil)
+	client.On("Create", &proto.Team{Name: s.Team.Name}).Return(&proto.TeamResponse{
+		StatusCode: http.StatusCreated,
+		Errors:     nil,
+		Data:       s.Team,
+	}, nil)
+	client.On("Update", &proto.Team{Id: 2, SubTeams: []*proto.Team{s.Team}}).Return(nil, errors.New("Service is down"))
+	client.On("Delete", &proto.DeleteTeamRequest{Id: int32(s.Team.Id)}).Return(&proto.TeamResponse{
+		StatusCode: http.StatusOK,
+		Errors:     nil,
+		Data:       s.Team,
+	}, nil)
+
+	srv := service.NewTeamService(client)
+
+	team, err := srv.Create(context.Background(), &dto.TeamDto{Name: s.Team.Name, ParentTeamID: dto.NullableID{Set: true, ID: 2}})
+
+	assert.Nil(s.T(), team)
+	assert.Equal(s.T(), want, err)
+	client.AssertCalled(s.T(), "Delete", &proto.DeleteTeamRequest{Id: int32(s.Team.Id)})
+}
+
+func (s *TeamServiceTest) TestCreateAttachParentAndDeleteFailedTeamService() {
+	want := s.ServiceDownErr
+
+	client := new(ClientMock)
+
+	client.On("FindOne", &proto.FindOneTeamRequest{Id: 2}).Return(&proto.TeamResponse{
+		StatusCode: http.StatusOK,
+		Errors:     nil,
+		Data:       &proto.Team{Id: 2},
+	}, nil)
+	client.On("Create", &proto.Team{Name: s.Team.Name}).Return(&proto.TeamResponse{
+		StatusCode: http.StatusCreated,
+		Errors:     nil,
+		Data:       s.Team,
+	}, nil)
+	client.On("Update", &proto.Team{Id: 2, SubTeams: []*proto.Team{s.Team}}).Return(nil, errors.New("Service is down"))
+	client.On("Delete", &proto.DeleteTeamRequest{Id: int32(s.Team.Id)}).Return(nil, errors.New("Service is down"))
+
+	srv := service.NewTeamService(client)
+
+	team, err := srv.Create(context.Background(), &dto.TeamDto{Name: s.Team.Name, ParentTeamID: dto.NullableID{Set: true, ID: 2}})
+
+	assert.Nil(s.T(), team)
+	assert.Equal(s.T(), want, err)
+}
+
+func (s *TeamServiceTest) TestCreateNotFoundParentTeamService() {
+	want := s.NotFoundErr
+
+	client := new(ClientMock)
+
+	client.On("FindOne", &proto.FindOneTeamRequest{Id: 2}).Return(&proto.TeamResponse{
+		StatusCode: http.StatusNotFound,
+		Errors:     []string{"Not found team"},
+		Data:       nil,
+	}, nil)
+
+	srv := service.NewTeamService(client)
+
+	team, err := srv.Create(context.Background(), &dto.TeamDto{Name: s.Team.Name, ParentTeamID: dto.NullableID{Set: true, ID: 2}})
+
+	assert.Nil(s.T(), team)
+	assert.Equal(s.T(), want, err)
+	client.AssertNumberOfCalls(s.T(), "Create", 0)
+}
@@ -0,0 +1,223 @@
+package twofactor
+
+import (
+	"github.com/samithiwat/samithiwat-backend-gateway/src/config"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/dto"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/handler"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/proto"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/service"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/test/activity"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/test/user"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/validator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"testing"
+	"time"
+)
+
+type TwoFactorHandlerTest struct {
+	suite.Suite
+	User          *proto.User
+	Credential    *proto.Credential
+	Session       config.Session
+	CookieSession config.Session
+	Sessions      *service.SessionService
+	Store         *service.MemorySessionStore
+}
+
+func TestTwoFactorHandler(t *testing.T) {
+	suite.Run(t, new(TwoFactorHandlerTest))
+}
+
+func (u *TwoFactorHandlerTest) SetupTest() {
+	u.User = &proto.User{Id: 1, DisplayName: "Smithy"}
+
+	u.Credential = &proto.Credential{
+		AccessToken:  "access",
+		RefreshToken: "refresh",
+		ExpiresIn:    3600,
+	}
+
+	u.Session = config.Session{Mode: "header"}
+
+	u.Store = service.NewMemorySessionStore()
+	u.Sessions = service.NewSessionService(u.Store, time.Hour)
+
+	u.CookieSession = config.Session{
+		Mode:          "cookie",
+		AccessCookie:  "access_token",
+		RefreshCookie: "refresh_token",
+		CsrfCookie:    "csrf_token",
+		RefreshMaxAge: 24 * time.Hour,
+	}
+}
+
+func (u *TwoFactorHandlerTest) TestSetupSuccess() {
+	want := &dto.TotpSetup{Secret: "JBSWY3DPEHPK3PXP", Uri: "otpauth://totp/Samithiwat.dev:Smithy", QrCode: "png"}
+
+	srv := new(ServiceMock)
+	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	c := new(ContextMock)
+
+	c.On("Principal").Return(&dto.Principal{UserID: 1})
+	userSrv.On("FindOne", int32(1)).Return(u.User, nil)
+	srv.On("Setup", uint32(1), "Smithy").Return(want, nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewTwoFactorHandler(srv, userSrv, activitySrv, u.Sessions, u.Session, v)
+	h.Setup(c)
+
+	assert.Equal(u.T(), want, c.V)
+}
+
+func (u *TwoFactorHandlerTest) TestSetupApiKeyClient() {
+	srv := new(ServiceMock)
+	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	c := new(ContextMock)
+
+	c.On("Principal").Return(&dto.Principal{ApiKeyID: "ci"})
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewTwoFactorHandler(srv, userSrv, activitySrv, u.Sessions, u.Session, v)
+	h.Setup(c)
+
+	assert.Equal(u.T(), http.StatusUnauthorized, c.Status)
+	srv.AssertNotCalled(u.T(), "Setup", mock.Anything, mock.Anything)
+}
+
+func (u *TwoFactorHandlerTest) TestConfirmSuccess() {
+	want := &dto.RecoveryCodes{Codes: []string{"abcdefgh-ijklmnop"}}
+
+	srv := new(ServiceMock)
+	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	c := &ContextMock{TotpCode: &dto.TotpCode{Code: "123456"}}
+
+	c.On("Principal").Return(&dto.Principal{UserID: 1})
+	c.On("Bind", mock.AnythingOfType("*dto.TotpCode")).Return(nil)
+	srv.On("Confirm", uint32(1), "123456").Return(want, nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewTwoFactorHandler(srv, userSrv, activitySrv, u.Sessions, u.Session, v)
+	h.Confirm(c)
+
+	assert.Equal(u.T(), want, c.V)
+}
+
+func (u *TwoFactorHandlerTest) TestDisableSuccess() {
+	srv := new(ServiceMock)
+	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	c := &ContextMock{TotpCode: &dto.TotpCode{Code: "123456"}}
+
+	c.On("Principal").Return(&dto.Principal{UserID: 1})
+	c.On("Bind", mock.AnythingOfType("*dto.TotpCode")).Return(nil)
+	srv.On("Disable", uint32(1), "123456").Return(nil)
+	activitySrv.On("Record", proto.LogType_EDIT, uint32(1), "Disable two-factor authentication").Return()
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewTwoFactorHandler(srv, userSrv, activitySrv, u.Sessions, u.Session, v)
+	h.Disable(c)
+
+	assert.Equal(u.T(), http.StatusNoContent, c.Status)
+	activitySrv.AssertCalled(u.T(), "Record", proto.LogType_EDIT, uint32(1), "Disable two-factor authentication")
+}
+
+func (u *TwoFactorHandlerTest) TestDisableInvalidCode() {
+	want := &dto.ResponseErr{StatusCode: http.StatusBadRequest, Message: "Invalid code"}
+
+	srv := new(ServiceMock)
+	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	c := &ContextMock{TotpCode: &dto.TotpCode{Code: "000000"}}
+
+	c.On("Principal").Return(&dto.Principal{UserID: 1})
+	c.On("Bind", mock.AnythingOfType("*dto.TotpCode")).Return(nil)
+	srv.On("Disable", uint32(1), "000000").Return(want)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewTwoFactorHandler(srv, userSrv, activitySrv, u.Sessions, u.Session, v)
+	h.Disable(c)
+
+	assert.Equal(u.T(), want, c.V)
+	activitySrv.AssertNotCalled(u.T(), "Record", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (u *TwoFactorHandlerTest) TestVerifySuccess() {
+	srv := new(ServiceMock)
+	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	c := &ContextMock{
+		VerifyDto: &dto.TwoFactorVerify{ChallengeToken: "challenge", Code: "123456"},
+		UserAgent: "Mozilla/5.0",
+	}
+
+	c.On("Bind", mock.AnythingOfType("*dto.TwoFactorVerify")).Return(nil)
+	srv.On("Verify", "challenge", "123456").Return(1, u.Credential, nil)
+	activitySrv.On("Record", proto.LogType_LOGIN, uint32(1), "Login with two-factor authentication").Return()
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewTwoFactorHandler(srv, userSrv, activitySrv, u.Sessions, u.Session, v)
+	h.Verify(c)
+
+	assert.Equal(u.T(), u.Credential, c.V)
+	activitySrv.AssertCalled(u.T(), "Record", proto.LogType_LOGIN, uint32(1), "Login with two-factor authentication")
+
+	sessions, _ := u.Store.FindByUser(1)
+	assert.Len(u.T(), sessions, 1)
+	assert.Equal(u.T(), "Mozilla/5.0", sessions[0].UserAgent)
+}
+
+func (u *TwoFactorHandlerTest) TestVerifyCookieSession() {
+	srv := new(ServiceMock)
+	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	c := &ContextMock{VerifyDto: &dto.TwoFactorVerify{ChallengeToken: "challenge", Code: "123456"}}
+
+	c.On("Bind", mock.AnythingOfType("*dto.TwoFactorVerify")).Return(nil)
+	srv.On("Verify", "challenge", "123456").Return(1, u.Credential, nil)
+	activitySrv.On("Record", mock.Anything, mock.Anything, mock.Anything).Return()
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewTwoFactorHandler(srv, userSrv, activitySrv, u.Sessions, u.CookieSession, v)
+	h.Verify(c)
+
+	_, ok := c.V.(*dto.Session)
+	assert.True(u.T(), ok)
+	assert.Equal(u.T(), u.Credential.AccessToken, c.Cookies["access_token"].Value)
+}
+
+func (u *TwoFactorHandlerTest) TestVerifyInvalidCode() {
+	want := &dto.ResponseErr{
+		StatusCode: http.StatusUnauthorized,
+		Message:    "Invalid code",
+		Data:       nil,
+	}
+
+	srv := new(ServiceMock)
+	userSrv := new(user.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	c := &ContextMock{VerifyDto: &dto.TwoFactorVerify{ChallengeToken: "challenge", Code: "000000"}}
+
+	c.On("Bind", mock.AnythingOfType("*dto.TwoFactorVerify")).Return(nil)
+	srv.On("Verify", "challenge", "000000").Return(0, nil, want)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewTwoFactorHandler(srv, userSrv, activitySrv, u.Sessions, u.Session, v)
+	h.Verify(c)
+
+	assert.Equal(u.T(), want, c.V)
+	activitySrv.AssertNotCalled(u.T(), "Record", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,128 @@
+package twofactor
+
+import (
+	"context"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/dto"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/proto"
+	"github.com/stretchr/testify/mock"
+)
+
+type ContextMock struct {
+	mock.Mock
+	V         interface{}
+	Status    int
+	TotpCode  *dto.TotpCode
+	VerifyDto *dto.TwoFactorVerify
+	Cookies   map[string]*dto.Cookie
+	UserAgent string
+	ClientIP  string
+}
+
+func (c *ContextMock) Bind(v interface{}) error {
+	args := c.Called(v)
+	switch v.(type) {
+	case *dto.TotpCode:
+		*v.(*dto.TotpCode) = *c.TotpCode
+	case *dto.TwoFactorVerify:
+		*v.(*dto.TwoFactorVerify) = *c.VerifyDto
+	}
+
+	return args.Error(0)
+}
+
+func (c *ContextMock) JSON(status int, v interface{}) {
+	c.Status = status
+	c.V = v
+}
+
+func (c *ContextMock) Principal() *dto.Principal {
+	args := c.Called()
+
+	return args.Get(0).(*dto.Principal)
+}
+
+func (c *ContextMock) SetCookie(cookie *dto.Cookie) {
+	if c.Cookies == nil {
+		c.Cookies = map[string]*dto.Cookie{}
+	}
+
+	c.Cookies[cookie.Name] = cookie
+}
+
+func (c *ContextMock) Device() *dto.Device {
+	return &dto.Device{UserAgent: c.UserAgent, IP: c.ClientIP}
+}
+
+type ServiceMock struct {
+	mock.Mock
+}
+
+func (s *ServiceMock) Setup(_ context.Context, userId uint32, account string) (res *dto.TotpSetup, err *dto.ResponseErr) {
+	args := s.Called(userId, account)
+
+	if args.Get(0) != nil {
+		res = args.Get(0).(*dto.TotpSetup)
+	}
+
+	if args.Get(1) != nil {
+		err = args.Get(1).(*dto.ResponseErr)
+	}
+
+	return
+}
+
+func (s *ServiceMock) Confirm(_ context.Context, userId uint32, code string) (res *dto.RecoveryCodes, err *dto.ResponseErr) {
+	args := s.Called(userId, code)
+
+	if args.Get(0) != nil {
+		res = args.Get(0).(*dto.RecoveryCodes)
+	}
+
+	if args.Get(1) != nil {
+		err = args.Get(1).(*dto.ResponseErr)
+	}
+
+	return
+}
+
+func (s *ServiceMock) Challenge(_ context.Context, userId uint32, credential *proto.Credential) (res *dto.TwoFactorChallenge, err *dto.ResponseErr) {
+	args := s.Called(userId, credential)
+
+	if args.Get(0) != nil {
+		res = args.Get(0).(*dto.TwoFactorChallenge)
+	}
+
+	if args.Get(1) != nil {
+		err = args.Get(1).(*dto.ResponseErr)
+	}
+
+	return
+}
+
+func (s *ServiceMock) Verify(_ context.Context, challengeToken string, code string) (userId uint32, res *proto.Credential, err *dto.ResponseErr) {
+	args := s.Called(challengeToken, code)
+
+	if args.Get(1) != nil {
+		res = args.Get(1).(*proto.Credential)
+	}
+
+	if args.Get(2) != nil {
+		err = args.Get(2).(*dto.ResponseErr)
+	}
+
+	return uint32(args.Int(0)), res, err
+}
+
+func (s *ServiceMock) Disable(_ context.Context, userId uint32, code string) *dto.ResponseErr {
+	args := s.Called(userId, code)
+
+	if args.Get(0) != nil {
+		return args.Get(0).(*dto.ResponseErr)
+	}
+
+	return nil
+}
+
+func (c *ContextMock) UserContext() context.Context {
+	return context.Background()
+}
@@ -0,0 +1,316 @@
+package twofactor
+
+import (
+	"context"
+	"encoding/base32"
+	"encoding/base64"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/constant"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/dto"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/proto"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+type TwoFactorServiceTest struct {
+	suite.Suite
+	Key        []byte
+	Store      *service.MemoryTotpStore
+	Lockout    *service.LockoutService
+	Credential *proto.Credential
+}
+
+func TestTwoFactorService(t *testing.T) {
+	suite.Run(t, new(TwoFactorServiceTest))
+}
+
+func (s *TwoFactorServiceTest) SetupTest() {
+	s.Key = []byte("0123456789abcdef0123456789abcdef")
+	s.Store = service.NewMemoryTotpStore()
+	s.Lockout = service.NewLockoutService(service.NewMemoryLockoutStore(), map[string]service.LockoutPolicy{
+		constant.LockoutTwoFactorUser: {Window: time.Hour, MaxFailures: 3, BaseLockout: time.Minute, MaxLockout: time.Hour},
+	})
+	s.Credential = &proto.Credential{
+		AccessToken:  "access",
+		RefreshToken: "refresh",
+		ExpiresIn:    3600,
+	}
+}
+
+func (s *TwoFactorServiceTest) newService() *service.TwoFactorService {
+	srv, err := service.NewTwoFactorService(s.Store, s.Lockout, s.Key, "Samithiwat.dev", 5*time.Minute, 4)
+	s.Require().Nil(err)
+
+	return srv
+}
+
+func (s *TwoFactorServiceTest) code(secret string, offset int64) string {
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(secret)
+	s.Require().Nil(err)
+
+	return service.TotpCode(key, time.Now().Unix()/30+offset)
+}
+
+// enroll sets up and confirms the authenticator of the user, it returns the secret and the recovery codes
+func (s *TwoFactorServiceTest) enroll(srv *service.TwoFactorService, userId uint32) (string, []string) {
+	setup, errRes := srv.Setup(context.Background(), userId, "Smithy")
+	s.Require().Nil(errRes)
+
+	codes, errRes := srv.Confirm(context.Background(), userId, s.code(setup.Secret, -1))
+	s.Require().Nil(errRes)
+
+	return setup.Secret, codes.Codes
+}
+
+func (s *TwoFactorServiceTest) TestTotpCodeRfc6238() {
+	secret := []byte("12345678901234567890")
+
+	assert.Equal(s.T(), "287082", service.TotpCode(secret, 59/30))
+	assert.Equal(s.T(), "081804", service.TotpCode(secret, 1111111109/30))
+	assert.Equal(s.T(), "005924", service.TotpCode(secret, 1234567890/30))
+}
+
+func (s *TwoFactorServiceTest) TestNewServiceInvalidKey() {
+	_, err := service.NewTwoFactorService(s.Store, s.Lockout, []byte("short"), "Samithiwat.dev", 5*time.Minute, 4)
+
+	assert.NotNil(s.T(), err)
+}
+
+func (s *TwoFactorServiceTest) TestSetup() {
+	srv := s.newService()
+
+	setup, errRes := srv.Setup(context.Background(), 1, "Smithy")
+
+	assert.Nil(s.T(), errRes)
+	assert.True(s.T(), strings.HasPrefix(setup.Uri, "otpauth://totp/Samithiwat.dev:Smithy?"))
+	assert.Contains(s.T(), setup.Uri, "secret="+setup.Secret)
+
+	png, err := base64.StdEncoding.DecodeString(setup.QrCode)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), "\x89PNG", string(png[:4]))
+
+	enrollment, _ := s.Store.Get(1)
+	assert.False(s.T(), enrollment.Confirmed)
+	assert.NotContains(s.T(), string(enrollment.Secret), setup.Secret)
+}
+
+func (s *TwoFactorServiceTest) TestConfirm() {
+	srv := s.newService()
+
+	_, codes := s.enroll(srv, 1)
+
+	assert.Len(s.T(), codes, 4)
+
+	enrollment, _ := s.Store.Get(1)
+	assert.True(s.T(), enrollment.Confirmed)
+
+	_, errRes := srv.Setup(context.Background(), 1, "Smithy")
+	assert.Equal(s.T(), http.StatusConflict, errRes.StatusCode)
+}
+
+func (s *TwoFactorServiceTest) TestConfirmInvalidCode() {
+	srv := s.newService()
+
+	_, _ = srv.Setup(context.Background(), 1, "Smithy")
+
+	_, errRes := srv.Confirm(context.Background(), 1, "000000")
+
+	assert.Equal(s.T(), http.StatusBadRequest, errRes.StatusCode)
+}
+
+func (s *TwoFactorServiceTest) TestConfirmWithoutSetup() {
+	_, errRes := s.newService().Confirm(context.Background(), 1, "000000")
+
+	assert.Equal(s.T(), http.StatusBadRequest, errRes.StatusCode)
+}
+
+func (s *TwoFactorServiceTest) TestChallengeWithoutTwoFactor() {
+	challenge, errRes := s.newService().Challenge(context.Background(), 1, s.Credential)
+
+	assert.Nil(s.T(), errRes)
+	assert.Nil(s.T(), challenge)
+}
+
+func (s *TwoFactorServiceTest) TestVerifyTotp() {
+	srv := s.newService()
+	secret, _ := s.enroll(srv, 1)
+
+	challenge, errRes := srv.Challenge(context.Background(), 1, s.Credential)
+	s.Require().Nil(errRes)
+	assert.Equal(s.T(), int32(300), challenge.ExpiresIn)
+
+	userId, credential, errRes := srv.Verify(context.Background(), challenge.ChallengeToken, s.code(secret, 0))
+
+	assert.Nil(s.T(), errRes)
+	assert.Equal(s.T(), uint32(1), userId)
+	assert.Equal(s.T(), s.Credential, credential)
+
+	_, _, errRes = srv.Verify(context.Background(), challenge.ChallengeToken, s.code(secret, 0))
+	assert.Equal(s.T(), http.StatusUnauthorized, errRes.StatusCode)
+}
+
+func (s *TwoFactorServiceTest) TestVerifyReplayedCode() {
+	srv := s.newService()
+	secret, _ := s.enroll(srv, 1)
+
+	challenge, _ := srv.Challenge(context.Background(), 1, s.Credential)
+	code := s.code(secret, 0)
+	_, _, errRes := srv.Verify(context.Background(), challenge.ChallengeToken, code)
+	s.Require().Nil(errRes)
+
+	challenge, _ = srv.Challenge(context.Background(), 1, s.Credential)
+	_, _, errRes = srv.Verify(context.Background(), challenge.ChallengeToken, code)
+
+	assert.Equal(s.T(), &dto.ResponseErr{StatusCode: http.StatusUnauthorized, Message: "Invalid code"}, errRes)
+}
+
+func (s *TwoFactorServiceTest) TestVerifyRecoveryCodeOnce() {
+	srv := s.newService()
+	_, codes := s.enroll(srv, 1)
+
+	challenge, _ := srv.Challenge(context.Background(), 1, s.Credential)
+	_, _, errRes := srv.Verify(context.Background(), challenge.ChallengeToken, strings.ToUpper(codes[0]))
+	assert.Nil(s.T(), errRes)
+
+	challenge, _ = srv.Challenge(context.Background(), 1, s.Credential)
+	_, _, errRes = srv.Verify(context.Background(), challenge.ChallengeToken, codes[0])
+	assert.Equal(s.T(), http.StatusUnauthorized, errRes.StatusCode)
+
+	enrollment, _ := s.Store.Get(1)
+	assert.Len(s.T(), enrollment.RecoveryCodes, 3)
+}
+
+func (s *TwoFactorServiceTest) TestVerifyTooManyAttempts() {
+	// the user is not locked out so only the attempts of the challenge are limited
+	s.Lockout = service.NewLockoutService(service.NewMemoryLockoutStore(), nil)
+	srv := s.newService()
+	secret, _ := s.enroll(srv, 1)
+
+	challenge, _ := srv.Challenge(context.Background(), 1, s.Credential)
+	for i := 0; i < 5; i++ {
+		_, _, errRes := srv.Verify(context.Background(), challenge.ChallengeToken, "wrong")
+		assert.Equal(s.T(), "Invalid code", errRes.Message)
+	}
+
+	_, _, errRes := srv.Verify(context.Background(), challenge.ChallengeToken, s.code(secret, 0))
+
+	assert.Equal(s.T(), "Invalid or expired challenge", errRes.Message)
+}
+
+// TestVerifyLockoutAcrossChallenges keeps the user locked out when it logs in again for a new challenge
+func (s *TwoFactorServiceTest) TestVerifyLockoutAcrossChallenges() {
+	srv := s.newService()
+	secret, _ := s.enroll(srv, 1)
+
+	for i := 0; i < 3; i++ {
+		challenge, _ := srv.Challenge(context.Background(), 1, s.Credential)
+		_, _, errRes := srv.Verify(context.Background(), challenge.ChallengeToken, "000000")
+		assert.Equal(s.T(), "Invalid code", errRes.Message)
+	}
+
+	challenge, _ := srv.Challenge(context.Background(), 1, s.Credential)
+	_, _, errRes := srv.Verify(context.Background(), challenge.ChallengeToken, s.code(secret, 0))
+
+	assert.Equal(s.T(), &dto.ResponseErr{StatusCode: http.StatusTooManyRequests, Message: "Too many attempts, try again later"}, errRes)
+}
+
+func (s *TwoFactorServiceTest) TestVerifyResetsLockout() {
+	srv := s.newService()
+	secret, _ := s.enroll(srv, 1)
+
+	challenge, _ := srv.Challenge(context.Background(), 1, s.Credential)
+	_, _, _ = srv.Verify(context.Background(), challenge.ChallengeToken, "000000")
+	_, _, _ = srv.Verify(context.Background(), challenge.ChallengeToken, "000000")
+	_, _, errRes := srv.Verify(context.Background(), challenge.ChallengeToken, s.code(secret, 0))
+	s.Require().Nil(errRes)
+
+	assert.Zero(s.T(), s.Lockout.Check(context.Background(), constant.LockoutTwoFactorUser, "1"))
+}
+
+func (s *TwoFactorServiceTest) TestDisable() {
+	srv := s.newService()
+	secret, _ := s.enroll(srv, 1)
+
+	assert.Nil(s.T(), srv.Disable(context.Background(), 1, s.code(secret, 0)))
+
+	enrollment, _ := s.Store.Get(1)
+	assert.Nil(s.T(), enrollment)
+
+	challenge, errRes := srv.Challenge(context.Background(), 1, s.Credential)
+	assert.Nil(s.T(), errRes)
+	assert.Nil(s.T(), challenge)
+}
+
+func (s *TwoFactorServiceTest) TestDisableWithRecoveryCode() {
+	srv := s.newService()
+	_, codes := s.enroll(srv, 1)
+
+	assert.Nil(s.T(), srv.Disable(context.Background(), 1, codes[1]))
+}
+
+func (s *TwoFactorServiceTest) TestDisableInvalidCode() {
+	srv := s.newService()
+	_, _ = s.enroll(srv, 1)
+
+	errRes := srv.Disable(context.Background(), 1, "000000")
+
+	assert.Equal(s.T(), &dto.ResponseErr{StatusCode: http.StatusBadRequest, Message: "Invalid code"}, errRes)
+
+	enrollment, _ := s.Store.Get(1)
+	assert.True(s.T(), enrollment.Confirmed)
+}
+
+func (s *TwoFactorServiceTest) TestDisableWithoutTwoFactor() {
+	errRes := s.newService().Disable(context.Background(), 1, "000000")
+
+	assert.Equal(s.T(), http.StatusBadRequest, errRes.StatusCode)
+}
+
+func (s *TwoFactorServiceTest) TestRedisStore() {
+	server := miniredis.RunT(s.T())
+	store := service.NewRedisTotpStore(redis.NewClient(&redis.Options{Addr: server.Addr()}), "gateway:")
+
+	enrollment, err := store.Get(1)
+	assert.Nil(s.T(), err)
+	assert.Nil(s.T(), enrollment)
+
+	want := &service.TotpEnrollment{Secret: []byte{1, 2, 3}, Confirmed: true, LastStep: 42, RecoveryCodes: []string{"hash"}}
+	assert.Nil(s.T(), store.Set(1, want))
+
+	enrollment, err = store.Get(1)
+	assert.Nil(s.T(), err)
+	assert.Equal(s.T(), want, enrollment)
+
+	assert.Nil(s.T(), store.Delete(1))
+
+	enrollment, _ = store.Get(1)
+	assert.Nil(s.T(), enrollment)
+}
+
+func (s *TwoFactorServiceTest) TestVerifyExpiredChallenge() {
+	srv, err := service.NewTwoFactorService(s.Store, s.Lockout, s.Key, "Samithiwat.dev", -time.Second, 4)
+	s.Require().Nil(err)
+	secret, _ := s.enroll(srv, 1)
+
+	challenge, _ := srv.Challenge(context.Background(), 1, s.Credential)
+	_, _, errRes := srv.Verify(context.Background(), challenge.ChallengeToken, s.code(secret, 0))
+
+	assert.Equal(s.T(), "Invalid or expired challenge", errRes.Message)
+}
+
+func (s *TwoFactorServiceTest) TestDisabledTwoFactor() {
+	srv := service.DisabledTwoFactor{}
+
+	challenge, errRes := srv.Challenge(context.Background(), 1, s.Credential)
+	assert.Nil(s.T(), errRes)
+	assert.Nil(s.T(), challenge)
+
+	_, errRes = srv.Setup(context.Background(), 1, "Smithy")
+	assert.Equal(s.T(), http.StatusNotFound, errRes.StatusCode)
+}
@@ -3,13 +3,20 @@ package user
 import (
 	"github.com/bxcodec/faker/v3"
 	"github.com/pkg/errors"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/constant"
 	"github.com/samithiwat/samithiwat-backend-gateway/src/dto"
 	"github.com/samithiwat/samithiwat-backend-gateway/src/handler"
 	"github.com/samithiwat/samithiwat-backend-gateway/src/proto"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/test/activity"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/test/contact"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/test/location"
 	"github.com/samithiwat/samithiwat-backend-gateway/src/validator"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 	"net/http"
+	"strconv"
+	"strings"
 	"testing"
 )
 
@@ -20,6 +27,10 @@ type UserHandlerTest struct {
 	UserDto        *dto.UserDto
 	Query          *dto.PaginationQueryParams
 	InvalidIDErr   *dto.ResponseErr
+	Contact        *proto.Contact
+	ContactDto     *dto.ContactDto
+	Location       *proto.Location
+	LocationDto    *dto.LocationDto
 	NotFoundErr    *dto.ResponseErr
 	ServiceDownErr *dto.ResponseErr
 }
@@ -84,6 +95,38 @@ func (u *UserHandlerTest) SetupTest() {
 		StatusCode: http.StatusBadRequest,
 		Message:    "Invalid ID",
 	}
+
+	u.Contact = &proto.Contact{
+		Id:        1,
+		Facebook:  faker.URL(),
+		Instagram: faker.URL(),
+		Twitter:   faker.URL(),
+		Linkedin:  faker.URL(),
+	}
+
+	u.ContactDto = &dto.ContactDto{
+		Facebook:  u.Contact.Facebook,
+		Instagram: u.Contact.Instagram,
+		Twitter:   u.Contact.Twitter,
+		Linkedin:  u.Contact.Linkedin,
+	}
+
+	u.Location = &proto.Location{
+		Id:       1,
+		Address:  faker.Sentence(),
+		District: faker.Word(),
+		Province: faker.Word(),
+		Country:  faker.Word(),
+		Zipcode:  "10330",
+	}
+
+	u.LocationDto = &dto.LocationDto{
+		Address:  u.Location.Address,
+		District: u.Location.District,
+		Province: u.Location.Province,
+		Country:  u.Location.Country,
+		Zipcode:  u.Location.Zipcode,
+	}
 }
 
 func (u *UserHandlerTest) TestFindAllUser() {
@@ -99,6 +142,9 @@ func (u *UserHandlerTest) TestFindAllUser() {
 	}
 
 	srv := new(ServiceMock)
+	contactSrv := new(contact.ServiceMock)
+	locationSrv := new(location.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
 	c := &ContextMock{
 		User:    u.User,
 		Users:   u.Users,
@@ -110,7 +156,7 @@ func (u *UserHandlerTest) TestFindAllUser() {
 	c.On("PaginationQueryParam", &dto.PaginationQueryParams{}).Return(nil)
 	v, _ := validator.NewValidator()
 
-	h := handler.NewUserHandler(srv, v)
+	h := handler.NewUserHandler(srv, contactSrv, locationSrv, activitySrv, v)
 	h.FindAll(c)
 
 	assert.Equal(u.T(), want, c.V)
@@ -123,6 +169,9 @@ func (u *UserHandlerTest) TestFindAllInvalidQueryParamUser() {
 	}
 
 	srv := new(ServiceMock)
+	contactSrv := new(contact.ServiceMock)
+	locationSrv := new(location.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
 	c := &ContextMock{
 		User:    u.User,
 		Users:   u.Users,
@@ -135,7 +184,7 @@ func (u *UserHandlerTest) TestFindAllInvalidQueryParamUser() {
 
 	v, _ := validator.NewValidator()
 
-	h := handler.NewUserHandler(srv, v)
+	h := handler.NewUserHandler(srv, contactSrv, locationSrv, activitySrv, v)
 
 	h.FindAll(c)
 
@@ -146,6 +195,9 @@ func (u *UserHandlerTest) TestFindAllGrpcErrUser() {
 	want := u.ServiceDownErr
 
 	srv := new(ServiceMock)
+	contactSrv := new(contact.ServiceMock)
+	locationSrv := new(location.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
 	c := &ContextMock{
 		User:    u.User,
 		Users:   u.Users,
@@ -158,7 +210,7 @@ func (u *UserHandlerTest) TestFindAllGrpcErrUser() {
 
 	v, _ := validator.NewValidator()
 
-	h := handler.NewUserHandler(srv, v)
+	h := handler.NewUserHandler(srv, contactSrv, locationSrv, activitySrv, v)
 
 	h.FindAll(c)
 
@@ -169,6 +221,9 @@ func (u *UserHandlerTest) TestFindOneUser() {
 	want := u.User
 
 	srv := new(ServiceMock)
+	contactSrv := new(contact.ServiceMock)
+	locationSrv := new(location.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
 	c := &ContextMock{
 		User:    u.User,
 		Users:   u.Users,
@@ -181,17 +236,43 @@ func (u *UserHandlerTest) TestFindOneUser() {
 
 	v, _ := validator.NewValidator()
 
-	h := handler.NewUserHandler(srv, v)
+	h := handler.NewUserHandler(srv, contactSrv, locationSrv, activitySrv, v)
 
 	h.FindOne(c)
 
 	assert.Equal(u.T(), want, c.V)
 }
 
+func (u *UserHandlerTest) TestFindOneHidesAddressUser() {
+	u.User.Address = u.Location
+
+	srv := new(ServiceMock)
+	c := &ContextMock{
+		User:    u.User,
+		Users:   u.Users,
+		UserDto: u.UserDto,
+		Query:   u.Query,
+	}
+
+	srv.On("FindOne", int32(1)).Return(u.User, nil)
+	c.On("ID").Return(1, nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewUserHandler(srv, new(contact.ServiceMock), new(location.ServiceMock), new(activity.ServiceMock), v)
+
+	h.FindOne(c)
+
+	assert.Nil(u.T(), c.V.(*proto.User).Address)
+}
+
 func (u *UserHandlerTest) TestFindOneInvalidRequestParamIDUser() {
 	want := u.InvalidIDErr
 
 	srv := new(ServiceMock)
+	contactSrv := new(contact.ServiceMock)
+	locationSrv := new(location.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
 	c := &ContextMock{
 		User:    u.User,
 		Users:   u.Users,
@@ -204,7 +285,7 @@ func (u *UserHandlerTest) TestFindOneInvalidRequestParamIDUser() {
 
 	v, _ := validator.NewValidator()
 
-	h := handler.NewUserHandler(srv, v)
+	h := handler.NewUserHandler(srv, contactSrv, locationSrv, activitySrv, v)
 	h.FindOne(c)
 
 	assert.Equal(u.T(), want, c.V)
@@ -214,6 +295,9 @@ func (u *UserHandlerTest) TestFindOneErrorNotFoundUser() {
 	want := u.NotFoundErr
 
 	srv := new(ServiceMock)
+	contactSrv := new(contact.ServiceMock)
+	locationSrv := new(location.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
 	c := &ContextMock{
 		User:    u.User,
 		Users:   u.Users,
@@ -226,7 +310,7 @@ func (u *UserHandlerTest) TestFindOneErrorNotFoundUser() {
 
 	v, _ := validator.NewValidator()
 
-	h := handler.NewUserHandler(srv, v)
+	h := handler.NewUserHandler(srv, contactSrv, locationSrv, activitySrv, v)
 
 	h.FindOne(c)
 
@@ -237,6 +321,9 @@ func (u *UserHandlerTest) TestFindOneGrpcErrUser() {
 	want := u.ServiceDownErr
 
 	srv := new(ServiceMock)
+	contactSrv := new(contact.ServiceMock)
+	locationSrv := new(location.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
 	c := &ContextMock{
 		User:    u.User,
 		Users:   u.Users,
@@ -249,7 +336,7 @@ func (u *UserHandlerTest) TestFindOneGrpcErrUser() {
 
 	v, _ := validator.NewValidator()
 
-	h := handler.NewUserHandler(srv, v)
+	h := handler.NewUserHandler(srv, contactSrv, locationSrv, activitySrv, v)
 
 	h.FindOne(c)
 
@@ -260,6 +347,9 @@ func (u *UserHandlerTest) TestCreateUser() {
 	want := u.User
 
 	srv := new(ServiceMock)
+	contactSrv := new(contact.ServiceMock)
+	locationSrv := new(location.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
 	c := &ContextMock{
 		User:    u.User,
 		Users:   u.Users,
@@ -272,7 +362,7 @@ func (u *UserHandlerTest) TestCreateUser() {
 
 	v, _ := validator.NewValidator()
 
-	h := handler.NewUserHandler(srv, v)
+	h := handler.NewUserHandler(srv, contactSrv, locationSrv, activitySrv, v)
 	h.Create(c)
 
 	assert.Equal(u.T(), want, c.V)
@@ -285,6 +375,9 @@ func (u *UserHandlerTest) TestCreateErrorDuplicatedUser() {
 	}
 
 	srv := new(ServiceMock)
+	contactSrv := new(contact.ServiceMock)
+	locationSrv := new(location.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
 	c := &ContextMock{
 		User:    u.User,
 		Users:   u.Users,
@@ -297,7 +390,7 @@ func (u *UserHandlerTest) TestCreateErrorDuplicatedUser() {
 
 	v, _ := validator.NewValidator()
 
-	h := handler.NewUserHandler(srv, v)
+	h := handler.NewUserHandler(srv, contactSrv, locationSrv, activitySrv, v)
 	h.Create(c)
 
 	assert.Equal(u.T(), want, c.V)
@@ -310,6 +403,9 @@ func (u *UserHandlerTest) TestCreateInvalidBodyRequest() {
 	}
 
 	srv := new(ServiceMock)
+	contactSrv := new(contact.ServiceMock)
+	locationSrv := new(location.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
 	c := &ContextMock{
 		User:    u.User,
 		Users:   u.Users,
@@ -322,7 +418,7 @@ func (u *UserHandlerTest) TestCreateInvalidBodyRequest() {
 
 	v, _ := validator.NewValidator()
 
-	h := handler.NewUserHandler(srv, v)
+	h := handler.NewUserHandler(srv, contactSrv, locationSrv, activitySrv, v)
 	h.Create(c)
 
 	assert.Equal(u.T(), want, c.V)
@@ -332,6 +428,9 @@ func (u *UserHandlerTest) TestCreateGrpcErrUser() {
 	want := u.ServiceDownErr
 
 	srv := new(ServiceMock)
+	contactSrv := new(contact.ServiceMock)
+	locationSrv := new(location.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
 	c := &ContextMock{
 		User:    u.User,
 		Users:   u.Users,
@@ -344,7 +443,7 @@ func (u *UserHandlerTest) TestCreateGrpcErrUser() {
 
 	v, _ := validator.NewValidator()
 
-	h := handler.NewUserHandler(srv, v)
+	h := handler.NewUserHandler(srv, contactSrv, locationSrv, activitySrv, v)
 
 	h.Create(c)
 
@@ -355,6 +454,9 @@ func (u *UserHandlerTest) TestUpdateUser() {
 	want := u.User
 
 	srv := new(ServiceMock)
+	contactSrv := new(contact.ServiceMock)
+	locationSrv := new(location.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
 	c := &ContextMock{
 		User:    u.User,
 		Users:   u.Users,
@@ -368,7 +470,7 @@ func (u *UserHandlerTest) TestUpdateUser() {
 
 	v, _ := validator.NewValidator()
 
-	h := handler.NewUserHandler(srv, v)
+	h := handler.NewUserHandler(srv, contactSrv, locationSrv, activitySrv, v)
 
 	h.Update(c)
 
@@ -379,6 +481,9 @@ func (u *UserHandlerTest) TestUpdateInvalidRequestParamIDUser() {
 	want := u.InvalidIDErr
 
 	srv := new(ServiceMock)
+	contactSrv := new(contact.ServiceMock)
+	locationSrv := new(location.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
 	c := &ContextMock{
 		User:    u.User,
 		Users:   u.Users,
@@ -392,7 +497,7 @@ func (u *UserHandlerTest) TestUpdateInvalidRequestParamIDUser() {
 
 	v, _ := validator.NewValidator()
 
-	h := handler.NewUserHandler(srv, v)
+	h := handler.NewUserHandler(srv, contactSrv, locationSrv, activitySrv, v)
 
 	h.Update(c)
 
@@ -406,6 +511,9 @@ func (u *UserHandlerTest) TestUpdateInvalidBodyRequest() {
 	}
 
 	srv := new(ServiceMock)
+	contactSrv := new(contact.ServiceMock)
+	locationSrv := new(location.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
 	c := &ContextMock{
 		User:    u.User,
 		Users:   u.Users,
@@ -419,7 +527,7 @@ func (u *UserHandlerTest) TestUpdateInvalidBodyRequest() {
 
 	v, _ := validator.NewValidator()
 
-	h := handler.NewUserHandler(srv, v)
+	h := handler.NewUserHandler(srv, contactSrv, locationSrv, activitySrv, v)
 	h.Create(c)
 
 	assert.Equal(u.T(), want, c.V)
@@ -429,6 +537,9 @@ func (u *UserHandlerTest) TestUpdateErrorNotFoundUser() {
 	want := u.NotFoundErr
 
 	srv := new(ServiceMock)
+	contactSrv := new(contact.ServiceMock)
+	locationSrv := new(location.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
 	c := &ContextMock{
 		User:    u.User,
 		Users:   u.Users,
@@ -442,7 +553,7 @@ func (u *UserHandlerTest) TestUpdateErrorNotFoundUser() {
 
 	v, _ := validator.NewValidator()
 
-	h := handler.NewUserHandler(srv, v)
+	h := handler.NewUserHandler(srv, contactSrv, locationSrv, activitySrv, v)
 	h.Update(c)
 
 	assert.Equal(u.T(), want, c.V)
@@ -452,6 +563,9 @@ func (u *UserHandlerTest) TestUpdateGrpcErrUser() {
 	want := u.ServiceDownErr
 
 	srv := new(ServiceMock)
+	contactSrv := new(contact.ServiceMock)
+	locationSrv := new(location.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
 	c := &ContextMock{
 		User:    u.User,
 		Users:   u.Users,
@@ -465,7 +579,7 @@ func (u *UserHandlerTest) TestUpdateGrpcErrUser() {
 
 	v, _ := validator.NewValidator()
 
-	h := handler.NewUserHandler(srv, v)
+	h := handler.NewUserHandler(srv, contactSrv, locationSrv, activitySrv, v)
 
 	h.Update(c)
 
@@ -476,6 +590,9 @@ func (u *UserHandlerTest) TestDeleteUser() {
 	want := u.User
 
 	srv := new(ServiceMock)
+	contactSrv := new(contact.ServiceMock)
+	locationSrv := new(location.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
 	c := &ContextMock{
 		User:    u.User,
 		Users:   u.Users,
@@ -488,7 +605,7 @@ func (u *UserHandlerTest) TestDeleteUser() {
 
 	v, _ := validator.NewValidator()
 
-	h := handler.NewUserHandler(srv, v)
+	h := handler.NewUserHandler(srv, contactSrv, locationSrv, activitySrv, v)
 
 	h.Delete(c)
 
@@ -499,6 +616,9 @@ func (u *UserHandlerTest) TestDeleteInvalidRequestParamIDUser() {
 	want := u.InvalidIDErr
 
 	srv := new(ServiceMock)
+	contactSrv := new(contact.ServiceMock)
+	locationSrv := new(location.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
 	c := &ContextMock{
 		User:    u.User,
 		Users:   u.Users,
@@ -511,7 +631,7 @@ func (u *UserHandlerTest) TestDeleteInvalidRequestParamIDUser() {
 
 	v, _ := validator.NewValidator()
 
-	h := handler.NewUserHandler(srv, v)
+	h := handler.NewUserHandler(srv, contactSrv, locationSrv, activitySrv, v)
 
 	h.Delete(c)
 
@@ -522,6 +642,9 @@ func (u *UserHandlerTest) TestDeleteErrorNotFoundUser() {
 	want := u.NotFoundErr
 
 	srv := new(ServiceMock)
+	contactSrv := new(contact.ServiceMock)
+	locationSrv := new(location.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
 	c := &ContextMock{
 		User:    u.User,
 		Users:   u.Users,
@@ -534,7 +657,7 @@ func (u *UserHandlerTest) TestDeleteErrorNotFoundUser() {
 
 	v, _ := validator.NewValidator()
 
-	h := handler.NewUserHandler(srv, v)
+	h := handler.NewUserHandler(srv, contactSrv, locationSrv, activitySrv, v)
 
 	h.Delete(c)
 
@@ -545,6 +668,9 @@ func (u *UserHandlerTest) TestDeleteGrpcErrUser() {
 	want := u.ServiceDownErr
 
 	srv := new(ServiceMock)
+	contactSrv := new(contact.ServiceMock)
+	locationSrv := new(location.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
 	c := &ContextMock{
 		User:    u.User,
 		Users:   u.Users,
@@ -557,9 +683,518 @@ func (u *UserHandlerTest) TestDeleteGrpcErrUser() {
 
 	v, _ := validator.NewValidator()
 
-	h := handler.NewUserHandler(srv, v)
+	h := handler.NewUserHandler(srv, contactSrv, locationSrv, activitySrv, v)
 
 	h.Delete(c)
 
 	assert.Equal(u.T(), want, c.V)
 }
+
+func (u *UserHandlerTest) TestFindContactUser() {
+	want := u.Contact
+
+	srv := new(ServiceMock)
+	contactSrv := new(contact.ServiceMock)
+	locationSrv := new(location.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	c := &ContextMock{
+		User:    u.User,
+		Users:   u.Users,
+		UserDto: u.UserDto,
+		Query:   u.Query,
+	}
+
+	u.User.Contact = u.Contact
+
+	srv.On("FindOne", int32(1)).Return(u.User, nil)
+	c.On("ID").Return(1, nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewUserHandler(srv, contactSrv, locationSrv, activitySrv, v)
+	h.FindContact(c)
+
+	assert.Equal(u.T(), want, c.V)
+}
+
+func (u *UserHandlerTest) TestFindContactNotFoundUser() {
+	want := &dto.ResponseErr{
+		StatusCode: http.StatusNotFound,
+		Message:    "Not found contact",
+	}
+
+	srv := new(ServiceMock)
+	contactSrv := new(contact.ServiceMock)
+	locationSrv := new(location.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	c := &ContextMock{
+		User:    u.User,
+		Users:   u.Users,
+		UserDto: u.UserDto,
+		Query:   u.Query,
+	}
+
+	srv.On("FindOne", int32(1)).Return(u.User, nil)
+	c.On("ID").Return(1, nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewUserHandler(srv, contactSrv, locationSrv, activitySrv, v)
+	h.FindContact(c)
+
+	assert.Equal(u.T(), want, c.V)
+}
+
+func (u *UserHandlerTest) TestUpdateExistedContactUser() {
+	want := u.Contact
+
+	srv := new(ServiceMock)
+	contactSrv := new(contact.ServiceMock)
+	locationSrv := new(location.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	c := &ContextMock{
+		User:       u.User,
+		Users:      u.Users,
+		UserDto:    u.UserDto,
+		ContactDto: u.ContactDto,
+		Query:      u.Query,
+	}
+
+	u.User.Contact = u.Contact
+
+	srv.On("FindOne", int32(1)).Return(u.User, nil)
+	contactSrv.On("Update", int32(u.Contact.Id), u.ContactDto).Return(u.Contact, nil)
+	c.On("Bind", &dto.ContactDto{}).Return(nil)
+	c.On("ID").Return(1, nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewUserHandler(srv, contactSrv, locationSrv, activitySrv, v)
+	h.UpdateContact(c)
+
+	assert.Equal(u.T(), want, c.V)
+	srv.AssertNumberOfCalls(u.T(), "Update", 0)
+}
+
+func (u *UserHandlerTest) TestUpdateNewContactUser() {
+	want := u.Contact
+
+	srv := new(ServiceMock)
+	contactSrv := new(contact.ServiceMock)
+	locationSrv := new(location.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	c := &ContextMock{
+		User:       u.User,
+		Users:      u.Users,
+		UserDto:    u.UserDto,
+		ContactDto: u.ContactDto,
+		Query:      u.Query,
+	}
+
+	updated := &proto.User{
+		Id:        u.User.Id,
+		Firstname: u.User.Firstname,
+		Lastname:  u.User.Lastname,
+		ImageUrl:  u.User.ImageUrl,
+		Contact:   u.Contact,
+	}
+
+	srv.On("FindOne", int32(1)).Return(u.User, nil)
+	srv.On("Update", int32(1), &dto.UserDto{
+		Firstname:   u.User.Firstname,
+		Lastname:    u.User.Lastname,
+		DisplayName: u.User.DisplayName,
+		ImageUrl:    u.User.ImageUrl,
+		Contact:     u.ContactDto,
+	}).Return(updated, nil)
+	c.On("Bind", &dto.ContactDto{}).Return(nil)
+	c.On("ID").Return(1, nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewUserHandler(srv, contactSrv, locationSrv, activitySrv, v)
+	h.UpdateContact(c)
+
+	assert.Equal(u.T(), want, c.V)
+	contactSrv.AssertNumberOfCalls(u.T(), "Update", 0)
+}
+
+func (u *UserHandlerTest) TestUpdateContactInvalidBodyRequestUser() {
+	srv := new(ServiceMock)
+	contactSrv := new(contact.ServiceMock)
+	locationSrv := new(location.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	c := &ContextMock{
+		User:    u.User,
+		Users:   u.Users,
+		UserDto: u.UserDto,
+		ContactDto: &dto.ContactDto{
+			Facebook: "samithiwat",
+		},
+		Query: u.Query,
+	}
+
+	c.On("Bind", &dto.ContactDto{}).Return(nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewUserHandler(srv, contactSrv, locationSrv, activitySrv, v)
+	h.UpdateContact(c)
+
+	errRes, ok := c.V.(*dto.ResponseErr)
+
+	assert.True(u.T(), ok)
+	assert.Equal(u.T(), http.StatusBadRequest, errRes.StatusCode)
+	srv.AssertNumberOfCalls(u.T(), "FindOne", 0)
+}
+
+func (u *UserHandlerTest) TestFindAddressUser() {
+	want := u.Location
+
+	srv := new(ServiceMock)
+	contactSrv := new(contact.ServiceMock)
+	locationSrv := new(location.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	c := &ContextMock{
+		User:    u.User,
+		Users:   u.Users,
+		UserDto: u.UserDto,
+		Query:   u.Query,
+	}
+
+	u.User.Address = u.Location
+
+	srv.On("FindOne", int32(1)).Return(u.User, nil)
+	c.On("ID").Return(1, nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewUserHandler(srv, contactSrv, locationSrv, activitySrv, v)
+	h.FindAddress(c)
+
+	assert.Equal(u.T(), want, c.V)
+}
+
+func (u *UserHandlerTest) TestUpdateExistedAddressUser() {
+	want := u.Location
+
+	srv := new(ServiceMock)
+	contactSrv := new(contact.ServiceMock)
+	locationSrv := new(location.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	c := &ContextMock{
+		User:        u.User,
+		Users:       u.Users,
+		UserDto:     u.UserDto,
+		LocationDto: u.LocationDto,
+		Query:       u.Query,
+	}
+
+	u.User.Address = u.Location
+
+	srv.On("FindOne", int32(1)).Return(u.User, nil)
+	locationSrv.On("Update", int32(u.Location.Id), u.LocationDto).Return(u.Location, nil)
+	c.On("Bind", &dto.LocationDto{}).Return(nil)
+	c.On("ID").Return(1, nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewUserHandler(srv, contactSrv, locationSrv, activitySrv, v)
+	h.UpdateAddress(c)
+
+	assert.Equal(u.T(), want, c.V)
+}
+
+func (u *UserHandlerTest) TestUpdateAddressNotFoundUser() {
+	want := u.NotFoundErr
+
+	srv := new(ServiceMock)
+	contactSrv := new(contact.ServiceMock)
+	locationSrv := new(location.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	c := &ContextMock{
+		User:        u.User,
+		Users:       u.Users,
+		UserDto:     u.UserDto,
+		LocationDto: u.LocationDto,
+		Query:       u.Query,
+	}
+
+	srv.On("FindOne", int32(1)).Return(nil, u.NotFoundErr)
+	c.On("Bind", &dto.LocationDto{}).Return(nil)
+	c.On("ID").Return(1, nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewUserHandler(srv, contactSrv, locationSrv, activitySrv, v)
+	h.UpdateAddress(c)
+
+	assert.Equal(u.T(), want, c.V)
+}
+
+func (u *UserHandlerTest) TestFindMultiUser() {
+	want := []*proto.User{u.Users[2], u.Users[0]}
+
+	srv := new(ServiceMock)
+	contactSrv := new(contact.ServiceMock)
+	locationSrv := new(location.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	c := &ContextMock{
+		User:    u.User,
+		Users:   u.Users,
+		UserDto: u.UserDto,
+		IDs:     "3,1",
+		Query:   u.Query,
+	}
+
+	srv.On("FindMulti", []int32{3, 1}).Return(want, nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewUserHandler(srv, contactSrv, locationSrv, activitySrv, v)
+	h.FindAll(c)
+
+	assert.Equal(u.T(), want, c.V)
+	srv.AssertNumberOfCalls(u.T(), "FindAll", 0)
+}
+
+func (u *UserHandlerTest) TestFindMultiInvalidIDsUser() {
+	srv := new(ServiceMock)
+	contactSrv := new(contact.ServiceMock)
+	locationSrv := new(location.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	c := &ContextMock{
+		User:    u.User,
+		Users:   u.Users,
+		UserDto: u.UserDto,
+		IDs:     "1,abc",
+		Query:   u.Query,
+	}
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewUserHandler(srv, contactSrv, locationSrv, activitySrv, v)
+	h.FindAll(c)
+
+	errRes, ok := c.V.(*dto.ResponseErr)
+
+	assert.True(u.T(), ok)
+	assert.Equal(u.T(), http.StatusBadRequest, errRes.StatusCode)
+	assert.Equal(u.T(), "Invalid ids query param", errRes.Message)
+	srv.AssertNumberOfCalls(u.T(), "FindMulti", 0)
+}
+
+func (u *UserHandlerTest) TestFindMultiExceedMaxBatchSizeUser() {
+	var ids []string
+	for i := 1; i <= constant.MaxBatchSize+1; i++ {
+		ids = append(ids, strconv.Itoa(i))
+	}
+
+	srv := new(ServiceMock)
+	contactSrv := new(contact.ServiceMock)
+	locationSrv := new(location.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	c := &ContextMock{
+		User:    u.User,
+		Users:   u.Users,
+		UserDto: u.UserDto,
+		IDs:     strings.Join(ids, ","),
+		Query:   u.Query,
+	}
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewUserHandler(srv, contactSrv, locationSrv, activitySrv, v)
+	h.FindAll(c)
+
+	errRes, ok := c.V.(*dto.ResponseErr)
+
+	assert.True(u.T(), ok)
+	assert.Equal(u.T(), http.StatusBadRequest, errRes.StatusCode)
+	srv.AssertNumberOfCalls(u.T(), "FindMulti", 0)
+}
+
+func (u *UserHandlerTest) TestFindMultiGrpcErrUser() {
+	want := u.ServiceDownErr
+
+	srv := new(ServiceMock)
+	contactSrv := new(contact.ServiceMock)
+	locationSrv := new(location.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	c := &ContextMock{
+		User:    u.User,
+		Users:   u.Users,
+		UserDto: u.UserDto,
+		IDs:     "1,2",
+		Query:   u.Query,
+	}
+
+	srv.On("FindMulti", []int32{1, 2}).Return(nil, u.ServiceDownErr)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewUserHandler(srv, contactSrv, locationSrv, activitySrv, v)
+	h.FindAll(c)
+
+	assert.Equal(u.T(), want, c.V)
+}
+
+func (u *UserHandlerTest) TestFindActivityUser() {
+	gatewayLogs := []*proto.Log{
+		{
+			Title: "Login",
+			Type:  proto.LogType_LOGIN,
+			User:  &proto.User{Id: u.User.Id},
+		},
+	}
+
+	query := &dto.ActivityQueryParams{
+		Limit: 10,
+		Page:  1,
+	}
+
+	want := &dto.LogPagination{
+		Items: gatewayLogs,
+		Meta: &proto.PaginationMetadata{
+			TotalItem:    1,
+			ItemCount:    1,
+			ItemsPerPage: 10,
+			TotalPage:    1,
+			CurrentPage:  1,
+		},
+	}
+
+	srv := new(ServiceMock)
+	contactSrv := new(contact.ServiceMock)
+	locationSrv := new(location.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	c := &ContextMock{
+		User:          u.User,
+		Users:         u.Users,
+		UserDto:       u.UserDto,
+		Query:         u.Query,
+		ActivityQuery: query,
+	}
+
+	srv.On("FindOne", int32(1)).Return(u.User, nil)
+	activitySrv.On("FindByUser", u.User.Id).Return(gatewayLogs)
+	activitySrv.On("Paginate", gatewayLogs, query).Return(want, nil)
+	c.On("ID").Return(1, nil)
+	c.On("ActivityQueryParam", &dto.ActivityQueryParams{}).Return(nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewUserHandler(srv, contactSrv, locationSrv, activitySrv, v)
+	h.FindActivity(c)
+
+	assert.Equal(u.T(), want, c.V)
+}
+
+func (u *UserHandlerTest) TestFindActivityKeepsUserLogs() {
+	userLogs := make([]*proto.Log, 1, 2)
+	userLogs[0] = &proto.Log{
+		Title: "Post",
+		Type:  proto.LogType_POST,
+		User:  &proto.User{Id: u.User.Id},
+	}
+	u.User.Logs = userLogs
+
+	gatewayLogs := []*proto.Log{
+		{
+			Title: "Login",
+			Type:  proto.LogType_LOGIN,
+			User:  &proto.User{Id: u.User.Id},
+		},
+	}
+
+	query := &dto.ActivityQueryParams{
+		Limit: 10,
+		Page:  1,
+	}
+
+	srv := new(ServiceMock)
+	contactSrv := new(contact.ServiceMock)
+	locationSrv := new(location.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	c := &ContextMock{
+		User:          u.User,
+		Users:         u.Users,
+		UserDto:       u.UserDto,
+		Query:         u.Query,
+		ActivityQuery: query,
+	}
+
+	srv.On("FindOne", int32(1)).Return(u.User, nil)
+	activitySrv.On("FindByUser", u.User.Id).Return(gatewayLogs)
+	activitySrv.On("Paginate", []*proto.Log{userLogs[0], gatewayLogs[0]}, query).Return(&dto.LogPagination{}, nil)
+	c.On("ID").Return(1, nil)
+	c.On("ActivityQueryParam", &dto.ActivityQueryParams{}).Return(nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewUserHandler(srv, contactSrv, locationSrv, activitySrv, v)
+	h.FindActivity(c)
+
+	assert.Len(u.T(), u.User.Logs, 1)
+	assert.Nil(u.T(), userLogs[:2][1], "Must not write into the logs of the user")
+}
+
+func (u *UserHandlerTest) TestFindActivityInvalidType() {
+	want := &dto.ResponseErr{
+		StatusCode: http.StatusBadRequest,
+		Message:    "Invalid log type",
+	}
+
+	query := &dto.ActivityQueryParams{
+		Type: "UNKNOWN",
+	}
+
+	srv := new(ServiceMock)
+	contactSrv := new(contact.ServiceMock)
+	locationSrv := new(location.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	c := &ContextMock{
+		User:          u.User,
+		Users:         u.Users,
+		UserDto:       u.UserDto,
+		Query:         u.Query,
+		ActivityQuery: query,
+	}
+
+	srv.On("FindOne", int32(1)).Return(u.User, nil)
+	activitySrv.On("FindByUser", u.User.Id).Return(nil)
+	activitySrv.On("Paginate", mock.Anything, query).Return(nil, want)
+	c.On("ID").Return(1, nil)
+	c.On("ActivityQueryParam", &dto.ActivityQueryParams{}).Return(nil)
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewUserHandler(srv, contactSrv, locationSrv, activitySrv, v)
+	h.FindActivity(c)
+
+	assert.Equal(u.T(), want, c.V)
+}
+
+func (u *UserHandlerTest) TestFindActivityInvalidID() {
+	want := u.InvalidIDErr
+
+	srv := new(ServiceMock)
+	contactSrv := new(contact.ServiceMock)
+	locationSrv := new(location.ServiceMock)
+	activitySrv := new(activity.ServiceMock)
+	c := &ContextMock{
+		User:    u.User,
+		Users:   u.Users,
+		UserDto: u.UserDto,
+		Query:   u.Query,
+	}
+
+	c.On("ID").Return(-1, errors.New("Invalid ID"))
+
+	v, _ := validator.NewValidator()
+
+	h := handler.NewUserHandler(srv, contactSrv, locationSrv, activitySrv, v)
+	h.FindActivity(c)
+
+	assert.Equal(u.T(), want, c.V)
+}
@@ -12,7 +12,7 @@ type ServiceMock struct {
 	mock.Mock
 }
 
-func (m *ServiceMock) FindAll(params *dto.PaginationQueryParams) (res *proto.UserPagination, err *dto.ResponseErr) {
+func (m *ServiceMock) FindAll(_ context.Context, params *dto.PaginationQueryParams) (res *proto.UserPagination, err *dto.ResponseErr) {
 	args := m.Called(params)
 
 	if args.Get(0) != nil {
@@ -26,7 +26,7 @@ func (m *ServiceMock) FindAll(params *dto.PaginationQueryParams) (res *proto.Use
 	return
 }
 
-func (m *ServiceMock) FindOne(id int32) (res *proto.User, err *dto.ResponseErr) {
+func (m *ServiceMock) FindOne(_ context.Context, id int32) (res *proto.User, err *dto.ResponseErr) {
 	args := m.Called(id)
 
 	if args.Get(0) != nil {
@@ -40,7 +40,21 @@ func (m *ServiceMock) FindOne(id int32) (res *proto.User, err *dto.ResponseErr)
 	return
 }
 
-func (m *ServiceMock) Create(user *dto.UserDto) (res *proto.User, err *dto.ResponseErr) {
+func (m *ServiceMock) FindMulti(_ context.Context, ids []int32) (res []*proto.User, err *dto.ResponseErr) {
+	args := m.Called(ids)
+
+	if args.Get(0) != nil {
+		res = args.Get(0).([]*proto.User)
+	}
+
+	if args.Get(1) != nil {
+		err = args.Get(1).(*dto.ResponseErr)
+	}
+
+	return
+}
+
+func (m *ServiceMock) Create(_ context.Context, user *dto.UserDto) (res *proto.User, err *dto.ResponseErr) {
 	args := m.Called(user)
 
 	if args.Get(0) != nil {
@@ -54,7 +68,7 @@ func (m *ServiceMock) Create(user *dto.UserDto) (res *proto.User, err *dto.Respo
 	return
 }
 
-func (m *ServiceMock) Update(id int32, user *dto.UserDto) (res *proto.User, err *dto.ResponseErr) {
+func (m *ServiceMock) Update(_ context.Context, id int32, user *dto.UserDto) (res *proto.User, err *dto.ResponseErr) {
 	args := m.Called(id, user)
 
 	if args.Get(0) != nil {
@@ -68,7 +82,7 @@ func (m *ServiceMock) Update(id int32, user *dto.UserDto) (res *proto.User, err
 	return
 }
 
-func (m *ServiceMock) Delete(id int32) (res *proto.User, err *dto.ResponseErr) {
+func (m *ServiceMock) Delete(_ context.Context, id int32) (res *proto.User, err *dto.ResponseErr) {
 	args := m.Called(id)
 
 	if args.Get(0) != nil {
@@ -106,8 +120,14 @@ func (m *ClientMock) FindOne(ctx context.Context, in *proto.FindOneUserRequest,
 	return res, args.Error(1)
 }
 
-func (m *ClientMock) FindMulti(ctx context.Context, in *proto.FindMultiUserRequest, opts ...grpc.CallOption) (*proto.UserListResponse, error) {
-	return nil, nil
+func (m *ClientMock) FindMulti(ctx context.Context, in *proto.FindMultiUserRequest, opts ...grpc.CallOption) (res *proto.UserListResponse, err error) {
+	args := m.Called(in)
+
+	if args.Get(0) != nil {
+		res = args.Get(0).(*proto.UserListResponse)
+	}
+
+	return res, args.Error(1)
 }
 
 func (m *ClientMock) Create(ctx context.Context, in *proto.CreateUserRequest, opts ...grpc.CallOption) (res *proto.UserResponse, err error) {
@@ -142,17 +162,28 @@ func (m *ClientMock) Delete(ctx context.Context, in *proto.DeleteUserRequest, op
 
 type ContextMock struct {
 	mock.Mock
-	V       interface{}
-	User    *proto.User
-	Users   []*proto.User
-	UserDto *dto.UserDto
-	Query   *dto.PaginationQueryParams
+	V             interface{}
+	User          *proto.User
+	Users         []*proto.User
+	UserDto       *dto.UserDto
+	ContactDto    *dto.ContactDto
+	LocationDto   *dto.LocationDto
+	IDs           string
+	Query         *dto.PaginationQueryParams
+	ActivityQuery *dto.ActivityQueryParams
 }
 
 func (c *ContextMock) Bind(v interface{}) error {
 	args := c.Called(v)
 
-	*v.(*dto.UserDto) = *c.UserDto
+	switch v.(type) {
+	case *dto.UserDto:
+		*v.(*dto.UserDto) = *c.UserDto
+	case *dto.ContactDto:
+		*v.(*dto.ContactDto) = *c.ContactDto
+	case *dto.LocationDto:
+		*v.(*dto.LocationDto) = *c.LocationDto
+	}
 
 	return args.Error(0)
 }
@@ -174,3 +205,19 @@ func (c *ContextMock) PaginationQueryParam(query *dto.PaginationQueryParams) err
 
 	return args.Error(0)
 }
+
+func (c *ContextMock) ActivityQueryParam(query *dto.ActivityQueryParams) error {
+	args := c.Called(query)
+
+	*query = *c.ActivityQuery
+
+	return args.Error(0)
+}
+
+func (c *ContextMock) IDsQueryParam() string {
+	return c.IDs
+}
+
+func (c *ContextMock) UserContext() context.Context {
+	return context.Background()
+}
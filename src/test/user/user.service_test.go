@@ -1,6 +1,7 @@
 package user
 
 import (
+	"context"
 	"github.com/bxcodec/faker/v3"
 	"github.com/pkg/errors"
 	"github.com/samithiwat/samithiwat-backend-gateway/src/dto"
@@ -112,7 +113,7 @@ func (s *UserServiceTest) TestFindAllUserService() {
 
 	srv := service.NewUserService(client)
 
-	users, err := srv.FindAll(s.Query)
+	users, err := srv.FindAll(context.Background(), s.Query)
 
 	assert.Nil(s.T(), err, "Must not got any error")
 	assert.Equal(s.T(), want, users)
@@ -130,7 +131,7 @@ func (s *UserServiceTest) TestFindAllGrpcErrUserService() {
 
 	srv := service.NewUserService(client)
 
-	_, err := srv.FindAll(s.Query)
+	_, err := srv.FindAll(context.Background(), s.Query)
 
 	assert.Equal(s.T(), want, err)
 }
@@ -151,7 +152,7 @@ func (s *UserServiceTest) TestFindOneUserService() {
 
 	srv := service.NewUserService(client)
 
-	user, err := srv.FindOne(id)
+	user, err := srv.FindOne(context.Background(), id)
 
 	assert.Nil(s.T(), err, "Must not got any error")
 	assert.Equal(s.T(), want, user)
@@ -173,7 +174,7 @@ func (s *UserServiceTest) TestFindOneNotFoundUserService() {
 
 	srv := service.NewUserService(client)
 
-	user, err := srv.FindOne(id)
+	user, err := srv.FindOne(context.Background(), id)
 
 	assert.Nil(s.T(), user)
 	assert.Equal(s.T(), want, err)
@@ -191,7 +192,7 @@ func (s *UserServiceTest) TestFindOneGrpcErrUserService() {
 
 	srv := service.NewUserService(client)
 
-	_, err := srv.FindOne(id)
+	_, err := srv.FindOne(context.Background(), id)
 
 	assert.Equal(s.T(), want, err)
 }
@@ -209,7 +210,7 @@ func (s *UserServiceTest) TestCreateUserService() {
 
 	srv := service.NewUserService(client)
 
-	user, err := srv.Create(s.UserDto)
+	user, err := srv.Create(context.Background(), s.UserDto)
 
 	assert.Nil(s.T(), err, "Must not got any error")
 	assert.Equal(s.T(), want, user)
@@ -232,7 +233,7 @@ func (s *UserServiceTest) TestCreateDuplicatedUserService() {
 
 	srv := service.NewUserService(client)
 
-	user, err := srv.Create(s.UserDto)
+	user, err := srv.Create(context.Background(), s.UserDto)
 
 	assert.Nil(s.T(), user)
 	assert.Equal(s.T(), want, err)
@@ -247,7 +248,7 @@ func (s *UserServiceTest) TestCreateGrpcErrUserService() {
 
 	srv := service.NewUserService(client)
 
-	_, err := srv.Create(s.UserDto)
+	_, err := srv.Create(context.Background(), s.UserDto)
 
 	assert.Equal(s.T(), want, err)
 }
@@ -265,7 +266,7 @@ func (s *UserServiceTest) TestUpdateUserService() {
 
 	srv := service.NewUserService(client)
 
-	user, err := srv.Update(1, s.UserDto)
+	user, err := srv.Update(context.Background(), 1, s.UserDto)
 
 	assert.Nil(s.T(), err, "Must not got any error")
 	assert.Equal(s.T(), want, user)
@@ -284,7 +285,7 @@ func (s *UserServiceTest) TestUpdateNotFoundUserService() {
 
 	srv := service.NewUserService(client)
 
-	user, err := srv.Update(1, s.UserDto)
+	user, err := srv.Update(context.Background(), 1, s.UserDto)
 
 	assert.Nil(s.T(), user)
 	assert.Equal(s.T(), want, err)
@@ -299,7 +300,7 @@ func (s *UserServiceTest) TestUpdateGrpcErrUserService() {
 
 	srv := service.NewUserService(client)
 
-	_, err := srv.Update(1, s.UserDto)
+	_, err := srv.Update(context.Background(), 1, s.UserDto)
 
 	assert.Equal(s.T(), want, err)
 }
@@ -320,7 +321,7 @@ func (s *UserServiceTest) TestDeleteUserService() {
 
 	srv := service.NewUserService(client)
 
-	user, err := srv.Delete(id)
+	user, err := srv.Delete(context.Background(), id)
 
 	assert.Nil(s.T(), err, "Must not got any error")
 	assert.Equal(s.T(), want, user)
@@ -342,7 +343,7 @@ func (s *UserServiceTest) TestDeleteNotFoundUserService() {
 
 	srv := service.NewUserService(client)
 
-	user, err := srv.Delete(id)
+	user, err := srv.Delete(context.Background(), id)
 
 	assert.Nil(s.T(), user)
 	assert.Equal(s.T(), want, err)
@@ -360,7 +361,59 @@ func (s *UserServiceTest) TestDeleteGrpcErrUserService() {
 
 	srv := service.NewUserService(client)
 
-	_, err := srv.Delete(id)
+	_, err := srv.Delete(context.Background(), id)
+
+	assert.Equal(s.T(), want, err)
+}
+
+func (s *UserServiceTest) TestFindMultiUserService() {
+	want := []*proto.User{s.Users[2], s.Users[0], s.Users[1]}
+
+	client := new(ClientMock)
+
+	client.On("FindMulti", &proto.FindMultiUserRequest{Ids: []uint32{3, 1, 2}}).Return(&proto.UserListResponse{
+		StatusCode: http.StatusOK,
+		Errors:     nil,
+		Data:       []*proto.User{s.Users[0], s.Users[1], s.Users[2]},
+	}, nil)
+
+	srv := service.NewUserService(client)
+
+	users, err := srv.FindMulti(context.Background(), []int32{3, 1, 2})
+
+	assert.Nil(s.T(), err, "Must not got any error")
+	assert.Equal(s.T(), want, users)
+}
+
+func (s *UserServiceTest) TestFindMultiSomeNotFoundUserService() {
+	want := []*proto.User{s.Users[1]}
+
+	client := new(ClientMock)
+
+	client.On("FindMulti", &proto.FindMultiUserRequest{Ids: []uint32{10, 2}}).Return(&proto.UserListResponse{
+		StatusCode: http.StatusOK,
+		Errors:     nil,
+		Data:       []*proto.User{s.Users[1]},
+	}, nil)
+
+	srv := service.NewUserService(client)
+
+	users, err := srv.FindMulti(context.Background(), []int32{10, 2})
+
+	assert.Nil(s.T(), err, "Must not got any error")
+	assert.Equal(s.T(), want, users)
+}
+
+func (s *UserServiceTest) TestFindMultiGrpcErrUserService() {
+	want := s.ServiceDownErr
+
+	client := new(ClientMock)
+
+	client.On("FindMulti", &proto.FindMultiUserRequest{Ids: []uint32{1, 2}}).Return(nil, errors.New("Service is down"))
+
+	srv := service.NewUserService(client)
+
+	_, err := srv.FindMulti(context.Background(), []int32{1, 2})
 
 	assert.Equal(s.T(), want, err)
 }
@@ -0,0 +1,130 @@
+package validator
+
+import (
+	"fmt"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/dto"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/validator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type PasswordPolicyTest struct {
+	suite.Suite
+	Policy *validator.PasswordPolicy
+}
+
+func TestPasswordPolicy(t *testing.T) {
+	suite.Run(t, new(PasswordPolicyTest))
+}
+
+func (u *PasswordPolicyTest) SetupTest() {
+	u.Policy = &validator.PasswordPolicy{
+		MinLength:     10,
+		MaxLength:     20,
+		RequireUpper:  true,
+		RequireLower:  true,
+		RequireDigit:  true,
+		RequireSymbol: true,
+	}
+}
+
+func tags(violations []*dto.BadReqErrResponse) []string {
+	var result []string
+	for _, v := range violations {
+		result = append(result, v.Tag)
+	}
+
+	return result
+}
+
+func (u *PasswordPolicyTest) TestStrongPassword() {
+	assert.Empty(u.T(), u.Policy.Check("Password", "Correct-Horse-7"))
+}
+
+func (u *PasswordPolicyTest) TestEveryViolation() {
+	violations := u.Policy.Check("Password", "short")
+
+	assert.Equal(u.T(), []string{"password_min", "password_upper", "password_digit", "password_symbol"}, tags(violations))
+	for _, v := range violations {
+		assert.Equal(u.T(), "Password", v.FailedField)
+		assert.Nil(u.T(), v.Value)
+	}
+	assert.Equal(u.T(), "Password must be at least 10 characters", violations[0].Message)
+}
+
+func (u *PasswordPolicyTest) TestTooLong() {
+	violations := u.Policy.Check("Password", "Correct-Horse-Battery-Staple-7")
+
+	assert.Equal(u.T(), []string{"password_max"}, tags(violations))
+}
+
+func (u *PasswordPolicyTest) TestContainsPersonal() {
+	assert.Equal(u.T(), []string{"password_personal"}, tags(u.Policy.Check("Password", "Smithy-2022!", "smithy@samithiwat.dev")))
+	assert.Equal(u.T(), []string{"password_personal"}, tags(u.Policy.Check("Password", "Boonchai-2022", "Boonchai")))
+	assert.Empty(u.T(), u.Policy.Check("Password", "Correct-Horse-7", "Al", ""))
+}
+
+func (u *PasswordPolicyTest) TestBreached() {
+	path := filepath.Join(u.T().TempDir(), "breached.txt")
+	assert.Nil(u.T(), os.WriteFile(path, []byte("123456\r\npassword\n\nCorrect-Horse-7\n"), 0600))
+
+	breached, err := validator.LoadBreachedPasswords(path, 0.001)
+	assert.Nil(u.T(), err)
+
+	u.Policy.Breached = breached
+
+	assert.Equal(u.T(), []string{"password_breached"}, tags(u.Policy.Check("Password", "Correct-Horse-7")))
+	assert.Empty(u.T(), u.Policy.Check("Password", "Correct-Horse-8"))
+}
+
+func (u *PasswordPolicyTest) TestBreachedListNotFound() {
+	_, err := validator.LoadBreachedPasswords(filepath.Join(u.T().TempDir(), "missing.txt"), 0.001)
+
+	assert.NotNil(u.T(), err)
+}
+
+func (u *PasswordPolicyTest) TestBloomFilter() {
+	filter := validator.NewBloomFilter(1000, 0.01)
+	for i := 0; i < 1000; i++ {
+		filter.Add(fmt.Sprintf("password-%v", i))
+	}
+
+	for i := 0; i < 1000; i++ {
+		assert.True(u.T(), filter.Test(fmt.Sprintf("password-%v", i)))
+	}
+
+	falsePositives := 0
+	for i := 0; i < 10000; i++ {
+		if filter.Test(fmt.Sprintf("other-%v", i)) {
+			falsePositives++
+		}
+	}
+	assert.Less(u.T(), falsePositives, 300)
+}
+
+type ChangePasswordDto struct {
+	NewPassword string `validate:"password"`
+}
+
+func (u *PasswordPolicyTest) TestValidatorExpandsPasswordTag() {
+	v, err := validator.NewValidatorWithPolicy(u.Policy)
+	assert.Nil(u.T(), err)
+
+	violations := v.Validate(ChangePasswordDto{NewPassword: "lowercase-only"})
+
+	assert.Equal(u.T(), []string{"password_upper", "password_digit"}, tags(violations))
+	assert.Equal(u.T(), "NewPassword", violations[0].FailedField)
+	assert.Nil(u.T(), v.Validate(ChangePasswordDto{NewPassword: "Correct-Horse-7"}))
+}
+
+func (u *PasswordPolicyTest) TestDefaultPolicy() {
+	v, _ := validator.NewValidator()
+
+	violations := v.Validate(ChangePasswordDto{NewPassword: "short"})
+
+	assert.Equal(u.T(), []string{"password_min"}, tags(violations))
+	assert.Equal(u.T(), "NewPassword must be at least 8 characters", violations[0].Message)
+}
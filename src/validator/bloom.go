@@ -0,0 +1,106 @@
+package validator
+
+import (
+	"bufio"
+	"github.com/pkg/errors"
+	"hash/fnv"
+	"math"
+	"os"
+	"strings"
+)
+
+// BloomFilter tells that a value is not in the set for sure or that it may be in the set with the false positive rate
+// that the filter is sized for, it keeps a few bits for each value instead of the values
+type BloomFilter struct {
+	bits   []uint64
+	size   uint64
+	hashes uint64
+}
+
+// NewBloomFilter sizes the filter for n values with the false positive rate p
+func NewBloomFilter(n int, p float64) *BloomFilter {
+	if n < 1 {
+		n = 1
+	}
+	if p <= 0 || p >= 1 {
+		p = 0.001
+	}
+
+	size := uint64(math.Ceil(-float64(n) * math.Log(p) / (math.Ln2 * math.Ln2)))
+	hashes := uint64(math.Round(float64(size) / float64(n) * math.Ln2))
+	if hashes < 1 {
+		hashes = 1
+	}
+
+	return &BloomFilter{
+		bits:   make([]uint64, (size+63)/64),
+		size:   size,
+		hashes: hashes,
+	}
+}
+
+func (f *BloomFilter) Add(value string) {
+	h1, h2 := bloomHash(value)
+	for i := uint64(0); i < f.hashes; i++ {
+		bit := (h1 + i*h2) % f.size
+		f.bits[bit/64] |= 1 << (bit % 64)
+	}
+}
+
+func (f *BloomFilter) Test(value string) bool {
+	h1, h2 := bloomHash(value)
+	for i := uint64(0); i < f.hashes; i++ {
+		bit := (h1 + i*h2) % f.size
+		if f.bits[bit/64]&(1<<(bit%64)) == 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// bloomHash derives the two hashes that the positions are made of (Kirsch and Mitzenmacher)
+func bloomHash(value string) (uint64, uint64) {
+	a := fnv.New64a()
+	_, _ = a.Write([]byte(value))
+
+	b := fnv.New64()
+	_, _ = b.Write([]byte(value))
+
+	return a.Sum64(), b.Sum64() | 1
+}
+
+// LoadBreachedPasswords reads the file with a password on each line into a bloom filter, the blank lines are skipped
+func LoadBreachedPasswords(path string, p float64) (*BloomFilter, error) {
+	n := 0
+	if err := readLines(path, func(string) { n++ }); err != nil {
+		return nil, err
+	}
+
+	filter := NewBloomFilter(n, p)
+	if err := readLines(path, filter.Add); err != nil {
+		return nil, err
+	}
+
+	return filter, nil
+}
+
+func readLines(path string, fn func(string)) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return errors.Wrap(err, "cannot open the breached password list")
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" {
+			continue
+		}
+
+		fn(line)
+	}
+
+	return errors.Wrap(scanner.Err(), "cannot read the breached password list")
+}
@@ -0,0 +1,110 @@
+package validator
+
+import (
+	"fmt"
+	"github.com/samithiwat/samithiwat-backend-gateway/src/dto"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// personalMinLength is the shortest part of the email or the name that a password must not contain, the shorter
+// parts are too common to ban
+const personalMinLength = 3
+
+// PasswordPolicy is the rules of a new password, a zero max length does not limit it and a nil breached filter does
+// not check the breached passwords
+type PasswordPolicy struct {
+	MinLength     int
+	MaxLength     int
+	RequireUpper  bool
+	RequireLower  bool
+	RequireDigit  bool
+	RequireSymbol bool
+	Breached      *BloomFilter
+}
+
+func DefaultPasswordPolicy() *PasswordPolicy {
+	return &PasswordPolicy{
+		MinLength: 8,
+		MaxLength: 72,
+	}
+}
+
+// Check returns a violation for each rule that the password breaks, the personal values are the email and the names
+// of the user that the password must not contain, the password is never echoed in the violations
+func (p *PasswordPolicy) Check(field string, password string, personal ...string) []*dto.BadReqErrResponse {
+	var violations []*dto.BadReqErrResponse
+	violate := func(tag string, format string, args ...interface{}) {
+		violations = append(violations, &dto.BadReqErrResponse{
+			Message:     fmt.Sprintf(format, args...),
+			FailedField: field,
+			Tag:         tag,
+		})
+	}
+
+	length := utf8.RuneCountInString(password)
+	if length < p.MinLength {
+		violate("password_min", "%v must be at least %v characters", field, p.MinLength)
+	}
+	if p.MaxLength > 0 && length > p.MaxLength {
+		violate("password_max", "%v must be at most %v characters", field, p.MaxLength)
+	}
+
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			symbol = true
+		}
+	}
+
+	if p.RequireUpper && !upper {
+		violate("password_upper", "%v must contain an uppercase letter", field)
+	}
+	if p.RequireLower && !lower {
+		violate("password_lower", "%v must contain a lowercase letter", field)
+	}
+	if p.RequireDigit && !digit {
+		violate("password_digit", "%v must contain a digit", field)
+	}
+	if p.RequireSymbol && !symbol {
+		violate("password_symbol", "%v must contain a symbol", field)
+	}
+
+	if p.Breached != nil && p.Breached.Test(password) {
+		violate("password_breached", "%v is found in a list of breached passwords", field)
+	}
+
+	if containsPersonal(password, personal) {
+		violate("password_personal", "%v must not contain the email or the name", field)
+	}
+
+	return violations
+}
+
+func containsPersonal(password string, personal []string) bool {
+	password = strings.ToLower(password)
+	for _, value := range personal {
+		value = strings.ToLower(strings.TrimSpace(value))
+
+		parts := []string{value}
+		if at := strings.LastIndex(value, "@"); at > 0 {
+			parts = append(parts, value[:at])
+		}
+
+		for _, part := range parts {
+			if utf8.RuneCountInString(part) >= personalMinLength && strings.Contains(password, part) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -7,11 +7,15 @@ import (
 	en_translations "github.com/go-playground/validator/v10/translations/en"
 	"github.com/pkg/errors"
 	"github.com/samithiwat/samithiwat-backend-gateway/src/dto"
+	"regexp"
 )
 
+var permissionCodeRegex = regexp.MustCompile("^[a-z][a-z0-9_-]*(:[a-z][a-z0-9_-]*)+$")
+
 type DtoValidator struct {
-	v     *validator.Validate
-	trans ut.Translator
+	v      *validator.Validate
+	trans  ut.Translator
+	policy *PasswordPolicy
 }
 
 func (v *DtoValidator) Validate(in interface{}) []*dto.BadReqErrResponse {
@@ -20,6 +24,12 @@ func (v *DtoValidator) Validate(in interface{}) []*dto.BadReqErrResponse {
 	var errors []*dto.BadReqErrResponse
 	if err != nil {
 		for _, e := range err.(validator.ValidationErrors) {
+			// a weak password is reported by each rule of the policy that it breaks
+			if e.Tag() == "password" {
+				errors = append(errors, v.policy.Check(e.StructField(), e.Value().(string))...)
+				continue
+			}
+
 			element := dto.BadReqErrResponse{
 				Message:     e.Translate(v.trans),
 				FailedField: e.StructField(),
@@ -33,7 +43,17 @@ func (v *DtoValidator) Validate(in interface{}) []*dto.BadReqErrResponse {
 	return errors
 }
 
+// ValidatePassword checks the password against the policy together with the email and the names of the user that it
+// must not contain, the other rules are already checked by the password tag of the dto
+func (v *DtoValidator) ValidatePassword(field string, password string, personal ...string) []*dto.BadReqErrResponse {
+	return v.policy.Check(field, password, personal...)
+}
+
 func NewValidator() (*DtoValidator, error) {
+	return NewValidatorWithPolicy(DefaultPasswordPolicy())
+}
+
+func NewValidatorWithPolicy(policy *PasswordPolicy) (*DtoValidator, error) {
 	translator := en.New()
 	uni := ut.New(translator, translator)
 
@@ -56,19 +76,31 @@ func NewValidator() (*DtoValidator, error) {
 	})
 
 	_ = v.RegisterTranslation("password", trans, func(ut ut.Translator) error {
-		return ut.Add("password", "{0} is not strong enough (must be at lease 8 characters)", true) // see universal-translator for details
+		return ut.Add("password", "{0} does not meet the password policy", true) // see universal-translator for details
 	}, func(ut ut.Translator, fe validator.FieldError) string {
 		t, _ := ut.T("password", fe.Field())
 		return t
 	})
 
 	_ = v.RegisterValidation("password", func(fl validator.FieldLevel) bool {
-		return len(fl.Field().String()) >= 8
+		return len(policy.Check(fl.FieldName(), fl.Field().String())) == 0
+	})
+
+	_ = v.RegisterTranslation("permission_code", trans, func(ut ut.Translator) error {
+		return ut.Add("permission_code", "{0} must be in the format of resource:action (e.g. user:update)", true) // see universal-translator for details
+	}, func(ut ut.Translator, fe validator.FieldError) string {
+		t, _ := ut.T("permission_code", fe.Field())
+		return t
+	})
+
+	_ = v.RegisterValidation("permission_code", func(fl validator.FieldLevel) bool {
+		return permissionCodeRegex.MatchString(fl.Field().String())
 	})
 
 	return &DtoValidator{
-		v:     v,
-		trans: trans,
+		v:      v,
+		trans:  trans,
+		policy: policy,
 	}, nil
 }
 